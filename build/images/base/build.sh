@@ -23,11 +23,13 @@ function echoerr {
     >&2 echo "$@"
 }
 
-_usage="Usage: $0 [--pull] [--push] [--platform <PLATFORM>]
+_usage="Usage: $0 [--pull] [--push] [--platform <PLATFORM>] [--with-suricata]
 Build the antrea/base-ubuntu:<OVS_VERSION> image.
         --pull                  Always attempt to pull a newer version of the base images
         --push                  Push the built image to the registry
-        --platform <PLATFORM>   Target platform for the image if server is multi-platform capable"
+        --platform <PLATFORM>   Target platform for the image if server is multi-platform capable
+        --with-suricata         Install Suricata, the L7 engine of the L7NetworkPolicy feature, and build
+                                the antrea/base-ubuntu:<OVS_VERSION>-suricata image instead"
 
 function print_usage {
     echoerr "$_usage"
@@ -36,6 +38,7 @@ function print_usage {
 PULL=false
 PUSH=false
 PLATFORM=""
+WITH_SURICATA=false
 
 while [[ $# -gt 0 ]]
 do
@@ -54,6 +57,10 @@ case $key in
     PLATFORM="$2"
     shift 2
     ;;
+    --with-suricata)
+    WITH_SURICATA=true
+    shift
+    ;;
     -h|--help)
     print_usage
     exit 0
@@ -82,6 +89,13 @@ pushd $THIS_DIR > /dev/null
 OVS_VERSION=$(head -n 1 ../deps/ovs-version)
 CNI_BINARIES_VERSION=$(head -n 1 ../deps/cni-binaries-version)
 
+BASE_IMAGE_TAG=$OVS_VERSION
+SURICATA_VERSION=""
+if $WITH_SURICATA; then
+    BASE_IMAGE_TAG=$OVS_VERSION-suricata
+    SURICATA_VERSION=$(head -n 1 ../deps/suricata-version)
+fi
+
 if $PULL; then
     if [[ ${DOCKER_REGISTRY} == "" ]]; then
         docker pull $PLATFORM_ARG ubuntu:20.04
@@ -92,7 +106,7 @@ if $PULL; then
     IMAGES_LIST=(
         "antrea/openvswitch:$OVS_VERSION"
         "antrea/cni-binaries:$CNI_BINARIES_VERSION"
-        "antrea/base-ubuntu:$OVS_VERSION"
+        "antrea/base-ubuntu:$BASE_IMAGE_TAG"
     )
     for image in "${IMAGES_LIST[@]}"; do
         if [[ ${DOCKER_REGISTRY} == "" ]]; then
@@ -115,14 +129,15 @@ docker build $PLATFORM_ARG --target cni-binaries \
 
 docker build $PLATFORM_ARG \
        --cache-from antrea/cni-binaries:$CNI_BINARIES_VERSION \
-       --cache-from antrea/base-ubuntu:$OVS_VERSION \
-       -t antrea/base-ubuntu:$OVS_VERSION \
+       --cache-from antrea/base-ubuntu:$BASE_IMAGE_TAG \
+       -t antrea/base-ubuntu:$BASE_IMAGE_TAG \
        --build-arg CNI_BINARIES_VERSION=$CNI_BINARIES_VERSION \
+       --build-arg SURICATA_VERSION=$SURICATA_VERSION \
        --build-arg OVS_VERSION=$OVS_VERSION .
 
 if $PUSH; then
     docker push antrea/cni-binaries:$CNI_BINARIES_VERSION
-    docker push antrea/base-ubuntu:$OVS_VERSION
+    docker push antrea/base-ubuntu:$BASE_IMAGE_TAG
 fi
 
 popd > /dev/null
@@ -25,7 +25,7 @@ import (
 	"antrea.io/antrea/pkg/agent"
 	"antrea.io/antrea/pkg/agent/apiserver"
 	"antrea.io/antrea/pkg/agent/cniserver"
-	_ "antrea.io/antrea/pkg/agent/cniserver/ipam"
+	"antrea.io/antrea/pkg/agent/cniserver/ipam"
 	"antrea.io/antrea/pkg/agent/config"
 	"antrea.io/antrea/pkg/agent/controller/egress"
 	"antrea.io/antrea/pkg/agent/controller/networkpolicy"
@@ -39,11 +39,14 @@ import (
 	npl "antrea.io/antrea/pkg/agent/nodeportlocal"
 	"antrea.io/antrea/pkg/agent/openflow"
 	"antrea.io/antrea/pkg/agent/proxy"
+	proxytypes "antrea.io/antrea/pkg/agent/proxy/types"
 	"antrea.io/antrea/pkg/agent/querier"
 	"antrea.io/antrea/pkg/agent/route"
 	"antrea.io/antrea/pkg/agent/stats"
 	"antrea.io/antrea/pkg/agent/types"
+	"antrea.io/antrea/pkg/agent/util"
 	crdinformers "antrea.io/antrea/pkg/client/informers/externalversions"
+	crdv1a2informers "antrea.io/antrea/pkg/client/informers/externalversions/crd/v1alpha2"
 	"antrea.io/antrea/pkg/features"
 	"antrea.io/antrea/pkg/log"
 	"antrea.io/antrea/pkg/monitor"
@@ -113,13 +116,15 @@ func run(o *Options) error {
 		_, serviceCIDRNetv6, _ = net.ParseCIDR(o.config.ServiceCIDRv6)
 	}
 
+	proxyNodePort := features.DefaultFeatureGate.Enabled(features.AntreaProxy) && o.config.AntreaProxy.ProxyNodePort
+
 	_, encapMode := config.GetTrafficEncapModeFromStr(o.config.TrafficEncapMode)
 	networkConfig := &config.NetworkConfig{
 		TunnelType:        ovsconfig.TunnelType(o.config.TunnelType),
 		TrafficEncapMode:  encapMode,
 		EnableIPSecTunnel: o.config.EnableIPSecTunnel}
 
-	routeClient, err := route.NewClient(serviceCIDRNet, networkConfig, o.config.NoSNAT)
+	routeClient, err := route.NewClient(serviceCIDRNet, networkConfig, o.config.NoSNAT, proxyNodePort)
 	if err != nil {
 		return fmt.Errorf("error creating route client: %v", err)
 	}
@@ -156,6 +161,10 @@ func run(o *Options) error {
 	}
 	nodeConfig := agentInitializer.GetNodeConfig()
 
+	var ipPoolInformer crdv1a2informers.IPPoolInformer
+	if features.DefaultFeatureGate.Enabled(features.AntreaIPAM) {
+		ipPoolInformer = crdInformerFactory.Crd().V1alpha2().IPPools()
+	}
 	nodeRouteController := noderoute.NewNodeRouteController(
 		k8sClient,
 		informerFactory,
@@ -164,19 +173,28 @@ func run(o *Options) error {
 		routeClient,
 		ifaceStore,
 		networkConfig,
-		nodeConfig)
+		nodeConfig,
+		ipPoolInformer)
 
 	var proxier proxy.Proxier
 	if features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
 		v4Enabled := config.IsIPv4Enabled(nodeConfig, networkConfig.TrafficEncapMode)
 		v6Enabled := config.IsIPv6Enabled(nodeConfig, networkConfig.TrafficEncapMode)
+		var nodePortAddressesIPv4, nodePortAddressesIPv6 []net.IP
+		if proxyNodePort {
+			nodePortAddressesIPv4, nodePortAddressesIPv6, err = getNodePortAddresses(o.config.AntreaProxy.NodePortAddresses, o.config.HostGateway)
+			if err != nil {
+				return fmt.Errorf("error when getting NodePort addresses: %v", err)
+			}
+		}
+		defaultLoadBalancingMode := proxytypes.LoadBalancingMode(o.config.AntreaProxy.DefaultLoadBalancingMode)
 		switch {
 		case v4Enabled && v6Enabled:
-			proxier = proxy.NewDualStackProxier(nodeConfig.Name, informerFactory, ofClient)
+			proxier = proxy.NewDualStackProxier(nodeConfig.Name, informerFactory, ofClient, routeClient, nodePortAddressesIPv4, nodePortAddressesIPv6, proxyNodePort, defaultLoadBalancingMode)
 		case v4Enabled:
-			proxier = proxy.NewProxier(nodeConfig.Name, informerFactory, ofClient, false)
+			proxier = proxy.NewProxier(nodeConfig.Name, informerFactory, ofClient, routeClient, nodePortAddressesIPv4, proxyNodePort, defaultLoadBalancingMode, false)
 		case v6Enabled:
-			proxier = proxy.NewProxier(nodeConfig.Name, informerFactory, ofClient, true)
+			proxier = proxy.NewProxier(nodeConfig.Name, informerFactory, ofClient, routeClient, nodePortAddressesIPv6, proxyNodePort, defaultLoadBalancingMode, true)
 		default:
 			return fmt.Errorf("at least one of IPv4 or IPv6 should be enabled")
 		}
@@ -210,6 +228,7 @@ func run(o *Options) error {
 		antreaPolicyEnabled,
 		statusManagerEnabled,
 		loggingEnabled,
+		o.auditLoggerConfig,
 		denyConnStore,
 		asyncRuleDeleteInterval)
 	if err != nil {
@@ -228,12 +247,17 @@ func run(o *Options) error {
 		egressController, err = egress.NewEgressController(
 			ofClient, antreaClientProvider, crdClient, ifaceStore, routeClient, nodeConfig.Name, nodeConfig.NodeIPAddr.IP,
 			o.config.ClusterMembershipPort, egressInformer, nodeInformer, externalIPPoolInformer,
+			crdInformerFactory.Crd().V1alpha3().ClusterGroups(), o.egressHealthCheckConfig,
 		)
 		if err != nil {
 			return fmt.Errorf("error creating new Egress controller: %v", err)
 		}
 	}
 
+	if features.DefaultFeatureGate.Enabled(features.AntreaIPAM) {
+		ipam.InitializeAntreaIPAMDriver(nodeConfig.Name, crdClient, informerFactory.Core().V1().Namespaces(), crdInformerFactory.Crd().V1alpha2().IPPools(), stopCh)
+	}
+
 	isChaining := false
 	if networkConfig.TrafficEncapMode.IsNetworkPolicyOnly() {
 		isChaining = true
@@ -395,7 +419,8 @@ func run(o *Options) error {
 			v6Enabled,
 			k8sClient,
 			nodeRouteController,
-			isNetworkPolicyOnly)
+			isNetworkPolicyOnly,
+			o.flowExportFilter)
 		if err != nil {
 			return fmt.Errorf("error when creating IPFIX flow exporter: %v", err)
 		}
@@ -406,3 +431,24 @@ func run(o *Options) error {
 	klog.Info("Stopping Antrea agent")
 	return nil
 }
+
+// getNodePortAddresses returns the IPv4 and IPv6 addresses of the Node on which the NodePort Services are exposed. If
+// nodePortAddresses is not empty, only the addresses in the provided CIDRs are returned. The addresses of the host
+// gateway interface are always excluded. Like kube-proxy, the IPv4 loopback address is included, so that the NodePort
+// Services can be accessed from the Node through 127.0.0.1.
+func getNodePortAddresses(nodePortAddresses []string, hostGateway string) ([]net.IP, []net.IP, error) {
+	var cidrs []*net.IPNet
+	for _, nodePortAddress := range nodePortAddresses {
+		_, cidr, err := net.ParseCIDR(nodePortAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	nodeAddressesIPv4, nodeAddressesIPv6, err := util.GetAllNodeAddresses([]string{hostGateway})
+	if err != nil {
+		return nil, nil, err
+	}
+	nodeAddressesIPv4 = append(nodeAddressesIPv4, net.IPv4(127, 0, 0, 1))
+	return util.FilterIPsByCIDRs(nodeAddressesIPv4, cidrs), util.FilterIPsByCIDRs(nodeAddressesIPv6, cidrs), nil
+}
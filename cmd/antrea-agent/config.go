@@ -148,4 +148,108 @@ type AgentConfig struct {
 	TLSCipherSuites string `yaml:"tlsCipherSuites,omitempty"`
 	// TLS min version.
 	TLSMinVersion string `yaml:"tlsMinVersion,omitempty"`
+	// AntreaProxy contains AntreaProxy related configuration options.
+	AntreaProxy AntreaProxyConfig `yaml:"antreaProxy,omitempty"`
+	// Egress contains Egress related configuration options.
+	Egress EgressConfig `yaml:"egress,omitempty"`
+	// AuditLogging contains the configuration of the audit logging of Antrea-native policy rules.
+	AuditLogging AuditLoggingConfig `yaml:"auditLogging,omitempty"`
+	// FlowExportFilter selects the connections exported by the Flow Exporter. This option only takes effect when the
+	// FlowExporter feature is enabled.
+	FlowExportFilter FlowExportFilterConfig `yaml:"flowExportFilter,omitempty"`
+}
+
+type AntreaProxyConfig struct {
+	// ProxyNodePort tells antrea-agent whether NodePort Services are handled by AntreaProxy, so that kube-proxy is
+	// no longer required for them. Note that LoadBalancer Services are handled through their NodePorts when the
+	// traffic comes from outside the cluster. This option only takes effect when the AntreaProxy feature is enabled.
+	// It is not supported on Windows Nodes or in networkPolicyOnly mode.
+	// Defaults to false.
+	ProxyNodePort bool `yaml:"proxyNodePort,omitempty"`
+	// A string array of values which specifies the host IPv4/IPv6 addresses for NodePort. Values can be valid IP
+	// blocks (e.g. 1.2.3.0/24, 1.2.3.4/32). An empty string slice is meant to select all host IPv4/IPv6 addresses,
+	// except the addresses of the host gateway interface and the link-local addresses. Among the loopback addresses,
+	// only 127.0.0.1 is selected.
+	NodePortAddresses []string `yaml:"nodePortAddresses,omitempty"`
+	// The default algorithm used to select an Endpoint for the connections to a Service, which can be overridden for
+	// a Service with the "service.antrea.io/load-balancing-mode" annotation. Supported values are "Random" (all the
+	// Endpoints have the same weight), "Weighted" (local Endpoints have a higher weight, which can be set with the
+	// "service.antrea.io/local-endpoint-weight" annotation) and "ConsistentHash" (Maglev consistent hashing, which
+	// minimizes the remapping of connections when Endpoints change). This option only takes effect when the
+	// AntreaProxy feature is enabled.
+	// Defaults to "Random".
+	DefaultLoadBalancingMode string `yaml:"defaultLoadBalancingMode,omitempty"`
+}
+
+type EgressConfig struct {
+	// HealthCheck contains the configuration of the health checks which decide whether the Node can own Egress IPs.
+	HealthCheck EgressHealthCheckConfig `yaml:"healthCheck,omitempty"`
+}
+
+type EgressHealthCheckConfig struct {
+	// Enable the health checks of the Node. When the health checks of a Node fail, the Node is not selected to own
+	// Egress IPs anymore and the Egress IPs it owned fail over to other Nodes, unless none of the Nodes selected by the
+	// ExternalIPPool is healthy. This option only takes effect when the Egress feature is enabled. It is not supported
+	// on Windows Nodes.
+	// Defaults to false.
+	Enable bool `yaml:"enable,omitempty"`
+	// The interval between two runs of the health checks, e.g. "5s".
+	// Defaults to "5s".
+	Interval string `yaml:"interval,omitempty"`
+	// The number of consecutive failures of a health check after which the Node is considered unhealthy. The Node is
+	// considered healthy again as soon as all the health checks succeed.
+	// Defaults to 3.
+	FailureThreshold int `yaml:"failureThreshold,omitempty"`
+	// The health checks to run. Supported values are "Uplink" (the interface which has the Node IP is up and running)
+	// and "Gateway" (the link-layer address of the default gateway can be resolved by ARP or NDP).
+	// Defaults to ["Uplink", "Gateway"].
+	Checks []string `yaml:"checks,omitempty"`
+}
+
+type AuditLoggingConfig struct {
+	// The format of the audit log entries. Supported values are "text" (one line of text per logged packet) and
+	// "json" (one JSON object per logged packet, which also includes the names of the policy and the rule, and the
+	// Pods of the source and destination IPs when they are known).
+	// Defaults to "text".
+	Format string `yaml:"format,omitempty"`
+	// Where the audit log entries are written. Supported values are "file" (the np.log file in the "networkpolicy"
+	// subdirectory of the log directory of antrea-agent, rotated automatically) and "syslog" (RFC 5424 messages sent
+	// to syslogAddress).
+	// Defaults to "file".
+	Output string `yaml:"output,omitempty"`
+	// The address of the syslog server when output is "syslog", as "udp://HOST:PORT", "tcp://HOST:PORT" or
+	// "unix:///PATH".
+	SyslogAddress string `yaml:"syslogAddress,omitempty"`
+	// The maximum number of packets logged per second for each rule. It is enforced by an OpenFlow meter for each
+	// rule when OVS supports meters. The packets exceeding it are still enforced but not logged.
+	// Defaults to 0, which means no limit.
+	RateLimit int `yaml:"rateLimit,omitempty"`
+	// The maximum number of packets logged at once for each rule when rateLimit is set.
+	// Defaults to the value of rateLimit.
+	RateLimitBurst int `yaml:"rateLimitBurst,omitempty"`
+}
+
+type FlowExportFilterConfig struct {
+	// Only export the connections whose source or destination Pod is in one of these Namespaces. Inter-Node
+	// connections are always exported, as the Namespace of the remote Pod is unknown.
+	// Defaults to [], which matches all the connections.
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// Only export the connections with one of these protocols. Supported values are "TCP", "UDP", "SCTP" and "ICMP"
+	// (which also matches ICMPv6).
+	// Defaults to [], which matches all the connections.
+	Protocols []string `yaml:"protocols,omitempty"`
+	// Only export the connections whose source or destination IP is in one of these CIDRs, e.g. "10.10.0.0/16".
+	// Defaults to [], which matches all the connections.
+	CIDRs []string `yaml:"cidrs,omitempty"`
+	// Only export the connections with one of these flow types. Supported values are "IntraNode" (between Pods on
+	// the same Node), "InterNode" (between Pods on different Nodes) and "ToExternal" (from a Pod to a destination
+	// outside of the cluster).
+	// Defaults to [], which matches all the connections.
+	FlowTypes []string `yaml:"flowTypes,omitempty"`
+	// Export 1 in samplingRate of the connections matched by the other filters. The connections are selected with a
+	// hash of their 5-tuple, so that the Nodes of both ends of a connection select the same connections. The sampling
+	// rate is exported in the samplingInterval Information Element of the flow records, so that the collectors can
+	// scale the counts.
+	// Defaults to 1, which means that all the connections are exported.
+	SamplingRate int `yaml:"samplingRate,omitempty"`
 }
@@ -25,6 +25,10 @@ import (
 	"k8s.io/klog/v2"
 
 	"antrea.io/antrea/pkg/agent/config"
+	"antrea.io/antrea/pkg/agent/controller/networkpolicy"
+	"antrea.io/antrea/pkg/agent/flowexporter/exporter"
+	"antrea.io/antrea/pkg/agent/memberlist"
+	proxytypes "antrea.io/antrea/pkg/agent/proxy/types"
 	"antrea.io/antrea/pkg/apis"
 	"antrea.io/antrea/pkg/cni"
 	"antrea.io/antrea/pkg/features"
@@ -62,6 +66,12 @@ type Options struct {
 	activeFlowTimeout time.Duration
 	// Idle flow timeout to export records of inactive flows
 	idleFlowTimeout time.Duration
+	// Health check configuration of Egress Nodes, nil if the health checks are disabled
+	egressHealthCheckConfig *memberlist.HealthCheckConfig
+	// Configuration of the audit logging of Antrea-native policy rules
+	auditLoggerConfig *networkpolicy.AuditLoggerConfig
+	// Filter of the connections exported by the Flow Exporter, nil if all the connections are exported
+	flowExportFilter *exporter.FlowFilter
 }
 
 func newOptions() *Options {
@@ -146,9 +156,39 @@ func (o *Options) validate(args []string) error {
 		// (but SNAT can be done by the primary CNI).
 		o.config.NoSNAT = true
 	}
+	if err := o.validateAntreaProxyConfig(encapMode); err != nil {
+		return fmt.Errorf("failed to validate AntreaProxy config: %v", err)
+	}
 	if err := o.validateFlowExporterConfig(); err != nil {
 		return fmt.Errorf("failed to validate flow exporter config: %v", err)
 	}
+	if err := o.validateEgressConfig(); err != nil {
+		return fmt.Errorf("failed to validate Egress config: %v", err)
+	}
+	if err := o.validateAuditLoggingConfig(); err != nil {
+		return fmt.Errorf("failed to validate audit logging config: %v", err)
+	}
+	return nil
+}
+
+func (o *Options) validateAntreaProxyConfig(encapMode config.TrafficEncapModeType) error {
+	if !proxytypes.IsValidLoadBalancingMode(proxytypes.LoadBalancingMode(o.config.AntreaProxy.DefaultLoadBalancingMode)) {
+		return fmt.Errorf("defaultLoadBalancingMode %s is invalid", o.config.AntreaProxy.DefaultLoadBalancingMode)
+	}
+	if !o.config.AntreaProxy.ProxyNodePort {
+		return nil
+	}
+	if !features.DefaultFeatureGate.Enabled(features.AntreaProxy) {
+		return fmt.Errorf("proxyNodePort requires AntreaProxy to be enabled")
+	}
+	if encapMode == config.TrafficEncapModeNetworkPolicyOnly {
+		return fmt.Errorf("proxyNodePort is not applicable to the %s mode", config.TrafficEncapModeNetworkPolicyOnly)
+	}
+	for _, nodePortAddress := range o.config.AntreaProxy.NodePortAddresses {
+		if _, _, err := net.ParseCIDR(nodePortAddress); err != nil {
+			return fmt.Errorf("NodePort address %s is invalid", nodePortAddress)
+		}
+	}
 	return nil
 }
 
@@ -197,6 +237,10 @@ func (o *Options) setDefaults() {
 		o.config.ClusterMembershipPort = apis.AntreaAgentClusterMembershipPort
 	}
 
+	if o.config.AntreaProxy.DefaultLoadBalancingMode == "" {
+		o.config.AntreaProxy.DefaultLoadBalancingMode = string(proxytypes.LoadBalancingModeRandom)
+	}
+
 	if features.DefaultFeatureGate.Enabled(features.FlowExporter) {
 		if o.config.FlowCollectorAddr == "" {
 			o.config.FlowCollectorAddr = defaultFlowCollectorAddress
@@ -258,6 +302,127 @@ func (o *Options) validateFlowExporterConfig() error {
 				klog.Warningf("IdleFlowExportTimeout must be greater than or equal to FlowPollInterval")
 			}
 		}
+		if err := o.validateFlowExportFilterConfig(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) validateFlowExportFilterConfig() error {
+	filterConfig := o.config.FlowExportFilter
+	if filterConfig.SamplingRate < 0 {
+		return fmt.Errorf("flowExportFilter samplingRate must be positive")
+	}
+	if len(filterConfig.Namespaces) == 0 && len(filterConfig.Protocols) == 0 && len(filterConfig.CIDRs) == 0 &&
+		len(filterConfig.FlowTypes) == 0 && filterConfig.SamplingRate <= 1 {
+		return nil
+	}
+	filter := &exporter.FlowFilter{
+		Namespaces:   filterConfig.Namespaces,
+		SamplingRate: 1,
+	}
+	for _, p := range filterConfig.Protocols {
+		protocols, err := exporter.ParseFlowProtocol(p)
+		if err != nil {
+			return fmt.Errorf("invalid flowExportFilter protocols: %v", err)
+		}
+		filter.Protocols = append(filter.Protocols, protocols...)
+	}
+	for _, c := range filterConfig.CIDRs {
+		_, cidr, err := net.ParseCIDR(c)
+		if err != nil {
+			return fmt.Errorf("invalid flowExportFilter cidrs: %v", err)
+		}
+		filter.CIDRs = append(filter.CIDRs, cidr)
+	}
+	for _, t := range filterConfig.FlowTypes {
+		flowType, err := exporter.ParseFlowType(t)
+		if err != nil {
+			return fmt.Errorf("invalid flowExportFilter flowTypes: %v", err)
+		}
+		filter.FlowTypes = append(filter.FlowTypes, flowType)
+	}
+	if filterConfig.SamplingRate > 1 {
+		filter.SamplingRate = uint32(filterConfig.SamplingRate)
+	}
+	o.flowExportFilter = filter
+	return nil
+}
+
+func (o *Options) validateEgressConfig() error {
+	healthCheck := o.config.Egress.HealthCheck
+	if !features.DefaultFeatureGate.Enabled(features.Egress) || !healthCheck.Enable {
+		return nil
+	}
+	healthCheckConfig := &memberlist.HealthCheckConfig{
+		Interval:         memberlist.DefaultHealthCheckInterval,
+		FailureThreshold: memberlist.DefaultHealthCheckFailureThreshold,
+		Checks:           []memberlist.HealthCheckType{memberlist.HealthCheckUplink, memberlist.HealthCheckGateway},
+	}
+	if healthCheck.Interval != "" {
+		interval, err := time.ParseDuration(healthCheck.Interval)
+		if err != nil {
+			return fmt.Errorf("health check interval %s is invalid: %v", healthCheck.Interval, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("health check interval must be positive")
+		}
+		healthCheckConfig.Interval = interval
+	}
+	if healthCheck.FailureThreshold < 0 {
+		return fmt.Errorf("health check failureThreshold must be positive")
+	} else if healthCheck.FailureThreshold > 0 {
+		healthCheckConfig.FailureThreshold = healthCheck.FailureThreshold
+	}
+	if len(healthCheck.Checks) > 0 {
+		healthCheckConfig.Checks = nil
+		for _, check := range healthCheck.Checks {
+			checkType := memberlist.HealthCheckType(check)
+			if !memberlist.IsValidHealthCheckType(checkType) {
+				return fmt.Errorf("health check %s is not supported", check)
+			}
+			healthCheckConfig.Checks = append(healthCheckConfig.Checks, checkType)
+		}
+	}
+	o.egressHealthCheckConfig = healthCheckConfig
+	return nil
+}
+
+func (o *Options) validateAuditLoggingConfig() error {
+	auditLogging := o.config.AuditLogging
+	auditLoggerConfig := &networkpolicy.AuditLoggerConfig{
+		Format: networkpolicy.AuditLogFormatText,
+		Output: networkpolicy.AuditLogOutputFile,
+	}
+	switch auditLogging.Format {
+	case "", networkpolicy.AuditLogFormatText:
+	case networkpolicy.AuditLogFormatJSON:
+		auditLoggerConfig.Format = networkpolicy.AuditLogFormatJSON
+	default:
+		return fmt.Errorf("audit log format %s is not supported", auditLogging.Format)
+	}
+	switch auditLogging.Output {
+	case "", networkpolicy.AuditLogOutputFile:
+	case networkpolicy.AuditLogOutputSyslog:
+		if auditLogging.SyslogAddress == "" {
+			return fmt.Errorf("syslogAddress must be set when the audit log output is %s", networkpolicy.AuditLogOutputSyslog)
+		}
+		network, address, err := networkpolicy.ParseSyslogAddress(auditLogging.SyslogAddress)
+		if err != nil {
+			return err
+		}
+		auditLoggerConfig.Output = networkpolicy.AuditLogOutputSyslog
+		auditLoggerConfig.SyslogNetwork = network
+		auditLoggerConfig.SyslogAddress = address
+	default:
+		return fmt.Errorf("audit log output %s is not supported", auditLogging.Output)
+	}
+	if auditLogging.RateLimit < 0 || auditLogging.RateLimitBurst < 0 {
+		return fmt.Errorf("audit log rateLimit and rateLimitBurst must not be negative")
 	}
+	auditLoggerConfig.RateLimit = auditLogging.RateLimit
+	auditLoggerConfig.RateLimitBurst = auditLogging.RateLimitBurst
+	o.auditLoggerConfig = auditLoggerConfig
 	return nil
 }
@@ -51,6 +51,12 @@ func (o *Options) checkUnsupportedFeatures() error {
 	if o.config.EnableIPSecTunnel {
 		unsupported = append(unsupported, "IPsecTunnel")
 	}
+	if o.config.AntreaProxy.ProxyNodePort {
+		unsupported = append(unsupported, "AntreaProxy: ProxyNodePort")
+	}
+	if o.config.Egress.HealthCheck.Enable {
+		unsupported = append(unsupported, "Egress: HealthCheck")
+	}
 
 	if unsupported != nil {
 		return fmt.Errorf("unsupported features on Windows: {%s}", strings.Join(unsupported, ", "))
@@ -153,6 +153,7 @@ func run(o *Options) error {
 		crdClient,
 		groupEntityIndex,
 		namespaceInformer,
+		nodeInformer,
 		serviceInformer,
 		networkPolicyInformer,
 		cnpInformer,
@@ -166,7 +167,7 @@ func run(o *Options) error {
 
 	var networkPolicyStatusController *networkpolicy.StatusController
 	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
-		networkPolicyStatusController = networkpolicy.NewStatusController(crdClient, networkPolicyStore, cnpInformer, anpInformer)
+		networkPolicyStatusController = networkpolicy.NewStatusController(crdClient, networkPolicyStore, appliedToGroupStore, addressGroupStore, cnpInformer, anpInformer)
 	}
 
 	var anpMirroringController *crdmirroring.Controller
@@ -223,6 +224,8 @@ func run(o *Options) error {
 
 	endpointQuerier := networkpolicy.NewEndpointQuerier(networkPolicyController)
 
+	reachabilityQuerier := networkpolicy.NewReachabilityQuerier(networkPolicyController, podInformer)
+
 	controllerQuerier := querier.NewControllerQuerier(networkPolicyController, o.config.APIPort)
 
 	controllerMonitor := monitor.NewControllerMonitor(crdClient, legacyCRDClient, nodeInformer, controllerQuerier)
@@ -274,6 +277,7 @@ func run(o *Options) error {
 		egressGroupStore,
 		controllerQuerier,
 		endpointQuerier,
+		reachabilityQuerier,
 		networkPolicyController,
 		networkPolicyStatusController,
 		egressController,
@@ -369,6 +373,7 @@ func createAPIServerConfig(kubeconfig string,
 	egressGroupStore storage.Interface,
 	controllerQuerier querier.ControllerQuerier,
 	endpointQuerier networkpolicy.EndpointQuerier,
+	reachabilityQuerier networkpolicy.ReachabilityQuerier,
 	npController *networkpolicy.NetworkPolicyController,
 	networkPolicyStatusController *networkpolicy.StatusController,
 	egressController *egress.EgressController,
@@ -432,6 +437,7 @@ func createAPIServerConfig(kubeconfig string,
 		controllerQuerier,
 		networkPolicyStatusController,
 		endpointQuerier,
+		reachabilityQuerier,
 		npController,
 		egressController), nil
 }
@@ -20,6 +20,9 @@ package main
 import (
 	"flag"
 	"os"
+	// Embed the time zone database, as the time zones of the activation
+	// windows of Antrea-native policies must be loadable in any image.
+	_ "time/tzdata"
 
 	"github.com/spf13/cobra"
 	"k8s.io/component-base/logs"
@@ -48,4 +48,96 @@ type FlowAggregatorConfig struct {
 	// is not available), a value will be randomly generated, which may vary across restarts of the flow
 	// aggregator.
 	ObservationDomainID *uint32 `yaml:"observationDomainID,omitempty"`
+	// Provide the sinks to which the aggregated flow records are exported in addition to, or instead
+	// of, the IPFIX flow collector. All enabled sinks receive all flow records.
+	FlowSinks FlowSinksConfig `yaml:"flowSinks,omitempty"`
+	// Provide the configuration of the in-memory window of recent aggregated flow records, which can be
+	// queried with "antctl get flows" from the flow aggregator Pod.
+	FlowQuery FlowQueryConfig `yaml:"flowQuery,omitempty"`
+	// Provide the configuration of the Prometheus metrics computed from the aggregated flow records. The
+	// metrics are served by the flow aggregator API server on port 10348.
+	FlowMetrics FlowMetricsConfig `yaml:"flowMetrics,omitempty"`
+}
+
+type FlowQueryConfig struct {
+	// Enable keeping the recent aggregated flow records in memory and serving the flow query API.
+	// Defaults to true.
+	Enable bool `yaml:"enable,omitempty"`
+	// Provide the time window as a duration string. The last flow record exported for a connection
+	// is kept until it is older than this window. Defaults to "10m".
+	Window string `yaml:"window,omitempty"`
+	// Maximum number of connections whose flow records are kept. The oldest flow records are evicted
+	// first beyond this limit. Defaults to 10000.
+	MaxFlows int `yaml:"maxFlows,omitempty"`
+}
+
+type FlowSinksConfig struct {
+	File       FileSinkConfig       `yaml:"file,omitempty"`
+	Kafka      KafkaSinkConfig      `yaml:"kafka,omitempty"`
+	ClickHouse ClickHouseSinkConfig `yaml:"clickHouse,omitempty"`
+}
+
+type FileSinkConfig struct {
+	// Enable writing the flow records to a local file, which is rotated when it reaches maxSize.
+	// Defaults to false.
+	Enable bool `yaml:"enable,omitempty"`
+	// Path of the file. Defaults to "/var/log/antrea/flow-aggregator/flows.log".
+	Path string `yaml:"path,omitempty"`
+	// Format of the flow records, "json" (one JSON object per line) or "csv". Defaults to "json".
+	Format string `yaml:"format,omitempty"`
+	// Maximum size in megabytes of the file before it gets rotated. Defaults to 100.
+	MaxSize int `yaml:"maxSize,omitempty"`
+	// Maximum number of rotated files to retain. Defaults to 3.
+	MaxBackups int `yaml:"maxBackups,omitempty"`
+	// Compress the rotated files with gzip. Defaults to false.
+	Compress bool `yaml:"compress,omitempty"`
+}
+
+type KafkaSinkConfig struct {
+	// Enable publishing each flow record as a message to a Kafka topic. Defaults to false.
+	Enable bool `yaml:"enable,omitempty"`
+	// Addresses of the Kafka brokers, with format <host>:<port>.
+	Brokers []string `yaml:"brokers,omitempty"`
+	// Kafka topic to which the flow records are published. Defaults to "flows".
+	Topic string `yaml:"topic,omitempty"`
+	// Format of the messages, "json" or "protobuf" (FlowType2 schema of go-ipfix). Defaults to "json".
+	Format string `yaml:"format,omitempty"`
+	// Version of the Kafka brokers, e.g. "2.6.0". Defaults to the oldest version supported by the client.
+	Version string `yaml:"version,omitempty"`
+}
+
+type ClickHouseSinkConfig struct {
+	// Enable inserting the flow records into a ClickHouse table by batches. Defaults to false.
+	Enable bool `yaml:"enable,omitempty"`
+	// URL of the HTTP interface of the ClickHouse server, e.g. "http://clickhouse.default.svc:8123".
+	Address string `yaml:"address,omitempty"`
+	// Database of the table. Defaults to "default".
+	Database string `yaml:"database,omitempty"`
+	// Table into which the flow records are inserted. Defaults to "flows".
+	Table string `yaml:"table,omitempty"`
+	// Username used to authenticate to the ClickHouse server. The password is read from the
+	// CLICKHOUSE_PASSWORD environment variable, which can be populated from a Secret.
+	Username string `yaml:"username,omitempty"`
+	// Maximum number of flow records inserted with a single query. The buffered flow records are
+	// also inserted at the end of each export cycle. Defaults to 1000.
+	BatchSize int `yaml:"batchSize,omitempty"`
+}
+
+type FlowMetricsConfig struct {
+	// Enable the per-Namespace, per-Service and per-NetworkPolicy-rule byte, packet and connection
+	// counters and the top talker gauge. Defaults to true.
+	Enable bool `yaml:"enable,omitempty"`
+	// Maximum number of Namespaces for which the Namespace metrics are reported. The traffic of the
+	// other Namespaces is reported with the "_other" label value. Defaults to 100.
+	MaxNamespaces int `yaml:"maxNamespaces,omitempty"`
+	// Maximum number of Service ports for which the Service metrics are reported. Defaults to 100.
+	MaxServices int `yaml:"maxServices,omitempty"`
+	// Maximum number of NetworkPolicy rules for which the NetworkPolicy rule metrics are reported.
+	// Defaults to 100.
+	MaxNetworkPolicyRules int `yaml:"maxNetworkPolicyRules,omitempty"`
+	// Number of Pods with the most traffic reported by the top talker gauge. Defaults to 10.
+	TopTalkers int `yaml:"topTalkers,omitempty"`
+	// Provide the interval over which the traffic of the top talkers is computed, as a duration
+	// string. Defaults to "1m".
+	TopTalkerInterval string `yaml:"topTalkerInterval,omitempty"`
 }
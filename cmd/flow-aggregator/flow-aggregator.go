@@ -26,8 +26,11 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
+	"antrea.io/antrea/pkg/apis"
 	"antrea.io/antrea/pkg/clusteridentity"
 	aggregator "antrea.io/antrea/pkg/flowaggregator"
+	"antrea.io/antrea/pkg/flowaggregator/apiserver"
+	"antrea.io/antrea/pkg/flowaggregator/metrics"
 	"antrea.io/antrea/pkg/signals"
 )
 
@@ -93,6 +96,38 @@ func run(o *Options) error {
 	}
 	klog.Infof("Flow aggregator Observation Domain ID: %d", observationDomainID)
 
+	var sinks []aggregator.FlowSink
+	if o.fileSinkConfig != nil {
+		sink, err := aggregator.NewFileSink(*o.fileSinkConfig)
+		if err != nil {
+			return fmt.Errorf("error when creating file sink: %v", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if o.kafkaSinkConfig != nil {
+		sink, err := aggregator.NewKafkaSink(*o.kafkaSinkConfig)
+		if err != nil {
+			return fmt.Errorf("error when creating Kafka sink: %v", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if o.clickHouseSinkConfig != nil {
+		sink, err := aggregator.NewClickHouseSink(*o.clickHouseSinkConfig)
+		if err != nil {
+			return fmt.Errorf("error when creating ClickHouse sink: %v", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	var flowStore *aggregator.FlowStore
+	if o.config.FlowQuery.Enable {
+		flowStore = aggregator.NewFlowStore(o.flowQueryWindow, o.flowQueryMaxFlows)
+		sinks = append(sinks, flowStore)
+	}
+	if o.metricsSinkConfig != nil {
+		metrics.InitializePrometheusMetrics()
+		sinks = append(sinks, aggregator.NewMetricsSink(*o.metricsSinkConfig))
+	}
+
 	flowAggregator := aggregator.NewFlowAggregator(
 		o.externalFlowCollectorAddr,
 		o.externalFlowCollectorProto,
@@ -103,6 +138,7 @@ func run(o *Options) error {
 		k8sClient,
 		observationDomainID,
 		podInformer,
+		sinks,
 	)
 	err = flowAggregator.InitCollectingProcess()
 	if err != nil {
@@ -114,6 +150,18 @@ func run(o *Options) error {
 	}
 	go flowAggregator.Run(stopCh)
 
+	if flowStore != nil || o.metricsSinkConfig != nil {
+		var flowQuerier aggregator.FlowQuerier
+		if flowStore != nil {
+			flowQuerier = flowStore
+		}
+		apiServer, err := apiserver.New(flowQuerier, apis.FlowAggregatorAPIPort, o.metricsSinkConfig != nil)
+		if err != nil {
+			return fmt.Errorf("error when creating flow aggregator API server: %v", err)
+		}
+		go apiServer.Run(stopCh)
+	}
+
 	informerFactory.Start(stopCh)
 
 	<-stopCh
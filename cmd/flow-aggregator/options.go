@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"os"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -35,6 +36,21 @@ const (
 	defaultInactiveFlowRecordTimeout      = 90 * time.Second
 	defaultAggregatorTransportProtocol    = flowaggregator.AggregatorTransportProtocolTLS
 	defaultFlowAggregatorAddress          = "flow-aggregator.flow-aggregator.svc"
+	defaultFileSinkPath                   = "/var/log/antrea/flow-aggregator/flows.log"
+	defaultFileSinkMaxSize                = 100
+	defaultFileSinkMaxBackups             = 3
+	defaultKafkaSinkTopic                 = "flows"
+	defaultClickHouseSinkDatabase         = "default"
+	defaultClickHouseSinkTable            = "flows"
+	defaultClickHouseSinkBatchSize        = 1000
+	clickHousePasswordEnvKey              = "CLICKHOUSE_PASSWORD"
+	defaultFlowQueryWindow                = 10 * time.Minute
+	defaultFlowQueryMaxFlows              = 10000
+	defaultFlowMetricsMaxNamespaces       = 100
+	defaultFlowMetricsMaxServices         = 100
+	defaultFlowMetricsMaxRules            = 100
+	defaultFlowMetricsTopTalkers          = 10
+	defaultFlowMetricsTopTalkerInterval   = time.Minute
 )
 
 type Options struct {
@@ -54,11 +70,30 @@ type Options struct {
 	aggregatorTransportProtocol flowaggregator.AggregatorTransportProtocol
 	// DNS name or IP address of flow aggregator for generating TLS certificate
 	flowAggregatorAddress string
+	// Configuration of the file sink, nil if it is disabled
+	fileSinkConfig *flowaggregator.FileSinkConfig
+	// Configuration of the Kafka sink, nil if it is disabled
+	kafkaSinkConfig *flowaggregator.KafkaSinkConfig
+	// Configuration of the ClickHouse sink, nil if it is disabled
+	clickHouseSinkConfig *flowaggregator.ClickHouseSinkConfig
+	// Time window of the flow records kept for the flow query API
+	flowQueryWindow time.Duration
+	// Maximum number of connections whose flow records are kept for the flow query API
+	flowQueryMaxFlows int
+	// Configuration of the flow metrics, nil if they are disabled
+	metricsSinkConfig *flowaggregator.MetricsSinkConfig
 }
 
 func newOptions() *Options {
 	return &Options{
-		config: new(FlowAggregatorConfig),
+		config: &FlowAggregatorConfig{
+			FlowQuery: FlowQueryConfig{
+				Enable: true,
+			},
+			FlowMetrics: FlowMetricsConfig{
+				Enable: true,
+			},
+		},
 	}
 }
 
@@ -84,15 +119,28 @@ func (o *Options) validate(args []string) error {
 	if len(args) != 0 {
 		return errors.New("no positional arguments are supported")
 	}
-	if o.config.ExternalFlowCollectorAddr == "" {
-		return fmt.Errorf("IPFIX flow collector address should be provided")
+	if err := o.validateFlowSinksConfig(); err != nil {
+		return err
 	}
-	host, port, proto, err := flowexport.ParseFlowCollectorAddr(o.config.ExternalFlowCollectorAddr, defaultExternalFlowCollectorPort, defaultExternalFlowCollectorTransport)
-	if err != nil {
+	if err := o.validateFlowQueryConfig(); err != nil {
+		return err
+	}
+	if err := o.validateFlowMetricsConfig(); err != nil {
 		return err
 	}
-	o.externalFlowCollectorAddr = net.JoinHostPort(host, port)
-	o.externalFlowCollectorProto = proto
+	var err error
+	if o.config.ExternalFlowCollectorAddr == "" {
+		if o.fileSinkConfig == nil && o.kafkaSinkConfig == nil && o.clickHouseSinkConfig == nil && !o.config.FlowQuery.Enable && o.metricsSinkConfig == nil {
+			return fmt.Errorf("IPFIX flow collector address should be provided when no other flow sink, flow query or flow metrics is enabled")
+		}
+	} else {
+		host, port, proto, err := flowexport.ParseFlowCollectorAddr(o.config.ExternalFlowCollectorAddr, defaultExternalFlowCollectorPort, defaultExternalFlowCollectorTransport)
+		if err != nil {
+			return err
+		}
+		o.externalFlowCollectorAddr = net.JoinHostPort(host, port)
+		o.externalFlowCollectorProto = proto
+	}
 	if o.config.ActiveFlowRecordTimeout == "" {
 		o.activeFlowRecordTimeout = defaultActiveFlowRecordTimeout
 	} else {
@@ -126,12 +174,157 @@ func (o *Options) validate(args []string) error {
 	return nil
 }
 
+func (o *Options) validateFlowSinksConfig() error {
+	fileConfig := o.config.FlowSinks.File
+	if fileConfig.Enable {
+		o.fileSinkConfig = &flowaggregator.FileSinkConfig{
+			Path:       fileConfig.Path,
+			Format:     fileConfig.Format,
+			MaxSize:    fileConfig.MaxSize,
+			MaxBackups: fileConfig.MaxBackups,
+			Compress:   fileConfig.Compress,
+		}
+		if o.fileSinkConfig.Path == "" {
+			o.fileSinkConfig.Path = defaultFileSinkPath
+		}
+		if o.fileSinkConfig.Format == "" {
+			o.fileSinkConfig.Format = flowaggregator.FlowRecordFormatJSON
+		} else if o.fileSinkConfig.Format != flowaggregator.FlowRecordFormatJSON && o.fileSinkConfig.Format != flowaggregator.FlowRecordFormatCSV {
+			return fmt.Errorf("format of file sink must be %s or %s", flowaggregator.FlowRecordFormatJSON, flowaggregator.FlowRecordFormatCSV)
+		}
+		if o.fileSinkConfig.MaxSize == 0 {
+			o.fileSinkConfig.MaxSize = defaultFileSinkMaxSize
+		}
+		if o.fileSinkConfig.MaxBackups == 0 {
+			o.fileSinkConfig.MaxBackups = defaultFileSinkMaxBackups
+		}
+		if o.fileSinkConfig.MaxSize < 0 || o.fileSinkConfig.MaxBackups < 0 {
+			return fmt.Errorf("maxSize and maxBackups of file sink must not be negative")
+		}
+	}
+	kafkaConfig := o.config.FlowSinks.Kafka
+	if kafkaConfig.Enable {
+		if len(kafkaConfig.Brokers) == 0 {
+			return fmt.Errorf("brokers should be provided when Kafka sink is enabled")
+		}
+		o.kafkaSinkConfig = &flowaggregator.KafkaSinkConfig{
+			Brokers: kafkaConfig.Brokers,
+			Topic:   kafkaConfig.Topic,
+			Format:  kafkaConfig.Format,
+			Version: kafkaConfig.Version,
+		}
+		if o.kafkaSinkConfig.Topic == "" {
+			o.kafkaSinkConfig.Topic = defaultKafkaSinkTopic
+		}
+		if o.kafkaSinkConfig.Format == "" {
+			o.kafkaSinkConfig.Format = flowaggregator.FlowRecordFormatJSON
+		} else if o.kafkaSinkConfig.Format != flowaggregator.FlowRecordFormatJSON && o.kafkaSinkConfig.Format != flowaggregator.FlowRecordFormatProtobuf {
+			return fmt.Errorf("format of Kafka sink must be %s or %s", flowaggregator.FlowRecordFormatJSON, flowaggregator.FlowRecordFormatProtobuf)
+		}
+	}
+	clickHouseConfig := o.config.FlowSinks.ClickHouse
+	if clickHouseConfig.Enable {
+		if clickHouseConfig.Address == "" {
+			return fmt.Errorf("address should be provided when ClickHouse sink is enabled")
+		}
+		o.clickHouseSinkConfig = &flowaggregator.ClickHouseSinkConfig{
+			Address:   clickHouseConfig.Address,
+			Database:  clickHouseConfig.Database,
+			Table:     clickHouseConfig.Table,
+			Username:  clickHouseConfig.Username,
+			Password:  os.Getenv(clickHousePasswordEnvKey),
+			BatchSize: clickHouseConfig.BatchSize,
+		}
+		if o.clickHouseSinkConfig.Database == "" {
+			o.clickHouseSinkConfig.Database = defaultClickHouseSinkDatabase
+		}
+		if o.clickHouseSinkConfig.Table == "" {
+			o.clickHouseSinkConfig.Table = defaultClickHouseSinkTable
+		}
+		if o.clickHouseSinkConfig.BatchSize == 0 {
+			o.clickHouseSinkConfig.BatchSize = defaultClickHouseSinkBatchSize
+		} else if o.clickHouseSinkConfig.BatchSize < 0 {
+			return fmt.Errorf("batchSize of ClickHouse sink must be positive")
+		}
+	}
+	return nil
+}
+
+func (o *Options) validateFlowQueryConfig() error {
+	queryConfig := o.config.FlowQuery
+	if !queryConfig.Enable {
+		return nil
+	}
+	if queryConfig.Window == "" {
+		o.flowQueryWindow = defaultFlowQueryWindow
+	} else {
+		var err error
+		o.flowQueryWindow, err = time.ParseDuration(queryConfig.Window)
+		if err != nil {
+			return err
+		}
+		if o.flowQueryWindow <= 0 {
+			return fmt.Errorf("window of flow query must be positive")
+		}
+	}
+	if queryConfig.MaxFlows == 0 {
+		o.flowQueryMaxFlows = defaultFlowQueryMaxFlows
+	} else if queryConfig.MaxFlows < 0 {
+		return fmt.Errorf("maxFlows of flow query must be positive")
+	} else {
+		o.flowQueryMaxFlows = queryConfig.MaxFlows
+	}
+	return nil
+}
+
+func (o *Options) validateFlowMetricsConfig() error {
+	metricsConfig := o.config.FlowMetrics
+	if !metricsConfig.Enable {
+		return nil
+	}
+	o.metricsSinkConfig = &flowaggregator.MetricsSinkConfig{
+		MaxNamespaces:         metricsConfig.MaxNamespaces,
+		MaxServices:           metricsConfig.MaxServices,
+		MaxNetworkPolicyRules: metricsConfig.MaxNetworkPolicyRules,
+		TopTalkers:            metricsConfig.TopTalkers,
+		TopTalkerInterval:     defaultFlowMetricsTopTalkerInterval,
+	}
+	if o.metricsSinkConfig.MaxNamespaces == 0 {
+		o.metricsSinkConfig.MaxNamespaces = defaultFlowMetricsMaxNamespaces
+	}
+	if o.metricsSinkConfig.MaxServices == 0 {
+		o.metricsSinkConfig.MaxServices = defaultFlowMetricsMaxServices
+	}
+	if o.metricsSinkConfig.MaxNetworkPolicyRules == 0 {
+		o.metricsSinkConfig.MaxNetworkPolicyRules = defaultFlowMetricsMaxRules
+	}
+	if o.metricsSinkConfig.TopTalkers == 0 {
+		o.metricsSinkConfig.TopTalkers = defaultFlowMetricsTopTalkers
+	}
+	if o.metricsSinkConfig.MaxNamespaces < 0 || o.metricsSinkConfig.MaxServices < 0 ||
+		o.metricsSinkConfig.MaxNetworkPolicyRules < 0 || o.metricsSinkConfig.TopTalkers < 0 {
+		return fmt.Errorf("maxNamespaces, maxServices, maxNetworkPolicyRules and topTalkers of flow metrics must not be negative")
+	}
+	if metricsConfig.TopTalkerInterval != "" {
+		var err error
+		o.metricsSinkConfig.TopTalkerInterval, err = time.ParseDuration(metricsConfig.TopTalkerInterval)
+		if err != nil {
+			return err
+		}
+		if o.metricsSinkConfig.TopTalkerInterval <= 0 {
+			return fmt.Errorf("topTalkerInterval of flow metrics must be positive")
+		}
+	}
+	return nil
+}
+
 func (o *Options) loadConfigFromFile(file string) (*FlowAggregatorConfig, error) {
 	data, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, err
 	}
-	c := FlowAggregatorConfig{}
+	// Start from the default configuration, for the fields whose zero value is not the default.
+	c := *o.config
 	err = yaml.UnmarshalStrict(data, &c)
 	if err != nil {
 		return nil, err
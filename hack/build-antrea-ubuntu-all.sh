@@ -20,14 +20,15 @@ function echoerr {
     >&2 echo "$@"
 }
 
-_usage="Usage: $0 [--pull] [--push-base-images] [--coverage] [--platform <PLATFORM>]
+_usage="Usage: $0 [--pull] [--push-base-images] [--coverage] [--platform <PLATFORM>] [--with-suricata]
 Build the antrea/antrea-ubuntu image, as well as all the base images in the build chain. This is
 typically used in CI to build the image with the latest version of all dependencies, taking into
 account changes to all Dockerfiles.
         --pull                  Always attempt to pull a newer version of the base images.
         --push-base-images      Push built images to the registry. Only base images will be pushed.
         --coverage              Build the image with support for code coverage.
-        --platform <PLATFORM>   Target platform for the images if server is multi-platform capable."
+        --platform <PLATFORM>   Target platform for the images if server is multi-platform capable.
+        --with-suricata         Build the image with Suricata, the L7 engine of the L7NetworkPolicy feature."
 
 function print_usage {
     echoerr "$_usage"
@@ -37,6 +38,7 @@ PULL=false
 PUSH=false
 COVERAGE=false
 PLATFORM=""
+WITH_SURICATA=false
 
 while [[ $# -gt 0 ]]
 do
@@ -59,6 +61,10 @@ case $key in
     PLATFORM="$2"
     shift 2
     ;;
+    --with-suricata)
+    WITH_SURICATA=true
+    shift
+    ;;
     -h|--help)
     print_usage
     exit 0
@@ -83,9 +89,17 @@ if [ "$PLATFORM" != "" ]; then
     ARGS="$ARGS --platform $PLATFORM"
     PLATFORM_ARG="--platform $PLATFORM"
 fi
+BASE_ARGS="$ARGS"
+if $WITH_SURICATA; then
+    BASE_ARGS="$BASE_ARGS --with-suricata"
+fi
 
 OVS_VERSION=$(head -n 1 build/images/deps/ovs-version)
 CNI_BINARIES_VERSION=$(head -n 1 build/images/deps/cni-binaries-version)
+BASE_IMAGE_TAG=$OVS_VERSION
+if $WITH_SURICATA; then
+    BASE_IMAGE_TAG=$OVS_VERSION-suricata
+fi
 
 # We pull all images ahead of time, instead of calling the independent build.sh
 # scripts with "--pull". We do not want to overwrite the antrea/openvswitch
@@ -106,7 +120,7 @@ if $PULL; then
         "antrea/openvswitch-debs:$OVS_VERSION"
         "antrea/openvswitch:$OVS_VERSION"
         "antrea/cni-binaries:$CNI_BINARIES_VERSION"
-        "antrea/base-ubuntu:$OVS_VERSION"
+        "antrea/base-ubuntu:$BASE_IMAGE_TAG"
     )
     for image in "${IMAGES_LIST[@]}"; do
         if [[ ${DOCKER_REGISTRY} == "" ]]; then
@@ -126,10 +140,13 @@ cd build/images/ovs
 cd -
 
 cd build/images/base
-./build.sh $ARGS
+./build.sh $BASE_ARGS
 cd -
 
 export NO_PULL=1
+if $WITH_SURICATA; then
+    export WITH_SURICATA=1
+fi
 if $COVERAGE; then
     make build-ubuntu-coverage
 else
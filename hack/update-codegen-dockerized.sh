@@ -170,10 +170,10 @@ MOCKGEN_TARGETS=(
   "pkg/agent/route Interface testing"
   "pkg/agent/controller/egress/ipassigner IPAssigner testing"
   "pkg/antctl AntctlClient ."
-  "pkg/controller/networkpolicy EndpointQuerier testing"
+  "pkg/controller/networkpolicy EndpointQuerier,ReachabilityQuerier,RuleConflictQuerier testing"
   "pkg/controller/querier ControllerQuerier testing"
   "pkg/ipfix IPFIXExportingProcess,IPFIXRegistry,IPFIXCollectingProcess,IPFIXAggregationProcess testing"
-  "pkg/ovs/openflow Bridge,Table,Flow,Action,CTAction,FlowBuilder testing"
+  "pkg/ovs/openflow Bridge,Table,Flow,Action,CTAction,FlowBuilder,Meter,MeterBandBuilder testing"
   "pkg/ovs/ovsconfig OVSBridgeClient testing"
   "pkg/ovs/ovsctl OVSCtlClient testing"
   "pkg/querier AgentNetworkPolicyInfoQuerier testing"
@@ -41,6 +41,7 @@ import (
 	"antrea.io/antrea/pkg/agent/route"
 	"antrea.io/antrea/pkg/agent/types"
 	"antrea.io/antrea/pkg/agent/util"
+	"antrea.io/antrea/pkg/features"
 	"antrea.io/antrea/pkg/ovs/ovsconfig"
 	"antrea.io/antrea/pkg/util/env"
 	"antrea.io/antrea/pkg/util/k8s"
@@ -75,6 +76,10 @@ type Initializer struct {
 	networkConfig   *config.NetworkConfig
 	nodeConfig      *config.NodeConfig
 	enableProxy     bool
+	// l7NPTargetOFPort and l7NPReturnOFPort are the ofports of the OVS internal ports connecting the OVS bridge
+	// to the L7 engine. They are only set when the L7NetworkPolicy feature is enabled.
+	l7NPTargetOFPort uint32
+	l7NPReturnOFPort uint32
 	// networkReadyCh should be closed once the Node's network is ready.
 	// The CNI server will wait for it before handling any CNI Add requests.
 	networkReadyCh chan<- struct{}
@@ -144,6 +149,12 @@ func (i *Initializer) setupOVSBridge() error {
 		return err
 	}
 
+	if features.DefaultFeatureGate.Enabled(features.L7NetworkPolicy) {
+		if err := i.setupL7NetworkPolicyInterfaces(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -332,6 +343,14 @@ func (i *Initializer) initOpenFlowPipeline() error {
 		}
 	}
 
+	if features.DefaultFeatureGate.Enabled(features.L7NetworkPolicy) {
+		// Set up flow entries to receive the packets sent back to the OVS bridge by the L7 engine.
+		if err := i.ofClient.InstallL7NetworkPolicyFlows(i.l7NPTargetOFPort, i.l7NPReturnOFPort); err != nil {
+			klog.Errorf("Failed to setup OpenFlow entries for L7 NetworkPolicy: %v", err)
+			return err
+		}
+	}
+
 	if !i.enableProxy {
 		// Set up flow entries to enable Service connectivity. Upstream kube-proxy is leveraged to
 		// provide load-balancing, and the flows installed by this method ensure that traffic sent
@@ -503,6 +522,39 @@ func (i *Initializer) setupGatewayInterface() error {
 	return nil
 }
 
+// setupL7NetworkPolicyInterfaces creates the OVS internal ports connecting the OVS bridge to the L7 engine: the
+// packets to be inspected are output to the target port, and the accepted packets are sent back to the OVS bridge
+// through the return port. The ofports of both are automatically assigned.
+func (i *Initializer) setupL7NetworkPolicyInterfaces() error {
+	setupInterface := func(name string) (uint32, error) {
+		if _, err := i.ovsBridgeClient.GetOFPort(name); err == nil {
+			klog.V(2).Infof("L7 NetworkPolicy port %s already exists on OVS bridge", name)
+		} else {
+			klog.V(2).Infof("Creating L7 NetworkPolicy port %s on OVS bridge", name)
+			if _, err := i.ovsBridgeClient.CreateInternalPort(name, config.AutoAssignedOFPort, nil); err != nil {
+				klog.Errorf("Failed to create L7 NetworkPolicy port %s on OVS bridge: %v", name, err)
+				return 0, err
+			}
+		}
+		ofPort, err := i.ovsBridgeClient.GetOFPort(name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get ofport of L7 NetworkPolicy port %s: %v", name, err)
+		}
+		if _, _, err := util.SetLinkUp(name); err != nil {
+			return 0, fmt.Errorf("failed to set L7 NetworkPolicy port %s up: %v", name, err)
+		}
+		return uint32(ofPort), nil
+	}
+	var err error
+	if i.l7NPTargetOFPort, err = setupInterface(config.L7NetworkPolicyTargetInterfaceName); err != nil {
+		return err
+	}
+	if i.l7NPReturnOFPort, err = setupInterface(config.L7NetworkPolicyReturnInterfaceName); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (i *Initializer) configureGatewayInterface(gatewayIface *interfacestore.InterfaceConfig) error {
 	var gwMAC net.HardwareAddr
 	var gwLinkIdx int
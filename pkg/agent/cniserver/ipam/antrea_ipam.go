@@ -0,0 +1,383 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/invoke"
+	cnitypes "github.com/containernetworking/cni/pkg/types"
+	"github.com/containernetworking/cni/pkg/types/current"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	crdv1a2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+	clientset "antrea.io/antrea/pkg/client/clientset/versioned"
+	crdinformers "antrea.io/antrea/pkg/client/informers/externalversions/crd/v1alpha2"
+	crdlisters "antrea.io/antrea/pkg/client/listers/crd/v1alpha2"
+	"antrea.io/antrea/pkg/ipam/ipallocator"
+)
+
+const (
+	// AntreaIPAMAnnotationKey is the annotation of the Namespaces whose Pods get their IPs from an IPPool. Its value
+	// is the name of the IPPool.
+	AntreaIPAMAnnotationKey = "ipam.antrea.io/ippools"
+)
+
+// cacheSyncTimeout is how long a request waits for the Namespace and IPPool caches to be synced, as the driver
+// can't tell whether it owns the request before. It's declared as a variable to allow overriding for testing.
+var cacheSyncTimeout = 30 * time.Second
+
+// podArgs are the Kubernetes arguments passed by kubelet in CNI_ARGS.
+type podArgs struct {
+	cnitypes.CommonArgs
+	K8S_POD_NAME               cnitypes.UnmarshallableString
+	K8S_POD_NAMESPACE          cnitypes.UnmarshallableString
+	K8S_POD_INFRA_CONTAINER_ID cnitypes.UnmarshallableString
+}
+
+// AntreaIPAM is an IPAMDriver which allocates the IPs of the Pods in the Namespaces annotated with
+// AntreaIPAMAnnotationKey from the IPPool named by the annotation. The allocations are persisted in the status of the
+// IPPool, so that they survive restarts of the Agent and don't depend on the Node which allocated them. It is
+// registered before the host-local driver, which handles the requests of the other Pods, and doesn't own any request
+// until it is initialized by InitializeAntreaIPAMDriver.
+type AntreaIPAM struct {
+	nodeName  string
+	crdClient clientset.Interface
+
+	namespaceLister       corelisters.NamespaceLister
+	namespaceListerSynced cache.InformerSynced
+	ipPoolLister          crdlisters.IPPoolLister
+	ipPoolListerSynced    cache.InformerSynced
+	// cacheSyncedCh is closed once the Namespace and IPPool caches are synced.
+	cacheSyncedCh chan struct{}
+
+	// mutex serializes the allocations made by the local Node. The allocations made by different Nodes are
+	// serialized by the resourceVersion of the IPPools.
+	mutex sync.Mutex
+}
+
+// antreaIPAMDriver is the registered Antrea IPAM driver.
+var antreaIPAMDriver = &AntreaIPAM{}
+
+// InitializeAntreaIPAMDriver initializes the registered Antrea IPAM driver, so that it owns the requests of the Pods
+// using an IPPool. It must be called before the CNI server is started. The requests wait for the Namespace and
+// IPPool caches to be synced once the informers are started.
+func InitializeAntreaIPAMDriver(
+	nodeName string,
+	crdClient clientset.Interface,
+	namespaceInformer coreinformers.NamespaceInformer,
+	ipPoolInformer crdinformers.IPPoolInformer,
+	stopCh <-chan struct{},
+) {
+	antreaIPAMDriver.init(nodeName, crdClient, namespaceInformer, ipPoolInformer, stopCh)
+}
+
+func (d *AntreaIPAM) init(
+	nodeName string,
+	crdClient clientset.Interface,
+	namespaceInformer coreinformers.NamespaceInformer,
+	ipPoolInformer crdinformers.IPPoolInformer,
+	stopCh <-chan struct{},
+) {
+	d.nodeName = nodeName
+	d.crdClient = crdClient
+	d.namespaceLister = namespaceInformer.Lister()
+	d.namespaceListerSynced = namespaceInformer.Informer().HasSynced
+	d.ipPoolLister = ipPoolInformer.Lister()
+	d.ipPoolListerSynced = ipPoolInformer.Informer().HasSynced
+	d.cacheSyncedCh = make(chan struct{})
+	go func() {
+		if cache.WaitForNamedCacheSync("AntreaIPAM", stopCh, d.namespaceListerSynced, d.ipPoolListerSynced) {
+			close(d.cacheSyncedCh)
+		}
+	}()
+}
+
+// waitForCacheSync waits for the Namespace and IPPool caches to be synced, for at most cacheSyncTimeout.
+func (d *AntreaIPAM) waitForCacheSync() error {
+	select {
+	case <-d.cacheSyncedCh:
+		return nil
+	case <-time.After(cacheSyncTimeout):
+		return fmt.Errorf("Namespace and IPPool caches are not synced after %v", cacheSyncTimeout)
+	}
+}
+
+func (d *AntreaIPAM) initialized() bool {
+	return d.crdClient != nil
+}
+
+// Add owns the request if the Namespace of the Pod selects an IPPool.
+func (d *AntreaIPAM) Add(args *invoke.Args, networkConfig []byte) (bool, *current.Result, error) {
+	if !d.initialized() {
+		return false, nil, nil
+	}
+	owner, poolName, err := d.getPodIPPool(args)
+	if err != nil {
+		return true, nil, err
+	}
+	if poolName == "" {
+		return false, nil, nil
+	}
+	ip, ipRange, err := d.allocateIP(poolName, owner)
+	if err != nil {
+		return true, nil, err
+	}
+	klog.InfoS("Allocated IP from IPPool", "ip", ip, "pool", poolName, "pod", klog.KRef(owner.Namespace, owner.Name))
+	return true, generateResult(ip, ipRange), nil
+}
+
+// Del owns the request if an IPPool has an IP allocated to the container.
+func (d *AntreaIPAM) Del(args *invoke.Args, networkConfig []byte) (bool, error) {
+	if !d.initialized() {
+		return false, nil
+	}
+	poolName, err := d.findIPPool(args)
+	if err != nil {
+		return true, err
+	}
+	if poolName == "" {
+		return false, nil
+	}
+	return true, d.releaseIP(poolName, args.ContainerID)
+}
+
+// Check owns the request if an IPPool has an IP allocated to the container.
+func (d *AntreaIPAM) Check(args *invoke.Args, networkConfig []byte) (bool, error) {
+	if !d.initialized() {
+		return false, nil
+	}
+	poolName, err := d.findIPPool(args)
+	if err != nil {
+		return true, err
+	}
+	return poolName != "", nil
+}
+
+// getPodIPPool returns the owner of the IP of the Pod in the CNI args, and the name of the IPPool selected by the
+// Namespace of the Pod. The name is empty if the Namespace doesn't select an IPPool.
+func (d *AntreaIPAM) getPodIPPool(args *invoke.Args) (*crdv1a2.PodOwner, string, error) {
+	k8sArgs := &podArgs{}
+	if err := cnitypes.LoadArgs(args.PluginArgsStr, k8sArgs); err != nil {
+		return nil, "", fmt.Errorf("failed to parse CNI args: %v", err)
+	}
+	owner := &crdv1a2.PodOwner{
+		Name:        string(k8sArgs.K8S_POD_NAME),
+		Namespace:   string(k8sArgs.K8S_POD_NAMESPACE),
+		ContainerID: args.ContainerID,
+	}
+	if owner.Namespace == "" {
+		return owner, "", nil
+	}
+	if err := d.waitForCacheSync(); err != nil {
+		return nil, "", err
+	}
+	namespace, err := d.namespaceLister.Get(owner.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return owner, "", nil
+		}
+		return nil, "", err
+	}
+	return owner, namespace.Annotations[AntreaIPAMAnnotationKey], nil
+}
+
+// findIPPool returns the name of the IPPool which has an IP allocated to the container in the CNI args. The name is
+// empty if no IPPool has an IP allocated to the container.
+func (d *AntreaIPAM) findIPPool(args *invoke.Args) (string, error) {
+	_, poolName, err := d.getPodIPPool(args)
+	if err != nil {
+		return "", err
+	}
+	// The IPPool selected by the Namespace is got from the API, as the lister may not have received the latest
+	// allocations yet.
+	if poolName != "" {
+		pool, err := d.crdClient.CrdV1alpha2().IPPools().Get(context.TODO(), poolName, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+		if err == nil && findAllocation(pool, args.ContainerID) >= 0 {
+			return poolName, nil
+		}
+	}
+	// The annotation of the Namespace may have been changed since the IP was allocated.
+	if err := d.waitForCacheSync(); err != nil {
+		return "", err
+	}
+	pools, err := d.ipPoolLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	for _, pool := range pools {
+		if findAllocation(pool, args.ContainerID) >= 0 {
+			return pool.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// allocateIP allocates an IP from the IPPool to the owner and records the allocation in the status of the IPPool,
+// with the local Node as the Node the IP is routed to. If an IP has already been allocated to the container of the owner, it is returned.
+func (d *AntreaIPAM) allocateIP(poolName string, owner *crdv1a2.PodOwner) (net.IP, *crdv1a2.SubnetIPRange, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	var ip net.IP
+	var ipRange *crdv1a2.SubnetIPRange
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ip, ipRange = nil, nil
+		pool, err := d.crdClient.CrdV1alpha2().IPPools().Get(context.TODO(), poolName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		allocators, err := newIPRangeAllocators(pool)
+		if err != nil {
+			return err
+		}
+		if i := findAllocation(pool, owner.ContainerID); i >= 0 {
+			ip = net.ParseIP(pool.Status.IPAddresses[i].IPAddress)
+			for j, allocator := range allocators {
+				if allocator.Has(ip) {
+					ipRange = &pool.Spec.IPRanges[j]
+					return nil
+				}
+			}
+			return fmt.Errorf("IP %s allocated to container %s is not in any range of IPPool %s", ip, owner.ContainerID, poolName)
+		}
+		for j, allocator := range allocators {
+			if ip, err = allocator.AllocateNext(); err == nil {
+				ipRange = &pool.Spec.IPRanges[j]
+				break
+			}
+		}
+		if ipRange == nil {
+			return fmt.Errorf("no available IP in IPPool %s", poolName)
+		}
+		toUpdate := pool.DeepCopy()
+		toUpdate.Status.IPAddresses = append(toUpdate.Status.IPAddresses, crdv1a2.IPAddressState{
+			IPAddress: ip.String(),
+			Phase:     crdv1a2.IPAddressPhaseAllocated,
+			Owner:     crdv1a2.IPAddressOwner{Pod: owner},
+			NodeName:  d.nodeName,
+		})
+		_, err = d.crdClient.CrdV1alpha2().IPPools().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to allocate IP from IPPool %s: %v", poolName, err)
+	}
+	return ip, ipRange, nil
+}
+
+// releaseIP removes the allocation of the container from the status of the IPPool.
+func (d *AntreaIPAM) releaseIP(poolName string, containerID string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := d.crdClient.CrdV1alpha2().IPPools().Get(context.TODO(), poolName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		i := findAllocation(pool, containerID)
+		if i < 0 {
+			return nil
+		}
+		toUpdate := pool.DeepCopy()
+		toUpdate.Status.IPAddresses = append(toUpdate.Status.IPAddresses[:i], toUpdate.Status.IPAddresses[i+1:]...)
+		_, err = d.crdClient.CrdV1alpha2().IPPools().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release IP of container %s to IPPool %s: %v", containerID, poolName, err)
+	}
+	klog.InfoS("Released IP to IPPool", "pool", poolName, "container", containerID)
+	return nil
+}
+
+// findAllocation returns the index of the IP allocated to the container in the status of the IPPool, or -1 if there
+// is none.
+func findAllocation(pool *crdv1a2.IPPool, containerID string) int {
+	for i, ipState := range pool.Status.IPAddresses {
+		if ipState.Owner.Pod != nil && ipState.Owner.Pod.ContainerID == containerID {
+			return i
+		}
+	}
+	return -1
+}
+
+// newIPRangeAllocators creates an allocator for each IP range of the IPPool, with the allocated IPs and the gateways
+// marked as used.
+func newIPRangeAllocators(pool *crdv1a2.IPPool) ([]*ipallocator.SingleIPAllocator, error) {
+	allocators := make([]*ipallocator.SingleIPAllocator, 0, len(pool.Spec.IPRanges))
+	for _, ipRange := range pool.Spec.IPRanges {
+		var allocator *ipallocator.SingleIPAllocator
+		var err error
+		if ipRange.CIDR != "" {
+			allocator, err = ipallocator.NewCIDRAllocator(ipRange.CIDR)
+		} else {
+			allocator, err = ipallocator.NewIPRangeAllocator(ipRange.Start, ipRange.End)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP range in IPPool %s: %v", pool.Name, err)
+		}
+		if gateway := net.ParseIP(ipRange.Gateway); gateway != nil && allocator.Has(gateway) {
+			allocator.AllocateIP(gateway)
+		}
+		allocators = append(allocators, allocator)
+	}
+	for _, ipState := range pool.Status.IPAddresses {
+		ip := net.ParseIP(ipState.IPAddress)
+		if ip == nil {
+			continue
+		}
+		for _, allocator := range allocators {
+			if allocator.Has(ip) {
+				allocator.AllocateIP(ip)
+				break
+			}
+		}
+	}
+	return allocators, nil
+}
+
+func generateResult(ip net.IP, ipRange *crdv1a2.SubnetIPRange) *current.Result {
+	version, bits := "6", 128
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, version, bits = ip4, "4", 32
+	}
+	return &current.Result{
+		CNIVersion: current.ImplementedSpecVersion,
+		IPs: []*current.IPConfig{
+			{
+				Version: version,
+				Address: net.IPNet{IP: ip, Mask: net.CIDRMask(int(ipRange.PrefixLength), bits)},
+				Gateway: net.ParseIP(ipRange.Gateway),
+			},
+		},
+	}
+}
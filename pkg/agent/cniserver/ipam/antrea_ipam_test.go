@@ -0,0 +1,258 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containernetworking/cni/pkg/invoke"
+	"github.com/containernetworking/cni/pkg/types/current"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	ipamtest "antrea.io/antrea/pkg/agent/cniserver/ipam/testing"
+	cnipb "antrea.io/antrea/pkg/apis/cni/v1beta1"
+	crdv1a2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+	fakeversioned "antrea.io/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "antrea.io/antrea/pkg/client/informers/externalversions"
+)
+
+const (
+	testPoolNamespace  = "pool-ns"
+	testOtherNamespace = "other-ns"
+	testPool           = "pool"
+	testNodeName       = "node1"
+)
+
+func newTestNamespace(name string, pool string) *corev1.Namespace {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if pool != "" {
+		namespace.Annotations = map[string]string{AntreaIPAMAnnotationKey: pool}
+	}
+	return namespace
+}
+
+func newTestIPPool(name string, ipRange crdv1a2.IPRange, allocatedIPs ...string) *crdv1a2.IPPool {
+	pool := &crdv1a2.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: crdv1a2.IPPoolSpec{
+			IPRanges: []crdv1a2.SubnetIPRange{
+				{
+					IPRange:    ipRange,
+					SubnetInfo: crdv1a2.SubnetInfo{Gateway: "10.2.0.1", PrefixLength: 24},
+				},
+			},
+		},
+	}
+	for i, ip := range allocatedIPs {
+		pool.Status.IPAddresses = append(pool.Status.IPAddresses, crdv1a2.IPAddressState{
+			IPAddress: ip,
+			Phase:     crdv1a2.IPAddressPhaseAllocated,
+			Owner: crdv1a2.IPAddressOwner{Pod: &crdv1a2.PodOwner{
+				Name:        fmt.Sprintf("allocated-pod-%d", i),
+				Namespace:   testPoolNamespace,
+				ContainerID: fmt.Sprintf("allocated-container-%d", i),
+			}},
+		})
+	}
+	return pool
+}
+
+func newTestArgs(namespace, podName, containerID string) *invoke.Args {
+	return &invoke.Args{
+		ContainerID:   containerID,
+		PluginArgsStr: fmt.Sprintf("IgnoreUnknown=1;K8S_POD_NAMESPACE=%s;K8S_POD_NAME=%s;K8S_POD_INFRA_CONTAINER_ID=%s", namespace, podName, containerID),
+	}
+}
+
+// newUnsyncedTestAntreaIPAM returns an initialized AntreaIPAM, and a function which starts its informers and waits for
+// them to be synced.
+func newUnsyncedTestAntreaIPAM(t *testing.T, pool *crdv1a2.IPPool) (*AntreaIPAM, *fakeversioned.Clientset, func()) {
+	k8sClient := fake.NewSimpleClientset(newTestNamespace(testPoolNamespace, testPool), newTestNamespace(testOtherNamespace, ""))
+	crdClient := fakeversioned.NewSimpleClientset(pool)
+	informerFactory := informers.NewSharedInformerFactory(k8sClient, 0)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	d := &AntreaIPAM{}
+	d.init(testNodeName, crdClient, informerFactory.Core().V1().Namespaces(), crdInformerFactory.Crd().V1alpha2().IPPools(), stopCh)
+	startInformers := func() {
+		informerFactory.Start(stopCh)
+		crdInformerFactory.Start(stopCh)
+		informerFactory.WaitForCacheSync(stopCh)
+		crdInformerFactory.WaitForCacheSync(stopCh)
+	}
+	return d, crdClient, startInformers
+}
+
+func newTestAntreaIPAM(t *testing.T, pool *crdv1a2.IPPool) (*AntreaIPAM, *fakeversioned.Clientset) {
+	d, crdClient, startInformers := newUnsyncedTestAntreaIPAM(t, pool)
+	startInformers()
+	return d, crdClient
+}
+
+func getTestIPPool(t *testing.T, crdClient *fakeversioned.Clientset) *crdv1a2.IPPool {
+	pool, err := crdClient.CrdV1alpha2().IPPools().Get(context.TODO(), testPool, metav1.GetOptions{})
+	require.NoError(t, err)
+	return pool
+}
+
+func TestAntreaIPAMAdd(t *testing.T) {
+	d, crdClient := newTestAntreaIPAM(t, newTestIPPool(testPool, crdv1a2.IPRange{CIDR: "10.2.0.0/24"}, "10.2.0.2"))
+
+	args := newTestArgs(testPoolNamespace, "pod1", "container1")
+	owns, result, err := d.Add(args, nil)
+	require.NoError(t, err)
+	assert.True(t, owns)
+	require.Len(t, result.IPs, 1)
+	assert.Equal(t, "10.2.0.3/24", result.IPs[0].Address.String())
+	assert.Equal(t, net.ParseIP("10.2.0.1"), result.IPs[0].Gateway)
+	pool := getTestIPPool(t, crdClient)
+	require.Len(t, pool.Status.IPAddresses, 2)
+	assert.Equal(t, crdv1a2.IPAddressState{
+		IPAddress: "10.2.0.3",
+		Phase:     crdv1a2.IPAddressPhaseAllocated,
+		Owner:     crdv1a2.IPAddressOwner{Pod: &crdv1a2.PodOwner{Name: "pod1", Namespace: testPoolNamespace, ContainerID: "container1"}},
+		NodeName:  testNodeName,
+	}, pool.Status.IPAddresses[1])
+
+	// Adding the same container again returns the same IP.
+	_, result, err = d.Add(args, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.2.0.3/24", result.IPs[0].Address.String())
+	assert.Len(t, getTestIPPool(t, crdClient).Status.IPAddresses, 2)
+
+	// The requests of the Pods in the other Namespaces are not owned by the driver.
+	owns, _, err = d.Add(newTestArgs(testOtherNamespace, "pod2", "container2"), nil)
+	require.NoError(t, err)
+	assert.False(t, owns)
+	assert.Len(t, getTestIPPool(t, crdClient).Status.IPAddresses, 2)
+}
+
+func TestAntreaIPAMAddExhausted(t *testing.T) {
+	d, crdClient := newTestAntreaIPAM(t, newTestIPPool(testPool, crdv1a2.IPRange{Start: "10.2.0.1", End: "10.2.0.2"}, "10.2.0.2"))
+
+	// 10.2.0.1 is the gateway and 10.2.0.2 is already allocated.
+	owns, _, err := d.Add(newTestArgs(testPoolNamespace, "pod1", "container1"), nil)
+	assert.True(t, owns)
+	assert.Error(t, err)
+	assert.Len(t, getTestIPPool(t, crdClient).Status.IPAddresses, 1)
+}
+
+func TestAntreaIPAMDelAndCheck(t *testing.T) {
+	d, crdClient := newTestAntreaIPAM(t, newTestIPPool(testPool, crdv1a2.IPRange{CIDR: "10.2.0.0/24"}, "10.2.0.2", "10.2.0.3"))
+
+	args := newTestArgs(testPoolNamespace, "allocated-pod-0", "allocated-container-0")
+	owns, err := d.Check(args, nil)
+	assert.NoError(t, err)
+	assert.True(t, owns)
+	owns, err = d.Del(args, nil)
+	require.NoError(t, err)
+	assert.True(t, owns)
+	pool := getTestIPPool(t, crdClient)
+	require.Len(t, pool.Status.IPAddresses, 1)
+	assert.Equal(t, "10.2.0.3", pool.Status.IPAddresses[0].IPAddress)
+
+	// The released IP can be allocated again.
+	_, result, err := d.Add(newTestArgs(testPoolNamespace, "pod1", "container1"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.2.0.2/24", result.IPs[0].Address.String())
+
+	// The requests of the containers without an IP from an IPPool are not owned by the driver.
+	otherArgs := newTestArgs(testOtherNamespace, "pod2", "container2")
+	owns, err = d.Check(otherArgs, nil)
+	assert.NoError(t, err)
+	assert.False(t, owns)
+	owns, err = d.Del(otherArgs, nil)
+	assert.NoError(t, err)
+	assert.False(t, owns)
+}
+
+func TestAntreaIPAMWaitForCacheSync(t *testing.T) {
+	d, _, startInformers := newUnsyncedTestAntreaIPAM(t, newTestIPPool(testPool, crdv1a2.IPRange{CIDR: "10.2.0.0/24"}, "10.2.0.2"))
+
+	// The requests received before the caches are synced wait for them, instead of failing.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		startInformers()
+	}()
+	owns, result, err := d.Add(newTestArgs(testPoolNamespace, "pod1", "container1"), nil)
+	require.NoError(t, err)
+	assert.True(t, owns)
+	assert.Equal(t, "10.2.0.3/24", result.IPs[0].Address.String())
+	owns, _, err = d.Add(newTestArgs(testOtherNamespace, "pod2", "container2"), nil)
+	assert.NoError(t, err)
+	assert.False(t, owns)
+}
+
+func TestAntreaIPAMCacheSyncTimeout(t *testing.T) {
+	defer func(timeout time.Duration) {
+		cacheSyncTimeout = timeout
+	}(cacheSyncTimeout)
+	cacheSyncTimeout = 100 * time.Millisecond
+	d, _, _ := newUnsyncedTestAntreaIPAM(t, newTestIPPool(testPool, crdv1a2.IPRange{CIDR: "10.2.0.0/24"}))
+
+	owns, _, err := d.Add(newTestArgs(testOtherNamespace, "pod1", "container1"), nil)
+	assert.True(t, owns)
+	assert.Error(t, err)
+}
+
+func TestAntreaIPAMNotInitialized(t *testing.T) {
+	d := &AntreaIPAM{}
+	args := newTestArgs(testPoolNamespace, "pod1", "container1")
+	owns, _, err := d.Add(args, nil)
+	assert.NoError(t, err)
+	assert.False(t, owns)
+	owns, err = d.Del(args, nil)
+	assert.NoError(t, err)
+	assert.False(t, owns)
+}
+
+func TestExecIPAMDriverOwnership(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	ipamType := "test-ownership"
+	driver1 := ipamtest.NewMockIPAMDriver(controller)
+	driver2 := ipamtest.NewMockIPAMDriver(controller)
+	require.NoError(t, RegisterIPAMDriver(ipamType, driver1))
+	require.NoError(t, RegisterIPAMDriver(ipamType, driver2))
+	assert.Error(t, RegisterIPAMDriver(ipamType, driver1))
+
+	cniArgs := &cnipb.CniCmdArgs{ContainerId: "container1"}
+	// The request is handled by the first driver which owns it.
+	result := &current.Result{}
+	driver1.EXPECT().Add(gomock.Any(), gomock.Any()).Return(false, nil, nil)
+	driver2.EXPECT().Add(gomock.Any(), gomock.Any()).Return(true, result, nil)
+	actualResult, err := ExecIPAMAdd(cniArgs, ipamType, "container1")
+	require.NoError(t, err)
+	assert.Equal(t, result, actualResult)
+
+	driver1.EXPECT().Check(gomock.Any(), gomock.Any()).Return(true, fmt.Errorf("check error"))
+	assert.Error(t, ExecIPAMCheck(cniArgs, ipamType))
+
+	// The request fails if no driver owns it.
+	driver1.EXPECT().Del(gomock.Any(), gomock.Any()).Return(false, nil)
+	driver2.EXPECT().Del(gomock.Any(), gomock.Any()).Return(false, nil)
+	assert.Error(t, ExecIPAMDelete(cniArgs, ipamType, "container1"))
+}
@@ -34,7 +34,8 @@ type IPAMDelegator struct {
 	pluginType string
 }
 
-func (d *IPAMDelegator) Add(args *invoke.Args, networkConfig []byte) (*current.Result, error) {
+// IPAMDelegator owns all the requests of its type, which it delegates to the IPAM plugin of the same name.
+func (d *IPAMDelegator) Add(args *invoke.Args, networkConfig []byte) (bool, *current.Result, error) {
 	var success = false
 	defer func() {
 		if !success {
@@ -48,32 +49,32 @@ func (d *IPAMDelegator) Add(args *invoke.Args, networkConfig []byte) (*current.R
 	args.Command = "ADD"
 	r, err := delegateWithResult(d.pluginType, networkConfig, args)
 	if err != nil {
-		return nil, err
+		return true, nil, err
 	}
 
 	ipamResult, err := current.NewResultFromResult(r)
 	if err != nil {
-		return nil, err
+		return true, nil, err
 	}
 	success = true
-	return ipamResult, nil
+	return true, ipamResult, nil
 }
 
-func (d *IPAMDelegator) Del(args *invoke.Args, networkConfig []byte) error {
+func (d *IPAMDelegator) Del(args *invoke.Args, networkConfig []byte) (bool, error) {
 	args.Command = "DEL"
 	if err := delegateNoResult(d.pluginType, networkConfig, args); err != nil {
-		return err
+		return true, err
 	}
 
-	return nil
+	return true, nil
 }
 
-func (d *IPAMDelegator) Check(args *invoke.Args, networkConfig []byte) error {
+func (d *IPAMDelegator) Check(args *invoke.Args, networkConfig []byte) (bool, error) {
 	args.Command = "CHECK"
 	if err := delegateNoResult(d.pluginType, networkConfig, args); err != nil {
-		return err
+		return true, err
 	}
-	return nil
+	return true, nil
 }
 
 var defaultExec = &invoke.DefaultExec{
@@ -121,6 +122,11 @@ func delegateNoResult(delegatePlugin string, networkConfig []byte, args *invoke.
 }
 
 func init() {
+	// The Antrea IPAM driver must come before the host-local driver, which owns all the requests. It doesn't own any
+	// request until InitializeAntreaIPAMDriver is called.
+	if err := RegisterIPAMDriver(ipamHostLocal, antreaIPAMDriver); err != nil {
+		klog.Errorf("Failed to register Antrea IPAM driver on type %s", ipamHostLocal)
+	}
 	if err := RegisterIPAMDriver(ipamHostLocal, &IPAMDelegator{pluginType: ipamHostLocal}); err != nil {
 		klog.Errorf("Failed to register IPAM plugin on type %s", ipamHostLocal)
 	}
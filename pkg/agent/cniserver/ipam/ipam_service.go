@@ -24,7 +24,8 @@ import (
 	cnipb "antrea.io/antrea/pkg/apis/cni/v1beta1"
 )
 
-var ipamDrivers map[string]IPAMDriver
+// ipamDrivers are the registered IPAM drivers of each IPAM type, in registration order.
+var ipamDrivers map[string][]IPAMDriver
 
 type Range struct {
 	Subnet  string `json:"subnet"`
@@ -38,31 +39,38 @@ type IPAMConfig struct {
 	Ranges []RangeSet `json:"ranges,omitempty"`
 }
 
+// IPAMDriver handles the IPAM requests of an IPAM type. Several drivers can be registered for the same type, in which
+// case each request is handled by the first driver which owns it. Each method returns whether the driver owns the
+// request, its other return values are ignored if it doesn't.
 type IPAMDriver interface {
-	Add(args *invoke.Args, networkConfig []byte) (*current.Result, error)
-	Del(args *invoke.Args, networkConfig []byte) error
-	Check(args *invoke.Args, networkConfig []byte) error
+	Add(args *invoke.Args, networkConfig []byte) (bool, *current.Result, error)
+	Del(args *invoke.Args, networkConfig []byte) (bool, error)
+	Check(args *invoke.Args, networkConfig []byte) (bool, error)
 }
 
 var ipamResults = sync.Map{}
 
+// RegisterIPAMDriver registers the driver for the IPAM type, after the drivers already registered for the type.
 func RegisterIPAMDriver(ipamType string, ipamDriver IPAMDriver) error {
 	if ipamDrivers == nil {
-		ipamDrivers = make(map[string]IPAMDriver)
+		ipamDrivers = make(map[string][]IPAMDriver)
 	}
-	if _, existed := ipamDrivers[ipamType]; existed {
-		return fmt.Errorf("Already registered IPAM with type %s", ipamType)
+	for _, driver := range ipamDrivers[ipamType] {
+		if driver == ipamDriver {
+			return fmt.Errorf("Already registered IPAM driver with type %s", ipamType)
+		}
 	}
-	ipamDrivers[ipamType] = ipamDriver
+	ipamDrivers[ipamType] = append(ipamDrivers[ipamType], ipamDriver)
 	return nil
 }
 
 func argsFromEnv(cniArgs *cnipb.CniCmdArgs) *invoke.Args {
 	return &invoke.Args{
-		ContainerID: cniArgs.ContainerId,
-		NetNS:       cniArgs.Netns,
-		IfName:      cniArgs.Ifname,
-		Path:        cniArgs.Path,
+		ContainerID:   cniArgs.ContainerId,
+		NetNS:         cniArgs.Netns,
+		IfName:        cniArgs.Ifname,
+		PluginArgsStr: cniArgs.Args,
+		Path:          cniArgs.Path,
 	}
 }
 
@@ -79,30 +87,45 @@ func ExecIPAMAdd(cniArgs *cnipb.CniCmdArgs, ipamType string, resultKey string) (
 	}
 
 	args := argsFromEnv(cniArgs)
-	driver := ipamDrivers[ipamType]
-	result, err := driver.Add(args, cniArgs.NetworkConfiguration)
-	if err != nil {
-		return nil, err
+	for _, driver := range ipamDrivers[ipamType] {
+		owns, result, err := driver.Add(args, cniArgs.NetworkConfiguration)
+		if !owns {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ipamResults.Store(resultKey, result)
+		return result, nil
 	}
-	ipamResults.Store(resultKey, result)
-	return result, nil
+	return nil, fmt.Errorf("no IPAM driver of type %s owns the ADD request", ipamType)
 }
 
 func ExecIPAMDelete(cniArgs *cnipb.CniCmdArgs, ipamType string, resultKey string) error {
 	args := argsFromEnv(cniArgs)
-	driver := ipamDrivers[ipamType]
-	err := driver.Del(args, cniArgs.NetworkConfiguration)
-	if err != nil {
-		return err
+	for _, driver := range ipamDrivers[ipamType] {
+		owns, err := driver.Del(args, cniArgs.NetworkConfiguration)
+		if !owns {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		ipamResults.Delete(resultKey)
+		return nil
 	}
-	ipamResults.Delete(resultKey)
-	return nil
+	return fmt.Errorf("no IPAM driver of type %s owns the DEL request", ipamType)
 }
 
 func ExecIPAMCheck(cniArgs *cnipb.CniCmdArgs, ipamType string) error {
 	args := argsFromEnv(cniArgs)
-	driver := ipamDrivers[ipamType]
-	return driver.Check(args, cniArgs.NetworkConfiguration)
+	for _, driver := range ipamDrivers[ipamType] {
+		owns, err := driver.Check(args, cniArgs.NetworkConfiguration)
+		if owns {
+			return err
+		}
+	}
+	return fmt.Errorf("no IPAM driver of type %s owns the CHECK request", ipamType)
 }
 
 func GetIPFromCache(resultKey string) (*current.Result, bool) {
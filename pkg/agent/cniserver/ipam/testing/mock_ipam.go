@@ -50,12 +50,13 @@ func (m *MockIPAMDriver) EXPECT() *MockIPAMDriverMockRecorder {
 }
 
 // Add mocks base method
-func (m *MockIPAMDriver) Add(arg0 *invoke.Args, arg1 []byte) (*current.Result, error) {
+func (m *MockIPAMDriver) Add(arg0 *invoke.Args, arg1 []byte) (bool, *current.Result, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Add", arg0, arg1)
-	ret0, _ := ret[0].(*current.Result)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(*current.Result)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Add indicates an expected call of Add
@@ -65,11 +66,12 @@ func (mr *MockIPAMDriverMockRecorder) Add(arg0, arg1 interface{}) *gomock.Call {
 }
 
 // Check mocks base method
-func (m *MockIPAMDriver) Check(arg0 *invoke.Args, arg1 []byte) error {
+func (m *MockIPAMDriver) Check(arg0 *invoke.Args, arg1 []byte) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Check", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Check indicates an expected call of Check
@@ -79,11 +81,12 @@ func (mr *MockIPAMDriverMockRecorder) Check(arg0, arg1 interface{}) *gomock.Call
 }
 
 // Del mocks base method
-func (m *MockIPAMDriver) Del(arg0 *invoke.Args, arg1 []byte) error {
+func (m *MockIPAMDriver) Del(arg0 *invoke.Args, arg1 []byte) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Del", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Del indicates an expected call of Del
@@ -33,6 +33,15 @@ const (
 	BridgeOFPort = 0xfffffffe
 )
 
+const (
+	// L7NetworkPolicyTargetInterfaceName is the name of the OVS internal port to which the packets of the
+	// connections allowed by the Antrea-native policy rules with L7 protocols are redirected.
+	L7NetworkPolicyTargetInterfaceName = "antrea-l7-tap0"
+	// L7NetworkPolicyReturnInterfaceName is the name of the OVS internal port through which the L7 engine sends
+	// the accepted packets back to the OVS bridge.
+	L7NetworkPolicyReturnInterfaceName = "antrea-l7-tap1"
+)
+
 const (
 	VXLANOverhead  = 50
 	GeneveOverhead = 50
@@ -43,6 +52,15 @@ const (
 	IPv6ExtraOverhead = 20
 )
 
+var (
+	// VirtualNodePortDNATIPv4 is the virtual IPv4 address to which the NodePort Service traffic received by the
+	// Node is DNAT'd in the host network, before being steered into OVS through the host gateway interface.
+	VirtualNodePortDNATIPv4 = net.ParseIP("169.254.169.110")
+	// VirtualNodePortDNATIPv6 is the virtual IPv6 address to which the NodePort Service traffic received by the
+	// Node is DNAT'd in the host network, before being steered into OVS through the host gateway interface.
+	VirtualNodePortDNATIPv6 = net.ParseIP("fc01::aabb:ccdd:eeff")
+)
+
 type GatewayConfig struct {
 	// Name is the name of host gateway, e.g. antrea-gw0.
 	Name string
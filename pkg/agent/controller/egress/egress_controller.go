@@ -17,6 +17,7 @@ package egress
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"net"
 	"reflect"
 	"strings"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
@@ -39,14 +41,19 @@ import (
 	"antrea.io/antrea/pkg/agent/controller/egress/ipassigner"
 	"antrea.io/antrea/pkg/agent/interfacestore"
 	"antrea.io/antrea/pkg/agent/memberlist"
+	agentmetrics "antrea.io/antrea/pkg/agent/metrics"
 	"antrea.io/antrea/pkg/agent/openflow"
 	"antrea.io/antrea/pkg/agent/route"
 	cpv1b2 "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
 	crdv1a2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+	crdv1a3 "antrea.io/antrea/pkg/apis/crd/v1alpha3"
 	clientsetversioned "antrea.io/antrea/pkg/client/clientset/versioned"
 	crdinformers "antrea.io/antrea/pkg/client/informers/externalversions/crd/v1alpha2"
+	crdv1a3informers "antrea.io/antrea/pkg/client/informers/externalversions/crd/v1alpha3"
 	crdlisters "antrea.io/antrea/pkg/client/listers/crd/v1alpha2"
+	crdv1a3listers "antrea.io/antrea/pkg/client/listers/crd/v1alpha3"
 	"antrea.io/antrea/pkg/controller/metrics"
+	"antrea.io/antrea/pkg/util/bandwidth"
 	"antrea.io/antrea/pkg/util/k8s"
 )
 
@@ -63,9 +70,15 @@ const (
 	minEgressMark = 1
 	// maxEgressMark is the maximum mark of Egress IPs can be configured on a Node.
 	maxEgressMark = 255
+	// maxEgressFailoverHistory is the maximum number of failovers recorded in the status of an Egress.
+	maxEgressFailoverHistory = 5
+	// egressStatsInterval is the interval at which the packets dropped by the bandwidth limits of the local Egress
+	// IPs are collected.
+	egressStatsInterval = 60 * time.Second
 
 	egressIPIndex       = "egressIP"
 	externalIPPoolIndex = "externalIPPool"
+	clusterGroupIndex   = "clusterGroup"
 
 	// egressDummyDevice is the dummy device that holds the Egress IPs configured to the system by antrea-agent.
 	egressDummyDevice = "antrea-egress0"
@@ -75,11 +88,19 @@ var emptyWatch = watch.NewEmptyWatch()
 
 // egressState keeps the actual state of an Egress that has been realized.
 type egressState struct {
-	// The actual egress IP of the Egress. If it's different from the desired IP, there is an update to EgressIP, and we
-	// need to remove previously installed flows.
-	egressIP string
-	// The actual datapath mark of this Egress. Used to check if the mark changes since last process.
-	mark uint32
+	// The actual egress IPs of the Egress, which has a single IP unless EgressIPs is set. If they're different from the
+	// desired IPs, there is an update to EgressIP or EgressIPs, and we need to remove previously installed flows.
+	egressIPs []string
+	// The actual datapath marks of the Egress IPs, in the same order. Used to check if they change since last process.
+	marks []uint32
+	// Whether the Pod flows of each Egress IP send the packets to the meter of the IP, in the same order. Used to check
+	// if they change since last process.
+	metered []bool
+	// Whether the Egress only applies to the traffic to specific destinations. Such an Egress doesn't compete with
+	// the other Egresses applying to the same Pods, so the Pods are not bound to it.
+	restricted bool
+	// The actual destination CIDRs of the Egress if it's restricted. Used to check if they change since last process.
+	destinations sets.String
 	// The actual openflow ports for which we have installed SNAT rules. Used to identify stale openflow ports when
 	// updating or deleting an Egress.
 	ofPorts sets.Int32
@@ -99,6 +120,18 @@ type egressIPState struct {
 	flowsInstalled bool
 	// Whether its iptables rule has been installed.
 	ruleInstalled bool
+	// The bandwidth limit enforced by the meter of this Egress IP. nil if no meter has been installed.
+	meterBandwidth *meterBandwidth
+	// Whether its flows send the packets to the meter.
+	flowsMetered bool
+	// The number of packets dropped by the meter when the stats were last collected.
+	droppedPackets uint64
+}
+
+// meterBandwidth is the bandwidth limit of a meter, the rate is in kbps and the burst is in kbits.
+type meterBandwidth struct {
+	rate  uint32
+	burst uint32
 }
 
 // egressBinding keeps the Egresses applying to a Pod.
@@ -117,7 +150,10 @@ type EgressController struct {
 	egressInformer     cache.SharedIndexInformer
 	egressLister       crdlisters.EgressLister
 	egressListerSynced cache.InformerSynced
-	queue              workqueue.RateLimitingInterface
+	// clusterGroupLister is used to resolve the destinations of Egresses referring to ClusterGroups.
+	clusterGroupLister       crdv1a3listers.ClusterGroupLister
+	clusterGroupListerSynced cache.InformerSynced
+	queue                    workqueue.RateLimitingInterface
 
 	// Use an interface for IP detector to enable testing.
 	localIPDetector LocalIPDetector
@@ -155,25 +191,29 @@ func NewEgressController(
 	egressInformer crdinformers.EgressInformer,
 	nodeInformer coreinformers.NodeInformer,
 	externalIPPoolInformer crdinformers.ExternalIPPoolInformer,
+	clusterGroupInformer crdv1a3informers.ClusterGroupInformer,
+	healthCheckConfig *memberlist.HealthCheckConfig,
 ) (*EgressController, error) {
 	localIPDetector := NewLocalIPDetector()
 	c := &EgressController{
-		ofClient:             ofClient,
-		routeClient:          routeClient,
-		antreaClientProvider: antreaClientGetter,
-		crdClient:            crdClient,
-		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "egressgroup"),
-		egressInformer:       egressInformer.Informer(),
-		egressLister:         egressInformer.Lister(),
-		egressListerSynced:   egressInformer.Informer().HasSynced,
-		nodeName:             nodeName,
-		ifaceStore:           ifaceStore,
-		egressGroups:         map[string]sets.String{},
-		egressStates:         map[string]*egressState{},
-		egressIPStates:       map[string]*egressIPState{},
-		egressBindings:       map[string]*egressBinding{},
-		localIPDetector:      localIPDetector,
-		idAllocator:          newIDAllocator(minEgressMark, maxEgressMark),
+		ofClient:                 ofClient,
+		routeClient:              routeClient,
+		antreaClientProvider:     antreaClientGetter,
+		crdClient:                crdClient,
+		queue:                    workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "egressgroup"),
+		egressInformer:           egressInformer.Informer(),
+		egressLister:             egressInformer.Lister(),
+		egressListerSynced:       egressInformer.Informer().HasSynced,
+		clusterGroupLister:       clusterGroupInformer.Lister(),
+		clusterGroupListerSynced: clusterGroupInformer.Informer().HasSynced,
+		nodeName:                 nodeName,
+		ifaceStore:               ifaceStore,
+		egressGroups:             map[string]sets.String{},
+		egressStates:             map[string]*egressState{},
+		egressIPStates:           map[string]*egressIPState{},
+		egressBindings:           map[string]*egressBinding{},
+		localIPDetector:          localIPDetector,
+		idAllocator:              newIDAllocator(minEgressMark, maxEgressMark),
 	}
 	ipAssigner, err := ipassigner.NewIPAssigner(nodeIP, egressDummyDevice)
 	if err != nil {
@@ -181,7 +221,7 @@ func NewEgressController(
 	}
 	c.ipAssigner = ipAssigner
 
-	cluster, err := memberlist.NewCluster(clusterPort, nodeIP, nodeName, nodeInformer, externalIPPoolInformer)
+	cluster, err := memberlist.NewCluster(clusterPort, nodeIP, nodeName, nodeInformer, externalIPPoolInformer, healthCheckConfig)
 	if err != nil {
 		return nil, fmt.Errorf("initializing memberlist cluster failed: %v", err)
 	}
@@ -192,7 +232,7 @@ func NewEgressController(
 		if !ok {
 			return nil, fmt.Errorf("obj is not Egress: %+v", obj)
 		}
-		return []string{egress.Spec.EgressIP}, nil
+		return getEgressIPs(egress), nil
 	}})
 	// externalIPPoolIndex will be used to get all Egresses associated with a given ExternalIPPool.
 	c.egressInformer.AddIndexers(cache.Indexers{externalIPPoolIndex: func(obj interface{}) (strings []string, e error) {
@@ -205,6 +245,20 @@ func NewEgressController(
 		}
 		return []string{egress.Spec.ExternalIPPool}, nil
 	}})
+	// clusterGroupIndex will be used to get all Egresses whose destinations refer to a given ClusterGroup.
+	c.egressInformer.AddIndexers(cache.Indexers{clusterGroupIndex: func(obj interface{}) ([]string, error) {
+		egress, ok := obj.(*crdv1a2.Egress)
+		if !ok {
+			return nil, fmt.Errorf("obj is not Egress: %+v", obj)
+		}
+		var groups []string
+		for _, peer := range egress.Spec.To {
+			if peer.Group != "" {
+				groups = append(groups, peer.Group)
+			}
+		}
+		return groups, nil
+	}})
 	c.egressInformer.AddEventHandlerWithResyncPeriod(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: c.enqueueEgress,
@@ -215,6 +269,16 @@ func NewEgressController(
 		},
 		resyncPeriod,
 	)
+	clusterGroupInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueueEgressesByClusterGroup,
+			UpdateFunc: func(old, cur interface{}) {
+				c.enqueueEgressesByClusterGroup(cur)
+			},
+			DeleteFunc: c.enqueueEgressesByClusterGroup,
+		},
+		resyncPeriod,
+	)
 	localIPDetector.AddEventHandler(c.onLocalIPUpdate)
 	c.cluster.AddClusterEventHandler(c.enqueueEgressesByExternalIPPool)
 	return c, nil
@@ -264,6 +328,39 @@ func (c *EgressController) enqueueEgressesByExternalIPPool(eipName string) {
 	klog.InfoS("Detected ExternalIPPool event", "ExternalIPPool", eipName, "enqueueEgressNum", len(objects))
 }
 
+// enqueueEgressesByClusterGroup enqueues all Egresses whose destinations refer to the provided ClusterGroup, directly
+// or through a parent ClusterGroup.
+func (c *EgressController) enqueueEgressesByClusterGroup(obj interface{}) {
+	group, isGroup := obj.(*crdv1a3.ClusterGroup)
+	if !isGroup {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Received unexpected object: %v", obj)
+			return
+		}
+		group, ok = deletedState.Obj.(*crdv1a3.ClusterGroup)
+		if !ok {
+			klog.Errorf("DeletedFinalStateUnknown contains non-ClusterGroup object: %v", deletedState.Obj)
+			return
+		}
+	}
+	groupNames := sets.NewString(group.Name)
+	allGroups, _ := c.clusterGroupLister.List(labels.Everything())
+	for _, parent := range allGroups {
+		for _, child := range parent.Spec.ChildGroups {
+			if string(child) == group.Name {
+				groupNames.Insert(parent.Name)
+			}
+		}
+	}
+	for groupName := range groupNames {
+		objects, _ := c.egressInformer.GetIndexer().ByIndex(clusterGroupIndex, groupName)
+		for _, object := range objects {
+			c.queue.Add(object.(*crdv1a2.Egress).Name)
+		}
+	}
+}
+
 // Run will create defaultWorkers workers (go routines) which will process the Egress events from the
 // workqueue.
 func (c *EgressController) Run(stopCh <-chan struct{}) {
@@ -274,7 +371,7 @@ func (c *EgressController) Run(stopCh <-chan struct{}) {
 
 	go c.localIPDetector.Run(stopCh)
 
-	if !cache.WaitForNamedCacheSync(controllerName, stopCh, c.egressListerSynced, c.localIPDetector.HasSynced) {
+	if !cache.WaitForNamedCacheSync(controllerName, stopCh, c.egressListerSynced, c.clusterGroupListerSynced, c.localIPDetector.HasSynced) {
 		return
 	}
 
@@ -284,6 +381,8 @@ func (c *EgressController) Run(stopCh <-chan struct{}) {
 
 	go wait.NonSlidingUntil(c.watchEgressGroup, 5*time.Second, stopCh)
 
+	go wait.Until(c.syncEgressStats, egressStatsInterval, stopCh)
+
 	for i := 0; i < defaultWorkers; i++ {
 		go wait.Until(c.worker, time.Second, stopCh)
 	}
@@ -297,9 +396,18 @@ func (c *EgressController) removeStaleEgressIPs() {
 	desiredLocalEgressIPs := sets.NewString()
 	egresses, _ := c.egressLister.List(labels.Everything())
 	for _, egress := range egresses {
-		if egress.Spec.EgressIP != "" && egress.Spec.ExternalIPPool != "" && egress.Status.EgressNode == c.nodeName {
+		if egress.Spec.ExternalIPPool == "" {
+			continue
+		}
+		if egress.Spec.EgressIP != "" && egress.Status.EgressNode == c.nodeName {
 			desiredLocalEgressIPs.Insert(egress.Spec.EgressIP)
 		}
+		egressIPs := sets.NewString(egress.Spec.EgressIPs...)
+		for _, ipStatus := range egress.Status.EgressIPs {
+			if ipStatus.Node == c.nodeName && egressIPs.Has(ipStatus.IP) {
+				desiredLocalEgressIPs.Insert(ipStatus.IP)
+			}
+		}
 	}
 	actualLocalEgressIPs := c.ipAssigner.AssignedIPs()
 	for ip := range actualLocalEgressIPs.Difference(desiredLocalEgressIPs) {
@@ -345,10 +453,13 @@ func (c *EgressController) processNextWorkItem() bool {
 
 // realizeEgressIP realizes an Egress IP. Multiple Egresses can share the same Egress IP.
 // If it's called the first time for a local Egress IP, it allocates a locally-unique mark for the IP and installs flows
-// and iptables rule for this IP and the mark.
-// If the Egress IP is changed from local to non local, it uninstalls flows and iptables rule and releases the mark.
-// The method returns the mark on success. Non local Egresses use 0 as the mark.
-func (c *EgressController) realizeEgressIP(egressName, egressIP string) (uint32, error) {
+// and iptables rule for this IP and the mark. If the Egresses sharing the IP have bandwidth limits, a meter enforcing
+// the lowest one is installed for the IP, and the flows send the packets to the meter.
+// If the Egress IP is changed from local to non local, it uninstalls flows, meter and iptables rule and releases the
+// mark.
+// The method returns the mark and whether the packets should be sent to the meter of the IP on success. Non local
+// Egresses use 0 as the mark.
+func (c *EgressController) realizeEgressIP(egressName, egressIP string) (uint32, bool, error) {
 	isLocalIP := c.localIPDetector.IsLocalIP(egressIP)
 
 	c.egressIPStatesMutex.Lock()
@@ -372,50 +483,116 @@ func (c *EgressController) realizeEgressIP(egressName, egressIP string) (uint32,
 		if ipState.mark == 0 {
 			ipState.mark, err = c.idAllocator.allocate()
 			if err != nil {
-				return 0, fmt.Errorf("error allocating mark for IP %s: %v", egressIP, err)
+				return 0, false, fmt.Errorf("error allocating mark for IP %s: %v", egressIP, err)
+			}
+		}
+		// Ensure the meter is installed before the flows referring to it.
+		desiredBandwidth := c.getDesiredBandwidth(ipState)
+		if desiredBandwidth != nil && (ipState.meterBandwidth == nil || *ipState.meterBandwidth != *desiredBandwidth) {
+			if err := c.ofClient.InstallEgressMeter(ipState.mark, desiredBandwidth.rate, desiredBandwidth.burst); err == openflow.ErrOVSMetersNotSupported {
+				klog.InfoS("Bandwidth limit of Egress IP is not enforced as OpenFlow meters are not supported by the OVS datapath", "ip", egressIP)
+			} else if err != nil {
+				return 0, false, fmt.Errorf("error installing meter for IP %s: %v", ipState.egressIP, err)
+			} else {
+				ipState.meterBandwidth = desiredBandwidth
 			}
 		}
+		metered := desiredBandwidth != nil && ipState.meterBandwidth != nil
 		// Ensure datapath is installed properly.
-		if !ipState.flowsInstalled {
-			if err := c.ofClient.InstallSNATMarkFlows(ipState.egressIP, ipState.mark); err != nil {
-				return 0, fmt.Errorf("error installing SNAT mark flows for IP %s: %v", ipState.egressIP, err)
+		if !ipState.flowsInstalled || ipState.flowsMetered != metered {
+			if err := c.ofClient.InstallSNATMarkFlows(ipState.egressIP, ipState.mark, metered); err != nil {
+				return 0, false, fmt.Errorf("error installing SNAT mark flows for IP %s: %v", ipState.egressIP, err)
+			}
+			if ipState.flowsInstalled {
+				// The Pod flows of the other Egresses sharing the IP must be updated too.
+				for name := range ipState.egressNames {
+					if name != egressName {
+						c.queue.Add(name)
+					}
+				}
 			}
 			ipState.flowsInstalled = true
+			ipState.flowsMetered = metered
 		}
 		if !ipState.ruleInstalled {
 			if err := c.routeClient.AddSNATRule(ipState.egressIP, ipState.mark); err != nil {
-				return 0, fmt.Errorf("error installing SNAT rule for IP %s: %v", ipState.egressIP, err)
+				return 0, false, fmt.Errorf("error installing SNAT rule for IP %s: %v", ipState.egressIP, err)
 			}
 			ipState.ruleInstalled = true
 		}
+		// Ensure the meter is uninstalled after the flows no longer refer to it.
+		if !metered {
+			if err := c.uninstallEgressMeter(ipState); err != nil {
+				return 0, false, err
+			}
+		}
 	} else {
 		// Ensure datapath is uninstalled properly.
 		if ipState.ruleInstalled {
 			if err := c.routeClient.DeleteSNATRule(ipState.mark); err != nil {
-				return 0, fmt.Errorf("error uninstalling SNAT rule for IP %s: %v", ipState.egressIP, err)
+				return 0, false, fmt.Errorf("error uninstalling SNAT rule for IP %s: %v", ipState.egressIP, err)
 			}
 			ipState.ruleInstalled = false
 		}
 		if ipState.flowsInstalled {
 			if err := c.ofClient.UninstallSNATMarkFlows(ipState.mark); err != nil {
-				return 0, fmt.Errorf("error uninstalling SNAT mark flows for IP %s: %v", ipState.egressIP, err)
+				return 0, false, fmt.Errorf("error uninstalling SNAT mark flows for IP %s: %v", ipState.egressIP, err)
 			}
 			ipState.flowsInstalled = false
+			ipState.flowsMetered = false
+		}
+		if err := c.uninstallEgressMeter(ipState); err != nil {
+			return 0, false, err
 		}
 		if ipState.mark != 0 {
 			err := c.idAllocator.release(ipState.mark)
 			if err != nil {
-				return 0, fmt.Errorf("error releasing mark for IP %s: %v", egressIP, err)
+				return 0, false, fmt.Errorf("error releasing mark for IP %s: %v", egressIP, err)
 			}
 			ipState.mark = 0
 		}
 	}
-	return ipState.mark, nil
+	return ipState.mark, ipState.flowsMetered, nil
+}
+
+// getDesiredBandwidth returns the lowest bandwidth limit of the Egresses sharing the Egress IP, nil if none of them
+// has a valid bandwidth limit.
+func (c *EgressController) getDesiredBandwidth(ipState *egressIPState) *meterBandwidth {
+	var desired *meterBandwidth
+	for name := range ipState.egressNames {
+		egress, err := c.egressLister.Get(name)
+		if err != nil || egress.Spec.Bandwidth == nil {
+			continue
+		}
+		rate, burst, err := bandwidth.Parse(egress.Spec.Bandwidth)
+		if err != nil {
+			klog.ErrorS(err, "Invalid bandwidth limit of Egress", "egress", name)
+			continue
+		}
+		if desired == nil || rate < desired.rate {
+			desired = &meterBandwidth{rate: rate, burst: burst}
+		}
+	}
+	return desired
+}
+
+// uninstallEgressMeter uninstalls the meter of the Egress IP if it has been installed.
+func (c *EgressController) uninstallEgressMeter(ipState *egressIPState) error {
+	if ipState.meterBandwidth == nil {
+		return nil
+	}
+	if err := c.ofClient.UninstallEgressMeter(ipState.mark); err != nil {
+		return fmt.Errorf("error uninstalling meter for IP %s: %v", ipState.egressIP, err)
+	}
+	ipState.meterBandwidth = nil
+	ipState.droppedPackets = 0
+	return nil
 }
 
 // unrealizeEgressIP unrealizes an Egress IP, reverts what realizeEgressIP does.
 // For a local Egress IP, only when the last Egress unrealizes the Egress IP, it will releases the IP's mark and
-// uninstalls corresponding flows and iptables rule.
+// uninstalls corresponding flows, meter and iptables rule. Otherwise, the remaining Egresses are resynced if the IP
+// has a meter, as the bandwidth limit of the IP may change.
 func (c *EgressController) unrealizeEgressIP(egressName, egressIP string) error {
 	c.egressIPStatesMutex.Lock()
 	defer c.egressIPStatesMutex.Unlock()
@@ -429,6 +606,11 @@ func (c *EgressController) unrealizeEgressIP(egressName, egressIP string) error
 	// release the mark if installed.
 	ipState.egressNames.Delete(egressName)
 	if len(ipState.egressNames) > 0 {
+		if ipState.meterBandwidth != nil {
+			for name := range ipState.egressNames {
+				c.queue.Add(name)
+			}
+		}
 		return nil
 	}
 	if ipState.mark != 0 {
@@ -443,6 +625,10 @@ func (c *EgressController) unrealizeEgressIP(egressName, egressIP string) error
 				return err
 			}
 			ipState.flowsInstalled = false
+			ipState.flowsMetered = false
+		}
+		if err := c.uninstallEgressMeter(ipState); err != nil {
+			return err
 		}
 		c.idAllocator.release(ipState.mark)
 	}
@@ -463,13 +649,13 @@ func (c *EgressController) deleteEgressState(egressName string) {
 	delete(c.egressStates, egressName)
 }
 
-func (c *EgressController) newEgressState(egressName string, egressIP string) *egressState {
+func (c *EgressController) newEgressState(egressName string, egressIPs []string) *egressState {
 	c.egressStatesMutex.Lock()
 	defer c.egressStatesMutex.Unlock()
 	state := &egressState{
-		egressIP: egressIP,
-		ofPorts:  sets.NewInt32(),
-		pods:     sets.NewString(),
+		egressIPs: egressIPs,
+		ofPorts:   sets.NewInt32(),
+		pods:      sets.NewString(),
 	}
 	c.egressStates[egressName] = state
 	return state
@@ -521,13 +707,27 @@ func (c *EgressController) unbindPodEgress(pod, egress string) (string, bool) {
 	return "", false
 }
 
-func (c *EgressController) updateEgressStatus(egress *crdv1a2.Egress, nodeName string) error {
+// updateEgressStatus sets the owner Node of the Egress IP to nodeName. If the Egress IP was owned by another Node,
+// the failover is recorded with the provided reason.
+func (c *EgressController) updateEgressStatus(egress *crdv1a2.Egress, nodeName string, reason string) error {
 	if egress.Status.EgressNode == nodeName {
 		return nil
 	}
-	klog.V(2).InfoS("Updating Egress status", "Egress", egress.Name, "oldNode", egress.Status.EgressNode, "newNode", nodeName)
+	klog.V(2).InfoS("Updating Egress status", "Egress", egress.Name, "oldNode", egress.Status.EgressNode, "newNode", nodeName, "reason", reason)
 	toUpdate := egress.DeepCopy()
 	toUpdate.Status.EgressNode = nodeName
+	if egress.Status.EgressNode != "" {
+		toUpdate.Status.Reason = reason
+		toUpdate.Status.FailoverHistory = append(toUpdate.Status.FailoverHistory, crdv1a2.EgressFailover{
+			FromNode: egress.Status.EgressNode,
+			ToNode:   nodeName,
+			Reason:   reason,
+			Time:     metav1.Now(),
+		})
+		if len(toUpdate.Status.FailoverHistory) > maxEgressFailoverHistory {
+			toUpdate.Status.FailoverHistory = toUpdate.Status.FailoverHistory[len(toUpdate.Status.FailoverHistory)-maxEgressFailoverHistory:]
+		}
+	}
 	if _, err := c.crdClient.CrdV1alpha2().Egresses().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{}); err != nil {
 		return fmt.Errorf("updating Egress %s status error: %v", egress.Name, err)
 	}
@@ -559,55 +759,72 @@ func (c *EgressController) syncEgress(egressName string) error {
 		return err
 	}
 
+	egressIPs := getEgressIPs(egress)
 	eState, exist := c.getEgressState(egressName)
-	// If the EgressIP changes, uninstalls this Egress first.
-	if exist && eState.egressIP != egress.Spec.EgressIP {
+	// If the EgressIPs change, uninstalls this Egress first.
+	if exist && !reflect.DeepEqual(eState.egressIPs, egressIPs) {
 		if err := c.uninstallEgress(egressName, eState); err != nil {
 			return err
 		}
 		exist = false
 	}
+	// Remove the Egress IPs from the status if EgressIPs is no longer set. The IPs removed from EgressIPs are removed
+	// by assignEgressIPs.
+	if len(egress.Spec.EgressIPs) == 0 && len(egress.Status.EgressIPs) > 0 {
+		if err := c.updateEgressIPsStatus(egress, nil); err != nil {
+			return err
+		}
+	}
 	// Do not proceed if EgressIP is empty.
-	if egress.Spec.EgressIP == "" {
+	if len(egressIPs) == 0 {
 		return nil
 	}
 	if !exist {
-		eState = c.newEgressState(egressName, egress.Spec.EgressIP)
+		eState = c.newEgressState(egressName, egressIPs)
 	}
 
-	localNodeSelected, err := c.cluster.ShouldSelectEgress(egress)
-	if err != nil {
-		return err
-	}
-	if localNodeSelected {
-		// Ensure the Egress IP is assigned to the system.
-		if err := c.ipAssigner.AssignIP(egress.Spec.EgressIP); err != nil {
+	if len(egress.Spec.EgressIPs) > 0 {
+		if err := c.assignEgressIPs(egress); err != nil {
 			return err
 		}
-		if err := c.updateEgressStatus(egress, c.nodeName); err != nil {
-			return err
-		}
-	} else {
-		// Unassign the Egress IP from the local Node if it was assigned by the agent.
-		if err := c.ipAssigner.UnassignIP(egress.Spec.EgressIP); err != nil {
+	} else if err := c.assignEgressIP(egress); err != nil {
+		return err
+	}
+
+	// Realize the latest EgressIPs and get the desired marks.
+	marks := make([]uint32, len(egressIPs))
+	metered := make([]bool, len(egressIPs))
+	for i, ip := range egressIPs {
+		var err error
+		marks[i], metered[i], err = c.realizeEgressIP(egressName, ip)
+		if err != nil {
 			return err
 		}
 	}
 
-	// Realize the latest EgressIP and get the desired mark.
-	mark, err := c.realizeEgressIP(egressName, egress.Spec.EgressIP)
-	if err != nil {
-		return err
+	restricted := len(egress.Spec.To) > 0
+	var destinations sets.String
+	if restricted {
+		destinations = c.getEgressDestinations(egress)
 	}
 
 	// If the mark changes, uninstall all of the Egress's Pod flows first, then installs them with new mark.
-	// It could happen when the Egress IP is added to or removed from the Node.
-	if eState.mark != mark {
+	// It could happen when the Egress IP is added to or removed from the Node. The same applies when the bandwidth
+	// limit of the Egress IP is added or removed, or when the destinations of the Egress change.
+	if !reflect.DeepEqual(eState.marks, marks) || !reflect.DeepEqual(eState.metered, metered) || eState.restricted != restricted || !eState.destinations.Equal(destinations) {
 		// Uninstall all of its Pod flows.
 		if err := c.uninstallPodFlows(egressName, eState, eState.ofPorts, eState.pods); err != nil {
 			return err
 		}
-		eState.mark = mark
+		eState.marks = marks
+		eState.metered = metered
+		eState.restricted = restricted
+		eState.destinations = destinations
+	}
+	destinationCIDRs := make([]net.IPNet, 0, len(destinations))
+	for _, cidr := range destinations.List() {
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		destinationCIDRs = append(destinationCIDRs, *ipNet)
 	}
 
 	// Copy the previous ofPorts and Pods. They will be used to identify stale ofPorts and Pods.
@@ -625,14 +842,14 @@ func (c *EgressController) syncEgress(egressName string) error {
 		return pods.Union(nil)
 	}()
 
-	egressIP := net.ParseIP(eState.egressIP)
 	// Install SNAT flows for desired Pods.
 	for pod := range pods {
 		eState.pods.Insert(pod)
 		stalePods.Delete(pod)
 
-		// If the Egress is not the effective one for the Pod, do nothing.
-		if !c.bindPodEgress(pod, egressName) {
+		// If the Egress is not the effective one for the Pod, do nothing. Egresses with destinations apply to their
+		// Pods regardless of the other Egresses.
+		if !restricted && !c.bindPodEgress(pod, egressName) {
 			continue
 		}
 
@@ -650,7 +867,14 @@ func (c *EgressController) syncEgress(egressName string) error {
 			staleOFPorts.Delete(ofPort)
 			continue
 		}
-		if err := c.ofClient.InstallPodSNATFlows(uint32(ofPort), egressIP, mark); err != nil {
+		// Pods are hashed among the Egress IPs.
+		i := getPodEgressIPIndex(pod, egressIPs)
+		egressIP, mark, ipMetered := net.ParseIP(egressIPs[i]), marks[i], metered[i]
+		if restricted {
+			if err := c.ofClient.InstallPodDestinationSNATFlows(uint32(ofPort), egressName, egressIP, mark, ipMetered, destinationCIDRs); err != nil {
+				return err
+			}
+		} else if err := c.ofClient.InstallPodSNATFlows(uint32(ofPort), egressIP, mark, ipMetered); err != nil {
 			return err
 		}
 		eState.ofPorts.Insert(ofPort)
@@ -663,19 +887,261 @@ func (c *EgressController) syncEgress(egressName string) error {
 	return nil
 }
 
-func (c *EgressController) uninstallEgress(egressName string, eState *egressState) error {
-	// Uninstall all of its Pod flows.
-	if err := c.uninstallPodFlows(egressName, eState, eState.ofPorts, eState.pods); err != nil {
+// assignEgressIP assigns the EgressIP of the Egress to the local Node and updates the status of the Egress if the local
+// Node is selected to hold it, otherwise it unassigns the IP from the local Node.
+func (c *EgressController) assignEgressIP(egress *crdv1a2.Egress) error {
+	localNodeSelected, err := c.cluster.ShouldSelectEgress(egress)
+	if err != nil {
 		return err
 	}
-	// Release the EgressIP's mark if the Egress is the last one referring to it.
-	if err := c.unrealizeEgressIP(egressName, eState.egressIP); err != nil {
-		return err
+	if localNodeSelected {
+		// Ensure the Egress IP is assigned to the system.
+		if err := c.ipAssigner.AssignIP(egress.Spec.EgressIP); err != nil {
+			return err
+		}
+		var reason string
+		if egress.Status.EgressNode != "" && egress.Status.EgressNode != c.nodeName {
+			reason = c.cluster.NodeUnavailableReason(egress.Spec.ExternalIPPool, egress.Status.EgressNode)
+		}
+		return c.updateEgressStatus(egress, c.nodeName, reason)
 	}
 	// Unassign the Egress IP from the local Node if it was assigned by the agent.
-	if err := c.ipAssigner.UnassignIP(eState.egressIP); err != nil {
+	return c.ipAssigner.UnassignIP(egress.Spec.EgressIP)
+}
+
+// assignEgressIPs is the counterpart of assignEgressIP for the Egresses with multiple Egress IPs. Each IP is assigned
+// to the Node selected for it, and the status of the Egress records the IPs held by the local Node.
+func (c *EgressController) assignEgressIPs(egress *crdv1a2.Egress) error {
+	localIPs := sets.NewString()
+	for _, ip := range egress.Spec.EgressIPs {
+		localNodeSelected, err := c.cluster.ShouldSelectEgressIP(egress.Spec.ExternalIPPool, ip)
+		if err != nil {
+			return err
+		}
+		if localNodeSelected {
+			// Ensure the Egress IP is assigned to the system.
+			if err := c.ipAssigner.AssignIP(ip); err != nil {
+				return err
+			}
+			localIPs.Insert(ip)
+		} else if err := c.ipAssigner.UnassignIP(ip); err != nil {
+			// Unassign the Egress IP from the local Node if it was assigned by the agent.
+			return err
+		}
+	}
+	if egress.Spec.ExternalIPPool == "" {
+		// The Egress IPs are not owned by any Node without ExternalIPPool, only remove the stale status if any.
+		if len(egress.Status.EgressIPs) == 0 {
+			return nil
+		}
+		localIPs = nil
+	}
+	return c.updateEgressIPsStatus(egress, localIPs)
+}
+
+// updateEgressIPsStatus sets the local Node as the owner of the provided Egress IPs in the status of the Egress, and
+// removes the local Node from the other IPs. The owners of the IPs held by other Nodes are updated by these Nodes. The
+// IPs which are no longer in EgressIPs are removed from the status.
+func (c *EgressController) updateEgressIPsStatus(egress *crdv1a2.Egress, localIPs sets.String) error {
+	getDesiredStatus := func(egress *crdv1a2.Egress) []crdv1a2.EgressIPStatus {
+		owners := map[string]string{}
+		for _, ipStatus := range egress.Status.EgressIPs {
+			owners[ipStatus.IP] = ipStatus.Node
+		}
+		var desired []crdv1a2.EgressIPStatus
+		for _, ip := range egress.Spec.EgressIPs {
+			node := owners[ip]
+			if localIPs.Has(ip) {
+				node = c.nodeName
+			} else if node == c.nodeName {
+				node = ""
+			}
+			if node != "" {
+				desired = append(desired, crdv1a2.EgressIPStatus{IP: ip, Node: node})
+			}
+		}
+		return desired
+	}
+	if desired := getDesiredStatus(egress); reflect.DeepEqual(egress.Status.EgressIPs, desired) {
+		return nil
+	}
+	// Multiple Nodes may update the status at the same time, retry with the latest Egress on conflict.
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := c.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), egress.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		desired := getDesiredStatus(latest)
+		if reflect.DeepEqual(latest.Status.EgressIPs, desired) {
+			return nil
+		}
+		toUpdate := latest.DeepCopy()
+		toUpdate.Status.EgressIPs = desired
+		_, err = c.crdClient.CrdV1alpha2().Egresses().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("updating Egress %s status error: %v", egress.Name, err)
+	}
+	klog.V(2).InfoS("Updated Egress status", "Egress", egress.Name, "localIPs", localIPs.List())
+	metrics.AntreaEgressStatusUpdates.Inc()
+	return nil
+}
+
+// getEgressIPs returns the Egress IPs of the Egress: EgressIPs if it's set, otherwise EgressIP if it's set.
+func getEgressIPs(egress *crdv1a2.Egress) []string {
+	if len(egress.Spec.EgressIPs) > 0 {
+		return egress.Spec.EgressIPs
+	}
+	if egress.Spec.EgressIP != "" {
+		return []string{egress.Spec.EgressIP}
+	}
+	return nil
+}
+
+// getPodEgressIPIndex returns the index of the Egress IP used by the Pod among the provided Egress IPs. It uses
+// rendezvous hashing: the Pod uses the Egress IP with the highest hash of the Pod and the IP. The same Pod always uses
+// the same Egress IP as long as this IP is in the list, so adding or removing an Egress IP only remaps the Pods using
+// the removed IP or picking the added one.
+func getPodEgressIPIndex(pod string, egressIPs []string) int {
+	if len(egressIPs) == 1 {
+		return 0
+	}
+	index := 0
+	var maxWeight uint64
+	for i, ip := range egressIPs {
+		h := fnv.New64a()
+		h.Write([]byte(pod))
+		h.Write([]byte{0})
+		h.Write([]byte(ip))
+		// FNV hashes of inputs differing only in their last bytes are poorly distributed, mix the bits with the
+		// finalizer of MurmurHash3 before comparing them.
+		weight := h.Sum64()
+		weight ^= weight >> 33
+		weight *= 0xff51afd7ed558ccd
+		weight ^= weight >> 33
+		weight *= 0xc4ceb9fe1a85ec53
+		weight ^= weight >> 33
+		if i == 0 || weight > maxWeight {
+			index, maxWeight = i, weight
+		}
+	}
+	return index
+}
+
+// getEgressDestinations returns the destination CIDRs of an Egress with destinations, in their canonical form. The
+// ClusterGroups which don't exist yet don't contribute any CIDR, the Egress will be resynced when they are created.
+func (c *EgressController) getEgressDestinations(egress *crdv1a2.Egress) sets.String {
+	destinations := sets.NewString()
+	addCIDR := func(cidr string) {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			klog.ErrorS(err, "Invalid destination CIDR of Egress", "egress", egress.Name, "cidr", cidr)
+			return
+		}
+		destinations.Insert(ipNet.String())
+	}
+	var addGroup func(name string, isChild bool)
+	addGroup = func(name string, isChild bool) {
+		group, err := c.clusterGroupLister.Get(name)
+		if err != nil {
+			klog.V(2).InfoS("ClusterGroup referred by Egress not found", "egress", egress.Name, "clusterGroup", name)
+			return
+		}
+		for _, ipBlock := range group.Spec.IPBlocks {
+			addCIDR(ipBlock.CIDR)
+		}
+		// ChildGroups cannot contain ChildGroups themselves.
+		if !isChild {
+			for _, child := range group.Spec.ChildGroups {
+				addGroup(string(child), true)
+			}
+		}
+	}
+	for _, peer := range egress.Spec.To {
+		if peer.IPBlock != nil {
+			addCIDR(peer.IPBlock.CIDR)
+		}
+		if peer.Group != "" {
+			addGroup(peer.Group, false)
+		}
+	}
+	return destinations
+}
+
+// syncEgressStats collects the packets dropped by the meters of the local Egress IPs since the last collection, and
+// adds them to the metrics and the status of the Egresses sharing the IPs.
+func (c *EgressController) syncEgressStats() {
+	droppedPackets, err := c.ofClient.GetEgressMeterDroppedPackets()
+	if err != nil {
+		if err != openflow.ErrOVSMetersNotSupported {
+			klog.ErrorS(err, "Failed to get the packets dropped by Egress meters")
+		}
+		return
+	}
+	newDroppedPackets := map[string]uint64{}
+	func() {
+		c.egressIPStatesMutex.Lock()
+		defer c.egressIPStatesMutex.Unlock()
+		for _, ipState := range c.egressIPStates {
+			if ipState.meterBandwidth == nil {
+				continue
+			}
+			count, exists := droppedPackets[ipState.mark]
+			if !exists {
+				continue
+			}
+			delta := count
+			// The counter is reset when the meter is reinstalled, e.g. after OVS is restarted.
+			if count >= ipState.droppedPackets {
+				delta = count - ipState.droppedPackets
+			}
+			ipState.droppedPackets = count
+			if delta == 0 {
+				continue
+			}
+			for name := range ipState.egressNames {
+				newDroppedPackets[name] += delta
+			}
+		}
+	}()
+	for egressName, delta := range newDroppedPackets {
+		agentmetrics.EgressDroppedPackets.WithLabelValues(egressName).Add(float64(delta))
+		if err := c.addEgressDroppedPackets(egressName, delta); err != nil {
+			klog.ErrorS(err, "Failed to update the dropped packets of Egress", "egress", egressName)
+		}
+	}
+}
+
+// addEgressDroppedPackets adds the number of newly dropped packets to the status of the Egress.
+func (c *EgressController) addEgressDroppedPackets(egressName string, delta uint64) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		egress, err := c.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), egressName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		toUpdate := egress.DeepCopy()
+		toUpdate.Status.DroppedPackets += int64(delta)
+		_, err = c.crdClient.CrdV1alpha2().Egresses().UpdateStatus(context.TODO(), toUpdate, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (c *EgressController) uninstallEgress(egressName string, eState *egressState) error {
+	// Uninstall all of its Pod flows.
+	if err := c.uninstallPodFlows(egressName, eState, eState.ofPorts, eState.pods); err != nil {
 		return err
 	}
+	for _, egressIP := range eState.egressIPs {
+		// Release the EgressIP's mark if the Egress is the last one referring to it.
+		if err := c.unrealizeEgressIP(egressName, egressIP); err != nil {
+			return err
+		}
+		// Unassign the Egress IP from the local Node if it was assigned by the agent.
+		if err := c.ipAssigner.UnassignIP(egressIP); err != nil {
+			return err
+		}
+	}
 	// Remove the Egress's state.
 	c.deleteEgressState(egressName)
 	return nil
@@ -683,7 +1149,11 @@ func (c *EgressController) uninstallEgress(egressName string, eState *egressStat
 
 func (c *EgressController) uninstallPodFlows(egressName string, egressState *egressState, ofPorts sets.Int32, pods sets.String) error {
 	for ofPort := range ofPorts {
-		if err := c.ofClient.UninstallPodSNATFlows(uint32(ofPort)); err != nil {
+		if egressState.restricted {
+			if err := c.ofClient.UninstallPodDestinationSNATFlows(uint32(ofPort), egressName); err != nil {
+				return err
+			}
+		} else if err := c.ofClient.UninstallPodSNATFlows(uint32(ofPort)); err != nil {
 			return err
 		}
 		egressState.ofPorts.Delete(ofPort)
@@ -696,6 +1166,10 @@ func (c *EgressController) uninstallPodFlows(egressName string, egressState *egr
 	newEffectiveEgresses := sets.NewString()
 	for pod := range pods {
 		delete(egressState.pods, pod)
+		// The Pods are not bound to the Egresses with destinations.
+		if egressState.restricted {
+			continue
+		}
 		newEffectiveEgress, exists := c.unbindPodEgress(pod, egressName)
 		if exists {
 			newEffectiveEgresses.Insert(newEffectiveEgress)
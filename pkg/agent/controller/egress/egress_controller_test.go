@@ -16,6 +16,7 @@ package egress
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"reflect"
 	"testing"
@@ -32,11 +33,14 @@ import (
 
 	ipassignertest "antrea.io/antrea/pkg/agent/controller/egress/ipassigner/testing"
 	"antrea.io/antrea/pkg/agent/interfacestore"
+	"antrea.io/antrea/pkg/agent/openflow"
 	openflowtest "antrea.io/antrea/pkg/agent/openflow/testing"
 	routetest "antrea.io/antrea/pkg/agent/route/testing"
 	"antrea.io/antrea/pkg/agent/util"
 	cpv1b2 "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
 	crdv1a2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+	crdv1a3 "antrea.io/antrea/pkg/apis/crd/v1alpha3"
 	"antrea.io/antrea/pkg/client/clientset/versioned"
 	"antrea.io/antrea/pkg/client/clientset/versioned/fake"
 	fakeversioned "antrea.io/antrea/pkg/client/clientset/versioned/fake"
@@ -102,6 +106,7 @@ func newFakeController(t *testing.T, initObjects []runtime.Object) *fakeControll
 	crdClient := fakeversioned.NewSimpleClientset(initObjects...)
 	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
 	egressInformer := crdInformerFactory.Crd().V1alpha2().Egresses()
+	clusterGroupInformer := crdInformerFactory.Crd().V1alpha3().ClusterGroups()
 	localIPDetector := &fakeLocalIPDetector{localIPs: sets.NewString(fakeLocalEgressIP1, fakeLocalEgressIP2)}
 	idAllocator := newIDAllocator(minEgressMark, maxEgressMark)
 
@@ -112,22 +117,25 @@ func newFakeController(t *testing.T, initObjects []runtime.Object) *fakeControll
 	addPodInterface(ifaceStore, "ns4", "pod4", 4)
 
 	egressController := &EgressController{
-		ofClient:             mockOFClient,
-		routeClient:          mockRouteClient,
-		antreaClientProvider: &antreaClientGetter{clientset},
-		egressInformer:       egressInformer.Informer(),
-		egressLister:         egressInformer.Lister(),
-		egressListerSynced:   egressInformer.Informer().HasSynced,
-		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "egressgroup"),
-		localIPDetector:      localIPDetector,
-		ifaceStore:           ifaceStore,
-		nodeName:             fakeNode,
-		idAllocator:          idAllocator,
-		egressGroups:         map[string]sets.String{},
-		egressBindings:       map[string]*egressBinding{},
-		egressStates:         map[string]*egressState{},
-		egressIPStates:       map[string]*egressIPState{},
-		ipAssigner:           mockIPAssigner,
+		ofClient:                 mockOFClient,
+		routeClient:              mockRouteClient,
+		antreaClientProvider:     &antreaClientGetter{clientset},
+		crdClient:                crdClient,
+		egressInformer:           egressInformer.Informer(),
+		egressLister:             egressInformer.Lister(),
+		egressListerSynced:       egressInformer.Informer().HasSynced,
+		clusterGroupLister:       clusterGroupInformer.Lister(),
+		clusterGroupListerSynced: clusterGroupInformer.Informer().HasSynced,
+		queue:                    workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "egressgroup"),
+		localIPDetector:          localIPDetector,
+		ifaceStore:               ifaceStore,
+		nodeName:                 fakeNode,
+		idAllocator:              idAllocator,
+		egressGroups:             map[string]sets.String{},
+		egressBindings:           map[string]*egressBinding{},
+		egressStates:             map[string]*egressState{},
+		egressIPStates:           map[string]*egressIPState{},
+		ipAssigner:               mockIPAssigner,
 	}
 	return &fakeController{
 		EgressController:   egressController,
@@ -176,9 +184,9 @@ func TestSyncEgress(t *testing.T) {
 			},
 			newLocalIPs: sets.NewString(),
 			expectedCalls: func(mockOFClient *openflowtest.MockClient, mockRouteClient *routetest.MockInterface, mockIPAssigner *ipassignertest.MockIPAssigner) {
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 
@@ -188,8 +196,8 @@ func TestSyncEgress(t *testing.T) {
 				mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(2))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(0))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP1), uint32(0))
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(0), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP1), uint32(0), false)
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 			},
 		},
@@ -219,17 +227,17 @@ func TestSyncEgress(t *testing.T) {
 			},
 			newLocalIPs: sets.NewString(fakeRemoteEgressIP1),
 			expectedCalls: func(mockOFClient *openflowtest.MockClient, mockRouteClient *routetest.MockInterface, mockIPAssigner *ipassignertest.MockIPAssigner) {
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
 				mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 
 				mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(1))
 				mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(2))
 				mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeRemoteEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeRemoteEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeRemoteEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeRemoteEgressIP1), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeRemoteEgressIP1), uint32(1))
 				mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 			},
@@ -259,9 +267,9 @@ func TestSyncEgress(t *testing.T) {
 				},
 			},
 			expectedCalls: func(mockOFClient *openflowtest.MockClient, mockRouteClient *routetest.MockInterface, mockIPAssigner *ipassignertest.MockIPAssigner) {
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 
@@ -272,9 +280,9 @@ func TestSyncEgress(t *testing.T) {
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP2)
 
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP2), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP2), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP2), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP2), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP2), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP2), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP2), uint32(1))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP2)
 			},
@@ -304,9 +312,9 @@ func TestSyncEgress(t *testing.T) {
 				},
 			},
 			expectedCalls: func(mockOFClient *openflowtest.MockClient, mockRouteClient *routetest.MockInterface, mockIPAssigner *ipassignertest.MockIPAssigner) {
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 
@@ -317,8 +325,8 @@ func TestSyncEgress(t *testing.T) {
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 				mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
 				mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 			},
 		},
@@ -347,8 +355,8 @@ func TestSyncEgress(t *testing.T) {
 				},
 			},
 			expectedCalls: func(mockOFClient *openflowtest.MockClient, mockRouteClient *routetest.MockInterface, mockIPAssigner *ipassignertest.MockIPAssigner) {
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
 				mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 
 				mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(1))
@@ -356,9 +364,9 @@ func TestSyncEgress(t *testing.T) {
 				mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 			},
@@ -388,14 +396,14 @@ func TestSyncEgress(t *testing.T) {
 				},
 			},
 			expectedCalls: func(mockOFClient *openflowtest.MockClient, mockRouteClient *routetest.MockInterface, mockIPAssigner *ipassignertest.MockIPAssigner) {
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP2), uint32(2))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP2), uint32(2))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP2), uint32(2), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP2), uint32(2), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP2), uint32(2))
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP2)
 
@@ -427,11 +435,11 @@ func TestSyncEgress(t *testing.T) {
 				},
 			},
 			expectedCalls: func(mockOFClient *openflowtest.MockClient, mockRouteClient *routetest.MockInterface, mockIPAssigner *ipassignertest.MockIPAssigner) {
-				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 				mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+				mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 				mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1).Times(3)
 			},
 		},
@@ -528,22 +536,22 @@ func TestSyncOverlappingEgress(t *testing.T) {
 	item, _ = c.queue.Get()
 	c.queue.Done(item)
 
-	c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1))
-	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1))
-	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
 	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 	err := c.syncEgress(egress1.Name)
 	assert.NoError(t, err)
 
 	// egress2's IP is not local and pod1 has enforced egress1, so only one Pod SNAT flow is expected.
-	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(3), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
 	c.mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 	err = c.syncEgress(egress2.Name)
 	assert.NoError(t, err)
 
 	// egress3 shares the same IP as egress1 and pod2 has enforced egress1, so only one Pod SNAT flow is expected.
-	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(4), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(4), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 	err = c.syncEgress(egress3.Name)
 	assert.NoError(t, err)
@@ -572,13 +580,13 @@ func TestSyncOverlappingEgress(t *testing.T) {
 	assert.ElementsMatch(t, []string{egress2.Name, egress3.Name}, pendingItems)
 
 	// pod1 is expected to enforce egress2.
-	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
 	c.mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
 	err = c.syncEgress(egress2.Name)
 	assert.NoError(t, err)
 
 	// pod2 is expected to enforce egress3.
-	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
 	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
 	err = c.syncEgress(egress3.Name)
 	assert.NoError(t, err)
@@ -616,6 +624,263 @@ func TestSyncOverlappingEgress(t *testing.T) {
 	assert.Len(t, c.egressIPStates, 0)
 }
 
+func TestSyncEgressBandwidth(t *testing.T) {
+	egress1 := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec: crdv1a2.EgressSpec{
+			EgressIP:  fakeLocalEgressIP1,
+			Bandwidth: &crdv1a2.Bandwidth{Rate: "10M"},
+		},
+	}
+	egressGroup1 := &cpv1b2.EgressGroup{
+		ObjectMeta:   metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		GroupMembers: []cpv1b2.GroupMember{{Pod: &cpv1b2.PodReference{Name: "pod1", Namespace: "ns1"}}},
+	}
+	// egress2 has the same EgressIP and a lower bandwidth limit.
+	egress2 := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressB", UID: "uidB"},
+		Spec: crdv1a2.EgressSpec{
+			EgressIP:  fakeLocalEgressIP1,
+			Bandwidth: &crdv1a2.Bandwidth{Rate: "5M", Burst: "1M"},
+		},
+	}
+	egressGroup2 := &cpv1b2.EgressGroup{
+		ObjectMeta:   metav1.ObjectMeta{Name: "egressB", UID: "uidB"},
+		GroupMembers: []cpv1b2.GroupMember{{Pod: &cpv1b2.PodReference{Name: "pod2", Namespace: "ns2"}}},
+	}
+	c := newFakeController(t, []runtime.Object{egress1, egress2})
+	defer c.mockController.Finish()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	c.crdInformerFactory.WaitForCacheSync(stopCh)
+	c.addEgressGroup(egressGroup1)
+	c.addEgressGroup(egressGroup2)
+	item, _ := c.queue.Get()
+	c.queue.Done(item)
+	item, _ = c.queue.Get()
+	c.queue.Done(item)
+
+	// The meter is installed before the flows referring to it.
+	gomock.InOrder(
+		c.mockOFClient.EXPECT().InstallEgressMeter(uint32(1), uint32(10000), uint32(10000)),
+		c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), true),
+	)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), true)
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	require.NoError(t, c.syncEgress(egress1.Name))
+
+	// The lowest bandwidth limit of the Egresses sharing the IP is enforced.
+	c.mockOFClient.EXPECT().InstallEgressMeter(uint32(1), uint32(5000), uint32(1000))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(2), net.ParseIP(fakeLocalEgressIP1), uint32(1), true)
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	require.NoError(t, c.syncEgress(egress2.Name))
+	require.Equal(t, 0, c.queue.Len())
+
+	// After deleting egress2, egress1 is expected to be triggered for resync to restore its bandwidth limit.
+	c.mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(2))
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	c.crdClient.CrdV1alpha2().Egresses().Delete(context.TODO(), egress2.Name, metav1.DeleteOptions{})
+	assert.NoError(t, wait.Poll(time.Millisecond*100, time.Second, func() (bool, error) {
+		_, err := c.egressLister.Get(egress2.Name)
+		return err != nil, nil
+	}))
+	require.NoError(t, c.syncEgress(egress2.Name))
+	require.Equal(t, 1, c.queue.Len())
+	item, _ = c.queue.Get()
+	c.queue.Done(item)
+	assert.Equal(t, egress1.Name, item)
+	c.mockOFClient.EXPECT().InstallEgressMeter(uint32(1), uint32(10000), uint32(10000))
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	require.NoError(t, c.syncEgress(egress1.Name))
+
+	// After removing the bandwidth limit, the flows no longer refer to the meter before it is uninstalled.
+	updatedEgress1 := egress1.DeepCopy()
+	updatedEgress1.Spec.Bandwidth = nil
+	c.crdClient.CrdV1alpha2().Egresses().Update(context.TODO(), updatedEgress1, metav1.UpdateOptions{})
+	assert.NoError(t, wait.Poll(time.Millisecond*100, time.Second, func() (bool, error) {
+		egress, _ := c.egressLister.Get(egress1.Name)
+		return egress.Spec.Bandwidth == nil, nil
+	}))
+	gomock.InOrder(
+		c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false),
+		c.mockOFClient.EXPECT().UninstallEgressMeter(uint32(1)),
+	)
+	c.mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(1))
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1).Times(2)
+	require.NoError(t, c.syncEgress(egress1.Name))
+	// Call it one more time to ensure it's idempotent, no extra datapath calls are supposed to be made.
+	require.NoError(t, c.syncEgress(egress1.Name))
+}
+
+func TestSyncEgressBandwidthMetersNotSupported(t *testing.T) {
+	egress := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec: crdv1a2.EgressSpec{
+			EgressIP:  fakeLocalEgressIP1,
+			Bandwidth: &crdv1a2.Bandwidth{Rate: "10M"},
+		},
+	}
+	c := newFakeController(t, []runtime.Object{egress})
+	defer c.mockController.Finish()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	c.crdInformerFactory.WaitForCacheSync(stopCh)
+
+	c.mockOFClient.EXPECT().InstallEgressMeter(uint32(1), uint32(10000), uint32(10000)).Return(openflow.ErrOVSMetersNotSupported)
+	c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	require.NoError(t, c.syncEgress(egress.Name))
+}
+
+func TestSyncEgressWithDestinations(t *testing.T) {
+	egress1 := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec: crdv1a2.EgressSpec{
+			EgressIP: fakeLocalEgressIP1,
+			To: []crdv1a2.EgressPeer{
+				{IPBlock: &crdv1alpha1.IPBlock{CIDR: "10.1.2.3/8"}},
+				{Group: "cg1"},
+			},
+		},
+	}
+	egress2 := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressB", UID: "uidB"},
+		Spec:       crdv1a2.EgressSpec{EgressIP: fakeLocalEgressIP1},
+	}
+	cg1 := &crdv1a3.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "cg1"},
+		Spec: crdv1a3.GroupSpec{
+			IPBlocks:    []crdv1alpha1.IPBlock{{CIDR: "192.168.0.0/16"}},
+			ChildGroups: []crdv1a3.ClusterGroupReference{"cg2"},
+		},
+	}
+	cg2 := &crdv1a3.ClusterGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "cg2"},
+		Spec: crdv1a3.GroupSpec{
+			IPBlocks: []crdv1alpha1.IPBlock{{CIDR: "172.16.0.0/12"}},
+		},
+	}
+	// Both Egresses apply to pod1.
+	egressGroup1 := &cpv1b2.EgressGroup{
+		ObjectMeta:   metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		GroupMembers: []cpv1b2.GroupMember{{Pod: &cpv1b2.PodReference{Name: "pod1", Namespace: "ns1"}}},
+	}
+	egressGroup2 := &cpv1b2.EgressGroup{
+		ObjectMeta:   metav1.ObjectMeta{Name: "egressB", UID: "uidB"},
+		GroupMembers: []cpv1b2.GroupMember{{Pod: &cpv1b2.PodReference{Name: "pod1", Namespace: "ns1"}}},
+	}
+	c := newFakeController(t, []runtime.Object{egress1, egress2, cg1, cg2})
+	defer c.mockController.Finish()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	c.crdInformerFactory.WaitForCacheSync(stopCh)
+	c.addEgressGroup(egressGroup1)
+	c.addEgressGroup(egressGroup2)
+
+	parseCIDRs := func(cidrs ...string) []net.IPNet {
+		var ipNets []net.IPNet
+		for _, cidr := range cidrs {
+			_, ipNet, _ := net.ParseCIDR(cidr)
+			ipNets = append(ipNets, *ipNet)
+		}
+		return ipNets
+	}
+
+	c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	c.mockOFClient.EXPECT().InstallPodDestinationSNATFlows(uint32(1), egress1.Name, net.ParseIP(fakeLocalEgressIP1), uint32(1), false,
+		parseCIDRs("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"))
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	require.NoError(t, c.syncEgress(egress1.Name))
+
+	// The Egress with destinations doesn't compete with the other Egresses applying to the Pod.
+	c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(1), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	require.NoError(t, c.syncEgress(egress2.Name))
+	assert.Equal(t, &egressBinding{effectiveEgress: egress2.Name, alternativeEgresses: sets.NewString()}, c.egressBindings["ns1/pod1"])
+
+	// Updating a child ClusterGroup changes the destinations of the Egress.
+	updatedCG2 := cg2.DeepCopy()
+	updatedCG2.Spec.IPBlocks = []crdv1alpha1.IPBlock{{CIDR: "172.17.0.0/16"}}
+	c.crdClient.CrdV1alpha3().ClusterGroups().Update(context.TODO(), updatedCG2, metav1.UpdateOptions{})
+	assert.NoError(t, wait.Poll(time.Millisecond*100, time.Second, func() (bool, error) {
+		group, _ := c.clusterGroupLister.Get(cg2.Name)
+		return group.Spec.IPBlocks[0].CIDR == "172.17.0.0/16", nil
+	}))
+	c.mockOFClient.EXPECT().UninstallPodDestinationSNATFlows(uint32(1), egress1.Name)
+	c.mockOFClient.EXPECT().InstallPodDestinationSNATFlows(uint32(1), egress1.Name, net.ParseIP(fakeLocalEgressIP1), uint32(1), false,
+		parseCIDRs("10.0.0.0/8", "172.17.0.0/16", "192.168.0.0/16"))
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1).Times(2)
+	require.NoError(t, c.syncEgress(egress1.Name))
+	// Call it one more time to ensure it's idempotent, no extra datapath calls are supposed to be made.
+	require.NoError(t, c.syncEgress(egress1.Name))
+
+	// Deleting the Egress with destinations doesn't affect the binding of the Pod.
+	c.mockOFClient.EXPECT().UninstallPodDestinationSNATFlows(uint32(1), egress1.Name)
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	c.crdClient.CrdV1alpha2().Egresses().Delete(context.TODO(), egress1.Name, metav1.DeleteOptions{})
+	assert.NoError(t, wait.Poll(time.Millisecond*100, time.Second, func() (bool, error) {
+		_, err := c.egressLister.Get(egress1.Name)
+		return err != nil, nil
+	}))
+	require.NoError(t, c.syncEgress(egress1.Name))
+	assert.Equal(t, &egressBinding{effectiveEgress: egress2.Name, alternativeEgresses: sets.NewString()}, c.egressBindings["ns1/pod1"])
+}
+
+func TestSyncEgressStats(t *testing.T) {
+	egress1 := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec:       crdv1a2.EgressSpec{EgressIP: fakeLocalEgressIP1},
+	}
+	egress2 := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressB", UID: "uidB"},
+		Spec:       crdv1a2.EgressSpec{EgressIP: fakeLocalEgressIP2},
+	}
+	c := newFakeController(t, []runtime.Object{egress1, egress2})
+	defer c.mockController.Finish()
+	c.egressIPStates[fakeLocalEgressIP1] = &egressIPState{
+		egressIP:       net.ParseIP(fakeLocalEgressIP1),
+		egressNames:    sets.NewString(egress1.Name),
+		mark:           1,
+		meterBandwidth: &meterBandwidth{rate: 10000, burst: 10000},
+	}
+	// The Egress IP doesn't have a meter.
+	c.egressIPStates[fakeLocalEgressIP2] = &egressIPState{
+		egressIP:    net.ParseIP(fakeLocalEgressIP2),
+		egressNames: sets.NewString(egress2.Name),
+		mark:        2,
+	}
+	getDroppedPackets := func(name string) int64 {
+		egress, err := c.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return egress.Status.DroppedPackets
+	}
+
+	c.mockOFClient.EXPECT().GetEgressMeterDroppedPackets().Return(map[uint32]uint64{1: 10, 2: 5}, nil)
+	c.syncEgressStats()
+	assert.Equal(t, int64(10), getDroppedPackets(egress1.Name))
+	assert.Equal(t, int64(0), getDroppedPackets(egress2.Name))
+
+	c.mockOFClient.EXPECT().GetEgressMeterDroppedPackets().Return(map[uint32]uint64{1: 15}, nil)
+	c.syncEgressStats()
+	assert.Equal(t, int64(15), getDroppedPackets(egress1.Name))
+
+	// The counter of the meter was reset.
+	c.mockOFClient.EXPECT().GetEgressMeterDroppedPackets().Return(map[uint32]uint64{1: 3}, nil)
+	c.syncEgressStats()
+	assert.Equal(t, int64(18), getDroppedPackets(egress1.Name))
+
+	c.mockOFClient.EXPECT().GetEgressMeterDroppedPackets().Return(nil, openflow.ErrOVSMetersNotSupported)
+	c.syncEgressStats()
+	assert.Equal(t, int64(18), getDroppedPackets(egress1.Name))
+}
+
 func addPodInterface(ifaceStore interfacestore.InterfaceStore, podNamespace, podName string, ofPort int32) {
 	containerName := k8s.NamespacedName(podNamespace, podName)
 	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
@@ -624,3 +889,213 @@ func addPodInterface(ifaceStore interfacestore.InterfaceStore, podNamespace, pod
 		OVSPortConfig:            &interfacestore.OVSPortConfig{OFPort: ofPort},
 	})
 }
+
+func TestUpdateEgressStatus(t *testing.T) {
+	egress := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec:       crdv1a2.EgressSpec{EgressIP: fakeLocalEgressIP1},
+	}
+	c := newFakeController(t, []runtime.Object{egress})
+	defer c.mockController.Finish()
+	getEgress := func() *crdv1a2.Egress {
+		egress, err := c.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), egress.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return egress
+	}
+
+	// The first assignment is not a failover.
+	require.NoError(t, c.updateEgressStatus(getEgress(), "node2", ""))
+	egress = getEgress()
+	assert.Equal(t, "node2", egress.Status.EgressNode)
+	assert.Empty(t, egress.Status.Reason)
+	assert.Empty(t, egress.Status.FailoverHistory)
+
+	require.NoError(t, c.updateEgressStatus(egress, fakeNode, "NodeUnhealthy: Gateway health check failed"))
+	egress = getEgress()
+	assert.Equal(t, fakeNode, egress.Status.EgressNode)
+	assert.Equal(t, "NodeUnhealthy: Gateway health check failed", egress.Status.Reason)
+	require.Len(t, egress.Status.FailoverHistory, 1)
+	assert.Equal(t, "node2", egress.Status.FailoverHistory[0].FromNode)
+	assert.Equal(t, fakeNode, egress.Status.FailoverHistory[0].ToNode)
+	assert.Equal(t, "NodeUnhealthy: Gateway health check failed", egress.Status.FailoverHistory[0].Reason)
+
+	// Only the most recent failovers are kept.
+	for i := 0; i < maxEgressFailoverHistory; i++ {
+		nodeName := fakeNode
+		if i%2 == 0 {
+			nodeName = "node2"
+		}
+		require.NoError(t, c.updateEgressStatus(egress, nodeName, "NodeNotAlive"))
+		egress = getEgress()
+	}
+	assert.Equal(t, "NodeNotAlive", egress.Status.Reason)
+	require.Len(t, egress.Status.FailoverHistory, maxEgressFailoverHistory)
+	assert.Equal(t, "NodeNotAlive", egress.Status.FailoverHistory[0].Reason)
+}
+
+func TestSyncEgressWithMultipleIPs(t *testing.T) {
+	egress := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec:       crdv1a2.EgressSpec{EgressIPs: []string{fakeLocalEgressIP1, fakeRemoteEgressIP1}},
+	}
+	egressGroup := &cpv1b2.EgressGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		GroupMembers: []cpv1b2.GroupMember{
+			{Pod: &cpv1b2.PodReference{Name: "pod1", Namespace: "ns1"}},
+			{Pod: &cpv1b2.PodReference{Name: "pod2", Namespace: "ns2"}},
+			{Pod: &cpv1b2.PodReference{Name: "pod3", Namespace: "ns3"}},
+			{Pod: &cpv1b2.PodReference{Name: "pod4", Namespace: "ns4"}},
+		},
+	}
+	c := newFakeController(t, []runtime.Object{egress})
+	defer c.mockController.Finish()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	c.crdInformerFactory.WaitForCacheSync(stopCh)
+	c.addEgressGroup(egressGroup)
+
+	c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1)
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
+	// The Pods are hashed among the Egress IPs, the local one is used with its mark and the remote one without mark.
+	usedIPs := sets.NewString()
+	for i := 1; i <= 4; i++ {
+		pod := fmt.Sprintf("ns%d/pod%d", i, i)
+		if getPodEgressIPIndex(pod, egress.Spec.EgressIPs) == 0 {
+			c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(i), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+			usedIPs.Insert(fakeLocalEgressIP1)
+		} else {
+			c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(i), net.ParseIP(fakeRemoteEgressIP1), uint32(0), false)
+			usedIPs.Insert(fakeRemoteEgressIP1)
+		}
+	}
+	require.NoError(t, c.syncEgress(egress.Name))
+	assert.Equal(t, 2, usedIPs.Len(), "The Pods should be spread across the Egress IPs")
+
+	// Removing an Egress IP uninstalls the Egress first.
+	updatedEgress := egress.DeepCopy()
+	updatedEgress.Spec.EgressIPs = []string{fakeLocalEgressIP1}
+	c.crdClient.CrdV1alpha2().Egresses().Update(context.TODO(), updatedEgress, metav1.UpdateOptions{})
+	assert.NoError(t, wait.Poll(time.Millisecond*100, time.Second, func() (bool, error) {
+		egress, _ := c.egressLister.Get(egress.Name)
+		return len(egress.Spec.EgressIPs) == 1, nil
+	}))
+	for i := 1; i <= 4; i++ {
+		c.mockOFClient.EXPECT().UninstallPodSNATFlows(uint32(i))
+		c.mockOFClient.EXPECT().InstallPodSNATFlows(uint32(i), net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	}
+	c.mockRouteClient.EXPECT().DeleteSNATRule(uint32(1))
+	c.mockOFClient.EXPECT().UninstallSNATMarkFlows(uint32(1))
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeLocalEgressIP1).Times(2)
+	c.mockIPAssigner.EXPECT().UnassignIP(fakeRemoteEgressIP1)
+	c.mockOFClient.EXPECT().InstallSNATMarkFlows(net.ParseIP(fakeLocalEgressIP1), uint32(1), false)
+	c.mockRouteClient.EXPECT().AddSNATRule(net.ParseIP(fakeLocalEgressIP1), uint32(1))
+	require.NoError(t, c.syncEgress(egress.Name))
+}
+
+func TestUpdateEgressIPsStatus(t *testing.T) {
+	egress := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec: crdv1a2.EgressSpec{
+			EgressIPs:      []string{fakeLocalEgressIP1, fakeLocalEgressIP2, fakeRemoteEgressIP1},
+			ExternalIPPool: "pool1",
+		},
+		Status: crdv1a2.EgressStatus{
+			EgressIPs: []crdv1a2.EgressIPStatus{
+				{IP: fakeLocalEgressIP2, Node: fakeNode},
+				{IP: fakeRemoteEgressIP1, Node: "node2"},
+			},
+		},
+	}
+	c := newFakeController(t, []runtime.Object{egress})
+	defer c.mockController.Finish()
+
+	// The local Node takes over fakeLocalEgressIP1 and no longer holds fakeLocalEgressIP2. The IP held by the other
+	// Node is kept.
+	require.NoError(t, c.updateEgressIPsStatus(egress, sets.NewString(fakeLocalEgressIP1)))
+	updatedEgress, err := c.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), egress.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []crdv1a2.EgressIPStatus{
+		{IP: fakeLocalEgressIP1, Node: fakeNode},
+		{IP: fakeRemoteEgressIP1, Node: "node2"},
+	}, updatedEgress.Status.EgressIPs)
+
+	// The IPs removed from EgressIPs are removed from the status, whichever Node holds them.
+	updatedEgress.Spec.EgressIPs = []string{fakeLocalEgressIP1}
+	updatedEgress, err = c.crdClient.CrdV1alpha2().Egresses().Update(context.TODO(), updatedEgress, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, c.updateEgressIPsStatus(updatedEgress, sets.NewString(fakeLocalEgressIP1)))
+	updatedEgress, err = c.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), egress.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []crdv1a2.EgressIPStatus{
+		{IP: fakeLocalEgressIP1, Node: fakeNode},
+	}, updatedEgress.Status.EgressIPs)
+}
+
+func TestSyncEgressRemovesStaleEgressIPsStatus(t *testing.T) {
+	// EgressIPs has been emptied, the IPs left in the status must be removed.
+	egress := &crdv1a2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec:       crdv1a2.EgressSpec{ExternalIPPool: "pool1"},
+		Status: crdv1a2.EgressStatus{
+			EgressIPs: []crdv1a2.EgressIPStatus{
+				{IP: fakeLocalEgressIP1, Node: fakeNode},
+				{IP: fakeRemoteEgressIP1, Node: "node2"},
+			},
+		},
+	}
+	c := newFakeController(t, []runtime.Object{egress})
+	defer c.mockController.Finish()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.crdInformerFactory.Start(stopCh)
+	c.crdInformerFactory.WaitForCacheSync(stopCh)
+
+	require.NoError(t, c.syncEgress(egress.Name))
+	updatedEgress, err := c.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), egress.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, updatedEgress.Status.EgressIPs)
+}
+
+func TestGetPodEgressIPIndex(t *testing.T) {
+	egressIPs := []string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}
+	var pods []string
+	for i := 0; i < 1000; i++ {
+		pods = append(pods, fmt.Sprintf("ns%d/pod%d", i%10, i))
+	}
+	getPodIPs := func(egressIPs []string) map[string]string {
+		podIPs := map[string]string{}
+		for _, pod := range pods {
+			podIPs[pod] = egressIPs[getPodEgressIPIndex(pod, egressIPs)]
+		}
+		return podIPs
+	}
+	podIPs := getPodIPs(egressIPs)
+	ipPods := map[string]int{}
+	for _, ip := range podIPs {
+		ipPods[ip]++
+	}
+	for _, ip := range egressIPs {
+		assert.InDelta(t, len(pods)/len(egressIPs), ipPods[ip], float64(len(pods)/10), "The Pods should be spread evenly across the Egress IPs")
+	}
+	// The order of the Egress IPs doesn't matter.
+	assert.Equal(t, podIPs, getPodIPs([]string{"1.1.1.3", "1.1.1.1", "1.1.1.2"}))
+
+	// Removing an Egress IP only remaps the Pods using it.
+	for pod, ip := range getPodIPs([]string{"1.1.1.1", "1.1.1.3"}) {
+		if podIPs[pod] != "1.1.1.2" {
+			assert.Equal(t, podIPs[pod], ip, "Pod %s should keep its Egress IP", pod)
+		}
+	}
+	// Adding an Egress IP only remaps the Pods to it.
+	moved := 0
+	for pod, ip := range getPodIPs([]string{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4"}) {
+		if ip != podIPs[pod] {
+			assert.Equal(t, "1.1.1.4", ip, "Pod %s should keep its Egress IP or use the new one", pod)
+			moved++
+		}
+	}
+	assert.InDelta(t, len(pods)/4, moved, float64(len(pods)/10))
+}
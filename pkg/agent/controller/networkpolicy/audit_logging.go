@@ -0,0 +1,257 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// AuditLogFormatText writes each audit log entry as a line of text.
+	AuditLogFormatText = "text"
+	// AuditLogFormatJSON writes each audit log entry as a JSON object.
+	AuditLogFormatJSON = "json"
+
+	// AuditLogOutputFile writes the audit log entries to the rotated np.log file.
+	AuditLogOutputFile = "file"
+	// AuditLogOutputSyslog sends the audit log entries as RFC 5424 messages to a syslog server.
+	AuditLogOutputSyslog = "syslog"
+
+	syslogAppName = "antrea-agent"
+	syslogMsgID   = "networkpolicy"
+	// syslogPriority is the priority of the syslog messages: facility local0 (16) and severity informational (6).
+	syslogPriority = 16*8 + 6
+	// syslogTimestampFormat is the RFC 3339 format allowed by RFC 5424, with at most 6 digits for the fraction
+	// of second.
+	syslogTimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+	syslogDialTimeout     = 5 * time.Second
+
+	// logRateLimiterIdleTimeout is the time after which the rate limiter of a rule which hasn't logged any packet
+	// is released.
+	logRateLimiterIdleTimeout = 5 * time.Minute
+)
+
+// AuditLoggerConfig is the configuration of the audit logging of Antrea-native policy rules.
+type AuditLoggerConfig struct {
+	// Format is the format of the audit log entries, AuditLogFormatText or AuditLogFormatJSON.
+	Format string
+	// Output is where the audit log entries are written, AuditLogOutputFile or AuditLogOutputSyslog.
+	Output string
+	// SyslogNetwork is the network of the syslog server, "udp", "tcp" or "unix".
+	SyslogNetwork string
+	// SyslogAddress is the address of the syslog server, as "host:port" or the path of a unix socket.
+	SyslogAddress string
+	// RateLimit is the maximum number of packets logged per second for each rule. 0 means no limit.
+	RateLimit int
+	// RateLimitBurst is the maximum number of packets logged at once for each rule. It defaults to RateLimit.
+	RateLimitBurst int
+}
+
+// auditLogFormat is the format of the entries written to AntreaPolicyLogger.
+var auditLogFormat = AuditLogFormatText
+
+// auditLogOutput is where AntreaPolicyLogger writes the entries.
+var auditLogOutput = AuditLogOutputFile
+
+// ParseSyslogAddress parses the address of a syslog server provided as "udp://host:port", "tcp://host:port" or
+// "unix:///path", and returns its network and address.
+func ParseSyslogAddress(address string) (string, string, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid syslog address %s: %v", address, err)
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		if _, _, err := net.SplitHostPort(u.Host); err != nil {
+			return "", "", fmt.Errorf("invalid syslog address %s: %v", address, err)
+		}
+		return u.Scheme, u.Host, nil
+	case "unix":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("invalid syslog address %s: missing socket path", address)
+		}
+		return u.Scheme, u.Path, nil
+	default:
+		return "", "", fmt.Errorf("invalid syslog address %s: network must be udp, tcp or unix", address)
+	}
+}
+
+// auditLogRecord is an audit log entry in JSON format.
+type auditLogRecord struct {
+	Timestamp        string `json:"timestamp"`
+	Table            string `json:"table"`
+	PolicyRef        string `json:"policyRef"`
+	PolicyType       string `json:"policyType,omitempty"`
+	PolicyNamespace  string `json:"policyNamespace,omitempty"`
+	PolicyName       string `json:"policyName,omitempty"`
+	RuleName         string `json:"ruleName,omitempty"`
+	Disposition      string `json:"disposition"`
+	OFPriority       string `json:"ofPriority,omitempty"`
+	SrcIP            string `json:"srcIP"`
+	SrcPort          int    `json:"srcPort,omitempty"`
+	SrcPodNamespace  string `json:"srcPodNamespace,omitempty"`
+	SrcPodName       string `json:"srcPodName,omitempty"`
+	DestIP           string `json:"destIP"`
+	DestPort         int    `json:"destPort,omitempty"`
+	DestPodNamespace string `json:"destPodNamespace,omitempty"`
+	DestPodName      string `json:"destPodName,omitempty"`
+	PacketLength     uint16 `json:"packetLength,omitempty"`
+	Protocol         string `json:"protocol"`
+	HTTP             string `json:"http,omitempty"`
+}
+
+// logAuditRecord writes the record to AntreaPolicyLogger as a JSON object.
+func logAuditRecord(record *auditLogRecord) {
+	record.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	data, err := json.Marshal(record)
+	if err != nil {
+		klog.Errorf("Error encoding audit log record: %v", err)
+		return
+	}
+	AntreaPolicyLogger.Print(string(data))
+}
+
+// syslogWriter is an io.Writer which sends each write as an RFC 5424 message to a syslog server. Messages sent
+// over TCP are framed with octet counting (RFC 6587). The connection is re-established when a write fails.
+type syslogWriter struct {
+	mutex    sync.Mutex
+	network  string
+	address  string
+	hostname string
+	conn     net.Conn
+}
+
+func newSyslogWriter(network, address string) *syslogWriter {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogWriter{network: network, address: address, hostname: hostname}
+}
+
+func (w *syslogWriter) connect() error {
+	if w.network != "unix" {
+		conn, err := net.DialTimeout(w.network, w.address, syslogDialTimeout)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+		return nil
+	}
+	// The local syslog socket is usually a datagram socket, fall back to a stream socket otherwise.
+	var err error
+	for _, network := range []string{"unixgram", "unix"} {
+		var conn net.Conn
+		if conn, err = net.DialTimeout(network, w.address, syslogDialTimeout); err == nil {
+			w.conn = conn
+			return nil
+		}
+	}
+	return err
+}
+
+func (w *syslogWriter) format(t time.Time, msg string) []byte {
+	message := fmt.Sprintf("<%d>1 %s %s %s %d %s - %s", syslogPriority, t.Format(syslogTimestampFormat), w.hostname, syslogAppName, os.Getpid(), syslogMsgID, msg)
+	if w.network == "tcp" {
+		message = fmt.Sprintf("%d %s", len(message), message)
+	}
+	return []byte(message)
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	message := w.format(time.Now(), strings.TrimSuffix(string(p), "\n"))
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for attempt := 0; ; attempt++ {
+		if w.conn == nil {
+			if err := w.connect(); err != nil {
+				return 0, err
+			}
+		}
+		_, err := w.conn.Write(message)
+		if err == nil {
+			return len(p), nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		if attempt > 0 {
+			return 0, err
+		}
+	}
+}
+
+// logRateLimiter limits the number of packets logged per second for each rule, to protect the Agent when a rule
+// with logging enabled is matched by a large amount of traffic.
+type logRateLimiter struct {
+	mutex    sync.Mutex
+	limit    rate.Limit
+	burst    int
+	limiters map[uint32]*ruleLogRateLimiter
+	lastGC   time.Time
+}
+
+type ruleLogRateLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// newLogRateLimiter returns a new *logRateLimiter, or nil if there is no limit.
+func newLogRateLimiter(limit, burst int) *logRateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = limit
+	}
+	return &logRateLimiter{
+		limit:    rate.Limit(limit),
+		burst:    burst,
+		limiters: map[uint32]*ruleLogRateLimiter{},
+	}
+}
+
+// allow returns whether a packet logged by the rule identified by ruleID can be logged at the provided time.
+func (l *logRateLimiter) allow(ruleID uint32, now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if now.Sub(l.lastGC) > logRateLimiterIdleTimeout {
+		for id, limiter := range l.limiters {
+			if now.Sub(limiter.lastUsed) > logRateLimiterIdleTimeout {
+				delete(l.limiters, id)
+			}
+		}
+		l.lastGC = now
+	}
+	limiter, exists := l.limiters[ruleID]
+	if !exists {
+		limiter = &ruleLogRateLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[ruleID] = limiter
+	}
+	limiter.lastUsed = now
+	return limiter.limiter.AllowN(now, 1)
+}
@@ -0,0 +1,219 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1beta "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+)
+
+func TestParseSyslogAddress(t *testing.T) {
+	tests := []struct {
+		address         string
+		expectedNetwork string
+		expectedAddress string
+		expectedErr     bool
+	}{
+		{address: "udp://10.0.0.1:514", expectedNetwork: "udp", expectedAddress: "10.0.0.1:514"},
+		{address: "tcp://syslog.example.com:601", expectedNetwork: "tcp", expectedAddress: "syslog.example.com:601"},
+		{address: "unix:///dev/log", expectedNetwork: "unix", expectedAddress: "/dev/log"},
+		{address: "udp://10.0.0.1", expectedErr: true},
+		{address: "unix://", expectedErr: true},
+		{address: "http://10.0.0.1:514", expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			network, address, err := ParseSyslogAddress(tt.address)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedNetwork, network)
+			assert.Equal(t, tt.expectedAddress, address)
+		})
+	}
+}
+
+func TestLogRateLimiter(t *testing.T) {
+	assert.Nil(t, newLogRateLimiter(0, 10))
+	var nilLimiter *logRateLimiter
+	assert.True(t, nilLimiter.allow(1, time.Now()))
+
+	limiter := newLogRateLimiter(2, 0)
+	now := time.Now()
+	assert.True(t, limiter.allow(1, now))
+	assert.True(t, limiter.allow(1, now))
+	assert.False(t, limiter.allow(1, now))
+	// The rules are limited independently.
+	assert.True(t, limiter.allow(2, now))
+	// Tokens are added at the configured rate.
+	assert.True(t, limiter.allow(1, now.Add(500*time.Millisecond)))
+	assert.False(t, limiter.allow(1, now.Add(500*time.Millisecond)))
+	assert.Len(t, limiter.limiters, 2)
+	// The limiters of idle rules are released.
+	assert.True(t, limiter.allow(3, now.Add(logRateLimiterIdleTimeout+time.Second)))
+	assert.Len(t, limiter.limiters, 1)
+}
+
+func TestSyslogWriter(t *testing.T) {
+	syslogRegexp := regexp.MustCompile(`^<134>1 \S+ \S+ antrea-agent [0-9]+ networkpolicy - (.*)$`)
+
+	t.Run("udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+		logger := log.New(newSyslogWriter("udp", conn.LocalAddr().String()), "", 0)
+		logger.Print("message 1")
+
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		matches := syslogRegexp.FindStringSubmatch(string(buf[:n]))
+		require.NotNil(t, matches, "unexpected syslog message %q", string(buf[:n]))
+		assert.Equal(t, "message 1", matches[1])
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		logger := log.New(newSyslogWriter("tcp", listener.Addr().String()), "", 0)
+		logger.Print("message 1")
+		logger.Print("message 2")
+
+		conn, err := listener.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		reader := bufio.NewReader(conn)
+		for _, expected := range []string{"message 1", "message 2"} {
+			var length int
+			_, err := fmt.Fscanf(reader, "%d ", &length)
+			require.NoError(t, err)
+			message := make([]byte, length)
+			_, err = reader.Read(message)
+			require.NoError(t, err)
+			matches := syslogRegexp.FindStringSubmatch(string(message))
+			require.NotNil(t, matches, "unexpected syslog message %q", string(message))
+			assert.Equal(t, expected, matches[1])
+		}
+	})
+}
+
+func TestLogPacketJSON(t *testing.T) {
+	controller, _, _ := newTestController()
+	controller.ruleCache.addressSetByGroup["group1"] = v1beta.NewGroupMemberSet(&v1beta.GroupMember{
+		Pod: &v1beta.PodReference{Namespace: "ns1", Name: "pod1"},
+		IPs: []v1beta.IPAddress{v1beta.IPAddress(net.ParseIP("10.0.0.1"))},
+	})
+
+	var buf bytes.Buffer
+	prevLogger, prevFormat := AntreaPolicyLogger, auditLogFormat
+	AntreaPolicyLogger, auditLogFormat = log.New(&buf, "", 0), AuditLogFormatJSON
+	defer func() { AntreaPolicyLogger, auditLogFormat = prevLogger, prevFormat }()
+
+	ob := &logInfo{
+		tableName:   "AntreaPolicyIngressRule",
+		npRef:       "AntreaNetworkPolicy:ns1/np1",
+		ruleID:      1,
+		disposition: "Drop",
+		ofPriority:  "44900",
+		srcIP:       "10.0.0.1",
+		destIP:      "10.0.0.2",
+		pktLength:   60,
+		protocolStr: "TCP",
+	}
+	controller.writeLogInfo(ob)
+
+	var record auditLogRecord
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.NotEmpty(t, record.Timestamp)
+	record.Timestamp = ""
+	assert.Equal(t, auditLogRecord{
+		Table:           "AntreaPolicyIngressRule",
+		PolicyRef:       "AntreaNetworkPolicy:ns1/np1",
+		Disposition:     "Drop",
+		OFPriority:      "44900",
+		SrcIP:           "10.0.0.1",
+		SrcPodNamespace: "ns1",
+		SrcPodName:      "pod1",
+		DestIP:          "10.0.0.2",
+		PacketLength:    60,
+		Protocol:        "TCP",
+	}, record)
+}
+
+func TestLogPacketText(t *testing.T) {
+	controller, _, _ := newTestController()
+	controller.ruleCache.addressSetByGroup["group1"] = v1beta.NewGroupMemberSet(&v1beta.GroupMember{
+		Pod: &v1beta.PodReference{Namespace: "ns1", Name: "pod1"},
+		IPs: []v1beta.IPAddress{v1beta.IPAddress(net.ParseIP("10.0.0.1"))},
+	})
+	newLogInfo := func() *logInfo {
+		return &logInfo{
+			tableName:   "AntreaPolicyIngressRule",
+			npRef:       "AntreaNetworkPolicy:ns1/np1",
+			ruleID:      1,
+			disposition: "Drop",
+			ofPriority:  "44900",
+			srcIP:       "10.0.0.1",
+			destIP:      "10.0.0.2",
+			pktLength:   60,
+			protocolStr: "TCP",
+		}
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "file",
+			output:   AuditLogOutputFile,
+			expected: "AntreaPolicyIngressRule AntreaNetworkPolicy:ns1/np1 Drop 44900 SRC: 10.0.0.1 DEST: 10.0.0.2 60 TCP\n",
+		},
+		{
+			name:     "syslog",
+			output:   AuditLogOutputSyslog,
+			expected: "AntreaPolicyIngressRule AntreaNetworkPolicy:ns1/np1 Drop 44900 SRC: 10.0.0.1 DEST: 10.0.0.2 60 TCP RULE: - SRC_POD: ns1/pod1 DEST_POD: -\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			prevLogger, prevFormat, prevOutput := AntreaPolicyLogger, auditLogFormat, auditLogOutput
+			AntreaPolicyLogger, auditLogFormat, auditLogOutput = log.New(&buf, "", 0), AuditLogFormatText, tt.output
+			defer func() { AntreaPolicyLogger, auditLogFormat, auditLogOutput = prevLogger, prevFormat, prevOutput }()
+
+			controller.writeLogInfo(newLogInfo())
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net"
 	"sync"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -90,6 +91,12 @@ type rule struct {
 	SourceRef *v1beta.NetworkPolicyReference
 	// EnableLogging is a boolean indicating whether logging is required for Antrea Policies. Always false for K8s NetworkPolicy.
 	EnableLogging bool
+	// L7Protocols of this rule, which are enforced by the L7 engine. Empty for K8s NetworkPolicy. It's omitted
+	// when empty to keep the IDs of the other rules unchanged.
+	L7Protocols []v1beta.L7Protocol `json:",omitempty"`
+	// EnforcementMode of this rule. Empty for K8s NetworkPolicy. It's omitted when empty to keep the IDs of the
+	// other rules unchanged.
+	EnforcementMode crdv1alpha1.RuleEnforcementMode `json:",omitempty"`
 }
 
 // hashRule calculates a string based on the rule's content.
@@ -372,6 +379,30 @@ func (c *ruleCache) GetAddressGroupNum() int {
 	return len(c.addressSetByGroup)
 }
 
+// getPodByIP returns the reference of the Pod which has the IP among the
+// members of the AddressGroups, or nil if it's not found.
+func (c *ruleCache) getPodByIP(ip string) *v1beta.PodReference {
+	podIP := net.ParseIP(ip)
+	if podIP == nil {
+		return nil
+	}
+	c.addressSetLock.RLock()
+	defer c.addressSetLock.RUnlock()
+	for _, memberSet := range c.addressSetByGroup {
+		for _, member := range memberSet {
+			if member.Pod == nil {
+				continue
+			}
+			for _, memberIP := range member.IPs {
+				if net.IP(memberIP).Equal(podIP) {
+					return member.Pod
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // ReplaceAddressGroups atomically adds the given groups to the cache and deletes
 // the pre-existing groups that are not in the given groups from the cache.
 // It makes the cache in sync with the apiserver when restarting a watch.
@@ -564,6 +595,8 @@ func toRule(r *v1beta.NetworkPolicyRule, policy *v1beta.NetworkPolicy, maxPriori
 		PolicyUID:       policy.UID,
 		SourceRef:       policy.SourceRef,
 		EnableLogging:   r.EnableLogging,
+		L7Protocols:     r.L7Protocols,
+		EnforcementMode: r.EnforcementMode,
 	}
 	rule.ID = hashRule(rule)
 	rule.PolicyName = policy.Name
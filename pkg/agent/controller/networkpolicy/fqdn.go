@@ -0,0 +1,445 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/miekg/dns"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"antrea.io/antrea/pkg/agent/config"
+	"antrea.io/antrea/pkg/agent/openflow"
+)
+
+const (
+	// minDNSEntryTTL is the minimum time the IPs learned from DNS responses
+	// are kept for. It avoids re-programming the flows too often for the
+	// names resolved with a very short TTL, as the Pods may still use the IPs
+	// after the TTL of the records expired.
+	minDNSEntryTTL = 10 * time.Second
+	// maxDNSResponseHoldTime is the maximum time a DNS response which made
+	// rules dirty is held, waiting for the rules to be realized with the
+	// learned IPs, before it's delivered to the Pod.
+	maxDNSResponseHoldTime = 2 * time.Second
+)
+
+// fqdnSelector selects the names matching a FQDN set in an Antrea-native
+// policy rule, which can be an exact name or a pattern with "*" wildcards.
+type fqdnSelector struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+func newFQDNSelector(fqdn string) *fqdnSelector {
+	fqdn = toCanonicalName(fqdn)
+	if !strings.Contains(fqdn, "*") {
+		return &fqdnSelector{name: fqdn}
+	}
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(fqdn), `\*`, ".*")
+	return &fqdnSelector{name: fqdn, regex: regexp.MustCompile("^" + pattern + "$")}
+}
+
+func (s *fqdnSelector) matches(name string) bool {
+	if s.regex == nil {
+		return s.name == name
+	}
+	return s.regex.MatchString(name)
+}
+
+// toCanonicalName converts a DNS name to the format used as keys by the
+// fqdnController, i.e. lowercase without the trailing dot.
+func toCanonicalName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+// dnsResponseWaiter is used to hold a DNS response until the rules it made
+// dirty are realized with the IPs learned from it.
+type dnsResponseWaiter struct {
+	// pendingRules is the number of rules which are not realized yet.
+	pendingRules int
+	// done is closed when all the rules are realized.
+	done chan struct{}
+}
+
+func (w *dnsResponseWaiter) ruleRealized() {
+	w.pendingRules--
+	if w.pendingRules == 0 {
+		close(w.done)
+	}
+}
+
+// fqdnController learns the IPs of the FQDNs used in Antrea-native policy
+// rules by snooping the DNS responses received by the local Pods, which are
+// sent to the agent with packet-in messages. The learned IPs expire after the
+// TTL of their DNS records. When the IPs of the FQDNs used in a rule change,
+// the rule is notified as dirty, and the reconciler updates the destination
+// addresses of its flows with the IPs returned by addFQDNRule. The DNS
+// responses are held until then, so that the Pods don't connect to the IPs
+// before they are allowed, and are sent back to the pipeline with packet-out
+// messages.
+type fqdnController struct {
+	ofClient openflow.Client
+	// dirtyRuleHandler is called with the ID of the rules whose IPs changed.
+	dirtyRuleHandler func(string)
+
+	mutex sync.Mutex
+	// ruleSelectors maps the IDs of the rules to their FQDN selectors.
+	ruleSelectors map[string][]*fqdnSelector
+	// dnsEntries maps the names resolved by the Pods which match the FQDN
+	// selectors of the rules to their IPs, and each IP to its expiration
+	// time.
+	dnsEntries map[string]map[string]time.Time
+	// dirtyRuleWaiters maps the IDs of the dirty rules to the waiters of the
+	// DNS responses which made them dirty. The waiters are moved to
+	// fetchedRuleWaiters when the reconciler gets the IPs of the rules with
+	// addFQDNRule, and are notified when the reconciler calls ruleRealized.
+	dirtyRuleWaiters   map[string][]*dnsResponseWaiter
+	fetchedRuleWaiters map[string][]*dnsResponseWaiter
+	// dnsInterceptionInstalled indicates whether the flows to send the DNS
+	// responses to the agent are installed. They are only installed when
+	// there is at least one rule with FQDNs.
+	dnsInterceptionInstalled bool
+	// expirationQueue stores the names whose IPs must be checked for
+	// expiration at a given time.
+	expirationQueue workqueue.DelayingInterface
+}
+
+func newFQDNController(ofClient openflow.Client, dirtyRuleHandler func(string)) *fqdnController {
+	return &fqdnController{
+		ofClient:           ofClient,
+		dirtyRuleHandler:   dirtyRuleHandler,
+		ruleSelectors:      map[string][]*fqdnSelector{},
+		dnsEntries:         map[string]map[string]time.Time{},
+		dirtyRuleWaiters:   map[string][]*dnsResponseWaiter{},
+		fetchedRuleWaiters: map[string][]*dnsResponseWaiter{},
+		expirationQueue:    workqueue.NewNamedDelayingQueue("fqdn"),
+	}
+}
+
+// addFQDNRule registers the FQDNs of a rule and returns the IPs currently
+// known for them. The DNS interception flows are installed with the first
+// rule. The DNS responses which made the rule dirty are released when
+// ruleRealized is called next.
+func (f *fqdnController) addFQDNRule(ruleID string, fqdns []string) (sets.String, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.dnsInterceptionInstalled {
+		if err := f.ofClient.InstallDNSInterceptionFlows(); err != nil {
+			return nil, fmt.Errorf("error installing DNS interception flows: %v", err)
+		}
+		f.dnsInterceptionInstalled = true
+	}
+	selectors := make([]*fqdnSelector, 0, len(fqdns))
+	for _, fqdn := range fqdns {
+		selectors = append(selectors, newFQDNSelector(fqdn))
+	}
+	f.ruleSelectors[ruleID] = selectors
+	if waiters, exists := f.dirtyRuleWaiters[ruleID]; exists {
+		f.fetchedRuleWaiters[ruleID] = append(f.fetchedRuleWaiters[ruleID], waiters...)
+		delete(f.dirtyRuleWaiters, ruleID)
+	}
+	return f.getIPsForSelectors(selectors, time.Now()), nil
+}
+
+// ruleRealized releases the DNS responses waiting for a rule, whose flows
+// have been updated with the IPs returned by the last addFQDNRule call.
+func (f *fqdnController) ruleRealized(ruleID string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, waiter := range f.fetchedRuleWaiters[ruleID] {
+		waiter.ruleRealized()
+	}
+	delete(f.fetchedRuleWaiters, ruleID)
+}
+
+// deleteFQDNRule unregisters the FQDNs of a rule, and removes the names which
+// are no longer selected by any rule. The DNS interception flows are
+// uninstalled with the last rule.
+func (f *fqdnController) deleteFQDNRule(ruleID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.ruleSelectors, ruleID)
+	// The DNS responses don't need to wait for the rule anymore.
+	for _, waiter := range append(f.dirtyRuleWaiters[ruleID], f.fetchedRuleWaiters[ruleID]...) {
+		waiter.ruleRealized()
+	}
+	delete(f.dirtyRuleWaiters, ruleID)
+	delete(f.fetchedRuleWaiters, ruleID)
+	for name := range f.dnsEntries {
+		if len(f.getRulesForName(name)) == 0 {
+			delete(f.dnsEntries, name)
+		}
+	}
+	if len(f.ruleSelectors) == 0 && f.dnsInterceptionInstalled {
+		if err := f.ofClient.UninstallDNSInterceptionFlows(); err != nil {
+			return fmt.Errorf("error uninstalling DNS interception flows: %v", err)
+		}
+		f.dnsInterceptionInstalled = false
+	}
+	return nil
+}
+
+func (f *fqdnController) getIPsForSelectors(selectors []*fqdnSelector, now time.Time) sets.String {
+	ips := sets.NewString()
+	for name, entry := range f.dnsEntries {
+		for _, selector := range selectors {
+			if !selector.matches(name) {
+				continue
+			}
+			for ip, expiration := range entry {
+				if expiration.After(now) {
+					ips.Insert(ip)
+				}
+			}
+			break
+		}
+	}
+	return ips
+}
+
+// getRulesForName returns the IDs of the rules with a FQDN selector matching
+// the provided name. It must be called with the mutex held.
+func (f *fqdnController) getRulesForName(name string) sets.String {
+	ruleIDs := sets.NewString()
+	for ruleID, selectors := range f.ruleSelectors {
+		for _, selector := range selectors {
+			if selector.matches(name) {
+				ruleIDs.Insert(ruleID)
+				break
+			}
+		}
+	}
+	return ruleIDs
+}
+
+// getDNSPacket returns the IPs and the UDP packet of a DNS response carried by
+// a packet-in message.
+func getDNSPacket(pktIn *ofctrl.PacketIn) (net.IP, net.IP, *protocol.UDP, error) {
+	var srcIP, dstIP net.IP
+	var udpPkt *protocol.UDP
+	var ok bool
+	switch ipPkt := pktIn.Data.Data.(type) {
+	case *protocol.IPv4:
+		srcIP, dstIP = ipPkt.NWSrc, ipPkt.NWDst
+		udpPkt, ok = ipPkt.Data.(*protocol.UDP)
+	case *protocol.IPv6:
+		srcIP, dstIP = ipPkt.NWSrc, ipPkt.NWDst
+		udpPkt, ok = ipPkt.Data.(*protocol.UDP)
+	}
+	if !ok {
+		return nil, nil, nil, errors.New("received DNS packet-in which is not UDP")
+	}
+	return srcIP, dstIP, udpPkt, nil
+}
+
+// handlePacketIn parses the DNS response carried by a packet-in message and
+// learns the IPs it contains. The response is sent back to the pipeline once
+// the rules selecting the learned IPs are realized, or after
+// maxDNSResponseHoldTime.
+func (f *fqdnController) handlePacketIn(pktIn *ofctrl.PacketIn) error {
+	_, _, udpPkt, err := getDNSPacket(pktIn)
+	if err != nil {
+		return err
+	}
+	msg := new(dns.Msg)
+	if err := msg.Unpack(udpPkt.Data); err != nil {
+		// The response is still delivered to the Pod, which may be able
+		// to parse it.
+		if sendErr := f.sendDNSPacketOut(pktIn); sendErr != nil {
+			klog.Errorf("Error sending DNS response packet-out: %v", sendErr)
+		}
+		return fmt.Errorf("error parsing DNS response: %v", err)
+	}
+	waiter := f.onDNSResponse(msg, time.Now())
+	if waiter == nil {
+		return f.sendDNSPacketOut(pktIn)
+	}
+	go func() {
+		select {
+		case <-waiter.done:
+		case <-time.After(maxDNSResponseHoldTime):
+			klog.Warningf("Timed out waiting for the rules to be realized with the IPs learned from a DNS response")
+		}
+		if err := f.sendDNSPacketOut(pktIn); err != nil {
+			klog.Errorf("Error sending DNS response packet-out: %v", err)
+		}
+	}()
+	return nil
+}
+
+// sendDNSPacketOut sends a DNS response held by the DNS interception flows
+// back to the pipeline. It's sent as if it was received from the gateway, as
+// its MAC addresses have already been rewritten for the Pod by the pipeline,
+// and the packets from the Pod ports are checked by the SpoofGuard.
+func (f *fqdnController) sendDNSPacketOut(pktIn *ofctrl.PacketIn) error {
+	srcIP, dstIP, udpPkt, err := getDNSPacket(pktIn)
+	if err != nil {
+		return err
+	}
+	return f.ofClient.SendUDPPacketOut(
+		pktIn.Data.HWSrc.String(),
+		pktIn.Data.HWDst.String(),
+		srcIP.String(),
+		dstIP.String(),
+		uint32(config.HostGatewayOFPort),
+		-1,
+		srcIP.To4() == nil,
+		udpPkt.PortSrc,
+		udpPkt.PortDst,
+		udpPkt.Data,
+		true)
+}
+
+// onDNSResponse records the A and AAAA records of a DNS response. The IPs are
+// recorded both for the names of the records and for the names of the
+// questions, as the names queried by the Pods may be aliases (CNAMEs) of the
+// names of the records. If new IPs are learned, the rules selecting them are
+// notified as dirty, and it returns a waiter which is notified when the rules
+// are realized.
+func (f *fqdnController) onDNSResponse(msg *dns.Msg, now time.Time) *dnsResponseWaiter {
+	if !msg.Response || msg.Rcode != dns.RcodeSuccess {
+		return nil
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	dirtyRules := sets.NewString()
+	addresses := map[string]time.Duration{}
+	for _, rr := range msg.Answer {
+		var ip string
+		switch record := rr.(type) {
+		case *dns.A:
+			ip = record.A.String()
+		case *dns.AAAA:
+			ip = record.AAAA.String()
+		default:
+			continue
+		}
+		ttl := time.Duration(rr.Header().Ttl) * time.Second
+		if ttl < minDNSEntryTTL {
+			ttl = minDNSEntryTTL
+		}
+		if ttl > addresses[ip] {
+			addresses[ip] = ttl
+		}
+		dirtyRules = dirtyRules.Union(f.learnIP(toCanonicalName(rr.Header().Name), ip, ttl, now))
+	}
+	for _, question := range msg.Question {
+		for ip, ttl := range addresses {
+			dirtyRules = dirtyRules.Union(f.learnIP(toCanonicalName(question.Name), ip, ttl, now))
+		}
+	}
+	if len(dirtyRules) == 0 {
+		return nil
+	}
+	// The waiter must be registered before the rules are notified, so that
+	// it's moved to fetchedRuleWaiters when the reconciler gets the IPs.
+	waiter := &dnsResponseWaiter{pendingRules: len(dirtyRules), done: make(chan struct{})}
+	for ruleID := range dirtyRules {
+		f.dirtyRuleWaiters[ruleID] = append(f.dirtyRuleWaiters[ruleID], waiter)
+		f.dirtyRuleHandler(ruleID)
+	}
+	return waiter
+}
+
+// learnIP records an IP for a name until now+ttl, if the name is selected by
+// any rule, and returns the IDs of the rules selecting the name if the IP is
+// new. It must be called with the mutex held.
+func (f *fqdnController) learnIP(name, ip string, ttl time.Duration, now time.Time) sets.String {
+	ruleIDs := f.getRulesForName(name)
+	if len(ruleIDs) == 0 {
+		return nil
+	}
+	entry, exists := f.dnsEntries[name]
+	if !exists {
+		entry = map[string]time.Time{}
+		f.dnsEntries[name] = entry
+	}
+	expiration := now.Add(ttl)
+	if oldExpiration, exists := entry[ip]; exists && oldExpiration.After(now) {
+		if expiration.After(oldExpiration) {
+			entry[ip] = expiration
+			f.expirationQueue.AddAfter(name, ttl)
+		}
+		return nil
+	}
+	klog.V(2).Infof("Learned IP %s for FQDN %s (TTL: %v)", ip, name, ttl)
+	entry[ip] = expiration
+	f.expirationQueue.AddAfter(name, ttl)
+	return ruleIDs
+}
+
+// expireIPs removes the expired IPs of a name, and notifies the rules
+// selecting the name if any IP was removed.
+func (f *fqdnController) expireIPs(name string, now time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	entry, exists := f.dnsEntries[name]
+	if !exists {
+		return
+	}
+	expired := false
+	var nextExpiration time.Time
+	for ip, expiration := range entry {
+		if !expiration.After(now) {
+			klog.V(2).Infof("IP %s for FQDN %s expired", ip, name)
+			delete(entry, ip)
+			expired = true
+		} else if nextExpiration.IsZero() || expiration.Before(nextExpiration) {
+			nextExpiration = expiration
+		}
+	}
+	if len(entry) == 0 {
+		delete(f.dnsEntries, name)
+	} else {
+		// The queue keeps the earliest time when a name is added several
+		// times, so the remaining IPs must be checked again.
+		f.expirationQueue.AddAfter(name, nextExpiration.Sub(now))
+	}
+	if expired {
+		for ruleID := range f.getRulesForName(name) {
+			f.dirtyRuleHandler(ruleID)
+		}
+	}
+}
+
+// runExpirationWorker removes the expired IPs until stopCh is closed.
+func (f *fqdnController) runExpirationWorker(stopCh <-chan struct{}) {
+	defer f.expirationQueue.ShutDown()
+	go wait.Until(f.expirationWorker, time.Second, stopCh)
+	<-stopCh
+}
+
+func (f *fqdnController) expirationWorker() {
+	for {
+		key, quit := f.expirationQueue.Get()
+		if quit {
+			return
+		}
+		f.expireIPs(key.(string), time.Now())
+		f.expirationQueue.Done(key)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/contiv/libOpenflow/protocol"
+	"github.com/contiv/ofnet/ofctrl"
+	"github.com/golang/mock/gomock"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"antrea.io/antrea/pkg/agent/config"
+	"antrea.io/antrea/pkg/agent/interfacestore"
+	openflowtest "antrea.io/antrea/pkg/agent/openflow/testing"
+	"antrea.io/antrea/pkg/agent/types"
+	"antrea.io/antrea/pkg/agent/util"
+	"antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+)
+
+func newTestDNSResponse(question string, answers ...dns.RR) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(question), dns.TypeA)
+	msg.Response = true
+	msg.Answer = answers
+	return msg
+}
+
+func newTestA(name, ip string, ttl uint32) dns.RR {
+	return &dns.A{
+		Hdr: dns.RR_Header{Name: dns.Fqdn(name), Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: ttl},
+		A:   net.ParseIP(ip),
+	}
+}
+
+func newTestCNAME(name, target string, ttl uint32) dns.RR {
+	return &dns.CNAME{
+		Hdr:    dns.RR_Header{Name: dns.Fqdn(name), Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: ttl},
+		Target: dns.Fqdn(target),
+	}
+}
+
+func TestFQDNSelectorMatches(t *testing.T) {
+	tests := []struct {
+		fqdn    string
+		name    string
+		matches bool
+	}{
+		{"www.example.com", "www.example.com", true},
+		{"WWW.Example.com.", "www.example.com", true},
+		{"www.example.com", "example.com", false},
+		{"*.example.com", "www.example.com", true},
+		{"*.example.com", "a.b.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "www.example.com.cn", false},
+		{"*example.com", "myexample.com", true},
+		{"www.example.*", "www.example.org", true},
+		{"www.example.com", "wwwxexample.com", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.matches, newFQDNSelector(tt.fqdn).matches(tt.name), "FQDN %s, name %s", tt.fqdn, tt.name)
+	}
+}
+
+func TestFQDNControllerLearnAndExpire(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	dirtyRules := sets.NewString()
+	f := newFQDNController(mockOFClient, func(ruleID string) { dirtyRules.Insert(ruleID) })
+
+	mockOFClient.EXPECT().InstallDNSInterceptionFlows().Times(1)
+	ips, err := f.addFQDNRule("rule1", []string{"*.example.com"})
+	require.NoError(t, err)
+	assert.Empty(t, ips)
+	ips, err = f.addFQDNRule("rule2", []string{"www.github.com"})
+	require.NoError(t, err)
+	assert.Empty(t, ips)
+
+	now := time.Now()
+	// The IPs of the CNAME target are recorded for the name queried by the Pod.
+	f.onDNSResponse(newTestDNSResponse("www.example.com",
+		newTestCNAME("www.example.com", "cdn.example.net", 300),
+		newTestA("cdn.example.net", "10.0.0.1", 300),
+		newTestA("cdn.example.net", "10.0.0.2", 1)), now)
+	assert.Equal(t, sets.NewString("rule1"), dirtyRules)
+	assert.Equal(t, sets.NewString("10.0.0.1", "10.0.0.2"), f.getIPsForSelectors(f.ruleSelectors["rule1"], now))
+	assert.Empty(t, f.getIPsForSelectors(f.ruleSelectors["rule2"], now))
+	// Only the names selected by the rules are recorded.
+	assert.NotContains(t, f.dnsEntries, "cdn.example.net")
+	dirtyRules.Delete("rule1")
+	assert.Nil(t, f.onDNSResponse(newTestDNSResponse("www.other.com", newTestA("www.other.com", "10.0.2.1", 300)), now))
+	assert.NotContains(t, f.dnsEntries, "www.other.com")
+	assert.Empty(t, dirtyRules)
+
+	// The same response doesn't change the IPs.
+	assert.Nil(t, f.onDNSResponse(newTestDNSResponse("www.example.com", newTestA("www.example.com", "10.0.0.1", 300)), now))
+	assert.Empty(t, dirtyRules)
+
+	// Failed responses are ignored.
+	failedResponse := newTestDNSResponse("www.github.com", newTestA("www.github.com", "10.0.1.1", 300))
+	failedResponse.Rcode = dns.RcodeServerFailure
+	f.onDNSResponse(failedResponse, now)
+	assert.Empty(t, dirtyRules)
+
+	// 10.0.0.2 expires after minDNSEntryTTL, as its TTL is lower.
+	f.expireIPs("www.example.com", now.Add(minDNSEntryTTL-time.Second))
+	assert.Empty(t, dirtyRules)
+	f.expireIPs("www.example.com", now.Add(minDNSEntryTTL))
+	assert.Equal(t, sets.NewString("rule1"), dirtyRules)
+	assert.Equal(t, sets.NewString("10.0.0.1"), f.getIPsForSelectors(f.ruleSelectors["rule1"], now.Add(minDNSEntryTTL)))
+
+	// The names are removed with the last rule selecting them.
+	require.NoError(t, f.deleteFQDNRule("rule1"))
+	assert.Empty(t, f.dnsEntries)
+	mockOFClient.EXPECT().UninstallDNSInterceptionFlows().Times(1)
+	require.NoError(t, f.deleteFQDNRule("rule2"))
+}
+
+func newTestDNSPacketIn(t *testing.T, msg *dns.Msg) *ofctrl.PacketIn {
+	data, err := msg.Pack()
+	require.NoError(t, err)
+	srcMAC, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	dstMAC, _ := net.ParseMAC("aa:bb:cc:dd:ee:00")
+	return &ofctrl.PacketIn{
+		Data: protocol.Ethernet{
+			HWSrc: srcMAC,
+			HWDst: dstMAC,
+			Data: &protocol.IPv4{
+				NWSrc: net.ParseIP("10.96.0.10").To4(),
+				NWDst: net.ParseIP("10.10.0.2").To4(),
+				Data:  &protocol.UDP{PortSrc: 53, PortDst: 34567, Data: data},
+			},
+		},
+	}
+}
+
+func TestFQDNControllerHoldDNSResponse(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	dirtyRules := sets.NewString()
+	f := newFQDNController(mockOFClient, func(ruleID string) { dirtyRules.Insert(ruleID) })
+
+	mockOFClient.EXPECT().InstallDNSInterceptionFlows()
+	_, err := f.addFQDNRule("rule1", []string{"www.example.com"})
+	require.NoError(t, err)
+
+	pktIn := newTestDNSPacketIn(t, newTestDNSResponse("www.example.com", newTestA("www.example.com", "10.0.0.1", 300)))
+	sent := make(chan struct{})
+	mockOFClient.EXPECT().SendUDPPacketOut("aa:bb:cc:dd:ee:ff", "aa:bb:cc:dd:ee:00", "10.96.0.10", "10.10.0.2", uint32(config.HostGatewayOFPort),
+		int32(-1), false, uint16(53), uint16(34567), pktIn.Data.Data.(*protocol.IPv4).Data.(*protocol.UDP).Data, true).
+		Do(func(string, string, string, string, uint32, int32, bool, uint16, uint16, []byte, bool) { close(sent) })
+	require.NoError(t, f.handlePacketIn(pktIn))
+	assert.Equal(t, sets.NewString("rule1"), dirtyRules)
+
+	// The response is held until the rule is realized with the learned IP.
+	select {
+	case <-sent:
+		t.Fatal("The DNS response should be held until the rule is realized")
+	case <-time.After(100 * time.Millisecond):
+	}
+	ips, err := f.addFQDNRule("rule1", []string{"www.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, sets.NewString("10.0.0.1"), ips)
+	f.ruleRealized("rule1")
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("The DNS response should be sent once the rule is realized")
+	}
+
+	// The response which doesn't change the IPs is sent back immediately.
+	mockOFClient.EXPECT().SendUDPPacketOut(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), true)
+	require.NoError(t, f.handlePacketIn(pktIn))
+}
+
+func TestReconcilerReconcileFQDNRule(t *testing.T) {
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
+		InterfaceName:            util.GenerateContainerInterfaceName("pod1", "ns1", "container1"),
+		IPs:                      []net.IP{net.ParseIP("2.2.2.2")},
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{PodName: "pod1", PodNamespace: "ns1", ContainerID: "container1"},
+		OVSPortConfig:            &interfacestore.OVSPortConfig{OFPort: 1},
+	})
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockOFClient.EXPECT().IsIPv4Enabled().Return(true).AnyTimes()
+	mockOFClient.EXPECT().IsIPv6Enabled().Return(false).AnyTimes()
+	r := newReconciler(mockOFClient, ifaceStore, testAsyncDeleteInterval)
+	r.fqdnController = newFQDNController(mockOFClient, func(string) {})
+
+	rule := &CompletedRule{
+		rule: &rule{
+			ID:             "egress-rule",
+			Direction:      v1beta2.DirectionOut,
+			To:             v1beta2.NetworkPolicyPeer{FQDNs: []string{"*.example.com"}},
+			Services:       services1,
+			PolicyPriority: &policyPriority,
+			TierPriority:   &tierPriority,
+			SourceRef:      &cnp1,
+		},
+		TargetMembers: appliedToGroup1,
+	}
+	mockOFClient.EXPECT().InstallDNSInterceptionFlows()
+	mockOFClient.EXPECT().InstallPolicyRuleFlows(gomock.Any()).Do(func(ofRule *types.PolicyRule) {
+		assert.Equal(t, ipsToOFAddresses(sets.NewString("2.2.2.2")), ofRule.From)
+		assert.Empty(t, ofRule.To)
+	})
+	require.NoError(t, r.Reconcile(rule))
+
+	// A new IP of the FQDN is added to the installed rule, and the DNS
+	// response is released once the rule is realized.
+	waiter := r.fqdnController.onDNSResponse(newTestDNSResponse("api.example.com", newTestA("api.example.com", "10.0.0.2", 300)), time.Now())
+	require.NotNil(t, waiter)
+	mockOFClient.EXPECT().AddPolicyRuleAddress(gomock.Any(), types.DstAddress, gomock.Eq(ipsToOFAddresses(sets.NewString("10.0.0.2"))), gomock.Any())
+	require.NoError(t, r.Reconcile(rule))
+	select {
+	case <-waiter.done:
+	default:
+		t.Fatal("The DNS response should be released once the rule is realized")
+	}
+
+	// The rule is unregistered from the FQDN controller when its FQDNs are
+	// removed.
+	ruleCopy := *rule.rule
+	updatedRule := &CompletedRule{rule: &ruleCopy, TargetMembers: rule.TargetMembers}
+	updatedRule.To = v1beta2.NetworkPolicyPeer{IPBlocks: []v1beta2.IPBlock{
+		{CIDR: v1beta2.IPNet{IP: v1beta2.IPAddress(net.ParseIP("10.0.1.0").To4()), PrefixLength: 24}},
+	}}
+	mockOFClient.EXPECT().UninstallDNSInterceptionFlows()
+	mockOFClient.EXPECT().DeletePolicyRuleAddress(gomock.Any(), types.DstAddress, gomock.Eq(ipsToOFAddresses(sets.NewString("10.0.0.2"))), gomock.Any())
+	require.NoError(t, r.Reconcile(updatedRule))
+	assert.Empty(t, r.fqdnController.ruleSelectors)
+
+	mockOFClient.EXPECT().UninstallPolicyRuleFlows(gomock.Any())
+	require.NoError(t, r.Forget(rule.ID))
+}
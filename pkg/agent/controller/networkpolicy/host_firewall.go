@@ -0,0 +1,45 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+// hostFirewall enforces the rules applied to the local Node on the traffic
+// received and sent by its host network namespace, whichever interface the
+// traffic goes through, e.g. the host gateway interface or the uplink
+// interface. The Openflow pipeline only sees the traffic of the Pods.
+type hostFirewall interface {
+	// batchAddRules enforces the provided rules and removes the rules which
+	// were enforced before, including the ones of the previous run of the
+	// agent. It's called once after the agent starts.
+	batchAddRules(rules []*CompletedRule) error
+	// addOrUpdateRule enforces the provided rule, or updates it if it's
+	// already enforced.
+	addOrUpdateRule(rule *CompletedRule) error
+	// deleteRule stops enforcing the rule. It's a no-op if the rule is not
+	// enforced.
+	deleteRule(ruleID string) error
+}
+
+// isHostRule returns whether the rule is applied to the local Node, in which
+// case it's enforced by the hostFirewall instead of the Openflow pipeline.
+// The controller only disseminates the Node member of the local Node, and it
+// doesn't allow a policy to be applied to both Nodes and other workloads.
+func isHostRule(rule *CompletedRule) bool {
+	for _, m := range rule.TargetMembers {
+		if m.Node != nil {
+			return true
+		}
+	}
+	return false
+}
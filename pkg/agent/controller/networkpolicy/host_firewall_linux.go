@@ -0,0 +1,565 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"antrea.io/antrea/pkg/agent/util/ipset"
+	"antrea.io/antrea/pkg/agent/util/iptables"
+	"antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	"antrea.io/antrea/pkg/util/ip"
+)
+
+const (
+	// hostIngressChain and hostEgressChain are the chains of the filter
+	// table enforcing the rules applied to the local Node. They are jumped
+	// to from the INPUT and OUTPUT chains, so that they see all the traffic
+	// received and sent by the host network namespace.
+	hostIngressChain = "ANTREA-HOST-INGRESS"
+	hostEgressChain  = "ANTREA-HOST-EGRESS"
+	// hostIPSetPrefix is the prefix of the ipsets storing the peers of the
+	// rules, which are named after the rule IDs.
+	hostIPSetPrefix = "ANTREA-HOST-"
+)
+
+// hostRulePeers are the peers of a rule in one address family.
+type hostRulePeers struct {
+	// matchAll is true if the peers include all the addresses of the family,
+	// in which case the rule doesn't match the addresses.
+	matchAll bool
+	// cidrs are the IPs and CIDRs of the peers, which are stored in the
+	// ipset of the rule.
+	cidrs sets.String
+}
+
+// needsIPSet returns whether the addresses of the peers must be matched with
+// an ipset.
+func (p *hostRulePeers) needsIPSet() bool {
+	return !p.matchAll && len(p.cidrs) > 0
+}
+
+// hostRule is a rule enforced by the iptablesHostFirewall.
+type hostRule struct {
+	*CompletedRule
+	// peers are indexed by whether the address family is IPv6.
+	peers map[bool]*hostRulePeers
+}
+
+func newHostRule(rule *CompletedRule) *hostRule {
+	members, ipBlocks := rule.FromAddresses, rule.From.IPBlocks
+	if rule.Direction == v1beta2.DirectionOut {
+		members, ipBlocks = rule.ToAddresses, rule.To.IPBlocks
+	}
+	peers := map[bool]*hostRulePeers{
+		false: {cidrs: sets.NewString()},
+		true:  {cidrs: sets.NewString()},
+	}
+	for _, m := range members {
+		for _, ipAddr := range m.IPs {
+			memberIP := net.IP(ipAddr)
+			peers[memberIP.To4() == nil].cidrs.Insert(memberIP.String())
+		}
+	}
+	for _, b := range ipBlocks {
+		blockCIDR := ip.IPNetToNetIPNet(&b.CIDR)
+		exceptIPNets := make([]*net.IPNet, 0, len(b.Except))
+		for i := range b.Except {
+			exceptIPNets = append(exceptIPNets, ip.IPNetToNetIPNet(&b.Except[i]))
+		}
+		diffCIDRs, err := ip.DiffFromCIDRs(blockCIDR, exceptIPNets)
+		if err != nil {
+			klog.Errorf("Error when determining diffCIDRs: %v", err)
+			continue
+		}
+		for _, d := range diffCIDRs {
+			p := peers[d.IP.To4() == nil]
+			// The ipsets of type hash:net can't store CIDRs with prefix
+			// length 0.
+			if ones, _ := d.Mask.Size(); ones == 0 {
+				p.matchAll = true
+			} else {
+				p.cidrs.Insert(d.String())
+			}
+		}
+	}
+	return &hostRule{CompletedRule: rule, peers: peers}
+}
+
+// hostChains returns the host chain of a rule and the chain of its Tier, which
+// is named after the host chain and the priority of the Tier, e.g.
+// ANTREA-HOST-INGRESS-250.
+func hostChains(rule *hostRule) (string, string) {
+	hostChain := hostIngressChain
+	if rule.Direction == v1beta2.DirectionOut {
+		hostChain = hostEgressChain
+	}
+	return hostChain, fmt.Sprintf("%s-%d", hostChain, *rule.TierPriority)
+}
+
+// hostIPSetName returns the name of the ipset storing the peers of a rule.
+// The name is at most 30 characters, which is under the limit of ipset.
+func hostIPSetName(ruleID string, isIPv6 bool) string {
+	if isIPv6 {
+		return hostIPSetPrefix + ruleID + "-6"
+	}
+	return hostIPSetPrefix + ruleID
+}
+
+// iptablesHostFirewall implements hostFirewall with iptables. The rules of
+// each Tier are written to a chain of the Tier in the order of their
+// priorities, each rule matching its peers with an ipset. The chains are
+// rewritten with iptables-restore whenever the rules change.
+type iptablesHostFirewall struct {
+	ipv4Enabled bool
+	ipv6Enabled bool
+	mutex       sync.Mutex
+	// ipt is created when the rules are enforced for the first time.
+	ipt *iptables.Client
+	// rules is a mapping from rule ID to *hostRule.
+	rules map[string]*hostRule
+	// ipSets caches the entries of the ipsets of the rules, so that only the
+	// changed entries are added and deleted.
+	ipSets map[string]sets.String
+	// tierChains are the chains of the Tiers which may exist, so that the
+	// ones of the Tiers without rules can be deleted.
+	tierChains sets.String
+}
+
+func newHostFirewall(ipv4Enabled, ipv6Enabled bool) hostFirewall {
+	return &iptablesHostFirewall{
+		ipv4Enabled: ipv4Enabled,
+		ipv6Enabled: ipv6Enabled,
+		rules:       map[string]*hostRule{},
+		ipSets:      map[string]sets.String{},
+		tierChains:  sets.NewString(),
+	}
+}
+
+// initialize creates the host chains and links them to the built-in chains.
+// The jump rules are inserted at the beginning of the built-in chains, so that
+// the traffic accepted by other rules of the built-in chains is still checked.
+func (f *iptablesHostFirewall) initialize() error {
+	if f.ipt != nil {
+		return nil
+	}
+	ipt, err := iptables.New(f.ipv4Enabled, f.ipv6Enabled)
+	if err != nil {
+		return fmt.Errorf("error creating IPTables instance: %v", err)
+	}
+	jumpRules := []struct{ srcChain, dstChain, comment string }{
+		{iptables.InputChain, hostIngressChain, "Antrea: jump to Antrea host ingress rules"},
+		{iptables.OutputChain, hostEgressChain, "Antrea: jump to Antrea host egress rules"},
+	}
+	for _, rule := range jumpRules {
+		if err := ipt.EnsureChain(iptables.FilterTable, rule.dstChain); err != nil {
+			return err
+		}
+		ruleSpec := []string{"-j", rule.dstChain, "-m", "comment", "--comment", rule.comment}
+		if err := ipt.InsertRule(iptables.ProtocolDual, iptables.FilterTable, rule.srcChain, ruleSpec); err != nil {
+			return err
+		}
+	}
+	// The chains of the Tiers may have been created by the previous run of
+	// the agent.
+	iptablesData, err := ipt.Save()
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(iptablesData), "\n") {
+		if strings.HasPrefix(line, ":"+hostIngressChain+"-") || strings.HasPrefix(line, ":"+hostEgressChain+"-") {
+			f.tierChains.Insert(strings.Fields(line[1:])[0])
+		}
+	}
+	f.ipt = ipt
+	return nil
+}
+
+func (f *iptablesHostFirewall) batchAddRules(rules []*CompletedRule) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.rules = map[string]*hostRule{}
+	for _, rule := range rules {
+		f.rules[rule.ID] = newHostRule(rule)
+	}
+	if err := f.sync(); err != nil {
+		return err
+	}
+	// Delete the ipsets of the rules which were deleted while the agent was
+	// not running.
+	names, err := ipset.ListIPSets()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, exists := f.ipSets[name]; !exists && strings.HasPrefix(name, hostIPSetPrefix) {
+			if err := ipset.DestroyIPSet(name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *iptablesHostFirewall) addOrUpdateRule(rule *CompletedRule) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.rules[rule.ID] = newHostRule(rule)
+	return f.sync()
+}
+
+func (f *iptablesHostFirewall) deleteRule(ruleID string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	// The host chains are rewritten even if the rule is not in f.rules, as
+	// the previous attempt to delete it may have failed.
+	delete(f.rules, ruleID)
+	return f.sync()
+}
+
+// sync realizes the rules in f.rules. The ipsets are updated before the host
+// chains referencing them are rewritten, and the ipsets which are not needed
+// anymore are destroyed after that.
+func (f *iptablesHostFirewall) sync() error {
+	if err := f.initialize(); err != nil {
+		return err
+	}
+	var families []bool
+	if f.ipv4Enabled {
+		families = append(families, false)
+	}
+	if f.ipv6Enabled {
+		families = append(families, true)
+	}
+	desiredIPSets := sets.NewString()
+	for _, rule := range f.rules {
+		for _, isIPv6 := range families {
+			if peers := rule.peers[isIPv6]; peers.needsIPSet() {
+				name := hostIPSetName(rule.ID, isIPv6)
+				if err := f.syncIPSet(name, peers.cidrs, isIPv6); err != nil {
+					return err
+				}
+				desiredIPSets.Insert(name)
+			}
+		}
+	}
+	rules := make([]*hostRule, 0, len(f.rules))
+	desiredTierChains := sets.NewString()
+	for _, rule := range f.rules {
+		rules = append(rules, rule)
+		_, tierChain := hostChains(rule)
+		desiredTierChains.Insert(tierChain)
+	}
+	sortHostRules(rules)
+	staleTierChains := f.tierChains.Difference(desiredTierChains).List()
+	for _, isIPv6 := range families {
+		iptablesData := buildHostChainsData(rules, staleTierChains, isIPv6)
+		// Setting --noflush to keep the previous contents (i.e. non antrea managed chains) of the table.
+		if err := f.ipt.Restore(iptablesData.Bytes(), false, isIPv6); err != nil {
+			return err
+		}
+	}
+	f.tierChains = desiredTierChains
+	for name := range f.ipSets {
+		if desiredIPSets.Has(name) {
+			continue
+		}
+		if err := ipset.DestroyIPSet(name); err != nil {
+			return err
+		}
+		delete(f.ipSets, name)
+	}
+	return nil
+}
+
+// syncIPSet creates the ipset if it doesn't exist and makes its entries the
+// provided ones.
+func (f *iptablesHostFirewall) syncIPSet(name string, entries sets.String, isIPv6 bool) error {
+	existingEntries, exists := f.ipSets[name]
+	if !exists {
+		if err := ipset.CreateIPSet(name, ipset.HashNet, isIPv6); err != nil {
+			return err
+		}
+		// The ipset may have been created by the previous run of the agent.
+		entryList, err := ipset.ListEntries(name)
+		if err != nil {
+			return err
+		}
+		existingEntries = sets.NewString(entryList...)
+		f.ipSets[name] = existingEntries
+	}
+	for entry := range entries.Difference(existingEntries) {
+		if err := ipset.AddEntry(name, entry); err != nil {
+			return err
+		}
+		existingEntries.Insert(entry)
+	}
+	for entry := range existingEntries.Difference(entries) {
+		if err := ipset.DelEntry(name, entry); err != nil {
+			return err
+		}
+		existingEntries.Delete(entry)
+	}
+	return nil
+}
+
+// sortHostRules sorts the rules in the order they are evaluated: by the
+// priorities of their Tiers, then the priorities of their policies, then their
+// priorities within the policies. The rule ID is used as the last criterion so
+// that the generated chains are stable.
+func sortHostRules(rules []*hostRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if *a.TierPriority != *b.TierPriority {
+			return *a.TierPriority < *b.TierPriority
+		}
+		if *a.PolicyPriority != *b.PolicyPriority {
+			return *a.PolicyPriority < *b.PolicyPriority
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	})
+}
+
+// buildHostChainsData returns the iptables-restore data of the host chains
+// enforcing the provided sorted rules in the given address family, and deleting
+// the provided stale chains of the Tiers. The host chains go to the chain of the
+// first Tier, and the chain of each Tier goes to the chain of the next Tier
+// after its rules. As the chains of the Tiers are never jumped to, returning
+// from them returns to the built-in chains. The traffic matching no rule
+// returns to the built-in chains, as the policies applied to Nodes don't
+// isolate them by default.
+func buildHostChainsData(rules []*hostRule, staleTierChains []string, isIPv6 bool) *bytes.Buffer {
+	// tierChains are the chains of the Tiers of each host chain in the order
+	// they are evaluated.
+	tierChains := map[string][]string{}
+	tierRules := map[string][]*hostRule{}
+	for _, rule := range rules {
+		hostChain, tierChain := hostChains(rule)
+		if _, exists := tierRules[tierChain]; !exists {
+			tierChains[hostChain] = append(tierChains[hostChain], tierChain)
+		}
+		tierRules[tierChain] = append(tierRules[tierChain], rule)
+	}
+	iptablesData := bytes.NewBuffer(nil)
+	writeLine(iptablesData, "*filter")
+	for _, hostChain := range []string{hostIngressChain, hostEgressChain} {
+		writeLine(iptablesData, iptables.MakeChainLine(hostChain))
+		for _, tierChain := range tierChains[hostChain] {
+			writeLine(iptablesData, iptables.MakeChainLine(tierChain))
+		}
+	}
+	// The stale chains are flushed before being deleted.
+	for _, tierChain := range staleTierChains {
+		writeLine(iptablesData, iptables.MakeChainLine(tierChain))
+	}
+	// The loopback traffic and the reply traffic of the allowed connections
+	// are always allowed, so that the host processes keep working and the
+	// rules only need to allow the traffic in the initiating direction.
+	for _, chain := range []struct{ name, loopbackMatch string }{
+		{hostIngressChain, "-i"},
+		{hostEgressChain, "-o"},
+	} {
+		writeLine(iptablesData, []string{
+			"-A", chain.name, chain.loopbackMatch, "lo",
+			"-m", "comment", "--comment", `"Antrea: allow loopback traffic"`,
+			"-j", iptables.ReturnTarget,
+		}...)
+		writeLine(iptablesData, []string{
+			"-A", chain.name,
+			"-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED",
+			"-m", "comment", "--comment", `"Antrea: allow established traffic"`,
+			"-j", iptables.ReturnTarget,
+		}...)
+		if len(tierChains[chain.name]) > 0 {
+			writeLine(iptablesData, "-A", chain.name, "-g", tierChains[chain.name][0])
+		}
+	}
+	for _, hostChain := range []string{hostIngressChain, hostEgressChain} {
+		chains := tierChains[hostChain]
+		for i, tierChain := range chains {
+			var nextTierChain string
+			if i+1 < len(chains) {
+				nextTierChain = chains[i+1]
+			}
+			for _, rule := range tierRules[tierChain] {
+				writeHostRule(iptablesData, rule, tierChain, nextTierChain, isIPv6)
+			}
+			if nextTierChain != "" {
+				writeLine(iptablesData, "-A", tierChain, "-g", nextTierChain)
+			}
+		}
+	}
+	for _, tierChain := range staleTierChains {
+		writeLine(iptablesData, "-X", tierChain)
+	}
+	writeLine(iptablesData, "COMMIT")
+	return iptablesData
+}
+
+// writeHostRule writes the iptables rules of a rule to the chain of its Tier
+// in the given address family. nextTierChain is the chain of the next Tier, or
+// empty if the Tier is the last one. Nothing is written if the rule has no peer
+// or no Service in the family.
+func writeHostRule(iptablesData *bytes.Buffer, rule *hostRule, tierChain, nextTierChain string, isIPv6 bool) {
+	peers := rule.peers[isIPv6]
+	if !peers.matchAll && len(peers.cidrs) == 0 {
+		return
+	}
+	ipSetDirection := "src"
+	if rule.Direction == v1beta2.DirectionOut {
+		ipSetDirection = "dst"
+	}
+	var peerMatch []string
+	if peers.needsIPSet() {
+		peerMatch = []string{"-m", "set", "--match-set", hostIPSetName(rule.ID, isIPv6), ipSetDirection}
+	}
+	comment := []string{"-m", "comment", "--comment", fmt.Sprintf(`"Antrea: %s rule %s"`, rule.SourceRef.ToString(), rule.ID)}
+	for _, serviceMatch := range hostServiceMatches(rule.Services, isIPv6) {
+		// The first option of the match of a Service is the protocol.
+		var protocol string
+		if len(serviceMatch) > 0 {
+			protocol = serviceMatch[1]
+		}
+		for _, target := range hostRuleTargets(rule, protocol, nextTierChain) {
+			var words []string
+			words = append(words, "-A", tierChain)
+			words = append(words, serviceMatch...)
+			words = append(words, peerMatch...)
+			words = append(words, comment...)
+			words = append(words, target...)
+			writeLine(iptablesData, words...)
+		}
+	}
+}
+
+// hostServiceMatches returns the iptables matches of the Services in the given
+// address family. An empty match is returned if there is no Service, which
+// means all traffic. The Services with named ports are ignored as the host
+// network namespace has no named port.
+func hostServiceMatches(services []v1beta2.Service, isIPv6 bool) [][]string {
+	if len(services) == 0 {
+		return [][]string{{}}
+	}
+	var matches [][]string
+	for _, svc := range services {
+		protocol := v1beta2.ProtocolTCP
+		if svc.Protocol != nil {
+			protocol = *svc.Protocol
+		}
+		var match []string
+		switch protocol {
+		case v1beta2.ProtocolTCP, v1beta2.ProtocolUDP, v1beta2.ProtocolSCTP:
+			match = []string{"-p", strings.ToLower(string(protocol))}
+			if svc.Port != nil {
+				if svc.Port.Type == intstr.String {
+					klog.V(2).Infof("Ignoring named port %s which can't be resolved for the Node", svc.Port.StrVal)
+					continue
+				}
+				port := fmt.Sprintf("%d", svc.Port.IntVal)
+				if svc.EndPort != nil {
+					port = fmt.Sprintf("%d:%d", svc.Port.IntVal, *svc.EndPort)
+				}
+				match = append(match, "--dport", port)
+			}
+		case v1beta2.ProtocolICMP, v1beta2.ProtocolICMPv6:
+			if (protocol == v1beta2.ProtocolICMPv6) != isIPv6 {
+				continue
+			}
+			typeOption := "--icmp-type"
+			match = []string{"-p", "icmp"}
+			if isIPv6 {
+				typeOption = "--icmpv6-type"
+				match = []string{"-p", "ipv6-icmp"}
+			}
+			if svc.ICMPType != nil {
+				icmpType := fmt.Sprintf("%d", *svc.ICMPType)
+				if svc.ICMPCode != nil {
+					icmpType = fmt.Sprintf("%d/%d", *svc.ICMPType, *svc.ICMPCode)
+				}
+				match = append(match, typeOption, icmpType)
+			}
+		default:
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// hostRuleTargets returns the iptables targets of a rule for the traffic of
+// the given protocol, each of them making an iptables rule. The traffic is logged first if the rule enables logging.
+// The rules in Audit mode only log the traffic. The Allow action returns to the
+// built-in chains, as there is no K8s NetworkPolicy applied to Nodes. The Pass
+// action skips the rest of the Tier by going to nextTierChain, or returns to the
+// built-in chains if the Tier is the last one.
+func hostRuleTargets(rule *hostRule, protocol, nextTierChain string) [][]string {
+	auditMode := rule.EnforcementMode == crdv1alpha1.RuleEnforcementModeAudit
+	var targets [][]string
+	if rule.EnableLogging || auditMode {
+		action := string(*rule.Action)
+		if auditMode {
+			action = string(crdv1alpha1.RuleEnforcementModeAudit)
+		}
+		// The log prefix is at most 29 characters.
+		prefix := fmt.Sprintf(`"Antrea %s %s: "`, rule.ID[:8], action)
+		targets = append(targets, []string{"-j", iptables.LogTarget, "--log-prefix", prefix})
+	}
+	if auditMode {
+		return targets
+	}
+	switch *rule.Action {
+	case crdv1alpha1.RuleActionDrop:
+		targets = append(targets, []string{"-j", iptables.DropTarget})
+	case crdv1alpha1.RuleActionReject:
+		// The TCP connections are reset, the other traffic is rejected
+		// with ICMP port unreachable messages.
+		switch protocol {
+		case "tcp":
+			targets = append(targets, []string{"-j", iptables.RejectTarget, "--reject-with", "tcp-reset"})
+		case "":
+			targets = append(targets, []string{"-p", "tcp", "-j", iptables.RejectTarget, "--reject-with", "tcp-reset"})
+			targets = append(targets, []string{"-j", iptables.RejectTarget})
+		default:
+			targets = append(targets, []string{"-j", iptables.RejectTarget})
+		}
+	case crdv1alpha1.RuleActionPass:
+		if nextTierChain != "" {
+			targets = append(targets, []string{"-g", nextTierChain})
+		} else {
+			targets = append(targets, []string{"-j", iptables.ReturnTarget})
+		}
+	default:
+		targets = append(targets, []string{"-j", iptables.ReturnTarget})
+	}
+	return targets
+}
+
+func writeLine(buf *bytes.Buffer, words ...string) {
+	buf.WriteString(strings.Join(words, " "))
+	buf.WriteByte('\n')
+}
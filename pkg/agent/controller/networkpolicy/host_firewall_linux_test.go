@@ -0,0 +1,211 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+)
+
+func newIPBlock(cidr string, excepts ...string) v1beta2.IPBlock {
+	ipNet := newCIDR(cidr)
+	prefixLength, _ := ipNet.Mask.Size()
+	block := v1beta2.IPBlock{CIDR: v1beta2.IPNet{IP: v1beta2.IPAddress(ipNet.IP), PrefixLength: int32(prefixLength)}}
+	for _, except := range excepts {
+		exceptNet := newCIDR(except)
+		exceptPrefixLength, _ := exceptNet.Mask.Size()
+		block.Except = append(block.Except, v1beta2.IPNet{IP: v1beta2.IPAddress(exceptNet.IP), PrefixLength: int32(exceptPrefixLength)})
+	}
+	return block
+}
+
+func TestNewHostRule(t *testing.T) {
+	ingressRule := &CompletedRule{
+		rule: &rule{
+			ID:        "ingress-rule",
+			Direction: v1beta2.DirectionIn,
+			From:      v1beta2.NetworkPolicyPeer{IPBlocks: []v1beta2.IPBlock{newIPBlock("10.0.0.0/24", "10.0.0.0/25")}},
+		},
+		FromAddresses: dualAddressGroup1,
+	}
+	peers := newHostRule(ingressRule).peers
+	assert.Equal(t, &hostRulePeers{cidrs: sets.NewString("1.1.1.1", "10.0.0.128/25")}, peers[false])
+	assert.Equal(t, &hostRulePeers{cidrs: sets.NewString("2002:1a23:fb44::1")}, peers[true])
+
+	// The IPBlocks including all addresses don't need an ipset.
+	egressRule := &CompletedRule{
+		rule: &rule{
+			ID:        "egress-rule",
+			Direction: v1beta2.DirectionOut,
+			To:        v1beta2.NetworkPolicyPeer{IPBlocks: []v1beta2.IPBlock{newIPBlock("0.0.0.0/0")}},
+		},
+	}
+	peers = newHostRule(egressRule).peers
+	assert.True(t, peers[false].matchAll)
+	assert.False(t, peers[false].needsIPSet())
+	assert.False(t, peers[true].matchAll)
+	assert.False(t, peers[true].needsIPSet())
+}
+
+func TestBuildHostChainsData(t *testing.T) {
+	tierPriority, baselineTier := int32(250), baselineTierPriority
+	policyPriority := float64(1)
+	protocolICMP := v1beta2.ProtocolICMP
+	icmpType := int32(8)
+	endPort := int32(90)
+	allowAction, rejectAction := crdv1alpha1.RuleActionAllow, crdv1alpha1.RuleActionReject
+	ingressRule := &CompletedRule{
+		rule: &rule{
+			ID:             "ingress-rule0001",
+			Direction:      v1beta2.DirectionIn,
+			Services:       []v1beta2.Service{{Protocol: &protocolTCP, Port: &port80, EndPort: &endPort}, serviceHTTP, {Protocol: &protocolICMP, ICMPType: &icmpType}},
+			Action:         &allowAction,
+			Priority:       1,
+			PolicyPriority: &policyPriority,
+			TierPriority:   &tierPriority,
+			SourceRef:      &cnp1,
+		},
+		FromAddresses: dualAddressGroup1,
+	}
+	egressRule := &CompletedRule{
+		rule: &rule{
+			ID:             "egress-rule00001",
+			Direction:      v1beta2.DirectionOut,
+			To:             v1beta2.NetworkPolicyPeer{IPBlocks: []v1beta2.IPBlock{newIPBlock("0.0.0.0/0")}},
+			Action:         &rejectAction,
+			Priority:       0,
+			PolicyPriority: &policyPriority,
+			TierPriority:   &baselineTier,
+			SourceRef:      &cnp1,
+			EnableLogging:  true,
+		},
+	}
+	auditRule := &CompletedRule{
+		rule: &rule{
+			ID:              "audit-rule000001",
+			Direction:       v1beta2.DirectionOut,
+			To:              v1beta2.NetworkPolicyPeer{IPBlocks: []v1beta2.IPBlock{newIPBlock("10.0.0.0/8")}},
+			Services:        services1,
+			Action:          &rejectAction,
+			Priority:        0,
+			PolicyPriority:  &policyPriority,
+			TierPriority:    &tierPriority,
+			SourceRef:       &cnp1,
+			EnforcementMode: crdv1alpha1.RuleEnforcementModeAudit,
+		},
+	}
+	rules := []*hostRule{newHostRule(egressRule), newHostRule(ingressRule), newHostRule(auditRule)}
+	sortHostRules(rules)
+	assert.Equal(t, []string{auditRule.ID, ingressRule.ID, egressRule.ID}, []string{rules[0].ID, rules[1].ID, rules[2].ID})
+
+	header := `*filter
+:ANTREA-HOST-INGRESS - [0:0]
+:ANTREA-HOST-INGRESS-250 - [0:0]
+:ANTREA-HOST-EGRESS - [0:0]
+:ANTREA-HOST-EGRESS-250 - [0:0]
+:ANTREA-HOST-EGRESS-253 - [0:0]
+:ANTREA-HOST-INGRESS-100 - [0:0]
+-A ANTREA-HOST-INGRESS -i lo -m comment --comment "Antrea: allow loopback traffic" -j RETURN
+-A ANTREA-HOST-INGRESS -m conntrack --ctstate ESTABLISHED,RELATED -m comment --comment "Antrea: allow established traffic" -j RETURN
+-A ANTREA-HOST-INGRESS -g ANTREA-HOST-INGRESS-250
+-A ANTREA-HOST-EGRESS -o lo -m comment --comment "Antrea: allow loopback traffic" -j RETURN
+-A ANTREA-HOST-EGRESS -m conntrack --ctstate ESTABLISHED,RELATED -m comment --comment "Antrea: allow established traffic" -j RETURN
+-A ANTREA-HOST-EGRESS -g ANTREA-HOST-EGRESS-250
+`
+	// The stale chain of a Tier is flushed and deleted.
+	staleTierChains := []string{"ANTREA-HOST-INGRESS-100"}
+	expectedIPv4Data := header + `-A ANTREA-HOST-INGRESS-250 -p tcp --dport 80:90 -m set --match-set ANTREA-HOST-ingress-rule0001 src -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule ingress-rule0001" -j RETURN
+-A ANTREA-HOST-INGRESS-250 -p icmp --icmp-type 8 -m set --match-set ANTREA-HOST-ingress-rule0001 src -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule ingress-rule0001" -j RETURN
+-A ANTREA-HOST-EGRESS-250 -p tcp --dport 80 -m set --match-set ANTREA-HOST-audit-rule000001 dst -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule audit-rule000001" -j LOG --log-prefix "Antrea audit-ru Audit: "
+-A ANTREA-HOST-EGRESS-250 -g ANTREA-HOST-EGRESS-253
+-A ANTREA-HOST-EGRESS-253 -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule egress-rule00001" -j LOG --log-prefix "Antrea egress-r Reject: "
+-A ANTREA-HOST-EGRESS-253 -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule egress-rule00001" -p tcp -j REJECT --reject-with tcp-reset
+-A ANTREA-HOST-EGRESS-253 -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule egress-rule00001" -j REJECT
+-X ANTREA-HOST-INGRESS-100
+COMMIT
+`
+	assert.Equal(t, expectedIPv4Data, buildHostChainsData(rules, staleTierChains, false).String())
+
+	// Only the ingress rule has IPv6 peers, and its ICMP Service doesn't apply to IPv6.
+	expectedIPv6Data := header + `-A ANTREA-HOST-INGRESS-250 -p tcp --dport 80:90 -m set --match-set ANTREA-HOST-ingress-rule0001-6 src -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule ingress-rule0001" -j RETURN
+-A ANTREA-HOST-EGRESS-250 -g ANTREA-HOST-EGRESS-253
+-X ANTREA-HOST-INGRESS-100
+COMMIT
+`
+	assert.Equal(t, expectedIPv6Data, buildHostChainsData(rules, staleTierChains, true).String())
+}
+
+func TestBuildHostChainsDataWithPass(t *testing.T) {
+	securityOpsTier, applicationTier, baselineTier := int32(5), int32(100), baselineTierPriority
+	policyPriority := float64(1)
+	passAction, dropAction := crdv1alpha1.RuleActionPass, crdv1alpha1.RuleActionDrop
+	newRule := func(id string, action *crdv1alpha1.RuleAction, tierPriority *int32) *CompletedRule {
+		return &CompletedRule{
+			rule: &rule{
+				ID:             id,
+				Direction:      v1beta2.DirectionIn,
+				Services:       []v1beta2.Service{{Protocol: &protocolTCP, Port: &port80}},
+				Action:         action,
+				PolicyPriority: &policyPriority,
+				TierPriority:   tierPriority,
+				SourceRef:      &cnp1,
+			},
+			FromAddresses: dualAddressGroup1,
+		}
+	}
+	// The Pass rule of the higher Tier skips the rest of its Tier, but the
+	// traffic is still dropped by the rule of the lower Tier.
+	passRule := newRule("pass-rule0000001", &passAction, &securityOpsTier)
+	shadowedDropRule := newRule("drop-rule0000001", &dropAction, &securityOpsTier)
+	shadowedDropRule.Priority = 1
+	dropRule := newRule("drop-rule0000002", &dropAction, &applicationTier)
+	// The Pass rule of the last Tier returns to the built-in chains.
+	baselinePassRule := newRule("pass-rule0000002", &passAction, &baselineTier)
+	rules := []*hostRule{newHostRule(baselinePassRule), newHostRule(dropRule), newHostRule(shadowedDropRule), newHostRule(passRule)}
+	sortHostRules(rules)
+
+	expectedData := `*filter
+:ANTREA-HOST-INGRESS - [0:0]
+:ANTREA-HOST-INGRESS-5 - [0:0]
+:ANTREA-HOST-INGRESS-100 - [0:0]
+:ANTREA-HOST-INGRESS-253 - [0:0]
+:ANTREA-HOST-EGRESS - [0:0]
+-A ANTREA-HOST-INGRESS -i lo -m comment --comment "Antrea: allow loopback traffic" -j RETURN
+-A ANTREA-HOST-INGRESS -m conntrack --ctstate ESTABLISHED,RELATED -m comment --comment "Antrea: allow established traffic" -j RETURN
+-A ANTREA-HOST-INGRESS -g ANTREA-HOST-INGRESS-5
+-A ANTREA-HOST-EGRESS -o lo -m comment --comment "Antrea: allow loopback traffic" -j RETURN
+-A ANTREA-HOST-EGRESS -m conntrack --ctstate ESTABLISHED,RELATED -m comment --comment "Antrea: allow established traffic" -j RETURN
+-A ANTREA-HOST-INGRESS-5 -p tcp --dport 80 -m set --match-set ANTREA-HOST-pass-rule0000001 src -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule pass-rule0000001" -g ANTREA-HOST-INGRESS-100
+-A ANTREA-HOST-INGRESS-5 -p tcp --dport 80 -m set --match-set ANTREA-HOST-drop-rule0000001 src -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule drop-rule0000001" -j DROP
+-A ANTREA-HOST-INGRESS-5 -g ANTREA-HOST-INGRESS-100
+-A ANTREA-HOST-INGRESS-100 -p tcp --dport 80 -m set --match-set ANTREA-HOST-drop-rule0000002 src -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule drop-rule0000002" -j DROP
+-A ANTREA-HOST-INGRESS-100 -g ANTREA-HOST-INGRESS-253
+-A ANTREA-HOST-INGRESS-253 -p tcp --dport 80 -m set --match-set ANTREA-HOST-pass-rule0000002 src -m comment --comment "Antrea: AntreaClusterNetworkPolicy:name1 rule pass-rule0000002" -j RETURN
+COMMIT
+`
+	assert.Equal(t, expectedData, buildHostChainsData(rules, nil, false).String())
+}
+
+func TestHostIPSetName(t *testing.T) {
+	ruleID := "0123456789abcdef"
+	assert.Equal(t, "ANTREA-HOST-0123456789abcdef", hostIPSetName(ruleID, false))
+	// The names of ipsets can't be longer than 31 characters.
+	assert.LessOrEqual(t, len(hostIPSetName(ruleID, true)), 31)
+}
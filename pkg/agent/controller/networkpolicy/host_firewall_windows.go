@@ -0,0 +1,43 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+)
+
+// unsupportedHostFirewall is used on Windows, where the policies applied to
+// Nodes are not supported yet, the rules of these policies fail to be
+// realized.
+type unsupportedHostFirewall struct{}
+
+func newHostFirewall(ipv4Enabled, ipv6Enabled bool) hostFirewall {
+	return &unsupportedHostFirewall{}
+}
+
+func (f *unsupportedHostFirewall) batchAddRules(rules []*CompletedRule) error {
+	if len(rules) > 0 {
+		return fmt.Errorf("policies applied to Nodes are not supported on Windows")
+	}
+	return nil
+}
+
+func (f *unsupportedHostFirewall) addOrUpdateRule(rule *CompletedRule) error {
+	return fmt.Errorf("rule %s can't be enforced as policies applied to Nodes are not supported on Windows", rule.ID)
+}
+
+func (f *unsupportedHostFirewall) deleteRule(ruleID string) error {
+	return nil
+}
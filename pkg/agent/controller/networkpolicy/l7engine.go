@@ -0,0 +1,483 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"antrea.io/antrea/pkg/agent/config"
+	"antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+)
+
+const (
+	// l7EngineRunDir is the directory storing the configuration, the rules and
+	// the sockets of the L7 engine.
+	l7EngineRunDir = "/var/run/antrea/l7engine"
+	// suricataBinary is the Suricata executable installed in the antrea-agent
+	// image.
+	suricataBinary = "suricata"
+	// suricataDefaultConfigFile is the default configuration file installed
+	// with Suricata. It's never modified, the agent generates a dedicated
+	// configuration file from it.
+	suricataDefaultConfigFile = "/etc/suricata/suricata.yaml"
+	suricataConfigFileName    = "suricata.yaml"
+	suricataCommandSocketName = "suricata-command.socket"
+	l7EngineEventSocketName   = "eve.socket"
+
+	// The VLAN IDs allocated to the rules, which are also used as the
+	// tenant IDs in Suricata.
+	minL7RuleVlanID uint32 = 1
+	maxL7RuleVlanID uint32 = 4094
+
+	// The sids of the Suricata rules generated for a policy rule.
+	l7RejectSid    = 1
+	l7PassSidStart = 2
+)
+
+// suricataConfigTemplate enables the multi-tenancy of Suricata with the VLAN
+// ID as the tenant selector, and runs Suricata in IPS mode between the two
+// OVS internal ports connecting the OVS bridge to it. The events are sent to
+// the unix socket the agent listens on. Its sections replace the ones of the
+// default configuration of Suricata in the dedicated configuration file.
+const suricataConfigTemplate = `outputs:
+  - eve-log:
+      enabled: yes
+      filetype: unix_stream
+      filename: %[1]s
+      types:
+        - alert
+        - http
+af-packet:
+  - interface: %[2]s
+    threads: auto
+    cluster-id: 80
+    cluster-type: cluster_flow
+    defrag: no
+    use-mmap: yes
+    tpacket-v2: yes
+    checksum-checks: no
+    copy-mode: ips
+    copy-iface: %[3]s
+  - interface: %[3]s
+    threads: auto
+    cluster-id: 81
+    cluster-type: cluster_flow
+    defrag: no
+    use-mmap: yes
+    tpacket-v2: yes
+    checksum-checks: no
+    copy-mode: ips
+    copy-iface: %[2]s
+multi-detect:
+  enabled: yes
+  selector: vlan
+unix-command:
+  enabled: yes
+  filename: %[4]s
+`
+
+// suricataTenantConfigTemplate is the configuration of the tenant created for
+// a rule, which only loads the rules file of the rule.
+const suricataTenantConfigTemplate = `%%YAML 1.1
+---
+rule-files:
+  - %s
+`
+
+// l7Rule is an Antrea-native policy rule enforced by the L7 engine.
+type l7Rule struct {
+	vlanID        uint32
+	policyRef     string
+	l7Protocols   []v1beta2.L7Protocol
+	enableLogging bool
+}
+
+// eveEvent is an event generated by Suricata in the EVE JSON format. Only the
+// fields consumed by the agent are decoded.
+type eveEvent struct {
+	EventType string   `json:"event_type"`
+	TenantID  uint32   `json:"tenant_id"`
+	Vlan      []uint32 `json:"vlan"`
+	SrcIP     string   `json:"src_ip"`
+	SrcPort   int      `json:"src_port"`
+	DestIP    string   `json:"dest_ip"`
+	DestPort  int      `json:"dest_port"`
+	HTTP      *struct {
+		Hostname   string `json:"hostname"`
+		URL        string `json:"url"`
+		HTTPMethod string `json:"http_method"`
+	} `json:"http"`
+}
+
+// l7Engine enforces the L7 protocols of Antrea-native policy rules with
+// Suricata. Each rule is allocated a VLAN ID, which the OVS bridge uses to
+// tag the packets of the connections allowed by the rule before redirecting
+// them to Suricata. Suricata runs in multi-tenancy mode with the VLAN ID as
+// the tenant selector, so that the packets are only inspected with the rules
+// generated for the rule that allowed them. The accepted packets are sent
+// back to the OVS bridge, while the other connections are rejected. Suricata
+// is started with the first rule.
+type l7Engine struct {
+	runDir string
+
+	mutex sync.Mutex
+	// rules maps the IDs of the rules to their l7Rules.
+	rules map[string]*l7Rule
+	// rulesByVlanID maps the VLAN IDs to the l7Rules they are allocated to.
+	rulesByVlanID map[uint32]*l7Rule
+	started       bool
+
+	// startEngine starts the engine and waits for it to be ready to receive
+	// commands. It's a field so that it can be replaced in unit tests.
+	startEngine func() error
+	// sendCommand sends a command to the engine. It's a field so that it can
+	// be replaced in unit tests.
+	sendCommand func(command string, arguments map[string]interface{}) error
+}
+
+func newL7Engine(runDir string) *l7Engine {
+	e := &l7Engine{
+		runDir:        runDir,
+		rules:         map[string]*l7Rule{},
+		rulesByVlanID: map[uint32]*l7Rule{},
+	}
+	e.startEngine = func() error {
+		return e.startSuricata(suricataDefaultConfigFile)
+	}
+	e.sendCommand = e.sendSuricataCommand
+	return e
+}
+
+// addRule registers a rule to the L7 engine and returns the VLAN ID allocated
+// to it. Adding a rule that has been registered returns its VLAN ID directly.
+func (e *l7Engine) addRule(ruleID, policyRef string, l7Protocols []v1beta2.L7Protocol, enableLogging bool) (uint32, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if rule, exists := e.rules[ruleID]; exists {
+		return rule.vlanID, nil
+	}
+	vlanID, err := e.allocateVlanID()
+	if err != nil {
+		return 0, err
+	}
+	if !e.started {
+		if err := e.startEngine(); err != nil {
+			return 0, fmt.Errorf("error starting L7 engine: %v", err)
+		}
+		e.started = true
+	}
+	rule := &l7Rule{
+		vlanID:        vlanID,
+		policyRef:     policyRef,
+		l7Protocols:   l7Protocols,
+		enableLogging: enableLogging,
+	}
+	rulesPath := e.rulesFilePath(vlanID)
+	if err := ioutil.WriteFile(rulesPath, []byte(renderL7Rules(rule)), 0644); err != nil {
+		return 0, fmt.Errorf("error writing L7 rules file %s: %v", rulesPath, err)
+	}
+	tenantConfigPath := e.tenantConfigFilePath(vlanID)
+	if err := ioutil.WriteFile(tenantConfigPath, []byte(fmt.Sprintf(suricataTenantConfigTemplate, rulesPath)), 0644); err != nil {
+		return 0, fmt.Errorf("error writing L7 tenant config file %s: %v", tenantConfigPath, err)
+	}
+	if err := e.sendCommand("register-tenant", map[string]interface{}{"id": vlanID, "filename": tenantConfigPath}); err != nil {
+		return 0, err
+	}
+	if err := e.sendCommand("register-tenant-handler", map[string]interface{}{"id": vlanID, "htype": "vlan", "hargs": vlanID}); err != nil {
+		return 0, err
+	}
+	e.rules[ruleID] = rule
+	e.rulesByVlanID[vlanID] = rule
+	return vlanID, nil
+}
+
+// deleteRule unregisters a rule from the L7 engine and releases its VLAN ID.
+func (e *l7Engine) deleteRule(ruleID string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	rule, exists := e.rules[ruleID]
+	if !exists {
+		return nil
+	}
+	if err := e.sendCommand("unregister-tenant-handler", map[string]interface{}{"id": rule.vlanID, "htype": "vlan", "hargs": rule.vlanID}); err != nil {
+		return err
+	}
+	if err := e.sendCommand("unregister-tenant", map[string]interface{}{"id": rule.vlanID}); err != nil {
+		return err
+	}
+	os.Remove(e.rulesFilePath(rule.vlanID))
+	os.Remove(e.tenantConfigFilePath(rule.vlanID))
+	delete(e.rules, ruleID)
+	delete(e.rulesByVlanID, rule.vlanID)
+	return nil
+}
+
+func (e *l7Engine) allocateVlanID() (uint32, error) {
+	for vlanID := minL7RuleVlanID; vlanID <= maxL7RuleVlanID; vlanID++ {
+		if _, exists := e.rulesByVlanID[vlanID]; !exists {
+			return vlanID, nil
+		}
+	}
+	return 0, errors.New("no VLAN ID available for L7 rules")
+}
+
+func (e *l7Engine) rulesFilePath(vlanID uint32) string {
+	return filepath.Join(e.runDir, fmt.Sprintf("antrea-l7-rule-%d.rules", vlanID))
+}
+
+func (e *l7Engine) tenantConfigFilePath(vlanID uint32) string {
+	return filepath.Join(e.runDir, fmt.Sprintf("antrea-l7-tenant-%d.yaml", vlanID))
+}
+
+// escapeSuricataString escapes the characters which have a special meaning in
+// the content and msg options of Suricata rules with their hex values.
+func escapeSuricataString(s string) string {
+	replacer := strings.NewReplacer(`|`, `|7C|`, `"`, `|22|`, `;`, `|3B|`, `\`, `|5C|`)
+	return replacer.Replace(s)
+}
+
+// renderL7Rules generates the Suricata rules of a rule: the requests matching
+// any of its L7 protocols pass, while the other connections are rejected.
+func renderL7Rules(rule *l7Rule) string {
+	var b strings.Builder
+	policyRef := escapeSuricataString(rule.policyRef)
+	// The TCP handshake is let through so that the protocol of the connection
+	// can be detected, the first packet with payload is rejected unless a
+	// pass rule matches.
+	fmt.Fprintf(&b, "reject tcp any any -> any any (msg: \"Reject by %s\"; flow: to_server, established; dsize: >0; sid: %d;)\n", policyRef, l7RejectSid)
+	sid := l7PassSidStart
+	for _, l7Protocol := range rule.l7Protocols {
+		if l7Protocol.HTTP == nil {
+			continue
+		}
+		var keywords []string
+		if l7Protocol.HTTP.Method != "" {
+			keywords = append(keywords, fmt.Sprintf("http.method; content: \"%s\";", escapeSuricataString(l7Protocol.HTTP.Method)))
+		}
+		if l7Protocol.HTTP.Host != "" {
+			// Suricata normalizes the HTTP hosts to lowercase.
+			keywords = append(keywords, fmt.Sprintf("http.host; content: \"%s\";", escapeSuricataString(strings.ToLower(l7Protocol.HTTP.Host))))
+		}
+		if l7Protocol.HTTP.Path != "" {
+			keywords = append(keywords, fmt.Sprintf("http.uri; content: \"%s\"; startswith;", escapeSuricataString(l7Protocol.HTTP.Path)))
+		}
+		keywords = append(keywords, fmt.Sprintf("sid: %d;", sid))
+		fmt.Fprintf(&b, "pass http any any -> any any (msg: \"Allow http by %s\"; %s)\n", policyRef, strings.Join(keywords, " "))
+		sid++
+	}
+	return b.String()
+}
+
+// startSuricata generates the dedicated configuration file of Suricata from
+// its default one, starts listening on the event socket, starts Suricata and
+// waits for its command socket to be ready.
+func (e *l7Engine) startSuricata(defaultConfigPath string) error {
+	binaryPath, err := exec.LookPath(suricataBinary)
+	if err != nil {
+		return fmt.Errorf("the Suricata binary is not installed in the antrea-agent container: %v", err)
+	}
+	if err := os.MkdirAll(e.runDir, 0755); err != nil {
+		return err
+	}
+	eventSocketPath := filepath.Join(e.runDir, l7EngineEventSocketName)
+	commandSocketPath := filepath.Join(e.runDir, suricataCommandSocketName)
+	configPath := filepath.Join(e.runDir, suricataConfigFileName)
+	if err := writeSuricataConfig(defaultConfigPath, configPath, eventSocketPath, commandSocketPath); err != nil {
+		return fmt.Errorf("error generating Suricata configuration file %s: %v", configPath, err)
+	}
+	os.Remove(eventSocketPath)
+	os.Remove(commandSocketPath)
+	listener, err := net.Listen("unix", eventSocketPath)
+	if err != nil {
+		return fmt.Errorf("error listening on L7 event socket %s: %v", eventSocketPath, err)
+	}
+	go e.receiveEvents(listener)
+
+	cmd := exec.Command(binaryPath, "-c", configPath, "--af-packet")
+	if err := cmd.Start(); err != nil {
+		listener.Close()
+		return err
+	}
+	go func() {
+		err := cmd.Wait()
+		klog.Errorf("Suricata exited: %v", err)
+	}()
+	return wait.PollImmediate(200*time.Millisecond, 10*time.Second, func() (bool, error) {
+		if _, err := os.Stat(commandSocketPath); err != nil {
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// writeSuricataConfig writes the dedicated configuration file of Suricata:
+// the top-level sections of the default configuration file are kept, except
+// the ones set by suricataConfigTemplate, which replace them.
+func writeSuricataConfig(defaultConfigPath, configPath, eventSocketPath, commandSocketPath string) error {
+	defaultData, err := ioutil.ReadFile(defaultConfigPath)
+	if err != nil {
+		return err
+	}
+	var sections yaml.MapSlice
+	if err := yaml.Unmarshal(defaultData, &sections); err != nil {
+		return fmt.Errorf("error parsing Suricata default configuration file %s: %v", defaultConfigPath, err)
+	}
+	var antreaSections yaml.MapSlice
+	antreaData := fmt.Sprintf(suricataConfigTemplate, eventSocketPath,
+		config.L7NetworkPolicyTargetInterfaceName, config.L7NetworkPolicyReturnInterfaceName, commandSocketPath)
+	if err := yaml.Unmarshal([]byte(antreaData), &antreaSections); err != nil {
+		return err
+	}
+	for _, antreaSection := range antreaSections {
+		replaced := false
+		for i := range sections {
+			if sections[i].Key == antreaSection.Key {
+				sections[i].Value = antreaSection.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			sections = append(sections, antreaSection)
+		}
+	}
+	data, err := yaml.Marshal(sections)
+	if err != nil {
+		return err
+	}
+	// Suricata requires the YAML version directive.
+	return ioutil.WriteFile(configPath, append([]byte("%YAML 1.1\n---\n"), data...), 0644)
+}
+
+// sendSuricataCommand sends a command to Suricata with the protocol of its
+// unix socket, and checks the result.
+func (e *l7Engine) sendSuricataCommand(command string, arguments map[string]interface{}) error {
+	conn, err := net.Dial("unix", filepath.Join(e.runDir, suricataCommandSocketName))
+	if err != nil {
+		return fmt.Errorf("error connecting to Suricata: %v", err)
+	}
+	defer conn.Close()
+	encoder := json.NewEncoder(conn)
+	decoder := json.NewDecoder(conn)
+	var response struct {
+		Return  string      `json:"return"`
+		Message interface{} `json:"message"`
+	}
+	if err := encoder.Encode(map[string]string{"version": "0.2"}); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&response); err != nil {
+		return err
+	}
+	if response.Return != "OK" {
+		return fmt.Errorf("error negotiating with Suricata: %v", response.Message)
+	}
+	if err := encoder.Encode(map[string]interface{}{"command": command, "arguments": arguments}); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&response); err != nil {
+		return err
+	}
+	if response.Return != "OK" {
+		return fmt.Errorf("error executing Suricata command %s: %v", command, response.Message)
+	}
+	return nil
+}
+
+// receiveEvents accepts the connections of Suricata on the event socket and
+// handles the events received from them, one per line.
+func (e *l7Engine) receiveEvents(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			klog.Errorf("Error accepting connection on L7 event socket: %v", err)
+			return
+		}
+		go func() {
+			defer conn.Close()
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				e.handleEvent(scanner.Bytes())
+			}
+		}()
+	}
+}
+
+// handleEvent reports the verdict of an event in the NetworkPolicy audit log
+// if logging is enabled for the rule that redirected the connection: HTTP
+// events are generated for the allowed requests, and alerts for the rejected
+// connections.
+func (e *l7Engine) handleEvent(data []byte) {
+	var event eveEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		klog.Errorf("Error decoding L7 event: %v", err)
+		return
+	}
+	var disposition string
+	switch event.EventType {
+	case "http":
+		disposition = "Allow"
+	case "alert":
+		disposition = "Reject"
+	default:
+		return
+	}
+	vlanID := event.TenantID
+	if len(event.Vlan) > 0 {
+		vlanID = event.Vlan[0]
+	}
+	e.mutex.Lock()
+	rule, exists := e.rulesByVlanID[vlanID]
+	e.mutex.Unlock()
+	if !exists || !rule.enableLogging || AntreaPolicyLogger == nil {
+		return
+	}
+	var method, host, url string
+	if event.HTTP != nil {
+		method, host, url = event.HTTP.HTTPMethod, event.HTTP.Hostname, event.HTTP.URL
+	}
+	if auditLogFormat == AuditLogFormatJSON {
+		logAuditRecord(&auditLogRecord{
+			Table:       "L7Engine",
+			PolicyRef:   rule.policyRef,
+			Disposition: disposition,
+			SrcIP:       event.SrcIP,
+			SrcPort:     event.SrcPort,
+			DestIP:      event.DestIP,
+			DestPort:    event.DestPort,
+			Protocol:    "HTTP",
+			HTTP:        fmt.Sprintf("%s %s%s", method, host, url),
+		})
+		return
+	}
+	AntreaPolicyLogger.Printf("L7Engine %s %s SRC: %s DEST: %s HTTP %s %s%s", rule.policyRef, disposition,
+		net.JoinHostPort(event.SrcIP, fmt.Sprint(event.SrcPort)), net.JoinHostPort(event.DestIP, fmt.Sprint(event.DestPort)),
+		method, host, url)
+}
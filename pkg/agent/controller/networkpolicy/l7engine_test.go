@@ -0,0 +1,337 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"antrea.io/antrea/pkg/agent/interfacestore"
+	openflowtest "antrea.io/antrea/pkg/agent/openflow/testing"
+	"antrea.io/antrea/pkg/agent/types"
+	"antrea.io/antrea/pkg/agent/util"
+	"antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+)
+
+type fakeL7EngineCommand struct {
+	command   string
+	arguments map[string]interface{}
+}
+
+func newFakeL7Engine(t *testing.T) (*l7Engine, *[]fakeL7EngineCommand, func()) {
+	runDir, err := ioutil.TempDir("", "l7engine")
+	require.NoError(t, err)
+	var commands []fakeL7EngineCommand
+	e := newL7Engine(runDir)
+	e.startEngine = func() error {
+		commands = append(commands, fakeL7EngineCommand{command: "start"})
+		return nil
+	}
+	e.sendCommand = func(command string, arguments map[string]interface{}) error {
+		commands = append(commands, fakeL7EngineCommand{command: command, arguments: arguments})
+		return nil
+	}
+	return e, &commands, func() { os.RemoveAll(runDir) }
+}
+
+func TestRenderL7Rules(t *testing.T) {
+	rule := &l7Rule{
+		vlanID:    1,
+		policyRef: "AntreaNetworkPolicy:ns1/np1",
+		l7Protocols: []v1beta2.L7Protocol{
+			{HTTP: &v1beta2.HTTPProtocol{}},
+			{HTTP: &v1beta2.HTTPProtocol{Host: "Foo.bar.com", Method: "GET", Path: "/api;v1"}},
+		},
+	}
+	expected := `reject tcp any any -> any any (msg: "Reject by AntreaNetworkPolicy:ns1/np1"; flow: to_server, established; dsize: >0; sid: 1;)
+pass http any any -> any any (msg: "Allow http by AntreaNetworkPolicy:ns1/np1"; sid: 2;)
+pass http any any -> any any (msg: "Allow http by AntreaNetworkPolicy:ns1/np1"; http.method; content: "GET"; http.host; content: "foo.bar.com"; http.uri; content: "/api|3B|v1"; startswith; sid: 3;)
+`
+	assert.Equal(t, expected, renderL7Rules(rule))
+}
+
+func TestL7EngineAddDeleteRule(t *testing.T) {
+	e, commands, cleanup := newFakeL7Engine(t)
+	defer cleanup()
+	l7Protocols := []v1beta2.L7Protocol{{HTTP: &v1beta2.HTTPProtocol{Method: "GET"}}}
+
+	vlanID1, err := e.addRule("rule1", "AntreaNetworkPolicy:ns1/np1", l7Protocols, false)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), vlanID1)
+	// Adding the same rule again doesn't allocate a new VLAN ID.
+	vlanID, err := e.addRule("rule1", "AntreaNetworkPolicy:ns1/np1", l7Protocols, false)
+	require.NoError(t, err)
+	assert.Equal(t, vlanID1, vlanID)
+	vlanID2, err := e.addRule("rule2", "AntreaNetworkPolicy:ns1/np2", l7Protocols, false)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), vlanID2)
+	assert.FileExists(t, e.rulesFilePath(vlanID1))
+	assert.FileExists(t, e.tenantConfigFilePath(vlanID1))
+
+	require.NoError(t, e.deleteRule("rule1"))
+	assert.NoFileExists(t, e.rulesFilePath(vlanID1))
+	// The released VLAN ID is allocated to the next rule.
+	vlanID3, err := e.addRule("rule3", "AntreaNetworkPolicy:ns1/np3", l7Protocols, false)
+	require.NoError(t, err)
+	assert.Equal(t, vlanID1, vlanID3)
+
+	expectedCommands := []fakeL7EngineCommand{
+		{command: "start"},
+		{command: "register-tenant", arguments: map[string]interface{}{"id": vlanID1, "filename": e.tenantConfigFilePath(vlanID1)}},
+		{command: "register-tenant-handler", arguments: map[string]interface{}{"id": vlanID1, "htype": "vlan", "hargs": vlanID1}},
+		{command: "register-tenant", arguments: map[string]interface{}{"id": vlanID2, "filename": e.tenantConfigFilePath(vlanID2)}},
+		{command: "register-tenant-handler", arguments: map[string]interface{}{"id": vlanID2, "htype": "vlan", "hargs": vlanID2}},
+		{command: "unregister-tenant-handler", arguments: map[string]interface{}{"id": vlanID1, "htype": "vlan", "hargs": vlanID1}},
+		{command: "unregister-tenant", arguments: map[string]interface{}{"id": vlanID1}},
+		{command: "register-tenant", arguments: map[string]interface{}{"id": vlanID3, "filename": e.tenantConfigFilePath(vlanID3)}},
+		{command: "register-tenant-handler", arguments: map[string]interface{}{"id": vlanID3, "htype": "vlan", "hargs": vlanID3}},
+	}
+	assert.Equal(t, expectedCommands, *commands)
+}
+
+func TestL7EngineHandleEvent(t *testing.T) {
+	e, _, cleanup := newFakeL7Engine(t)
+	defer cleanup()
+	_, err := e.addRule("rule1", "AntreaNetworkPolicy:ns1/np1", []v1beta2.L7Protocol{{HTTP: &v1beta2.HTTPProtocol{}}}, true)
+	require.NoError(t, err)
+	_, err = e.addRule("rule2", "AntreaNetworkPolicy:ns1/np2", []v1beta2.L7Protocol{{HTTP: &v1beta2.HTTPProtocol{}}}, false)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	prevLogger := AntreaPolicyLogger
+	AntreaPolicyLogger = log.New(&buf, "", 0)
+	defer func() { AntreaPolicyLogger = prevLogger }()
+
+	tests := []struct {
+		name     string
+		event    string
+		expected string
+	}{
+		{
+			name:     "allowed request",
+			event:    `{"event_type":"http","vlan":[1],"src_ip":"10.0.0.1","src_port":40000,"dest_ip":"10.0.0.2","dest_port":80,"http":{"hostname":"foo.bar.com","url":"/api","http_method":"GET"}}`,
+			expected: "L7Engine AntreaNetworkPolicy:ns1/np1 Allow SRC: 10.0.0.1:40000 DEST: 10.0.0.2:80 HTTP GET foo.bar.com/api\n",
+		},
+		{
+			name:     "rejected request",
+			event:    `{"event_type":"alert","tenant_id":1,"src_ip":"10.0.0.1","src_port":40000,"dest_ip":"10.0.0.2","dest_port":80,"http":{"hostname":"foo.bar.com","url":"/admin","http_method":"POST"}}`,
+			expected: "L7Engine AntreaNetworkPolicy:ns1/np1 Reject SRC: 10.0.0.1:40000 DEST: 10.0.0.2:80 HTTP POST foo.bar.com/admin\n",
+		},
+		{
+			name:  "logging disabled",
+			event: `{"event_type":"http","vlan":[2],"src_ip":"10.0.0.1","src_port":40000,"dest_ip":"10.0.0.2","dest_port":80}`,
+		},
+		{
+			name:  "unknown rule",
+			event: `{"event_type":"http","vlan":[3],"src_ip":"10.0.0.1","src_port":40000,"dest_ip":"10.0.0.2","dest_port":80}`,
+		},
+		{
+			name:  "other event",
+			event: `{"event_type":"flow","vlan":[1],"src_ip":"10.0.0.1","src_port":40000,"dest_ip":"10.0.0.2","dest_port":80}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			e.handleEvent([]byte(tt.event))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestReconcilerReconcileL7Rule(t *testing.T) {
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
+		InterfaceName:            util.GenerateContainerInterfaceName("pod1", "ns1", "container1"),
+		IPs:                      []net.IP{net.ParseIP("2.2.2.2")},
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{PodName: "pod1", PodNamespace: "ns1", ContainerID: "container1"},
+		OVSPortConfig:            &interfacestore.OVSPortConfig{OFPort: 1},
+	})
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockOFClient.EXPECT().IsIPv4Enabled().Return(true).AnyTimes()
+	mockOFClient.EXPECT().IsIPv6Enabled().Return(false).AnyTimes()
+	r := newReconciler(mockOFClient, ifaceStore, testAsyncDeleteInterval)
+	e, _, cleanup := newFakeL7Engine(t)
+	defer cleanup()
+	r.l7Engine = e
+
+	allowAction := crdv1alpha1.RuleActionAllow
+	rule := &CompletedRule{
+		rule: &rule{
+			ID:             "egress-rule",
+			Direction:      v1beta2.DirectionOut,
+			To:             v1beta2.NetworkPolicyPeer{IPBlocks: []v1beta2.IPBlock{{CIDR: v1beta2.IPNet{IP: v1beta2.IPAddress(net.ParseIP("10.0.0.0")), PrefixLength: 24}}}},
+			Services:       services1,
+			Action:         &allowAction,
+			PolicyPriority: &policyPriority,
+			TierPriority:   &tierPriority,
+			SourceRef:      &cnp1,
+			L7Protocols:    []v1beta2.L7Protocol{{HTTP: &v1beta2.HTTPProtocol{Method: "GET"}}},
+		},
+		TargetMembers: appliedToGroup1,
+	}
+	mockOFClient.EXPECT().InstallPolicyRuleFlows(gomock.Any()).Do(func(ofRule *types.PolicyRule) {
+		require.NotNil(t, ofRule.L7RuleVlanID)
+		assert.Equal(t, uint32(1), *ofRule.L7RuleVlanID)
+	})
+	require.NoError(t, r.Reconcile(rule))
+	assert.Contains(t, e.rules, rule.ID)
+
+	mockOFClient.EXPECT().UninstallPolicyRuleFlows(gomock.Any())
+	require.NoError(t, r.Forget(rule.ID))
+	assert.NotContains(t, e.rules, rule.ID)
+}
+
+func TestReconcilerReconcileL7RuleEngineFailure(t *testing.T) {
+	ifaceStore := interfacestore.NewInterfaceStore()
+	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
+		InterfaceName:            util.GenerateContainerInterfaceName("pod1", "ns1", "container1"),
+		IPs:                      []net.IP{net.ParseIP("2.2.2.2")},
+		ContainerInterfaceConfig: &interfacestore.ContainerInterfaceConfig{PodName: "pod1", PodNamespace: "ns1", ContainerID: "container1"},
+		OVSPortConfig:            &interfacestore.OVSPortConfig{OFPort: 1},
+	})
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockOFClient.EXPECT().IsIPv4Enabled().Return(true).AnyTimes()
+	mockOFClient.EXPECT().IsIPv6Enabled().Return(false).AnyTimes()
+	r := newReconciler(mockOFClient, ifaceStore, testAsyncDeleteInterval)
+	r.idAllocator.deleteInterval = 0
+	e, _, cleanup := newFakeL7Engine(t)
+	defer cleanup()
+	r.l7Engine = e
+	e.startEngine = func() error {
+		return fmt.Errorf("engine not ready")
+	}
+
+	allowAction := crdv1alpha1.RuleActionAllow
+	rule := &CompletedRule{
+		rule: &rule{
+			ID:             "egress-rule",
+			Direction:      v1beta2.DirectionOut,
+			To:             v1beta2.NetworkPolicyPeer{IPBlocks: []v1beta2.IPBlock{{CIDR: v1beta2.IPNet{IP: v1beta2.IPAddress(net.ParseIP("10.0.0.0")), PrefixLength: 24}}}},
+			Services:       services1,
+			Action:         &allowAction,
+			PolicyPriority: &policyPriority,
+			TierPriority:   &tierPriority,
+			SourceRef:      &cnp1,
+			L7Protocols:    []v1beta2.L7Protocol{{HTTP: &v1beta2.HTTPProtocol{Method: "GET"}}},
+		},
+		TargetMembers: appliedToGroup1,
+	}
+	// The traffic of the rule is dropped while the L7 engine fails to accept it.
+	var dropFlowID uint32
+	mockOFClient.EXPECT().InstallPolicyRuleFlows(gomock.Any()).Do(func(ofRule *types.PolicyRule) {
+		assert.Nil(t, ofRule.L7RuleVlanID)
+		assert.Equal(t, crdv1alpha1.RuleActionDrop, *ofRule.Action)
+		dropFlowID = ofRule.FlowID
+	})
+	assert.Error(t, r.Reconcile(rule))
+	value, exists := r.lastRealizeds.Load(rule.ID)
+	require.True(t, exists)
+	assert.True(t, value.(*lastRealized).isL7Pending())
+	// The rule stays dropped if the L7 engine still fails.
+	assert.Error(t, r.Reconcile(rule))
+
+	// Once the L7 engine accepts the rule, the traffic is redirected to it before the Drop flows are uninstalled.
+	e.startEngine = func() error {
+		return nil
+	}
+	installCall := mockOFClient.EXPECT().InstallPolicyRuleFlows(gomock.Any()).Do(func(ofRule *types.PolicyRule) {
+		require.NotNil(t, ofRule.L7RuleVlanID)
+		assert.Equal(t, uint32(1), *ofRule.L7RuleVlanID)
+		assert.Equal(t, crdv1alpha1.RuleActionAllow, *ofRule.Action)
+	})
+	mockOFClient.EXPECT().UninstallPolicyRuleFlows(dropFlowID).After(installCall)
+	require.NoError(t, r.Reconcile(rule))
+	value, _ = r.lastRealizeds.Load(rule.ID)
+	assert.False(t, value.(*lastRealized).isL7Pending())
+	assert.Contains(t, e.rules, rule.ID)
+}
+
+func TestReconcilerReconcileL7RuleFeatureDisabled(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockOFClient.EXPECT().IsIPv4Enabled().Return(true).AnyTimes()
+	mockOFClient.EXPECT().IsIPv6Enabled().Return(false).AnyTimes()
+	r := newReconciler(mockOFClient, interfacestore.NewInterfaceStore(), testAsyncDeleteInterval)
+
+	rule := &CompletedRule{
+		rule: &rule{
+			ID:          "egress-rule",
+			Direction:   v1beta2.DirectionOut,
+			SourceRef:   &cnp1,
+			L7Protocols: []v1beta2.L7Protocol{{HTTP: &v1beta2.HTTPProtocol{Method: "GET"}}},
+		},
+	}
+	_, err := r.getL7RuleVlanID(rule)
+	assert.Error(t, err)
+}
+
+func TestWriteSuricataConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "l7engine")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defaultConfigPath := filepath.Join(dir, "default.yaml")
+	defaultConfig := `%YAML 1.1
+---
+vars:
+  address-groups:
+    HOME_NET: "[10.0.0.0/8]"
+outputs:
+  - fast:
+      enabled: yes
+af-packet:
+  - interface: eth0
+`
+	require.NoError(t, ioutil.WriteFile(defaultConfigPath, []byte(defaultConfig), 0644))
+	configPath := filepath.Join(dir, "suricata.yaml")
+	require.NoError(t, writeSuricataConfig(defaultConfigPath, configPath, "/run/eve.socket", "/run/command.socket"))
+
+	// The default configuration file is not modified.
+	data, err := ioutil.ReadFile(defaultConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, defaultConfig, string(data))
+
+	data, err = ioutil.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "%YAML 1.1\n---\n"))
+	var config map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &config))
+	assert.Equal(t, map[interface{}]interface{}{"address-groups": map[interface{}]interface{}{"HOME_NET": "[10.0.0.0/8]"}}, config["vars"])
+	outputs := config["outputs"].([]interface{})
+	require.Len(t, outputs, 1)
+	assert.Equal(t, "/run/eve.socket", outputs[0].(map[interface{}]interface{})["eve-log"].(map[interface{}]interface{})["filename"])
+	afPackets := config["af-packet"].([]interface{})
+	require.Len(t, afPackets, 2)
+	assert.Equal(t, "/run/command.socket", config["unix-command"].(map[interface{}]interface{})["filename"])
+	assert.Contains(t, config, "multi-detect")
+}
@@ -35,6 +35,7 @@ import (
 	"antrea.io/antrea/pkg/agent/openflow"
 	"antrea.io/antrea/pkg/agent/types"
 	"antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	"antrea.io/antrea/pkg/features"
 	"antrea.io/antrea/pkg/querier"
 )
 
@@ -67,6 +68,9 @@ type Controller struct {
 	statusManagerEnabled bool
 	// loggingEnabled indicates where Antrea policy audit logging is enabled.
 	loggingEnabled bool
+	// logRateLimiter limits the number of packets logged per second for each
+	// rule. It's nil if there is no limit.
+	logRateLimiter *logRateLimiter
 	// antreaClientProvider provides interfaces to get antreaClient, which can be
 	// used to watch Antrea AddressGroups, AppliedToGroups, and NetworkPolicies.
 	// We need to get antreaClient dynamically because the apiserver cert can be
@@ -94,6 +98,12 @@ type Controller struct {
 	ifaceStore            interfacestore.InterfaceStore
 	// denyConnStore is for storing deny connections for flow exporter.
 	denyConnStore *connections.DenyConnectionStore
+	// fqdnController learns the IPs of the FQDNs used in Antrea-native
+	// policy rules. It's nil if Antrea-native policies are not enabled.
+	fqdnController *fqdnController
+	// l7Engine enforces the L7 protocols of Antrea-native policy rules. It's
+	// nil if the L7NetworkPolicy feature is not enabled.
+	l7Engine *l7Engine
 }
 
 // NewNetworkPolicyController returns a new *Controller.
@@ -105,12 +115,14 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 	antreaPolicyEnabled bool,
 	statusManagerEnabled bool,
 	loggingEnabled bool,
+	auditLoggerConfig *AuditLoggerConfig,
 	denyConnStore *connections.DenyConnectionStore,
 	asyncRuleDeleteInterval time.Duration) (*Controller, error) {
+	reconciler := newReconciler(ofClient, ifaceStore, asyncRuleDeleteInterval)
 	c := &Controller{
 		antreaClientProvider: antreaClientGetter,
 		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicyrule"),
-		reconciler:           newReconciler(ofClient, ifaceStore, asyncRuleDeleteInterval),
+		reconciler:           reconciler,
 		ofClient:             ofClient,
 		antreaPolicyEnabled:  antreaPolicyEnabled,
 		statusManagerEnabled: statusManagerEnabled,
@@ -118,6 +130,15 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 		denyConnStore:        denyConnStore,
 	}
 	c.ruleCache = newRuleCache(c.enqueueRule, entityUpdates)
+	if antreaPolicyEnabled {
+		c.fqdnController = newFQDNController(ofClient, c.enqueueRule)
+		reconciler.fqdnController = c.fqdnController
+		reconciler.hostFirewall = newHostFirewall(reconciler.ipv4Enabled, reconciler.ipv6Enabled)
+		if features.DefaultFeatureGate.Enabled(features.L7NetworkPolicy) {
+			c.l7Engine = newL7Engine(l7EngineRunDir)
+			reconciler.l7Engine = c.l7Engine
+		}
+	}
 	if statusManagerEnabled {
 		c.statusManager = newStatusController(antreaClientGetter, nodeName, c.ruleCache)
 	}
@@ -132,10 +153,22 @@ func NewNetworkPolicyController(antreaClientGetter agent.AntreaClientProvider,
 		// Register packetInHandler
 		c.ofClient.RegisterPacketInHandler(uint8(openflow.PacketInReasonNP), "networkpolicy", c)
 		// Initiate logger for Antrea Policy audit logging
-		err := initLogger()
+		err := initLogger(auditLoggerConfig)
 		if err != nil {
 			return nil, err
 		}
+		if auditLoggerConfig != nil && auditLoggerConfig.RateLimit > 0 {
+			// The logged packets are rate-limited by a meter of each rule in the datapath, so that they don't reach
+			// the Agent when exceeding the rate, and by logRateLimiter when OVS meters are not supported.
+			c.logRateLimiter = newLogRateLimiter(auditLoggerConfig.RateLimit, auditLoggerConfig.RateLimitBurst)
+			burst := auditLoggerConfig.RateLimitBurst
+			if burst <= 0 {
+				burst = auditLoggerConfig.RateLimit
+			}
+			if err := c.ofClient.InstallPolicyLoggingMeters(uint32(auditLoggerConfig.RateLimit), uint32(burst)); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Use nodeName to filter resources when watching resources.
@@ -426,6 +459,10 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 		go c.statusManager.Run(stopCh)
 	}
 
+	if c.fqdnController != nil {
+		go c.fqdnController.runExpirationWorker(stopCh)
+	}
+
 	<-stopCh
 }
 
@@ -63,19 +63,48 @@ var (
 
 // logInfo will be set by retrieving info from packetin and register
 type logInfo struct {
-	tableName   string // name of the table sending packetin
-	npRef       string // Network Policy name reference for Antrea NetworkPolicy
-	disposition string // Allow/Drop of the rule sending packetin
-	ofPriority  string // openflow priority of the flow sending packetin
-	srcIP       string // source IP of the traffic logged
-	destIP      string // destination IP of the traffic logged
-	pktLength   uint16 // packet length of packetin
-	protocolStr string // protocol of the traffic logged
+	tableName        string // name of the table sending packetin
+	npRef            string // Network Policy name reference for Antrea NetworkPolicy
+	ruleID           uint32 // conjunction ID of the rule sending packetin
+	disposition      string // Allow/Drop of the rule sending packetin
+	ofPriority       string // openflow priority of the flow sending packetin
+	srcIP            string // source IP of the traffic logged
+	destIP           string // destination IP of the traffic logged
+	pktLength        uint16 // packet length of packetin
+	protocolStr      string // protocol of the traffic logged
+	policyType       string // type of the policy of the rule sending packetin
+	policyNamespace  string // namespace of the policy of the rule sending packetin
+	policyName       string // name of the policy of the rule sending packetin
+	ruleName         string // name of the rule sending packetin
+	srcPodNamespace  string // namespace of the source Pod, if known
+	srcPodName       string // name of the source Pod, if known
+	destPodNamespace string // namespace of the destination Pod, if known
+	destPodName      string // name of the destination Pod, if known
 }
 
 // initLogger is called while newing Antrea network policy agent controller.
 // Customize AntreaPolicyLogger specifically for Antrea Policies audit logging.
-func initLogger() error {
+// A nil config writes text entries to the log file.
+func initLogger(config *AuditLoggerConfig) error {
+	if config == nil {
+		config = &AuditLoggerConfig{}
+	}
+	auditLogFormat = AuditLogFormatText
+	auditLogOutput = AuditLogOutputFile
+	flags := log.Ldate | log.Lmicroseconds
+	if config.Format == AuditLogFormatJSON {
+		// The JSON entries include their own timestamp.
+		auditLogFormat = AuditLogFormatJSON
+		flags = 0
+	}
+	if config.Output == AuditLogOutputSyslog {
+		auditLogOutput = AuditLogOutputSyslog
+		// The syslog messages include their own timestamp.
+		AntreaPolicyLogger = log.New(newSyslogWriter(config.SyslogNetwork, config.SyslogAddress), "", 0)
+		klog.V(2).Infof("Initialized Antrea-native Policy Logger for audit logging with syslog server %s://%s", config.SyslogNetwork, config.SyslogAddress)
+		return nil
+	}
+
 	logDir := filepath.Join(logdir.GetLogDir(), logfileSubdir)
 	logFile := filepath.Join(logDir, logfileName)
 	if _, err := os.Stat(logDir); os.IsNotExist(err) {
@@ -90,7 +119,7 @@ func initLogger() error {
 		MaxAge:     28,   // allow max 28 days maintenance of old log files
 		Compress:   true, // compress the old log files for backup
 	}
-	AntreaPolicyLogger = log.New(logOutput, "", log.Ldate|log.Lmicroseconds)
+	AntreaPolicyLogger = log.New(logOutput, "", flags)
 	klog.V(2).Infof("Initialized Antrea-native Policy Logger for audit logging with log file '%s'", logFile)
 	return nil
 }
@@ -127,6 +156,11 @@ func (c *Controller) HandlePacketIn(pktIn *ofctrl.PacketIn) error {
 			return err
 		}
 	}
+	if customReasons&openflow.CustomReasonDNS == openflow.CustomReasonDNS && c.fqdnController != nil {
+		if err := c.fqdnController.handlePacketIn(pktIn); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -141,6 +175,11 @@ func (c *Controller) logPacket(pktIn *ofctrl.PacketIn) error {
 		return fmt.Errorf("received error while retrieving NetworkPolicy info: %v", err)
 	}
 
+	// Drop the packet silently if its rule has exceeded its rate of logged packets.
+	if !c.logRateLimiter.allow(ob.ruleID, time.Now()) {
+		return nil
+	}
+
 	// Get packet log info
 	err = getPacketInfo(pktIn, ob)
 	if err != nil {
@@ -148,10 +187,94 @@ func (c *Controller) logPacket(pktIn *ofctrl.PacketIn) error {
 	}
 
 	// Store log file
-	AntreaPolicyLogger.Printf("%s %s %s %s SRC: %s DEST: %s %d %s", ob.tableName, ob.npRef, ob.disposition, ob.ofPriority, ob.srcIP, ob.destIP, ob.pktLength, ob.protocolStr)
+	c.writeLogInfo(ob)
 	return nil
 }
 
+// writeLogInfo writes logInfo ob to AntreaPolicyLogger with auditLogFormat.
+func (c *Controller) writeLogInfo(ob *logInfo) {
+	if auditLogFormat == AuditLogFormatJSON {
+		c.enrichLogInfo(ob)
+		logAuditRecord(ob.toAuditLogRecord())
+		return
+	}
+	if auditLogOutput == AuditLogOutputSyslog {
+		// The text entries written to the log file keep their format, while the ones sent to the syslog server are
+		// also enriched.
+		c.enrichLogInfo(ob)
+		AntreaPolicyLogger.Printf("%s %s %s %s SRC: %s DEST: %s %d %s RULE: %s SRC_POD: %s DEST_POD: %s", ob.tableName, ob.npRef, ob.disposition, ob.ofPriority, ob.srcIP, ob.destIP, ob.pktLength, ob.protocolStr,
+			textLogValue(ob.ruleName), textLogValue(podRef(ob.srcPodNamespace, ob.srcPodName)), textLogValue(podRef(ob.destPodNamespace, ob.destPodName)))
+		return
+	}
+	AntreaPolicyLogger.Printf("%s %s %s %s SRC: %s DEST: %s %d %s", ob.tableName, ob.npRef, ob.disposition, ob.ofPriority, ob.srcIP, ob.destIP, ob.pktLength, ob.protocolStr)
+}
+
+// podRef returns the reference of a Pod as "namespace/name", or an empty string if the Pod is unknown.
+func podRef(namespace, name string) string {
+	if name == "" {
+		return ""
+	}
+	return namespace + "/" + name
+}
+
+// textLogValue returns the value of a field of a text audit log entry, "-" if it is unknown.
+func textLogValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// enrichLogInfo fills in the policy and rule names of the rule sending packetin, and the Pods of the source
+// and destination IPs of logInfo ob.
+func (c *Controller) enrichLogInfo(ob *logInfo) {
+	if rule := c.GetRuleByFlowID(ob.ruleID); rule != nil {
+		ob.ruleName = rule.Name
+		if rule.PolicyRef != nil {
+			ob.policyType = string(rule.PolicyRef.Type)
+			ob.policyNamespace = rule.PolicyRef.Namespace
+			ob.policyName = rule.PolicyRef.Name
+		}
+	}
+	ob.srcPodNamespace, ob.srcPodName = c.getPodByIP(ob.srcIP)
+	ob.destPodNamespace, ob.destPodName = c.getPodByIP(ob.destIP)
+}
+
+// getPodByIP returns the namespace and name of the Pod which has the IP. Local Pods are found in the interface
+// store, remote Pods are only found if they are members of an AddressGroup.
+func (c *Controller) getPodByIP(ip string) (string, string) {
+	if c.ifaceStore != nil {
+		if iface, ok := c.ifaceStore.GetInterfaceByIP(ip); ok && iface.ContainerInterfaceConfig != nil {
+			return iface.PodNamespace, iface.PodName
+		}
+	}
+	if pod := c.ruleCache.getPodByIP(ip); pod != nil {
+		return pod.Namespace, pod.Name
+	}
+	return "", ""
+}
+
+func (ob *logInfo) toAuditLogRecord() *auditLogRecord {
+	return &auditLogRecord{
+		Table:            ob.tableName,
+		PolicyRef:        ob.npRef,
+		PolicyType:       ob.policyType,
+		PolicyNamespace:  ob.policyNamespace,
+		PolicyName:       ob.policyName,
+		RuleName:         ob.ruleName,
+		Disposition:      ob.disposition,
+		OFPriority:       ob.ofPriority,
+		SrcIP:            ob.srcIP,
+		SrcPodNamespace:  ob.srcPodNamespace,
+		SrcPodName:       ob.srcPodName,
+		DestIP:           ob.destIP,
+		DestPodNamespace: ob.destPodNamespace,
+		DestPodName:      ob.destPodName,
+		PacketLength:     ob.pktLength,
+		Protocol:         ob.protocolStr,
+	}
+}
+
 // getMatchRegField returns match to the regNum register.
 func getMatchRegField(matchers *ofctrl.Matchers, regNum uint32) *ofctrl.MatchField {
 	return matchers.GetMatchByName(fmt.Sprintf("NXM_NX_REG%d", regNum))
@@ -160,11 +283,11 @@ func getMatchRegField(matchers *ofctrl.Matchers, regNum uint32) *ofctrl.MatchFie
 // getMatch receives ofctrl matchers and table id, match field.
 // Modifies match field to Ingress/Egress register based on tableID.
 func getMatch(matchers *ofctrl.Matchers, tableID binding.TableIDType, disposition uint32) *ofctrl.MatchField {
-	// Get match from CNPDenyConjIDReg if disposition is not allow.
-	if disposition != openflow.DispositionAllow {
+	// Get match from CNPDenyConjIDReg if disposition is drop or reject.
+	if disposition == openflow.DispositionDrop || disposition == openflow.DispositionRej {
 		return getMatchRegField(matchers, uint32(openflow.CNPDenyConjIDReg))
 	}
-	// Get match from ingress/egress reg if disposition is allow
+	// Get match from ingress/egress reg if disposition is allow or pass
 	for _, table := range append(openflow.GetAntreaPolicyEgressTables(), openflow.EgressRuleTable) {
 		if tableID == table {
 			return getMatchRegField(matchers, uint32(openflow.EgressReg))
@@ -178,6 +301,23 @@ func getMatch(matchers *ofctrl.Matchers, tableID binding.TableIDType, dispositio
 	return nil
 }
 
+// isAuditPacket returns whether the packet-in is sent by a rule in Audit mode, according to the audit mark of
+// the direction of the table sending the packet-in.
+func isAuditPacket(matchers *ofctrl.Matchers, tableID binding.TableIDType) (bool, error) {
+	auditMarkRange := openflow.IngressAuditMarkRange
+	for _, table := range append(openflow.GetAntreaPolicyEgressTables(), openflow.EgressRuleTable) {
+		if tableID == table {
+			auditMarkRange = openflow.EgressAuditMarkRange
+		}
+	}
+	match := getMatchRegField(matchers, uint32(openflow.DispositionMarkReg))
+	auditMark, err := getInfoInReg(match, auditMarkRange.ToNXRange())
+	if err != nil {
+		return false, err
+	}
+	return auditMark == 1, nil
+}
+
 // getInfoInReg unloads and returns data stored in the match field.
 func getInfoInReg(regMatch *ofctrl.MatchField, rng *openflow13.NXRange) (uint32, error) {
 	regValue, ok := regMatch.GetValue().(*ofctrl.NXRegister)
@@ -190,12 +330,33 @@ func getInfoInReg(regMatch *ofctrl.MatchField, rng *openflow13.NXRange) (uint32,
 	return regValue.Data, nil
 }
 
+// getPacketInTableID returns the ID of the table of the flow which sent the packet to the controller. If a copy of
+// the packet has been sent by PacketInTable, it is the ID stored in PacketInTableIDReg.
+func getPacketInTableID(pktIn *ofctrl.PacketIn) (binding.TableIDType, error) {
+	tableID := binding.TableIDType(pktIn.TableId)
+	if tableID != openflow.PacketInTable {
+		return tableID, nil
+	}
+	match := getMatchRegField(pktIn.GetMatches(), uint32(openflow.PacketInTableIDReg))
+	if match == nil {
+		return 0, fmt.Errorf("packet-in from table %d without table ID", tableID)
+	}
+	info, err := getInfoInReg(match, openflow.PacketInTableIDRange.ToNXRange())
+	if err != nil {
+		return 0, fmt.Errorf("received error while unloading table ID from reg: %v", err)
+	}
+	return binding.TableIDType(info), nil
+}
+
 // getNetworkPolicyInfo fills in tableName, npName, ofPriority, disposition of logInfo ob.
 func getNetworkPolicyInfo(pktIn *ofctrl.PacketIn, c *Controller, ob *logInfo) error {
 	matchers := pktIn.GetMatches()
 	var match *ofctrl.MatchField
 	// Get table name
-	tableID := binding.TableIDType(pktIn.TableId)
+	tableID, err := getPacketInTableID(pktIn)
+	if err != nil {
+		return err
+	}
 	ob.tableName = openflow.GetFlowTableName(tableID)
 
 	// Get disposition Allow or Drop
@@ -208,12 +369,23 @@ func getNetworkPolicyInfo(pktIn *ofctrl.PacketIn, c *Controller, ob *logInfo) er
 
 	// Set match to corresponding ingress/egress reg according to disposition
 	match = getMatch(matchers, tableID, info)
+	// The conjunction ID of a rule in Audit mode is always stored in the ingress/egress reg, as the packet
+	// is not denied by the rule. Its disposition is suffixed to tell it is not enforced.
+	audit, err := isAuditPacket(matchers, tableID)
+	if err != nil {
+		return fmt.Errorf("received error while unloading audit mark from reg: %v", err)
+	}
+	if audit {
+		ob.disposition = fmt.Sprintf("%s(Audit)", ob.disposition)
+		match = getMatch(matchers, tableID, openflow.DispositionAllow)
+	}
 
 	// Get Network Policy full name and OF priority of the conjunction
 	info, err = getInfoInReg(match, nil)
 	if err != nil {
 		return fmt.Errorf("received error while unloading conjunction id from reg: %v", err)
 	}
+	ob.ruleID = info
 	ob.npRef, ob.ofPriority = c.ofClient.GetPolicyInfoFromConjunction(info)
 
 	return nil
@@ -370,7 +542,10 @@ func (c *Controller) storeDenyConnection(pktIn *ofctrl.PacketIn) error {
 	matchers := pktIn.GetMatches()
 	var match *ofctrl.MatchField
 	// Get table ID
-	tableID := binding.TableIDType(pktIn.TableId)
+	tableID, err := getPacketInTableID(pktIn)
+	if err != nil {
+		return err
+	}
 	// Get disposition Allow, Drop or Reject
 	match = getMatchRegField(matchers, uint32(openflow.DispositionMarkReg))
 	id, err := getInfoInReg(match, openflow.APDispositionMarkRange.ToNXRange())
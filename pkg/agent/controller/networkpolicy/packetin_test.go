@@ -18,10 +18,14 @@ import (
 	"net"
 	"testing"
 
+	"github.com/contiv/libOpenflow/openflow13"
 	"github.com/contiv/libOpenflow/protocol"
 	"github.com/contiv/libOpenflow/util"
 	"github.com/contiv/ofnet/ofctrl"
 	"github.com/stretchr/testify/assert"
+
+	"antrea.io/antrea/pkg/agent/openflow"
+	binding "antrea.io/antrea/pkg/ovs/openflow"
 )
 
 func TestGetPacketInfo(t *testing.T) {
@@ -63,3 +67,47 @@ func TestGetPacketInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPacketInTableID(t *testing.T) {
+	tableIDField := openflow13.MatchField{
+		Class: openflow13.OXM_CLASS_NXM_1,
+		Field: uint8(openflow13.NXM_NX_REG0 + openflow.PacketInTableIDReg),
+		Value: &openflow13.Uint32Message{Data: uint32(openflow.AntreaPolicyIngressRuleTable)},
+	}
+	tests := []struct {
+		name            string
+		pktIn           *ofctrl.PacketIn
+		expectedTableID binding.TableIDType
+		wantErr         bool
+	}{
+		{
+			name:            "rule table",
+			pktIn:           &ofctrl.PacketIn{TableId: uint8(openflow.IngressDefaultTable)},
+			expectedTableID: openflow.IngressDefaultTable,
+		},
+		{
+			name: "PacketInTable",
+			pktIn: &ofctrl.PacketIn{
+				TableId: uint8(openflow.PacketInTable),
+				Match:   openflow13.Match{Fields: []openflow13.MatchField{tableIDField}},
+			},
+			expectedTableID: openflow.AntreaPolicyIngressRuleTable,
+		},
+		{
+			name:    "PacketInTable without table ID",
+			pktIn:   &ofctrl.PacketIn{TableId: uint8(openflow.PacketInTable)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tableID, err := getPacketInTableID(tt.pktIn)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTableID, tableID)
+		})
+	}
+}
@@ -32,12 +32,17 @@ import (
 	"antrea.io/antrea/pkg/agent/openflow"
 	"antrea.io/antrea/pkg/agent/types"
 	"antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
 	binding "antrea.io/antrea/pkg/ovs/openflow"
 	"antrea.io/antrea/pkg/util/ip"
 )
 
 var (
 	baselineTierPriority int32 = 253
+
+	// dropAction is the action of the rules whose L7 protocols haven't been
+	// accepted by the L7 engine.
+	dropAction = crdv1alpha1.RuleActionDrop
 )
 
 // Reconciler is an interface that knows how to reconcile the desired state of
@@ -101,28 +106,31 @@ func normalizeServices(services []v1beta2.Service) servicesKey {
 //
 // NetworkPolicy rule:
 // spec:
-//  ingress:
-//  - from:
-//    - namespaceSelector: {}
-//    ports:
-//    - port: http
-//      protocol: TCP
+//
+//	ingress:
+//	- from:
+//	  - namespaceSelector: {}
+//	  ports:
+//	  - port: http
+//	    protocol: TCP
 //
 // Pod A and Pod B:
 // spec:
-//   containers:
-//   - ports:
-//     - containerPort: 80
-//       name: http
-//       protocol: TCP
+//
+//	containers:
+//	- ports:
+//	  - containerPort: 80
+//	    name: http
+//	    protocol: TCP
 //
 // Pod C:
 // spec:
-//   containers:
-//   - ports:
-//     - containerPort: 8080
-//       name: http
-//       protocol: TCP
+//
+//	containers:
+//	- ports:
+//	  - containerPort: 8080
+//	    name: http
+//	    protocol: TCP
 //
 // Then Pod A and B will share an Openflow rule as both of them resolve "http" to 80,
 // while Pod C will have another Openflow rule as it resolves "http" to 8080.
@@ -148,6 +156,22 @@ type lastRealized struct {
 	// It's same in all Openflow rules, because named port is only for
 	// destination Pods.
 	podIPs sets.String
+	// The IP set we have realized for the FQDNs of the rule. It's only used
+	// for egress rule as part of its "to" addresses, in the Openflow rule of
+	// the original services.
+	fqdnIPs sets.String
+	// The VLAN ID allocated to the rule by the L7 engine. It's only set for
+	// the rules with L7 protocols, once the L7 engine has accepted them.
+	l7RuleVlanID *uint32
+	// hostRule is true if the rule is applied to the local Node, in which
+	// case it's enforced by the hostFirewall and has no Openflow rule.
+	hostRule bool
+}
+
+// isL7Pending returns whether the rule has L7 protocols which the L7 engine
+// hasn't accepted yet, in which case its Openflow rules drop the traffic.
+func (r *lastRealized) isL7Pending() bool {
+	return len(r.L7Protocols) > 0 && r.l7RuleVlanID == nil
 }
 
 func newLastRealized(rule *CompletedRule) *lastRealized {
@@ -190,6 +214,15 @@ type reconciler struct {
 	ipv4Enabled bool
 	// ipv6Enabled tells is IPv6 is supported on this Node or not.
 	ipv6Enabled bool
+	// fqdnController provides the IPs of the FQDNs used in egress rules.
+	// It's nil if Antrea-native policies are not enabled.
+	fqdnController *fqdnController
+	// l7Engine enforces the L7 protocols of Antrea-native policy rules.
+	// It's nil if the L7NetworkPolicy feature is not enabled.
+	l7Engine *l7Engine
+	// hostFirewall enforces the rules applied to the local Node.
+	// It's nil if Antrea-native policies are not enabled.
+	hostFirewall hostFirewall
 }
 
 // newReconciler returns a new *reconciler.
@@ -232,10 +265,21 @@ func (r *reconciler) RunIDAllocatorWorker(stopCh <-chan struct{}) {
 // invoke the add or update method accordingly.
 func (r *reconciler) Reconcile(rule *CompletedRule) error {
 	klog.Infof("Reconciling rule %s of NetworkPolicy %s", rule.ID, rule.SourceRef.ToString())
+	if isHostRule(rule) {
+		return r.reconcileHostRule(rule)
+	}
 	var err error
 	var ofPriority *uint16
 
 	value, exists := r.lastRealizeds.Load(rule.ID)
+	if exists && value.(*lastRealized).hostRule {
+		// The rule was applied to the local Node, its host rule must be
+		// removed before its Openflow rules are installed.
+		if err := r.Forget(rule.ID); err != nil {
+			return err
+		}
+		exists = false
+	}
 	ruleTable := r.getOFRuleTable(rule)
 	priorityAssigner, _ := r.priorityAssigners[ruleTable]
 	if rule.isAntreaNetworkPolicyRule() {
@@ -258,9 +302,32 @@ func (r *reconciler) Reconcile(rule *CompletedRule) error {
 	if ofRuleInstallErr != nil && ofPriority != nil && !registeredBefore {
 		priorityAssigner.assigner.Release(*ofPriority)
 	}
+	// Release the DNS responses held until the rule is realized with the IPs
+	// learned from them.
+	if ofRuleInstallErr == nil && len(rule.To.FQDNs) > 0 && r.fqdnController != nil {
+		r.fqdnController.ruleRealized(rule.ID)
+	}
 	return ofRuleInstallErr
 }
 
+// reconcileHostRule enforces a rule applied to the local Node with the
+// hostFirewall.
+func (r *reconciler) reconcileHostRule(rule *CompletedRule) error {
+	if r.hostFirewall == nil {
+		return fmt.Errorf("rule %s is applied to the Node but Antrea-native policies are not enabled", rule.ID)
+	}
+	if value, exists := r.lastRealizeds.Load(rule.ID); exists && !value.(*lastRealized).hostRule {
+		if err := r.Forget(rule.ID); err != nil {
+			return err
+		}
+	}
+	if err := r.hostFirewall.addOrUpdateRule(rule); err != nil {
+		return fmt.Errorf("error enforcing host rule %s: %v", rule.ID, err)
+	}
+	r.lastRealizeds.Store(rule.ID, &lastRealized{CompletedRule: rule, hostRule: true})
+	return nil
+}
+
 // getOFRuleTable retreives the OpenFlow table to install the CompletedRule.
 // The decision is made based on whether the rule is created for a CNP/ANP, and
 // the Tier of that NetworkPolicy.
@@ -328,12 +395,14 @@ func (r *reconciler) getOFPriority(rule *CompletedRule, table binding.TableIDTyp
 // with the actual state of Openflow entries in batch. It should only be invoked
 // if all rules are newly added without last realized status.
 func (r *reconciler) BatchReconcile(rules []*CompletedRule) error {
-	var rulesToInstall []*CompletedRule
+	var rulesToInstall, hostRulesToInstall []*CompletedRule
 	var priorities []*uint16
 	prioritiesByTable := map[binding.TableIDType][]*uint16{}
 	for _, rule := range rules {
 		if _, exists := r.lastRealizeds.Load(rule.ID); exists {
 			klog.Errorf("rule %s already realized during the initialization phase", rule.ID)
+		} else if isHostRule(rule) {
+			hostRulesToInstall = append(hostRulesToInstall, rule)
 		} else {
 			rulesToInstall = append(rulesToInstall, rule)
 		}
@@ -361,8 +430,30 @@ func (r *reconciler) BatchReconcile(rules []*CompletedRule) error {
 				pa.assigner.Release(*ofPriority)
 			}
 		}
+		return ofRuleInstallErr
 	}
-	return ofRuleInstallErr
+	// The host rules are always synced, so that the stale host rules of the
+	// previous run of the agent are removed.
+	if r.hostFirewall != nil {
+		if err := r.hostFirewall.batchAddRules(hostRulesToInstall); err != nil {
+			return fmt.Errorf("error enforcing host rules: %v", err)
+		}
+		for _, rule := range hostRulesToInstall {
+			r.lastRealizeds.Store(rule.ID, &lastRealized{CompletedRule: rule, hostRule: true})
+		}
+	} else if len(hostRulesToInstall) > 0 {
+		return fmt.Errorf("%d rules are applied to the Node but Antrea-native policies are not enabled", len(hostRulesToInstall))
+	}
+	var l7PendingRules []string
+	for _, rule := range rulesToInstall {
+		if value, exists := r.lastRealizeds.Load(rule.ID); exists && value.(*lastRealized).isL7Pending() {
+			l7PendingRules = append(l7PendingRules, rule.ID)
+		}
+	}
+	if len(l7PendingRules) > 0 {
+		return fmt.Errorf("rules %v are dropping traffic until the L7 engine accepts them", l7PendingRules)
+	}
+	return nil
 }
 
 // registerOFPriorities constructs a Priority type for each CompletedRule in the input list,
@@ -389,16 +480,40 @@ func (r *reconciler) registerOFPriorities(rules []*CompletedRule) error {
 }
 
 // add converts CompletedRule to PolicyRule(s) and invokes installOFRule to install them.
+// If the L7 engine fails to accept the rule, the rule is installed with the Drop
+// action and the error is returned, so that the rule is reconciled again.
 func (r *reconciler) add(rule *CompletedRule, ofPriority *uint16, table binding.TableIDType) error {
 	klog.V(2).Infof("Adding new rule %v", rule)
-	ofRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriority, table)
+	ofRuleByServicesMap, lastRealized, l7Err := r.computeOFRulesForAdd(rule, ofPriority, table)
+	if err := r.installOFRules(ofRuleByServicesMap, lastRealized, table); err != nil {
+		if releaseErr := r.releaseRuleResources(rule); releaseErr != nil {
+			klog.Errorf("Error when releasing the resources of rule %s: %v", rule.ID, releaseErr)
+		}
+		return err
+	}
+	// Record the rule only if all its Openflow rules are installed successfully.
+	r.lastRealizeds.Store(rule.ID, lastRealized)
+	return l7Err
+}
+
+// installOFRules installs the Openflow rules of a rule and records their IDs in
+// lastRealized. If any of them fails to be installed, the installed ones are
+// uninstalled.
+func (r *reconciler) installOFRules(ofRuleByServicesMap map[servicesKey]*types.PolicyRule, lastRealized *lastRealized, table binding.TableIDType) error {
 	for svcKey, ofRule := range ofRuleByServicesMap {
 		// Each pod group gets an Openflow ID.
 		err := r.idAllocator.allocateForRule(ofRule)
-		if err != nil {
-			return fmt.Errorf("error allocating Openflow ID")
+		if err == nil {
+			err = r.installOFRule(ofRule)
+		} else {
+			err = fmt.Errorf("error allocating Openflow ID")
 		}
-		if err = r.installOFRule(ofRule); err != nil {
+		if err != nil {
+			for _, ofID := range lastRealized.ofIDs {
+				if uninstallErr := r.uninstallOFRule(ofID, table); uninstallErr != nil {
+					klog.Errorf("Error when reverting the installed Openflow rules: %v", uninstallErr)
+				}
+			}
 			return err
 		}
 		// Record ofID only if its Openflow is installed successfully.
@@ -407,11 +522,22 @@ func (r *reconciler) add(rule *CompletedRule, ofPriority *uint16, table binding.
 	return nil
 }
 
+// computeOFRulesForAdd converts a CompletedRule to PolicyRule(s) and a
+// lastRealized which can be recorded once the PolicyRules are installed. If
+// the rule has L7 protocols and the L7 engine fails to accept it, the
+// PolicyRules drop the traffic selected by the rule, and the error of the L7
+// engine is returned along with them.
 func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint16, table binding.TableIDType) (
-	map[servicesKey]*types.PolicyRule, *lastRealized) {
+	map[servicesKey]*types.PolicyRule, *lastRealized, error) {
 	lastRealized := newLastRealized(rule)
-	// TODO: Handle the case that the following processing fails or partially succeeds.
-	r.lastRealizeds.Store(rule.ID, lastRealized)
+
+	var l7Err error
+	if len(rule.L7Protocols) > 0 {
+		lastRealized.l7RuleVlanID, l7Err = r.getL7RuleVlanID(rule)
+		if l7Err != nil {
+			klog.Errorf("Dropping the traffic of rule %s until the L7 engine accepts it: %v", rule.ID, l7Err)
+		}
+	}
 
 	ofRuleByServicesMap := map[servicesKey]*types.PolicyRule{}
 
@@ -426,16 +552,18 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 			ofPorts := r.getOFPorts(members)
 			lastRealized.podOFPorts[svcKey] = ofPorts
 			ofRuleByServicesMap[svcKey] = &types.PolicyRule{
-				Direction:     v1beta2.DirectionIn,
-				From:          append(from1, from2...),
-				To:            ofPortsToOFAddresses(ofPorts),
-				Service:       filterUnresolvablePort(servicesMap[svcKey]),
-				Action:        rule.Action,
-				Name:          rule.Name,
-				Priority:      ofPriority,
-				TableID:       table,
-				PolicyRef:     rule.SourceRef,
-				EnableLogging: rule.EnableLogging,
+				Direction:       v1beta2.DirectionIn,
+				From:            append(from1, from2...),
+				To:              ofPortsToOFAddresses(ofPorts),
+				Service:         filterUnresolvablePort(servicesMap[svcKey]),
+				Action:          rule.Action,
+				Name:            rule.Name,
+				Priority:        ofPriority,
+				TableID:         table,
+				PolicyRef:       rule.SourceRef,
+				EnableLogging:   rule.EnableLogging,
+				EnforcementMode: rule.EnforcementMode,
+				TierPriority:    rule.TierPriority,
 			}
 		}
 	} else {
@@ -445,40 +573,44 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 		memberByServicesMap, servicesMap := groupMembersByServices(rule.Services, rule.ToAddresses)
 		for svcKey, members := range memberByServicesMap {
 			ofRuleByServicesMap[svcKey] = &types.PolicyRule{
-				Direction:     v1beta2.DirectionOut,
-				From:          from,
-				To:            groupMembersToOFAddresses(members),
-				Service:       filterUnresolvablePort(servicesMap[svcKey]),
-				Action:        rule.Action,
-				Priority:      ofPriority,
-				Name:          rule.Name,
-				TableID:       table,
-				PolicyRef:     rule.SourceRef,
-				EnableLogging: rule.EnableLogging,
+				Direction:       v1beta2.DirectionOut,
+				From:            from,
+				To:              groupMembersToOFAddresses(members),
+				Service:         filterUnresolvablePort(servicesMap[svcKey]),
+				Action:          rule.Action,
+				Priority:        ofPriority,
+				Name:            rule.Name,
+				TableID:         table,
+				PolicyRef:       rule.SourceRef,
+				EnableLogging:   rule.EnableLogging,
+				EnforcementMode: rule.EnforcementMode,
+				TierPriority:    rule.TierPriority,
 			}
 		}
 
 		// If there are no "ToAddresses", the above process doesn't create any PolicyRule.
 		// We must ensure there is at least one PolicyRule, otherwise the Pods won't be
 		// isolated, so we create a PolicyRule with the original services if it doesn't exist.
-		// If there are IPBlocks or Pods that cannot resolve any named port, they will share
-		// this PolicyRule. Antrea policies do not need this default isolation.
-		if !rule.isAntreaNetworkPolicyRule() || len(rule.To.IPBlocks) > 0 {
+		// If there are IPBlocks, FQDNs or Pods that cannot resolve any named port, they will
+		// share this PolicyRule. Antrea policies do not need this default isolation.
+		if !rule.isAntreaNetworkPolicyRule() || len(rule.To.IPBlocks) > 0 || len(rule.To.FQDNs) > 0 {
 			svcKey := normalizeServices(rule.Services)
 			ofRule, exists := ofRuleByServicesMap[svcKey]
 			// Create a new Openflow rule if the group doesn't exist.
 			if !exists {
 				ofRule = &types.PolicyRule{
-					Direction:     v1beta2.DirectionOut,
-					From:          from,
-					To:            []types.Address{},
-					Service:       filterUnresolvablePort(rule.Services),
-					Action:        rule.Action,
-					Name:          rule.Name,
-					Priority:      nil,
-					TableID:       table,
-					PolicyRef:     rule.SourceRef,
-					EnableLogging: rule.EnableLogging,
+					Direction:       v1beta2.DirectionOut,
+					From:            from,
+					To:              []types.Address{},
+					Service:         filterUnresolvablePort(rule.Services),
+					Action:          rule.Action,
+					Name:            rule.Name,
+					Priority:        nil,
+					TableID:         table,
+					PolicyRef:       rule.SourceRef,
+					EnableLogging:   rule.EnableLogging,
+					EnforcementMode: rule.EnforcementMode,
+					TierPriority:    rule.TierPriority,
 				}
 				ofRuleByServicesMap[svcKey] = ofRule
 			}
@@ -487,9 +619,20 @@ func (r *reconciler) computeOFRulesForAdd(rule *CompletedRule, ofPriority *uint1
 				to := ipBlocksToOFAddresses(rule.To.IPBlocks, r.ipv4Enabled, r.ipv6Enabled)
 				ofRule.To = append(ofRule.To, to...)
 			}
+			if len(rule.To.FQDNs) > 0 {
+				fqdnIPs := r.getFQDNIPs(rule)
+				lastRealized.fqdnIPs = fqdnIPs
+				ofRule.To = append(ofRule.To, ipsToOFAddresses(fqdnIPs)...)
+			}
+		}
+	}
+	for _, ofRule := range ofRuleByServicesMap {
+		if l7Err != nil {
+			ofRule.Action = &dropAction
 		}
+		ofRule.L7RuleVlanID = lastRealized.l7RuleVlanID
 	}
-	return ofRuleByServicesMap, lastRealized
+	return ofRuleByServicesMap, lastRealized, l7Err
 }
 
 // batchAdd converts CompletedRules to PolicyRules and invokes BatchInstallPolicyRuleFlows to install them.
@@ -501,7 +644,9 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 
 	for idx, rule := range rules {
 		ruleTable := r.getOFRuleTable(rule)
-		ofRuleByServicesMap, lastRealized := r.computeOFRulesForAdd(rule, ofPriorities[idx], ruleTable)
+		// The rules which the L7 engine fails to accept are installed with the
+		// Drop action, BatchReconcile returns an error for them once installed.
+		ofRuleByServicesMap, lastRealized, _ := r.computeOFRulesForAdd(rule, ofPriorities[idx], ruleTable)
 		lastRealizeds[idx] = lastRealized
 		for svcKey, ofRule := range ofRuleByServicesMap {
 			err := r.idAllocator.allocateForRule(ofRule)
@@ -519,6 +664,11 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 		for _, rule := range allOFRules {
 			r.idAllocator.forgetRule(rule.FlowID)
 		}
+		for _, rule := range rules {
+			if releaseErr := r.releaseRuleResources(rule); releaseErr != nil {
+				klog.Errorf("Error when releasing the resources of rule %s: %v", rule.ID, releaseErr)
+			}
+		}
 		return err
 	}
 	for i, lastRealized := range lastRealizeds {
@@ -526,6 +676,7 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 		for svcKey, ofID := range ofIDUpdatesByRule {
 			lastRealized.ofIDs[svcKey] = ofID
 		}
+		r.lastRealizeds.Store(lastRealized.ID, lastRealized)
 	}
 	return nil
 }
@@ -534,6 +685,16 @@ func (r *reconciler) batchAdd(rules []*CompletedRule, ofPriorities []*uint16) er
 // and invokes Openflow client's methods to reconcile them.
 func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule, ofPriority *uint16, table binding.TableIDType) error {
 	klog.V(2).Infof("Updating existing rule %v", newRule)
+	// The rule must be unregistered from the FQDN controller if the update
+	// removes its FQDNs.
+	if len(lastRealized.To.FQDNs) > 0 && len(newRule.To.FQDNs) == 0 && r.fqdnController != nil {
+		if err := r.fqdnController.deleteFQDNRule(newRule.ID); err != nil {
+			return err
+		}
+	}
+	if lastRealized.isL7Pending() {
+		return r.reinstallL7PendingRule(lastRealized, newRule, ofPriority, table)
+	}
 	// staleOFIDs tracks servicesKey that are no long needed.
 	// Firstly fill it with the last realized ofIDs.
 	staleOFIDs := make(map[servicesKey]uint32, len(lastRealized.ofIDs))
@@ -556,16 +717,19 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 			// Install a new Openflow rule if this group doesn't exist, otherwise do incremental update.
 			if !exists {
 				ofRule := &types.PolicyRule{
-					Direction:     v1beta2.DirectionIn,
-					From:          append(from1, from2...),
-					To:            ofPortsToOFAddresses(newOFPorts),
-					Service:       filterUnresolvablePort(servicesMap[svcKey]),
-					Action:        newRule.Action,
-					Priority:      ofPriority,
-					FlowID:        ofID,
-					TableID:       table,
-					PolicyRef:     newRule.SourceRef,
-					EnableLogging: newRule.EnableLogging,
+					Direction:       v1beta2.DirectionIn,
+					From:            append(from1, from2...),
+					To:              ofPortsToOFAddresses(newOFPorts),
+					Service:         filterUnresolvablePort(servicesMap[svcKey]),
+					Action:          newRule.Action,
+					Priority:        ofPriority,
+					FlowID:          ofID,
+					TableID:         table,
+					PolicyRef:       newRule.SourceRef,
+					EnableLogging:   newRule.EnableLogging,
+					EnforcementMode: newRule.EnforcementMode,
+					TierPriority:    newRule.TierPriority,
+					L7RuleVlanID:    lastRealized.l7RuleVlanID,
 				}
 				err := r.idAllocator.allocateForRule(ofRule)
 				if err != nil {
@@ -591,6 +755,10 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 		from := ipsToOFAddresses(newIPs)
 		addedFrom := ipsToOFAddresses(newIPs.Difference(lastRealized.podIPs))
 		deletedFrom := ipsToOFAddresses(lastRealized.podIPs.Difference(newIPs))
+		var newFQDNIPs sets.String
+		if len(newRule.To.FQDNs) > 0 {
+			newFQDNIPs = r.getFQDNIPs(newRule)
+		}
 
 		memberByServicesMap, servicesMap := groupMembersByServices(newRule.Services, newRule.ToAddresses)
 		// Same as the process in `add`, we must ensure the group for the original services is present
@@ -605,16 +773,19 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 			ofID, exists := lastRealized.ofIDs[svcKey]
 			if !exists {
 				ofRule := &types.PolicyRule{
-					Direction:     v1beta2.DirectionOut,
-					From:          from,
-					To:            groupMembersToOFAddresses(members),
-					Service:       filterUnresolvablePort(servicesMap[svcKey]),
-					Action:        newRule.Action,
-					Priority:      ofPriority,
-					FlowID:        ofID,
-					TableID:       table,
-					PolicyRef:     newRule.SourceRef,
-					EnableLogging: newRule.EnableLogging,
+					Direction:       v1beta2.DirectionOut,
+					From:            from,
+					To:              groupMembersToOFAddresses(members),
+					Service:         filterUnresolvablePort(servicesMap[svcKey]),
+					Action:          newRule.Action,
+					Priority:        ofPriority,
+					FlowID:          ofID,
+					TableID:         table,
+					PolicyRef:       newRule.SourceRef,
+					EnableLogging:   newRule.EnableLogging,
+					EnforcementMode: newRule.EnforcementMode,
+					TierPriority:    newRule.TierPriority,
+					L7RuleVlanID:    lastRealized.l7RuleVlanID,
 				}
 				// If the PolicyRule for the original services doesn't exist and IPBlocks is present, it means the
 				// reconciler hasn't installed flows for IPBlocks, then it must be added to the new PolicyRule.
@@ -622,6 +793,10 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 					to := ipBlocksToOFAddresses(newRule.To.IPBlocks, r.ipv4Enabled, r.ipv6Enabled)
 					ofRule.To = append(ofRule.To, to...)
 				}
+				// Same for the IPs of the FQDNs.
+				if svcKey == originalSvcKey && len(newFQDNIPs) > 0 {
+					ofRule.To = append(ofRule.To, ipsToOFAddresses(newFQDNIPs)...)
+				}
 				err := r.idAllocator.allocateForRule(ofRule)
 				if err != nil {
 					return fmt.Errorf("error allocating Openflow ID")
@@ -633,6 +808,10 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 			} else {
 				addedTo := groupMembersToOFAddresses(members.Difference(prevMembersByServicesMap[svcKey]))
 				deletedTo := groupMembersToOFAddresses(prevMembersByServicesMap[svcKey].Difference(members))
+				if svcKey == originalSvcKey {
+					addedTo = append(addedTo, ipsToOFAddresses(newFQDNIPs.Difference(lastRealized.fqdnIPs))...)
+					deletedTo = append(deletedTo, ipsToOFAddresses(lastRealized.fqdnIPs.Difference(newFQDNIPs))...)
+				}
 				if err := r.updateOFRule(ofID, addedFrom, addedTo, deletedFrom, deletedTo, ofPriority); err != nil {
 					return err
 				}
@@ -641,6 +820,7 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 			}
 		}
 		lastRealized.podIPs = newIPs
+		lastRealized.fqdnIPs = newFQDNIPs
 	}
 	// Remove stale Openflow rules.
 	for svcKey, ofID := range staleOFIDs {
@@ -654,6 +834,28 @@ func (r *reconciler) update(lastRealized *lastRealized, newRule *CompletedRule,
 	return nil
 }
 
+// reinstallL7PendingRule retries to add a rule which was installed with the
+// Drop action because the L7 engine failed to accept it. If the L7 engine
+// accepts it, the Openflow rules redirecting its traffic to the L7 engine are
+// installed before the ones dropping its traffic are uninstalled, so that the
+// traffic is never allowed without being inspected.
+func (r *reconciler) reinstallL7PendingRule(lastRealized *lastRealized, newRule *CompletedRule, ofPriority *uint16, table binding.TableIDType) error {
+	ofRuleByServicesMap, newLastRealized, l7Err := r.computeOFRulesForAdd(newRule, ofPriority, table)
+	if l7Err != nil {
+		return l7Err
+	}
+	if err := r.installOFRules(ofRuleByServicesMap, newLastRealized, table); err != nil {
+		return err
+	}
+	r.lastRealizeds.Store(newRule.ID, newLastRealized)
+	for _, ofID := range lastRealized.ofIDs {
+		if err := r.uninstallOFRule(ofID, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *reconciler) installOFRule(ofRule *types.PolicyRule) error {
 	klog.V(2).Infof("Installing ofRule %d (Direction: %v, From: %d, To: %d, Service: %d)",
 		ofRule.FlowID, ofRule.Direction, len(ofRule.From), len(ofRule.To), len(ofRule.Service))
@@ -726,6 +928,13 @@ func (r *reconciler) Forget(ruleID string) error {
 	}
 
 	lastRealized := value.(*lastRealized)
+	if lastRealized.hostRule {
+		if err := r.hostFirewall.deleteRule(ruleID); err != nil {
+			return fmt.Errorf("error deleting host rule %s: %v", ruleID, err)
+		}
+		r.lastRealizeds.Delete(ruleID)
+		return nil
+	}
 	table := r.getOFRuleTable(lastRealized.CompletedRule)
 	priorityAssigner, exists := r.priorityAssigners[table]
 	if exists {
@@ -739,11 +948,32 @@ func (r *reconciler) Forget(ruleID string) error {
 		delete(lastRealized.ofIDs, svcKey)
 		delete(lastRealized.podOFPorts, svcKey)
 	}
+	if err := r.releaseRuleResources(lastRealized.CompletedRule); err != nil {
+		return err
+	}
 
 	r.lastRealizeds.Delete(ruleID)
 	return nil
 }
 
+// releaseRuleResources unregisters a rule from the FQDN controller and the L7
+// engine, which it's registered to when its PolicyRules are computed.
+func (r *reconciler) releaseRuleResources(rule *CompletedRule) error {
+	if len(rule.To.FQDNs) > 0 && r.fqdnController != nil {
+		if err := r.fqdnController.deleteFQDNRule(rule.ID); err != nil {
+			return err
+		}
+	}
+	// The rule may have been accepted by the L7 engine even if its Openflow
+	// rules are still dropping its traffic.
+	if len(rule.L7Protocols) > 0 && r.l7Engine != nil {
+		if err := r.l7Engine.deleteRule(rule.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *reconciler) GetRuleByFlowID(ruleFlowID uint32) (*types.PolicyRule, bool, error) {
 	return r.idAllocator.getRuleFromAsyncCache(ruleFlowID)
 }
@@ -771,6 +1001,34 @@ func (r *reconciler) getOFPorts(members v1beta2.GroupMemberSet) sets.Int32 {
 	return ofPorts
 }
 
+// getFQDNIPs returns the IPs currently known for the FQDNs of an egress rule.
+func (r *reconciler) getFQDNIPs(rule *CompletedRule) sets.String {
+	if r.fqdnController == nil {
+		klog.Warningf("Ignoring the FQDNs of rule %s as Antrea-native policies are not enabled", rule.ID)
+		return sets.NewString()
+	}
+	ips, err := r.fqdnController.addFQDNRule(rule.ID, rule.To.FQDNs)
+	if err != nil {
+		klog.Errorf("Error getting the IPs of the FQDNs of rule %s: %v", rule.ID, err)
+		return sets.NewString()
+	}
+	return ips
+}
+
+// getL7RuleVlanID registers the L7 protocols of a rule to the L7 engine and
+// returns the VLAN ID allocated to the rule, with which the packets allowed by
+// the rule are redirected to the L7 engine.
+func (r *reconciler) getL7RuleVlanID(rule *CompletedRule) (*uint32, error) {
+	if r.l7Engine == nil {
+		return nil, fmt.Errorf("rule %s has L7 protocols but the L7NetworkPolicy feature is not enabled", rule.ID)
+	}
+	vlanID, err := r.l7Engine.addRule(rule.ID, rule.SourceRef.ToString(), rule.L7Protocols, rule.EnableLogging)
+	if err != nil {
+		return nil, fmt.Errorf("error adding rule %s to the L7 engine: %v", rule.ID, err)
+	}
+	return &vlanID, nil
+}
+
 func (r *reconciler) getIPs(members v1beta2.GroupMemberSet) sets.String {
 	ips := sets.NewString()
 	for _, m := range members {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -590,10 +591,9 @@ func TestReconcileWithTransientError(t *testing.T) {
 	mockOFClient.EXPECT().InstallPolicyRuleFlows(gomock.Any()).Return(transientError).Times(1)
 	err := r.Reconcile(egressRule)
 	assert.Error(t, err)
-	// Ensure the openflow ID is not persistent in lastRealized and is released to idAllocator upon error.
-	value, exists := r.lastRealizeds.Load(egressRule.ID)
-	assert.True(t, exists)
-	assert.Empty(t, value.(*lastRealized).ofIDs)
+	// Ensure the rule is not recorded in lastRealizeds and the openflow ID is released to idAllocator upon error.
+	_, exists := r.lastRealizeds.Load(egressRule.ID)
+	assert.False(t, exists)
 	assert.Equal(t, 1, r.idAllocator.deleteQueue.Len())
 
 	// Make the second call success.
@@ -630,7 +630,7 @@ func TestReconcileWithTransientError(t *testing.T) {
 	err = r.Reconcile(egressRule)
 	assert.NoError(t, err)
 	// Ensure the openflow IDs are persistent in lastRealized and are not released to idAllocator upon success.
-	value, exists = r.lastRealizeds.Load(egressRule.ID)
+	value, exists := r.lastRealizeds.Load(egressRule.ID)
 	assert.True(t, exists)
 	assert.Len(t, value.(*lastRealized).ofIDs, 3)
 	// Ensure the number of released IDs doesn't change.
@@ -641,6 +641,88 @@ func TestReconcileWithTransientError(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// fakeHostFirewall implements hostFirewall. It records the rules it enforces.
+type fakeHostFirewall struct {
+	rules map[string]*CompletedRule
+}
+
+func (f *fakeHostFirewall) batchAddRules(rules []*CompletedRule) error {
+	f.rules = map[string]*CompletedRule{}
+	for _, rule := range rules {
+		f.rules[rule.ID] = rule
+	}
+	return nil
+}
+
+func (f *fakeHostFirewall) addOrUpdateRule(rule *CompletedRule) error {
+	f.rules[rule.ID] = rule
+	return nil
+}
+
+func (f *fakeHostFirewall) deleteRule(ruleID string) error {
+	delete(f.rules, ruleID)
+	return nil
+}
+
+func TestReconcilerReconcileNodeMember(t *testing.T) {
+	nodeMember := func(ip string) *v1beta2.GroupMember {
+		return &v1beta2.GroupMember{
+			Node: &v1beta2.NodeReference{Name: "node1"},
+			IPs:  []v1beta2.IPAddress{v1beta2.IPAddress(net.ParseIP(ip))},
+		}
+	}
+	ingressRule := &CompletedRule{
+		rule:          &rule{ID: "ingress-rule", Direction: v1beta2.DirectionIn, Services: services1, SourceRef: &cnp1},
+		FromAddresses: addressGroup1,
+		TargetMembers: v1beta2.NewGroupMemberSet(nodeMember("192.168.0.1")),
+	}
+	egressRule := &CompletedRule{
+		rule:          &rule{ID: "egress-rule", Direction: v1beta2.DirectionOut, Services: services1, SourceRef: &cnp1},
+		ToAddresses:   addressGroup1,
+		TargetMembers: v1beta2.NewGroupMemberSet(nodeMember("192.168.0.1")),
+	}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	// No Openflow rule is expected to be installed for the rules applied to the Node.
+	mockOFClient := openflowtest.NewMockClient(controller)
+	mockOFClient.EXPECT().IsIPv4Enabled().Return(true).AnyTimes()
+	mockOFClient.EXPECT().IsIPv6Enabled().Return(false).AnyTimes()
+	mockOFClient.EXPECT().BatchInstallPolicyRuleFlows(nil).Return(nil).Times(2)
+	r := newReconciler(mockOFClient, interfacestore.NewInterfaceStore(), testAsyncDeleteInterval)
+
+	// The rules applied to the Node are rejected if there is no host firewall.
+	assert.Error(t, r.Reconcile(ingressRule))
+	assert.Error(t, r.BatchReconcile([]*CompletedRule{ingressRule}))
+
+	hostFirewall := &fakeHostFirewall{}
+	r.hostFirewall = hostFirewall
+	require.NoError(t, r.BatchReconcile([]*CompletedRule{ingressRule}))
+	assert.Equal(t, map[string]*CompletedRule{ingressRule.ID: ingressRule}, hostFirewall.rules)
+	value, exists := r.lastRealizeds.Load(ingressRule.ID)
+	require.True(t, exists)
+	assert.True(t, value.(*lastRealized).hostRule)
+	assert.Empty(t, value.(*lastRealized).ofIDs)
+
+	require.NoError(t, r.Reconcile(egressRule))
+	assert.Len(t, hostFirewall.rules, 2)
+
+	// Changing the Node IP updates the host rule.
+	newIngressRule := &CompletedRule{
+		rule:          ingressRule.rule,
+		FromAddresses: addressGroup1,
+		TargetMembers: v1beta2.NewGroupMemberSet(nodeMember("192.168.0.2")),
+	}
+	require.NoError(t, r.Reconcile(newIngressRule))
+	assert.Equal(t, newIngressRule, hostFirewall.rules[ingressRule.ID])
+
+	require.NoError(t, r.Forget(ingressRule.ID))
+	require.NoError(t, r.Forget(egressRule.ID))
+	assert.Empty(t, hostFirewall.rules)
+	_, exists = r.lastRealizeds.Load(ingressRule.ID)
+	assert.False(t, exists)
+}
+
 func TestReconcilerBatchReconcile(t *testing.T) {
 	ifaceStore := interfacestore.NewInterfaceStore()
 	ifaceStore.AddInterface(&interfacestore.InterfaceConfig{
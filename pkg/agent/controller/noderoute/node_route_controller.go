@@ -22,6 +22,7 @@ import (
 	"github.com/containernetworking/plugins/pkg/ip"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -37,6 +38,9 @@ import (
 	"antrea.io/antrea/pkg/agent/route"
 	"antrea.io/antrea/pkg/agent/types"
 	"antrea.io/antrea/pkg/agent/util"
+	crdv1a2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+	crdinformers "antrea.io/antrea/pkg/client/informers/externalversions/crd/v1alpha2"
+	crdlisters "antrea.io/antrea/pkg/client/listers/crd/v1alpha2"
 	"antrea.io/antrea/pkg/ovs/ovsconfig"
 	utilip "antrea.io/antrea/pkg/util/ip"
 	"antrea.io/antrea/pkg/util/k8s"
@@ -55,6 +59,7 @@ const (
 	ovsExternalIDNodeName = "node-name"
 
 	nodeRouteInfoPodCIDRIndexName = "podCIDR"
+	ipPoolNodeIndexName           = "node"
 )
 
 // Controller is responsible for setting up necessary IP routes and Openflow entries for inter-node traffic.
@@ -74,10 +79,19 @@ type Controller struct {
 	// The key is the host name of the Node, the value is the nodeRouteInfo of the Node.
 	// A node will be in the map after its flows and routes are installed successfully.
 	installedNodes cache.Indexer
+	// ipPoolInformer is only set when AntreaIPAM is enabled. The IPs allocated from the IPPools which are not in the
+	// PodCIDR of their Node are routed to the Node like its PodCIDR.
+	ipPoolInformer     crdinformers.IPPoolInformer
+	ipPoolLister       crdlisters.IPPoolLister
+	ipPoolListerSynced cache.InformerSynced
+	// localIPPoolIPs is the set of the IPs allocated from the IPPools on the local Node which are routed to the local
+	// gateway. It is only accessed when syncing the local Node, which the work queue guarantees is not done
+	// concurrently.
+	localIPPoolIPs sets.String
 }
 
 // NewNodeRouteController instantiates a new Controller object which will process Node events
-// and ensure connectivity between different Nodes.
+// and ensure connectivity between different Nodes. ipPoolInformer must be nil if AntreaIPAM is disabled.
 func NewNodeRouteController(
 	kubeClient clientset.Interface,
 	informerFactory informers.SharedInformerFactory,
@@ -86,7 +100,8 @@ func NewNodeRouteController(
 	routeClient route.Interface,
 	interfaceStore interfacestore.InterfaceStore,
 	networkConfig *config.NetworkConfig,
-	nodeConfig *config.NodeConfig) *Controller {
+	nodeConfig *config.NodeConfig,
+	ipPoolInformer crdinformers.IPPoolInformer) *Controller {
 	nodeInformer := informerFactory.Core().V1().Nodes()
 	controller := &Controller{
 		kubeClient:       kubeClient,
@@ -116,9 +131,42 @@ func NewNodeRouteController(
 		},
 		nodeResyncPeriod,
 	)
+	if ipPoolInformer != nil {
+		controller.ipPoolInformer = ipPoolInformer
+		controller.ipPoolLister = ipPoolInformer.Lister()
+		controller.ipPoolListerSynced = ipPoolInformer.Informer().HasSynced
+		controller.localIPPoolIPs = sets.NewString()
+		if err := ipPoolInformer.Informer().AddIndexers(cache.Indexers{ipPoolNodeIndexName: ipPoolNodeIndexFunc}); err != nil {
+			klog.Errorf("Failed to add indexer to IPPool informer: %v", err)
+		}
+		ipPoolInformer.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(cur interface{}) {
+					controller.enqueueIPPoolNodes(cur)
+				},
+				UpdateFunc: func(old, cur interface{}) {
+					controller.enqueueIPPoolNodes(old)
+					controller.enqueueIPPoolNodes(cur)
+				},
+				DeleteFunc: func(old interface{}) {
+					controller.enqueueIPPoolNodes(old)
+				},
+			},
+		)
+	}
 	return controller
 }
 
+func ipPoolNodeIndexFunc(obj interface{}) ([]string, error) {
+	nodeNames := sets.NewString()
+	for _, ipState := range obj.(*crdv1a2.IPPool).Status.IPAddresses {
+		if ipState.NodeName != "" {
+			nodeNames.Insert(ipState.NodeName)
+		}
+	}
+	return nodeNames.List(), nil
+}
+
 func nodeRouteInfoKeyFunc(obj interface{}) (string, error) {
 	return obj.(*nodeRouteInfo).nodeName, nil
 }
@@ -138,6 +186,8 @@ type nodeRouteInfo struct {
 	nodeIP    net.IP
 	gatewayIP []net.IP
 	nodeMAC   net.HardwareAddr
+	// ipPoolIPs are the IPs allocated from the IPPools on the Node which are not in its PodCIDRs.
+	ipPoolIPs []net.IP
 }
 
 // enqueueNode adds an object to the controller work queue
@@ -163,6 +213,69 @@ func (c *Controller) enqueueNode(obj interface{}) {
 	}
 }
 
+// enqueueIPPoolNodes adds the Nodes which have IPs allocated from the IPPool to the work queue. The local Node is
+// always added, as the flows for the gateways of the IPPools are installed on it.
+// obj could be a *crdv1a2.IPPool, or a DeletionFinalStateUnknown item.
+func (c *Controller) enqueueIPPoolNodes(obj interface{}) {
+	pool, isIPPool := obj.(*crdv1a2.IPPool)
+	if !isIPPool {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Received unexpected object: %v", obj)
+			return
+		}
+		pool, ok = deletedState.Obj.(*crdv1a2.IPPool)
+		if !ok {
+			klog.Errorf("DeletedFinalStateUnknown contains non-IPPool object: %v", deletedState.Obj)
+			return
+		}
+	}
+	nodeNames, _ := ipPoolNodeIndexFunc(pool)
+	for _, nodeName := range nodeNames {
+		c.queue.Add(nodeName)
+	}
+	c.queue.Add(c.nodeConfig.Name)
+}
+
+// getIPPoolIPs returns the IPs allocated from the IPPools on the Node which are not in the provided PodCIDRs.
+func (c *Controller) getIPPoolIPs(nodeName string, podCIDRs []*net.IPNet) []net.IP {
+	if c.ipPoolInformer == nil {
+		return nil
+	}
+	pools, _ := c.ipPoolInformer.Informer().GetIndexer().ByIndex(ipPoolNodeIndexName, nodeName)
+	var ips []net.IP
+	for _, obj := range pools {
+		for _, ipState := range obj.(*crdv1a2.IPPool).Status.IPAddresses {
+			if ipState.NodeName != nodeName {
+				continue
+			}
+			ip := net.ParseIP(ipState.IPAddress)
+			if ip == nil || ipInCIDRs(ip, podCIDRs) {
+				continue
+			}
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+func ipInCIDRs(ip net.IP, cidrs []*net.IPNet) bool {
+	for _, cidr := range cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func ipsToStrings(ips []net.IP) sets.String {
+	ipStrs := sets.NewString()
+	for _, ip := range ips {
+		ipStrs.Insert(ip.String())
+	}
+	return ipStrs
+}
+
 // removeStaleGatewayRoutes removes all the gateway routes which no longer correspond to a Node in
 // the cluster. If the antrea agent restarts and Nodes have left the cluster, this function will
 // take care of removing routes which are no longer valid.
@@ -182,6 +295,20 @@ func (c *Controller) removeStaleGatewayRoutes() error {
 		}
 		desiredPodCIDRs = append(desiredPodCIDRs, podCIDRs...)
 	}
+	// The routes to the IPs allocated from the IPPools are added with single-IP CIDRs.
+	if c.ipPoolLister != nil {
+		pools, err := c.ipPoolLister.List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("error when listing IPPools: %v", err)
+		}
+		for _, pool := range pools {
+			for _, ipState := range pool.Status.IPAddresses {
+				if ip := net.ParseIP(ipState.IPAddress); ip != nil && ipState.NodeName != "" {
+					desiredPodCIDRs = append(desiredPodCIDRs, utilip.NewHostIPNet(ip).String())
+				}
+			}
+		}
+	}
 
 	// routeClient will remove orphaned routes whose destinations are not in desiredPodCIDRs.
 	if err := c.routeClient.Reconcile(desiredPodCIDRs); err != nil {
@@ -288,7 +415,11 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	klog.Infof("Starting %s", controllerName)
 	defer klog.Infof("Shutting down %s", controllerName)
 
-	if !cache.WaitForNamedCacheSync(controllerName, stopCh, c.nodeListerSynced) {
+	cacheSyncs := []cache.InformerSynced{c.nodeListerSynced}
+	if c.ipPoolListerSynced != nil {
+		cacheSyncs = append(cacheSyncs, c.ipPoolListerSynced)
+	}
+	if !cache.WaitForNamedCacheSync(controllerName, stopCh, cacheSyncs...) {
 		return
 	}
 
@@ -355,6 +486,7 @@ func (c *Controller) processNextWorkItem() bool {
 //   peerPodCIDR goes through the correct L3 tunnel.
 // If the Node no longer exists (cannot be retrieved by name from nodeLister) we delete the route
 // and OpenFlow flows associated with it.
+// The local Node is only synced when AntreaIPAM is enabled, to route the IPs allocated from the IPPools on it.
 func (c *Controller) syncNodeRoute(nodeName string) error {
 	startTime := time.Now()
 	defer func() {
@@ -365,6 +497,9 @@ func (c *Controller) syncNodeRoute(nodeName string) error {
 	// same Node, which is required by the InstallNodeFlows / UninstallNodeFlows OF Client
 	// methods.
 
+	if nodeName == c.nodeConfig.Name {
+		return c.syncLocalIPPoolRoutes()
+	}
 	node, err := c.nodeLister.Get(nodeName)
 	if err != nil {
 		return c.deleteNodeRoute(nodeName)
@@ -387,6 +522,11 @@ func (c *Controller) deleteNodeRoute(nodeName string) error {
 			return fmt.Errorf("failed to delete the route to Node %s: %v", nodeName, err)
 		}
 	}
+	for _, ip := range nodeRouteInfo.ipPoolIPs {
+		if err := c.routeClient.DeletePodIPRoute(ip); err != nil {
+			return fmt.Errorf("failed to delete the route to IP %s on Node %s: %v", ip, nodeName, err)
+		}
+	}
 	if err := c.ofClient.UninstallNodeFlows(nodeName); err != nil {
 		return fmt.Errorf("failed to uninstall flows to Node %s: %v", nodeName, err)
 	}
@@ -417,12 +557,24 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 
 	nrInfo, installed, _ := c.installedNodes.GetByKey(nodeName)
 
-	if installed && nrInfo.(*nodeRouteInfo).nodeMAC.String() == peerNodeMAC.String() {
-		// Route is already added for this Node and Node MAC isn't changed.
+	podCIDRStrs := getPodCIDRsOnNode(node)
+	var ipPoolIPs []net.IP
+	if c.ipPoolInformer != nil {
+		var nodePodCIDRs []*net.IPNet
+		for _, podCIDR := range podCIDRStrs {
+			if _, cidr, err := net.ParseCIDR(podCIDR); err == nil {
+				nodePodCIDRs = append(nodePodCIDRs, cidr)
+			}
+		}
+		ipPoolIPs = c.getIPPoolIPs(nodeName, nodePodCIDRs)
+	}
+
+	if installed && nrInfo.(*nodeRouteInfo).nodeMAC.String() == peerNodeMAC.String() &&
+		ipsToStrings(nrInfo.(*nodeRouteInfo).ipPoolIPs).Equal(ipsToStrings(ipPoolIPs)) {
+		// Route is already added for this Node and Node MAC and IPPool IPs aren't changed.
 		return nil
 	}
 
-	podCIDRStrs := getPodCIDRsOnNode(node)
 	if len(podCIDRStrs) == 0 {
 		// If no valid PodCIDR is configured in Node.Spec, return immediately.
 		return nil
@@ -486,9 +638,21 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 		}
 	}
 
+	flowPeerConfig := peerConfig
+	if len(ipPoolIPs) > 0 {
+		flowPeerConfig = make(map[*net.IPNet]net.IP, len(peerConfig)+len(ipPoolIPs))
+		for peerPodCIDR, peerGatewayIP := range peerConfig {
+			flowPeerConfig[peerPodCIDR] = peerGatewayIP
+		}
+		// The flows to the IPPool IPs are the same as the flows to the PodCIDRs, with each IP as its own gateway, so
+		// that the local Pods in the same subnet get replies to their ARP requests for it.
+		for _, ip := range ipPoolIPs {
+			flowPeerConfig[utilip.NewHostIPNet(ip)] = ip
+		}
+	}
 	err = c.ofClient.InstallNodeFlows(
 		nodeName,
-		peerConfig,
+		flowPeerConfig,
 		peerNodeIP,
 		uint32(ipsecTunOFPort),
 		peerNodeMAC)
@@ -503,16 +667,97 @@ func (c *Controller) addNodeRoute(nodeName string, node *corev1.Node) error {
 		}
 		peerGatewayIPs = append(peerGatewayIPs, peerGatewayIP)
 	}
+	for _, ip := range ipPoolIPs {
+		peerGatewayIP := getIPOfFamily(peerGatewayIPs, ip.To4() != nil)
+		if peerGatewayIP == nil {
+			klog.Errorf("Node %s has no PodCIDR in the IP family of IP %s allocated from an IPPool", nodeName, ip)
+			continue
+		}
+		if err := c.routeClient.AddPodIPRoute(ip, nodeName, peerNodeIP, peerGatewayIP); err != nil {
+			return err
+		}
+	}
+	if installed {
+		// Delete the routes to the IPs which are no longer allocated on the Node. Their flows are removed by
+		// InstallNodeFlows.
+		ipPoolIPStrs := ipsToStrings(ipPoolIPs)
+		for _, ip := range nrInfo.(*nodeRouteInfo).ipPoolIPs {
+			if ipPoolIPStrs.Has(ip.String()) {
+				continue
+			}
+			if err := c.routeClient.DeletePodIPRoute(ip); err != nil {
+				return err
+			}
+		}
+	}
 	c.installedNodes.Add(&nodeRouteInfo{
 		nodeName:  nodeName,
 		podCIDRs:  podCIDRs,
 		nodeIP:    peerNodeIP,
 		gatewayIP: peerGatewayIPs,
 		nodeMAC:   peerNodeMAC,
+		ipPoolIPs: ipPoolIPs,
 	})
 	return err
 }
 
+func getIPOfFamily(ips []net.IP, isIPv4 bool) net.IP {
+	for _, ip := range ips {
+		if (ip.To4() != nil) == isIPv4 {
+			return ip
+		}
+	}
+	return nil
+}
+
+// syncLocalIPPoolRoutes installs the flows for the gateways of the IPPools, and the routes to the IPs allocated from
+// the IPPools on the local Node which are not in its PodCIDRs.
+func (c *Controller) syncLocalIPPoolRoutes() error {
+	pools, err := c.ipPoolLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error when listing IPPools: %v", err)
+	}
+	var gatewayIPs []net.IP
+	for _, pool := range pools {
+		for _, ipRange := range pool.Spec.IPRanges {
+			if gatewayIP := net.ParseIP(ipRange.Gateway); gatewayIP != nil {
+				gatewayIPs = append(gatewayIPs, gatewayIP)
+			}
+		}
+	}
+	if err := c.ofClient.InstallIPPoolGatewayFlows(gatewayIPs); err != nil {
+		return fmt.Errorf("failed to install flows for IPPool gateways: %v", err)
+	}
+
+	var localPodCIDRs []*net.IPNet
+	for _, podCIDR := range []*net.IPNet{c.nodeConfig.PodIPv4CIDR, c.nodeConfig.PodIPv6CIDR} {
+		if podCIDR != nil {
+			localPodCIDRs = append(localPodCIDRs, podCIDR)
+		}
+	}
+	ips := c.getIPPoolIPs(c.nodeConfig.Name, localPodCIDRs)
+	ipStrs := ipsToStrings(ips)
+	for _, ip := range ips {
+		if c.localIPPoolIPs.Has(ip.String()) {
+			continue
+		}
+		if err := c.routeClient.AddPodIPRoute(ip, c.nodeConfig.Name, nil, nil); err != nil {
+			return err
+		}
+		c.localIPPoolIPs.Insert(ip.String())
+	}
+	for _, ipStr := range c.localIPPoolIPs.List() {
+		if ipStrs.Has(ipStr) {
+			continue
+		}
+		if err := c.routeClient.DeletePodIPRoute(net.ParseIP(ipStr)); err != nil {
+			return err
+		}
+		c.localIPPoolIPs.Delete(ipStr)
+	}
+	return nil
+}
+
 func getPodCIDRsOnNode(node *corev1.Node) []string {
 	if node.Spec.PodCIDRs != nil {
 		return node.Spec.PodCIDRs
@@ -32,6 +32,9 @@ import (
 	"antrea.io/antrea/pkg/agent/interfacestore"
 	oftest "antrea.io/antrea/pkg/agent/openflow/testing"
 	routetest "antrea.io/antrea/pkg/agent/route/testing"
+	crdv1a2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+	fakeversioned "antrea.io/antrea/pkg/client/clientset/versioned/fake"
+	crdinformers "antrea.io/antrea/pkg/client/informers/externalversions"
 	ovsconfigtest "antrea.io/antrea/pkg/ovs/ovsconfig/testing"
 )
 
@@ -66,7 +69,7 @@ func newController(t *testing.T) (*fakeController, func()) {
 	c := NewNodeRouteController(clientset, informerFactory, ofClient, ovsClient, routeClient, interfaceStore, &config.NetworkConfig{}, &config.NodeConfig{GatewayConfig: &config.GatewayConfig{
 		IPv4: nil,
 		MAC:  gatewayMAC,
-	}})
+	}}, nil)
 	return &fakeController{
 		Controller:      c,
 		clientset:       clientset,
@@ -228,3 +231,103 @@ func TestIPInPodSubnets(t *testing.T) {
 	assert.Equal(t, false, c.Controller.IPInPodSubnets(net.ParseIP("10.10.10.10")))
 	assert.Equal(t, false, c.Controller.IPInPodSubnets(net.ParseIP("8.8.8.8")))
 }
+
+func TestControllerWithIPPool(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	crdClient := fakeversioned.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(clientset, 12*time.Hour)
+	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 12*time.Hour)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ofClient := oftest.NewMockClient(ctrl)
+	routeClient := routetest.NewMockInterface(ctrl)
+	nodeConfig := &config.NodeConfig{
+		Name:          "local",
+		PodIPv4CIDR:   podCIDR2,
+		GatewayConfig: &config.GatewayConfig{MAC: gatewayMAC},
+	}
+	c := NewNodeRouteController(clientset, informerFactory, ofClient, ovsconfigtest.NewMockOVSBridgeClient(ctrl), routeClient,
+		interfacestore.NewInterfaceStore(), &config.NetworkConfig{}, nodeConfig, crdInformerFactory.Crd().V1alpha2().IPPools())
+	defer c.queue.ShutDown()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informerFactory.Start(stopCh)
+	crdInformerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	crdInformerFactory.WaitForCacheSync(stopCh)
+
+	pool := &crdv1a2.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool"},
+		Spec: crdv1a2.IPPoolSpec{
+			IPRanges: []crdv1a2.SubnetIPRange{
+				{
+					IPRange:    crdv1a2.IPRange{CIDR: "10.2.0.0/24"},
+					SubnetInfo: crdv1a2.SubnetInfo{Gateway: "10.2.0.1", PrefixLength: 24},
+				},
+			},
+		},
+		Status: crdv1a2.IPPoolStatus{
+			IPAddresses: []crdv1a2.IPAddressState{
+				{IPAddress: "10.2.0.2", Phase: crdv1a2.IPAddressPhaseAllocated, NodeName: "node1"},
+				{IPAddress: "10.2.0.3", Phase: crdv1a2.IPAddressPhaseAllocated, NodeName: "local"},
+				// The IPs in the PodCIDR of their Node are routed with the PodCIDR.
+				{IPAddress: "1.1.2.10", Phase: crdv1a2.IPAddressPhaseAllocated, NodeName: "local"},
+			},
+		},
+	}
+	node1 := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+		},
+		Spec: corev1.NodeSpec{
+			PodCIDR:  podCIDR.String(),
+			PodCIDRs: []string{podCIDR.String()},
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{
+					Type:    corev1.NodeInternalIP,
+					Address: nodeIP1.String(),
+				},
+			},
+		},
+	}
+
+	finishCh := make(chan struct{})
+	go func() {
+		defer close(finishCh)
+
+		// The IPPool enqueues the local Node, then node1 which doesn't exist yet.
+		ofClient.EXPECT().InstallIPPoolGatewayFlows([]net.IP{net.ParseIP("10.2.0.1")}).Times(1)
+		routeClient.EXPECT().AddPodIPRoute(net.ParseIP("10.2.0.3"), "local", nil, nil).Times(1)
+		crdClient.CrdV1alpha2().IPPools().Create(context.TODO(), pool, metav1.CreateOptions{})
+		c.processNextWorkItem()
+		c.processNextWorkItem()
+
+		// The IP allocated on node1 is routed to node1.
+		clientset.CoreV1().Nodes().Create(context.TODO(), node1, metav1.CreateOptions{})
+		ofClient.EXPECT().InstallNodeFlows("node1", gomock.Any(), nodeIP1, uint32(0), nil).Times(1)
+		routeClient.EXPECT().AddRoutes(podCIDR, "node1", nodeIP1, podCIDRGateway).Times(1)
+		routeClient.EXPECT().AddPodIPRoute(net.ParseIP("10.2.0.2"), "node1", nodeIP1, podCIDRGateway).Times(1)
+		c.processNextWorkItem()
+
+		// The routes to the released IPs are deleted.
+		updatedPool := pool.DeepCopy()
+		updatedPool.Status.IPAddresses = nil
+		ofClient.EXPECT().InstallIPPoolGatewayFlows([]net.IP{net.ParseIP("10.2.0.1")}).Times(1)
+		routeClient.EXPECT().DeletePodIPRoute(net.ParseIP("10.2.0.3")).Times(1)
+		ofClient.EXPECT().InstallNodeFlows("node1", gomock.Any(), nodeIP1, uint32(0), nil).Times(1)
+		routeClient.EXPECT().AddRoutes(podCIDR, "node1", nodeIP1, podCIDRGateway).Times(1)
+		routeClient.EXPECT().DeletePodIPRoute(net.ParseIP("10.2.0.2")).Times(1)
+		crdClient.CrdV1alpha2().IPPools().Update(context.TODO(), updatedPool, metav1.UpdateOptions{})
+		c.processNextWorkItem()
+		c.processNextWorkItem()
+	}()
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Errorf("Test didn't finish in time")
+	case <-finishCh:
+	}
+}
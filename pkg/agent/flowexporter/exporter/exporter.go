@@ -48,6 +48,7 @@ var (
 		"octetTotalCount",
 		"packetDeltaCount",
 		"octetDeltaCount",
+		"samplingInterval",
 	}
 	IANAInfoElementsIPv4 = append(IANAInfoElementsCommon, []string{"sourceIPv4Address", "destinationIPv4Address"}...)
 	IANAInfoElementsIPv6 = append(IANAInfoElementsCommon, []string{"sourceIPv6Address", "destinationIPv6Address"}...)
@@ -105,6 +106,7 @@ type flowExporter struct {
 	nodeRouteController *noderoute.Controller
 	isNetworkPolicyOnly bool
 	nodeName            string
+	filter              *FlowFilter
 }
 
 func genObservationID(nodeName string) uint32 {
@@ -134,7 +136,7 @@ func prepareExporterInputArgs(collectorAddr, collectorProto, nodeName string) ex
 func NewFlowExporter(connStore *connections.ConntrackConnectionStore, records *flowrecords.FlowRecords, denyConnStore *connections.DenyConnectionStore,
 	collectorAddr string, collectorProto string, activeFlowTimeout time.Duration, idleFlowTimeout time.Duration,
 	v4Enabled bool, v6Enabled bool, k8sClient kubernetes.Interface,
-	nodeRouteController *noderoute.Controller, isNetworkPolicyOnly bool) (*flowExporter, error) {
+	nodeRouteController *noderoute.Controller, isNetworkPolicyOnly bool, filter *FlowFilter) (*flowExporter, error) {
 	// Initialize IPFIX registry
 	registry := ipfix.NewIPFIXRegistry()
 	registry.LoadRegistry()
@@ -161,6 +163,7 @@ func NewFlowExporter(connStore *connections.ConntrackConnectionStore, records *f
 		nodeRouteController: nodeRouteController,
 		isNetworkPolicyOnly: isNetworkPolicyOnly,
 		nodeName:            nodeName,
+		filter:              filter,
 	}, nil
 }
 
@@ -274,32 +277,14 @@ func (exp *flowExporter) sendFlowRecords() error {
 			recordNeedsSending = true
 		}
 		if recordNeedsSending {
-			exp.ipfixSet.ResetSet()
-			if record.IsIPv6 {
-				if err := exp.ipfixSet.PrepareSet(ipfixentities.Data, exp.templateIDv6); err != nil {
-					return err
-				}
-				// TODO: more records per data set will be supported when go-ipfix supports size check when adding records
-				if err := exp.addRecordToSet(record); err != nil {
-					return err
-				}
-				if _, err := exp.sendDataSet(); err != nil {
-					return err
-				}
-			} else {
-				if err := exp.ipfixSet.PrepareSet(ipfixentities.Data, exp.templateIDv4); err != nil {
-					return err
-				}
-				// TODO: more records per data set will be supported when go-ipfix supports size check when adding records
-				if err := exp.addRecordToSet(record); err != nil {
-					return err
-				}
-				if _, err := exp.sendDataSet(); err != nil {
+			// The records of the connections which are not selected by the filter are not sent, but they are
+			// updated or deleted like the sent ones.
+			if exp.shouldExport(&record.Conn) {
+				if err := exp.sendFlowRecord(record); err != nil {
 					return err
 				}
+				klog.V(4).InfoS("Record sent successfully", "flowKey", key, "record", record)
 			}
-			exp.numDataSetsSent = exp.numDataSetsSent + 1
-
 			if flowexporter.IsConnectionDying(&record.Conn) {
 				// If the connection is in dying state or connection is not in conntrack table,
 				// we will delete the flow records from records map.
@@ -313,7 +298,6 @@ func (exp *flowExporter) sendFlowRecords() error {
 			} else {
 				exp.flowRecords.ValidateAndUpdateStats(key, record)
 			}
-			klog.V(4).InfoS("Record sent successfully", "flowKey", key, "record", record)
 		}
 		return nil
 	}
@@ -323,26 +307,25 @@ func (exp *flowExporter) sendFlowRecords() error {
 	}
 
 	exportDenyConn := func(connKey flowexporter.ConnectionKey, conn *flowexporter.Connection) error {
+		// Like the flow records, the deny connections which are not selected by the filter are reset or deleted
+		// without being sent.
+		shouldExport := exp.shouldExport(conn)
 		if conn.DeltaPackets > 0 && time.Since(conn.LastExportTime) >= exp.activeFlowTimeout {
-			if err := exp.addDenyConnToSet(conn, ipfixregistry.ActiveTimeoutReason); err != nil {
-				return err
-			}
-			if _, err := exp.sendDataSet(); err != nil {
-				return err
+			if shouldExport {
+				if err := exp.sendDenyConn(conn, ipfixregistry.ActiveTimeoutReason); err != nil {
+					return err
+				}
+				klog.V(4).InfoS("Record for deny connection sent successfully", "flowKey", connKey, "connection", conn)
 			}
-			exp.numDataSetsSent = exp.numDataSetsSent + 1
-			klog.V(4).InfoS("Record for deny connection sent successfully", "flowKey", connKey, "connection", conn)
 			exp.denyConnStore.ResetConnStatsWithoutLock(connKey)
 		}
 		if time.Since(conn.LastExportTime) >= exp.idleFlowTimeout {
-			if err := exp.addDenyConnToSet(conn, ipfixregistry.IdleTimeoutReason); err != nil {
-				return err
-			}
-			if _, err := exp.sendDataSet(); err != nil {
-				return err
+			if shouldExport {
+				if err := exp.sendDenyConn(conn, ipfixregistry.IdleTimeoutReason); err != nil {
+					return err
+				}
+				klog.V(4).InfoS("Record for deny connection sent successfully", "flowKey", connKey, "connection", conn)
 			}
-			exp.numDataSetsSent = exp.numDataSetsSent + 1
-			klog.V(4).InfoS("Record for deny connection sent successfully", "flowKey", connKey, "connection", conn)
 			exp.denyConnStore.DeleteConnWithoutLock(connKey)
 		}
 		return nil
@@ -354,6 +337,37 @@ func (exp *flowExporter) sendFlowRecords() error {
 	return nil
 }
 
+func (exp *flowExporter) sendFlowRecord(record flowexporter.FlowRecord) error {
+	exp.ipfixSet.ResetSet()
+	templateID := exp.templateIDv4
+	if record.IsIPv6 {
+		templateID = exp.templateIDv6
+	}
+	if err := exp.ipfixSet.PrepareSet(ipfixentities.Data, templateID); err != nil {
+		return err
+	}
+	// TODO: more records per data set will be supported when go-ipfix supports size check when adding records
+	if err := exp.addRecordToSet(record); err != nil {
+		return err
+	}
+	if _, err := exp.sendDataSet(); err != nil {
+		return err
+	}
+	exp.numDataSetsSent = exp.numDataSetsSent + 1
+	return nil
+}
+
+func (exp *flowExporter) sendDenyConn(conn *flowexporter.Connection, flowEndReason uint8) error {
+	if err := exp.addDenyConnToSet(conn, flowEndReason); err != nil {
+		return err
+	}
+	if _, err := exp.sendDataSet(); err != nil {
+		return err
+	}
+	exp.numDataSetsSent = exp.numDataSetsSent + 1
+	return nil
+}
+
 func (exp *flowExporter) sendTemplateSet(isIPv6 bool) (int, error) {
 	elements := make([]*ipfixentities.InfoElementWithValue, 0)
 
@@ -462,6 +476,8 @@ func (exp *flowExporter) addRecordToSet(record flowexporter.FlowRecord) error {
 				klog.Warningf("Byte delta count for connection should not be negative: %d", deltaBytes)
 			}
 			ie.Value = uint64(deltaBytes)
+		case "samplingInterval":
+			ie.Value = exp.filter.samplingInterval()
 		case "reversePacketTotalCount":
 			ie.Value = record.Conn.ReversePackets
 		case "reverseOctetTotalCount":
@@ -609,6 +625,8 @@ func (exp *flowExporter) addDenyConnToSet(conn *flowexporter.Connection, flowEnd
 			ie.Value = conn.DeltaPackets
 		case "octetDeltaCount":
 			ie.Value = conn.DeltaBytes
+		case "samplingInterval":
+			ie.Value = exp.filter.samplingInterval()
 		case "reversePacketTotalCount", "reverseOctetTotalCount", "reversePacketDeltaCount", "reverseOctetDeltaCount":
 			ie.Value = uint64(0)
 		case "sourcePodNamespace":
@@ -145,7 +145,7 @@ func setupExporter(isConntrackConn bool) (*flowExporter, error) {
 		addDenyConns(denyConnStore)
 	}
 
-	exp, _ := NewFlowExporter(conntrackConnStore, records, denyConnStore, collectorAddr.String(), collectorAddr.Network(), testActiveFlowTimeout, testIdleFlowTimeout, true, false, nil, nil, false)
+	exp, _ := NewFlowExporter(conntrackConnStore, records, denyConnStore, collectorAddr.String(), collectorAddr.Network(), testActiveFlowTimeout, testIdleFlowTimeout, true, false, nil, nil, false, nil)
 	return exp, err
 }
 
@@ -244,6 +244,8 @@ func getElemList(ianaIE []string, antreaIE []string) []*ipfixentities.InfoElemen
 			elemList[i] = ipfixentities.NewInfoElementWithValue(ie.Element, uint16(0))
 		case "protocolIdentifier":
 			elemList[i] = ipfixentities.NewInfoElementWithValue(ie.Element, uint8(0))
+		case "samplingInterval":
+			elemList[i] = ipfixentities.NewInfoElementWithValue(ie.Element, uint32(1))
 		case "packetTotalCount", "octetTotalCount", "packetDeltaCount", "octetDeltaCount", "reversePacketTotalCount", "reverseOctetTotalCount", "reversePacketDeltaCount", "reverseOctetDeltaCount":
 			elemList[i] = ipfixentities.NewInfoElementWithValue(ie.Element, uint64(0))
 		case "sourcePodName", "sourcePodNamespace", "sourceNodeName", "destinationPodName", "destinationPodNamespace", "destinationNodeName", "destinationServicePortName":
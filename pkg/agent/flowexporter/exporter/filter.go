@@ -0,0 +1,164 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strings"
+
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+
+	"antrea.io/antrea/pkg/agent/flowexporter"
+)
+
+// FlowFilter selects the connections exported by the Flow Exporter. A connection is exported when it matches all
+// the non-empty fields and it is selected by the sampling.
+type FlowFilter struct {
+	// Namespaces matches the connections whose source or destination Pod is in one of these Namespaces. It doesn't
+	// apply to the inter-Node connections, as the Namespace of the remote Pod is unknown to the local Node.
+	Namespaces []string
+	// Protocols matches the connections with one of these IP protocol numbers.
+	Protocols []uint8
+	// CIDRs matches the connections whose source or destination IP is in one of these CIDRs.
+	CIDRs []*net.IPNet
+	// FlowTypes matches the connections with one of these flow types, e.g. ipfixregistry.FlowTypeIntraNode.
+	FlowTypes []uint8
+	// SamplingRate is N when 1 in N connections is exported. The connections are selected with a hash of their
+	// flow key, so that the Nodes of both ends of a connection make the same decision and the sampled connections
+	// can still be correlated by the Flow Aggregator. 0 and 1 mean that all the connections are exported.
+	SamplingRate uint32
+}
+
+// ParseFlowProtocol returns the IP protocol numbers of a protocol name supported by FlowFilter. "ICMP" matches both
+// ICMP and ICMPv6.
+func ParseFlowProtocol(protocol string) ([]uint8, error) {
+	switch strings.ToUpper(protocol) {
+	case "TCP":
+		return []uint8{6}, nil
+	case "UDP":
+		return []uint8{17}, nil
+	case "SCTP":
+		return []uint8{132}, nil
+	case "ICMP":
+		return []uint8{1, 58}, nil
+	}
+	return nil, fmt.Errorf("unsupported protocol %s, it must be TCP, UDP, SCTP or ICMP", protocol)
+}
+
+// ParseFlowType returns the value of the flowType Information Element corresponding to a flow type name.
+func ParseFlowType(flowType string) (uint8, error) {
+	switch strings.ToLower(flowType) {
+	case "intranode":
+		return ipfixregistry.FlowTypeIntraNode, nil
+	case "internode":
+		return ipfixregistry.FlowTypeInterNode, nil
+	case "toexternal":
+		return ipfixregistry.FlowTypeToExternal, nil
+	}
+	return 0, fmt.Errorf("unsupported flow type %s, it must be IntraNode, InterNode or ToExternal", flowType)
+}
+
+// samplingInterval returns the value of the samplingInterval Information Element, which collectors can use to scale
+// the counts of the exported connections.
+func (f *FlowFilter) samplingInterval() uint32 {
+	if f == nil || f.SamplingRate == 0 {
+		return 1
+	}
+	return f.SamplingRate
+}
+
+// sampled returns whether a connection is selected by the sampling.
+func (f *FlowFilter) sampled(conn *flowexporter.Connection) bool {
+	rate := f.samplingInterval()
+	if rate == 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write(conn.FlowKey.SourceAddress.To16())
+	h.Write(conn.FlowKey.DestinationAddress.To16())
+	var buf [5]byte
+	buf[0] = conn.FlowKey.Protocol
+	binary.BigEndian.PutUint16(buf[1:3], conn.FlowKey.SourcePort)
+	binary.BigEndian.PutUint16(buf[3:5], conn.FlowKey.DestinationPort)
+	h.Write(buf[:])
+	return h.Sum32()%rate == 0
+}
+
+// shouldExport returns whether a connection is selected by the filter of the Flow Exporter. A nil filter selects all
+// the connections.
+func (exp *flowExporter) shouldExport(conn *flowexporter.Connection) bool {
+	f := exp.filter
+	if f == nil {
+		return true
+	}
+	if len(f.Namespaces) > 0 && !exp.namespacesMatch(conn) {
+		return false
+	}
+	if len(f.Protocols) > 0 && !containsUint8(f.Protocols, conn.FlowKey.Protocol) {
+		return false
+	}
+	if len(f.CIDRs) > 0 && !cidrsContain(f.CIDRs, conn.FlowKey.SourceAddress) && !cidrsContain(f.CIDRs, conn.FlowKey.DestinationAddress) {
+		return false
+	}
+	if len(f.FlowTypes) > 0 && !containsUint8(f.FlowTypes, exp.findFlowType(*conn)) {
+		return false
+	}
+	return f.sampled(conn)
+}
+
+// namespacesMatch returns whether the source or destination Pod of a connection is in one of the Namespaces of the
+// filter. The Node of each end of an inter-Node connection only knows the Namespace of its local Pod, so they would
+// make different decisions and the Flow Aggregator couldn't correlate their flow records: the inter-Node connections
+// are always matched.
+func (exp *flowExporter) namespacesMatch(conn *flowexporter.Connection) bool {
+	f := exp.filter
+	if containsString(f.Namespaces, conn.SourcePodNamespace) || containsString(f.Namespaces, conn.DestinationPodNamespace) {
+		return true
+	}
+	return exp.findFlowType(*conn) == ipfixregistry.FlowTypeInterNode
+}
+
+func containsString(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint8(values []uint8, value uint8) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func cidrsContain(cidrs []*net.IPNet, ip net.IP) bool {
+	for _, cidr := range cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
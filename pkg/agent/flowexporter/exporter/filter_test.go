@@ -0,0 +1,168 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporter
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
+	ipfixentitiestesting "github.com/vmware/go-ipfix/pkg/entities/testing"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+
+	"antrea.io/antrea/pkg/agent/flowexporter"
+	"antrea.io/antrea/pkg/agent/flowexporter/connections"
+	connectionstest "antrea.io/antrea/pkg/agent/flowexporter/connections/testing"
+	"antrea.io/antrea/pkg/agent/flowexporter/flowrecords"
+	ipfixtest "antrea.io/antrea/pkg/ipfix/testing"
+)
+
+func TestFlowExporter_shouldExport(t *testing.T) {
+	_, cidr, _ := net.ParseCIDR("4.3.2.0/24")
+	_, otherCIDR, _ := net.ParseCIDR("10.0.0.0/8")
+	tests := []struct {
+		name     string
+		filter   *FlowFilter
+		expected bool
+	}{
+		{"no filter", nil, true},
+		{"empty filter", &FlowFilter{}, true},
+		{"source Namespace", &FlowFilter{Namespaces: []string{"ns"}}, true},
+		{"other Namespace of inter-Node flow", &FlowFilter{Namespaces: []string{"ns2"}}, true},
+		{"protocol", &FlowFilter{Protocols: []uint8{17, 6}}, true},
+		{"other protocol", &FlowFilter{Protocols: []uint8{17}}, false},
+		{"destination CIDR", &FlowFilter{CIDRs: []*net.IPNet{otherCIDR, cidr}}, true},
+		{"other CIDR", &FlowFilter{CIDRs: []*net.IPNet{otherCIDR}}, false},
+		{"flow type", &FlowFilter{FlowTypes: []uint8{ipfixregistry.FlowTypeInterNode}}, true},
+		{"other flow type", &FlowFilter{FlowTypes: []uint8{ipfixregistry.FlowTypeIntraNode}}, false},
+		{"all fields", &FlowFilter{Namespaces: []string{"ns"}, Protocols: []uint8{6}, CIDRs: []*net.IPNet{cidr}, SamplingRate: 1}, true},
+		{"one field not matched", &FlowFilter{Namespaces: []string{"ns"}, Protocols: []uint8{17}, CIDRs: []*net.IPNet{cidr}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The destination Pod is not local, so the flow type is InterNode in networkPolicyOnly mode.
+			exp := &flowExporter{isNetworkPolicyOnly: true, filter: tt.filter}
+			assert.Equal(t, tt.expected, exp.shouldExport(getConnection(false, true, 302, 6, "ESTABLISHED")))
+		})
+	}
+}
+
+func TestFlowExporter_shouldExportNamespaces(t *testing.T) {
+	filter := &FlowFilter{Namespaces: []string{"ns"}}
+	exp := &flowExporter{isNetworkPolicyOnly: true, filter: filter}
+	intraNodeConn := getConnection(false, true, 302, 6, "ESTABLISHED")
+	intraNodeConn.DestinationPodNamespace = "ns2"
+	intraNodeConn.DestinationPodName = "pod2"
+	assert.True(t, exp.shouldExport(intraNodeConn))
+	intraNodeConn.SourcePodNamespace = "ns3"
+	assert.False(t, exp.shouldExport(intraNodeConn), "The Namespace filter should apply when both Pods are local")
+
+	// The Nodes of both ends of an inter-Node connection export it, whichever Pod is in the Namespace.
+	for _, namespaces := range [][2]string{{"ns", "ns2"}, {"ns2", "ns"}, {"ns2", "ns3"}} {
+		sourceNodeConn := getConnection(false, true, 302, 6, "ESTABLISHED")
+		sourceNodeConn.SourcePodNamespace = namespaces[0]
+		destinationNodeConn := getConnection(false, true, 302, 6, "ESTABLISHED")
+		destinationNodeConn.SourcePodNamespace, destinationNodeConn.SourcePodName = "", ""
+		destinationNodeConn.DestinationPodNamespace, destinationNodeConn.DestinationPodName = namespaces[1], "pod2"
+		assert.True(t, exp.shouldExport(sourceNodeConn), "Source Node should export inter-Node flow from %s to %s", namespaces[0], namespaces[1])
+		assert.True(t, exp.shouldExport(destinationNodeConn), "Destination Node should export inter-Node flow from %s to %s", namespaces[0], namespaces[1])
+	}
+}
+
+func TestFlowFilterSampling(t *testing.T) {
+	filter := &FlowFilter{SamplingRate: 4}
+	assert.Equal(t, uint32(4), filter.samplingInterval())
+	sampled := 0
+	for port := 0; port < 1000; port++ {
+		conn := getConnection(false, true, 302, 6, "ESTABLISHED")
+		conn.FlowKey.SourcePort = uint16(30000 + port)
+		if filter.sampled(conn) {
+			sampled++
+		}
+		// The sampling only depends on the flow key.
+		otherConn := getConnection(false, false, 0x204, 6, "TIME_WAIT")
+		otherConn.FlowKey.SourcePort = conn.FlowKey.SourcePort
+		assert.Equal(t, filter.sampled(conn), filter.sampled(otherConn))
+	}
+	assert.InDelta(t, 250, sampled, 50)
+
+	var noFilter *FlowFilter
+	assert.Equal(t, uint32(1), noFilter.samplingInterval())
+	assert.Equal(t, uint32(1), (&FlowFilter{}).samplingInterval())
+}
+
+func TestParseFlowFilterValues(t *testing.T) {
+	protocols, err := ParseFlowProtocol("icmp")
+	require.NoError(t, err)
+	assert.Equal(t, []uint8{1, 58}, protocols)
+	_, err = ParseFlowProtocol("GRE")
+	assert.Error(t, err)
+
+	flowType, err := ParseFlowType("ToExternal")
+	require.NoError(t, err)
+	assert.Equal(t, ipfixregistry.FlowTypeToExternal, flowType)
+	_, err = ParseFlowType("External")
+	assert.Error(t, err)
+}
+
+// TestFlowExporter_sendFlowRecordsFiltered tests that the records and the deny connections which are not selected by
+// the filter are not sent, but are still removed from the stores.
+func TestFlowExporter_sendFlowRecordsFiltered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIPFIXExpProc := ipfixtest.NewMockIPFIXExportingProcess(ctrl)
+	mockDataSet := ipfixentitiestesting.NewMockSet(ctrl)
+	mockConnDumper := connectionstest.NewMockConnTrackDumper(ctrl)
+	flowExp := &flowExporter{
+		process:            mockIPFIXExpProc,
+		ipfixSet:           mockDataSet,
+		elementsListv4:     getElemList(IANAInfoElementsIPv4, AntreaInfoElementsIPv4),
+		templateIDv4:       testTemplateIDv4,
+		v4Enabled:          true,
+		activeFlowTimeout:  testActiveFlowTimeout,
+		idleFlowTimeout:    testIdleFlowTimeout,
+		conntrackConnStore: connections.NewConntrackConnectionStore(mockConnDumper, flowrecords.NewFlowRecords(), nil, true, false, nil, nil, 1),
+		flowRecords:        flowrecords.NewFlowRecords(),
+		denyConnStore:      connections.NewDenyConnectionStore(nil, nil),
+		filter:             &FlowFilter{Protocols: []uint8{17}},
+	}
+
+	// The connection is not present in conntrack anymore, so its record is deleted once exported.
+	conn := getConnection(false, false, 0x204, 6, "TIME_WAIT")
+	connKey := flowexporter.NewConnectionKey(conn)
+	flowExp.conntrackConnStore.AddOrUpdateConn(conn)
+	require.NoError(t, flowExp.flowRecords.AddOrUpdateFlowRecord(connKey, conn))
+	flowRec, _ := flowExp.flowRecords.GetFlowRecordFromMap(&connKey)
+	flowRec.IsActive = true
+	flowRec.LastExportTime = time.Now().Add(-testActiveFlowTimeout)
+	flowExp.flowRecords.AddFlowRecordToMap(&connKey, flowRec)
+	denyConn := getDenyConnection(false, false, 6)
+	flowExp.denyConnStore.AddOrUpdateConn(denyConn, denyConn.LastExportTime, denyConn.DeltaBytes)
+
+	mockDataSet.EXPECT().ResetSet().Times(0)
+	mockDataSet.EXPECT().AddRecord(gomock.Any(), gomock.Any()).Times(0)
+	mockDataSet.EXPECT().PrepareSet(ipfixentities.Data, gomock.Any()).Times(0)
+	mockIPFIXExpProc.EXPECT().SendSet(gomock.Any()).Times(0)
+	require.NoError(t, flowExp.sendFlowRecords())
+	assert.Equal(t, uint64(0), flowExp.numDataSetsSent)
+	_, exists := flowExp.flowRecords.GetFlowRecordFromMap(&connKey)
+	assert.False(t, exists, "record should not be in the map")
+	assert.Equal(t, 0, getNumOfConnections(flowExp.denyConnStore))
+}
@@ -54,12 +54,26 @@ const (
 	maxRetryDelay = 300 * time.Second
 	// Default number of workers processing an ExternalIPPool change.
 	defaultWorkers = 4
+	// How long to wait for the updated metadata of the local Node to be propagated.
+	nodeUpdateTimeout = 5 * time.Second
 
 	nodeEventTypeJoin   nodeEventType = "Join"
 	nodeEventTypeLeave  nodeEventType = "Leave"
 	nodeEventTypeUpdate nodeEventType = "Update"
 )
 
+// The reasons why a Node, which owned an Egress IP, doesn't own it anymore.
+const (
+	// The Node has left the memberlist cluster or failed.
+	NodeUnavailableReasonNotAlive = "NodeNotAlive"
+	// The health checks of the Node have failed.
+	NodeUnavailableReasonUnhealthy = "NodeUnhealthy"
+	// The Node has been deleted or is not selected by the ExternalIPPool anymore.
+	NodeUnavailableReasonNotSelected = "NodeNotSelected"
+	// The Node is still available, the Egress IP has been moved because of a change of the available Nodes.
+	NodeUnavailableReasonRebalanced = "Rebalanced"
+)
+
 type nodeEventType string
 
 // Default Hash Fn is crc32.ChecksumIEEE.
@@ -112,6 +126,16 @@ type Cluster struct {
 
 	// queue maintains the ExternalIPPool names that need to be synced.
 	queue workqueue.RateLimitingInterface
+
+	// healthCheckConfig is nil if the health checks of the local Node are disabled.
+	healthCheckConfig *HealthCheckConfig
+	healthChecker     healthChecker
+	// healthCheckFailures stores the number of consecutive failures of each health check.
+	healthCheckFailures map[HealthCheckType]int
+	// unhealthyReason is the reason why the local Node is unhealthy, empty if it is healthy. It is propagated to
+	// the other Nodes with the metadata of the local Node.
+	unhealthyReason string
+	healthMutex     sync.RWMutex
 }
 
 // NewCluster returns a new *Cluster.
@@ -121,6 +145,7 @@ func NewCluster(
 	nodeName string,
 	nodeInformer coreinformers.NodeInformer,
 	externalIPPoolInformer crdinformers.ExternalIPPoolInformer,
+	healthCheckConfig *HealthCheckConfig,
 ) (*Cluster, error) {
 	// The Node join/leave events will be notified via it.
 	nodeEventCh := make(chan memberlist.NodeEvent, 1024)
@@ -137,6 +162,9 @@ func NewCluster(
 		externalIPPoolLister:            externalIPPoolInformer.Lister(),
 		externalIPPoolInformerHasSynced: externalIPPoolInformer.Informer().HasSynced,
 		queue:                           workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "externalIPPool"),
+		healthCheckConfig:               healthCheckConfig,
+		healthChecker:                   newHealthChecker(),
+		healthCheckFailures:             map[HealthCheckType]int{},
 	}
 
 	conf := memberlist.DefaultLocalConfig()
@@ -144,6 +172,7 @@ func NewCluster(
 	conf.BindPort = c.bindPort
 	conf.AdvertisePort = c.bindPort
 	conf.Events = &memberlist.ChannelEventDelegate{Ch: nodeEventCh}
+	conf.Delegate = &nodeDelegate{cluster: c}
 	conf.LogOutput = ioutil.Discard
 	klog.V(1).InfoS("New memberlist cluster", "config", conf)
 
@@ -324,6 +353,10 @@ func (c *Cluster) Run(stopCh <-chan struct{}) {
 		go wait.Until(c.worker, time.Second, stopCh)
 	}
 
+	if c.healthCheckConfig != nil {
+		go c.runHealthChecks(stopCh)
+	}
+
 	for {
 		select {
 		case <-stopCh:
@@ -393,17 +426,8 @@ func (c *Cluster) syncConsistentHash(eipName string) error {
 		if err != nil {
 			return err
 		}
-		aliveNodes := c.aliveNodes()
-		// Node alive and Node labels match ExternalIPPool nodeSelector.
-		var aliveAndMatchedNodes []string
-		for _, node := range nodes {
-			nodeName := node.Name
-			if aliveNodes.Has(nodeName) {
-				aliveAndMatchedNodes = append(aliveAndMatchedNodes, nodeName)
-			}
-		}
 		consistentHashMap := newNodeConsistentHashMap()
-		consistentHashMap.Add(aliveAndMatchedNodes...)
+		consistentHashMap.Add(selectEgressNodes(nodes, c.aliveNodes())...)
 		c.consistentHashRWMutex.Lock()
 		defer c.consistentHashRWMutex.Unlock()
 		c.consistentHashMap[eip.Name] = consistentHashMap
@@ -421,48 +445,106 @@ func newNodeConsistentHashMap() *consistenthash.Map {
 	return consistenthash.New(defaultVirtualNodeReplicas, defaultHashFn)
 }
 
+// selectEgressNodes returns the names of the Nodes which can own the Egress IPs of an ExternalIPPool, given the Nodes
+// matching its nodeSelector and the metadata of the alive Nodes: Nodes must be alive and healthy. If none of the
+// alive Nodes is healthy, e.g. because the gateway is unreachable for all of them, the unhealthy Nodes are selected
+// anyway, so that the Egress IPs still have an owner.
+func selectEgressNodes(nodes []*corev1.Node, aliveNodes map[string]*nodeMeta) []string {
+	var healthyNodes, unhealthyNodes []string
+	for _, node := range nodes {
+		meta, alive := aliveNodes[node.Name]
+		if !alive {
+			continue
+		}
+		if meta.UnhealthyReason != "" {
+			unhealthyNodes = append(unhealthyNodes, node.Name)
+		} else {
+			healthyNodes = append(healthyNodes, node.Name)
+		}
+	}
+	if len(healthyNodes) == 0 {
+		return unhealthyNodes
+	}
+	return healthyNodes
+}
+
 func (c *Cluster) handleClusterNodeEvents(nodeEvent *memberlist.NodeEvent) {
 	node, event := nodeEvent.Node, nodeEvent.Event
 	switch event {
-	case memberlist.NodeJoin, memberlist.NodeLeave:
+	case memberlist.NodeJoin, memberlist.NodeLeave, memberlist.NodeUpdate:
 		// When a Node joins cluster, all matched ExternalIPPools consistentHash should be updated;
 		// when a Node leaves cluster, the Node may have failed or have been deleted,
 		// if the Node has been deleted, affected ExternalIPPool should be enqueued, and deleteNode handler has been executed,
-		// if the Node has failed, ExternalIPPools consistentHash maybe changed, and affected ExternalIPPool should be enqueued.
-		coreNode, err := c.nodeLister.Get(node.Name)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				// Node has been deleted, and deleteNode handler has been executed.
-				klog.ErrorS(err, "Processing Node event, not found", "eventType", event)
-				return
-			}
-			klog.ErrorS(err, "Processing Node event, get Node failed", "eventType", event)
-			return
-		}
-		affectedEIPs := c.filterEIPsFromNodeLabels(coreNode)
-		c.enqueueExternalIPPools(affectedEIPs)
-		klog.InfoS("Processed Node event", "eventType", mapNodeEventType[event], "nodeName", node.Name, "affectedExternalIPPoolNum", len(affectedEIPs))
+		// if the Node has failed, ExternalIPPools consistentHash maybe changed, and affected ExternalIPPool should be enqueued;
+		// when the metadata of a Node is updated, its health state may have changed, and affected ExternalIPPool should be enqueued.
+		affectedEIPNum := c.enqueueExternalIPPoolsForNode(node.Name)
+		klog.InfoS("Processed Node event", "eventType", mapNodeEventType[event], "nodeName", node.Name, "affectedExternalIPPoolNum", affectedEIPNum)
 	default:
 		klog.InfoS("Processed Node event", "eventType", mapNodeEventType[event], "nodeName", node.Name)
 	}
 }
 
-// aliveNodes returns the list of nodeNames in the cluster.
-func (c *Cluster) aliveNodes() sets.String {
-	nodes := sets.NewString()
+// enqueueExternalIPPoolsForNode enqueues the ExternalIPPools selecting the Node and returns their number.
+func (c *Cluster) enqueueExternalIPPoolsForNode(nodeName string) int {
+	coreNode, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Node has been deleted, and deleteNode handler has been executed.
+			klog.ErrorS(err, "Processing Node event, not found", "nodeName", nodeName)
+			return 0
+		}
+		klog.ErrorS(err, "Processing Node event, get Node failed", "nodeName", nodeName)
+		return 0
+	}
+	affectedEIPs := c.filterEIPsFromNodeLabels(coreNode)
+	c.enqueueExternalIPPools(affectedEIPs)
+	return len(affectedEIPs)
+}
+
+// aliveNodes returns the metadata of the alive Nodes in the cluster, keyed by nodeName.
+func (c *Cluster) aliveNodes() map[string]*nodeMeta {
+	nodes := make(map[string]*nodeMeta)
 	for _, node := range c.mList.Members() {
-		nodes.Insert(node.Name)
+		nodes[node.Name] = decodeNodeMeta(node.Meta)
 	}
 	return nodes
 }
 
+// NodeUnavailableReason returns the reason why the Node cannot own the Egress IPs of the ExternalIPPool anymore. It is
+// NodeUnavailableReasonRebalanced if the Node is still alive, healthy and selected by the ExternalIPPool.
+func (c *Cluster) NodeUnavailableReason(eipName, nodeName string) string {
+	meta, alive := c.aliveNodes()[nodeName]
+	if !alive {
+		return NodeUnavailableReasonNotAlive
+	}
+	if meta.UnhealthyReason != "" {
+		return fmt.Sprintf("%s: %s", NodeUnavailableReasonUnhealthy, meta.UnhealthyReason)
+	}
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		return NodeUnavailableReasonNotSelected
+	}
+	if eip, err := c.externalIPPoolLister.Get(eipName); err == nil {
+		nodeSelector, _ := metav1.LabelSelectorAsSelector(&eip.Spec.NodeSelector)
+		if !nodeSelector.Matches(labels.Set(node.GetLabels())) {
+			return NodeUnavailableReasonNotSelected
+		}
+	}
+	return NodeUnavailableReasonRebalanced
+}
+
 // ShouldSelectEgress returns true if the local Node selected as the owner Node of the Egress,
 // the local Node in the cluster holds the same consistent hash ring for each ExternalIPPool,
 // consistentHash.Get gets the closest item (Node name) in the hash to the provided key(egressIP),
 // if the name of the local Node is equal to the name of the selected Node, returns true.
 func (c *Cluster) ShouldSelectEgress(egress *v1alpha2.Egress) (bool, error) {
-	eipName := egress.Spec.ExternalIPPool
-	if eipName == "" || egress.Spec.EgressIP == "" {
+	return c.ShouldSelectEgressIP(egress.Spec.ExternalIPPool, egress.Spec.EgressIP)
+}
+
+// ShouldSelectEgressIP returns true if the local Node is selected to hold the provided Egress IP allocated from the
+// provided ExternalIPPool. The IPs of an Egress with multiple Egress IPs are selected independently of each other.
+func (c *Cluster) ShouldSelectEgressIP(eipName, egressIP string) (bool, error) {
+	if eipName == "" || egressIP == "" {
 		return false, nil
 	}
 	c.consistentHashRWMutex.RLock()
@@ -471,7 +553,7 @@ func (c *Cluster) ShouldSelectEgress(egress *v1alpha2.Egress) (bool, error) {
 	if !ok {
 		return false, fmt.Errorf("local Node consistentHashMap has not synced, ExternalIPPool %s", eipName)
 	}
-	return consistentHash.Get(egress.Spec.EgressIP) == c.nodeName, nil
+	return consistentHash.Get(egressIP) == c.nodeName, nil
 }
 
 func (c *Cluster) notify(objName string) {
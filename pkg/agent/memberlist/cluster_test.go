@@ -58,7 +58,7 @@ func newFakeCluster(nodeConfig *config.NodeConfig, stopCh <-chan struct{}, i int
 	crdInformerFactory := crdinformers.NewSharedInformerFactory(crdClient, 0)
 	ipPoolInformer := crdInformerFactory.Crd().V1alpha2().ExternalIPPools()
 
-	cluster, err := NewCluster(port, nodeConfig.NodeIPAddr.IP, nodeConfig.Name, nodeInformer, ipPoolInformer)
+	cluster, err := NewCluster(port, nodeConfig.NodeIPAddr.IP, nodeConfig.Name, nodeInformer, ipPoolInformer, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -428,6 +428,50 @@ func genNodes(n int) []string {
 	return nodes
 }
 
+func TestSelectEgressNodes(t *testing.T) {
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+	}
+	testCases := []struct {
+		name          string
+		aliveNodes    map[string]*nodeMeta
+		expectedNodes []string
+	}{
+		{
+			name:          "All Nodes alive and healthy",
+			aliveNodes:    map[string]*nodeMeta{"node0": {}, "node1": {}, "node2": {}},
+			expectedNodes: []string{"node0", "node1", "node2"},
+		},
+		{
+			name:          "Node not alive",
+			aliveNodes:    map[string]*nodeMeta{"node0": {}, "node2": {}},
+			expectedNodes: []string{"node0", "node2"},
+		},
+		{
+			name:          "Node unhealthy",
+			aliveNodes:    map[string]*nodeMeta{"node0": {}, "node1": {UnhealthyReason: "Gateway health check failed"}, "node2": {}},
+			expectedNodes: []string{"node0", "node2"},
+		},
+		{
+			name:          "All alive Nodes unhealthy",
+			aliveNodes:    map[string]*nodeMeta{"node0": {UnhealthyReason: "Uplink health check failed"}, "node1": {UnhealthyReason: "Gateway health check failed"}},
+			expectedNodes: []string{"node0", "node1"},
+		},
+		{
+			name:          "Alive Node not matched",
+			aliveNodes:    map[string]*nodeMeta{"node0": {}, "node3": {}},
+			expectedNodes: []string{"node0"},
+		},
+	}
+	for _, tCase := range testCases {
+		t.Run(tCase.name, func(t *testing.T) {
+			assert.Equal(t, tCase.expectedNodes, selectEgressNodes(nodes, tCase.aliveNodes))
+		})
+	}
+}
+
 // TestCluster_ConsistentHashDistribute test the distributions of Egresses in Nodes
 func TestCluster_ConsistentHashDistribute(t *testing.T) {
 	egressNum := 10
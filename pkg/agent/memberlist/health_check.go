@@ -0,0 +1,174 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memberlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// HealthCheckType is the type of a health check run by the local Node to decide whether it can own Egress IPs.
+type HealthCheckType string
+
+const (
+	// HealthCheckUplink checks that the uplink interface, i.e. the interface which has the IP of the Node, is up
+	// and running.
+	HealthCheckUplink HealthCheckType = "Uplink"
+	// HealthCheckGateway checks that the default gateway of the Node is reachable, i.e. that its address can be
+	// resolved by ARP (IPv4) or NDP (IPv6).
+	HealthCheckGateway HealthCheckType = "Gateway"
+)
+
+const (
+	// The default interval between two runs of the health checks.
+	DefaultHealthCheckInterval = 5 * time.Second
+	// The default number of consecutive failures of a health check after which the Node is considered unhealthy.
+	DefaultHealthCheckFailureThreshold = 3
+)
+
+// IsValidHealthCheckType returns whether the provided HealthCheckType is supported.
+func IsValidHealthCheckType(checkType HealthCheckType) bool {
+	return checkType == HealthCheckUplink || checkType == HealthCheckGateway
+}
+
+// HealthCheckConfig is the configuration of the health checks of the local Node.
+type HealthCheckConfig struct {
+	// Interval between two runs of the health checks.
+	Interval time.Duration
+	// Number of consecutive failures of a health check after which the Node is considered unhealthy. The Node
+	// is considered healthy again as soon as all the health checks succeed.
+	FailureThreshold int
+	// The health checks to run.
+	Checks []HealthCheckType
+}
+
+// healthChecker runs a single health check for the Node which has the provided IP.
+type healthChecker interface {
+	check(checkType HealthCheckType, nodeIP net.IP) error
+}
+
+// nodeMeta is the metadata of a Node, which is propagated to the other Nodes of the memberlist cluster.
+type nodeMeta struct {
+	// UnhealthyReason is the reason why the Node is unhealthy. It is empty if the Node is healthy.
+	UnhealthyReason string `json:"unhealthyReason,omitempty"`
+}
+
+func encodeNodeMeta(meta *nodeMeta) []byte {
+	data, _ := json.Marshal(meta)
+	return data
+}
+
+// decodeNodeMeta decodes the metadata of a Node. A Node without metadata, e.g. a Node running an older version, is
+// considered healthy.
+func decodeNodeMeta(data []byte) *nodeMeta {
+	meta := &nodeMeta{}
+	if len(data) == 0 {
+		return meta
+	}
+	if err := json.Unmarshal(data, meta); err != nil {
+		klog.ErrorS(err, "Failed to decode Node metadata", "metadata", string(data))
+	}
+	return meta
+}
+
+// nodeDelegate implements memberlist.Delegate. It is only used to propagate the metadata of the local Node.
+type nodeDelegate struct {
+	cluster *Cluster
+}
+
+func (d *nodeDelegate) NodeMeta(limit int) []byte {
+	data := encodeNodeMeta(&nodeMeta{UnhealthyReason: d.cluster.getUnhealthyReason()})
+	if len(data) > limit {
+		// Truncating the reason is better than not reporting the Node as unhealthy.
+		data = encodeNodeMeta(&nodeMeta{UnhealthyReason: "unhealthy"})
+	}
+	return data
+}
+
+func (d *nodeDelegate) NotifyMsg([]byte) {}
+
+func (d *nodeDelegate) GetBroadcasts(overhead, limit int) [][]byte {
+	return nil
+}
+
+func (d *nodeDelegate) LocalState(join bool) []byte {
+	return nil
+}
+
+func (d *nodeDelegate) MergeRemoteState(buf []byte, join bool) {}
+
+func (c *Cluster) getUnhealthyReason() string {
+	c.healthMutex.RLock()
+	defer c.healthMutex.RUnlock()
+	return c.unhealthyReason
+}
+
+// runHealthChecks runs the configured health checks periodically until stopCh is closed.
+func (c *Cluster) runHealthChecks(stopCh <-chan struct{}) {
+	klog.InfoS("Starting Egress Node health checks", "checks", c.healthCheckConfig.Checks, "interval", c.healthCheckConfig.Interval, "failureThreshold", c.healthCheckConfig.FailureThreshold)
+	wait.Until(c.checkHealth, c.healthCheckConfig.Interval, stopCh)
+}
+
+// checkHealth runs all the configured health checks once and updates the health state of the local Node. The Node
+// becomes unhealthy when a health check has failed FailureThreshold consecutive times, and becomes healthy again
+// when all the health checks succeed.
+func (c *Cluster) checkHealth() {
+	var unhealthyReason string
+	failing := false
+	for _, checkType := range c.healthCheckConfig.Checks {
+		err := c.healthChecker.check(checkType, c.localNodeIP)
+		if err == nil {
+			c.healthCheckFailures[checkType] = 0
+			continue
+		}
+		failing = true
+		c.healthCheckFailures[checkType]++
+		klog.V(2).InfoS("Egress Node health check failed", "check", checkType, "failures", c.healthCheckFailures[checkType], "err", err)
+		if unhealthyReason == "" && c.healthCheckFailures[checkType] >= c.healthCheckConfig.FailureThreshold {
+			unhealthyReason = fmt.Sprintf("%s health check failed: %v", checkType, err)
+		}
+	}
+	// Keep the Node unhealthy until all the health checks succeed.
+	if unhealthyReason == "" && failing {
+		unhealthyReason = c.getUnhealthyReason()
+	}
+	c.setUnhealthyReason(unhealthyReason)
+}
+
+// setUnhealthyReason updates the health state of the local Node. If it has changed, the new metadata of the Node is
+// propagated to the other Nodes and the ExternalIPPools selecting the Node are resynced.
+func (c *Cluster) setUnhealthyReason(reason string) {
+	c.healthMutex.Lock()
+	changed := c.unhealthyReason != reason
+	c.unhealthyReason = reason
+	c.healthMutex.Unlock()
+	if !changed {
+		return
+	}
+	if reason != "" {
+		klog.InfoS("Local Node became unhealthy, it will not own Egress IPs", "reason", reason)
+	} else {
+		klog.InfoS("Local Node became healthy")
+	}
+	if err := c.mList.UpdateNode(nodeUpdateTimeout); err != nil {
+		klog.ErrorS(err, "Failed to propagate the health state of the local Node")
+	}
+	c.enqueueExternalIPPoolsForNode(c.nodeName)
+}
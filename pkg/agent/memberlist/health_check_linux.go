@@ -0,0 +1,125 @@
+// +build linux
+
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memberlist
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"antrea.io/antrea/pkg/agent/util"
+)
+
+// The port used to send the probe which triggers the resolution of the gateway address. It is the discard port, the
+// probe is not expected to be answered.
+const gatewayProbePort = 9
+
+type netlinkHealthChecker struct{}
+
+func newHealthChecker() healthChecker {
+	return &netlinkHealthChecker{}
+}
+
+func (h *netlinkHealthChecker) check(checkType HealthCheckType, nodeIP net.IP) error {
+	link, err := getUplink(nodeIP)
+	if err != nil {
+		return err
+	}
+	switch checkType {
+	case HealthCheckUplink:
+		return checkUplink(link)
+	case HealthCheckGateway:
+		return checkGateway(link, nodeIP)
+	}
+	return fmt.Errorf("unsupported health check %s", checkType)
+}
+
+func getUplink(nodeIP net.IP) (netlink.Link, error) {
+	_, iface, err := util.GetIPNetDeviceFromIP(nodeIP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the uplink interface: %v", err)
+	}
+	link, err := netlink.LinkByIndex(iface.Index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the uplink interface %s: %v", iface.Name, err)
+	}
+	return link, nil
+}
+
+// checkUplink returns an error if the uplink interface is administratively down or has no carrier.
+func checkUplink(link netlink.Link) error {
+	attrs := link.Attrs()
+	if attrs.Flags&net.FlagUp == 0 {
+		return fmt.Errorf("uplink interface %s is down", attrs.Name)
+	}
+	if attrs.RawFlags&unix.IFF_RUNNING == 0 {
+		return fmt.Errorf("uplink interface %s is not running", attrs.Name)
+	}
+	return nil
+}
+
+// checkGateway returns an error if the address of the default gateway on the uplink interface failed to be resolved.
+// The state of the neighbor entry is checked before sending a new probe, so that the kernel has had the whole interval
+// between two health checks to resolve the address.
+func checkGateway(link netlink.Link, nodeIP net.IP) error {
+	family := netlink.FAMILY_V4
+	if nodeIP.To4() == nil {
+		family = netlink.FAMILY_V6
+	}
+	gateway, err := getDefaultGateway(link, family)
+	if err != nil {
+		return err
+	}
+	neighs, err := netlink.NeighList(link.Attrs().Index, family)
+	if err != nil {
+		return fmt.Errorf("failed to list neighbors of uplink interface %s: %v", link.Attrs().Name, err)
+	}
+	for _, neigh := range neighs {
+		if neigh.IP.Equal(gateway) && neigh.State&netlink.NUD_FAILED != 0 {
+			return fmt.Errorf("gateway %s is unreachable", gateway)
+		}
+	}
+	return probeGateway(gateway)
+}
+
+func getDefaultGateway(link netlink.Link, family int) (net.IP, error) {
+	routes, err := netlink.RouteList(link, family)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes of uplink interface %s: %v", link.Attrs().Name, err)
+	}
+	for _, route := range routes {
+		if route.Dst == nil && route.Gw != nil {
+			return route.Gw, nil
+		}
+	}
+	return nil, fmt.Errorf("no default gateway found on uplink interface %s", link.Attrs().Name)
+}
+
+// probeGateway sends a UDP packet to the gateway, which makes the kernel resolve or confirm its link-layer address.
+func probeGateway(gateway net.IP) error {
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: gateway, Port: gatewayProbePort})
+	if err != nil {
+		return fmt.Errorf("failed to probe gateway %s: %v", gateway, err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return fmt.Errorf("failed to probe gateway %s: %v", gateway, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memberlist
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/antrea/pkg/agent/config"
+)
+
+type fakeHealthChecker struct {
+	errors map[HealthCheckType]error
+}
+
+func (h *fakeHealthChecker) check(checkType HealthCheckType, nodeIP net.IP) error {
+	return h.errors[checkType]
+}
+
+func TestNodeMeta(t *testing.T) {
+	meta := &nodeMeta{UnhealthyReason: "Gateway health check failed"}
+	assert.Equal(t, meta, decodeNodeMeta(encodeNodeMeta(meta)))
+	// Nodes without metadata are healthy.
+	assert.Equal(t, &nodeMeta{}, decodeNodeMeta(nil))
+
+	delegate := &nodeDelegate{cluster: &Cluster{unhealthyReason: "Uplink health check failed: uplink interface eth0 is down"}}
+	assert.Equal(t, "Uplink health check failed: uplink interface eth0 is down", decodeNodeMeta(delegate.NodeMeta(512)).UnhealthyReason)
+	assert.Equal(t, "unhealthy", decodeNodeMeta(delegate.NodeMeta(32)).UnhealthyReason)
+}
+
+func TestCluster_CheckHealth(t *testing.T) {
+	nodeConfig := &config.NodeConfig{
+		Name:       "localNodeName",
+		NodeIPAddr: &net.IPNet{IP: net.IPv4(127, 0, 0, 1), Mask: net.IPv4Mask(255, 255, 255, 255)},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	fakeCluster, err := newFakeCluster(nodeConfig, stopCh, 20)
+	require.NoError(t, err)
+	cluster := fakeCluster.cluster
+	defer cluster.mList.Shutdown()
+	checker := &fakeHealthChecker{errors: map[HealthCheckType]error{}}
+	cluster.healthChecker = checker
+	cluster.healthCheckConfig = &HealthCheckConfig{
+		FailureThreshold: 2,
+		Checks:           []HealthCheckType{HealthCheckUplink, HealthCheckGateway},
+	}
+	localNodeUnhealthyReason := func() string {
+		return cluster.aliveNodes()[nodeConfig.Name].UnhealthyReason
+	}
+
+	cluster.checkHealth()
+	assert.Empty(t, cluster.getUnhealthyReason())
+
+	// The Node becomes unhealthy after FailureThreshold consecutive failures.
+	checker.errors[HealthCheckGateway] = fmt.Errorf("gateway 10.0.0.1 is unreachable")
+	cluster.checkHealth()
+	assert.Empty(t, cluster.getUnhealthyReason())
+	cluster.checkHealth()
+	expectedReason := "Gateway health check failed: gateway 10.0.0.1 is unreachable"
+	assert.Equal(t, expectedReason, cluster.getUnhealthyReason())
+	assert.Equal(t, expectedReason, localNodeUnhealthyReason())
+
+	// The Node stays unhealthy while a health check is failing.
+	checker.errors[HealthCheckGateway] = nil
+	checker.errors[HealthCheckUplink] = fmt.Errorf("uplink interface eth0 is down")
+	cluster.checkHealth()
+	assert.Equal(t, expectedReason, cluster.getUnhealthyReason())
+
+	// The Node becomes healthy when all the health checks succeed.
+	checker.errors[HealthCheckUplink] = nil
+	cluster.checkHealth()
+	assert.Empty(t, cluster.getUnhealthyReason())
+	assert.Empty(t, localNodeUnhealthyReason())
+}
@@ -155,6 +155,17 @@ var (
 			StabilityLevel: metrics.ALPHA,
 		},
 	)
+
+	EgressDroppedPackets = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemAgent,
+			Name:           "egress_dropped_packets",
+			Help:           "Number of packets dropped on local Node by the bandwidth limit of an Egress. The Egress name is used as a label.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"egress"},
+	)
 )
 
 func InitializePrometheusMetrics() {
@@ -164,6 +175,7 @@ func InitializePrometheusMetrics() {
 	InitializeNetworkPolicyMetrics()
 	InitializeOVSMetrics()
 	InitializeConnectionMetrics()
+	InitializeEgressMetrics()
 }
 
 func InitializePodMetrics() {
@@ -227,3 +239,9 @@ func InitializeConnectionMetrics() {
 		klog.Errorf("Failed to register antrea_agent_conntrack_max_connection_count with error: %v", err)
 	}
 }
+
+func InitializeEgressMetrics() {
+	if err := legacyregistry.Register(EgressDroppedPackets); err != nil {
+		klog.Errorf("Failed to register antrea_agent_egress_dropped_packets with error: %v", err)
+	}
+}
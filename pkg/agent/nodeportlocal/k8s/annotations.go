@@ -37,10 +37,13 @@ const (
 )
 
 // NPLAnnotation is the structure used for setting NodePortLocal annotation on the Pods.
+// Protocol is the lowercase name of the protocol of the port mapping: tcp, udp or sctp. It may be empty in
+// annotations set by previous versions, which only supported TCP.
 type NPLAnnotation struct {
 	PodPort  int    `json:"podPort"`
 	NodeIP   string `json:"nodeIP"`
 	NodePort int    `json:"nodePort"`
+	Protocol string `json:"protocol"`
 }
 
 func toJSON(serialize interface{}) string {
@@ -87,8 +90,10 @@ func compareNPLAnnotationLists(annotations1, annotations2 []NPLAnnotation) bool
 		return false
 	}
 	nplAnnotationLess := func(a1, a2 *NPLAnnotation) bool {
-		return a1.NodePort < a2.NodePort
-
+		if a1.NodePort != a2.NodePort {
+			return a1.NodePort < a2.NodePort
+		}
+		return a1.Protocol < a2.Protocol
 	}
 	sort.Slice(annotations1, func(i, j int) bool {
 		return nplAnnotationLess(&annotations1[i], &annotations1[j])
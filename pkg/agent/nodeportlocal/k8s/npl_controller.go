@@ -198,11 +198,6 @@ func validateNPLService(svc *corev1.Service) {
 	if svc.Spec.Type == corev1.ServiceTypeNodePort {
 		klog.InfoS("Service is of type NodePort and cannot be used for NodePortLocal, the NodePortLocal annotation will have no effect", "service", klog.KObj(svc))
 	}
-	for _, port := range svc.Spec.Ports {
-		if port.Protocol != corev1.ProtocolTCP {
-			klog.InfoS("Service has NodePortLocal enabled but it includes a non-TCP Service port, which will be ignored", "service", klog.KObj(svc))
-		}
-	}
 }
 
 func (c *NPLController) enqueueSvcUpdate(oldObj, newObj interface{}) {
@@ -294,6 +289,15 @@ func buildPortProto(name, prototcol string) string {
 	return name + delim + prototcol
 }
 
+// nplProtocol returns the protocol used by the port table and the NodePortLocal annotation for a Kubernetes
+// protocol.
+func nplProtocol(protocol corev1.Protocol) string {
+	if protocol == "" {
+		return rules.ProtocolTCP
+	}
+	return strings.ToLower(string(protocol))
+}
+
 func parsePortProto(targetPort string) (int, string, error) {
 	portProto := strings.Split(targetPort, delim)
 	if len(portProto) != 2 {
@@ -321,11 +325,6 @@ func (c *NPLController) getTargetPortsForServicesOfPod(obj interface{}) (sets.St
 			if pod.Namespace == svc.Namespace &&
 				matchSvcSelectorPodLabels(svc.Spec.Selector, pod.GetLabels()) {
 				for _, port := range svc.Spec.Ports {
-					if port.Protocol != corev1.ProtocolTCP {
-						// Not supported yet. A message is logged when the
-						// Service is processed.
-						continue
-					}
 					switch port.TargetPort.Type {
 					case intstr.Int:
 						// An entry of format <target-port>:<protocol> (e.g. 8080:TCP) is added for a target port in the set targetPortsInt.
@@ -409,7 +408,7 @@ func (c *NPLController) deletePodIPFromCache(key string) {
 func (c *NPLController) deleteAllPortRulesIfAny(podIP string) error {
 	data := c.portTable.GetDataForPodIP(podIP)
 	for _, d := range data {
-		err := c.portTable.DeleteRule(d.PodIP, int(d.PodPort))
+		err := c.portTable.DeleteRule(d.PodIP, int(d.PodPort), d.Protocol)
 		if err != nil {
 			return err
 		}
@@ -453,7 +452,7 @@ func (c *NPLController) handleAddUpdatePod(key string, obj interface{}) error {
 	klog.V(2).Infof("Pod %s is selected by a Service for which NodePortLocal is enabled", key)
 
 	var nodePort int
-	podPorts := make(map[int]struct{})
+	podPorts := make(map[string]struct{})
 	podContainers := pod.Spec.Containers
 	nplAnnotations := []NPLAnnotation{}
 
@@ -503,17 +502,18 @@ func (c *NPLController) handleAddUpdatePod(key string, obj interface{}) error {
 	// (ignoring NPL annotations) and make sure they are present. As we do so, we build the expected list of
 	// NPL annotations for the Pod.
 	for _, targetPort := range targetPortsInt.List() {
-		port, _, err := parsePortProto(targetPort)
+		port, k8sProtocol, err := parsePortProto(targetPort)
 		if err != nil {
 			return fmt.Errorf("failed to parse port number and protocol from %s for Pod %s: %v", targetPort, key, err)
 		}
-		podPorts[port] = struct{}{}
-		portData := c.portTable.GetEntryByPodIPPort(podIP, port)
+		protocol := nplProtocol(corev1.Protocol(k8sProtocol))
+		podPorts[buildPortProto(fmt.Sprint(port), protocol)] = struct{}{}
+		portData := c.portTable.GetEntryByPodIPPort(podIP, port, protocol)
 		if portData == nil {
 			if hport, ok := hostPorts[targetPort]; ok {
 				nodePort = hport
 			} else {
-				nodePort, err = c.portTable.AddRule(podIP, port)
+				nodePort, err = c.portTable.AddRule(podIP, port, protocol)
 				if err != nil {
 					return fmt.Errorf("failed to add rule for Pod %s: %v", key, err)
 				}
@@ -525,19 +525,19 @@ func (c *NPLController) handleAddUpdatePod(key string, obj interface{}) error {
 			PodPort:  port,
 			NodeIP:   pod.Status.HostIP,
 			NodePort: nodePort,
+			Protocol: protocol,
 		})
 	}
 
 	// second, delete any existing rule that is not needed based on the current Pod
-	// specification.
+	// specification. Note that the Pod may have rules without having the annotation, if
+	// they were restored from the rules programmed in the Node when the Agent started.
 	entries := c.portTable.GetDataForPodIP(podIP)
-	if nplExists {
-		for _, data := range entries {
-			if _, exists := podPorts[data.PodPort]; !exists {
-				err := c.portTable.DeleteRule(podIP, int(data.PodPort))
-				if err != nil {
-					return fmt.Errorf("failed to delete rule for Pod IP %s, Pod Port %d: %v", podIP, data.PodPort, err)
-				}
+	for _, data := range entries {
+		if _, exists := podPorts[buildPortProto(fmt.Sprint(data.PodPort), data.Protocol)]; !exists {
+			err := c.portTable.DeleteRule(podIP, int(data.PodPort), data.Protocol)
+			if err != nil {
+				return fmt.Errorf("failed to delete rule for Pod IP %s, Pod Port %d, Protocol %s: %v", podIP, data.PodPort, data.Protocol, err)
 			}
 		}
 	}
@@ -557,19 +557,27 @@ func (c *NPLController) handleAddUpdatePod(key string, obj interface{}) error {
 // cleared. If the Node port is invalid (maybe the port range was changed and the Agent was
 // restarted), the annotation is ignored and will be removed by the Pod event handlers. The Pod
 // event handlers will also take care of allocating a new Node port if required.
+// The port mappings of the NodePortLocal rules which are still programmed in the Node (e.g. when the
+// Agent restarted before annotating a Pod) are restored as well, so that the Node ports which may
+// already be used by external load balancers are kept rather than reallocated. See
+// reconcileNPLPorts for how conflicts between both sources are resolved.
 func (c *NPLController) GetPodsAndGenRules() error {
 	podList, err := c.podLister.List(labels.Everything())
 	if err != nil {
 		return fmt.Errorf("error in fetching the Pods for Node %s: %v", c.nodeName, err)
 	}
 
-	allNPLPorts := []rules.PodNodePort{}
+	podIPs := sets.NewString()
+	annotatedNPLPorts := []rules.PodNodePort{}
 	for i := range podList {
 		// For each Pod:
 		// check if a valid NodePortLocal annotation exists for this Pod:
 		//   if yes, verifiy validity of the Node port, update the port table and add a rule to the
 		//   rules buffer.
 		pod := podList[i]
+		if pod.Status.PodIP != "" {
+			podIPs.Insert(pod.Status.PodIP)
+		}
 		annotations := pod.GetAnnotations()
 		nplAnnotation, ok := annotations[NPLAnnotationKey]
 		if !ok {
@@ -587,21 +595,38 @@ func (c *NPLController) GetPodsAndGenRules() error {
 		}
 
 		for _, npl := range nplData {
+			// Annotations set by previous versions don't have the protocol, which can only be TCP.
+			protocol := npl.Protocol
+			if protocol == "" {
+				protocol = rules.ProtocolTCP
+			}
+			if protocol != rules.ProtocolTCP && protocol != rules.ProtocolUDP && protocol != rules.ProtocolSCTP {
+				klog.V(2).Infof("Found NodePortLocal annotation with invalid protocol for Pod %s/%s: %s, ignoring it", pod.Namespace, pod.Name, nplAnnotation)
+				continue
+			}
 			if npl.NodePort > c.portTable.EndPort || npl.NodePort < c.portTable.StartPort {
 				// ignoring annotation for now, it will be removed by the first call
 				// to handleAddUpdatePod
 				klog.V(2).Infof("Found invalid NodePortLocal annotation for Pod %s/%s: %s, ignoring it", pod.Namespace, pod.Name, nplAnnotation)
 				continue
 			} else {
-				allNPLPorts = append(allNPLPorts, rules.PodNodePort{
+				annotatedNPLPorts = append(annotatedNPLPorts, rules.PodNodePort{
 					NodePort: npl.NodePort,
 					PodPort:  npl.PodPort,
 					PodIP:    pod.Status.PodIP,
+					Protocol: protocol,
 				})
 			}
 		}
 	}
 
+	liveNPLPorts, err := c.portTable.PodPortRules.GetAllRules()
+	if err != nil {
+		// The mappings can still be restored from the annotations.
+		klog.Warningf("Unable to get existing NodePortLocal rules: %v", err)
+	}
+	allNPLPorts := c.reconcileNPLPorts(annotatedNPLPorts, liveNPLPorts, podIPs)
+
 	if err := c.addRulesForNPLPorts(allNPLPorts); err != nil {
 		return err
 	}
@@ -609,6 +634,66 @@ func (c *NPLController) GetPodsAndGenRules() error {
 	return nil
 }
 
+// reconcileNPLPorts merges the port mappings restored from the Pod annotations and the ones restored
+// from the existing rules. Each Node port and protocol pair can only be used by one mapping, and each
+// Pod port and protocol can only be exposed by one Node port. To keep the mappings which are most
+// likely in use, the mappings found in both sources take precedence, followed by the ones only found
+// in the annotations. The mappings only found in the rules are kept if they belong to a Pod running on
+// this Node and their Node port is in the configured range; otherwise they are stale and are removed
+// when the rules are synced.
+func (c *NPLController) reconcileNPLPorts(annotatedNPLPorts, liveNPLPorts []rules.PodNodePort, podIPs sets.String) []rules.PodNodePort {
+	type podPortProtocol struct {
+		podIP    string
+		podPort  int
+		protocol string
+	}
+	liveSet := make(map[rules.PodNodePort]struct{}, len(liveNPLPorts))
+	for _, nplPort := range liveNPLPorts {
+		liveSet[nplPort] = struct{}{}
+	}
+	usedNodePorts := make(map[portcache.NodePortProtocol]struct{})
+	usedPodPorts := make(map[podPortProtocol]struct{})
+	allNPLPorts := []rules.PodNodePort{}
+	addNPLPort := func(nplPort rules.PodNodePort) {
+		nodePortKey := portcache.NodePortProtocol{NodePort: nplPort.NodePort, Protocol: nplPort.Protocol}
+		podPortKey := podPortProtocol{podIP: nplPort.PodIP, podPort: nplPort.PodPort, protocol: nplPort.Protocol}
+		if _, ok := usedNodePorts[nodePortKey]; ok {
+			klog.V(2).Infof("Node port %d/%s is already used, ignoring mapping to %s:%d", nplPort.NodePort, nplPort.Protocol, nplPort.PodIP, nplPort.PodPort)
+			return
+		}
+		if _, ok := usedPodPorts[podPortKey]; ok {
+			klog.V(2).Infof("Pod port %s:%d/%s is already exposed, ignoring mapping from Node port %d", nplPort.PodIP, nplPort.PodPort, nplPort.Protocol, nplPort.NodePort)
+			return
+		}
+		usedNodePorts[nodePortKey] = struct{}{}
+		usedPodPorts[podPortKey] = struct{}{}
+		allNPLPorts = append(allNPLPorts, nplPort)
+	}
+
+	for _, nplPort := range annotatedNPLPorts {
+		if _, ok := liveSet[nplPort]; ok {
+			addNPLPort(nplPort)
+		}
+	}
+	for _, nplPort := range annotatedNPLPorts {
+		if _, ok := liveSet[nplPort]; !ok {
+			addNPLPort(nplPort)
+		}
+	}
+	for _, nplPort := range liveNPLPorts {
+		if !podIPs.Has(nplPort.PodIP) {
+			klog.V(2).Infof("Removing stale NodePortLocal rule for Node port %d/%s and Pod IP %s", nplPort.NodePort, nplPort.Protocol, nplPort.PodIP)
+			continue
+		}
+		if nplPort.NodePort > c.portTable.EndPort || nplPort.NodePort < c.portTable.StartPort {
+			klog.V(2).Infof("Removing NodePortLocal rule for Node port %d/%s out of the port range", nplPort.NodePort, nplPort.Protocol)
+			continue
+		}
+		addNPLPort(nplPort)
+	}
+	return allNPLPorts
+}
+
 func (c *NPLController) addRulesForNPLPorts(allNPLPorts []rules.PodNodePort) error {
 	return c.portTable.SyncRules(allNPLPorts)
 }
@@ -45,7 +45,7 @@ import (
 
 func newPortTable(mockIPTables rules.PodPortRules, mockPortOpener portcache.LocalPortOpener) *portcache.PortTable {
 	ptable := portcache.PortTable{StartPort: 61000, EndPort: 65000}
-	ptable.Table = make(map[int]portcache.NodePortData)
+	ptable.Table = make(map[portcache.NodePortProtocol]portcache.NodePortData)
 	ptable.PodPortRules = mockIPTables
 	ptable.LocalPortOpener = mockPortOpener
 	return &ptable
@@ -172,6 +172,7 @@ func (data *testData) runWrapper(c *nplk8s.NPLController) {
 
 type testConfig struct {
 	defaultPortOpenerExpectations bool
+	existingRules                 []rules.PodNodePort
 }
 
 func newTestConfig() *testConfig {
@@ -185,19 +186,25 @@ func (tc *testConfig) withDefaultPortOpenerExpectations(v bool) *testConfig {
 	return tc
 }
 
+func (tc *testConfig) withExistingRules(existingRules ...rules.PodNodePort) *testConfig {
+	tc.existingRules = existingRules
+	return tc
+}
+
 func setUp(t *testing.T, tc *testConfig, objects ...runtime.Object) *testData {
 	os.Setenv("NODE_NAME", defaultNodeName)
 
 	mockCtrl := gomock.NewController(t)
 
 	mockIPTables := rulestesting.NewMockPodPortRules(mockCtrl)
-	mockIPTables.EXPECT().AddRule(gomock.Any(), gomock.Any()).AnyTimes()
-	mockIPTables.EXPECT().DeleteRule(gomock.Any(), gomock.Any()).AnyTimes()
+	mockIPTables.EXPECT().AddRule(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockIPTables.EXPECT().DeleteRule(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 	mockIPTables.EXPECT().AddAllRules(gomock.Any()).AnyTimes()
+	mockIPTables.EXPECT().GetAllRules().AnyTimes().Return(tc.existingRules, nil)
 
 	mockPortOpener := portcachetesting.NewMockLocalPortOpener(mockCtrl)
 	if tc.defaultPortOpenerExpectations {
-		mockPortOpener.EXPECT().OpenLocalPort(gomock.Any()).AnyTimes().Return(&fakeSocket{}, nil)
+		mockPortOpener.EXPECT().OpenLocalPort(gomock.Any(), gomock.Any()).AnyTimes().Return(&fakeSocket{}, nil)
 	}
 
 	data := &testData{
@@ -243,7 +250,7 @@ func setUpWithTestServiceAndPod(t *testing.T, tc *testConfig) (*testData, *corev
 	value, err := testData.pollForPodAnnotation(testPod.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
 	testData.checkAnnotationValue(value, defaultPort)
-	require.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	require.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 
 	return testData, testSvc, testPod
 }
@@ -323,7 +330,7 @@ func TestSvcNamespaceUpdate(t *testing.T) {
 	// Check that annotation and the rule are removed.
 	_, err = testData.pollForPodAnnotation(testPodDefaultNS.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestSvcTypeUpdate updates Service type from ClusterIP to NodePort
@@ -339,7 +346,7 @@ func TestSvcTypeUpdate(t *testing.T) {
 	// Check that annotation and the rule are removed.
 	_, err := testData.pollForPodAnnotation(testPod.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 
 	// Update Service type to ClusterIP.
 	testSvc.Spec.Type = "ClusterIP"
@@ -347,7 +354,7 @@ func TestSvcTypeUpdate(t *testing.T) {
 
 	_, err = testData.pollForPodAnnotation(testPod.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestSvcUpdateAnnotation updates the Service spec to disabled NPL. It then verifies that the Pod's
@@ -363,7 +370,7 @@ func TestSvcUpdateAnnotation(t *testing.T) {
 	// Check that annotation and the rule is removed.
 	_, err := testData.pollForPodAnnotation(testPod.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 
 	// Enable NPL back.
 	testSvc.Annotations = map[string]string{nplk8s.NPLEnabledAnnotationKey: "true"}
@@ -371,7 +378,7 @@ func TestSvcUpdateAnnotation(t *testing.T) {
 
 	_, err = testData.pollForPodAnnotation(testPod.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestSvcRemoveAnnotation is the same as TestSvcUpdateAnnotation, but it deletes the NPL enabled
@@ -385,7 +392,7 @@ func TestSvcRemoveAnnotation(t *testing.T) {
 
 	_, err := testData.pollForPodAnnotation(testPod.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestSvcUpdateSelector updates the Service selector so that it no longer selects the Pod, and
@@ -399,14 +406,14 @@ func TestSvcUpdateSelector(t *testing.T) {
 
 	_, err := testData.pollForPodAnnotation(testPod.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 
 	testSvc.Spec.Selector = map[string]string{defaultAppSelectorKey: defaultAppSelectorVal}
 	testData.updateServiceOrFail(testSvc)
 
 	_, err = testData.pollForPodAnnotation(testPod.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestPodUpdateSelectorLabel updates the Pod's labels so that the Pod is no longer selected by the
@@ -421,7 +428,7 @@ func TestPodUpdateSelectorLabel(t *testing.T) {
 
 	_, err := testData.pollForPodAnnotation(testPod.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestSvcDelete deletes the Service. It then verifies that the Pod's NPL annotation is removed and
@@ -436,7 +443,7 @@ func TestSvcDelete(t *testing.T) {
 
 	_, err = testData.pollForPodAnnotation(testPod.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestPodDelete verifies that when a Pod gets deleted, the corresponding entry gets deleted from
@@ -450,7 +457,7 @@ func TestPodDelete(t *testing.T) {
 	t.Logf("Successfully deleted Pod: %s", testPod.Name)
 
 	err = wait.Poll(time.Second, 20*time.Second, func() (bool, error) {
-		return !testData.portTable.RuleExists(defaultPodIP, defaultPort), nil
+		return !testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP), nil
 	})
 	assert.NoError(t, err, "Error when polling for port table update")
 }
@@ -468,8 +475,40 @@ func TestAddMultiPortPodSvc(t *testing.T) {
 	require.NoError(t, err, "Poll for annotation check failed")
 	nplData := testData.checkAnnotationValue(value, defaultPort, newPort)
 	assert.NotEqual(t, nplData[0].NodePort, nplData[1].NodePort)
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, newPort))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, newPort, rules.ProtocolTCP))
+}
+
+// TestAddMultiProtocolPodSvc creates a Pod and a Service with the same target port for TCP and UDP.
+// It verifies that the Pod's NPL annotation and the local port table are updated with a port mapping for
+// each protocol, and that the Node ports are allocated independently for each protocol.
+func TestAddMultiProtocolPodSvc(t *testing.T) {
+	testSvc := getTestSvc()
+	udpPort := testSvc.Spec.Ports[0]
+	udpPort.Protocol = corev1.ProtocolUDP
+	testSvc.Spec.Ports = append(testSvc.Spec.Ports, udpPort)
+	testPod := getTestPod()
+	testData := setUp(t, newTestConfig(), testSvc, testPod)
+	defer testData.tearDown()
+
+	value, err := testData.pollForPodAnnotation(testPod.Name, true)
+	require.NoError(t, err, "Poll for annotation check failed")
+	nplData := testData.checkAnnotationValue(value, defaultPort, defaultPort)
+	protocols := []string{nplData[0].Protocol, nplData[1].Protocol}
+	assert.ElementsMatch(t, []string{rules.ProtocolTCP, rules.ProtocolUDP}, protocols)
+	assert.Equal(t, nplData[0].NodePort, nplData[1].NodePort)
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolUDP))
+
+	// Remove the UDP Service port.
+	testSvc.Spec.Ports = testSvc.Spec.Ports[:1]
+	testData.updateServiceOrFail(testSvc)
+
+	err = wait.Poll(time.Second, 20*time.Second, func() (bool, error) {
+		return !testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolUDP), nil
+	})
+	require.NoError(t, err, "Error when polling for port table update")
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestPodAddMultiPort creates a Pod with multiple ports and a Service with only one target port.
@@ -495,8 +534,8 @@ func TestAddMultiPortPodSinglePortSvc(t *testing.T) {
 	require.NoError(t, err, "Poll for annotation check failed")
 	nplData := testData.checkAnnotationValue(value, defaultPort)
 	assert.Len(t, nplData, 1)
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, newPort2))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, newPort2, rules.ProtocolTCP))
 }
 
 // TestPodAddHostPort creates a Pod with host ports and verifies that the Pod's NPL annotation
@@ -517,7 +556,7 @@ func TestPodAddHostPort(t *testing.T) {
 	require.NoError(t, err, "Poll for annotation check failed")
 	nplData := testData.checkAnnotationValue(value, defaultPort)
 	assert.Equal(t, nplData[0].NodePort, hostPort)
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestPodAddHostPort creates a Pod with multiple host ports having same value but different protocol.
@@ -542,7 +581,7 @@ func TestPodAddHostPortMultiProtocol(t *testing.T) {
 	require.NoError(t, err, "Poll for annotation check failed")
 	nplData := testData.checkAnnotationValue(value, defaultPort)
 	assert.Equal(t, nplData[0].NodePort, hostPort)
-	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestPodAddHostPortWrongProtocol creates a Pod with a host port but with protocol UDP instead of TCP.
@@ -562,7 +601,7 @@ func TestPodAddHostPortWrongProtocol(t *testing.T) {
 	require.NoError(t, err, "Poll for annotation check failed")
 	nplData := testData.checkAnnotationValue(value, defaultPort)
 	assert.NotEqual(t, nplData[0].NodePort, hostPort)
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestTargetPortWithName creates a Service with target port name in string.
@@ -581,13 +620,13 @@ func TestTargetPortWithName(t *testing.T) {
 
 	_, err := testData.pollForPodAnnotation(testPod.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort, rules.ProtocolTCP))
 
 	testSvc = getTestSvcWithPortName("wrongPort")
 	testData.updateServiceOrFail(testSvc)
 	_, err = testData.pollForPodAnnotation(testPod.Name, false)
 	require.NoError(t, err, "Poll for annotation check failed")
-	assert.False(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort))
+	assert.False(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort, rules.ProtocolTCP))
 }
 
 // TestMultiplePods creates multiple Pods and verifies that NPL annotations for both Pods are
@@ -606,12 +645,12 @@ func TestMultiplePods(t *testing.T) {
 	value, err := testData.pollForPodAnnotation(testPod1.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
 	nplData1 := testData.checkAnnotationValue(value, defaultPort)
-	assert.True(t, testData.portTable.RuleExists(testPod1.Status.PodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(testPod1.Status.PodIP, defaultPort, rules.ProtocolTCP))
 
 	value, err = testData.pollForPodAnnotation(testPod2.Name, true)
 	assert.NoError(t, err, "Poll for annotation check failed")
 	nplData2 := testData.checkAnnotationValue(value, defaultPort)
-	assert.True(t, testData.portTable.RuleExists(testPod2.Status.PodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(testPod2.Status.PodIP, defaultPort, rules.ProtocolTCP))
 
 	assert.NotEqual(t, nplData1[0].NodePort, nplData2[0].NodePort)
 }
@@ -627,8 +666,8 @@ func TestMultipleServicesSameBackendPod(t *testing.T) {
 	value, err := testData.pollForPodAnnotation(testPod.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
 	nplData := testData.checkAnnotationValue(value, defaultPort, 9090)
-	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort))
-	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, 9090))
+	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort, rules.ProtocolTCP))
+	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, 9090, rules.ProtocolTCP))
 	assert.NotEqual(t, nplData[0].NodePort, nplData[1].NodePort)
 }
 
@@ -649,7 +688,7 @@ func TestInitInvalidPod(t *testing.T) {
 	value, err := testData.pollForPodAnnotation(testPod.Name, true)
 	require.NoError(t, err, "Poll for annotation check failed")
 	testData.checkAnnotationValue(value, defaultPort)
-	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(testPod.Status.PodIP, defaultPort, rules.ProtocolTCP))
 }
 
 var (
@@ -667,8 +706,8 @@ func TestNodePortAlreadyBoundTo(t *testing.T) {
 
 	var nodePort int
 	gomock.InOrder(
-		testData.mockPortOpener.EXPECT().OpenLocalPort(gomock.Any()).Return(nil, portTakenError),
-		testData.mockPortOpener.EXPECT().OpenLocalPort(gomock.Any()).DoAndReturn(func(port int) (portcache.Closeable, error) {
+		testData.mockPortOpener.EXPECT().OpenLocalPort(gomock.Any(), gomock.Any()).Return(nil, portTakenError),
+		testData.mockPortOpener.EXPECT().OpenLocalPort(gomock.Any(), gomock.Any()).DoAndReturn(func(port int, protocol string) (portcache.Closeable, error) {
 			nodePort = port
 			return &fakeSocket{}, nil
 		}),
@@ -684,5 +723,52 @@ func TestNodePortAlreadyBoundTo(t *testing.T) {
 	require.NoError(t, err, "Poll for annotation check failed")
 	annotation := testData.checkAnnotationValue(value, defaultPort)[0] // length of slice is guaranteed to be correct at this stage
 	assert.Equal(t, nodePort, annotation.NodePort)
-	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort))
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
+}
+
+// TestInitRestoreFromRules simulates an agent restart case in which a NPL rule was programmed for a
+// Pod, but the Pod was not annotated. The Node port of the existing rule should be reused for the
+// Pod annotation, while the existing rule for an unknown Pod should be removed.
+func TestInitRestoreFromRules(t *testing.T) {
+	testSvc := getTestSvc()
+	testPod := getTestPod()
+	nodePort := 61005
+	staleNodePort := 61006
+	testConfig := newTestConfig().withExistingRules(
+		rules.PodNodePort{NodePort: nodePort, PodPort: defaultPort, PodIP: defaultPodIP, Protocol: rules.ProtocolTCP},
+		rules.PodNodePort{NodePort: staleNodePort, PodPort: defaultPort, PodIP: "192.168.32.20", Protocol: rules.ProtocolTCP},
+	)
+	testData := setUp(t, testConfig, testSvc, testPod)
+	defer testData.tearDown()
+
+	value, err := testData.pollForPodAnnotation(testPod.Name, true)
+	require.NoError(t, err, "Poll for annotation check failed")
+	nplData := testData.checkAnnotationValue(value, defaultPort)
+	assert.Equal(t, nodePort, nplData[0].NodePort)
+	assert.Equal(t, rules.ProtocolTCP, nplData[0].Protocol)
+	assert.True(t, testData.portTable.RuleExists(defaultPodIP, defaultPort, rules.ProtocolTCP))
+	assert.False(t, testData.portTable.RuleExists("192.168.32.20", defaultPort, rules.ProtocolTCP))
+}
+
+// TestInitRestoreConflict simulates an agent restart case in which the Pod annotation and the
+// existing NPL rule use different Node ports for the same Pod port. The Node port from the
+// annotation should be kept.
+func TestInitRestoreConflict(t *testing.T) {
+	testSvc := getTestSvc()
+	testPod := getTestPod()
+	annotatedNodePort := 61002
+	testPod.SetAnnotations(map[string]string{
+		nplk8s.NPLAnnotationKey: fmt.Sprintf("[{\"podPort\":%d,\"nodeIP\":\"%s\",\"nodePort\":%d,\"protocol\":\"tcp\"}]", defaultPort, defaultHostIP, annotatedNodePort),
+	})
+	testConfig := newTestConfig().withExistingRules(
+		rules.PodNodePort{NodePort: 61005, PodPort: defaultPort, PodIP: defaultPodIP, Protocol: rules.ProtocolTCP},
+	)
+	testData := setUp(t, testConfig, testSvc, testPod)
+	defer testData.tearDown()
+
+	value, err := testData.pollForPodAnnotation(testPod.Name, true)
+	require.NoError(t, err, "Poll for annotation check failed")
+	nplData := testData.checkAnnotationValue(value, defaultPort)
+	assert.Equal(t, annotatedNodePort, nplData[0].NodePort)
+	assert.Len(t, testData.portTable.GetDataForPodIP(defaultPodIP), 1)
 }
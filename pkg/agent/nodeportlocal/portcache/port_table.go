@@ -30,17 +30,24 @@ type NodePortData struct {
 	NodePort int
 	PodPort  int
 	PodIP    string
+	Protocol string
 	socket   Closeable
 }
 
+// NodePortProtocol is the key of the port table: the same Node port can be allocated independently for each protocol.
+type NodePortProtocol struct {
+	NodePort int
+	Protocol string
+}
+
 type LocalPortOpener interface {
-	OpenLocalPort(port int) (Closeable, error)
+	OpenLocalPort(port int, protocol string) (Closeable, error)
 }
 
 type localPortOpener struct{}
 
 type PortTable struct {
-	Table           map[int]NodePortData
+	Table           map[NodePortProtocol]NodePortData
 	StartPort       int
 	EndPort         int
 	PodPortRules    rules.PodPortRules
@@ -50,7 +57,7 @@ type PortTable struct {
 
 func NewPortTable(start, end int) (*PortTable, error) {
 	ptable := PortTable{StartPort: start, EndPort: end}
-	ptable.Table = make(map[int]NodePortData)
+	ptable.Table = make(map[NodePortProtocol]NodePortData)
 	ptable.PodPortRules = rules.InitRules()
 	ptable.LocalPortOpener = &localPortOpener{}
 	if err := ptable.PodPortRules.Init(); err != nil {
@@ -62,13 +69,13 @@ func NewPortTable(start, end int) (*PortTable, error) {
 func (pt *PortTable) CleanupAllEntries() {
 	pt.tableLock.Lock()
 	defer pt.tableLock.Unlock()
-	pt.Table = make(map[int]NodePortData)
+	pt.Table = make(map[NodePortProtocol]NodePortData)
 }
 
-func (pt *PortTable) GetEntry(nodeport int) *NodePortData {
+func (pt *PortTable) GetEntry(nodeport int, protocol string) *NodePortData {
 	pt.tableLock.RLock()
 	defer pt.tableLock.RUnlock()
-	data, _ := pt.Table[nodeport]
+	data, _ := pt.Table[NodePortProtocol{NodePort: nodeport, Protocol: protocol}]
 	return &data
 }
 
@@ -84,25 +91,25 @@ func (pt *PortTable) GetDataForPodIP(ip string) []NodePortData {
 	return allData
 }
 
-func (pt *PortTable) GetEntryByPodIPPort(ip string, port int) *NodePortData {
+func (pt *PortTable) GetEntryByPodIPPort(ip string, port int, protocol string) *NodePortData {
 	pt.tableLock.RLock()
 	defer pt.tableLock.RUnlock()
-	return pt.getEntryByPodIPPort(ip, port)
+	return pt.getEntryByPodIPPort(ip, port, protocol)
 }
 
-func (pt *PortTable) getEntryByPodIPPort(ip string, port int) *NodePortData {
+func (pt *PortTable) getEntryByPodIPPort(ip string, port int, protocol string) *NodePortData {
 	for _, data := range pt.Table {
-		if data.PodIP == ip && data.PodPort == port {
+		if data.PodIP == ip && data.PodPort == port && data.Protocol == protocol {
 			return &data
 		}
 	}
 	return nil
 }
 
-func (pt *PortTable) getFreePort(podIP string, podPort int) (int, Closeable, error) {
+func (pt *PortTable) getFreePort(podIP string, podPort int, protocol string) (int, Closeable, error) {
 	for i := pt.StartPort; i <= pt.EndPort; i++ {
-		if _, ok := pt.Table[i]; !ok {
-			socket, err := pt.LocalPortOpener.OpenLocalPort(i)
+		if _, ok := pt.Table[NodePortProtocol{NodePort: i, Protocol: protocol}]; !ok {
+			socket, err := pt.LocalPortOpener.OpenLocalPort(i, protocol)
 			if err != nil {
 				continue
 			}
@@ -112,44 +119,45 @@ func (pt *PortTable) getFreePort(podIP string, podPort int) (int, Closeable, err
 	return 0, nil, fmt.Errorf("no free port found")
 }
 
-func (pt *PortTable) AddRule(podIP string, podPort int) (int, error) {
+func (pt *PortTable) AddRule(podIP string, podPort int, protocol string) (int, error) {
 	pt.tableLock.Lock()
 	defer pt.tableLock.Unlock()
-	nodePort, socket, err := pt.getFreePort(podIP, podPort)
+	nodePort, socket, err := pt.getFreePort(podIP, podPort, protocol)
 	if err != nil {
 		return 0, err
 	}
-	if err := pt.PodPortRules.AddRule(nodePort, fmt.Sprintf("%s:%d", podIP, podPort)); err != nil {
+	if err := pt.PodPortRules.AddRule(nodePort, fmt.Sprintf("%s:%d", podIP, podPort), protocol); err != nil {
 		if err := socket.Close(); err != nil {
 			klog.ErrorS(err, "Unexpected error when closing socket")
 		}
 		return 0, err
 	}
-	pt.Table[nodePort] = NodePortData{
+	pt.Table[NodePortProtocol{NodePort: nodePort, Protocol: protocol}] = NodePortData{
 		NodePort: nodePort,
 		PodIP:    podIP,
 		PodPort:  podPort,
+		Protocol: protocol,
 		socket:   socket,
 	}
 	return nodePort, nil
 }
 
-func (pt *PortTable) DeleteRule(podIP string, podPort int) error {
+func (pt *PortTable) DeleteRule(podIP string, podPort int, protocol string) error {
 	pt.tableLock.Lock()
 	defer pt.tableLock.Unlock()
-	data := pt.getEntryByPodIPPort(podIP, podPort)
-	if err := pt.PodPortRules.DeleteRule(data.NodePort, fmt.Sprintf("%s:%d", podIP, podPort)); err != nil {
+	data := pt.getEntryByPodIPPort(podIP, podPort, protocol)
+	if err := pt.PodPortRules.DeleteRule(data.NodePort, fmt.Sprintf("%s:%d", podIP, podPort), protocol); err != nil {
 		return err
 	}
 	if err := data.socket.Close(); err != nil {
-		return fmt.Errorf("Error when releasing local port %d: %v", data.NodePort, err)
+		return fmt.Errorf("Error when releasing local port %d/%s: %v", data.NodePort, protocol, err)
 	}
-	delete(pt.Table, data.NodePort)
+	delete(pt.Table, NodePortProtocol{NodePort: data.NodePort, Protocol: protocol})
 	return nil
 }
 
-func (pt *PortTable) RuleExists(podIP string, podPort int) bool {
-	data := pt.GetEntryByPodIPPort(podIP, podPort)
+func (pt *PortTable) RuleExists(podIP string, podPort int, protocol string) bool {
+	data := pt.GetEntryByPodIPPort(podIP, podPort, protocol)
 	if data != nil {
 		return true
 	}
@@ -161,36 +169,64 @@ func (pt *PortTable) SyncRules(allNPLPorts []rules.PodNodePort) error {
 	pt.tableLock.Lock()
 	defer pt.tableLock.Unlock()
 	for _, nplPort := range allNPLPorts {
-		socket, err := pt.LocalPortOpener.OpenLocalPort(nplPort.NodePort)
+		socket, err := pt.LocalPortOpener.OpenLocalPort(nplPort.NodePort, nplPort.Protocol)
 		if err != nil {
 			// This will be handled gracefully by the NPL controller: if there is an
 			// annotation using this port, it will be removed and replaced with a new
 			// one with a valid port mapping.
-			klog.ErrorS(err, "Cannot bind to local port, skipping it", "port", nplPort.NodePort)
+			klog.ErrorS(err, "Cannot bind to local port, skipping it", "port", nplPort.NodePort, "protocol", nplPort.Protocol)
 			continue
 		}
 		data := NodePortData{
 			NodePort: nplPort.NodePort,
 			PodPort:  nplPort.PodPort,
 			PodIP:    nplPort.PodIP,
+			Protocol: nplPort.Protocol,
 			socket:   socket,
 		}
-		pt.Table[nplPort.NodePort] = data
+		pt.Table[NodePortProtocol{NodePort: nplPort.NodePort, Protocol: nplPort.Protocol}] = data
 		validNPLPorts = append(validNPLPorts, nplPort)
 	}
 	return pt.PodPortRules.AddAllRules(validNPLPorts)
 }
 
-// openLocalPort binds to the provided port.
+// nopCloseable is used for the protocols for which no local port can be held.
+type nopCloseable struct{}
+
+func (nopCloseable) Close() error {
+	return nil
+}
+
+// openLocalPort binds to the provided port for the provided protocol.
 // This is inspired by the openLocalPort function in kube-proxy:
 // https://github.com/kubernetes/kubernetes/blob/86f8c3ee91b6faec437f97e3991107747d7fc5e8/pkg/proxy/iptables/proxier.go#L1664
-func (lpo *localPortOpener) OpenLocalPort(port int) (Closeable, error) {
-	// For now, NodePortLocal only supports IPv4 and TCP.
-	network := "tcp4"
-	listener, err := net.Listen(network, fmt.Sprintf(":%d", port))
-	if err != nil {
-		return nil, err
+func (lpo *localPortOpener) OpenLocalPort(port int, protocol string) (Closeable, error) {
+	// For now, NodePortLocal only supports IPv4.
+	var socket Closeable
+	switch protocol {
+	case rules.ProtocolTCP:
+		listener, err := net.Listen("tcp4", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return nil, err
+		}
+		socket = listener
+	case rules.ProtocolUDP:
+		addr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.ListenUDP("udp4", addr)
+		if err != nil {
+			return nil, err
+		}
+		socket = conn
+	case rules.ProtocolSCTP:
+		// SCTP sockets are not supported by the Go standard library. Like kube-proxy, we don't hold the local
+		// port in this case.
+		socket = nopCloseable{}
+	default:
+		return nil, fmt.Errorf("unsupported protocol %s", protocol)
 	}
-	klog.V(2).InfoS("Opened local port", "port", port)
-	return listener, nil
+	klog.V(2).InfoS("Opened local port", "port", port, "protocol", protocol)
+	return socket, nil
 }
@@ -19,6 +19,9 @@ package rules
 import (
 	"bytes"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 
 	"k8s.io/klog/v2"
 
@@ -44,7 +47,8 @@ func NewIPTableRules() *iptablesRules {
 	return &iptRule
 }
 
-// Init initializes IPTABLES rules for NPL. Currently it deletes existing rules to ensure that no stale entries are present.
+// Init initializes IPTABLES rules for NPL. Existing NPL rules are kept, so that the port mappings can be restored
+// from them. Stale rules are removed when all the rules are synced with AddAllRules.
 func (ipt *iptablesRules) Init() error {
 	if err := ipt.initRules(); err != nil {
 		return fmt.Errorf("initialization of NPL iptables rules failed: %v", err)
@@ -52,6 +56,18 @@ func (ipt *iptablesRules) Init() error {
 	return nil
 }
 
+// jumpRuleSpec is the rule which sends the traffic destined to a local address to the NPL chain, whatever its
+// protocol is.
+var jumpRuleSpec = []string{
+	"-m", "addrtype", "--dst-type", "LOCAL", "-j", NodePortLocalChain,
+}
+
+// legacyJumpRuleSpec is the rule installed by previous versions, which only supported TCP. It is removed when
+// initializing the rules.
+var legacyJumpRuleSpec = []string{
+	"-p", "tcp", "-m", "addrtype", "--dst-type", "LOCAL", "-j", NodePortLocalChain,
+}
+
 // initRules creates the NPL chain and links it to the PREROUTING (for incoming
 // traffic) and OUTPUT chain (for locally-generated traffic). All NPL DNAT rules
 // will be added to this chain.
@@ -59,32 +75,31 @@ func (ipt *iptablesRules) initRules() error {
 	if err := ipt.table.EnsureChain(iptables.NATTable, NodePortLocalChain); err != nil {
 		return err
 	}
-	ruleSpec := []string{
-		"-p", "tcp", "-m", "addrtype", "--dst-type", "LOCAL", "-j", NodePortLocalChain,
-	}
-	if err := ipt.table.EnsureRule(iptables.NATTable, iptables.PreRoutingChain, ruleSpec); err != nil {
-		return err
-	}
-	if err := ipt.table.EnsureRule(iptables.NATTable, iptables.OutputChain, ruleSpec); err != nil {
-		return err
+	for _, chain := range []string{iptables.PreRoutingChain, iptables.OutputChain} {
+		if err := ipt.table.EnsureRule(iptables.NATTable, chain, jumpRuleSpec); err != nil {
+			return err
+		}
+		if err := ipt.table.DeleteRule(iptables.NATTable, chain, legacyJumpRuleSpec); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func buildRuleForPod(port int, podIP string) []string {
+func buildRuleForPod(port int, podIP string, protocol string) []string {
 	return []string{
-		"-p", "tcp", "-m", "tcp", "--dport", fmt.Sprint(port),
+		"-p", protocol, "-m", protocol, "--dport", fmt.Sprint(port),
 		"-j", "DNAT", "--to-destination", podIP,
 	}
 }
 
 // AddRule appends a DNAT rule in NodePortLocalChain chain of NAT table
-func (ipt *iptablesRules) AddRule(port int, podIP string) error {
-	rule := buildRuleForPod(port, podIP)
+func (ipt *iptablesRules) AddRule(port int, podIP string, protocol string) error {
+	rule := buildRuleForPod(port, podIP, protocol)
 	if err := ipt.table.EnsureRule(iptables.NATTable, NodePortLocalChain, rule); err != nil {
 		return err
 	}
-	klog.Infof("Successfully added DNAT rule for Pod IP %s, port %d", podIP, port)
+	klog.Infof("Successfully added DNAT rule for Pod IP %s, port %d, protocol %s", podIP, port, protocol)
 	return nil
 }
 
@@ -96,7 +111,7 @@ func (ipt *iptablesRules) AddAllRules(nplList []PodNodePort) error {
 	writeLine(iptablesData, iptables.MakeChainLine(NodePortLocalChain))
 	for _, nplData := range nplList {
 		destination := nplData.PodIP + ":" + fmt.Sprint(nplData.PodPort)
-		rule := buildRuleForPod(nplData.NodePort, destination)
+		rule := buildRuleForPod(nplData.NodePort, destination, nplData.Protocol)
 		writeLine(iptablesData, append([]string{"-A", NodePortLocalChain}, rule...)...)
 	}
 	writeLine(iptablesData, "COMMIT")
@@ -106,10 +121,67 @@ func (ipt *iptablesRules) AddAllRules(nplList []PodNodePort) error {
 	return nil
 }
 
+// GetAllRules returns the port mappings of all the NPL rules programmed in the NodePortLocalChain chain. The rules
+// which cannot be parsed are ignored.
+func (ipt *iptablesRules) GetAllRules() ([]PodNodePort, error) {
+	exists, err := ipt.table.ChainExists(iptables.NATTable, NodePortLocalChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if NodePortLocal chain exists in NAT table: %v", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	ruleStrs, err := ipt.table.ListRules(iptables.NATTable, NodePortLocalChain)
+	if err != nil {
+		return nil, err
+	}
+	var nplList []PodNodePort
+	for _, ruleStr := range ruleStrs {
+		nplData, ok := parseRuleForPod(ruleStr)
+		if !ok {
+			klog.V(4).Infof("Ignoring iptables rule in NodePortLocal chain: %s", ruleStr)
+			continue
+		}
+		nplList = append(nplList, *nplData)
+	}
+	return nplList, nil
+}
+
+// parseRuleForPod parses a rule built by buildRuleForPod, as listed by iptables, e.g.
+// "-A ANTREA-NODE-PORT-LOCAL -p tcp -m tcp --dport 61000 -j DNAT --to-destination 10.10.0.2:80".
+func parseRuleForPod(ruleStr string) (*PodNodePort, bool) {
+	fields := strings.Fields(ruleStr)
+	var nplData PodNodePort
+	var err error
+	for i := 0; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "-p":
+			nplData.Protocol = fields[i+1]
+		case "--dport":
+			if nplData.NodePort, err = strconv.Atoi(fields[i+1]); err != nil {
+				return nil, false
+			}
+		case "--to-destination":
+			host, port, err := net.SplitHostPort(fields[i+1])
+			if err != nil {
+				return nil, false
+			}
+			nplData.PodIP = host
+			if nplData.PodPort, err = strconv.Atoi(port); err != nil {
+				return nil, false
+			}
+		}
+	}
+	if nplData.Protocol == "" || nplData.NodePort == 0 || nplData.PodIP == "" || nplData.PodPort == 0 {
+		return nil, false
+	}
+	return &nplData, true
+}
+
 // DeleteRule deletes a specific NPL rule from NodePortLocalChain chain
-func (ipt *iptablesRules) DeleteRule(port int, podIP string) error {
-	klog.Infof("Deleting DNAT rule for Pod IP %s, port %d", podIP, port)
-	rule := buildRuleForPod(port, podIP)
+func (ipt *iptablesRules) DeleteRule(port int, podIP string, protocol string) error {
+	klog.Infof("Deleting DNAT rule for Pod IP %s, port %d, protocol %s", podIP, port, protocol)
+	rule := buildRuleForPod(port, podIP, protocol)
 	if err := ipt.table.DeleteRule(iptables.NATTable, NodePortLocalChain, rule); err != nil {
 		return err
 	}
@@ -125,14 +197,12 @@ func (ipt *iptablesRules) DeleteAllRules() error {
 	if !exists {
 		return nil
 	}
-	ruleSpec := []string{
-		"-p", "tcp", "-m", "addrtype", "--dst-type", "LOCAL", "-j", NodePortLocalChain,
-	}
-	if err := ipt.table.DeleteRule(iptables.NATTable, iptables.PreRoutingChain, ruleSpec); err != nil {
-		return err
-	}
-	if err := ipt.table.DeleteRule(iptables.NATTable, iptables.OutputChain, ruleSpec); err != nil {
-		return err
+	for _, chain := range []string{iptables.PreRoutingChain, iptables.OutputChain} {
+		for _, ruleSpec := range [][]string{jumpRuleSpec, legacyJumpRuleSpec} {
+			if err := ipt.table.DeleteRule(iptables.NATTable, chain, ruleSpec); err != nil {
+				return err
+			}
+		}
 	}
 	if err := ipt.table.DeleteChain(iptables.NATTable, NodePortLocalChain); err != nil {
 		return err
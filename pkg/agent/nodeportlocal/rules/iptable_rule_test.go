@@ -0,0 +1,58 @@
+// +build !windows
+
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRuleForPod(t *testing.T) {
+	tests := []struct {
+		name     string
+		ruleStr  string
+		expected *PodNodePort
+	}{
+		{
+			name:     "built rule",
+			ruleStr:  strings.Join(append([]string{"-A", NodePortLocalChain}, buildRuleForPod(61000, "10.10.0.2:80", ProtocolUDP)...), " "),
+			expected: &PodNodePort{NodePort: 61000, PodPort: 80, PodIP: "10.10.0.2", Protocol: ProtocolUDP},
+		},
+		{
+			name:     "listed rule",
+			ruleStr:  "-A ANTREA-NODE-PORT-LOCAL -p tcp -m tcp --dport 61001 -j DNAT --to-destination 10.10.0.3:8080",
+			expected: &PodNodePort{NodePort: 61001, PodPort: 8080, PodIP: "10.10.0.3", Protocol: ProtocolTCP},
+		},
+		{
+			name:    "chain",
+			ruleStr: "-N ANTREA-NODE-PORT-LOCAL",
+		},
+		{
+			name:    "invalid destination",
+			ruleStr: "-A ANTREA-NODE-PORT-LOCAL -p tcp -m tcp --dport 61001 -j DNAT --to-destination 10.10.0.3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nplData, ok := parseRuleForPod(tt.ruleStr)
+			assert.Equal(t, tt.expected != nil, ok)
+			assert.Equal(t, tt.expected, nplData)
+		})
+	}
+}
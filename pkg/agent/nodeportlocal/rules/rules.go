@@ -19,10 +19,11 @@ package rules
 // PodPortRules is an interface to abstract operations on rules for Pods
 type PodPortRules interface {
 	Init() error
-	AddRule(port int, podip string) error
-	DeleteRule(port int, podip string) error
+	AddRule(port int, podip string, protocol string) error
+	DeleteRule(port int, podip string, protocol string) error
 	DeleteAllRules() error
 	AddAllRules(nplList []PodNodePort) error
+	GetAllRules() ([]PodNodePort, error)
 }
 
 // InitRules initializes rules based on the underlying implementation
@@ -14,9 +14,17 @@
 
 package rules
 
-// PodNodePort contains the Node Port, Pod Port and the Pod IP for NodePortLocal.
+// Protocols supported by NodePortLocal, in the lowercase form used by iptables and in the NodePortLocal annotation.
+const (
+	ProtocolTCP  = "tcp"
+	ProtocolUDP  = "udp"
+	ProtocolSCTP = "sctp"
+)
+
+// PodNodePort contains the Node Port, Pod Port, Pod IP and Protocol for NodePortLocal.
 type PodNodePort struct {
 	NodePort int
 	PodPort  int
 	PodIP    string
+	Protocol string
 }
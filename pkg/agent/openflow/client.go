@@ -15,11 +15,16 @@
 package openflow
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/contiv/libOpenflow/protocol"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"antrea.io/antrea/pkg/agent/config"
@@ -30,7 +35,19 @@ import (
 	"antrea.io/antrea/third_party/proxy"
 )
 
-const maxRetryForOFSwitch = 5
+const (
+	maxRetryForOFSwitch = 5
+	// dnsInterceptionFlowsKey is the key of the DNS interception flows in dnsFlowCache.
+	dnsInterceptionFlowsKey = "dns"
+	// l7NPReturnFlowsKey is the key of the L7 NetworkPolicy return flows in l7NPFlowCache.
+	l7NPReturnFlowsKey = "l7np"
+	// ipPoolGatewayFlowsKey is the key of the IPPool gateway flows in ipPoolFlowCache.
+	ipPoolGatewayFlowsKey = "ippool-gateways"
+)
+
+// ErrOVSMetersNotSupported is returned when an operation requires OpenFlow meters, which are not supported by the OVS
+// datapath.
+var ErrOVSMetersNotSupported = errors.New("OpenFlow meters are not supported by the OVS datapath")
 
 // Client is the interface to program OVS flows for entity connectivity of Antrea.
 type Client interface {
@@ -75,6 +92,11 @@ type Client interface {
 	// hostname. UninstallNodeFlows will do nothing if no connection to the host was established.
 	UninstallNodeFlows(hostname string) error
 
+	// InstallIPPoolGatewayFlows installs the flows to reply to the ARP requests for the gateways of the IPPool ranges
+	// with the local gateway MAC, so that the local Pods getting their IPs from the IPPools send their traffic to the
+	// local gateway. Only the IPv4 gateways are handled. Each call replaces the flows installed by the previous one.
+	InstallIPPoolGatewayFlows(gatewayIPs []net.IP) error
+
 	// InstallPodFlows should be invoked when a connection to a Pod on current Node. The
 	// interfaceName is used to identify the added flows. InstallPodFlows has all-or-nothing
 	// semantics(call succeeds if all the flows are installed successfully, otherwise no
@@ -88,7 +110,10 @@ type Client interface {
 	UninstallPodFlows(interfaceName string) error
 
 	// InstallServiceGroup installs a group for Service LB. Each endpoint
-	// is a bucket of the group. For now, each bucket has the same weight.
+	// is a bucket of the group. The bucket of an Endpoint which implements
+	// WeightedEndpoint uses the weight of the Endpoint, otherwise the default
+	// weight is used. The same Endpoint can occur multiple times in
+	// endpoints, in which case it has multiple buckets in the group.
 	InstallServiceGroup(groupID binding.GroupIDType, withSessionAffinity bool, endpoints []proxy.Endpoint) error
 	// UninstallServiceGroup removes the group and its buckets that are
 	// installed by InstallServiceGroup.
@@ -144,6 +169,25 @@ type Client interface {
 	// are removed from PolicyRule.From, else from PolicyRule.To.
 	DeletePolicyRuleAddress(ruleID uint32, addrType types.AddressType, addresses []types.Address, priority *uint16) error
 
+	// InstallDNSInterceptionFlows installs the flows to send the DNS responses received by the local Pods to the
+	// controller, with the CustomReasonDNS custom reason. It is used to learn the IPs of the FQDNs used in
+	// Antrea-native policy rules.
+	InstallDNSInterceptionFlows() error
+
+	// UninstallDNSInterceptionFlows removes the flows installed by InstallDNSInterceptionFlows.
+	UninstallDNSInterceptionFlows() error
+
+	// InstallPolicyLoggingMeters makes the packets logged by each Antrea-native policy rule with logging enabled
+	// rate-limited by a meter of the rule, with the provided rate and burst in packets per second, and installs the
+	// group which sends copies of the logged packets to the controller. It must be called before installing any rule.
+	// It does nothing if OVS meters are not supported.
+	InstallPolicyLoggingMeters(rate, burst uint32) error
+
+	// InstallL7NetworkPolicyFlows installs the flows to receive the packets sent back to the OVS bridge by the L7
+	// engine through returnOFPort, and saves targetOFPort to which the packets of the connections allowed by the
+	// Antrea-native policy rules with L7 protocols are redirected. It must be called before installing such rules.
+	InstallL7NetworkPolicyFlows(targetOFPort, returnOFPort uint32) error
+
 	// InstallBridgeUplinkFlows installs Openflow flows between bridge local port and uplink port to support
 	// host networking.
 	// This function is only used for Windows platform.
@@ -159,8 +203,10 @@ type Client interface {
 	// InstallSNATMarkFlows installs flows for a local SNAT IP. On Linux, a
 	// single flow is added to mark the packets tunnelled from remote Nodes
 	// that should be SNAT'd with the SNAT IP. On Windows, an extra flow is
-	// added to perform SNAT for the marked packets with the SNAT IP.
-	InstallSNATMarkFlows(snatIP net.IP, mark uint32) error
+	// added to perform SNAT for the marked packets with the SNAT IP. If
+	// metered is true, the packets are also sent to the meter of the SNAT
+	// IP, which must have been installed with InstallEgressMeter.
+	InstallSNATMarkFlows(snatIP net.IP, mark uint32, metered bool) error
 
 	// UninstallSNATMarkFlows removes the flows installed to set the packet
 	// mark for a SNAT IP.
@@ -174,12 +220,41 @@ type Client interface {
 	// tunnels egress packets to the remote Node using the SNAT IP as the
 	// tunnel destination, and the packets should be SNAT'd on the remote
 	// Node. As of now, a Pod can be configured to use only a single SNAT
-	// IP in a single address family (IPv4 or IPv6).
-	InstallPodSNATFlows(ofPort uint32, snatIP net.IP, snatMark uint32) error
+	// IP in a single address family (IPv4 or IPv6). If metered is true, the
+	// egress packets are also sent to the meter of the local SNAT IP.
+	InstallPodSNATFlows(ofPort uint32, snatIP net.IP, snatMark uint32, metered bool) error
 
 	// UninstallPodSNATFlows removes the SNAT flows for the local Pod.
 	UninstallPodSNATFlows(ofPort uint32) error
 
+	// InstallPodDestinationSNATFlows installs the SNAT flows of an Egress
+	// which only applies to the traffic to the destination CIDRs for a
+	// local Pod. The flows work like the ones installed by
+	// InstallPodSNATFlows but take precedence over them, and the flows for
+	// a longer prefix take precedence over the ones for a shorter prefix.
+	// Multiple Egresses with destinations, identified by egressName, can
+	// apply to the same Pod. If some of them have the same destination
+	// CIDR, the Egress with the smallest name takes precedence for it.
+	InstallPodDestinationSNATFlows(ofPort uint32, egressName string, snatIP net.IP, snatMark uint32, metered bool, destinations []net.IPNet) error
+
+	// UninstallPodDestinationSNATFlows removes the SNAT flows of an Egress
+	// with destinations for the local Pod.
+	UninstallPodDestinationSNATFlows(ofPort uint32, egressName string) error
+
+	// InstallEgressMeter installs or updates the meter which limits the
+	// bandwidth of the local SNAT IP identified by the mark. rate is
+	// represented in kbps and burst in kbits. ErrOVSMetersNotSupported is
+	// returned if the OVS datapath doesn't support meters.
+	InstallEgressMeter(mark, rate, burst uint32) error
+
+	// UninstallEgressMeter removes the meter of the local SNAT IP. The
+	// flows which use the meter must have been updated first.
+	UninstallEgressMeter(mark uint32) error
+
+	// GetEgressMeterDroppedPackets returns the number of packets dropped by
+	// the meters of the local SNAT IPs, keyed by the marks of the SNAT IPs.
+	GetEgressMeterDroppedPackets() (map[uint32]uint64, error)
+
 	// Disconnect disconnects the connection between client and OFSwitch.
 	Disconnect() error
 
@@ -277,6 +352,20 @@ type Client interface {
 		icmpCode uint8,
 		icmpData []byte,
 		isReject bool) error
+	// SendUDPPacketOut sends UDP packet as a packet-out to OVS. If isDNSResponse is true, the packet is a DNS response
+	// held by the DNS interception flows, which must not be intercepted again.
+	SendUDPPacketOut(
+		srcMAC string,
+		dstMAC string,
+		srcIP string,
+		dstIP string,
+		inPort uint32,
+		outPort int32,
+		isIPv6 bool,
+		udpSrcPort uint16,
+		udpDstPort uint16,
+		udpData []byte,
+		isDNSResponse bool) error
 }
 
 // GetFlowTableStatus returns an array of flow table status.
@@ -417,6 +506,19 @@ func (c *client) UninstallNodeFlows(hostname string) error {
 	return c.deleteFlows(c.nodeFlowCache, hostname)
 }
 
+func (c *client) InstallIPPoolGatewayFlows(gatewayIPs []net.IP) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+
+	var flows []binding.Flow
+	for _, gatewayIP := range gatewayIPs {
+		if gatewayIP.To4() != nil {
+			flows = append(flows, c.arpResponderFlowWithMAC(gatewayIP, c.nodeConfig.GatewayConfig.MAC, cookie.Node))
+		}
+	}
+	return c.modifyFlows(c.ipPoolFlowCache, ipPoolGatewayFlowsKey, flows)
+}
+
 func (c *client) InstallPodFlows(interfaceName string, podInterfaceIPs []net.IP, podInterfaceMAC net.HardwareAddr, ofPort uint32) error {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
@@ -674,6 +776,15 @@ func (c *client) initialize() error {
 		if err := c.genPacketInMeter(PacketInMeterIDTF, PacketInMeterRateTF).Add(); err != nil {
 			return fmt.Errorf("failed to install OpenFlow meter entry (meterID:%d, rate:%d) for TraceFlow packet-in rate limiting: %v", PacketInMeterIDTF, PacketInMeterRateTF, err)
 		}
+		if err := c.genPacketInMeter(PacketInMeterIDAudit, PacketInMeterRateAudit).Add(); err != nil {
+			return fmt.Errorf("failed to install OpenFlow meter entry (meterID:%d, rate:%d) for Audit packet-in rate limiting: %v", PacketInMeterIDAudit, PacketInMeterRateAudit, err)
+		}
+		if err := c.auditLoggingGroup().Add(); err != nil {
+			return fmt.Errorf("failed to install Audit logging group: %v", err)
+		}
+		if err := c.ofEntryOperations.Add(c.auditLoggingPacketInFlow(cookie.Policy)); err != nil {
+			return fmt.Errorf("failed to install Audit logging packet-in flow: %v", err)
+		}
 	}
 	return nil
 }
@@ -742,12 +853,46 @@ func (c *client) InstallExternalFlows() error {
 	return nil
 }
 
-func (c *client) InstallSNATMarkFlows(snatIP net.IP, mark uint32) error {
-	flows := c.snatMarkFlows(snatIP, mark)
+func (c *client) InstallDNSInterceptionFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	return c.addFlows(c.dnsFlowCache, dnsInterceptionFlowsKey, c.dnsInterceptionFlows(cookie.Policy))
+}
+
+func (c *client) UninstallDNSInterceptionFlows() error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	return c.deleteFlows(c.dnsFlowCache, dnsInterceptionFlowsKey)
+}
+
+func (c *client) InstallPolicyLoggingMeters(rate, burst uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	if !c.ovsMetersAreSupported {
+		return nil
+	}
+	group := c.policyLoggingGroup()
+	if err := group.Add(); err != nil {
+		return fmt.Errorf("error when installing policy logging group: %w", err)
+	}
+	c.groupCache.Store(policyLoggingGroupID, group)
+	c.policyLoggingRate, c.policyLoggingBurst = rate, burst
+	return nil
+}
+
+func (c *client) InstallL7NetworkPolicyFlows(targetOFPort, returnOFPort uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	c.l7NPTargetOFPort = targetOFPort
+	return c.addFlows(c.l7NPFlowCache, l7NPReturnFlowsKey, c.l7NPReturnFlows(returnOFPort, cookie.Policy))
+}
+
+func (c *client) InstallSNATMarkFlows(snatIP net.IP, mark uint32, metered bool) error {
+	flows := c.snatMarkFlows(snatIP, mark, metered)
 	cacheKey := fmt.Sprintf("s%x", mark)
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
-	return c.addFlows(c.snatFlowCache, cacheKey, flows)
+	return c.modifyFlows(c.snatFlowCache, cacheKey, flows)
 }
 
 func (c *client) UninstallSNATMarkFlows(mark uint32) error {
@@ -757,12 +902,12 @@ func (c *client) UninstallSNATMarkFlows(mark uint32) error {
 	return c.deleteFlows(c.snatFlowCache, cacheKey)
 }
 
-func (c *client) InstallPodSNATFlows(ofPort uint32, snatIP net.IP, snatMark uint32) error {
-	flows := []binding.Flow{c.snatRuleFlow(ofPort, snatIP, snatMark, c.nodeConfig.GatewayConfig.MAC)}
+func (c *client) InstallPodSNATFlows(ofPort uint32, snatIP net.IP, snatMark uint32, metered bool) error {
+	flows := c.snatRuleFlows(ofPort, snatIP, snatMark, metered, c.nodeConfig.GatewayConfig.MAC)
 	cacheKey := fmt.Sprintf("p%x", ofPort)
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
-	return c.addFlows(c.snatFlowCache, cacheKey, flows)
+	return c.modifyFlows(c.snatFlowCache, cacheKey, flows)
 }
 
 func (c *client) UninstallPodSNATFlows(ofPort uint32) error {
@@ -772,6 +917,197 @@ func (c *client) UninstallPodSNATFlows(ofPort uint32) error {
 	return c.deleteFlows(c.snatFlowCache, cacheKey)
 }
 
+func (c *client) InstallPodDestinationSNATFlows(ofPort uint32, egressName string, snatIP net.IP, snatMark uint32, metered bool, destinations []net.IPNet) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	c.podDestinationSNATsLock.Lock()
+	defer c.podDestinationSNATsLock.Unlock()
+	snats, exists := c.podDestinationSNATs[ofPort]
+	if !exists {
+		snats = map[string]*podDestinationSNAT{}
+		c.podDestinationSNATs[ofPort] = snats
+	}
+	snats[egressName] = &podDestinationSNAT{
+		snatIP:       snatIP,
+		snatMark:     snatMark,
+		metered:      metered,
+		destinations: destinations,
+	}
+	return c.syncPodDestinationSNATFlows(ofPort, egressName)
+}
+
+func (c *client) UninstallPodDestinationSNATFlows(ofPort uint32, egressName string) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	c.podDestinationSNATsLock.Lock()
+	defer c.podDestinationSNATsLock.Unlock()
+	if err := c.deleteFlows(c.snatFlowCache, podDestinationSNATCacheKey(ofPort, egressName)); err != nil {
+		return err
+	}
+	snats := c.podDestinationSNATs[ofPort]
+	delete(snats, egressName)
+	if len(snats) == 0 {
+		delete(c.podDestinationSNATs, ofPort)
+		return nil
+	}
+	// The other Egresses may take over the destination CIDRs of the removed one.
+	return c.syncPodDestinationSNATFlows(ofPort, "")
+}
+
+func podDestinationSNATCacheKey(ofPort uint32, egressName string) string {
+	return fmt.Sprintf("p%x/%s", ofPort, egressName)
+}
+
+// syncPodDestinationSNATFlows installs the SNAT flows of the Egresses with destinations applying to the Pod. The flows
+// of the same destination CIDR would have the same match and priority for all the Egresses, so only the Egress with
+// the smallest name installs the flow of a CIDR. The flows taken over by another Egress are removed before the flows
+// are installed, otherwise removing them would remove the flow installed for the new Egress. The flows of the other
+// Egresses than updatedEgress are only updated if the destination CIDRs they install change.
+func (c *client) syncPodDestinationSNATFlows(ofPort uint32, updatedEgress string) error {
+	snats := c.podDestinationSNATs[ofPort]
+	egressNames := make([]string, 0, len(snats))
+	for egressName := range snats {
+		egressNames = append(egressNames, egressName)
+	}
+	sort.Strings(egressNames)
+	ownedDestinations := sets.NewString()
+	egressFlows := make(map[string][]binding.Flow, len(snats))
+	for _, egressName := range egressNames {
+		snat := snats[egressName]
+		var destinations []net.IPNet
+		for _, destination := range snat.destinations {
+			// The destinations which are not in the address family of the SNAT IP are ignored.
+			if getIPProtocol(destination.IP) != getIPProtocol(snat.snatIP) || ownedDestinations.Has(destination.String()) {
+				continue
+			}
+			ownedDestinations.Insert(destination.String())
+			destinations = append(destinations, destination)
+		}
+		egressFlows[egressName] = c.snatDestinationRuleFlows(ofPort, snat.snatIP, snat.snatMark, snat.metered, destinations, c.nodeConfig.GatewayConfig.MAC)
+	}
+	// getCachedFlows returns the installed flows of the Egress which are still needed.
+	getCachedFlows := func(egressName string) (flowCache, []binding.Flow) {
+		fCacheI, exists := c.snatFlowCache.Load(podDestinationSNATCacheKey(ofPort, egressName))
+		if !exists {
+			return nil, nil
+		}
+		fCache := fCacheI.(flowCache)
+		var keptFlows []binding.Flow
+		for _, flow := range egressFlows[egressName] {
+			if _, exists := fCache[flow.MatchString()]; exists {
+				keptFlows = append(keptFlows, flow)
+			}
+		}
+		return fCache, keptFlows
+	}
+	for _, egressName := range egressNames {
+		fCache, keptFlows := getCachedFlows(egressName)
+		if len(keptFlows) == len(fCache) {
+			continue
+		}
+		if err := c.modifyFlows(c.snatFlowCache, podDestinationSNATCacheKey(ofPort, egressName), keptFlows); err != nil {
+			return err
+		}
+	}
+	for _, egressName := range egressNames {
+		cacheKey := podDestinationSNATCacheKey(ofPort, egressName)
+		flows := egressFlows[egressName]
+		if fCache, keptFlows := getCachedFlows(egressName); egressName != updatedEgress && len(fCache) > 0 && len(keptFlows) == len(flows) {
+			continue
+		}
+		if len(flows) == 0 {
+			if err := c.deleteFlows(c.snatFlowCache, cacheKey); err != nil {
+				return err
+			}
+		} else if err := c.modifyFlows(c.snatFlowCache, cacheKey, flows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *client) InstallEgressMeter(mark, rate, burst uint32) error {
+	if !c.ovsMetersAreSupported {
+		return ErrOVSMetersNotSupported
+	}
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	meter := c.genEgressMeter(mark, rate, burst)
+	if err := meter.Add(); err != nil {
+		return fmt.Errorf("failed to install OpenFlow meter entry (meterID:%d, rate:%d, burst:%d) for Egress: %v", egressMeterID(mark), rate, burst, err)
+	}
+	c.egressMeterCache.Store(mark, meter)
+	return nil
+}
+
+func (c *client) UninstallEgressMeter(mark uint32) error {
+	c.replayMutex.RLock()
+	defer c.replayMutex.RUnlock()
+	if _, exists := c.egressMeterCache.Load(mark); !exists {
+		return nil
+	}
+	if !c.bridge.DeleteMeter(binding.MeterIDType(egressMeterID(mark))) {
+		return fmt.Errorf("failed to delete OpenFlow meter entry (meterID:%d) for Egress", egressMeterID(mark))
+	}
+	c.egressMeterCache.Delete(mark)
+	return nil
+}
+
+func (c *client) GetEgressMeterDroppedPackets() (map[uint32]uint64, error) {
+	if !c.ovsMetersAreSupported {
+		return nil, ErrOVSMetersNotSupported
+	}
+	out, err := c.ovsctlClient.RunOfctlCmd("meter-stats")
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump OpenFlow meter stats: %v", err)
+	}
+	droppedPackets := map[uint32]uint64{}
+	for meterID, count := range parseMeterBandPacketCounts(string(out)) {
+		if meterID <= egressMeterIDBase {
+			continue
+		}
+		droppedPackets[meterID-egressMeterIDBase] = count
+	}
+	return droppedPackets, nil
+}
+
+// parseMeterBandPacketCounts parses the output of "ovs-ofctl meter-stats" and
+// returns the number of packets processed by the bands of each meter, i.e. the
+// number of packets dropped by the meters which have a single drop band.
+// The output looks like:
+//   OFPST_METER reply (OF1.3) (xid=0x2):
+//   meter:257 flow_count:2 packet_in_count:1024 byte_in_count:98304 duration:12.345s bands:
+//   0: packet_count:12 byte_count:1152
+func parseMeterBandPacketCounts(out string) map[uint32]uint64 {
+	counts := map[uint32]uint64{}
+	var meterID uint32
+	meterFound := false
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "meter:") {
+			id, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "meter:"), 10, 32)
+			meterFound = err == nil
+			meterID = uint32(id)
+			continue
+		}
+		if !meterFound || !strings.HasSuffix(fields[0], ":") || len(fields) < 2 {
+			continue
+		}
+		if !strings.HasPrefix(fields[1], "packet_count:") {
+			continue
+		}
+		count, err := strconv.ParseUint(strings.TrimPrefix(fields[1], "packet_count:"), 10, 64)
+		if err != nil {
+			continue
+		}
+		counts[meterID] += count
+	}
+	return counts
+}
+
 func (c *client) ReplayFlows() {
 	c.replayMutex.Lock()
 	defer c.replayMutex.Unlock()
@@ -821,9 +1157,20 @@ func (c *client) ReplayFlows() {
 		}
 		return true
 	})
+	c.egressMeterCache.Range(func(mark, value interface{}) bool {
+		meter := value.(binding.Meter)
+		meter.Reset()
+		if err := meter.Add(); err != nil {
+			klog.Errorf("Error when replaying cached meter for Egress mark %d: %v", mark, err)
+		}
+		return true
+	})
 	c.nodeFlowCache.Range(installCachedFlows)
+	c.ipPoolFlowCache.Range(installCachedFlows)
 	c.podFlowCache.Range(installCachedFlows)
 	c.serviceFlowCache.Range(installCachedFlows)
+	c.dnsFlowCache.Range(installCachedFlows)
+	c.l7NPFlowCache.Range(installCachedFlows)
 
 	c.replayPolicyFlows()
 }
@@ -1067,3 +1414,42 @@ func (c *client) SendICMPPacketOut(
 	packetOutObj := packetOutBuilder.Done()
 	return c.bridge.SendPacketOut(packetOutObj)
 }
+
+// SendUDPPacketOut generates UDP packet as a packet-out and sends it to OVS.
+func (c *client) SendUDPPacketOut(
+	srcMAC string,
+	dstMAC string,
+	srcIP string,
+	dstIP string,
+	inPort uint32,
+	outPort int32,
+	isIPv6 bool,
+	udpSrcPort uint16,
+	udpDstPort uint16,
+	udpData []byte,
+	isDNSResponse bool) error {
+	// Generate a base IP PacketOutBuilder.
+	packetOutBuilder, err := setBasePacketOutBuilder(c.bridge.BuildPacketOut(), srcMAC, dstMAC, srcIP, dstIP, inPort, outPort)
+	if err != nil {
+		return err
+	}
+	// Set protocol.
+	if isIPv6 {
+		packetOutBuilder = packetOutBuilder.SetIPProtocol(binding.ProtocolUDPv6)
+	} else {
+		packetOutBuilder = packetOutBuilder.SetIPProtocol(binding.ProtocolUDP)
+	}
+	// Set UDP header data.
+	packetOutBuilder = packetOutBuilder.SetUDPSrcPort(udpSrcPort)
+	packetOutBuilder = packetOutBuilder.SetUDPDstPort(udpDstPort)
+	packetOutBuilder = packetOutBuilder.SetUDPData(udpData)
+
+	// DNS response packet should bypass ConnTrack and the DNS interception flows.
+	if isDNSResponse {
+		name := fmt.Sprintf("%s%d", binding.NxmFieldReg, marksReg)
+		packetOutBuilder = packetOutBuilder.AddLoadAction(name, uint64(CustomReasonDNS), CustomReasonMarkRange)
+	}
+
+	packetOutObj := packetOutBuilder.Done()
+	return c.bridge.SendPacketOut(packetOutObj)
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -286,6 +287,99 @@ func Test_client_InstallTraceflowFlows(t *testing.T) {
 	}
 }
 
+func Test_client_InstallIPPoolGatewayFlows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := oftest.NewMockOFEntryOperations(ctrl)
+	ofClient := NewClient(bridgeName, bridgeMgmtAddr, ovsconfig.OVSDatapathSystem, true, false, false, false)
+	c := ofClient.(*client)
+	c.cookieAllocator = cookie.NewAllocator(0)
+	c.ofEntryOperations = m
+	c.nodeConfig = nodeConfig
+
+	getCachedFlows := func() flowCache {
+		fCacheI, ok := c.ipPoolFlowCache.Load(ipPoolGatewayFlowsKey)
+		require.True(t, ok)
+		return fCacheI.(flowCache)
+	}
+	// Only the IPv4 gateways get an ARP responder flow.
+	m.EXPECT().AddAll(gomock.Len(1)).Return(nil)
+	require.NoError(t, c.InstallIPPoolGatewayFlows([]net.IP{net.ParseIP("10.2.0.1"), net.ParseIP("fd00:10:2::1")}))
+	assert.Len(t, getCachedFlows(), 1)
+
+	m.EXPECT().BundleOps(gomock.Len(1), gomock.Len(0), gomock.Len(1)).Return(nil)
+	require.NoError(t, c.InstallIPPoolGatewayFlows([]net.IP{net.ParseIP("10.3.0.1")}))
+	assert.Len(t, getCachedFlows(), 1)
+
+	m.EXPECT().BundleOps(gomock.Len(0), gomock.Len(0), gomock.Len(1)).Return(nil)
+	require.NoError(t, c.InstallIPPoolGatewayFlows(nil))
+	assert.Empty(t, getCachedFlows())
+}
+
+// Test_client_InstallPodDestinationSNATFlows checks that a destination CIDR shared by multiple Egresses applying to the
+// same Pod is installed by a single Egress, and that it's kept when the other Egresses are removed.
+func Test_client_InstallPodDestinationSNATFlows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := oftest.NewMockOFEntryOperations(ctrl)
+	ofClient := NewClient(bridgeName, bridgeMgmtAddr, ovsconfig.OVSDatapathSystem, true, false, true, false)
+	c := ofClient.(*client)
+	c.cookieAllocator = cookie.NewAllocator(0)
+	c.ofEntryOperations = m
+	c.nodeConfig = nodeConfig
+
+	ofPort := uint32(10)
+	_, sharedCIDR, _ := net.ParseCIDR("10.10.0.0/16")
+	_, cidrA, _ := net.ParseCIDR("10.20.0.0/16")
+	_, cidrB, _ := net.ParseCIDR("10.30.0.0/16")
+	getCachedFlows := func(egressName string) flowCache {
+		fCacheI, ok := c.snatFlowCache.Load(podDestinationSNATCacheKey(ofPort, egressName))
+		if !ok {
+			return nil
+		}
+		return fCacheI.(flowCache)
+	}
+	hasDestination := func(egressName string, cidr *net.IPNet) bool {
+		for matchString := range getCachedFlows(egressName) {
+			if strings.Contains(matchString, "nw_dst="+cidr.String()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	m.EXPECT().AddAll(gomock.Len(2)).Return(nil)
+	require.NoError(t, c.InstallPodDestinationSNATFlows(ofPort, "egress-b", net.ParseIP("1.1.1.2"), 2, false, []net.IPNet{*sharedCIDR, *cidrB}))
+	assert.True(t, hasDestination("egress-b", sharedCIDR))
+
+	// egress-a takes precedence for the shared CIDR: the flow is removed from egress-b before it's installed for
+	// egress-a.
+	gomock.InOrder(
+		m.EXPECT().BundleOps(gomock.Len(0), gomock.Len(1), gomock.Len(1)).Return(nil),
+		m.EXPECT().AddAll(gomock.Len(2)).Return(nil),
+	)
+	require.NoError(t, c.InstallPodDestinationSNATFlows(ofPort, "egress-a", net.ParseIP("1.1.1.1"), 1, false, []net.IPNet{*sharedCIDR, *cidrA}))
+	assert.True(t, hasDestination("egress-a", sharedCIDR))
+	assert.True(t, hasDestination("egress-a", cidrA))
+	assert.False(t, hasDestination("egress-b", sharedCIDR))
+	assert.True(t, hasDestination("egress-b", cidrB))
+
+	// egress-b takes over the shared CIDR when egress-a is removed.
+	gomock.InOrder(
+		m.EXPECT().DeleteAll(gomock.Len(2)).Return(nil),
+		m.EXPECT().BundleOps(gomock.Len(1), gomock.Len(1), gomock.Len(0)).Return(nil),
+	)
+	require.NoError(t, c.UninstallPodDestinationSNATFlows(ofPort, "egress-a"))
+	assert.Nil(t, getCachedFlows("egress-a"))
+	assert.True(t, hasDestination("egress-b", sharedCIDR))
+	assert.True(t, hasDestination("egress-b", cidrB))
+
+	m.EXPECT().DeleteAll(gomock.Len(2)).Return(nil)
+	require.NoError(t, c.UninstallPodDestinationSNATFlows(ofPort, "egress-b"))
+	assert.Nil(t, getCachedFlows("egress-b"))
+	assert.Empty(t, c.podDestinationSNATs)
+}
+
 func Test_client_SendTraceflowPacket(t *testing.T) {
 	type args struct {
 		dataplaneTag uint8
@@ -510,3 +604,18 @@ func prepareSetBasePacketOutBuilder(ctrl *gomock.Controller, success bool) *clie
 	}
 	return c
 }
+
+func Test_parseMeterBandPacketCounts(t *testing.T) {
+	out := `OFPST_METER reply (OF1.3) (xid=0x2):
+meter:1 flow_count:4 packet_in_count:10 byte_in_count:980 duration:120.521s bands:
+0: packet_count:0 byte_count:0
+
+meter:257 flow_count:2 packet_in_count:2048 byte_in_count:3000000 duration:60.001s bands:
+0: packet_count:12 byte_count:18000
+
+meter:260 flow_count:1 packet_in_count:0 byte_in_count:0 duration:1.000s bands:
+0: packet_count:0 byte_count:0
+`
+	assert.Equal(t, map[uint32]uint64{1: 0, 257: 12, 260: 0}, parseMeterBandPacketCounts(out))
+	assert.Empty(t, parseMeterBandPacketCounts(""))
+}
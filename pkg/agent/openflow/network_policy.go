@@ -19,7 +19,9 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"antrea.io/antrea/pkg/agent/types"
@@ -46,14 +48,27 @@ var (
 	MatchUDPv6DstPort  = types.NewMatchKey(binding.ProtocolUDPv6, types.L4PortAddr, "tp_dst")
 	MatchSCTPDstPort   = types.NewMatchKey(binding.ProtocolSCTP, types.L4PortAddr, "tp_dst")
 	MatchSCTPv6DstPort = types.NewMatchKey(binding.ProtocolSCTPv6, types.L4PortAddr, "tp_dst")
+	MatchICMP          = types.NewMatchKey(binding.ProtocolICMP, types.ICMPAddr, "icmp_type,icmp_code")
+	MatchICMPv6        = types.NewMatchKey(binding.ProtocolICMPv6, types.ICMPAddr, "icmp_type,icmp_code")
 	Unsupported        = types.NewMatchKey(binding.ProtocolIP, types.UnSupported, "unknown")
 
 	// metricFlowIdentifier is used to identify metric flows in metric table.
 	// There could be other flows like default flow and Traceflow flows in the table. Only metric flows are supposed to
 	// have normal priority.
 	metricFlowIdentifier = fmt.Sprintf("priority=%d,", priorityNormal)
+	// actionFlowIdentifier is used to identify the conjunction action flows in rule tables, among which the ones of
+	// the rules in Audit mode and of the rules with the Pass action are used to collect the metrics of these rules.
+	actionFlowIdentifier = "conj_id="
 )
 
+// actionToDisposition maps the actions of Antrea-native policy rules to the dispositions loaded into marksReg.
+var actionToDisposition = map[crdv1alpha1.RuleAction]uint32{
+	crdv1alpha1.RuleActionAllow:  DispositionAllow,
+	crdv1alpha1.RuleActionDrop:   DispositionDrop,
+	crdv1alpha1.RuleActionReject: DispositionRej,
+	crdv1alpha1.RuleActionPass:   DispositionPass,
+}
+
 // IP address calculated from Pod's address.
 type IPAddress net.IP
 
@@ -221,6 +236,16 @@ func (m *conjunctiveMatch) generateGlobalMapKey() string {
 			// To normalize the key, set full mask while a single port is provided.
 			valueStr = fmt.Sprintf("%d/65535", bitRange.Value)
 		}
+	case types.ICMPMatch:
+		// Use "*" for the type or code which is not specified, as it matches all values.
+		icmpType, icmpCode := "*", "*"
+		if v.ICMPType != nil {
+			icmpType = strconv.Itoa(int(*v.ICMPType))
+		}
+		if v.ICMPCode != nil {
+			icmpCode = strconv.Itoa(int(*v.ICMPCode))
+		}
+		valueStr = fmt.Sprintf("%s,%s", icmpType, icmpCode)
 	default:
 		// The default cases include the matchValue is an ofport Number.
 		valueStr = fmt.Sprintf("%s", m.matchValue)
@@ -502,9 +527,107 @@ type policyRuleConjunction struct {
 	serviceClause *clause
 	actionFlows   []binding.Flow
 	metricFlows   []binding.Flow
+	// l7Flows redirect the packets of the connections allowed by the rule to the L7 engine. They are only
+	// installed for the Antrea-native policy rules with L7 protocols.
+	l7Flows []binding.Flow
+	// packetInFlows send the copies of the packets logged by the rule to the controller with loggingMeter. They are
+	// only installed for the Allow and Pass rules with logging enabled, when the logged packets are rate-limited by
+	// the meters of the rules.
+	packetInFlows []binding.Flow
+	// loggingMeter limits the rate of the packets logged by the rule. It is nil if the rule doesn't have a meter.
+	loggingMeter binding.Meter
+	// metricsFromActionFlow indicates whether the metrics of the rule are collected from its action flow instead of
+	// the metric flows, which is the case for the rules in Audit mode and the rules with the Pass action.
+	metricsFromActionFlow bool
 	// NetworkPolicy reference information for debugging usage.
 	npRef       *v1beta2.NetworkPolicyReference
 	ruleTableID binding.TableIDType
+	// tierPriority is the priority of the Tier of the rule. It is nil for the K8s NetworkPolicy rules and the rules
+	// of the baseline Tier, which are not skipped by the Pass rules.
+	tierPriority *int32
+	// isPassRule indicates whether the rule has the Pass action, in which case it holds a reference to the bit of
+	// tierPassReg allocated to its Tier.
+	isPassRule bool
+	// tierPassBit is the bit of tierPassReg matched by the action flows of the rule. It is nil if the Tier of the
+	// rule has no rule with the Pass action.
+	tierPassBit *uint32
+	// calculateActionFlows calculates the action flows of the rule at the provided priority, matching the provided
+	// bit of tierPassReg. It is used to update the action flows when a bit is allocated to or released from the Tier
+	// of the rule.
+	calculateActionFlows func(priority *uint16, tierPassBit *uint32) []binding.Flow
+}
+
+// tierPassBitAllocator allocates the bits of tierPassReg to the Tiers with rules of the Pass action, among the Tiers
+// of the Antrea-native policy rules installed in the Antrea-native policy rule tables, i.e. all the Tiers except the
+// baseline Tier. The action flows of the rules of a Tier with a bit match the bit being unset. When a rule with the
+// Pass action is matched, its Tier's bit is set and the packet is resubmitted to the rule table, so that the
+// remaining rules of the Tier are skipped and the packet is evaluated against the rules of the next Tiers. The rules
+// of the previous Tiers did not match the packet in the first place, so they don't match it either after the
+// resubmission. The ingress and egress rules of a Tier share a bit, as tierPassReg is reset when the packet enters
+// the ingress rule tables.
+type tierPassBitAllocator struct {
+	sync.Mutex
+	// bits is a map from the priority of a Tier to the bit allocated to it.
+	bits map[int32]uint32
+	// refs is a map from the priority of a Tier to the number of its rules with the Pass action.
+	refs map[int32]int
+}
+
+func newTierPassBitAllocator() *tierPassBitAllocator {
+	return &tierPassBitAllocator{
+		bits: map[int32]uint32{},
+		refs: map[int32]int{},
+	}
+}
+
+// get returns the bit allocated to the Tier with the provided priority, or nil if the Tier has none.
+func (a *tierPassBitAllocator) get(tierPriority int32) *uint32 {
+	a.Lock()
+	defer a.Unlock()
+	if bit, ok := a.bits[tierPriority]; ok {
+		return &bit
+	}
+	return nil
+}
+
+// allocate adds a reference to the bit of the Tier with the provided priority for a rule with the Pass action,
+// allocating one if the Tier has none yet.
+func (a *tierPassBitAllocator) allocate(tierPriority int32) (uint32, error) {
+	a.Lock()
+	defer a.Unlock()
+	if bit, ok := a.bits[tierPriority]; ok {
+		a.refs[tierPriority]++
+		return bit, nil
+	}
+	used := make(map[uint32]bool, len(a.bits))
+	for _, bit := range a.bits {
+		used[bit] = true
+	}
+	for bit := uint32(0); bit < 32; bit++ {
+		if !used[bit] {
+			a.bits[tierPriority] = bit
+			a.refs[tierPriority] = 1
+			return bit, nil
+		}
+	}
+	return 0, fmt.Errorf("no bit available in %s for Tier with priority %d", tierPassReg.reg(), tierPriority)
+}
+
+// release releases a reference to the bit of the Tier with the provided priority, which is freed when no rule with
+// the Pass action uses it anymore. It returns whether the bit is freed.
+func (a *tierPassBitAllocator) release(tierPriority int32) bool {
+	a.Lock()
+	defer a.Unlock()
+	if _, ok := a.refs[tierPriority]; !ok {
+		return false
+	}
+	a.refs[tierPriority]--
+	if a.refs[tierPriority] > 0 {
+		return false
+	}
+	delete(a.refs, tierPriority)
+	delete(a.bits, tierPriority)
+	return true
 }
 
 // clause groups conjunctive match flows. Matches in a clause represent source addresses(for fromClause), or destination
@@ -619,6 +742,14 @@ func getServiceMatchType(protocol *v1beta2.Protocol, ipv4Enabled, ipv6Enabled bo
 		if ipv6Enabled {
 			matchKeys = append(matchKeys, MatchSCTPv6DstPort)
 		}
+	case v1beta2.ProtocolICMP:
+		if ipv4Enabled {
+			matchKeys = append(matchKeys, MatchICMP)
+		}
+	case v1beta2.ProtocolICMPv6:
+		if ipv6Enabled {
+			matchKeys = append(matchKeys, MatchICMPv6)
+		}
 	default:
 		matchKeys = []*types.MatchKey{MatchTCPDstPort}
 	}
@@ -627,8 +758,20 @@ func getServiceMatchType(protocol *v1beta2.Protocol, ipv4Enabled, ipv6Enabled bo
 
 func (c *clause) generateServicePortConjMatches(service v1beta2.Service, priority *uint16, ipv4Enabled, ipv6Enabled bool) []*conjunctiveMatch {
 	matchKeys := getServiceMatchType(service.Protocol, ipv4Enabled, ipv6Enabled)
-	ovsBitRanges := c.serviceToBitRanges(service)
 	var matches []*conjunctiveMatch
+	if *service.Protocol == v1beta2.ProtocolICMP || *service.Protocol == v1beta2.ProtocolICMPv6 {
+		for _, matchKey := range matchKeys {
+			matches = append(matches,
+				&conjunctiveMatch{
+					tableID:    c.ruleTable.GetID(),
+					matchKey:   matchKey,
+					matchValue: types.ICMPMatch{ICMPType: service.ICMPType, ICMPCode: service.ICMPCode},
+					priority:   priority,
+				})
+		}
+		return matches
+	}
+	ovsBitRanges := c.serviceToBitRanges(service)
 	for _, matchKey := range matchKeys {
 		for _, ovsBitRange := range ovsBitRanges {
 			matches = append(matches,
@@ -822,19 +965,50 @@ func (c *client) InstallPolicyRuleFlows(rule *types.PolicyRule) error {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
 
-	conj := c.calculateActionFlowChangesForRule(rule)
-
+	// conjMatchFlowLock also protects the bits of tierPassReg, so that the action flows of the rules of a Tier always
+	// match the current bit of the Tier.
 	c.conjMatchFlowLock.Lock()
 	defer c.conjMatchFlowLock.Unlock()
+	conj, err := c.calculateActionFlowChangesForRule(rule)
+	if err != nil {
+		return err
+	}
 	ctxChanges := c.calculateMatchFlowChangesForRule(conj, rule, false)
 
+	// The other rules of the Tier must match the bit of the Tier before the Pass rule can set it.
+	if conj.isPassRule {
+		if err := c.updateTierPassBitOfRules(*conj.tierPriority); err != nil {
+			c.releaseTierPassBit(conj)
+			return err
+		}
+	}
+	if err := c.addPolicyLoggingMeter(conj); err != nil {
+		c.releaseTierPassBit(conj)
+		return err
+	}
 	if err := c.ofEntryOperations.AddAll(conj.metricFlows); err != nil {
+		c.releaseTierPassBit(conj)
+		c.deletePolicyLoggingMeter(conj)
+		return err
+	}
+	if err := c.ofEntryOperations.AddAll(conj.packetInFlows); err != nil {
+		c.releaseTierPassBit(conj)
+		c.deletePolicyLoggingMeter(conj)
 		return err
 	}
 	if err := c.ofEntryOperations.AddAll(conj.actionFlows); err != nil {
+		c.releaseTierPassBit(conj)
+		c.deletePolicyLoggingMeter(conj)
+		return err
+	}
+	if err := c.ofEntryOperations.AddAll(conj.l7Flows); err != nil {
+		c.releaseTierPassBit(conj)
+		c.deletePolicyLoggingMeter(conj)
 		return err
 	}
 	if err := c.applyConjunctiveMatchFlows(ctxChanges); err != nil {
+		c.releaseTierPassBit(conj)
+		c.deletePolicyLoggingMeter(conj)
 		return err
 	}
 	// Add the policyRuleConjunction into policyCache
@@ -843,13 +1017,15 @@ func (c *client) InstallPolicyRuleFlows(rule *types.PolicyRule) error {
 }
 
 // calculateActionFlowChangesForRule calculates and updates the actionFlows for the conjunction corresponded to the ofPolicyRule.
-func (c *client) calculateActionFlowChangesForRule(rule *types.PolicyRule) *policyRuleConjunction {
+// For the rules of the Antrea-native policy rule tables with the Pass action, it allocates the bit of tierPassReg of the
+// rule's Tier.
+func (c *client) calculateActionFlowChangesForRule(rule *types.PolicyRule) (*policyRuleConjunction, error) {
 	ruleOfID := rule.FlowID
 	// Check if the policyRuleConjunction is added into cache or not. If yes, return nil.
 	conj := c.getPolicyRuleConjunction(ruleOfID)
 	if conj != nil {
 		klog.V(2).Infof("PolicyRuleConjunction %d is already added in cache", ruleOfID)
-		return nil
+		return nil, nil
 	}
 	conj = &policyRuleConjunction{
 		id:    ruleOfID,
@@ -864,23 +1040,148 @@ func (c *client) calculateActionFlowChangesForRule(rule *types.PolicyRule) *poli
 	// to drop all packets.  If the number is 1, no conjunctive match flows or conjunction action flows are installed,
 	// but the default drop flow is installed.
 	if nClause > 1 {
+		// The action flows of the rules of the Antrea-native policy rule tables are skipped once a rule of the same
+		// Tier with the Pass action has been matched. Only the Tiers with rules of the Pass action have a bit.
+		if rule.IsAntreaNetworkPolicyRule() && rule.TierPriority != nil && isAntreaPolicyMultiTierTable(rule.TableID) {
+			conj.tierPriority = rule.TierPriority
+			if *rule.Action == crdv1alpha1.RuleActionPass {
+				bit, err := c.tierPassBits.allocate(*rule.TierPriority)
+				if err != nil {
+					return nil, err
+				}
+				conj.isPassRule = true
+				conj.tierPassBit = &bit
+			} else {
+				conj.tierPassBit = c.tierPassBits.get(*rule.TierPriority)
+			}
+		}
 		// Install action flows.
-		var actionFlows []binding.Flow
+		tableID := ruleTable.GetID()
+		enableLogging := rule.EnableLogging
 		var metricFlows []binding.Flow
-		if rule.IsAntreaNetworkPolicyRule() && *rule.Action == crdv1alpha1.RuleActionDrop {
+		if rule.IsAntreaNetworkPolicyRule() && rule.IsAuditRule() {
+			// No metric flow is needed as the metrics of the rule are collected from its action flow.
+			disposition := actionToDisposition[*rule.Action]
+			conj.calculateActionFlows = func(priority *uint16, tierPassBit *uint32) []binding.Flow {
+				return []binding.Flow{c.conjunctionActionAuditFlow(ruleOfID, tableID, priority, disposition, tierPassBit)}
+			}
+			conj.metricsFromActionFlow = true
+		} else if rule.IsAntreaNetworkPolicyRule() && *rule.Action == crdv1alpha1.RuleActionPass {
+			// No metric flow is needed as the metrics of the rule are collected from its action flow.
+			conj.calculateActionFlows = func(priority *uint16, tierPassBit *uint32) []binding.Flow {
+				return []binding.Flow{c.conjunctionActionPassFlow(ruleOfID, tableID, priority, enableLogging, tierPassBit)}
+			}
+			conj.metricsFromActionFlow = true
+		} else if rule.IsAntreaNetworkPolicyRule() && *rule.Action == crdv1alpha1.RuleActionDrop {
 			metricFlows = append(metricFlows, c.denyRuleMetricFlow(ruleOfID, isIngress))
-			actionFlows = append(actionFlows, c.conjunctionActionDenyFlow(ruleOfID, ruleTable.GetID(), rule.Priority, DispositionDrop, rule.EnableLogging))
+			conj.calculateActionFlows = func(priority *uint16, tierPassBit *uint32) []binding.Flow {
+				return []binding.Flow{c.conjunctionActionDenyFlow(ruleOfID, tableID, priority, DispositionDrop, enableLogging, tierPassBit)}
+			}
 		} else if rule.IsAntreaNetworkPolicyRule() && *rule.Action == crdv1alpha1.RuleActionReject {
 			metricFlows = append(metricFlows, c.denyRuleMetricFlow(ruleOfID, isIngress))
-			actionFlows = append(actionFlows, c.conjunctionActionDenyFlow(ruleOfID, ruleTable.GetID(), rule.Priority, DispositionRej, rule.EnableLogging))
+			conj.calculateActionFlows = func(priority *uint16, tierPassBit *uint32) []binding.Flow {
+				return []binding.Flow{c.conjunctionActionDenyFlow(ruleOfID, tableID, priority, DispositionRej, enableLogging, tierPassBit)}
+			}
 		} else {
 			metricFlows = append(metricFlows, c.allowRulesMetricFlows(ruleOfID, isIngress)...)
-			actionFlows = append(actionFlows, c.conjunctionActionFlow(ruleOfID, ruleTable.GetID(), dropTable.GetNext(), rule.Priority, rule.EnableLogging)...)
+			nextTable := dropTable.GetNext()
+			conj.calculateActionFlows = func(priority *uint16, tierPassBit *uint32) []binding.Flow {
+				return c.conjunctionActionFlow(ruleOfID, tableID, nextTable, priority, enableLogging, tierPassBit)
+			}
+			if rule.L7RuleVlanID != nil {
+				conj.l7Flows = c.l7NPRedirectFlows(ruleOfID, isIngress, *rule.L7RuleVlanID)
+			}
+		}
+		// The packets logged by a rule in Audit mode are not rate-limited by a meter of the rule.
+		if rule.EnableLogging && c.policyLoggingMetered() && !(rule.IsAntreaNetworkPolicyRule() && rule.IsAuditRule()) {
+			conj.loggingMeter = c.policyLoggingMeter(ruleOfID)
+			// The packets denied by the rule are metered by its action flow directly.
+			if rule.Action == nil || (*rule.Action != crdv1alpha1.RuleActionDrop && *rule.Action != crdv1alpha1.RuleActionReject) {
+				conj.packetInFlows = []binding.Flow{c.policyLoggingPacketInFlow(ruleOfID, ruleTable.GetID())}
+			}
 		}
-		conj.actionFlows = actionFlows
+		conj.actionFlows = conj.calculateActionFlows(rule.Priority, conj.tierPassBit)
 		conj.metricFlows = metricFlows
 	}
-	return conj
+	return conj, nil
+}
+
+// addPolicyLoggingMeter installs the meter which limits the rate of the packets logged by the policyRuleConjunction,
+// if any.
+func (c *client) addPolicyLoggingMeter(conj *policyRuleConjunction) error {
+	if conj.loggingMeter == nil {
+		return nil
+	}
+	if err := conj.loggingMeter.Add(); err != nil {
+		return fmt.Errorf("failed to install OpenFlow meter entry (meterID:%d) for the logging of rule %d: %v", policyLoggingMeterID(conj.id), conj.id, err)
+	}
+	return nil
+}
+
+// deletePolicyLoggingMeter removes the meter installed by addPolicyLoggingMeter, if any.
+func (c *client) deletePolicyLoggingMeter(conj *policyRuleConjunction) {
+	if conj.loggingMeter == nil {
+		return
+	}
+	if !c.bridge.DeleteMeter(binding.MeterIDType(policyLoggingMeterID(conj.id))) {
+		klog.Errorf("Failed to delete OpenFlow meter entry (meterID:%d) for the logging of rule %d", policyLoggingMeterID(conj.id), conj.id)
+	}
+}
+
+// isAntreaPolicyMultiTierTable returns whether the table is one of the Antrea-native policy rule tables in which the
+// rules of multiple Tiers are installed.
+func isAntreaPolicyMultiTierTable(tableID binding.TableIDType) bool {
+	for _, table := range GetAntreaPolicyMultiTierTables() {
+		if table == tableID {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseTierPassBit releases the reference of the policyRuleConjunction to the bit of tierPassReg of its Tier, if any.
+// When the bit is freed, the other rules of the Tier are updated not to match it anymore.
+func (c *client) releaseTierPassBit(conj *policyRuleConjunction) {
+	if !conj.isPassRule || !c.tierPassBits.release(*conj.tierPriority) {
+		return
+	}
+	if err := c.updateTierPassBitOfRules(*conj.tierPriority); err != nil {
+		klog.Errorf("Error when updating the rules of Tier with priority %d after releasing its bit of %s: %v", *conj.tierPriority, tierPassReg.reg(), err)
+	}
+}
+
+// updateTierPassBitOfRules updates the action flows of the rules of the Tier with the provided priority in the
+// policyCache to match the current bit of tierPassReg of the Tier, after a bit is allocated to or released from it.
+func (c *client) updateTierPassBitOfRules(tierPriority int32) error {
+	tierPassBit := c.tierPassBits.get(tierPriority)
+	var addFlows, delFlows []binding.Flow
+	var updatedConjunctions []*policyRuleConjunction
+	for _, conjObj := range c.policyCache.List() {
+		conj := conjObj.(*policyRuleConjunction)
+		if conj.tierPriority == nil || *conj.tierPriority != tierPriority || len(conj.actionFlows) == 0 {
+			continue
+		}
+		if (conj.tierPassBit == nil && tierPassBit == nil) || (conj.tierPassBit != nil && tierPassBit != nil && *conj.tierPassBit == *tierPassBit) {
+			continue
+		}
+		priority := conj.actionFlows[0].FlowPriority()
+		newActionFlows := conj.calculateActionFlows(&priority, tierPassBit)
+		addFlows = append(addFlows, newActionFlows...)
+		delFlows = append(delFlows, conj.actionFlows...)
+		updatedConj := c.updateConjunctionActionFlows(conj, flowUpdates{newActionFlows, priority})
+		updatedConj.tierPassBit = tierPassBit
+		updatedConjunctions = append(updatedConjunctions, updatedConj)
+	}
+	if len(updatedConjunctions) == 0 {
+		return nil
+	}
+	if err := c.bridge.AddFlowsInBundle(addFlows, nil, delFlows); err != nil {
+		return err
+	}
+	for _, conj := range updatedConjunctions {
+		c.policyCache.Update(conj)
+	}
+	return nil
 }
 
 // calculateMatchFlowChangesForRule calculates the contextChanges for the policyRule, and updates the context status in case of batch install.
@@ -904,20 +1205,69 @@ func (c *client) BatchInstallPolicyRuleFlows(ofPolicyRules []*types.PolicyRule)
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
 
+	c.conjMatchFlowLock.Lock()
+	defer c.conjMatchFlowLock.Unlock()
+
 	var allCtxChanges []*conjMatchFlowContextChange
 	var allFlows []binding.Flow
 	var updatedConjunctions []*policyRuleConjunction
+	passTierPriorities := sets.NewInt32()
 
+	// The rules with the Pass action are calculated first, so that the action flows of the other rules of their
+	// Tiers match the bits allocated to the Tiers.
+	sortedRules := make([]*types.PolicyRule, 0, len(ofPolicyRules))
 	for _, rule := range ofPolicyRules {
-		conj := c.calculateActionFlowChangesForRule(rule)
+		if rule.Action != nil && *rule.Action == crdv1alpha1.RuleActionPass {
+			sortedRules = append(sortedRules, rule)
+		}
+	}
+	for _, rule := range ofPolicyRules {
+		if rule.Action == nil || *rule.Action != crdv1alpha1.RuleActionPass {
+			sortedRules = append(sortedRules, rule)
+		}
+	}
+	for _, rule := range sortedRules {
+		conj, err := c.calculateActionFlowChangesForRule(rule)
+		if err == nil {
+			err = c.addPolicyLoggingMeter(conj)
+			if err != nil {
+				c.releaseTierPassBit(conj)
+			}
+		}
+		if err != nil {
+			for _, conj := range updatedConjunctions {
+				c.releaseTierPassBit(conj)
+				c.deletePolicyLoggingMeter(conj)
+			}
+			return err
+		}
 		ctxChanges := c.calculateMatchFlowChangesForRule(conj, rule, true)
+		allFlows = append(allFlows, conj.packetInFlows...)
 		allFlows = append(allFlows, conj.actionFlows...)
 		allFlows = append(allFlows, conj.metricFlows...)
+		allFlows = append(allFlows, conj.l7Flows...)
 		allCtxChanges = append(allCtxChanges, ctxChanges...)
 		updatedConjunctions = append(updatedConjunctions, conj)
+		if conj.isPassRule {
+			passTierPriorities.Insert(*conj.tierPriority)
+		}
+	}
+	releaseAll := func() {
+		for _, conj := range updatedConjunctions {
+			c.releaseTierPassBit(conj)
+			c.deletePolicyLoggingMeter(conj)
+		}
+	}
+	// The rules installed before must also match the bits allocated to their Tiers.
+	for _, tierPriority := range passTierPriorities.List() {
+		if err := c.updateTierPassBitOfRules(tierPriority); err != nil {
+			releaseAll()
+			return err
+		}
 	}
 	// Send the changed Openflow entries to the OVS bridge.
 	if err := c.sendConjunctiveFlows(allCtxChanges, allFlows); err != nil {
+		releaseAll()
 		return err
 	}
 	// Update conjMatchFlowContexts as the expected status.
@@ -1130,6 +1480,9 @@ func (c *client) UninstallPolicyRuleFlows(ruleID uint32) ([]string, error) {
 	c.replayMutex.RLock()
 	defer c.replayMutex.RUnlock()
 
+	// The action flows of the policyRuleConjunction can be updated by the rules of its Tier with the Pass action.
+	c.conjMatchFlowLock.Lock()
+	defer c.conjMatchFlowLock.Unlock()
 	conj := c.getPolicyRuleConjunction(ruleID)
 	if conj == nil {
 		klog.V(2).Infof("policyRuleConjunction with ID %d not found", ruleID)
@@ -1143,9 +1496,14 @@ func (c *client) UninstallPolicyRuleFlows(ruleID uint32) ([]string, error) {
 	if err := c.ofEntryOperations.DeleteAll(conj.metricFlows); err != nil {
 		return nil, err
 	}
+	if err := c.ofEntryOperations.DeleteAll(conj.l7Flows); err != nil {
+		return nil, err
+	}
+	if err := c.ofEntryOperations.DeleteAll(conj.packetInFlows); err != nil {
+		return nil, err
+	}
+	c.deletePolicyLoggingMeter(conj)
 
-	c.conjMatchFlowLock.Lock()
-	defer c.conjMatchFlowLock.Unlock()
 	// Get the conjMatchFlowContext changes.
 	ctxChanges := conj.calculateChangesForRuleDeletion()
 	// Send the changed OpenFlow entries to the OVS bridge and update the conjMatchFlowContext.
@@ -1154,6 +1512,7 @@ func (c *client) UninstallPolicyRuleFlows(ruleID uint32) ([]string, error) {
 	}
 
 	c.policyCache.Delete(conj)
+	c.releaseTierPassBit(conj)
 	return staleOFPriorities, nil
 }
 
@@ -1200,10 +1559,31 @@ func (c *client) replayPolicyFlows() {
 			flows = append(flows, flow)
 		}
 	}
+	addL7Flows := func(conj *policyRuleConjunction) {
+		for _, flow := range conj.l7Flows {
+			flow.Reset()
+			flows = append(flows, flow)
+		}
+	}
+	// The meters must be installed before the flows using them.
+	addPacketInFlows := func(conj *policyRuleConjunction) {
+		if conj.loggingMeter != nil {
+			conj.loggingMeter.Reset()
+			if err := conj.loggingMeter.Add(); err != nil {
+				klog.Errorf("Error when replaying meter for the logging of rule %d: %v", conj.id, err)
+			}
+		}
+		for _, flow := range conj.packetInFlows {
+			flow.Reset()
+			flows = append(flows, flow)
+		}
+	}
 
 	for _, conj := range c.policyCache.List() {
+		addPacketInFlows(conj.(*policyRuleConjunction))
 		addActionFlows(conj.(*policyRuleConjunction))
 		addMetricFlows(conj.(*policyRuleConjunction))
+		addL7Flows(conj.(*policyRuleConjunction))
 	}
 
 	addMatchFlows := func(ctx *conjMatchFlowContext) {
@@ -1361,8 +1741,17 @@ func (c *client) updateConjunctionActionFlows(conj *policyRuleConjunction, updat
 		toClause:      conj.toClause,
 		serviceClause: conj.serviceClause,
 		actionFlows:   newActionFlows,
+		l7Flows:       conj.l7Flows,
+		packetInFlows: conj.packetInFlows,
+		loggingMeter:  conj.loggingMeter,
 		npRef:         conj.npRef,
 		ruleTableID:   conj.ruleTableID,
+		tierPriority:  conj.tierPriority,
+		isPassRule:    conj.isPassRule,
+		tierPassBit:   conj.tierPassBit,
+
+		metricsFromActionFlow: conj.metricsFromActionFlow,
+		calculateActionFlows:  conj.calculateActionFlows,
 	}
 	return newConj
 }
@@ -1426,6 +1815,8 @@ func (c *client) calculateFlowUpdates(updates map[uint16]uint16, table binding.T
 // ReassignFlowPriorities takes a list of priority updates, and update the actionFlows to replace
 // the old priority with the desired one, for each priority update.
 func (c *client) ReassignFlowPriorities(updates map[uint16]uint16, table binding.TableIDType) error {
+	c.conjMatchFlowLock.Lock()
+	defer c.conjMatchFlowLock.Unlock()
 	addFlows, delFlows, conjFlowUpdates := c.calculateFlowUpdates(updates, table)
 	add, update, del := c.processFlowUpdates(addFlows, delFlows)
 	// Commit the flows updates calculated.
@@ -1455,6 +1846,21 @@ func parseDropFlow(flowMap map[string]string) (uint32, types.RuleMetric) {
 	return uint32(id), m
 }
 
+func parseActionFlow(flow string) (uint32, types.RuleMetric) {
+	// example action flow format of a rule in Audit mode:
+	// table=45, n_packets=3, n_bytes=222, priority=14900,conj_id=2,ip,reg0=0/0x10000000 actions=load:0x2->NXM_NX_REG5[],...
+	flowMap := parseFlowToMap(flow)
+	m := types.RuleMetric{}
+	pkts, _ := strconv.ParseUint(flowMap["n_packets"], 10, 64)
+	m.Packets = pkts
+	m.Sessions = pkts
+	bytes, _ := strconv.ParseUint(flowMap["n_bytes"], 10, 64)
+	m.Bytes = bytes
+	conjID := strings.Fields(flowMap["conj_id"])[0]
+	id, _ := strconv.ParseUint(conjID, 10, 32)
+	return uint32(id), m
+}
+
 func parseAllowFlow(flowMap map[string]string) (uint32, types.RuleMetric) {
 	m := types.RuleMetric{}
 	pkts, _ := strconv.ParseUint(flowMap["n_packets"], 10, 64)
@@ -1528,5 +1934,38 @@ func (c *client) NetworkPolicyMetrics() map[uint32]*types.RuleMetric {
 	// flows to get the correct number of total packets.
 	collectMetricsFromFlows(egressFlows)
 	collectMetricsFromFlows(ingressFlows)
+	c.collectActionFlowRuleMetrics(result)
 	return result
 }
+
+// collectActionFlowRuleMetrics collects the metrics of the rules in Audit mode and of the rules with the Pass action.
+// The traffic matching these rules doesn't hit any metric flow of them, so the metrics are collected from their action
+// flows in the rule tables.
+func (c *client) collectActionFlowRuleMetrics(result map[uint32]*types.RuleMetric) {
+	rules := map[uint32]bool{}
+	tables := map[binding.TableIDType]bool{}
+	for _, obj := range c.policyCache.List() {
+		conj := obj.(*policyRuleConjunction)
+		if conj.metricsFromActionFlow {
+			rules[conj.id] = true
+			tables[conj.ruleTableID] = true
+		}
+	}
+	for tableID := range tables {
+		flows, _ := c.ovsctlClient.DumpTableFlows(uint8(tableID))
+		for _, flow := range flows {
+			if !strings.Contains(flow, actionFlowIdentifier) {
+				continue
+			}
+			ruleID, metric := parseActionFlow(flow)
+			if !rules[ruleID] {
+				continue
+			}
+			if accMetric, ok := result[ruleID]; ok {
+				accMetric.Merge(&metric)
+			} else {
+				result[ruleID] = &metric
+			}
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/contiv/ofnet/ofctrl"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -550,6 +551,43 @@ func TestInstallPolicyRuleFlowsInDualStackCluster(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestGenerateICMPServiceConjMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	conj := &policyRuleConjunction{id: uint32(104)}
+	clause := conj.newClause(3, 3, outTable, outDropTable)
+	icmpProtocol := v1beta2.ProtocolICMP
+	icmpv6Protocol := v1beta2.ProtocolICMPv6
+	icmpType := int32(8)
+	icmpCode := int32(0)
+
+	matches := clause.generateServicePortConjMatches(v1beta2.Service{Protocol: &icmpProtocol, ICMPType: &icmpType, ICMPCode: &icmpCode}, nil, true, true)
+	require.Equal(t, 1, len(matches))
+	assert.Equal(t, MatchICMP, matches[0].matchKey)
+	assert.Equal(t, types.ICMPMatch{ICMPType: &icmpType, ICMPCode: &icmpCode}, matches[0].matchValue)
+	assert.Equal(t, fmt.Sprintf("table:%d,priority:%d,type:%v,value:8,0", EgressRuleTable, priorityNormal, MatchICMP), matches[0].generateGlobalMapKey())
+
+	matches = clause.generateServicePortConjMatches(v1beta2.Service{Protocol: &icmpv6Protocol}, nil, true, true)
+	require.Equal(t, 1, len(matches))
+	assert.Equal(t, MatchICMPv6, matches[0].matchKey)
+	assert.Equal(t, fmt.Sprintf("table:%d,priority:%d,type:%v,value:*,*", EgressRuleTable, priorityNormal, MatchICMPv6), matches[0].generateGlobalMapKey())
+
+	// ICMPv6 is not matched if IPv6 is not enabled.
+	matches = clause.generateServicePortConjMatches(v1beta2.Service{Protocol: &icmpv6Protocol}, nil, true, false)
+	assert.Empty(t, matches)
+
+	fb := mocks.NewMockFlowBuilder(ctrl)
+	fb.EXPECT().MatchProtocol(binding.ProtocolICMP).Return(fb)
+	fb.EXPECT().MatchICMPType(uint8(8)).Return(fb)
+	fb.EXPECT().MatchICMPCode(uint8(0)).Return(fb)
+	c.addFlowMatch(fb, MatchICMP, types.ICMPMatch{ICMPType: &icmpType, ICMPCode: &icmpCode})
+	fb.EXPECT().MatchProtocol(binding.ProtocolICMPv6).Return(fb)
+	fb.EXPECT().MatchICMPv6Type(uint8(8)).Return(fb)
+	c.addFlowMatch(fb, MatchICMPv6, types.ICMPMatch{ICMPType: &icmpType})
+}
+
 func getChangedFlowCount(flows []*flowChange) int {
 	var count int
 	for _, changedFlow := range flows {
@@ -755,6 +793,7 @@ func prepareClient(ctrl *gomock.Controller) *client {
 		},
 		policyCache:              policyCache,
 		globalConjMatchFlowCache: map[string]*conjMatchFlowContext{},
+		tierPassBits:             newTierPassBitAllocator(),
 		bridge:                   bridge,
 		ovsDatapathType:          ovsconfig.OVSDatapathNetdev,
 	}
@@ -901,3 +940,421 @@ func TestNetworkPolicyMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestAuditRuleMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	c.policyCache.Add(&policyRuleConjunction{id: 7, metricsFromActionFlow: true, ruleTableID: AntreaPolicyEgressRuleTable})
+	c.policyCache.Add(&policyRuleConjunction{id: 8, ruleTableID: AntreaPolicyEgressRuleTable})
+	mockOVSClient := ovsctltest.NewMockOVSCtlClient(ctrl)
+	c.ovsctlClient = mockOVSClient
+	ruleTableFlows := []string{
+		"table=45, n_packets=3, n_bytes=222, priority=14900,conj_id=7,ip,reg0=0/0x10000000 actions=load:0x7->NXM_NX_REG5[],controller(max_len=128,id=15768),resubmit(,45)",
+		"table=45, n_packets=10, n_bytes=740, priority=14899,conj_id=8,ip actions=load:0x8->NXM_NX_REG5[],ct(commit,table=61,zone=65520)",
+		"table=45, n_packets=0, n_bytes=0, priority=14900,ip,nw_dst=10.10.0.1 actions=conjunction(7,2/2)",
+		"table=45, n_packets=1502362, n_bytes=601635949, priority=0 actions=goto_table:50",
+	}
+	gomock.InOrder(
+		mockOVSClient.EXPECT().DumpTableFlows(uint8(EgressMetricTable)).Return(nil, nil),
+		mockOVSClient.EXPECT().DumpTableFlows(uint8(IngressMetricTable)).Return(nil, nil),
+		mockOVSClient.EXPECT().DumpTableFlows(uint8(AntreaPolicyEgressRuleTable)).Return(ruleTableFlows, nil),
+	)
+	want := map[uint32]*types.RuleMetric{
+		7: {Bytes: 222, Sessions: 3, Packets: 3},
+	}
+	assert.Equal(t, want, c.NetworkPolicyMetrics())
+}
+
+func TestCalculateActionFlowsForAuditRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
+	cnpOutTable.EXPECT().BuildFlow(gomock.Any()).Return(newMockRuleFlowBuilder(ctrl)).AnyTimes()
+	ruleAction.EXPECT().SendToController(gomock.Any()).Return(ruleFlowBuilder).AnyTimes()
+	ruleAction.EXPECT().ResubmitToTable(AntreaPolicyEgressRuleTable).Return(ruleFlowBuilder).Times(1)
+	dropAction := crdv1alpha1.RuleActionDrop
+	priority := uint16(14900)
+	rule := &types.PolicyRule{
+		Direction:       v1beta2.DirectionOut,
+		From:            parseAddresses([]string{"192.168.1.30"}),
+		To:              parseAddresses([]string{"192.168.2.0/24"}),
+		Action:          &dropAction,
+		Priority:        &priority,
+		FlowID:          uint32(105),
+		TableID:         AntreaPolicyEgressRuleTable,
+		EnforcementMode: crdv1alpha1.RuleEnforcementModeAudit,
+		PolicyRef: &v1beta2.NetworkPolicyReference{
+			Type: v1beta2.AntreaClusterNetworkPolicy,
+			Name: "acnp1",
+			UID:  "id1",
+		},
+	}
+	conj, err := c.calculateActionFlowChangesForRule(rule)
+	require.NoError(t, err)
+	assert.True(t, conj.metricsFromActionFlow)
+	assert.Equal(t, 1, len(conj.actionFlows))
+	assert.Empty(t, conj.metricFlows)
+}
+
+func TestCalculateActionFlowsForPassRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
+	cnpOutTable.EXPECT().BuildFlow(gomock.Any()).Return(newMockRuleFlowBuilder(ctrl)).AnyTimes()
+	ruleAction.EXPECT().SendToController(gomock.Any()).Return(ruleFlowBuilder).Times(1)
+	ruleAction.EXPECT().ResubmitToTable(AntreaPolicyEgressRuleTable).Return(ruleFlowBuilder).Times(1)
+	passAction := crdv1alpha1.RuleActionPass
+	priority := uint16(14900)
+	tierPriority := int32(100)
+	rule := &types.PolicyRule{
+		Direction:     v1beta2.DirectionOut,
+		From:          parseAddresses([]string{"192.168.1.30"}),
+		To:            parseAddresses([]string{"192.168.2.0/24"}),
+		Action:        &passAction,
+		Priority:      &priority,
+		FlowID:        uint32(106),
+		TableID:       AntreaPolicyEgressRuleTable,
+		EnableLogging: true,
+		TierPriority:  &tierPriority,
+		PolicyRef: &v1beta2.NetworkPolicyReference{
+			Type: v1beta2.AntreaClusterNetworkPolicy,
+			Name: "acnp1",
+			UID:  "id1",
+		},
+	}
+	conj, err := c.calculateActionFlowChangesForRule(rule)
+	require.NoError(t, err)
+	assert.True(t, conj.metricsFromActionFlow)
+	assert.Equal(t, 1, len(conj.actionFlows))
+	assert.Empty(t, conj.metricFlows)
+}
+
+func TestCalculateActionFlowsForMeteredLoggingRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
+	c.ovsMetersAreSupported = true
+	c.policyLoggingRate = 10
+	c.policyLoggingBurst = 20
+	packetInTable := createMockTable(ctrl, PacketInTable, binding.LastTableID, binding.TableMissActionDrop)
+	c.pipeline[PacketInTable] = packetInTable
+	metricTable.EXPECT().BuildFlow(gomock.Any()).Return(newMockMetricFlowBuilder(ctrl)).AnyTimes()
+	metricFlowBuilder.EXPECT().MatchReg(gomock.Any(), gomock.Any()).Return(metricFlowBuilder).AnyTimes()
+	cnpOutTable.EXPECT().BuildFlow(gomock.Any()).Return(newMockRuleFlowBuilder(ctrl)).AnyTimes()
+	ruleAction.EXPECT().SendToController(gomock.Any()).Return(ruleFlowBuilder).AnyTimes()
+	ruleAction.EXPECT().Meter(gomock.Any()).Return(ruleFlowBuilder).AnyTimes()
+
+	bridge := c.bridge.(*mocks.MockBridge)
+	newMeter := func(conjID uint32) {
+		meter := mocks.NewMockMeter(ctrl)
+		meterBand := mocks.NewMockMeterBandBuilder(ctrl)
+		bridge.EXPECT().CreateMeter(binding.MeterIDType(policyLoggingMeterID(conjID)), ofctrl.MeterBurst|ofctrl.MeterPktps).Return(meter)
+		meter.EXPECT().ResetMeterBands().Return(meter)
+		meter.EXPECT().MeterBand().Return(meterBand)
+		meterBand.EXPECT().MeterType(ofctrl.MeterDrop).Return(meterBand)
+		meterBand.EXPECT().Rate(uint32(10)).Return(meterBand)
+		meterBand.EXPECT().Burst(uint32(20)).Return(meterBand)
+		meterBand.EXPECT().Done().Return(meter)
+	}
+
+	allowAction := crdv1alpha1.RuleActionAllow
+	dropAction := crdv1alpha1.RuleActionDrop
+	priority := uint16(14900)
+	newRule := func(flowID uint32, action *crdv1alpha1.RuleAction, enforcementMode crdv1alpha1.RuleEnforcementMode) *types.PolicyRule {
+		return &types.PolicyRule{
+			Direction:       v1beta2.DirectionOut,
+			From:            parseAddresses([]string{"192.168.1.30"}),
+			To:              parseAddresses([]string{"192.168.2.0/24"}),
+			Action:          action,
+			Priority:        &priority,
+			FlowID:          flowID,
+			TableID:         AntreaPolicyEgressRuleTable,
+			EnableLogging:   true,
+			EnforcementMode: enforcementMode,
+			PolicyRef: &v1beta2.NetworkPolicyReference{
+				Type: v1beta2.AntreaClusterNetworkPolicy,
+				Name: "acnp1",
+				UID:  "id1",
+			},
+		}
+	}
+
+	// The copies of the packets logged by an Allow rule are sent to the controller by the flow of the rule in
+	// PacketInTable, with the meter of the rule.
+	newMeter(107)
+	packetInFlowBuilder := mocks.NewMockFlowBuilder(ctrl)
+	packetInFlowAction := mocks.NewMockAction(ctrl)
+	packetInTable.EXPECT().BuildFlow(uint16(priorityNormal)).Return(packetInFlowBuilder)
+	packetInFlowBuilder.EXPECT().MatchRegRange(int(marksReg), uint32(packetInMarkPolicyLogging), packetInMarkRange).Return(packetInFlowBuilder)
+	packetInFlowBuilder.EXPECT().MatchRegRange(int(PacketInTableIDReg), uint32(AntreaPolicyEgressRuleTable), PacketInTableIDRange).Return(packetInFlowBuilder)
+	packetInFlowBuilder.EXPECT().MatchRegRange(int(EgressReg), uint32(107), binding.Range{0, 31}).Return(packetInFlowBuilder)
+	packetInFlowBuilder.EXPECT().Action().Return(packetInFlowAction).Times(2)
+	packetInFlowAction.EXPECT().Meter(policyLoggingMeterID(107)).Return(packetInFlowBuilder)
+	packetInFlowAction.EXPECT().SendToController(uint8(PacketInReasonNP)).Return(packetInFlowBuilder)
+	packetInFlowBuilder.EXPECT().Cookie(gomock.Any()).Return(packetInFlowBuilder)
+	packetInFlowBuilder.EXPECT().Done().Return(mocks.NewMockFlow(ctrl))
+	ruleAction.EXPECT().Group(policyLoggingGroupID).Return(ruleFlowBuilder)
+	conj, err := c.calculateActionFlowChangesForRule(newRule(107, &allowAction, crdv1alpha1.RuleEnforcementModeEnforce))
+	require.NoError(t, err)
+	assert.NotNil(t, conj.loggingMeter)
+	assert.Equal(t, 1, len(conj.packetInFlows))
+
+	// The packets logged by a Drop rule are metered by its action flow.
+	newMeter(108)
+	conj, err = c.calculateActionFlowChangesForRule(newRule(108, &dropAction, crdv1alpha1.RuleEnforcementModeEnforce))
+	require.NoError(t, err)
+	assert.NotNil(t, conj.loggingMeter)
+	assert.Empty(t, conj.packetInFlows)
+
+	// The packets logged by a rule in Audit mode are not metered by a meter of the rule, but sent to
+	// PacketInTable by auditLoggingGroup.
+	ruleAction.EXPECT().Group(auditLoggingGroupID).Return(ruleFlowBuilder)
+	ruleAction.EXPECT().ResubmitToTable(AntreaPolicyEgressRuleTable).Return(ruleFlowBuilder)
+	conj, err = c.calculateActionFlowChangesForRule(newRule(109, &dropAction, crdv1alpha1.RuleEnforcementModeAudit))
+	require.NoError(t, err)
+	assert.Nil(t, conj.loggingMeter)
+	assert.Empty(t, conj.packetInFlows)
+}
+
+func TestCalculateActionFlowsForPassRuleAndLowerTierRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
+	metricTable.EXPECT().BuildFlow(gomock.Any()).Return(newMockMetricFlowBuilder(ctrl)).AnyTimes()
+	metricFlowBuilder.EXPECT().MatchReg(gomock.Any(), gomock.Any()).Return(metricFlowBuilder).AnyTimes()
+
+	// The Pass rule skips the remaining rules of its Tier by setting the Tier's bit, and resubmits the packet to
+	// the rule table to evaluate it against the rules of the next Tiers.
+	passPriority := uint16(14900)
+	passFlowBuilder := mocks.NewMockFlowBuilder(ctrl)
+	passFlowAction := mocks.NewMockAction(ctrl)
+	cnpOutTable.EXPECT().BuildFlow(passPriority).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().MatchConjID(uint32(106)).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().MatchRegRange(int(tierPassReg), uint32(0), binding.Range{0, 0}).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().Action().Return(passFlowAction).Times(2)
+	passFlowAction.EXPECT().LoadRegRange(int(tierPassReg), uint32(1), binding.Range{0, 0}).Return(passFlowBuilder)
+	passFlowAction.EXPECT().ResubmitToTable(AntreaPolicyEgressRuleTable).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().Cookie(gomock.Any()).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().Done().Return(mocks.NewMockFlow(ctrl))
+
+	// The Drop rule of the lower Tier is still evaluated after the Pass rule has been matched, as its Tier has no
+	// rule with the Pass action, hence no bit.
+	dropPriority := uint16(13000)
+	dropFlowBuilder := mocks.NewMockFlowBuilder(ctrl)
+	dropFlowAction := mocks.NewMockAction(ctrl)
+	cnpOutTable.EXPECT().BuildFlow(dropPriority).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().MatchConjID(uint32(107)).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().Action().Return(dropFlowAction).AnyTimes()
+	dropFlowAction.EXPECT().LoadRegRange(gomock.Any(), gomock.Any(), gomock.Any()).Return(dropFlowBuilder).AnyTimes()
+	dropFlowAction.EXPECT().GotoTable(EgressMetricTable).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().Cookie(gomock.Any()).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().Done().Return(mocks.NewMockFlow(ctrl))
+
+	passAction := crdv1alpha1.RuleActionPass
+	dropAction := crdv1alpha1.RuleActionDrop
+	securityOpsTierPriority := int32(100)
+	applicationTierPriority := int32(250)
+	newRule := func(flowID uint32, action *crdv1alpha1.RuleAction, priority *uint16, tierPriority *int32) *types.PolicyRule {
+		return &types.PolicyRule{
+			Direction:    v1beta2.DirectionOut,
+			From:         parseAddresses([]string{"192.168.1.30"}),
+			To:           parseAddresses([]string{"192.168.2.0/24"}),
+			Action:       action,
+			Priority:     priority,
+			FlowID:       flowID,
+			TableID:      AntreaPolicyEgressRuleTable,
+			TierPriority: tierPriority,
+			PolicyRef: &v1beta2.NetworkPolicyReference{
+				Type: v1beta2.AntreaClusterNetworkPolicy,
+				Name: "acnp1",
+				UID:  "id1",
+			},
+		}
+	}
+	passConj, err := c.calculateActionFlowChangesForRule(newRule(106, &passAction, &passPriority, &securityOpsTierPriority))
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(passConj.actionFlows))
+	dropConj, err := c.calculateActionFlowChangesForRule(newRule(107, &dropAction, &dropPriority, &applicationTierPriority))
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(dropConj.actionFlows))
+	assert.Equal(t, map[int32]uint32{securityOpsTierPriority: 0}, c.tierPassBits.bits)
+
+	// The bit of a Tier is freed once no rule of the Tier with the Pass action is installed anymore.
+	c.releaseTierPassBit(passConj)
+	assert.Empty(t, c.tierPassBits.bits)
+}
+
+func TestCalculateActionFlowsForEgressPassRuleAndIngressRuleOfSameTier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
+	cnpInTable := createMockTable(ctrl, AntreaPolicyIngressRuleTable, IngressRuleTable, binding.TableMissActionNext)
+	inMetricTable := createMockTable(ctrl, IngressMetricTable, conntrackCommitTable, binding.TableMissActionNext)
+	c.pipeline[AntreaPolicyIngressRuleTable] = cnpInTable
+	c.pipeline[IngressMetricTable] = inMetricTable
+	inMetricTable.EXPECT().BuildFlow(gomock.Any()).Return(newMockMetricFlowBuilder(ctrl)).AnyTimes()
+	metricFlowBuilder.EXPECT().MatchReg(gomock.Any(), gomock.Any()).Return(metricFlowBuilder).AnyTimes()
+
+	// The egress Pass rule sets the bit of its Tier.
+	passPriority := uint16(14900)
+	passFlowBuilder := mocks.NewMockFlowBuilder(ctrl)
+	passFlowAction := mocks.NewMockAction(ctrl)
+	cnpOutTable.EXPECT().BuildFlow(passPriority).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().MatchConjID(uint32(106)).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().MatchRegRange(int(tierPassReg), uint32(0), binding.Range{0, 0}).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().Action().Return(passFlowAction).Times(2)
+	passFlowAction.EXPECT().LoadRegRange(int(tierPassReg), uint32(1), binding.Range{0, 0}).Return(passFlowBuilder)
+	passFlowAction.EXPECT().ResubmitToTable(AntreaPolicyEgressRuleTable).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().Cookie(gomock.Any()).Return(passFlowBuilder)
+	passFlowBuilder.EXPECT().Done().Return(mocks.NewMockFlow(ctrl))
+
+	// The ingress Drop rule of the same Tier matches the same bit. It is still enforced on the packets which have
+	// matched the egress Pass rule, as tierPassReg is reset when the packets enter the ingress rule tables.
+	dropPriority := uint16(14900)
+	dropFlowBuilder := mocks.NewMockFlowBuilder(ctrl)
+	dropFlowAction := mocks.NewMockAction(ctrl)
+	cnpInTable.EXPECT().BuildFlow(dropPriority).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().MatchConjID(uint32(107)).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().MatchRegRange(int(tierPassReg), uint32(0), binding.Range{0, 0}).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().Action().Return(dropFlowAction).AnyTimes()
+	dropFlowAction.EXPECT().LoadRegRange(gomock.Any(), gomock.Any(), gomock.Any()).Return(dropFlowBuilder).AnyTimes()
+	dropFlowAction.EXPECT().GotoTable(IngressMetricTable).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().Cookie(gomock.Any()).Return(dropFlowBuilder)
+	dropFlowBuilder.EXPECT().Done().Return(mocks.NewMockFlow(ctrl))
+
+	passAction := crdv1alpha1.RuleActionPass
+	dropAction := crdv1alpha1.RuleActionDrop
+	securityOpsTierPriority := int32(100)
+	policyRef := &v1beta2.NetworkPolicyReference{
+		Type: v1beta2.AntreaClusterNetworkPolicy,
+		Name: "acnp1",
+		UID:  "id1",
+	}
+	passConj, err := c.calculateActionFlowChangesForRule(&types.PolicyRule{
+		Direction:    v1beta2.DirectionOut,
+		From:         parseAddresses([]string{"192.168.1.30"}),
+		To:           parseAddresses([]string{"192.168.1.31"}),
+		Action:       &passAction,
+		Priority:     &passPriority,
+		FlowID:       uint32(106),
+		TableID:      AntreaPolicyEgressRuleTable,
+		TierPriority: &securityOpsTierPriority,
+		PolicyRef:    policyRef,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(passConj.actionFlows))
+	dropConj, err := c.calculateActionFlowChangesForRule(&types.PolicyRule{
+		Direction:    v1beta2.DirectionIn,
+		From:         parseAddresses([]string{"192.168.1.30"}),
+		To:           parseAddresses([]string{"192.168.1.31"}),
+		Action:       &dropAction,
+		Priority:     &dropPriority,
+		FlowID:       uint32(107),
+		TableID:      AntreaPolicyIngressRuleTable,
+		TierPriority: &securityOpsTierPriority,
+		PolicyRef:    policyRef,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(dropConj.actionFlows))
+	assert.Equal(t, map[int32]uint32{securityOpsTierPriority: 0}, c.tierPassBits.bits)
+
+	c.releaseTierPassBit(passConj)
+	assert.Empty(t, c.tierPassBits.bits)
+}
+
+func TestInstallPolicyRuleFlowsInMaxTiers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c = prepareClient(ctrl)
+	c.ipProtocols = []binding.Protocol{binding.ProtocolIP}
+	bridge := binding.NewOFBridge("br-int", "")
+	for tableID, nextTable := range map[binding.TableIDType]binding.TableIDType{
+		AntreaPolicyEgressRuleTable:  EgressRuleTable,
+		EgressDefaultTable:           EgressMetricTable,
+		EgressMetricTable:            l3ForwardingTable,
+		AntreaPolicyIngressRuleTable: IngressRuleTable,
+		IngressDefaultTable:          IngressMetricTable,
+		IngressMetricTable:           conntrackCommitTable,
+	} {
+		c.pipeline[tableID] = bridge.CreateTable(tableID, nextTable, binding.TableMissActionNext)
+	}
+
+	passAction := crdv1alpha1.RuleActionPass
+	dropAction := crdv1alpha1.RuleActionDrop
+	flowID := uint32(100)
+	newRule := func(action *crdv1alpha1.RuleAction, tableID binding.TableIDType, tierPriority int32) *types.PolicyRule {
+		flowID++
+		direction := v1beta2.DirectionOut
+		if tableID == AntreaPolicyIngressRuleTable {
+			direction = v1beta2.DirectionIn
+		}
+		priority := uint16(flowID)
+		return &types.PolicyRule{
+			Direction:    direction,
+			From:         parseAddresses([]string{"192.168.1.30"}),
+			To:           parseAddresses([]string{"192.168.1.31"}),
+			Action:       action,
+			Priority:     &priority,
+			FlowID:       flowID,
+			TableID:      tableID,
+			TierPriority: &tierPriority,
+			PolicyRef: &v1beta2.NetworkPolicyReference{
+				Type: v1beta2.AntreaClusterNetworkPolicy,
+				Name: fmt.Sprintf("acnp%d", tierPriority),
+				UID:  "id1",
+			},
+		}
+	}
+	// Install a Drop rule and then a Pass rule in both directions in each of the 20 Tiers supported by the
+	// Controller, so that the action flows of the Drop rules are updated when the bits are allocated.
+	tierPriorities := make([]int32, 20)
+	var passRules []*types.PolicyRule
+	for i := range tierPriorities {
+		tierPriorities[i] = int32(i * 10)
+		for _, tableID := range []binding.TableIDType{AntreaPolicyEgressRuleTable, AntreaPolicyIngressRuleTable} {
+			require.NoError(t, c.InstallPolicyRuleFlows(newRule(&dropAction, tableID, tierPriorities[i])))
+			passRule := newRule(&passAction, tableID, tierPriorities[i])
+			require.NoError(t, c.InstallPolicyRuleFlows(passRule))
+			passRules = append(passRules, passRule)
+		}
+	}
+	// The ingress and egress rules of a Tier share a bit.
+	assert.Len(t, c.tierPassBits.bits, len(tierPriorities))
+	checkTierPassBits := func() {
+		for _, obj := range c.policyCache.List() {
+			conj := obj.(*policyRuleConjunction)
+			assert.Equal(t, c.tierPassBits.get(*conj.tierPriority), conj.tierPassBit, "Rule %d doesn't match the bit of its Tier", conj.id)
+			for _, flow := range conj.actionFlows {
+				if conj.tierPassBit == nil {
+					assert.NotContains(t, flow.MatchString(), "reg8", "Rule %d matches a bit of Tier", conj.id)
+				} else {
+					assert.Contains(t, flow.MatchString(), fmt.Sprintf("reg8[%[1]d..%[1]d]=0x0", *conj.tierPassBit), "Rule %d doesn't match the bit of its Tier", conj.id)
+				}
+			}
+		}
+	}
+	checkTierPassBits()
+
+	// The bit of a Tier is freed and no longer matched by the Drop rules of the Tier once its Pass rules are
+	// uninstalled.
+	for _, rule := range passRules[:2] {
+		_, err := c.UninstallPolicyRuleFlows(rule.FlowID)
+		require.NoError(t, err)
+	}
+	assert.Len(t, c.tierPassBits.bits, len(tierPriorities)-1)
+	assert.Nil(t, c.tierPassBits.get(tierPriorities[0]))
+	checkTierPassBits()
+}
@@ -35,10 +35,14 @@ const (
 	// Meter Entry ID.
 	PacketInMeterIDNP = 1
 	PacketInMeterIDTF = 2
+	// PacketInMeterIDAudit is the meter of the packets matching the
+	// Antrea-native policy rules in Audit mode sent to the controller.
+	PacketInMeterIDAudit = 3
 	// Meter Entry Rate. It is represented as number of events per second.
 	// Packets which exceed the rate will be dropped.
-	PacketInMeterRateNP = 100
-	PacketInMeterRateTF = 100
+	PacketInMeterRateNP    = 100
+	PacketInMeterRateTF    = 100
+	PacketInMeterRateAudit = 100
 
 	// PacketIn reasons
 	PacketInReasonTF ofpPacketInReason = 1
@@ -70,6 +70,7 @@ const (
 	conntrackCommitTable         binding.TableIDType = 105
 	hairpinSNATTable             binding.TableIDType = 106
 	L2ForwardingOutTable         binding.TableIDType = 110
+	PacketInTable                binding.TableIDType = 120
 
 	// Flow priority level
 	priorityHigh            = uint16(210)
@@ -87,10 +88,33 @@ const (
 	markTrafficFromLocal   = 2
 	markTrafficFromUplink  = 4
 
+	// The IDs of the meters of Egress IPs start from egressMeterIDBase, so
+	// that they don't overlap with the packet-in meters.
+	egressMeterIDBase = 256
+
+	// policyLoggingGroupID is the ID of the group which sends a copy of the
+	// packets logged by the Antrea-native policy rules to PacketInTable. The
+	// IDs of the groups which are not allocated for Services are taken from
+	// the end of the group ID range.
+	policyLoggingGroupID binding.GroupIDType = 0xfffffeff
+	// auditLoggingGroupID is the ID of the group which sends a copy of the
+	// packets matching the Antrea-native policy rules in Audit mode to
+	// PacketInTable.
+	auditLoggingGroupID binding.GroupIDType = 0xfffffefe
+
+	// The IDs of the meters of the Antrea-native policy rules with logging
+	// enabled start from policyLoggingMeterIDBase, so that they don't
+	// overlap with the packet-in meters and the meters of Egress IPs.
+	policyLoggingMeterIDBase = 0x10000
+
 	// IPv6 multicast prefix
 	ipv6MulticastAddr = "FF00::/8"
 	// IPv6 link-local prefix
 	ipv6LinkLocalAddr = "FE80::/10"
+
+	// dnsPort is the transport port of DNS servers, whose responses are
+	// intercepted to learn the IPs of the FQDNs used in policy rules.
+	dnsPort = uint16(53)
 )
 
 type ofAction int32
@@ -154,6 +178,7 @@ var (
 		{conntrackCommitTable, "ConntrackCommit"},
 		{hairpinSNATTable, "HairpinSNATTable"},
 		{L2ForwardingOutTable, "Output"},
+		{PacketInTable, "PacketIn"},
 	}
 )
 
@@ -224,7 +249,7 @@ func (rt regType) reg() string {
 
 const (
 	// marksReg stores traffic-source mark and pod-found mark.
-	// traffic-source resides in [0..15], pod-found resides in [16], Antrea Policy disposition in [21-22], Custom Reasons in [24-26]
+	// traffic-source resides in [0..15], pod-found resides in [16], Antrea Policy disposition in [21-22], Custom Reasons in [24-27]
 	marksReg        regType = 0
 	PortCacheReg    regType = 1
 	swapReg         regType = 2
@@ -233,7 +258,14 @@ const (
 	serviceLearnReg         = endpointPortReg // Use reg4[16..18] to store endpoint selection states.
 	EgressReg       regType = 5
 	IngressReg      regType = 6
-	TraceflowReg    regType = 9 // Use reg9[28..31] to store traceflow dataplaneTag.
+	// PacketInTableIDReg stores the ID of the table of the flow which sends a copy of the packet to PacketInTable,
+	// as the packet-in message sent by PacketInTable carries the ID of PacketInTable.
+	PacketInTableIDReg regType = 7
+	// tierPassReg stores a bit for each Tier with rules of the Pass action installed in the Antrea-native policy rule
+	// tables. A Tier's bit is set when the packet matches a rule of the Tier with the Pass action. It is reset when the
+	// packet enters the ingress rule tables, so that the ingress and egress rules of a Tier share the bit.
+	tierPassReg  regType = 8
+	TraceflowReg regType = 9 // Use reg9[28..31] to store traceflow dataplaneTag.
 	// CNPDenyConjIDReg reuses reg3 which will also be used for storing endpoint IP to store the rule ID. Since
 	// the service selection will finish when a packet hitting NetworkPolicy related rules, there is no conflict.
 	CNPDenyConjIDReg  regType = 3
@@ -245,6 +277,9 @@ const (
 	// marksRegServiceNeedLearn indicates a packet has done service selection and
 	// the selection result needs to be cached.
 	marksRegServiceNeedLearn uint32 = 0b011
+	// defaultServiceBucketWeight is the weight of the bucket of an Endpoint in the Service group if the Endpoint
+	// doesn't specify a weight.
+	defaultServiceBucketWeight uint16 = 100
 
 	CtZone   = 0xfff0
 	CtZoneV6 = 0xffe6
@@ -256,6 +291,18 @@ const (
 	macRewriteMark = 0b1
 	// cnpDenyMark indicates the packet is denied(Drop/Reject).
 	cnpDenyMark = 0b1
+	// l7NPReturnMark indicates the packet is sent back to the OVS bridge by the L7 engine
+	// after it has been accepted.
+	l7NPReturnMark = 0b1
+	// packetInMarkAudit indicates the packet is a copy of a packet matching
+	// an Antrea-native policy rule in Audit mode.
+	packetInMarkAudit = 0b10
+	// packetInMarkPolicyLogging indicates the packet is a copy of a packet
+	// logged by an Antrea-native policy rule.
+	packetInMarkPolicyLogging = 0b11
+	// auditMark indicates the packet has matched an Antrea-native policy rule in Audit mode. It prevents the packet
+	// from matching the same rule again when it is resubmitted to the rule table.
+	auditMark = 0b1
 
 	// gatewayCTMark is used to to mark connections initiated through the host gateway interface
 	// (i.e. for which the first packet of the connection was received through the gateway).
@@ -268,8 +315,9 @@ const (
 	DispositionAllow = 0b00
 	DispositionDrop  = 0b01
 	DispositionRej   = 0b10
+	DispositionPass  = 0b11
 
-	// custom reason is loaded in marksReg [24-26]
+	// custom reason is loaded in marksReg [24-27]
 	// The custom reason mark is used to indicate the reason(s) for sending the packet
 	// to the controller. Reasons can be or-ed to indicate that the packets was sent
 	// for multiple reasons.
@@ -290,12 +338,19 @@ const (
 	// by the Flow Exporter to export flow records for connections denied by network
 	// policy rules.
 	CustomReasonDeny = 0b100
+	// CustomReasonDNS is used when sending packet-in message to controller indicating
+	// that the packet is a DNS response, which should be parsed to learn the IPs of
+	// the FQDNs used in Antrea-native policy rules. It's also used when controller
+	// sends the DNS response back as packet-out, so that it bypasses the connTrack
+	// and is not sent to controller again.
+	CustomReasonDNS = 0b1000
 )
 
 var DispositionToString = map[uint32]string{
 	DispositionAllow: "Allow",
 	DispositionDrop:  "Drop",
 	DispositionRej:   "Reject",
+	DispositionPass:  "Pass",
 }
 
 var (
@@ -319,10 +374,26 @@ var (
 	// disposition of Antrea Policy. It could have more bits to support more disposition
 	// that Antrea policy support in the future.
 	APDispositionMarkRange = binding.Range{21, 22}
-	// CustomReasonMarkRange takes the 24 to 26 bits of register marksReg to indicate
+	// l7NPReturnMarkRange takes the 23rd bit of register marksReg to indicate if
+	// the packet is sent back by the L7 engine. 1 means yes.
+	l7NPReturnMarkRange = binding.Range{23, 23}
+	// CustomReasonMarkRange takes the 24 to 27 bits of register marksReg to indicate
 	// the reason of sending packet to the controller. It could have more bits to
 	// support more customReason in the future.
-	CustomReasonMarkRange = binding.Range{24, 26}
+	CustomReasonMarkRange = binding.Range{24, 27}
+	// EgressAuditMarkRange takes the 28th bit of register marksReg to indicate if the packet has
+	// matched an egress rule in Audit mode. Its value is 0x1 if yes.
+	EgressAuditMarkRange = binding.Range{28, 28}
+	// IngressAuditMarkRange takes the 29th bit of register marksReg to indicate if the packet has
+	// matched an ingress rule in Audit mode. Its value is 0x1 if yes.
+	IngressAuditMarkRange = binding.Range{29, 29}
+	// packetInMarkRange takes the 30 to 31 bits of register marksReg to indicate
+	// the feature for which a copy of the packet has been sent to PacketInTable
+	// by a group.
+	packetInMarkRange = binding.Range{30, 31}
+	// PacketInTableIDRange takes the 0 to 7 bits of register PacketInTableIDReg to store the ID of the table which
+	// sent a copy of the packet to PacketInTable.
+	PacketInTableIDRange = binding.Range{0, 7}
 	// endpointIPRegRange takes a 32-bit range of register endpointIPReg to store
 	// the selected Service Endpoint IP.
 	endpointIPRegRange = binding.Range{0, 31}
@@ -392,7 +463,7 @@ type client struct {
 	ingressEntryTable  binding.TableIDType
 	pipeline           map[binding.TableIDType]binding.Table
 	// Flow caches for corresponding deletions.
-	nodeFlowCache, podFlowCache, serviceFlowCache, snatFlowCache, tfFlowCache *flowCategoryCache
+	nodeFlowCache, ipPoolFlowCache, podFlowCache, serviceFlowCache, snatFlowCache, tfFlowCache, dnsFlowCache, l7NPFlowCache *flowCategoryCache
 	// "fixed" flows installed by the agent after initialization and which do not change during
 	// the lifetime of the client.
 	gatewayFlows, defaultServiceFlows, defaultTunnelFlows, hostNetworkingFlows []binding.Flow
@@ -404,6 +475,15 @@ type client struct {
 	policyCache       cache.Indexer
 	conjMatchFlowLock sync.Mutex // Lock for access globalConjMatchFlowCache
 	groupCache        sync.Map
+	// egressMeterCache stores the meters of the Egress IPs, keyed by the marks of the Egress IPs.
+	egressMeterCache sync.Map
+	// podDestinationSNATs stores the SNAT configurations of the Egresses with destinations, keyed by the ofPorts of
+	// the local Pods and the names of the Egresses. It's used to decide which Egress installs the flow of a
+	// destination CIDR shared by multiple Egresses applying to the same Pod.
+	podDestinationSNATs     map[uint32]map[string]*podDestinationSNAT
+	podDestinationSNATsLock sync.Mutex
+	// tierPassBits allocates the bits of tierPassReg to the Tiers with rules of the Pass action.
+	tierPassBits *tierPassBitAllocator
 	// globalConjMatchFlowCache is a global map for conjMatchFlowContext. The key is a string generated from the
 	// conjMatchFlowContext.
 	globalConjMatchFlowCache map[string]*conjMatchFlowContext
@@ -412,10 +492,17 @@ type client struct {
 	nodeConfig    *config.NodeConfig
 	encapMode     config.TrafficEncapModeType
 	gatewayOFPort uint32
+	// l7NPTargetOFPort is the ofport to which the packets of the connections allowed by the
+	// Antrea-native policy rules with L7 protocols are redirected.
+	l7NPTargetOFPort uint32
 	// ovsDatapathType is the type of the datapath used by the bridge.
 	ovsDatapathType ovsconfig.OVSDatapathType
 	// ovsMetersAreSupported indicates whether the OVS datapath supports OpenFlow meters.
 	ovsMetersAreSupported bool
+	// policyLoggingRate and policyLoggingBurst are the rate and the burst of the meters of the Antrea-native policy
+	// rules with logging enabled, in packets per second. policyLoggingRate is 0 if the rules don't have meters.
+	policyLoggingRate  uint32
+	policyLoggingBurst uint32
 	// packetInHandlers stores handler to process PacketIn event. Each packetin reason can have multiple handlers registered.
 	// When a packetin arrives, openflow send packet to registered handlers in this map.
 	packetInHandlers map[uint8]map[string]PacketInHandler
@@ -768,11 +855,11 @@ func (c *client) traceflowConnectionTrackFlows(dataplaneTag uint8, receiverOnly
 		} else {
 			l2FwdCalcTable := c.pipeline[l2ForwardingCalcTable]
 			nextTable := c.ingressEntryTable
-			flowBuilder = l2FwdCalcTable.BuildFlow(priorityHigh).
+			flowBuilder = c.resetTierPassReg(l2FwdCalcTable.BuildFlow(priorityHigh).
 				MatchDstMAC(packet.DestinationMAC).
 				Action().LoadRegRange(int(PortCacheReg), ofPort, ofPortRegRange).
 				Action().LoadRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
-				Action().LoadIPDSCP(dataplaneTag).
+				Action().LoadIPDSCP(dataplaneTag), nextTable).
 				Action().GotoTable(nextTable)
 			if packet.SourceIP != nil {
 				flowBuilder = flowBuilder.MatchSrcIP(packet.SourceIP)
@@ -936,10 +1023,10 @@ func (c *client) l2ForwardCalcFlow(dstMAC net.HardwareAddr, ofPort uint32, skipI
 		// Go to ingress NetworkPolicy tables for traffic to local Pods.
 		nextTable = c.ingressEntryTable
 	}
-	return l2FwdCalcTable.BuildFlow(priorityNormal).
+	return c.resetTierPassReg(l2FwdCalcTable.BuildFlow(priorityNormal).
 		MatchDstMAC(dstMAC).
 		Action().LoadRegRange(int(PortCacheReg), ofPort, ofPortRegRange).
-		Action().LoadRegRange(int(marksReg), portFoundMark, ofPortMarkRange).
+		Action().LoadRegRange(int(marksReg), portFoundMark, ofPortMarkRange), nextTable).
 		Action().GotoTable(nextTable).
 		Cookie(c.cookieAllocator.Request(category).Raw()).
 		Done()
@@ -947,6 +1034,15 @@ func (c *client) l2ForwardCalcFlow(dstMAC net.HardwareAddr, ofPort uint32, skipI
 	// the default flow of L2ForwardingOutTable.
 }
 
+// resetTierPassReg resets tierPassReg if the packet goes to the Antrea-native ingress policy rule table, so that the
+// bits set by the egress rules with the Pass action don't skip the ingress rules of the same Tiers.
+func (c *client) resetTierPassReg(fb binding.FlowBuilder, nextTable binding.TableIDType) binding.FlowBuilder {
+	if nextTable != AntreaPolicyIngressRuleTable {
+		return fb
+	}
+	return fb.Action().LoadRegRange(int(tierPassReg), 0, binding.Range{0, 31})
+}
+
 // traceflowL2ForwardOutputFlows generates Traceflow specific flows that outputs traceflow packets
 // to OVS port and Antrea Agent after L2forwarding calculation.
 func (c *client) traceflowL2ForwardOutputFlows(dataplaneTag uint8, liveTraffic, droppedOnly bool, timeout uint16, category cookie.Category) []binding.Flow {
@@ -1245,16 +1341,21 @@ func (c *client) l3FwdFlowToRemoteViaGW(
 // arpResponderFlow generates the ARP responder flow entry that replies request comes from local gateway for peer
 // gateway MAC.
 func (c *client) arpResponderFlow(peerGatewayIP net.IP, category cookie.Category) binding.Flow {
+	return c.arpResponderFlowWithMAC(peerGatewayIP, globalVirtualMAC, category)
+}
+
+// arpResponderFlowWithMAC generates the ARP responder flow entry that replies request for the IP with the MAC.
+func (c *client) arpResponderFlowWithMAC(ip net.IP, mac net.HardwareAddr, category cookie.Category) binding.Flow {
 	return c.pipeline[arpResponderTable].BuildFlow(priorityNormal).MatchProtocol(binding.ProtocolARP).
 		MatchARPOp(1).
-		MatchARPTpa(peerGatewayIP).
+		MatchARPTpa(ip).
 		Action().Move(binding.NxmFieldSrcMAC, binding.NxmFieldDstMAC).
-		Action().SetSrcMAC(globalVirtualMAC).
+		Action().SetSrcMAC(mac).
 		Action().LoadARPOperation(2).
 		Action().Move(binding.NxmFieldARPSha, binding.NxmFieldARPTha).
-		Action().SetARPSha(globalVirtualMAC).
+		Action().SetARPSha(mac).
 		Action().Move(binding.NxmFieldARPSpa, binding.NxmFieldARPTpa).
-		Action().SetARPSpa(peerGatewayIP).
+		Action().SetARPSpa(ip).
 		Action().OutputInPort().
 		Cookie(c.cookieAllocator.Request(category).Raw()).
 		Done()
@@ -1513,7 +1614,7 @@ func (c *client) ipv6Flows(category cookie.Category) []binding.Flow {
 
 // conjunctionActionFlow generates the flow to jump to a specific table if policyRuleConjunction ID is matched. Priority of
 // conjunctionActionFlow is created at priorityLow for k8s network policies, and *priority assigned by PriorityAssigner for AntreaPolicy.
-func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.TableIDType, nextTable binding.TableIDType, priority *uint16, enableLogging bool) []binding.Flow {
+func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.TableIDType, nextTable binding.TableIDType, priority *uint16, enableLogging bool, tierPassBit *uint32) []binding.Flow {
 	var ofPriority uint16
 	if priority == nil {
 		ofPriority = priorityLow
@@ -1532,24 +1633,24 @@ func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.Tab
 			ctZone = CtZoneV6
 		}
 		if enableLogging {
-			fb := c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(proto).
-				MatchConjID(conjunctionID)
-			if c.ovsMetersAreSupported {
+			fb := matchTierPassBit(c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(proto).
+				MatchConjID(conjunctionID), tierPassBit)
+			if c.ovsMetersAreSupported && !c.policyLoggingMetered() {
 				fb = fb.Action().Meter(PacketInMeterIDNP)
 			}
-			return fb.
-				Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}).         // Traceflow.
-				Action().LoadRegRange(int(marksReg), DispositionAllow, APDispositionMarkRange).   // AntreaPolicy.
-				Action().LoadRegRange(int(marksReg), CustomReasonLogging, CustomReasonMarkRange). // Enable logging.
-				Action().SendToController(uint8(PacketInReasonNP)).
+			fb = fb.
+				Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}).        // Traceflow.
+				Action().LoadRegRange(int(marksReg), DispositionAllow, APDispositionMarkRange).  // AntreaPolicy.
+				Action().LoadRegRange(int(marksReg), CustomReasonLogging, CustomReasonMarkRange) // Enable logging.
+			return c.policyLoggingPacketIn(fb, tableID).
 				Action().CT(true, nextTable, ctZone). // CT action requires commit flag if actions other than NAT without arguments are specified.
 				LoadToLabelRange(uint64(conjunctionID), &labelRange).
 				CTDone().
 				Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
 				Done()
 		} else {
-			return c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(proto).
-				MatchConjID(conjunctionID).
+			return matchTierPassBit(c.pipeline[tableID].BuildFlow(ofPriority).MatchProtocol(proto).
+				MatchConjID(conjunctionID), tierPassBit).
 				Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}). // Traceflow.
 				Action().CT(true, nextTable, ctZone).                                     // CT action requires commit flag if actions other than NAT without arguments are specified.
 				LoadToLabelRange(uint64(conjunctionID), &labelRange).
@@ -1568,15 +1669,15 @@ func (c *client) conjunctionActionFlow(conjunctionID uint32, tableID binding.Tab
 // conjunctionActionDenyFlow generates the flow to mark the packet to be denied
 // (dropped or rejected) if policyRuleConjunction ID is matched.
 // Any matched flow will be dropped in corresponding metric tables.
-func (c *client) conjunctionActionDenyFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16, disposition uint32, enableLogging bool) binding.Flow {
+func (c *client) conjunctionActionDenyFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16, disposition uint32, enableLogging bool, tierPassBit *uint32) binding.Flow {
 	ofPriority := *priority
 	metricTableID := IngressMetricTable
 	if _, ok := egressTables[tableID]; ok {
 		metricTableID = EgressMetricTable
 	}
 
-	flowBuilder := c.pipeline[tableID].BuildFlow(ofPriority).
-		MatchConjID(conjunctionID).
+	flowBuilder := matchTierPassBit(c.pipeline[tableID].BuildFlow(ofPriority).
+		MatchConjID(conjunctionID), tierPassBit).
 		Action().LoadRegRange(int(CNPDenyConjIDReg), conjunctionID, binding.Range{0, 31}).
 		Action().LoadRegRange(int(marksReg), cnpDenyMark, cnpDenyMarkRange)
 
@@ -1596,7 +1697,10 @@ func (c *client) conjunctionActionDenyFlow(conjunctionID uint32, tableID binding
 	}
 
 	if enableLogging || c.enableDenyTracking || disposition == DispositionRej {
-		if c.ovsMetersAreSupported {
+		// The denied packets are dropped anyway, so the meter of the rule can be applied to the packets themselves.
+		if c.ovsMetersAreSupported && enableLogging && c.policyLoggingMetered() {
+			flowBuilder = flowBuilder.Action().Meter(policyLoggingMeterID(conjunctionID))
+		} else if c.ovsMetersAreSupported {
 			flowBuilder = flowBuilder.Action().Meter(PacketInMeterIDNP)
 		}
 		flowBuilder = flowBuilder.
@@ -1610,6 +1714,99 @@ func (c *client) conjunctionActionDenyFlow(conjunctionID uint32, tableID binding
 		Done()
 }
 
+// conjunctionActionAuditFlow generates the flow for a rule in Audit mode if policyRuleConjunction ID is matched.
+// The packet is sent to the controller to be logged with the disposition of the rule, then it is resubmitted to the
+// rule table with the audit mark, with which it no longer matches the flow, so that it is evaluated against the
+// subsequent rules as if the rule did not exist. The registers loaded for logging are reset before the packet is
+// resubmitted.
+// When OVS meters are supported, only a copy of the packet is sent to PacketInTable by auditLoggingGroup, where it
+// is sent to the controller with the Audit packet-in meter, so that the packets exceeding the rate are not logged
+// but the traffic matching the rule is never dropped.
+func (c *client) conjunctionActionAuditFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16, disposition uint32, tierPassBit *uint32) binding.Flow {
+	ofPriority := *priority
+	conjReg := IngressReg
+	auditMarkRange := IngressAuditMarkRange
+	if _, ok := egressTables[tableID]; ok {
+		conjReg = EgressReg
+		auditMarkRange = EgressAuditMarkRange
+	}
+	flowBuilder := matchTierPassBit(c.pipeline[tableID].BuildFlow(ofPriority).
+		MatchConjID(conjunctionID), tierPassBit).
+		MatchRegRange(int(marksReg), 0, auditMarkRange).
+		Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}).
+		Action().LoadRegRange(int(marksReg), disposition, APDispositionMarkRange).
+		Action().LoadRegRange(int(marksReg), CustomReasonLogging, CustomReasonMarkRange).
+		Action().LoadRegRange(int(marksReg), auditMark, auditMarkRange)
+	if c.ovsMetersAreSupported {
+		flowBuilder = flowBuilder.
+			Action().LoadRegRange(int(PacketInTableIDReg), uint32(tableID), PacketInTableIDRange).
+			Action().Group(auditLoggingGroupID)
+	} else {
+		flowBuilder = flowBuilder.Action().SendToController(uint8(PacketInReasonNP))
+	}
+	return flowBuilder.
+		Action().LoadRegRange(int(conjReg), 0, binding.Range{0, 31}).
+		Action().LoadRegRange(int(marksReg), 0, APDispositionMarkRange).
+		Action().LoadRegRange(int(marksReg), 0, CustomReasonMarkRange).
+		Action().ResubmitToTable(tableID).
+		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+		Done()
+}
+
+// conjunctionActionPassFlow generates the flow to skip the evaluation of the
+// remaining rules of the Tier if policyRuleConjunction ID is matched. The bit
+// of the Tier in tierPassReg is set and the packet is resubmitted to the rule
+// table, where it is evaluated against the rules of the next Tiers. If no rule
+// of the next Tiers matches it, it goes to the K8s NetworkPolicy rule table,
+// and is then evaluated against the rules in the baseline Tier if not isolated
+// by any K8s NetworkPolicy. Without a bit for the Tier, the packet goes to the
+// K8s NetworkPolicy rule table directly.
+func (c *client) conjunctionActionPassFlow(conjunctionID uint32, tableID binding.TableIDType, priority *uint16, enableLogging bool, tierPassBit *uint32) binding.Flow {
+	ofPriority := *priority
+	conjReg := IngressReg
+	nextTable := IngressRuleTable
+	if _, ok := egressTables[tableID]; ok {
+		conjReg = EgressReg
+		nextTable = EgressRuleTable
+	}
+	flowBuilder := matchTierPassBit(c.pipeline[tableID].BuildFlow(ofPriority).
+		MatchConjID(conjunctionID), tierPassBit)
+	if enableLogging {
+		if c.ovsMetersAreSupported && !c.policyLoggingMetered() {
+			flowBuilder = flowBuilder.Action().Meter(PacketInMeterIDNP)
+		}
+		// The marks are reset after sending the packet-in message so that they
+		// don't affect the evaluation of the subsequent rules.
+		flowBuilder = flowBuilder.
+			Action().LoadRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}).
+			Action().LoadRegRange(int(marksReg), DispositionPass, APDispositionMarkRange).
+			Action().LoadRegRange(int(marksReg), CustomReasonLogging, CustomReasonMarkRange)
+		flowBuilder = c.policyLoggingPacketIn(flowBuilder, tableID).
+			Action().LoadRegRange(int(conjReg), 0, binding.Range{0, 31}).
+			Action().LoadRegRange(int(marksReg), 0, APDispositionMarkRange).
+			Action().LoadRegRange(int(marksReg), 0, CustomReasonMarkRange)
+	}
+	if tierPassBit == nil {
+		return flowBuilder.Action().GotoTable(nextTable).
+			Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+			Done()
+	}
+	return flowBuilder.Action().LoadRegRange(int(tierPassReg), 1, binding.Range{*tierPassBit, *tierPassBit}).
+		Action().ResubmitToTable(tableID).
+		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+		Done()
+}
+
+// matchTierPassBit makes the action flow of a rule match the bit of the rule's Tier in tierPassReg being unset, so
+// that the rule is skipped once a rule of the same Tier with the Pass action has been matched. tierPassBit is nil
+// for the rules which are not installed in the Antrea-native policy rule tables.
+func matchTierPassBit(fb binding.FlowBuilder, tierPassBit *uint32) binding.FlowBuilder {
+	if tierPassBit == nil {
+		return fb
+	}
+	return fb.MatchRegRange(int(tierPassReg), 0, binding.Range{*tierPassBit, *tierPassBit})
+}
+
 func (c *client) Disconnect() error {
 	return c.bridge.Disconnect()
 }
@@ -1672,6 +1869,169 @@ func (c *client) establishedConnectionFlows(category cookie.Category) (flows []b
 	return allEstFlows
 }
 
+// dnsInterceptionFlows generates the flows to send the DNS responses received by
+// the local Pods to the controller, so that the IPs of the FQDNs used in
+// Antrea-native policy rules can be learned. The responses are held by the
+// controller until the flows of the learned IPs are installed, then they are
+// sent back to the pipeline as packet-outs with the CustomReasonDNS mark, with
+// which they bypass conntrack like the reject responses, and skip the ingress
+// rules like other packets of established connections.
+func (c *client) dnsInterceptionFlows(category cookie.Category) []binding.Flow {
+	connectionTrackStateTable := c.pipeline[conntrackStateTable]
+	ingressRuleTable := c.pipeline[AntreaPolicyIngressRuleTable]
+	ingressDropTable := c.pipeline[IngressDefaultTable]
+	var flows []binding.Flow
+	for _, ipProto := range c.ipProtocols {
+		udpProto := binding.ProtocolUDP
+		if ipProto == binding.ProtocolIPv6 {
+			udpProto = binding.ProtocolUDPv6
+		}
+		flows = append(flows,
+			connectionTrackStateTable.BuildFlow(priorityHigh).
+				MatchProtocol(udpProto).MatchSrcPort(dnsPort, nil).
+				MatchRegRange(int(marksReg), CustomReasonDNS, CustomReasonMarkRange).
+				Action().ResubmitToTable(connectionTrackStateTable.GetNext()).
+				Cookie(c.cookieAllocator.Request(category).Raw()).
+				Done(),
+			ingressRuleTable.BuildFlow(priorityTopAntreaPolicy+2).
+				MatchProtocol(udpProto).MatchSrcPort(dnsPort, nil).
+				MatchRegRange(int(marksReg), CustomReasonDNS, CustomReasonMarkRange).
+				Action().GotoTable(ingressDropTable.GetNext()).
+				Cookie(c.cookieAllocator.Request(category).Raw()).
+				Done(),
+			ingressRuleTable.BuildFlow(priorityTopAntreaPolicy+1).
+				MatchProtocol(udpProto).MatchSrcPort(dnsPort, nil).
+				MatchCTStateNew(false).MatchCTStateEst(true).
+				Action().LoadRegRange(int(marksReg), CustomReasonDNS, CustomReasonMarkRange).
+				Action().SendToController(uint8(PacketInReasonNP)).
+				Cookie(c.cookieAllocator.Request(category).Raw()).
+				Done())
+	}
+	return flows
+}
+
+// policyLoggingMetered returns whether the packets logged by each Antrea-native policy rule are rate-limited by a
+// meter of the rule, instead of the packet-in meter shared by all the rules.
+func (c *client) policyLoggingMetered() bool {
+	return c.ovsMetersAreSupported && c.policyLoggingRate > 0
+}
+
+// policyLoggingPacketIn adds the actions to send a packet matching an Antrea-native policy rule with logging enabled
+// to the controller. If the logged packets are rate-limited by the meters of the rules, only a copy of the packet is
+// sent to PacketInTable by policyLoggingGroup, and metered by the flow of the rule in PacketInTable, so that the
+// packets exceeding the rate are not dropped. tableID is stored in PacketInTableIDReg for the controller.
+func (c *client) policyLoggingPacketIn(fb binding.FlowBuilder, tableID binding.TableIDType) binding.FlowBuilder {
+	if !c.policyLoggingMetered() {
+		return fb.Action().SendToController(uint8(PacketInReasonNP))
+	}
+	return fb.Action().LoadRegRange(int(PacketInTableIDReg), uint32(tableID), PacketInTableIDRange).
+		Action().Group(policyLoggingGroupID)
+}
+
+// policyLoggingPacketInFlow generates the flow which sends the copies of the packets logged by an Allow or Pass rule
+// to the controller, with the meter of the rule. The copies are identified by the conjunction ID of the rule, loaded
+// in the ingress or egress register, and the ID of the rule's table, as the packet may have been logged by a rule of
+// each direction.
+func (c *client) policyLoggingPacketInFlow(conjunctionID uint32, tableID binding.TableIDType) binding.Flow {
+	conjReg := IngressReg
+	if _, ok := egressTables[tableID]; ok {
+		conjReg = EgressReg
+	}
+	return c.pipeline[PacketInTable].BuildFlow(priorityNormal).
+		MatchRegRange(int(marksReg), packetInMarkPolicyLogging, packetInMarkRange).
+		MatchRegRange(int(PacketInTableIDReg), uint32(tableID), PacketInTableIDRange).
+		MatchRegRange(int(conjReg), conjunctionID, binding.Range{0, 31}).
+		Action().Meter(policyLoggingMeterID(conjunctionID)).
+		Action().SendToController(uint8(PacketInReasonNP)).
+		Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+		Done()
+}
+
+// policyLoggingGroup generates the group which sends a copy of the packets
+// logged by the Antrea-native policy rules to PacketInTable. It has a single
+// bucket: the changes made by the bucket are not applied to the original
+// packet, which goes on with the remaining actions of the flow of the rule.
+func (c *client) policyLoggingGroup() binding.Group {
+	return c.bridge.CreateGroupTypeAll(policyLoggingGroupID).ResetBuckets().
+		Bucket().
+		LoadRegRange(int(marksReg), packetInMarkPolicyLogging, packetInMarkRange).
+		ResubmitToTable(PacketInTable).
+		Done()
+}
+
+// auditLoggingGroup generates the group which sends a copy of the packets
+// matching the Antrea-native policy rules in Audit mode to PacketInTable. Like
+// policyLoggingGroup, it has a single bucket, and the original packet goes on
+// with the remaining actions of the flow of the rule.
+func (c *client) auditLoggingGroup() binding.Group {
+	return c.bridge.CreateGroupTypeAll(auditLoggingGroupID).ResetBuckets().
+		Bucket().
+		LoadRegRange(int(marksReg), packetInMarkAudit, packetInMarkRange).
+		ResubmitToTable(PacketInTable).
+		Done()
+}
+
+// auditLoggingPacketInFlow generates the flow which sends the copies of the
+// packets matching the Antrea-native policy rules in Audit mode to the
+// controller, with the Audit packet-in meter.
+func (c *client) auditLoggingPacketInFlow(category cookie.Category) binding.Flow {
+	return c.pipeline[PacketInTable].BuildFlow(priorityNormal).
+		MatchRegRange(int(marksReg), packetInMarkAudit, packetInMarkRange).
+		Action().Meter(PacketInMeterIDAudit).
+		Action().SendToController(uint8(PacketInReasonNP)).
+		Cookie(c.cookieAllocator.Request(category).Raw()).
+		Done()
+}
+
+// l7NPRedirectFlows generates the flows to redirect the packets of the
+// connections allowed by an Antrea-native policy rule with L7 protocols to the
+// L7 engine, after they have gone through all the other tables. The
+// connections are identified by the rule ID stored in ct_label, and the
+// packets are tagged with the VLAN ID allocated to the rule, so that the L7
+// engine knows which rule should be enforced.
+func (c *client) l7NPRedirectFlows(conjunctionID uint32, ingress bool, vlanID uint32) []binding.Flow {
+	offset := 0
+	labelRange := metricIngressRuleIDRange
+	if !ingress {
+		offset = 32
+		labelRange = metricEgressRuleIDRange
+	}
+	var flows []binding.Flow
+	for _, ipProto := range c.ipProtocols {
+		flows = append(flows, c.pipeline[L2ForwardingOutTable].BuildFlow(priorityNormal+1).
+			MatchProtocol(ipProto).
+			MatchCTLabelRange(0, uint64(conjunctionID)<<offset, labelRange).
+			Action().PushVLAN(0x8100).
+			Action().SetVLAN(uint16(vlanID)).
+			Action().Output(int(c.l7NPTargetOFPort)).
+			Cookie(c.cookieAllocator.Request(cookie.Policy).Raw()).
+			Done())
+	}
+	return flows
+}
+
+// l7NPReturnFlows generates the flows for the packets sent back to the OVS
+// bridge by the L7 engine after they have been accepted. The VLAN header is
+// removed and the packets are marked with l7NPReturnMark, then they are
+// forwarded again from l3ForwardingTable and skip the ingress rules, as all the
+// NetworkPolicy rules have been enforced before the packets were redirected.
+func (c *client) l7NPReturnFlows(returnOFPort uint32, category cookie.Category) []binding.Flow {
+	return []binding.Flow{
+		c.pipeline[ClassifierTable].BuildFlow(priorityNormal).
+			MatchInPort(returnOFPort).
+			Action().PopVLAN().
+			Action().LoadRegRange(int(marksReg), l7NPReturnMark, l7NPReturnMarkRange).
+			Action().GotoTable(l3ForwardingTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+		c.pipeline[c.ingressEntryTable].BuildFlow(priorityTopAntreaPolicy+2).
+			MatchRegRange(int(marksReg), l7NPReturnMark, l7NPReturnMarkRange).
+			Action().GotoTable(L2ForwardingOutTable).
+			Cookie(c.cookieAllocator.Request(category).Raw()).
+			Done(),
+	}
+}
+
 func (c *client) addFlowMatch(fb binding.FlowBuilder, matchKey *types.MatchKey, matchValue interface{}) binding.FlowBuilder {
 	switch matchKey {
 	case MatchDstOFPort:
@@ -1711,6 +2071,24 @@ func (c *client) addFlowMatch(fb binding.FlowBuilder, matchKey *types.MatchKey,
 		if portValue.Value > 0 {
 			fb = fb.MatchDstPort(portValue.Value, portValue.Mask)
 		}
+	case MatchICMP:
+		fb = fb.MatchProtocol(matchKey.GetOFProtocol())
+		icmpValue := matchValue.(types.ICMPMatch)
+		if icmpValue.ICMPType != nil {
+			fb = fb.MatchICMPType(uint8(*icmpValue.ICMPType))
+		}
+		if icmpValue.ICMPCode != nil {
+			fb = fb.MatchICMPCode(uint8(*icmpValue.ICMPCode))
+		}
+	case MatchICMPv6:
+		fb = fb.MatchProtocol(matchKey.GetOFProtocol())
+		icmpValue := matchValue.(types.ICMPMatch)
+		if icmpValue.ICMPType != nil {
+			fb = fb.MatchICMPv6Type(uint8(*icmpValue.ICMPType))
+		}
+		if icmpValue.ICMPCode != nil {
+			fb = fb.MatchICMPv6Code(uint8(*icmpValue.ICMPCode))
+		}
 	}
 	return fb
 }
@@ -1855,50 +2233,137 @@ func (c *client) snatCommonFlows(nodeIP net.IP, localSubnet net.IPNet, localGate
 	return flows
 }
 
-// snatIPFromTunnelFlow generates a flow that marks SNAT packets tunnelled from
-// remote Nodes. The SNAT IP matches the packet's tunnel destination IP.
-func (c *client) snatIPFromTunnelFlow(snatIP net.IP, mark uint32) binding.Flow {
+// snatIPFromTunnelFlows generates the flows that mark SNAT packets tunnelled
+// from remote Nodes. The SNAT IP matches the packet's tunnel destination IP.
+// If metered is true, the packets are also sent to the meter of the SNAT IP,
+// including the packets of established connections which are matched by an
+// extra flow.
+func (c *client) snatIPFromTunnelFlows(snatIP net.IP, mark uint32, metered bool) []binding.Flow {
 	ipProto := getIPProtocol(snatIP)
-	return c.pipeline[snatTable].BuildFlow(priorityNormal).
+	snatTable := c.pipeline[snatTable]
+	fb := snatTable.BuildFlow(priorityNormal).
 		MatchProtocol(ipProto).
 		MatchCTStateNew(true).MatchCTStateTrk(true).
 		MatchTunnelDst(snatIP).
-		Action().LoadPktMarkRange(mark, snatPktMarkRange).
-		Action().GotoTable(l3DecTTLTable).
-		Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
-		Done()
+		Action().LoadPktMarkRange(mark, snatPktMarkRange)
+	if !metered {
+		return []binding.Flow{fb.Action().GotoTable(l3DecTTLTable).
+			Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+			Done()}
+	}
+	meterID := egressMeterID(mark)
+	return []binding.Flow{
+		fb.Action().Meter(meterID).
+			Action().GotoTable(l3DecTTLTable).
+			Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+			Done(),
+		snatTable.BuildFlow(priorityLow + 1).
+			MatchProtocol(ipProto).
+			MatchTunnelDst(snatIP).
+			Action().Meter(meterID).
+			Action().GotoTable(snatTable.GetNext()).
+			Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+			Done(),
+	}
 }
 
-// snatRuleFlow generates a flow that applies the SNAT rule for a local Pod. If
-// the SNAT IP exists on the local Node, it sets the packet mark with the ID of
-// the SNAT IP, for the traffic from the ofPort to external; if the SNAT IP is
-// on a remote Node, it tunnels the packets to the SNAT IP.
-func (c *client) snatRuleFlow(ofPort uint32, snatIP net.IP, snatMark uint32, localGatewayMAC net.HardwareAddr) binding.Flow {
+// snatRuleFlows generates the flows that apply the SNAT rule for a local Pod.
+// If the SNAT IP exists on the local Node, it sets the packet mark with the ID
+// of the SNAT IP, for the traffic from the ofPort to external, and sends the
+// traffic to the meter of the SNAT IP if metered is true; if the SNAT IP is on
+// a remote Node, it tunnels the packets to the SNAT IP.
+func (c *client) snatRuleFlows(ofPort uint32, snatIP net.IP, snatMark uint32, metered bool, localGatewayMAC net.HardwareAddr) []binding.Flow {
 	ipProto := getIPProtocol(snatIP)
 	snatTable := c.pipeline[snatTable]
 	if snatMark != 0 {
 		// Local SNAT IP.
-		return snatTable.BuildFlow(priorityNormal).
+		fb := snatTable.BuildFlow(priorityNormal).
 			MatchProtocol(ipProto).
 			MatchCTStateNew(true).MatchCTStateTrk(true).
 			MatchInPort(ofPort).
-			Action().LoadPktMarkRange(snatMark, snatPktMarkRange).
-			Action().GotoTable(snatTable.GetNext()).
-			Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
-			Done()
-	} else {
-		// SNAT IP should be on a remote Node.
-		return snatTable.BuildFlow(priorityNormal).
+			Action().LoadPktMarkRange(snatMark, snatPktMarkRange)
+		if !metered {
+			return []binding.Flow{fb.Action().GotoTable(snatTable.GetNext()).
+				Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+				Done()}
+		}
+		meterID := egressMeterID(snatMark)
+		return []binding.Flow{
+			fb.Action().Meter(meterID).
+				Action().GotoTable(snatTable.GetNext()).
+				Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+				Done(),
+			snatTable.BuildFlow(priorityLow + 1).
+				MatchProtocol(ipProto).
+				MatchInPort(ofPort).
+				Action().Meter(meterID).
+				Action().GotoTable(snatTable.GetNext()).
+				Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+				Done(),
+		}
+	}
+	// SNAT IP should be on a remote Node.
+	return []binding.Flow{snatTable.BuildFlow(priorityNormal).
+		MatchProtocol(ipProto).
+		MatchInPort(ofPort).
+		Action().SetSrcMAC(localGatewayMAC).
+		Action().SetDstMAC(globalVirtualMAC).
+		// Set tunnel destination to the SNAT IP.
+		Action().SetTunnelDst(snatIP).
+		Action().GotoTable(l3DecTTLTable).
+		Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
+		Done()}
+}
+
+// podDestinationSNAT is the SNAT configuration of an Egress with destinations
+// for a local Pod.
+type podDestinationSNAT struct {
+	snatIP       net.IP
+	snatMark     uint32
+	metered      bool
+	destinations []net.IPNet
+}
+
+// snatDestinationRuleFlows generates the flows that apply the SNAT rule of an
+// Egress with destinations for a local Pod. They work like snatRuleFlows, but
+// only for the traffic to the destination CIDRs. As the flows don't depend on
+// the connection state, a single flow per destination CIDR is enough even if
+// the packets are sent to the meter of the SNAT IP. The priority of the flows
+// increases with the prefix length of the destination CIDRs, so that the most
+// specific destination takes precedence. The destination CIDRs which are not
+// in the address family of the SNAT IP are ignored.
+func (c *client) snatDestinationRuleFlows(ofPort uint32, snatIP net.IP, snatMark uint32, metered bool, destinations []net.IPNet, localGatewayMAC net.HardwareAddr) []binding.Flow {
+	ipProto := getIPProtocol(snatIP)
+	snatTable := c.pipeline[snatTable]
+	var flows []binding.Flow
+	for i := range destinations {
+		destination := destinations[i]
+		if getIPProtocol(destination.IP) != ipProto {
+			continue
+		}
+		prefixLength, _ := destination.Mask.Size()
+		fb := snatTable.BuildFlow(priorityNormal + 1 + uint16(prefixLength)).
 			MatchProtocol(ipProto).
 			MatchInPort(ofPort).
-			Action().SetSrcMAC(localGatewayMAC).
-			Action().SetDstMAC(globalVirtualMAC).
-			// Set tunnel destination to the SNAT IP.
-			Action().SetTunnelDst(snatIP).
-			Action().GotoTable(l3DecTTLTable).
-			Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
-			Done()
+			MatchDstIPNet(destination)
+		if snatMark != 0 {
+			// Local SNAT IP.
+			fb = fb.Action().LoadPktMarkRange(snatMark, snatPktMarkRange)
+			if metered {
+				fb = fb.Action().Meter(egressMeterID(snatMark))
+			}
+			fb = fb.Action().GotoTable(snatTable.GetNext())
+		} else {
+			// SNAT IP should be on a remote Node.
+			fb = fb.Action().SetSrcMAC(localGatewayMAC).
+				Action().SetDstMAC(globalVirtualMAC).
+				// Set tunnel destination to the SNAT IP.
+				Action().SetTunnelDst(snatIP).
+				Action().GotoTable(l3DecTTLTable)
+		}
+		flows = append(flows, fb.Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).Done())
 	}
+	return flows
 }
 
 // loadBalancerServiceFromOutsideFlow generates the flow to forward LoadBalancer service traffic from outside node
@@ -2053,6 +2518,21 @@ func (c *client) hairpinSNATFlow(endpointIP net.IP) binding.Flow {
 		Done()
 }
 
+// WeightedEndpoint is a Service Endpoint with a specific weight for its bucket in the Service group.
+type WeightedEndpoint interface {
+	proxy.Endpoint
+	// GetWeight returns the weight of the bucket of the Endpoint.
+	GetWeight() uint16
+}
+
+// getEndpointBucketWeight returns the weight of the bucket of the Endpoint in the Service group.
+func getEndpointBucketWeight(endpoint proxy.Endpoint) uint16 {
+	if weightedEndpoint, ok := endpoint.(WeightedEndpoint); ok {
+		return weightedEndpoint.GetWeight()
+	}
+	return defaultServiceBucketWeight
+}
+
 // serviceEndpointGroup creates/modifies the group/buckets of Endpoints. If the
 // withSessionAffinity is true, then buckets will resubmit packets back to
 // serviceLBTable to trigger the learn flow, the learn flow will then send packets
@@ -2074,14 +2554,14 @@ func (c *client) serviceEndpointGroup(groupID binding.GroupIDType, withSessionAf
 		ipProtocol := getIPProtocol(endpointIP)
 		if ipProtocol == binding.ProtocolIP {
 			ipVal := binary.BigEndian.Uint32(endpointIP.To4())
-			group = group.Bucket().Weight(100).
+			group = group.Bucket().Weight(getEndpointBucketWeight(endpoint)).
 				LoadReg(int(endpointIPReg), ipVal).
 				LoadRegRange(int(endpointPortReg), uint32(portVal), endpointPortRegRange).
 				ResubmitToTable(resubmitTableID).
 				Done()
 		} else if ipProtocol == binding.ProtocolIPv6 {
 			ipVal := []byte(endpointIP)
-			group = group.Bucket().Weight(100).
+			group = group.Bucket().Weight(getEndpointBucketWeight(endpoint)).
 				LoadXXReg(int(endpointIPv6XXReg), ipVal).
 				LoadRegRange(int(endpointPortReg), uint32(portVal), endpointPortRegRange).
 				ResubmitToTable(resubmitTableID).
@@ -2144,6 +2624,45 @@ func (c *client) genPacketInMeter(meterID binding.MeterIDType, rate uint32) bind
 	return meter
 }
 
+// policyLoggingMeterID returns the ID of the meter which limits the rate of
+// the packets logged by the Antrea-native policy rule identified by
+// conjunctionID.
+func policyLoggingMeterID(conjunctionID uint32) uint32 {
+	return policyLoggingMeterIDBase + conjunctionID
+}
+
+// policyLoggingMeter generates the meter which limits the rate of the packets logged by the Antrea-native policy rule
+// identified by conjunctionID.
+func (c *client) policyLoggingMeter(conjunctionID uint32) binding.Meter {
+	meter := c.bridge.CreateMeter(binding.MeterIDType(policyLoggingMeterID(conjunctionID)), ofctrl.MeterBurst|ofctrl.MeterPktps).ResetMeterBands()
+	meter = meter.MeterBand().
+		MeterType(ofctrl.MeterDrop).
+		Rate(c.policyLoggingRate).
+		Burst(c.policyLoggingBurst).
+		Done()
+	return meter
+}
+
+// egressMeterID returns the ID of the meter which limits the bandwidth of the
+// Egress IP identified by the mark. The IDs don't overlap with the packet-in
+// meters.
+func egressMeterID(mark uint32) uint32 {
+	return egressMeterIDBase + mark
+}
+
+// genEgressMeter generates a meter entry for an Egress IP. `rate` is
+// represented in kbps and `burst` in kbits. Packets which exceed the rate
+// will be dropped.
+func (c *client) genEgressMeter(mark, rate, burst uint32) binding.Meter {
+	meter := c.bridge.CreateMeter(binding.MeterIDType(egressMeterID(mark)), ofctrl.MeterKbps|ofctrl.MeterBurst|ofctrl.MeterStats).ResetMeterBands()
+	meter = meter.MeterBand().
+		MeterType(ofctrl.MeterDrop).
+		Rate(rate).
+		Burst(burst).
+		Done()
+	return meter
+}
+
 func (c *client) generatePipeline() {
 	bridge := c.bridge
 	c.pipeline = map[binding.TableIDType]binding.Table{
@@ -2161,6 +2680,7 @@ func (c *client) generatePipeline() {
 		IngressDefaultTable:   bridge.CreateTable(IngressDefaultTable, IngressMetricTable, binding.TableMissActionNext),
 		IngressMetricTable:    bridge.CreateTable(IngressMetricTable, conntrackCommitTable, binding.TableMissActionNext),
 		L2ForwardingOutTable:  bridge.CreateTable(L2ForwardingOutTable, binding.LastTableID, binding.TableMissActionDrop),
+		PacketInTable:         bridge.CreateTable(PacketInTable, binding.LastTableID, binding.TableMissActionDrop),
 	}
 	if c.enableProxy {
 		c.pipeline[spoofGuardTable] = bridge.CreateTable(spoofGuardTable, serviceHairpinTable, binding.TableMissActionDrop)
@@ -2206,12 +2726,17 @@ func NewClient(bridgeName, mgmtAddr string, ovsDatapathType ovsconfig.OVSDatapat
 		enableDenyTracking:       enableDenyTracking,
 		enableEgress:             enableEgress,
 		nodeFlowCache:            newFlowCategoryCache(),
+		ipPoolFlowCache:          newFlowCategoryCache(),
 		podFlowCache:             newFlowCategoryCache(),
 		serviceFlowCache:         newFlowCategoryCache(),
 		tfFlowCache:              newFlowCategoryCache(),
+		dnsFlowCache:             newFlowCategoryCache(),
+		l7NPFlowCache:            newFlowCategoryCache(),
 		policyCache:              policyCache,
 		groupCache:               sync.Map{},
 		globalConjMatchFlowCache: map[string]*conjMatchFlowContext{},
+		tierPassBits:             newTierPassBitAllocator(),
+		podDestinationSNATs:      map[uint32]map[string]*podDestinationSNAT{},
 		packetInHandlers:         map[uint8]map[string]PacketInHandler{},
 		ovsctlClient:             ovsctl.NewClient(bridgeName),
 		ovsDatapathType:          ovsDatapathType,
@@ -198,12 +198,11 @@ func (c *client) externalFlows(nodeIP net.IP, localSubnet net.IPNet, localGatewa
 	return flows
 }
 
-func (c *client) snatMarkFlows(snatIP net.IP, mark uint32) []binding.Flow {
+func (c *client) snatMarkFlows(snatIP net.IP, mark uint32, metered bool) []binding.Flow {
 	snatIPRange := &binding.IPRange{StartIP: snatIP, EndIP: snatIP}
 	ctCommitTable := c.pipeline[conntrackCommitTable]
 	nextTable := ctCommitTable.GetNext()
-	flows := []binding.Flow{
-		c.snatIPFromTunnelFlow(snatIP, mark),
+	flows := append(c.snatIPFromTunnelFlows(snatIP, mark, metered),
 		ctCommitTable.BuildFlow(priorityNormal).
 			MatchProtocol(binding.ProtocolIP).
 			MatchCTStateNew(true).MatchCTStateTrk(true).MatchCTStateDNAT(false).
@@ -213,7 +212,7 @@ func (c *client) snatMarkFlows(snatIP net.IP, mark uint32) []binding.Flow {
 			LoadToMark(snatCTMark).CTDone().
 			Cookie(c.cookieAllocator.Request(cookie.SNAT).Raw()).
 			Done(),
-	}
+	)
 
 	if c.enableProxy {
 		flows = append(flows, ctCommitTable.BuildFlow(priorityNormal).
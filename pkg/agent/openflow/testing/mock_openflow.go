@@ -122,6 +122,21 @@ func (mr *MockClientMockRecorder) Disconnect() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disconnect", reflect.TypeOf((*MockClient)(nil).Disconnect))
 }
 
+// GetEgressMeterDroppedPackets mocks base method
+func (m *MockClient) GetEgressMeterDroppedPackets() (map[uint32]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEgressMeterDroppedPackets")
+	ret0, _ := ret[0].(map[uint32]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEgressMeterDroppedPackets indicates an expected call of GetEgressMeterDroppedPackets
+func (mr *MockClientMockRecorder) GetEgressMeterDroppedPackets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEgressMeterDroppedPackets", reflect.TypeOf((*MockClient)(nil).GetEgressMeterDroppedPackets))
+}
+
 // GetFlowTableStatus mocks base method
 func (m *MockClient) GetFlowTableStatus() []openflow.TableStatus {
 	m.ctrl.T.Helper()
@@ -278,6 +293,20 @@ func (mr *MockClientMockRecorder) InstallClusterServiceFlows() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallClusterServiceFlows", reflect.TypeOf((*MockClient)(nil).InstallClusterServiceFlows))
 }
 
+// InstallDNSInterceptionFlows mocks base method
+func (m *MockClient) InstallDNSInterceptionFlows() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallDNSInterceptionFlows")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallDNSInterceptionFlows indicates an expected call of InstallDNSInterceptionFlows
+func (mr *MockClientMockRecorder) InstallDNSInterceptionFlows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallDNSInterceptionFlows", reflect.TypeOf((*MockClient)(nil).InstallDNSInterceptionFlows))
+}
+
 // InstallDefaultTunnelFlows mocks base method
 func (m *MockClient) InstallDefaultTunnelFlows() error {
 	m.ctrl.T.Helper()
@@ -292,6 +321,20 @@ func (mr *MockClientMockRecorder) InstallDefaultTunnelFlows() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallDefaultTunnelFlows", reflect.TypeOf((*MockClient)(nil).InstallDefaultTunnelFlows))
 }
 
+// InstallEgressMeter mocks base method
+func (m *MockClient) InstallEgressMeter(arg0, arg1, arg2 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallEgressMeter", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallEgressMeter indicates an expected call of InstallEgressMeter
+func (mr *MockClientMockRecorder) InstallEgressMeter(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallEgressMeter", reflect.TypeOf((*MockClient)(nil).InstallEgressMeter), arg0, arg1, arg2)
+}
+
 // InstallEndpointFlows mocks base method
 func (m *MockClient) InstallEndpointFlows(arg0 openflow.Protocol, arg1 []proxy.Endpoint) error {
 	m.ctrl.T.Helper()
@@ -334,6 +377,34 @@ func (mr *MockClientMockRecorder) InstallGatewayFlows() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallGatewayFlows", reflect.TypeOf((*MockClient)(nil).InstallGatewayFlows))
 }
 
+// InstallIPPoolGatewayFlows mocks base method
+func (m *MockClient) InstallIPPoolGatewayFlows(arg0 []net.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallIPPoolGatewayFlows", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallIPPoolGatewayFlows indicates an expected call of InstallIPPoolGatewayFlows
+func (mr *MockClientMockRecorder) InstallIPPoolGatewayFlows(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallIPPoolGatewayFlows", reflect.TypeOf((*MockClient)(nil).InstallIPPoolGatewayFlows), arg0)
+}
+
+// InstallL7NetworkPolicyFlows mocks base method
+func (m *MockClient) InstallL7NetworkPolicyFlows(arg0, arg1 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallL7NetworkPolicyFlows", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallL7NetworkPolicyFlows indicates an expected call of InstallL7NetworkPolicyFlows
+func (mr *MockClientMockRecorder) InstallL7NetworkPolicyFlows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallL7NetworkPolicyFlows", reflect.TypeOf((*MockClient)(nil).InstallL7NetworkPolicyFlows), arg0, arg1)
+}
+
 // InstallLoadBalancerServiceFromOutsideFlows mocks base method
 func (m *MockClient) InstallLoadBalancerServiceFromOutsideFlows(arg0 net.IP, arg1 uint16, arg2 openflow.Protocol) error {
 	m.ctrl.T.Helper()
@@ -362,6 +433,20 @@ func (mr *MockClientMockRecorder) InstallNodeFlows(arg0, arg1, arg2, arg3, arg4
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallNodeFlows", reflect.TypeOf((*MockClient)(nil).InstallNodeFlows), arg0, arg1, arg2, arg3, arg4)
 }
 
+// InstallPodDestinationSNATFlows mocks base method
+func (m *MockClient) InstallPodDestinationSNATFlows(arg0 uint32, arg1 string, arg2 net.IP, arg3 uint32, arg4 bool, arg5 []net.IPNet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallPodDestinationSNATFlows", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallPodDestinationSNATFlows indicates an expected call of InstallPodDestinationSNATFlows
+func (mr *MockClientMockRecorder) InstallPodDestinationSNATFlows(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallPodDestinationSNATFlows", reflect.TypeOf((*MockClient)(nil).InstallPodDestinationSNATFlows), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // InstallPodFlows mocks base method
 func (m *MockClient) InstallPodFlows(arg0 string, arg1 []net.IP, arg2 net.HardwareAddr, arg3 uint32) error {
 	m.ctrl.T.Helper()
@@ -377,17 +462,31 @@ func (mr *MockClientMockRecorder) InstallPodFlows(arg0, arg1, arg2, arg3 interfa
 }
 
 // InstallPodSNATFlows mocks base method
-func (m *MockClient) InstallPodSNATFlows(arg0 uint32, arg1 net.IP, arg2 uint32) error {
+func (m *MockClient) InstallPodSNATFlows(arg0 uint32, arg1 net.IP, arg2 uint32, arg3 bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InstallPodSNATFlows", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "InstallPodSNATFlows", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InstallPodSNATFlows indicates an expected call of InstallPodSNATFlows
-func (mr *MockClientMockRecorder) InstallPodSNATFlows(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) InstallPodSNATFlows(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallPodSNATFlows", reflect.TypeOf((*MockClient)(nil).InstallPodSNATFlows), arg0, arg1, arg2, arg3)
+}
+
+// InstallPolicyLoggingMeters mocks base method
+func (m *MockClient) InstallPolicyLoggingMeters(arg0, arg1 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstallPolicyLoggingMeters", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InstallPolicyLoggingMeters indicates an expected call of InstallPolicyLoggingMeters
+func (mr *MockClientMockRecorder) InstallPolicyLoggingMeters(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallPodSNATFlows", reflect.TypeOf((*MockClient)(nil).InstallPodSNATFlows), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallPolicyLoggingMeters", reflect.TypeOf((*MockClient)(nil).InstallPolicyLoggingMeters), arg0, arg1)
 }
 
 // InstallPolicyRuleFlows mocks base method
@@ -405,17 +504,17 @@ func (mr *MockClientMockRecorder) InstallPolicyRuleFlows(arg0 interface{}) *gomo
 }
 
 // InstallSNATMarkFlows mocks base method
-func (m *MockClient) InstallSNATMarkFlows(arg0 net.IP, arg1 uint32) error {
+func (m *MockClient) InstallSNATMarkFlows(arg0 net.IP, arg1 uint32, arg2 bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InstallSNATMarkFlows", arg0, arg1)
+	ret := m.ctrl.Call(m, "InstallSNATMarkFlows", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InstallSNATMarkFlows indicates an expected call of InstallSNATMarkFlows
-func (mr *MockClientMockRecorder) InstallSNATMarkFlows(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) InstallSNATMarkFlows(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallSNATMarkFlows", reflect.TypeOf((*MockClient)(nil).InstallSNATMarkFlows), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstallSNATMarkFlows", reflect.TypeOf((*MockClient)(nil).InstallSNATMarkFlows), arg0, arg1, arg2)
 }
 
 // InstallServiceFlows mocks base method
@@ -596,6 +695,20 @@ func (mr *MockClientMockRecorder) SendTraceflowPacket(arg0, arg1, arg2, arg3 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTraceflowPacket", reflect.TypeOf((*MockClient)(nil).SendTraceflowPacket), arg0, arg1, arg2, arg3)
 }
 
+// SendUDPPacketOut mocks base method
+func (m *MockClient) SendUDPPacketOut(arg0, arg1, arg2, arg3 string, arg4 uint32, arg5 int32, arg6 bool, arg7, arg8 uint16, arg9 []byte, arg10 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendUDPPacketOut", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendUDPPacketOut indicates an expected call of SendUDPPacketOut
+func (mr *MockClientMockRecorder) SendUDPPacketOut(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendUDPPacketOut", reflect.TypeOf((*MockClient)(nil).SendUDPPacketOut), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10)
+}
+
 // StartPacketInHandler mocks base method
 func (m *MockClient) StartPacketInHandler(arg0 []byte, arg1 <-chan struct{}) {
 	m.ctrl.T.Helper()
@@ -622,6 +735,34 @@ func (mr *MockClientMockRecorder) SubscribePacketIn(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribePacketIn", reflect.TypeOf((*MockClient)(nil).SubscribePacketIn), arg0, arg1)
 }
 
+// UninstallDNSInterceptionFlows mocks base method
+func (m *MockClient) UninstallDNSInterceptionFlows() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallDNSInterceptionFlows")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallDNSInterceptionFlows indicates an expected call of UninstallDNSInterceptionFlows
+func (mr *MockClientMockRecorder) UninstallDNSInterceptionFlows() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallDNSInterceptionFlows", reflect.TypeOf((*MockClient)(nil).UninstallDNSInterceptionFlows))
+}
+
+// UninstallEgressMeter mocks base method
+func (m *MockClient) UninstallEgressMeter(arg0 uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallEgressMeter", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallEgressMeter indicates an expected call of UninstallEgressMeter
+func (mr *MockClientMockRecorder) UninstallEgressMeter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallEgressMeter", reflect.TypeOf((*MockClient)(nil).UninstallEgressMeter), arg0)
+}
+
 // UninstallEndpointFlows mocks base method
 func (m *MockClient) UninstallEndpointFlows(arg0 openflow.Protocol, arg1 proxy.Endpoint) error {
 	m.ctrl.T.Helper()
@@ -664,6 +805,20 @@ func (mr *MockClientMockRecorder) UninstallNodeFlows(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallNodeFlows", reflect.TypeOf((*MockClient)(nil).UninstallNodeFlows), arg0)
 }
 
+// UninstallPodDestinationSNATFlows mocks base method
+func (m *MockClient) UninstallPodDestinationSNATFlows(arg0 uint32, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UninstallPodDestinationSNATFlows", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UninstallPodDestinationSNATFlows indicates an expected call of UninstallPodDestinationSNATFlows
+func (mr *MockClientMockRecorder) UninstallPodDestinationSNATFlows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UninstallPodDestinationSNATFlows", reflect.TypeOf((*MockClient)(nil).UninstallPodDestinationSNATFlows), arg0, arg1)
+}
+
 // UninstallPodFlows mocks base method
 func (m *MockClient) UninstallPodFlows(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,136 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// serviceHealthServer serves the healthCheckNodePorts of the Services whose externalTrafficPolicy is Local, so that
+// the external load balancers only send traffic to the Nodes which run Endpoints of these Services. The responses are
+// the same as the ones of kube-proxy: 200 if the Service has local Endpoints, 503 otherwise.
+type serviceHealthServer struct {
+	// network is "tcp4" or "tcp6", the health checks are served on all the addresses of the IP family of the proxier.
+	network string
+	// listen is used to create the listeners, it can be overridden in tests.
+	listen   func(network, address string) (net.Listener, error)
+	mutex    sync.Mutex
+	services map[apimachinerytypes.NamespacedName]*healthCheckService
+}
+
+type healthCheckService struct {
+	port           uint16
+	server         *http.Server
+	localEndpoints int
+}
+
+type healthCheckResponse struct {
+	Service        healthCheckServiceName `json:"service"`
+	LocalEndpoints int                    `json:"localEndpoints"`
+}
+
+type healthCheckServiceName struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func newServiceHealthServer(isIPv6 bool) *serviceHealthServer {
+	network := "tcp4"
+	if isIPv6 {
+		network = "tcp6"
+	}
+	return &serviceHealthServer{
+		network:  network,
+		listen:   net.Listen,
+		services: map[apimachinerytypes.NamespacedName]*healthCheckService{},
+	}
+}
+
+// SyncServices starts serving the given healthCheckNodePorts, and stops serving the ones which are not given anymore.
+func (s *serviceHealthServer) SyncServices(healthCheckNodePorts map[apimachinerytypes.NamespacedName]uint16) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for name, svc := range s.services {
+		if port, ok := healthCheckNodePorts[name]; !ok || port != svc.port {
+			klog.V(2).Infof("Stopping health check of Service %s on port %d", name, svc.port)
+			svc.server.Close()
+			delete(s.services, name)
+		}
+	}
+	for name, port := range healthCheckNodePorts {
+		if _, ok := s.services[name]; ok {
+			continue
+		}
+		listener, err := s.listen(s.network, net.JoinHostPort("", strconv.Itoa(int(port))))
+		if err != nil {
+			// The Service will be retried at the next sync.
+			klog.Errorf("Error when listening on health check port %d of Service %s: %v", port, name, err)
+			continue
+		}
+		klog.V(2).Infof("Starting health check of Service %s on port %d", name, port)
+		name := name
+		svc := &healthCheckService{port: port}
+		svc.server = &http.Server{Handler: s.handler(name)}
+		s.services[name] = svc
+		go func() {
+			if err := svc.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				klog.Errorf("Error when serving health check of Service %s: %v", name, err)
+			}
+		}()
+	}
+}
+
+// SyncEndpoints updates the numbers of local Endpoints of the Services. The Services which are not given have no
+// local Endpoint.
+func (s *serviceHealthServer) SyncEndpoints(localEndpoints map[apimachinerytypes.NamespacedName]int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for name, svc := range s.services {
+		svc.localEndpoints = localEndpoints[name]
+	}
+}
+
+func (s *serviceHealthServer) handler(name apimachinerytypes.NamespacedName) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		var count int
+		if svc, ok := s.services[name]; ok {
+			count = svc.localEndpoints
+		}
+		s.mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if count == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		response := healthCheckResponse{
+			Service:        healthCheckServiceName{Namespace: name.Namespace, Name: name.Name},
+			LocalEndpoints: count,
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			klog.Errorf("Error when writing health check response of Service %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimachinerytypes "k8s.io/apimachinery/pkg/types"
+)
+
+// newTestServiceHealthServer returns a serviceHealthServer which listens on random loopback ports, and the addresses
+// of its listeners indexed by the requested health check ports.
+func newTestServiceHealthServer() (*serviceHealthServer, map[uint16]string) {
+	s := newServiceHealthServer(false)
+	addresses := map[uint16]string{}
+	s.listen = func(network, address string) (net.Listener, error) {
+		_, portStr, _ := net.SplitHostPort(address)
+		var port uint16
+		fmt.Sscanf(portStr, "%d", &port)
+		listener, err := net.Listen(network, "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		addresses[port] = listener.Addr().String()
+		return listener, nil
+	}
+	return s, addresses
+}
+
+func getHealthCheck(t *testing.T, address string) (int, healthCheckResponse) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/", address))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var response healthCheckResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	return resp.StatusCode, response
+}
+
+func TestServiceHealthServer(t *testing.T) {
+	s, addresses := newTestServiceHealthServer()
+	svc1 := apimachinerytypes.NamespacedName{Namespace: "ns1", Name: "svc1"}
+	svc2 := apimachinerytypes.NamespacedName{Namespace: "ns1", Name: "svc2"}
+	s.SyncServices(map[apimachinerytypes.NamespacedName]uint16{svc1: 30001, svc2: 30002})
+	defer s.SyncServices(nil)
+	s.SyncEndpoints(map[apimachinerytypes.NamespacedName]int{svc1: 2})
+	require.Len(t, addresses, 2)
+
+	status, response := getHealthCheck(t, addresses[30001])
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, healthCheckResponse{Service: healthCheckServiceName{Namespace: "ns1", Name: "svc1"}, LocalEndpoints: 2}, response)
+	status, response = getHealthCheck(t, addresses[30002])
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, 0, response.LocalEndpoints)
+
+	// The local Endpoints of svc1 are removed.
+	s.SyncEndpoints(map[apimachinerytypes.NamespacedName]int{})
+	status, _ = getHealthCheck(t, addresses[30001])
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	// svc1 is removed and the health check port of svc2 is changed.
+	s.SyncServices(map[apimachinerytypes.NamespacedName]uint16{svc2: 30003})
+	assert.Len(t, s.services, 1)
+	assert.Equal(t, uint16(30003), s.services[svc2].port)
+	_, err := http.Get(fmt.Sprintf("http://%s/", addresses[30001]))
+	assert.Error(t, err)
+	_, err = http.Get(fmt.Sprintf("http://%s/", addresses[30002]))
+	assert.Error(t, err)
+	status, response = getHealthCheck(t, addresses[30003])
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "svc2", response.Service.Name)
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	"antrea.io/antrea/pkg/agent/proxy/types"
+	k8sproxy "antrea.io/antrea/third_party/proxy"
+)
+
+// maglevTableSize is the size of the Maglev lookup table, i.e. the number of buckets of the group of a Service in
+// ConsistentHash mode. It must be a prime number. The Endpoint selected by a connection is determined by the bucket
+// selected by OVS, which only depends on the hash of the connection and the number of buckets when all the buckets
+// have the same weight. As the number of buckets is constant, only the connections hashed to the buckets whose
+// Endpoint has changed in the lookup table are remapped when Endpoints are added or removed.
+const maglevTableSize = 251
+
+// weightedEndpoint is an Endpoint with the weight of its bucket in the group of the Service. It implements the
+// openflow.WeightedEndpoint interface.
+type weightedEndpoint struct {
+	k8sproxy.Endpoint
+	weight uint16
+}
+
+// GetWeight returns the weight of the bucket of the Endpoint.
+func (e *weightedEndpoint) GetWeight() uint16 {
+	return e.weight
+}
+
+// getLoadBalancingMode returns the LoadBalancingMode of the Service, which is set by the Service annotation or
+// defaults to the LoadBalancingMode configured for the proxier.
+func (p *proxier) getLoadBalancingMode(svcInfo *types.ServiceInfo) types.LoadBalancingMode {
+	if svcInfo.LoadBalancingMode != "" {
+		return svcInfo.LoadBalancingMode
+	}
+	return p.defaultLoadBalancingMode
+}
+
+// loadBalancingChanged returns whether the Endpoints of the Service need to be re-installed in the group because the
+// load balancing configuration of the Service has changed.
+func (p *proxier) loadBalancingChanged(svcInfo, pSvcInfo *types.ServiceInfo) bool {
+	mode := p.getLoadBalancingMode(svcInfo)
+	if mode != p.getLoadBalancingMode(pSvcInfo) {
+		return true
+	}
+	return mode == types.LoadBalancingModeWeighted && svcInfo.LocalEndpointWeight != pSvcInfo.LocalEndpointWeight
+}
+
+// getGroupEndpoints returns the Endpoints which are installed as the buckets of the group of the Service, according
+// to its LoadBalancingMode.
+func (p *proxier) getGroupEndpoints(svcInfo *types.ServiceInfo, endpoints []k8sproxy.Endpoint) []k8sproxy.Endpoint {
+	switch p.getLoadBalancingMode(svcInfo) {
+	case types.LoadBalancingModeWeighted:
+		return weightEndpoints(endpoints, svcInfo.LocalEndpointWeight)
+	case types.LoadBalancingModeConsistentHash:
+		if len(endpoints) > maglevTableSize {
+			klog.Warningf("Falling back to Random load balancing mode for Service %s since its Endpoints exceed %d", svcInfo.String(), maglevTableSize)
+			return endpoints
+		}
+		return newMaglevTable(endpoints, maglevTableSize)
+	}
+	return endpoints
+}
+
+// weightEndpoints returns the Endpoints with their weights in Weighted mode: local Endpoints have localEndpointWeight
+// and remote Endpoints have types.RemoteEndpointWeight.
+func weightEndpoints(endpoints []k8sproxy.Endpoint, localEndpointWeight uint16) []k8sproxy.Endpoint {
+	weightedEndpoints := make([]k8sproxy.Endpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		weight := types.RemoteEndpointWeight
+		if endpoint.GetIsLocal() {
+			weight = localEndpointWeight
+		}
+		weightedEndpoints = append(weightedEndpoints, &weightedEndpoint{Endpoint: endpoint, weight: weight})
+	}
+	return weightedEndpoints
+}
+
+// newMaglevTable populates a lookup table of the given size with the Endpoints, following the algorithm described in
+// "Maglev: A Fast and Reliable Software Network Load Balancer". Each Endpoint occupies nearly the same number of
+// entries in the table, and adding or removing an Endpoint only changes a small part of the entries. The Endpoints
+// are sorted first so that the table only depends on the set of Endpoints. The number of Endpoints must not exceed
+// the size of the table.
+func newMaglevTable(endpoints []k8sproxy.Endpoint, size int) []k8sproxy.Endpoint {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	sortedEndpoints := make([]k8sproxy.Endpoint, len(endpoints))
+	copy(sortedEndpoints, endpoints)
+	sort.Sort(byEndpoint(sortedEndpoints))
+
+	offsets := make([]int, len(sortedEndpoints))
+	skips := make([]int, len(sortedEndpoints))
+	for i, endpoint := range sortedEndpoints {
+		offsets[i], skips[i] = maglevPermutation(endpoint.String(), size)
+	}
+
+	table := make([]k8sproxy.Endpoint, size)
+	next := make([]int, len(sortedEndpoints))
+	filled := 0
+	for {
+		for i := range sortedEndpoints {
+			// Find the next preferred entry of the Endpoint which is not occupied yet.
+			entry := (offsets[i] + next[i]*skips[i]) % size
+			for table[entry] != nil {
+				next[i]++
+				entry = (offsets[i] + next[i]*skips[i]) % size
+			}
+			table[entry] = sortedEndpoints[i]
+			next[i]++
+			filled++
+			if filled == size {
+				return table
+			}
+		}
+	}
+}
+
+// maglevPermutation returns the offset and the skip which determine the preference list of an Endpoint in the Maglev
+// lookup table.
+func maglevPermutation(name string, size int) (int, int) {
+	h1 := fnv.New32a()
+	h1.Write([]byte(name))
+	h2 := fnv.New32()
+	h2.Write([]byte(name))
+	offset := int(h1.Sum32() % uint32(size))
+	skip := int(h2.Sum32()%uint32(size-1)) + 1
+	return offset, skip
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"antrea.io/antrea/pkg/agent/openflow"
+	"antrea.io/antrea/pkg/agent/proxy/types"
+	k8sproxy "antrea.io/antrea/third_party/proxy"
+)
+
+func makeTestEndpointList(count int) []k8sproxy.Endpoint {
+	var endpoints []k8sproxy.Endpoint
+	for i := 0; i < count; i++ {
+		endpoints = append(endpoints, k8sproxy.NewBaseEndpointInfo(fmt.Sprintf("10.10.%d.%d", i/250, i%250+1), 80, false, nil, nil))
+	}
+	return endpoints
+}
+
+func TestWeightEndpoints(t *testing.T) {
+	localEndpoint := k8sproxy.NewBaseEndpointInfo("10.10.0.1", 80, true, nil, nil)
+	remoteEndpoint := k8sproxy.NewBaseEndpointInfo("10.10.1.1", 80, false, nil, nil)
+	weighted := weightEndpoints([]k8sproxy.Endpoint{localEndpoint, remoteEndpoint}, 300)
+	require.Len(t, weighted, 2)
+	assert.Equal(t, uint16(300), weighted[0].(openflow.WeightedEndpoint).GetWeight())
+	assert.Equal(t, types.RemoteEndpointWeight, weighted[1].(openflow.WeightedEndpoint).GetWeight())
+	assert.Equal(t, localEndpoint.String(), weighted[0].String())
+	assert.Equal(t, remoteEndpoint.String(), weighted[1].String())
+}
+
+func TestMaglevTable(t *testing.T) {
+	endpoints := makeTestEndpointList(10)
+	table := newMaglevTable(endpoints, maglevTableSize)
+	require.Len(t, table, maglevTableSize)
+
+	// Every Endpoint occupies nearly the same number of entries.
+	counts := map[string]int{}
+	for _, endpoint := range table {
+		require.NotNil(t, endpoint)
+		counts[endpoint.String()]++
+	}
+	assert.Len(t, counts, len(endpoints))
+	for _, count := range counts {
+		assert.InDelta(t, maglevTableSize/len(endpoints), count, 5)
+	}
+
+	// The table doesn't depend on the order of the Endpoints.
+	reversed := make([]k8sproxy.Endpoint, len(endpoints))
+	for i := range endpoints {
+		reversed[len(endpoints)-1-i] = endpoints[i]
+	}
+	assert.Equal(t, table, newMaglevTable(reversed, maglevTableSize))
+
+	// Removing an Endpoint only remaps a small part of the entries, besides the ones of the removed Endpoint.
+	removed := endpoints[3]
+	newTable := newMaglevTable(append(append([]k8sproxy.Endpoint{}, endpoints[:3]...), endpoints[4:]...), maglevTableSize)
+	remapped := 0
+	for i := range table {
+		if table[i] != removed && newTable[i] != table[i] {
+			remapped++
+		}
+	}
+	assert.Less(t, remapped, maglevTableSize/10)
+
+	assert.Nil(t, newMaglevTable(nil, maglevTableSize))
+}
+
+func TestGetGroupEndpoints(t *testing.T) {
+	fp := NewFakeProxier(nil, false)
+	endpoints := makeTestEndpointList(3)
+	tests := []struct {
+		name          string
+		defaultMode   types.LoadBalancingMode
+		svcMode       types.LoadBalancingMode
+		endpoints     []k8sproxy.Endpoint
+		expectedCount int
+		expectWeight  bool
+	}{
+		{
+			name:          "Random by default",
+			defaultMode:   types.LoadBalancingModeRandom,
+			endpoints:     endpoints,
+			expectedCount: 3,
+		},
+		{
+			name:          "Weighted set by annotation",
+			defaultMode:   types.LoadBalancingModeRandom,
+			svcMode:       types.LoadBalancingModeWeighted,
+			endpoints:     endpoints,
+			expectedCount: 3,
+			expectWeight:  true,
+		},
+		{
+			name:          "ConsistentHash by default",
+			defaultMode:   types.LoadBalancingModeConsistentHash,
+			endpoints:     endpoints,
+			expectedCount: maglevTableSize,
+		},
+		{
+			name:          "ConsistentHash falling back with too many Endpoints",
+			defaultMode:   types.LoadBalancingModeConsistentHash,
+			endpoints:     makeTestEndpointList(maglevTableSize + 1),
+			expectedCount: maglevTableSize + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp.defaultLoadBalancingMode = tt.defaultMode
+			svcInfo := &types.ServiceInfo{
+				BaseServiceInfo:     &k8sproxy.BaseServiceInfo{},
+				LoadBalancingMode:   tt.svcMode,
+				LocalEndpointWeight: types.DefaultLocalEndpointWeight,
+			}
+			groupEndpoints := fp.getGroupEndpoints(svcInfo, tt.endpoints)
+			assert.Len(t, groupEndpoints, tt.expectedCount)
+			_, isWeighted := groupEndpoints[0].(openflow.WeightedEndpoint)
+			assert.Equal(t, tt.expectWeight, isWeighted)
+		})
+	}
+}
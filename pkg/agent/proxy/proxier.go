@@ -17,6 +17,7 @@ package proxy
 import (
 	"fmt"
 	"net"
+	"reflect"
 	"sort"
 	"strings"
 	"sync"
@@ -28,13 +29,17 @@ import (
 	k8sapitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 
+	agentconfig "antrea.io/antrea/pkg/agent/config"
 	"antrea.io/antrea/pkg/agent/openflow"
 	"antrea.io/antrea/pkg/agent/proxy/metrics"
 	"antrea.io/antrea/pkg/agent/proxy/types"
+	"antrea.io/antrea/pkg/agent/route"
 	"antrea.io/antrea/pkg/features"
 	binding "antrea.io/antrea/pkg/ovs/openflow"
 	k8sproxy "antrea.io/antrea/third_party/proxy"
@@ -102,8 +107,24 @@ type proxier struct {
 	runner              *k8sproxy.BoundedFrequencyRunner
 	stopChan            <-chan struct{}
 	ofClient            openflow.Client
+	routeClient         route.Interface
 	isIPv6              bool
 	enableEndpointSlice bool
+	// proxyNodePort indicates whether the NodePort Services are handled by AntreaProxy.
+	proxyNodePort bool
+	// nodePortAddresses contains the IP addresses of this Node on which the NodePort Services are exposed.
+	nodePortAddresses []net.IP
+	// serviceHealthServer serves the healthCheckNodePorts of the Services, it's only set when proxyNodePort is true.
+	serviceHealthServer *serviceHealthServer
+	// topologyAwareHintsEnabled indicates whether the Endpoints are filtered based on the topology hints of
+	// EndpointSlices.
+	topologyAwareHintsEnabled bool
+	hostname                  string
+	nodeLister                corelisters.NodeLister
+	// nodeLabels stores the labels of this Node, which are used to filter the Endpoints based on topology hints.
+	nodeLabels map[string]string
+	// defaultLoadBalancingMode is the LoadBalancingMode of the Services which don't set it with annotation.
+	defaultLoadBalancingMode types.LoadBalancingMode
 }
 
 func endpointKey(endpoint k8sproxy.Endpoint, protocol binding.Protocol) string {
@@ -132,6 +153,12 @@ func (p *proxier) removeStaleServices() {
 			klog.Errorf("Failed to remove flows of Service %v: %v", svcPortName, err)
 			continue
 		}
+		if p.proxyNodePort && svcInfo.NodePort() > 0 {
+			if err := p.uninstallNodePortService(svcPortName, svcInfo); err != nil {
+				klog.Errorf("Failed to remove NodePort of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
 		for _, ingress := range svcInfo.LoadBalancerIPStrings() {
 			if ingress != "" {
 				if err := p.uninstallLoadBalancerServiceFlows(net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol); err != nil {
@@ -140,15 +167,103 @@ func (p *proxier) removeStaleServices() {
 				}
 			}
 		}
-		groupID, _ := p.groupCounter.Get(svcPortName)
+		if svcInfo.NodeLocalInternal() {
+			if err := p.uninstallExternalServiceGroup(svcPortName); err != nil {
+				klog.Errorf("Failed to remove flows of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
+		groupID, _ := p.groupCounter.Get(svcPortName, types.InternalGroup)
 		if err := p.ofClient.UninstallServiceGroup(groupID); err != nil {
 			klog.Errorf("Failed to remove flows of Service %v: %v", svcPortName, err)
 			continue
 		}
 		delete(p.serviceInstalledMap, svcPortName)
 		p.deleteServiceByIP(svcInfo.String())
-		p.groupCounter.Recycle(svcPortName)
+		p.groupCounter.Recycle(svcPortName, types.InternalGroup)
+	}
+}
+
+// virtualNodePortDNATIP returns the virtual IP to which the NodePort traffic is DNAT'd on the host.
+func (p *proxier) virtualNodePortDNATIP() net.IP {
+	if p.isIPv6 {
+		return agentconfig.VirtualNodePortDNATIPv6
+	}
+	return agentconfig.VirtualNodePortDNATIPv4
+}
+
+// nodePortChanged returns true if the NodePort, or any attribute which affects how the NodePort traffic is handled,
+// of the Service has changed.
+func nodePortChanged(svcInfo, pSvcInfo *types.ServiceInfo) bool {
+	return svcInfo.NodePort() != pSvcInfo.NodePort() ||
+		svcInfo.OFProtocol != pSvcInfo.OFProtocol ||
+		svcInfo.NodeLocalExternal() != pSvcInfo.NodeLocalExternal() ||
+		svcInfo.NodeLocalInternal() != pSvcInfo.NodeLocalInternal()
+}
+
+// uninstallExternalServiceGroup removes the group used by the external traffic of the Service, which is only created
+// when the internalTrafficPolicy of the Service is Local.
+func (p *proxier) uninstallExternalServiceGroup(svcPortName k8sproxy.ServicePortName) error {
+	externalGroupID, _ := p.groupCounter.Get(svcPortName, types.ExternalGroup)
+	if err := p.ofClient.UninstallServiceGroup(externalGroupID); err != nil {
+		return fmt.Errorf("error when removing external Endpoints group: %v", err)
+	}
+	p.groupCounter.Recycle(svcPortName, types.ExternalGroup)
+	return nil
+}
+
+// installNodePortService installs the flows for the NodePort of the Service, and adds the NodePort to the host so that
+// the NodePort traffic is DNAT'd to the virtual NodePort DNAT IP and then forwarded to OVS. If the
+// externalTrafficPolicy of the Service is Local, the NodePort traffic is only load-balanced to the local Endpoints with
+// a dedicated group, and its source IP is preserved. Otherwise, it is load-balanced with externalGroupID.
+func (p *proxier) installNodePortService(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo, externalGroupID binding.GroupIDType, endpoints []k8sproxy.Endpoint, needUpdateService, needUpdateEndpoints bool) error {
+	nodePortGroupID := externalGroupID
+	if svcInfo.NodeLocalExternal() {
+		localGroupID, isNew := p.groupCounter.Get(svcPortName, types.LocalGroup)
+		if isNew || needUpdateEndpoints {
+			var localEndpoints []k8sproxy.Endpoint
+			for _, endpoint := range endpoints {
+				if endpoint.GetIsLocal() {
+					localEndpoints = append(localEndpoints, endpoint)
+				}
+			}
+			if err := p.ofClient.InstallServiceGroup(localGroupID, svcInfo.StickyMaxAgeSeconds() != 0, p.getGroupEndpoints(svcInfo, localEndpoints)); err != nil {
+				return fmt.Errorf("error when installing local Endpoints group: %v", err)
+			}
+		}
+		nodePortGroupID = localGroupID
 	}
+	if !needUpdateService {
+		return nil
+	}
+	nodePort := uint16(svcInfo.NodePort())
+	if err := p.ofClient.InstallServiceFlows(nodePortGroupID, p.virtualNodePortDNATIP(), nodePort, svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
+		return fmt.Errorf("error when installing NodePort Service flows: %v", err)
+	}
+	if err := p.routeClient.AddNodePort(p.nodePortAddresses, nodePort, svcInfo.OFProtocol, svcInfo.NodeLocalExternal()); err != nil {
+		return fmt.Errorf("error when adding NodePort to the host: %v", err)
+	}
+	return nil
+}
+
+// uninstallNodePortService removes the flows, the local Endpoints group and the host configurations for the NodePort
+// of the Service.
+func (p *proxier) uninstallNodePortService(svcPortName k8sproxy.ServicePortName, svcInfo *types.ServiceInfo) error {
+	nodePort := uint16(svcInfo.NodePort())
+	if err := p.routeClient.DeleteNodePort(p.nodePortAddresses, nodePort, svcInfo.OFProtocol); err != nil {
+		return fmt.Errorf("error when deleting NodePort from the host: %v", err)
+	}
+	if err := p.ofClient.UninstallServiceFlows(p.virtualNodePortDNATIP(), nodePort, svcInfo.OFProtocol); err != nil {
+		return fmt.Errorf("error when removing NodePort Service flows: %v", err)
+	}
+	if svcInfo.NodeLocalExternal() {
+		localGroupID, _ := p.groupCounter.Get(svcPortName, types.LocalGroup)
+		if err := p.ofClient.UninstallServiceGroup(localGroupID); err != nil {
+			return fmt.Errorf("error when removing local Endpoints group: %v", err)
+		}
+		p.groupCounter.Recycle(svcPortName, types.LocalGroup)
+	}
+	return nil
 }
 
 func getBindingProtoForIPProto(endpointIP string, protocol corev1.Protocol) binding.Protocol {
@@ -241,10 +356,31 @@ func smallSliceDifference(s1, s2 []string) []string {
 	return diff
 }
 
-func (p *proxier) installServices() {
+// syncNodeLabels updates the cached labels of this Node. It returns true if the labels have changed.
+func (p *proxier) syncNodeLabels() bool {
+	if p.nodeLister == nil {
+		return false
+	}
+	node, err := p.nodeLister.Get(p.hostname)
+	if err != nil {
+		klog.Errorf("Failed to get Node %s: %v", p.hostname, err)
+		return false
+	}
+	if reflect.DeepEqual(node.Labels, p.nodeLabels) {
+		return false
+	}
+	klog.V(2).Infof("Labels of Node %s changed: %v", p.hostname, node.Labels)
+	p.nodeLabels = node.Labels
+	return true
+}
+
+// installServices installs the flows and groups of all the expected Services. If forceUpdateEndpoints is true, the
+// Endpoints and groups of all the Services will be updated even if the Endpoints have not changed, e.g. when the
+// topology of this Node has changed.
+func (p *proxier) installServices(forceUpdateEndpoints bool) {
 	for svcPortName, svcPort := range p.serviceMap {
 		svcInfo := svcPort.(*types.ServiceInfo)
-		groupID, _ := p.groupCounter.Get(svcPortName)
+		groupID, _ := p.groupCounter.Get(svcPortName, types.InternalGroup)
 		endpointsInstalled, ok := p.endpointsInstalledMap[svcPortName]
 		if !ok {
 			endpointsInstalled = map[string]k8sproxy.Endpoint{}
@@ -258,15 +394,24 @@ func (p *proxier) installServices() {
 
 		installedSvcPort, ok := p.serviceInstalledMap[svcPortName]
 		var pSvcInfo *types.ServiceInfo
-		var needRemoval, needUpdateService, needUpdateEndpoints bool
+		var needRemoval, needUpdateService, needUpdateEndpoints, internalTrafficPolicyChanged bool
 		if ok { // Need to update.
 			pSvcInfo = installedSvcPort.(*types.ServiceInfo)
 			needRemoval = serviceIdentityChanged(svcInfo, pSvcInfo) || (svcInfo.SessionAffinityType() != pSvcInfo.SessionAffinityType())
 			needUpdateService = needRemoval || (svcInfo.StickyMaxAgeSeconds() != pSvcInfo.StickyMaxAgeSeconds())
-			needUpdateEndpoints = pSvcInfo.SessionAffinityType() != svcInfo.SessionAffinityType()
+			needUpdateEndpoints = pSvcInfo.SessionAffinityType() != svcInfo.SessionAffinityType() || p.loadBalancingChanged(svcInfo, pSvcInfo)
+			// The external traffic is moved to or from a dedicated group when the internalTrafficPolicy changes.
+			if internalTrafficPolicyChanged = svcInfo.NodeLocalInternal() != pSvcInfo.NodeLocalInternal(); internalTrafficPolicyChanged {
+				needUpdateService = true
+				needUpdateEndpoints = true
+			}
+			if p.proxyNodePort && nodePortChanged(svcInfo, pSvcInfo) {
+				needUpdateService = true
+			}
 		} else { // Need to install.
 			needUpdateService = true
 		}
+		needUpdateEndpoints = needUpdateEndpoints || forceUpdateEndpoints
 
 		var endpointUpdateList []k8sproxy.Endpoint
 		if len(endpoints) > maxEndpoints {
@@ -286,7 +431,7 @@ func (p *proxier) installServices() {
 			endpointList = endpointList[:maxEndpoints]
 
 			for _, endpoint := range endpointList { // Check if there is any installed Endpoint which is not expected anymore.
-				if _, ok := endpointsInstalled[endpoint.String()]; !ok { // There is an expected Endpoint which is not installed.
+				if installedEp, ok := endpointsInstalled[endpoint.String()]; !ok || !installedEp.Equal(endpoint) { // There is an expected Endpoint which is not installed or has changed.
 					needUpdateEndpoints = true
 				}
 				endpointUpdateList = append(endpointUpdateList, endpoint)
@@ -296,7 +441,7 @@ func (p *proxier) installServices() {
 				p.oversizeServiceSet.Delete(svcPortName.String())
 			}
 			for _, endpoint := range endpoints { // Check if there is any installed Endpoint which is not expected anymore.
-				if _, ok := endpointsInstalled[endpoint.String()]; !ok { // There is an expected Endpoint which is not installed.
+				if installedEp, ok := endpointsInstalled[endpoint.String()]; !ok || !installedEp.Equal(endpoint) { // There is an expected Endpoint which is not installed or has changed.
 					needUpdateEndpoints = true
 				}
 				endpointUpdateList = append(endpointUpdateList, endpoint)
@@ -331,24 +476,42 @@ func (p *proxier) installServices() {
 			klog.V(2).Infof("Installing Service %s %s", svcPortName.Name, svcInfo.String())
 		}
 
+		// The internalTrafficPolicy only applies to the ClusterIP traffic. If it is Local, the NodePort and LoadBalancer
+		// traffic is load-balanced with a dedicated group, which includes the Endpoints running on other Nodes.
+		externalGroupID := groupID
+		if svcInfo.NodeLocalInternal() {
+			externalGroupID, _ = p.groupCounter.Get(svcPortName, types.ExternalGroup)
+		}
+
 		if needUpdateEndpoints {
 			err := p.ofClient.InstallEndpointFlows(svcInfo.OFProtocol, endpointUpdateList)
 			if err != nil {
 				klog.Errorf("Error when installing Endpoints flows: %v", err)
 				continue
 			}
-			err = p.ofClient.InstallServiceGroup(groupID, svcInfo.StickyMaxAgeSeconds() != 0, endpointUpdateList)
+			// The flows are installed for all the Endpoints, but only the Endpoints selected by the topology of the
+			// Service are added to the group, as buckets arranged according to the LoadBalancingMode of the Service.
+			groupEndpoints := filterEndpoints(endpointUpdateList, svcInfo, p.nodeLabels, p.topologyAwareHintsEnabled, false)
+			err = p.ofClient.InstallServiceGroup(groupID, svcInfo.StickyMaxAgeSeconds() != 0, p.getGroupEndpoints(svcInfo, groupEndpoints))
 			if err != nil {
 				klog.Errorf("Error when installing Endpoints groups: %v", err)
 				continue
 			}
+			if externalGroupID != groupID {
+				externalEndpoints := filterEndpoints(endpointUpdateList, svcInfo, p.nodeLabels, p.topologyAwareHintsEnabled, true)
+				err = p.ofClient.InstallServiceGroup(externalGroupID, svcInfo.StickyMaxAgeSeconds() != 0, p.getGroupEndpoints(svcInfo, externalEndpoints))
+				if err != nil {
+					klog.Errorf("Error when installing external Endpoints groups: %v", err)
+					continue
+				}
+			}
 			for _, e := range endpointUpdateList {
 				// If the Endpoint is newly installed, add a reference.
 				if _, ok := endpointsInstalled[e.String()]; !ok {
 					key := endpointKey(e, svcInfo.OFProtocol)
 					p.endpointReferenceCounter[key] = p.endpointReferenceCounter[key] + 1
-					endpointsInstalled[e.String()] = e
 				}
+				endpointsInstalled[e.String()] = e
 			}
 		}
 
@@ -364,11 +527,19 @@ func (p *proxier) installServices() {
 				klog.Errorf("Error when installing Service flows: %v", err)
 				continue
 			}
+			// Delete the previous NodePort if it has changed, or if the flows of the Service have been removed.
+			if p.proxyNodePort && pSvcInfo != nil && pSvcInfo.NodePort() > 0 && (needRemoval || nodePortChanged(svcInfo, pSvcInfo)) {
+				if err := p.uninstallNodePortService(svcPortName, pSvcInfo); err != nil {
+					klog.Errorf("Failed to remove NodePort of Service %v: %v", svcPortName, err)
+					continue
+				}
+			}
 			// Install OpenFlow entries for the ingress IPs of LoadBalancer Service.
 			// The LoadBalancer Service should be accessible from Pod, Node and
 			// external host.
 			var toDelete, toAdd []string
-			if needRemoval {
+			// The flows of all the ingress IPs are reinstalled if the external traffic has moved to another group.
+			if needRemoval || internalTrafficPolicyChanged {
 				toDelete = pSvcInfo.LoadBalancerIPStrings()
 				toAdd = svcInfo.LoadBalancerIPStrings()
 			} else {
@@ -387,7 +558,7 @@ func (p *proxier) installServices() {
 			}
 			for _, ingress := range toAdd {
 				if ingress != "" {
-					if err := p.installLoadBalancerServiceFlows(groupID, net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
+					if err := p.installLoadBalancerServiceFlows(externalGroupID, net.ParseIP(ingress), uint16(svcInfo.Port()), svcInfo.OFProtocol, uint16(svcInfo.StickyMaxAgeSeconds())); err != nil {
 						klog.Errorf("Error when installing LoadBalancer Service flows: %v", err)
 						continue
 					}
@@ -395,6 +566,21 @@ func (p *proxier) installServices() {
 			}
 		}
 
+		if p.proxyNodePort && svcInfo.NodePort() > 0 {
+			if err := p.installNodePortService(svcPortName, svcInfo, externalGroupID, endpointUpdateList, needUpdateService, needUpdateEndpoints); err != nil {
+				klog.Errorf("Error when installing NodePort of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
+
+		// The external group is not used anymore once the flows of the external traffic have been moved to groupID.
+		if internalTrafficPolicyChanged && pSvcInfo.NodeLocalInternal() {
+			if err := p.uninstallExternalServiceGroup(svcPortName); err != nil {
+				klog.Errorf("Failed to remove external Endpoints group of Service %v: %v", svcPortName, err)
+				continue
+			}
+		}
+
 		p.serviceInstalledMap[svcPortName] = svcPort
 		p.addServiceByIP(svcInfo.String(), svcPortName)
 	}
@@ -430,12 +616,19 @@ func (p *proxier) syncProxyRules() {
 	p.serviceEndpointsMapsMutex.Lock()
 	defer p.serviceEndpointsMapsMutex.Unlock()
 	p.endpointsChanges.Update(p.endpointsMap)
-	p.serviceChanges.Update(p.serviceMap)
+	serviceUpdateResult := p.serviceChanges.Update(p.serviceMap)
+
+	nodeLabelsChanged := p.topologyAwareHintsEnabled && p.syncNodeLabels()
 
 	p.removeStaleServices()
-	p.installServices()
+	p.installServices(nodeLabelsChanged)
 	p.removeStaleEndpoints()
 
+	if p.serviceHealthServer != nil {
+		p.serviceHealthServer.SyncServices(serviceUpdateResult.HCServiceNodePorts)
+		p.serviceHealthServer.SyncEndpoints(p.localEndpointsCount())
+	}
+
 	counter := 0
 	for _, endpoints := range p.endpointsMap {
 		counter += len(endpoints)
@@ -449,6 +642,26 @@ func (p *proxier) syncProxyRules() {
 	}
 }
 
+// localEndpointsCount returns the number of local Endpoints of each Service, which is reported by the health checks
+// of the Services whose externalTrafficPolicy is Local.
+func (p *proxier) localEndpointsCount() map[k8sapitypes.NamespacedName]int {
+	counts := map[k8sapitypes.NamespacedName]int{}
+	for svcPortName, endpoints := range p.endpointsMap {
+		count := 0
+		for _, endpoint := range endpoints {
+			if endpoint.GetIsLocal() {
+				count++
+			}
+		}
+		// The ports of a Service usually share the same Endpoints, the Service is healthy if any of them has local
+		// Endpoints.
+		if count > counts[svcPortName.NamespacedName] {
+			counts[svcPortName.NamespacedName] = count
+		}
+	}
+	return counts
+}
+
 func (p *proxier) SyncLoop() {
 	p.runner.Loop(p.stopChan)
 }
@@ -532,6 +745,30 @@ func (p *proxier) OnServiceSynced() {
 	}
 }
 
+func (p *proxier) onNodeAdd(obj interface{}) {
+	node := obj.(*corev1.Node)
+	if node.Name != p.hostname {
+		return
+	}
+	if p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
+// onNodeUpdate triggers a sync when the labels of this Node have changed, so that the Endpoints selected by topology
+// aware hints follow the zone of the Node. The labels are then updated by syncNodeLabels.
+func (p *proxier) onNodeUpdate(oldObj, newObj interface{}) {
+	oldNode := oldObj.(*corev1.Node)
+	newNode := newObj.(*corev1.Node)
+	if newNode.Name != p.hostname || reflect.DeepEqual(oldNode.Labels, newNode.Labels) {
+		return
+	}
+	klog.V(2).Infof("Labels of Node %s updated, syncing proxy rules", p.hostname)
+	if p.isInitialized() {
+		p.runner.Run()
+	}
+}
+
 func (p *proxier) GetServiceByIP(serviceStr string) (k8sproxy.ServicePortName, bool) {
 	p.serviceStringMapMutex.Lock()
 	defer p.serviceStringMapMutex.Unlock()
@@ -605,8 +842,16 @@ func (p *proxier) GetServiceFlowKeys(serviceName, namespace string) ([]string, [
 		svcFlows := p.ofClient.GetServiceFlowKeys(svcInfo.ClusterIP(), uint16(svcInfo.Port()), svcInfo.OFProtocol, epList)
 		flows = append(flows, svcFlows...)
 
-		groupID, _ := p.groupCounter.Get(svcPortName)
+		groupID, _ := p.groupCounter.Get(svcPortName, types.InternalGroup)
 		groups = append(groups, groupID)
+		if svcInfo.NodeLocalInternal() {
+			externalGroupID, _ := p.groupCounter.Get(svcPortName, types.ExternalGroup)
+			groups = append(groups, externalGroupID)
+		}
+		if p.proxyNodePort && svcInfo.NodePort() > 0 && svcInfo.NodeLocalExternal() {
+			localGroupID, _ := p.groupCounter.Get(svcPortName, types.LocalGroup)
+			groups = append(groups, localGroupID)
+		}
 	}
 
 	return flows, groups, found
@@ -616,6 +861,10 @@ func NewProxier(
 	hostname string,
 	informerFactory informers.SharedInformerFactory,
 	ofClient openflow.Client,
+	routeClient route.Interface,
+	nodePortAddresses []net.IP,
+	proxyNodePort bool,
+	defaultLoadBalancingMode types.LoadBalancingMode,
 	isIPv6 bool) *proxier {
 	recorder := record.NewBroadcaster().NewRecorder(
 		runtime.NewScheme(),
@@ -625,6 +874,8 @@ func NewProxier(
 	klog.V(2).Infof("Creating proxier with IPv6 enabled=%t", isIPv6)
 
 	enableEndpointSlice := features.DefaultFeatureGate.Enabled(features.EndpointSlice)
+	// Topology hints are only available in EndpointSlices.
+	topologyAwareHintsEnabled := enableEndpointSlice && features.DefaultFeatureGate.Enabled(features.TopologyAwareHints)
 
 	ipFamily := corev1.IPv4Protocol
 	if isIPv6 {
@@ -632,21 +883,41 @@ func NewProxier(
 	}
 
 	p := &proxier{
-		enableEndpointSlice:      enableEndpointSlice,
-		endpointsConfig:          config.NewEndpointsConfig(informerFactory.Core().V1().Endpoints(), resyncPeriod),
-		serviceConfig:            config.NewServiceConfig(informerFactory.Core().V1().Services(), resyncPeriod),
-		endpointsChanges:         newEndpointsChangesTracker(hostname, enableEndpointSlice, isIPv6),
-		serviceChanges:           newServiceChangesTracker(recorder, ipFamily),
-		serviceMap:               k8sproxy.ServiceMap{},
-		serviceInstalledMap:      k8sproxy.ServiceMap{},
-		endpointsInstalledMap:    types.EndpointsMap{},
-		endpointsMap:             types.EndpointsMap{},
-		endpointReferenceCounter: map[string]int{},
-		serviceStringMap:         map[string]k8sproxy.ServicePortName{},
-		oversizeServiceSet:       sets.NewString(),
-		groupCounter:             types.NewGroupCounter(isIPv6),
-		ofClient:                 ofClient,
-		isIPv6:                   isIPv6,
+		enableEndpointSlice:       enableEndpointSlice,
+		endpointsConfig:           config.NewEndpointsConfig(informerFactory.Core().V1().Endpoints(), resyncPeriod),
+		serviceConfig:             config.NewServiceConfig(informerFactory.Core().V1().Services(), resyncPeriod),
+		endpointsChanges:          newEndpointsChangesTracker(hostname, enableEndpointSlice, isIPv6),
+		serviceChanges:            newServiceChangesTracker(recorder, ipFamily),
+		serviceMap:                k8sproxy.ServiceMap{},
+		serviceInstalledMap:       k8sproxy.ServiceMap{},
+		endpointsInstalledMap:     types.EndpointsMap{},
+		endpointsMap:              types.EndpointsMap{},
+		endpointReferenceCounter:  map[string]int{},
+		serviceStringMap:          map[string]k8sproxy.ServicePortName{},
+		oversizeServiceSet:        sets.NewString(),
+		groupCounter:              types.NewGroupCounter(isIPv6),
+		ofClient:                  ofClient,
+		routeClient:               routeClient,
+		isIPv6:                    isIPv6,
+		proxyNodePort:             proxyNodePort,
+		nodePortAddresses:         nodePortAddresses,
+		topologyAwareHintsEnabled: topologyAwareHintsEnabled,
+		hostname:                  hostname,
+		defaultLoadBalancingMode:  defaultLoadBalancingMode,
+	}
+	if topologyAwareHintsEnabled {
+		nodeInformer := informerFactory.Core().V1().Nodes()
+		p.nodeLister = nodeInformer.Lister()
+		nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    p.onNodeAdd,
+				UpdateFunc: p.onNodeUpdate,
+			},
+			resyncPeriod,
+		)
+	}
+	if proxyNodePort {
+		p.serviceHealthServer = newServiceHealthServer(isIPv6)
 	}
 	p.serviceConfig.RegisterEventHandler(p)
 	p.endpointsConfig.RegisterEventHandler(p)
@@ -692,13 +963,20 @@ func (p *metaProxierWrapper) GetServiceByIP(serviceStr string) (k8sproxy.Service
 }
 
 func NewDualStackProxier(
-	hostname string, informerFactory informers.SharedInformerFactory, ofClient openflow.Client) *metaProxierWrapper {
+	hostname string,
+	informerFactory informers.SharedInformerFactory,
+	ofClient openflow.Client,
+	routeClient route.Interface,
+	nodePortAddressesIPv4 []net.IP,
+	nodePortAddressesIPv6 []net.IP,
+	proxyNodePort bool,
+	defaultLoadBalancingMode types.LoadBalancingMode) *metaProxierWrapper {
 
 	// Create an ipv4 instance of the single-stack proxier
-	ipv4Proxier := NewProxier(hostname, informerFactory, ofClient, false)
+	ipv4Proxier := NewProxier(hostname, informerFactory, ofClient, routeClient, nodePortAddressesIPv4, proxyNodePort, defaultLoadBalancingMode, false)
 
 	// Create an ipv6 instance of the single-stack proxier
-	ipv6Proxier := NewProxier(hostname, informerFactory, ofClient, true)
+	ipv6Proxier := NewProxier(hostname, informerFactory, ofClient, routeClient, nodePortAddressesIPv6, proxyNodePort, defaultLoadBalancingMode, true)
 
 	// Create a meta-proxier that dispatch calls between the two
 	// single-stack proxier instances.
@@ -30,10 +30,13 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/component-base/metrics/testutil"
 
+	agentconfig "antrea.io/antrea/pkg/agent/config"
 	"antrea.io/antrea/pkg/agent/openflow"
 	ofmock "antrea.io/antrea/pkg/agent/openflow/testing"
 	"antrea.io/antrea/pkg/agent/proxy/metrics"
 	"antrea.io/antrea/pkg/agent/proxy/types"
+	"antrea.io/antrea/pkg/agent/route"
+	routetesting "antrea.io/antrea/pkg/agent/route/testing"
 	binding "antrea.io/antrea/pkg/ovs/openflow"
 	k8sproxy "antrea.io/antrea/third_party/proxy"
 )
@@ -106,11 +109,20 @@ func NewFakeProxier(ofClient openflow.Client, isIPv6 bool) *proxier {
 		ofClient:                 ofClient,
 		serviceStringMap:         map[string]k8sproxy.ServicePortName{},
 		isIPv6:                   isIPv6,
+		defaultLoadBalancingMode: types.LoadBalancingModeRandom,
 	}
 	p.runner = k8sproxy.NewBoundedFrequencyRunner(componentName, p.syncProxyRules, time.Second, 30*time.Second, 2)
 	return p
 }
 
+func NewFakeProxierWithNodePort(ofClient openflow.Client, routeClient route.Interface, nodePortAddresses []net.IP, isIPv6 bool) *proxier {
+	p := NewFakeProxier(ofClient, isIPv6)
+	p.routeClient = routeClient
+	p.nodePortAddresses = nodePortAddresses
+	p.proxyNodePort = true
+	return p
+}
+
 func testClusterIP(t *testing.T, svcIP net.IP, epIP net.IP, isIPv6 bool) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -149,7 +161,7 @@ func testClusterIP(t *testing.T, svcIP net.IP, epIP net.IP, isIPv6 bool) {
 		}),
 	)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	bindingProtocol := binding.ProtocolTCP
 	if isIPv6 {
@@ -206,7 +218,7 @@ func TestLoadbalancer(t *testing.T) {
 		}),
 	)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIPv4, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
@@ -224,6 +236,216 @@ func TestClusterIPv6(t *testing.T) {
 	testClusterIP(t, net.ParseIP("10:20::41"), net.ParseIP("10:180::1"), true)
 }
 
+func testNodePort(t *testing.T, nodePortAddresses []net.IP, svcIP net.IP, epIPs []net.IP, isIPv6 bool, nodeLocalExternal bool) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routetesting.NewMockInterface(ctrl)
+	fp := NewFakeProxierWithNodePort(mockOFClient, mockRouteClient, nodePortAddresses, isIPv6)
+
+	svcPort := 80
+	svcNodePort := 31000
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeServiceMap(fp,
+		makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIP.String()
+			svc.Spec.Type = corev1.ServiceTypeNodePort
+			if nodeLocalExternal {
+				svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+			} else {
+				svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+			}
+			svc.Spec.Ports = []corev1.ServicePort{{
+				NodePort: int32(svcNodePort),
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		}),
+	)
+
+	// The first Endpoint runs on the local Node.
+	localNodeName := "localhost"
+	remoteNodeName := "remote"
+	var addresses []corev1.EndpointAddress
+	for i, epIP := range epIPs {
+		nodeName := &remoteNodeName
+		if i == 0 {
+			nodeName = &localNodeName
+		}
+		addresses = append(addresses, corev1.EndpointAddress{IP: epIP.String(), NodeName: nodeName})
+	}
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: addresses,
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+
+	bindingProtocol := binding.ProtocolTCP
+	virtualNodePortDNATIP := agentconfig.VirtualNodePortDNATIPv4
+	if isIPv6 {
+		bindingProtocol = binding.ProtocolTCPv6
+		virtualNodePortDNATIP = agentconfig.VirtualNodePortDNATIPv6
+	}
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallEndpointFlows(bindingProtocol, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), bindingProtocol, uint16(0)).Times(1)
+	nodePortGroupID := groupID
+	if nodeLocalExternal {
+		nodePortGroupID, _ = fp.groupCounter.Get(svcPortName, types.LocalGroup)
+		mockOFClient.EXPECT().InstallServiceGroup(nodePortGroupID, false, gomock.Any()).Do(
+			func(_ binding.GroupIDType, _ bool, endpoints []k8sproxy.Endpoint) {
+				assert.Len(t, endpoints, 1)
+				assert.Equal(t, epIPs[0].String(), endpoints[0].IP())
+			}).Times(1)
+	}
+	mockOFClient.EXPECT().InstallServiceFlows(nodePortGroupID, virtualNodePortDNATIP, uint16(svcNodePort), bindingProtocol, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().AddNodePort(nodePortAddresses, uint16(svcNodePort), bindingProtocol, nodeLocalExternal).Times(1)
+
+	fp.syncProxyRules()
+}
+
+func TestNodePortIPv4(t *testing.T) {
+	nodePortAddresses := []net.IP{net.ParseIP("192.168.77.100")}
+	epIPs := []net.IP{net.ParseIP("10.180.0.1"), net.ParseIP("10.180.1.1")}
+	testNodePort(t, nodePortAddresses, net.ParseIP("10.20.30.41"), epIPs, false, false)
+}
+
+func TestNodePortIPv6(t *testing.T) {
+	nodePortAddresses := []net.IP{net.ParseIP("fd00::100")}
+	epIPs := []net.IP{net.ParseIP("10:180::1"), net.ParseIP("10:180::2")}
+	testNodePort(t, nodePortAddresses, net.ParseIP("10:20::41"), epIPs, true, false)
+}
+
+func TestNodePortLocalIPv4(t *testing.T) {
+	nodePortAddresses := []net.IP{net.ParseIP("192.168.77.100")}
+	epIPs := []net.IP{net.ParseIP("10.180.0.1"), net.ParseIP("10.180.1.1")}
+	testNodePort(t, nodePortAddresses, net.ParseIP("10.20.30.41"), epIPs, false, true)
+}
+
+func TestNodePortLocalIPv6(t *testing.T) {
+	nodePortAddresses := []net.IP{net.ParseIP("fd00::100")}
+	epIPs := []net.IP{net.ParseIP("10:180::1"), net.ParseIP("10:180::2")}
+	testNodePort(t, nodePortAddresses, net.ParseIP("10:20::41"), epIPs, true, true)
+}
+
+func TestInternalTrafficPolicyLocal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOFClient := ofmock.NewMockClient(ctrl)
+	mockRouteClient := routetesting.NewMockInterface(ctrl)
+	nodePortAddresses := []net.IP{net.ParseIP("192.168.77.100")}
+	fp := NewFakeProxierWithNodePort(mockOFClient, mockRouteClient, nodePortAddresses, false)
+
+	svcIP := net.ParseIP("10.20.30.41")
+	loadBalancerIP := net.ParseIP("169.254.0.1")
+	svcPort := 80
+	svcNodePort := 31000
+	svcPortName := k8sproxy.ServicePortName{
+		NamespacedName: makeNamespaceName("ns1", "svc1"),
+		Port:           "80",
+		Protocol:       corev1.ProtocolTCP,
+	}
+	makeService := func(internalTrafficPolicy corev1.ServiceInternalTrafficPolicyType) *corev1.Service {
+		return makeTestService(svcPortName.Namespace, svcPortName.Name, func(svc *corev1.Service) {
+			svc.Spec.ClusterIP = svcIP.String()
+			svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+			svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+			svc.Spec.InternalTrafficPolicy = &internalTrafficPolicy
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: loadBalancerIP.String()}}
+			svc.Spec.Ports = []corev1.ServicePort{{
+				NodePort: int32(svcNodePort),
+				Name:     svcPortName.Port,
+				Port:     int32(svcPort),
+				Protocol: corev1.ProtocolTCP,
+			}}
+		})
+	}
+	makeServiceMap(fp, makeService(corev1.ServiceInternalTrafficPolicyLocal))
+
+	// The first Endpoint runs on the local Node.
+	localNodeName := "localhost"
+	remoteNodeName := "remote"
+	localEpIP := net.ParseIP("10.180.0.1")
+	remoteEpIP := net.ParseIP("10.180.1.1")
+	makeEndpointsMap(fp,
+		makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, func(ept *corev1.Endpoints) {
+			ept.Subsets = []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{
+					{IP: localEpIP.String(), NodeName: &localNodeName},
+					{IP: remoteEpIP.String(), NodeName: &remoteNodeName},
+				},
+				Ports: []corev1.EndpointPort{{
+					Name:     svcPortName.Port,
+					Port:     int32(svcPort),
+					Protocol: corev1.ProtocolTCP,
+				}},
+			}}
+		}),
+	)
+	expectGroupEndpoints := func(expectedIPs ...net.IP) func(binding.GroupIDType, bool, []k8sproxy.Endpoint) {
+		return func(_ binding.GroupIDType, _ bool, endpoints []k8sproxy.Endpoint) {
+			var ips []string
+			for _, endpoint := range endpoints {
+				ips = append(ips, endpoint.IP())
+			}
+			var expected []string
+			for _, ip := range expectedIPs {
+				expected = append(expected, ip.String())
+			}
+			assert.ElementsMatch(t, expected, ips)
+		}
+	}
+
+	// The ClusterIP traffic is only load-balanced to the local Endpoint, while the NodePort and LoadBalancer traffic
+	// is load-balanced to all the Endpoints with the external group.
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
+	externalGroupID, _ := fp.groupCounter.Get(svcPortName, types.ExternalGroup)
+	assert.NotEqual(t, groupID, externalGroupID)
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Do(expectGroupEndpoints(localEpIP)).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(externalGroupID, false, gomock.Any()).Do(expectGroupEndpoints(localEpIP, remoteEpIP)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(externalGroupID, loadBalancerIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().InstallLoadBalancerServiceFromOutsideFlows(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockOFClient.EXPECT().InstallServiceFlows(externalGroupID, agentconfig.VirtualNodePortDNATIPv4, uint16(svcNodePort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().AddNodePort(nodePortAddresses, uint16(svcNodePort), binding.ProtocolTCP, false).Times(1)
+	fp.syncProxyRules()
+
+	mockOFClient.EXPECT().GetServiceFlowKeys(svcIP, uint16(svcPort), binding.ProtocolTCP, gomock.Any()).Times(1)
+	_, groups, found := fp.GetServiceFlowKeys(svcPortName.Name, svcPortName.Namespace)
+	assert.True(t, found)
+	assert.ElementsMatch(t, []binding.GroupIDType{groupID, externalGroupID}, groups)
+
+	// When the internalTrafficPolicy is changed to Cluster, all the traffic is load-balanced with the same group and
+	// the external group is removed once it is not used anymore.
+	makeServiceMap(fp, makeService(corev1.ServiceInternalTrafficPolicyCluster))
+	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
+	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Do(expectGroupEndpoints(localEpIP, remoteEpIP)).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockOFClient.EXPECT().UninstallServiceFlows(loadBalancerIP, uint16(svcPort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallLoadBalancerServiceFromOutsideFlows(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, loadBalancerIP, uint16(svcPort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().DeleteNodePort(nodePortAddresses, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().UninstallServiceFlows(agentconfig.VirtualNodePortDNATIPv4, uint16(svcNodePort), binding.ProtocolTCP).Times(1)
+	mockOFClient.EXPECT().InstallServiceFlows(groupID, agentconfig.VirtualNodePortDNATIPv4, uint16(svcNodePort), binding.ProtocolTCP, uint16(0)).Times(1)
+	mockRouteClient.EXPECT().AddNodePort(nodePortAddresses, uint16(svcNodePort), binding.ProtocolTCP, false).Times(1)
+	mockOFClient.EXPECT().UninstallServiceGroup(externalGroupID).Times(1)
+	fp.syncProxyRules()
+}
+
 func TestDualStackService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -284,8 +506,8 @@ func TestDualStackService(t *testing.T) {
 	metaProxier.OnEndpointsUpdate(nil, epv6)
 	metaProxier.OnEndpointsSynced()
 
-	groupIDv4, _ := fpv4.groupCounter.Get(svcPortName)
-	groupIDv6, _ := fpv6.groupCounter.Get(svcPortName)
+	groupIDv4, _ := fpv4.groupCounter.Get(svcPortName, types.InternalGroup)
+	groupIDv6, _ := fpv6.groupCounter.Get(svcPortName, types.InternalGroup)
 
 	mockOFClient.EXPECT().InstallServiceGroup(groupIDv4, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(binding.ProtocolTCP, gomock.Any()).Times(1)
@@ -340,7 +562,7 @@ func testClusterIPRemoval(t *testing.T, svcIP net.IP, epIP net.IP, isIPv6 bool)
 	}
 	ep := makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, epFunc)
 	makeEndpointsMap(fp, ep)
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(bindingProtocol, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), bindingProtocol, uint16(0)).Times(1)
@@ -468,8 +690,8 @@ func testClusterIPRemoveSamePortEndpoint(t *testing.T, svcIP net.IP, epIP net.IP
 	}
 	makeEndpointsMap(fp, ep, epUDP)
 
-	groupID, _ := fp.groupCounter.Get(svcPortName)
-	groupIDUDP, _ := fp.groupCounter.Get(svcPortNameUDP)
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
+	groupIDUDP, _ := fp.groupCounter.Get(svcPortNameUDP, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceGroup(groupIDUDP, false, gomock.Any()).Times(2)
 	mockOFClient.EXPECT().InstallEndpointFlows(protocolTCP, gomock.Any()).Times(1)
@@ -531,7 +753,7 @@ func testClusterIPRemoveEndpoints(t *testing.T, svcIP net.IP, epIP net.IP, isIPv
 		bindingProtocol = binding.ProtocolTCPv6
 	}
 	makeEndpointsMap(fp, ep)
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(2)
 	mockOFClient.EXPECT().InstallEndpointFlows(bindingProtocol, gomock.Any()).Times(2)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), bindingProtocol, uint16(0)).Times(1)
@@ -602,7 +824,7 @@ func testSessionAffinityNoEndpoint(t *testing.T, svcExternalIPs net.IP, svcIP ne
 	if isIPv6 {
 		bindingProtocol = binding.ProtocolTCPv6
 	}
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, true, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(bindingProtocol, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort), bindingProtocol, uint16(corev1.DefaultClientIPServiceAffinitySeconds)).Times(1)
@@ -708,7 +930,7 @@ func testPortChange(t *testing.T, svcIP net.IP, epIP net.IP, isIPv6 bool) {
 	}
 	ep := makeTestEndpoints(svcPortName.Namespace, svcPortName.Name, epFunc)
 	makeEndpointsMap(fp, ep)
-	groupID, _ := fp.groupCounter.Get(svcPortName)
+	groupID, _ := fp.groupCounter.Get(svcPortName, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallEndpointFlows(bindingProtocol, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceFlows(groupID, svcIP, uint16(svcPort1), bindingProtocol, uint16(0))
@@ -793,8 +1015,8 @@ func TestServicesWithSameEndpoints(t *testing.T) {
 	ep1 := epMapFactory(svcPortName1, epIP.String())
 	ep2 := epMapFactory(svcPortName2, epIP.String())
 
-	groupID1, _ := fp.groupCounter.Get(svcPortName1)
-	groupID2, _ := fp.groupCounter.Get(svcPortName2)
+	groupID1, _ := fp.groupCounter.Get(svcPortName1, types.InternalGroup)
+	groupID2, _ := fp.groupCounter.Get(svcPortName2, types.InternalGroup)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID1, false, gomock.Any()).Times(1)
 	mockOFClient.EXPECT().InstallServiceGroup(groupID2, false, gomock.Any()).Times(1)
 	bindingProtocol := binding.ProtocolTCP
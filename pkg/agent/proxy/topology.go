@@ -0,0 +1,89 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	k8sproxy "antrea.io/antrea/third_party/proxy"
+)
+
+// filterEndpoints returns the Endpoints of a Service which should be selected by its traffic. isExternalTraffic
+// indicates whether the Endpoints are selected for the NodePort and LoadBalancer traffic, or for the ClusterIP traffic
+// originating from the local Node or the Pods running on it:
+// - If the traffic is not external and the internalTrafficPolicy of the Service is Local, only the local Endpoints are
+//   returned.
+// - Otherwise, if topologyAwareHints is true and the Service has enabled topology aware hints, only the Endpoints
+//   hinted for the zone of the local Node are returned.
+// If no Endpoint is left after filtering, all the Endpoints are returned, so the Service remains reachable.
+func filterEndpoints(endpoints []k8sproxy.Endpoint, svcInfo k8sproxy.ServicePort, nodeLabels map[string]string, topologyAwareHints, isExternalTraffic bool) []k8sproxy.Endpoint {
+	if !isExternalTraffic && svcInfo.NodeLocalInternal() {
+		return filterEndpointsInternalTrafficPolicy(endpoints)
+	}
+	if topologyAwareHints {
+		return filterEndpointsWithHints(endpoints, svcInfo.HintsAnnotation(), nodeLabels)
+	}
+	return endpoints
+}
+
+// filterEndpointsInternalTrafficPolicy returns the local Endpoints, or all the Endpoints if none of them is local.
+func filterEndpointsInternalTrafficPolicy(endpoints []k8sproxy.Endpoint) []k8sproxy.Endpoint {
+	var localEndpoints []k8sproxy.Endpoint
+	for _, endpoint := range endpoints {
+		if endpoint.GetIsLocal() {
+			localEndpoints = append(localEndpoints, endpoint)
+		}
+	}
+	if len(localEndpoints) == 0 {
+		klog.V(4).Info("Skipping internalTrafficPolicy Endpoint filtering since there is no local Endpoint")
+		return endpoints
+	}
+	return localEndpoints
+}
+
+// filterEndpointsWithHints returns the Endpoints hinted for the zone of the local Node. The filtering is skipped if
+// the Service doesn't enable topology aware hints, if the Node has no zone label, if any Endpoint has no zone hint, or
+// if no Endpoint is hinted for the zone.
+func filterEndpointsWithHints(endpoints []k8sproxy.Endpoint, hintsAnnotation string, nodeLabels map[string]string) []k8sproxy.Endpoint {
+	if hintsAnnotation != "Auto" && hintsAnnotation != "auto" {
+		if hintsAnnotation != "" && hintsAnnotation != "Disabled" && hintsAnnotation != "disabled" {
+			klog.Warningf("Skipping topology aware Endpoint filtering since Service has unexpected value for %s annotation: %s", corev1.AnnotationTopologyAwareHints, hintsAnnotation)
+		}
+		return endpoints
+	}
+
+	zone, ok := nodeLabels[corev1.LabelTopologyZone]
+	if !ok || zone == "" {
+		klog.Warningf("Skipping topology aware Endpoint filtering since Node is missing %s label", corev1.LabelTopologyZone)
+		return endpoints
+	}
+
+	var filteredEndpoints []k8sproxy.Endpoint
+	for _, endpoint := range endpoints {
+		if endpoint.GetZoneHints().Len() == 0 {
+			klog.Warning("Skipping topology aware Endpoint filtering since one or more Endpoints is missing a zone hint")
+			return endpoints
+		}
+		if endpoint.GetZoneHints().Has(zone) {
+			filteredEndpoints = append(filteredEndpoints, endpoint)
+		}
+	}
+	if len(filteredEndpoints) == 0 {
+		klog.Warningf("Skipping topology aware Endpoint filtering since no hints were provided for zone %s", zone)
+		return endpoints
+	}
+	return filteredEndpoints
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+
+	k8sproxy "antrea.io/antrea/third_party/proxy"
+)
+
+func TestFilterEndpoints(t *testing.T) {
+	localPolicy := corev1.ServiceInternalTrafficPolicyLocal
+	clusterPolicy := corev1.ServiceInternalTrafficPolicyCluster
+	newServiceInfo := func(internalTrafficPolicy *corev1.ServiceInternalTrafficPolicyType, hintsAnnotation string) k8sproxy.ServicePort {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "svc1"},
+			Spec: corev1.ServiceSpec{
+				ClusterIP:             "10.96.0.10",
+				InternalTrafficPolicy: internalTrafficPolicy,
+				Ports:                 []corev1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+			},
+		}
+		if hintsAnnotation != "" {
+			svc.Annotations = map[string]string{corev1.AnnotationTopologyAwareHints: hintsAnnotation}
+		}
+		tracker := newServiceChangesTracker(record.NewFakeRecorder(10), corev1.IPv4Protocol)
+		tracker.OnServiceUpdate(nil, svc)
+		serviceMap := k8sproxy.ServiceMap{}
+		tracker.Update(serviceMap)
+		for _, svcPort := range serviceMap {
+			return svcPort
+		}
+		return nil
+	}
+	ep1 := k8sproxy.NewBaseEndpointInfo("10.10.0.1", 80, true, nil, sets.NewString("zone-a"))
+	ep2 := k8sproxy.NewBaseEndpointInfo("10.10.1.1", 80, false, nil, sets.NewString("zone-b"))
+	ep3 := k8sproxy.NewBaseEndpointInfo("10.10.2.1", 80, false, nil, sets.NewString("zone-a"))
+	epNoHints := k8sproxy.NewBaseEndpointInfo("10.10.3.1", 80, false, nil, nil)
+	zoneALabels := map[string]string{corev1.LabelTopologyZone: "zone-a"}
+	zoneCLabels := map[string]string{corev1.LabelTopologyZone: "zone-c"}
+
+	tests := []struct {
+		name               string
+		svcInfo            k8sproxy.ServicePort
+		endpoints          []k8sproxy.Endpoint
+		nodeLabels         map[string]string
+		topologyAwareHints bool
+		isExternalTraffic  bool
+		expected           []k8sproxy.Endpoint
+	}{
+		{
+			name:      "internalTrafficPolicy Cluster",
+			svcInfo:   newServiceInfo(&clusterPolicy, ""),
+			endpoints: []k8sproxy.Endpoint{ep1, ep2},
+			expected:  []k8sproxy.Endpoint{ep1, ep2},
+		},
+		{
+			name:      "internalTrafficPolicy Local",
+			svcInfo:   newServiceInfo(&localPolicy, ""),
+			endpoints: []k8sproxy.Endpoint{ep1, ep2},
+			expected:  []k8sproxy.Endpoint{ep1},
+		},
+		{
+			name:      "internalTrafficPolicy Local without local Endpoint",
+			svcInfo:   newServiceInfo(&localPolicy, ""),
+			endpoints: []k8sproxy.Endpoint{ep2, ep3},
+			expected:  []k8sproxy.Endpoint{ep2, ep3},
+		},
+		{
+			name:               "topology hints matching the zone of the Node",
+			svcInfo:            newServiceInfo(nil, "Auto"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, ep3},
+			nodeLabels:         zoneALabels,
+			topologyAwareHints: true,
+			expected:           []k8sproxy.Endpoint{ep1, ep3},
+		},
+		{
+			name:               "topology hints disabled by the feature",
+			svcInfo:            newServiceInfo(nil, "Auto"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, ep3},
+			nodeLabels:         zoneALabels,
+			topologyAwareHints: false,
+			expected:           []k8sproxy.Endpoint{ep1, ep2, ep3},
+		},
+		{
+			name:               "topology hints disabled by the Service",
+			svcInfo:            newServiceInfo(nil, "Disabled"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, ep3},
+			nodeLabels:         zoneALabels,
+			topologyAwareHints: true,
+			expected:           []k8sproxy.Endpoint{ep1, ep2, ep3},
+		},
+		{
+			name:               "Node without zone label",
+			svcInfo:            newServiceInfo(nil, "auto"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, ep3},
+			topologyAwareHints: true,
+			expected:           []k8sproxy.Endpoint{ep1, ep2, ep3},
+		},
+		{
+			name:               "Endpoint without zone hint",
+			svcInfo:            newServiceInfo(nil, "Auto"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, epNoHints},
+			nodeLabels:         zoneALabels,
+			topologyAwareHints: true,
+			expected:           []k8sproxy.Endpoint{ep1, ep2, epNoHints},
+		},
+		{
+			name:               "no Endpoint hinted for the zone of the Node",
+			svcInfo:            newServiceInfo(nil, "Auto"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, ep3},
+			nodeLabels:         zoneCLabels,
+			topologyAwareHints: true,
+			expected:           []k8sproxy.Endpoint{ep1, ep2, ep3},
+		},
+		{
+			name:               "internalTrafficPolicy Local takes precedence over topology hints",
+			svcInfo:            newServiceInfo(&localPolicy, "Auto"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, ep3},
+			nodeLabels:         zoneALabels,
+			topologyAwareHints: true,
+			expected:           []k8sproxy.Endpoint{ep1},
+		},
+		{
+			name:              "internalTrafficPolicy Local ignored by external traffic",
+			svcInfo:           newServiceInfo(&localPolicy, ""),
+			endpoints:         []k8sproxy.Endpoint{ep1, ep2},
+			isExternalTraffic: true,
+			expected:          []k8sproxy.Endpoint{ep1, ep2},
+		},
+		{
+			name:               "topology hints applied to external traffic with internalTrafficPolicy Local",
+			svcInfo:            newServiceInfo(&localPolicy, "Auto"),
+			endpoints:          []k8sproxy.Endpoint{ep1, ep2, ep3},
+			nodeLabels:         zoneALabels,
+			topologyAwareHints: true,
+			isExternalTraffic:  true,
+			expected:           []k8sproxy.Endpoint{ep1, ep3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := filterEndpoints(tt.endpoints, tt.svcInfo, tt.nodeLabels, tt.topologyAwareHints, tt.isExternalTraffic)
+			assert.ElementsMatch(t, tt.expected, filtered)
+		})
+	}
+}
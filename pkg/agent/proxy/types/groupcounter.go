@@ -21,16 +21,42 @@ import (
 	k8sproxy "antrea.io/antrea/third_party/proxy"
 )
 
+// GroupType indicates which Endpoints of a Service a group contains. A
+// Service may have one group of each type.
+type GroupType int
+
+const (
+	// InternalGroup contains the Endpoints selected by the ClusterIP traffic,
+	// i.e. the traffic originating from the local Node or its Pods. It is
+	// also used by the external traffic when the internalTrafficPolicy of
+	// the Service is Cluster.
+	InternalGroup GroupType = iota
+	// ExternalGroup contains the Endpoints selected by the NodePort and
+	// LoadBalancer traffic when the internalTrafficPolicy of the Service is
+	// Local, as the policy doesn't apply to the external traffic.
+	ExternalGroup
+	// LocalGroup only contains the Endpoints running on the local Node. It
+	// is used by the NodePort traffic when the externalTrafficPolicy of the
+	// Service is Local.
+	LocalGroup
+)
+
 // GroupCounter generates and manages global unique group ID.
 type GroupCounter interface {
 	// Get generates a global unique group ID for a specific service.
 	// If the group ID of the service has been generated, then return the
 	// prior one. The bool return value indicates whether the groupID is newly
-	// generated.
-	Get(svcPortName k8sproxy.ServicePortName) (binding.GroupIDType, bool)
+	// generated. groupType indicates which Endpoints of the Service the group
+	// contains.
+	Get(svcPortName k8sproxy.ServicePortName, groupType GroupType) (binding.GroupIDType, bool)
 	// Recycle removes a Service Group ID mapping. The recycled groupID can be
 	// reused.
-	Recycle(svcPortName k8sproxy.ServicePortName) bool
+	Recycle(svcPortName k8sproxy.ServicePortName, groupType GroupType) bool
+}
+
+type groupKey struct {
+	svcPortName k8sproxy.ServicePortName
+	groupType   GroupType
 }
 
 type groupCounter struct {
@@ -38,7 +64,7 @@ type groupCounter struct {
 	groupIDCounter binding.GroupIDType
 	recycled       []binding.GroupIDType
 
-	groupMap map[k8sproxy.ServicePortName]binding.GroupIDType
+	groupMap map[groupKey]binding.GroupIDType
 }
 
 func NewGroupCounter(isIPv6 bool) *groupCounter {
@@ -46,33 +72,35 @@ func NewGroupCounter(isIPv6 bool) *groupCounter {
 	if isIPv6 {
 		groupIDCounter = 0x10000000
 	}
-	return &groupCounter{groupMap: map[k8sproxy.ServicePortName]binding.GroupIDType{}, groupIDCounter: groupIDCounter}
+	return &groupCounter{groupMap: map[groupKey]binding.GroupIDType{}, groupIDCounter: groupIDCounter}
 }
 
-func (c *groupCounter) Get(svcPortName k8sproxy.ServicePortName) (binding.GroupIDType, bool) {
+func (c *groupCounter) Get(svcPortName k8sproxy.ServicePortName, groupType GroupType) (binding.GroupIDType, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if id, ok := c.groupMap[svcPortName]; ok {
+	key := groupKey{svcPortName: svcPortName, groupType: groupType}
+	if id, ok := c.groupMap[key]; ok {
 		return id, false
 	} else if len(c.recycled) != 0 {
 		id = c.recycled[len(c.recycled)-1]
 		c.recycled = c.recycled[:len(c.recycled)-1]
-		c.groupMap[svcPortName] = id
+		c.groupMap[key] = id
 		return id, true
 	} else {
 		c.groupIDCounter += 1
-		c.groupMap[svcPortName] = c.groupIDCounter
+		c.groupMap[key] = c.groupIDCounter
 		return c.groupIDCounter, true
 	}
 }
 
-func (c *groupCounter) Recycle(svcPortName k8sproxy.ServicePortName) bool {
+func (c *groupCounter) Recycle(svcPortName k8sproxy.ServicePortName, groupType GroupType) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if id, ok := c.groupMap[svcPortName]; ok {
-		delete(c.groupMap, svcPortName)
+	key := groupKey{svcPortName: svcPortName, groupType: groupType}
+	if id, ok := c.groupMap[key]; ok {
+		delete(c.groupMap, key)
 		c.recycled = append(c.recycled, id)
 		return true
 	}
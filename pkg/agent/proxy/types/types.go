@@ -15,18 +15,63 @@
 package types
 
 import (
+	"strconv"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 	utilnet "k8s.io/utils/net"
 
 	"antrea.io/antrea/pkg/ovs/openflow"
 	k8sproxy "antrea.io/antrea/third_party/proxy"
 )
 
+// LoadBalancingMode is the algorithm used by AntreaProxy to select an Endpoint for a connection to a Service.
+type LoadBalancingMode string
+
+const (
+	// LoadBalancingModeRandom selects an Endpoint randomly, all the Endpoints have the same weight.
+	LoadBalancingModeRandom LoadBalancingMode = "Random"
+	// LoadBalancingModeWeighted selects an Endpoint randomly, local Endpoints have a higher weight than remote
+	// Endpoints, which is configured with the AnnotationLocalEndpointWeight annotation.
+	LoadBalancingModeWeighted LoadBalancingMode = "Weighted"
+	// LoadBalancingModeConsistentHash selects an Endpoint with a Maglev consistent hashing lookup table, which
+	// minimizes the remapping of connections when Endpoints are added or removed.
+	LoadBalancingModeConsistentHash LoadBalancingMode = "ConsistentHash"
+
+	// AnnotationLoadBalancingMode is the annotation used to set the LoadBalancingMode of a Service, which overrides
+	// the default LoadBalancingMode configured for AntreaProxy.
+	AnnotationLoadBalancingMode = "service.antrea.io/load-balancing-mode"
+	// AnnotationLocalEndpointWeight is the annotation used to set the weight of local Endpoints of a Service in
+	// Weighted mode, relative to the weight of remote Endpoints which is 100.
+	AnnotationLocalEndpointWeight = "service.antrea.io/local-endpoint-weight"
+
+	// RemoteEndpointWeight is the weight of remote Endpoints in Weighted mode.
+	RemoteEndpointWeight uint16 = 100
+	// DefaultLocalEndpointWeight is the weight of local Endpoints in Weighted mode if it's not set by the
+	// AnnotationLocalEndpointWeight annotation.
+	DefaultLocalEndpointWeight uint16 = 200
+	// MaxLocalEndpointWeight is the maximum weight of local Endpoints in Weighted mode.
+	MaxLocalEndpointWeight = 10000
+)
+
+// IsValidLoadBalancingMode returns whether mode is a supported LoadBalancingMode.
+func IsValidLoadBalancingMode(mode LoadBalancingMode) bool {
+	switch mode {
+	case LoadBalancingModeRandom, LoadBalancingModeWeighted, LoadBalancingModeConsistentHash:
+		return true
+	}
+	return false
+}
+
 // ServiceInfo is the internal struct for caching service information.
 type ServiceInfo struct {
 	*k8sproxy.BaseServiceInfo
 	// cache for performance
 	OFProtocol openflow.Protocol
+	// LoadBalancingMode is the LoadBalancingMode set by the Service annotation, it's empty if not set.
+	LoadBalancingMode LoadBalancingMode
+	// LocalEndpointWeight is the weight of local Endpoints in Weighted mode.
+	LocalEndpointWeight uint16
 }
 
 // NewServiceInfo returns a new k8sproxy.ServicePort which abstracts a serviceInfo.
@@ -47,9 +92,33 @@ func NewServiceInfo(port *corev1.ServicePort, service *corev1.Service, baseInfo
 			info.OFProtocol = openflow.ProtocolSCTP
 		}
 	}
+	info.LoadBalancingMode, info.LocalEndpointWeight = parseLoadBalancingAnnotations(service)
 	return info
 }
 
+// parseLoadBalancingAnnotations returns the LoadBalancingMode and the local Endpoint weight set by the annotations
+// of the Service. Invalid values are ignored.
+func parseLoadBalancingAnnotations(service *corev1.Service) (LoadBalancingMode, uint16) {
+	var mode LoadBalancingMode
+	localEndpointWeight := DefaultLocalEndpointWeight
+	if value, ok := service.Annotations[AnnotationLoadBalancingMode]; ok {
+		if IsValidLoadBalancingMode(LoadBalancingMode(value)) {
+			mode = LoadBalancingMode(value)
+		} else {
+			klog.Warningf("Ignoring invalid value %q of annotation %s for Service %s/%s", value, AnnotationLoadBalancingMode, service.Namespace, service.Name)
+		}
+	}
+	if value, ok := service.Annotations[AnnotationLocalEndpointWeight]; ok {
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 1 || weight > MaxLocalEndpointWeight {
+			klog.Warningf("Ignoring invalid value %q of annotation %s for Service %s/%s, it must be an integer between 1 and %d", value, AnnotationLocalEndpointWeight, service.Namespace, service.Name, MaxLocalEndpointWeight)
+		} else {
+			localEndpointWeight = uint16(weight)
+		}
+	}
+	return mode, localEndpointWeight
+}
+
 // NewEndpointInfo returns a new k8sproxy.Endpoint which abstracts an endpointsInfo.
 func NewEndpointInfo(baseInfo *k8sproxy.BaseEndpointInfo) k8sproxy.Endpoint {
 	return baseInfo
@@ -18,6 +18,7 @@ import (
 	"net"
 
 	"antrea.io/antrea/pkg/agent/config"
+	binding "antrea.io/antrea/pkg/ovs/openflow"
 )
 
 // Interface is the interface for routing container packets in host network.
@@ -26,8 +27,9 @@ type Interface interface {
 	// It should be idempotent and can be safely called on every startup.
 	Initialize(nodeConfig *config.NodeConfig, done func()) error
 
-	// Reconcile should remove orphaned routes and related configuration based on the desired podCIDRs. If IPv6 is enabled
-	// in the cluster, Reconcile should also remove the orphaned IPv6 neighbors.
+	// Reconcile should remove orphaned routes and related configuration based on the desired podCIDRs, which include
+	// the single-IP CIDRs of the Pod IPs routed with AddPodIPRoute. If IPv6 is enabled in the cluster, Reconcile should
+	// also remove the orphaned IPv6 neighbors.
 	Reconcile(podCIDRs []string) error
 
 	// AddRoutes should add routes to the provided podCIDR.
@@ -38,6 +40,16 @@ type Interface interface {
 	// It should do nothing if the routes don't exist, without error.
 	DeleteRoutes(podCIDR *net.IPNet) error
 
+	// AddPodIPRoute should add the route to a Pod IP which is not in the podCIDR of its Node. If the Node is the local
+	// Node, the route should go to the local gateway, otherwise it should go to the Node like the routes to its
+	// podCIDR, in which case peerGwIP is the gateway IP of the podCIDR of the Node in the same IP family.
+	// It should override the route if it already exists, without error.
+	AddPodIPRoute(podIP net.IP, nodeName string, nodeIP, peerGwIP net.IP) error
+
+	// DeletePodIPRoute should delete the route to a Pod IP added by AddPodIPRoute.
+	// It should do nothing if the route doesn't exist, without error.
+	DeletePodIPRoute(podIP net.IP) error
+
 	// MigrateRoutesToGw should move routes from device linkname to local gateway.
 	MigrateRoutesToGw(linkName string) error
 
@@ -51,6 +63,16 @@ type Interface interface {
 	// DeleteSNATRule should delete rule to SNAT outgoing traffic with the mark.
 	DeleteSNATRule(mark uint32) error
 
+	// AddNodePort should add configurations to redirect the NodePort Service traffic received on the provided
+	// nodePortAddresses to OVS. If isLocal is true, the source IP of the traffic should be preserved; otherwise the
+	// traffic should be masqueraded, so that the reply traffic can be sent back through this Node.
+	// It should override the configurations if they already exist, without error.
+	AddNodePort(nodePortAddresses []net.IP, port uint16, protocol binding.Protocol, isLocal bool) error
+
+	// DeleteNodePort should delete the configurations added by AddNodePort.
+	// It should do nothing if the configurations don't exist, without error.
+	DeleteNodePort(nodePortAddresses []net.IP, port uint16, protocol binding.Protocol) error
+
 	// Run starts the sync loop.
 	Run(stopCh <-chan struct{})
 }
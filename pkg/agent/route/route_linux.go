@@ -20,6 +20,7 @@ import (
 	"net"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,8 +36,11 @@ import (
 	"antrea.io/antrea/pkg/agent/util"
 	"antrea.io/antrea/pkg/agent/util/ipset"
 	"antrea.io/antrea/pkg/agent/util/iptables"
+	"antrea.io/antrea/pkg/agent/util/sysctl"
+	binding "antrea.io/antrea/pkg/ovs/openflow"
 	"antrea.io/antrea/pkg/ovs/ovsconfig"
 	"antrea.io/antrea/pkg/util/env"
+	utilip "antrea.io/antrea/pkg/util/ip"
 )
 
 const (
@@ -48,9 +52,21 @@ const (
 	antreaPodIPSet = "ANTREA-POD-IP"
 	// antreaPodIP6Set contains all IPv6 Pod CIDRs of this cluster.
 	antreaPodIP6Set = "ANTREA-POD-IP6"
+	// antreaNodePortIPSet contains all the NodePort IP address and protocol-port pairs of this Node.
+	antreaNodePortIPSet = "ANTREA-NODEPORT-IP"
+	// antreaNodePortIP6Set contains all the IPv6 NodePort IP address and protocol-port pairs of this Node.
+	antreaNodePortIP6Set = "ANTREA-NODEPORT-IP6"
+	// antreaNodePortLocalIPSet contains the virtual NodePort DNAT IP and protocol-port pairs of the NodePort
+	// Services whose externalTrafficPolicy is Local. The traffic to these Services is not masqueraded.
+	antreaNodePortLocalIPSet = "ANTREA-NODEPORT-LOCAL"
+	// antreaNodePortLocalIP6Set is the IPv6 counterpart of antreaNodePortLocalIPSet.
+	antreaNodePortLocalIP6Set = "ANTREA-NODEPORT-LOCAL6"
+
+	loopbackCIDR = "127.0.0.0/8"
 
 	// Antrea managed iptables chains.
 	antreaForwardChain     = "ANTREA-FORWARD"
+	antreaInputChain       = "ANTREA-INPUT"
 	antreaPreRoutingChain  = "ANTREA-PREROUTING"
 	antreaPostRoutingChain = "ANTREA-POSTROUTING"
 	antreaOutputChain      = "ANTREA-OUTPUT"
@@ -74,6 +90,7 @@ type Client struct {
 	nodeConfig    *config.NodeConfig
 	networkConfig *config.NetworkConfig
 	noSNAT        bool
+	proxyNodePort bool
 	serviceCIDR   *net.IPNet
 	ipt           *iptables.Client
 	// nodeRoutes caches ip routes to remote Pods. It's a map of podCIDR to routes.
@@ -82,6 +99,9 @@ type Client struct {
 	nodeNeighbors sync.Map
 	// markToSNATIP caches marks to SNAT IPs. It's used in Egress feature.
 	markToSNATIP sync.Map
+	// nodePortIPSetEntries caches the entries of the NodePort ipsets. It's a map of ipset entry to ipset name. It's
+	// used when AntreaProxy handles NodePort Services.
+	nodePortIPSetEntries sync.Map
 	// iptablesInitialized is used to notify when iptables initialization is done.
 	iptablesInitialized chan struct{}
 }
@@ -89,11 +109,12 @@ type Client struct {
 // NewClient returns a route client.
 // TODO: remove param serviceCIDR after kube-proxy is replaced by Antrea Proxy. This param is not used in this file;
 // leaving it here is to be compatible with the implementation on Windows.
-func NewClient(serviceCIDR *net.IPNet, networkConfig *config.NetworkConfig, noSNAT bool) (*Client, error) {
+func NewClient(serviceCIDR *net.IPNet, networkConfig *config.NetworkConfig, noSNAT, proxyNodePort bool) (*Client, error) {
 	return &Client{
 		serviceCIDR:   serviceCIDR,
 		networkConfig: networkConfig,
 		noSNAT:        noSNAT,
+		proxyNodePort: proxyNodePort,
 	}, nil
 }
 
@@ -107,6 +128,15 @@ func (c *Client) Initialize(nodeConfig *config.NodeConfig, done func()) error {
 	if err := c.syncIPSet(); err != nil {
 		return fmt.Errorf("failed to initialize ipset: %v", err)
 	}
+	// The NodePort ipsets will be populated by AntreaProxy after it syncs the Services, remove the stale entries
+	// left by the previous agent.
+	if c.proxyNodePort {
+		for _, ipsetName := range []string{antreaNodePortIPSet, antreaNodePortIP6Set, antreaNodePortLocalIPSet, antreaNodePortLocalIP6Set} {
+			if err := ipset.FlushIPSet(ipsetName); err != nil {
+				return fmt.Errorf("failed to initialize ipset: %v", err)
+			}
+		}
+	}
 
 	// Sets up the iptables infrastructure required to route packets in host network.
 	// It's called in a goroutine because xtables lock may not be acquired immediately.
@@ -219,6 +249,33 @@ func (c *Client) syncIPSet() error {
 			}
 		}
 	}
+
+	if c.proxyNodePort {
+		for _, set := range []struct {
+			name   string
+			isIPv6 bool
+		}{
+			{antreaNodePortIPSet, false},
+			{antreaNodePortIP6Set, true},
+			{antreaNodePortLocalIPSet, false},
+			{antreaNodePortLocalIP6Set, true},
+		} {
+			if err := ipset.CreateIPSet(set.name, ipset.HashIPPort, set.isIPv6); err != nil {
+				return err
+			}
+		}
+		// Restore the NodePort entries which have been added by AntreaProxy.
+		var err error
+		c.nodePortIPSetEntries.Range(func(key, value interface{}) bool {
+			if err = ipset.AddEntry(value.(string), key.(string)); err != nil {
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -229,6 +286,27 @@ func getIPSetName(ip net.IP) string {
 	return antreaPodIPSet
 }
 
+func getNodePortIPSetName(isIPv6 bool) string {
+	if isIPv6 {
+		return antreaNodePortIP6Set
+	}
+	return antreaNodePortIPSet
+}
+
+func getNodePortLocalIPSetName(isIPv6 bool) string {
+	if isIPv6 {
+		return antreaNodePortLocalIP6Set
+	}
+	return antreaNodePortLocalIPSet
+}
+
+func getVirtualNodePortDNATIP(isIPv6 bool) net.IP {
+	if isIPv6 {
+		return config.VirtualNodePortDNATIPv6
+	}
+	return config.VirtualNodePortDNATIPv4
+}
+
 // writeEKSMangleRule writes an additional iptables mangle rule to the
 // iptablesData buffer, which is required to ensure that the reverse path for
 // NodePort Service traffic is correct on EKS.
@@ -273,6 +351,13 @@ func (c *Client) syncIPTables() error {
 		{iptables.MangleTable, iptables.PreRoutingChain, antreaMangleChain, "Antrea: jump to Antrea mangle rules"}, // TODO: unify the chain naming style
 		{iptables.MangleTable, iptables.OutputChain, antreaOutputChain, "Antrea: jump to Antrea output rules"},
 	}
+	if c.proxyNodePort {
+		jumpRules = append(jumpRules,
+			struct{ table, srcChain, dstChain, comment string }{iptables.NATTable, iptables.PreRoutingChain, antreaPreRoutingChain, "Antrea: jump to Antrea prerouting rules"},
+			struct{ table, srcChain, dstChain, comment string }{iptables.NATTable, iptables.OutputChain, antreaOutputChain, "Antrea: jump to Antrea output rules"},
+			struct{ table, srcChain, dstChain, comment string }{iptables.FilterTable, iptables.InputChain, antreaInputChain, "Antrea: jump to Antrea input rules"},
+		)
+	}
 	for _, rule := range jumpRules {
 		if err := c.ipt.EnsureChain(rule.table, rule.dstChain); err != nil {
 			return err
@@ -297,7 +382,7 @@ func (c *Client) syncIPTables() error {
 	})
 	// Use iptables-restore to configure IPv4 settings.
 	if v4Enabled {
-		iptablesData := c.restoreIptablesData(c.nodeConfig.PodIPv4CIDR, antreaPodIPSet, snatMarkToIPv4)
+		iptablesData := c.restoreIptablesData(c.nodeConfig.PodIPv4CIDR, antreaPodIPSet, snatMarkToIPv4, false)
 		// Setting --noflush to keep the previous contents (i.e. non antrea managed chains) of the tables.
 		if err := c.ipt.Restore(iptablesData.Bytes(), false, false); err != nil {
 			return err
@@ -306,7 +391,7 @@ func (c *Client) syncIPTables() error {
 
 	// Use ip6tables-restore to configure IPv6 settings.
 	if v6Enabled {
-		iptablesData := c.restoreIptablesData(c.nodeConfig.PodIPv6CIDR, antreaPodIP6Set, snatMarkToIPv6)
+		iptablesData := c.restoreIptablesData(c.nodeConfig.PodIPv6CIDR, antreaPodIP6Set, snatMarkToIPv6, true)
 		// Setting --noflush to keep the previous contents (i.e. non antrea managed chains) of the tables.
 		if err := c.ipt.Restore(iptablesData.Bytes(), false, true); err != nil {
 			return err
@@ -315,7 +400,7 @@ func (c *Client) syncIPTables() error {
 	return nil
 }
 
-func (c *Client) restoreIptablesData(podCIDR *net.IPNet, podIPSet string, snatMarkToIP map[uint32]net.IP) *bytes.Buffer {
+func (c *Client) restoreIptablesData(podCIDR *net.IPNet, podIPSet string, snatMarkToIP map[uint32]net.IP, isIPv6 bool) *bytes.Buffer {
 	// Create required rules in the antrea chains.
 	// Use iptables-restore as it flushes the involved chains and creates the desired rules
 	// with a single call, instead of string matching to clean up stale rules.
@@ -380,6 +465,22 @@ func (c *Client) restoreIptablesData(podCIDR *net.IPNet, podIPSet string, snatMa
 
 	writeLine(iptablesData, "*filter")
 	writeLine(iptablesData, iptables.MakeChainLine(antreaForwardChain))
+	if c.proxyNodePort {
+		writeLine(iptablesData, iptables.MakeChainLine(antreaInputChain))
+	}
+	if c.proxyNodePort && !isIPv6 {
+		// route_localnet is enabled on the host gateway interface for the NodePort traffic sent to 127.0.0.1. Like
+		// kube-proxy, drop the packets to the loopback addresses which don't come from them, so that the Pods and
+		// the external hosts cannot reach the services of the Node bound to the loopback addresses. The NodePort
+		// traffic sent to 127.0.0.1 is DNAT'd in the OUTPUT chain and doesn't go through the INPUT chain.
+		writeLine(iptablesData, []string{
+			"-A", antreaInputChain,
+			"-m", "comment", "--comment", `"Antrea: drop non-local packets to loopback addresses"`,
+			"-d", loopbackCIDR, "!", "-s", loopbackCIDR,
+			"-m", "conntrack", "!", "--ctstate", "RELATED,ESTABLISHED,DNAT",
+			"-j", iptables.DropTarget,
+		}...)
+	}
 	writeLine(iptablesData, []string{
 		"-A", antreaForwardChain,
 		"-m", "comment", "--comment", `"Antrea: accept packets from local Pods"`,
@@ -395,7 +496,38 @@ func (c *Client) restoreIptablesData(podCIDR *net.IPNet, podIPSet string, snatMa
 	writeLine(iptablesData, "COMMIT")
 
 	writeLine(iptablesData, "*nat")
+	if c.proxyNodePort {
+		writeLine(iptablesData, iptables.MakeChainLine(antreaPreRoutingChain))
+		writeLine(iptablesData, iptables.MakeChainLine(antreaOutputChain))
+	}
 	writeLine(iptablesData, iptables.MakeChainLine(antreaPostRoutingChain))
+	if c.proxyNodePort {
+		// DNAT the NodePort traffic received by the Node, and the NodePort traffic generated by the Node itself,
+		// to the virtual NodePort DNAT IP, which is routed to OVS through the host gateway interface. The
+		// destination port is not changed, AntreaProxy then load-balances the traffic in OVS.
+		// The NodePort traffic to 127.0.0.1 is only accepted from the Node itself: route_localnet is enabled on
+		// the host gateway interface for it, and the other packets to the loopback addresses are dropped in
+		// antreaInputChain.
+		nodePortIPSet := getNodePortIPSetName(isIPv6)
+		virtualNodePortDNATIP := getVirtualNodePortDNATIP(isIPv6)
+		preRoutingRule := []string{
+			"-A", antreaPreRoutingChain,
+			"-m", "comment", "--comment", `"Antrea: DNAT external to NodePort packets"`,
+		}
+		if !isIPv6 {
+			preRoutingRule = append(preRoutingRule, "!", "-d", loopbackCIDR)
+		}
+		writeLine(iptablesData, append(preRoutingRule,
+			"-m", "set", "--match-set", nodePortIPSet, "dst,dst",
+			"-j", iptables.DNATTarget, "--to-destination", virtualNodePortDNATIP.String(),
+		)...)
+		writeLine(iptablesData, []string{
+			"-A", antreaOutputChain,
+			"-m", "comment", "--comment", `"Antrea: DNAT local to NodePort packets"`,
+			"-m", "set", "--match-set", nodePortIPSet, "dst,dst",
+			"-j", iptables.DNATTarget, "--to-destination", virtualNodePortDNATIP.String(),
+		}...)
+	}
 	// Egress rules must be inserted before the default masquerade rule.
 	for snatMark, snatIP := range snatMarkToIP {
 		// Cannot reuse snatRuleSpec to generate the rule as it doesn't have "`" in the comment.
@@ -421,7 +553,7 @@ func (c *Client) restoreIptablesData(podCIDR *net.IPNet, podIPSet string, snatMa
 	// of the gateway's IP addresses, the traffic needs to be masqueraded. Otherwise, we observe
 	// that ARP requests may advertise a different source IP address, in which case they will be
 	// dropped by the SpoofGuard table in the OVS pipeline. See description for the arp_announce
-	// sysctl parameter.
+	// sysctl parameter. This also masquerades the NodePort traffic sent to 127.0.0.1.
 	writeLine(iptablesData, []string{
 		"-A", antreaPostRoutingChain,
 		"-m", "comment", "--comment", `"Antrea: masquerade LOCAL traffic"`,
@@ -431,6 +563,21 @@ func (c *Client) restoreIptablesData(podCIDR *net.IPNet, podIPSet string, snatMa
 		"-j", iptables.MasqueradeTarget, "--random-fully",
 	}...)
 
+	if c.proxyNodePort {
+		// Masquerade the external NodePort traffic, unless the externalTrafficPolicy of the Service is Local. The
+		// Endpoint may run on a remote Node, and the reply traffic must come back through this Node to be un-DNAT'd.
+		// Traffic to Services with externalTrafficPolicy Local is only load-balanced to local Endpoints, so the
+		// source IP can be preserved.
+		writeLine(iptablesData, []string{
+			"-A", antreaPostRoutingChain,
+			"-m", "comment", "--comment", `"Antrea: masquerade external to NodePort packets"`,
+			"-o", c.nodeConfig.GatewayConfig.Name,
+			"-d", getVirtualNodePortDNATIP(isIPv6).String(),
+			"-m", "set", "!", "--match-set", getNodePortLocalIPSetName(isIPv6), "dst,dst",
+			"-j", iptables.MasqueradeTarget,
+		}...)
+	}
+
 	writeLine(iptablesData, "COMMIT")
 	return iptablesData
 }
@@ -443,9 +590,64 @@ func (c *Client) initIPRoutes() error {
 			return fmt.Errorf("failed to add address %s to gw %s: %v", gwIP, gwLink.Attrs().Name, err)
 		}
 	}
+	if c.proxyNodePort {
+		if config.IsIPv4Enabled(c.nodeConfig, c.networkConfig.TrafficEncapMode) {
+			if err := c.addVirtualNodePortDNATIPRoute(false); err != nil {
+				return err
+			}
+			// The NodePort traffic sent to 127.0.0.1 is DNAT'd to the virtual NodePort DNAT IP before its source IP
+			// is masqueraded, the kernel only routes it to the host gateway interface if route_localnet is enabled.
+			if err := sysctl.EnsureSysctlNetValue(fmt.Sprintf("ipv4/conf/%s/route_localnet", c.nodeConfig.GatewayConfig.Name), 1); err != nil {
+				return err
+			}
+		}
+		if config.IsIPv6Enabled(c.nodeConfig, c.networkConfig.TrafficEncapMode) {
+			if err := c.addVirtualNodePortDNATIPRoute(true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addVirtualNodePortDNATIPRoute adds the route and the neighbor of the virtual NodePort DNAT IP on the host gateway
+// interface, so that the DNAT'd NodePort traffic can be sent to OVS.
+func (c *Client) addVirtualNodePortDNATIPRoute(isIPv6 bool) error {
+	virtualIP := getVirtualNodePortDNATIP(isIPv6)
+	mask := net.CIDRMask(32, 32)
+	family := netlink.FAMILY_V4
+	if isIPv6 {
+		mask = net.CIDRMask(128, 128)
+		family = netlink.FAMILY_V6
+	}
+	route := &netlink.Route{
+		Dst:       &net.IPNet{IP: virtualIP, Mask: mask},
+		Scope:     netlink.SCOPE_LINK,
+		LinkIndex: c.nodeConfig.GatewayConfig.LinkIndex,
+	}
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route for virtual NodePort DNAT IP %s: %v", virtualIP, err)
+	}
+	c.nodeRoutes.Store(route.Dst.String(), []*netlink.Route{route})
+	neigh := &netlink.Neigh{
+		LinkIndex:    c.nodeConfig.GatewayConfig.LinkIndex,
+		Family:       family,
+		State:        netlink.NUD_PERMANENT,
+		IP:           virtualIP,
+		HardwareAddr: globalVMAC,
+	}
+	if err := netlink.NeighSet(neigh); err != nil {
+		return fmt.Errorf("failed to add neigh %v to gw %s: %v", neigh, c.nodeConfig.GatewayConfig.Name, err)
+	}
 	return nil
 }
 
+// isVirtualNodePortDNATIP returns true if the provided IP is the virtual NodePort DNAT IP and NodePort support is
+// enabled.
+func (c *Client) isVirtualNodePortDNATIP(ip net.IP) bool {
+	return c.proxyNodePort && (ip.Equal(config.VirtualNodePortDNATIPv4) || ip.Equal(config.VirtualNodePortDNATIPv6))
+}
+
 // Reconcile removes orphaned podCIDRs from ipset and removes routes to orphaned podCIDRs
 // based on the desired podCIDRs.
 func (c *Client) Reconcile(podCIDRs []string) error {
@@ -458,6 +660,10 @@ func (c *Client) Reconcile(podCIDRs []string) error {
 			return err
 		}
 		for _, entry := range entries {
+			// ipset lists the single-IP entries added by AddPodIPRoute without their prefix length.
+			if !strings.Contains(entry, "/") {
+				entry = utilip.NewHostIPNet(net.ParseIP(entry)).String()
+			}
 			if desiredPodCIDRs.Has(entry) {
 				continue
 			}
@@ -489,6 +695,9 @@ func (c *Client) Reconcile(podCIDRs []string) error {
 		if desiredPodCIDRs.Has(route.Dst.String()) {
 			continue
 		}
+		if c.isVirtualNodePortDNATIP(route.Dst.IP) {
+			continue
+		}
 		klog.Infof("Deleting unknown route %v", route)
 		if err := netlink.RouteDel(&route); err != nil && err != unix.ESRCH {
 			return err
@@ -510,6 +719,9 @@ func (c *Client) Reconcile(podCIDRs []string) error {
 		if desiredGWs.Has(neighIP) {
 			continue
 		}
+		if c.isVirtualNodePortDNATIP(actualNeigh.IP) {
+			continue
+		}
 		klog.V(4).Infof("Deleting orphaned IPv6 neighbor %v", actualNeigh)
 		if err := netlink.NeighDel(actualNeigh); err != nil {
 			return err
@@ -538,10 +750,14 @@ func (c *Client) listIPRoutesOnGW() ([]netlink.Route, error) {
 func getIPv6Gateways(podCIDRs []string) sets.String {
 	ipv6GWs := sets.NewString()
 	for _, podCIDR := range podCIDRs {
-		peerPodCIDRAddr, _, _ := net.ParseCIDR(podCIDR)
+		peerPodCIDRAddr, peerPodCIDR, _ := net.ParseCIDR(podCIDR)
 		if peerPodCIDRAddr.To4() != nil {
 			continue
 		}
+		// The single-IP CIDRs of the Pod IPs routed with AddPodIPRoute have no gateway.
+		if ones, bits := peerPodCIDR.Mask.Size(); ones == bits {
+			continue
+		}
 		peerGatewayIP := ip.NextIP(peerPodCIDRAddr)
 		ipv6GWs.Insert(peerGatewayIP.String())
 	}
@@ -657,6 +873,64 @@ func (c *Client) DeleteRoutes(podCIDR *net.IPNet) error {
 	return nil
 }
 
+// AddPodIPRoute adds the route to a Pod IP which is not in the podCIDR of its Node. It overrides the route if it
+// already exists.
+func (c *Client) AddPodIPRoute(podIP net.IP, nodeName string, nodeIP, peerGwIP net.IP) error {
+	dst := utilip.NewHostIPNet(podIP)
+	dstStr := dst.String()
+	// Add the IP to antreaPodIPSet so that packets to it won't be masqueraded when they leave the host.
+	if err := ipset.AddEntry(getIPSetName(podIP), dstStr); err != nil {
+		return err
+	}
+	route := &netlink.Route{
+		Dst: dst,
+	}
+	if nodeName == c.nodeConfig.Name {
+		// The Pod is on the local Node, it is reachable on the link of the local gateway.
+		route.LinkIndex = c.nodeConfig.GatewayConfig.LinkIndex
+		route.Scope = netlink.SCOPE_LINK
+	} else if c.networkConfig.TrafficEncapMode.NeedsEncapToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
+		// For IPv6, the route to peerGwIP and its neighbor are added with the routes to the podCIDR of the Node.
+		if podIP.To4() != nil {
+			route.Flags = int(netlink.FLAG_ONLINK)
+		}
+		route.LinkIndex = c.nodeConfig.GatewayConfig.LinkIndex
+		route.Gw = peerGwIP
+	} else if c.networkConfig.TrafficEncapMode.NeedsDirectRoutingToPeer(nodeIP, c.nodeConfig.NodeIPAddr) {
+		route.Gw = nodeIP
+	} else {
+		// NoEncap traffic to Node on the different subnet. It is handled by host default route.
+		return nil
+	}
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("failed to install route to Pod IP %s on Node %s with netlink. Route config: %s. Error: %v", podIP, nodeName, route.String(), err)
+	}
+	c.nodeRoutes.Store(dstStr, []*netlink.Route{route})
+	return nil
+}
+
+// DeletePodIPRoute deletes the route to a Pod IP added by AddPodIPRoute. It does nothing if the route doesn't exist.
+func (c *Client) DeletePodIPRoute(podIP net.IP) error {
+	dst := utilip.NewHostIPNet(podIP)
+	dstStr := dst.String()
+	if err := ipset.DelEntry(getIPSetName(podIP), dstStr); err != nil {
+		return err
+	}
+	routes, exists := c.nodeRoutes.Load(dstStr)
+	if !exists {
+		return nil
+	}
+	c.nodeRoutes.Delete(dstStr)
+	for _, r := range routes.([]*netlink.Route) {
+		klog.V(4).Infof("Deleting route %v", r)
+		if err := netlink.RouteDel(r); err != nil && err != unix.ESRCH {
+			c.nodeRoutes.Store(dstStr, routes)
+			return err
+		}
+	}
+	return nil
+}
+
 // Join all words with spaces, terminate with newline and write to buf.
 func writeLine(buf *bytes.Buffer, words ...string) {
 	// We avoid strings.Join for performance reasons.
@@ -773,3 +1047,58 @@ func (c *Client) DeleteSNATRule(mark uint32) error {
 	snatIP := value.(net.IP)
 	return c.ipt.DeleteRule(iptables.NATTable, antreaPostRoutingChain, c.snatRuleSpec(snatIP, mark))
 }
+
+// nodePortIPSetEntry returns the ipset entry for the given IP, port and protocol, e.g. "10.0.0.1,tcp:30001".
+func nodePortIPSetEntry(ip net.IP, port uint16, protocol binding.Protocol) string {
+	// The protocol of ipset entries doesn't have the IP family suffix.
+	protocolStr := strings.TrimSuffix(string(protocol), "v6")
+	return fmt.Sprintf("%s,%s:%d", ip.String(), protocolStr, port)
+}
+
+// AddNodePort adds the NodePort IP address and protocol-port pairs to the NodePort ipsets, so that the NodePort
+// traffic is DNAT'd to the virtual NodePort DNAT IP by the iptables rules installed in syncIPTables.
+func (c *Client) AddNodePort(nodePortAddresses []net.IP, port uint16, protocol binding.Protocol, isLocal bool) error {
+	isIPv6 := protocol == binding.ProtocolTCPv6 || protocol == binding.ProtocolUDPv6 || protocol == binding.ProtocolSCTPv6
+	ipsetName := getNodePortIPSetName(isIPv6)
+	for _, nodePortAddress := range nodePortAddresses {
+		entry := nodePortIPSetEntry(nodePortAddress, port, protocol)
+		if err := ipset.AddEntry(ipsetName, entry); err != nil {
+			return err
+		}
+		c.nodePortIPSetEntries.Store(entry, ipsetName)
+	}
+	localIPSetName := getNodePortLocalIPSetName(isIPv6)
+	localEntry := nodePortIPSetEntry(getVirtualNodePortDNATIP(isIPv6), port, protocol)
+	if isLocal {
+		if err := ipset.AddEntry(localIPSetName, localEntry); err != nil {
+			return err
+		}
+		c.nodePortIPSetEntries.Store(localEntry, localIPSetName)
+	} else {
+		// The externalTrafficPolicy of the Service may have been changed from Local to Cluster.
+		if err := ipset.DelEntry(localIPSetName, localEntry); err != nil {
+			return err
+		}
+		c.nodePortIPSetEntries.Delete(localEntry)
+	}
+	return nil
+}
+
+// DeleteNodePort deletes the NodePort IP address and protocol-port pairs from the NodePort ipsets.
+func (c *Client) DeleteNodePort(nodePortAddresses []net.IP, port uint16, protocol binding.Protocol) error {
+	isIPv6 := protocol == binding.ProtocolTCPv6 || protocol == binding.ProtocolUDPv6 || protocol == binding.ProtocolSCTPv6
+	ipsetName := getNodePortIPSetName(isIPv6)
+	for _, nodePortAddress := range nodePortAddresses {
+		entry := nodePortIPSetEntry(nodePortAddress, port, protocol)
+		if err := ipset.DelEntry(ipsetName, entry); err != nil {
+			return err
+		}
+		c.nodePortIPSetEntries.Delete(entry)
+	}
+	localEntry := nodePortIPSetEntry(getVirtualNodePortDNATIP(isIPv6), port, protocol)
+	if err := ipset.DelEntry(getNodePortLocalIPSetName(isIPv6), localEntry); err != nil {
+		return err
+	}
+	c.nodePortIPSetEntries.Delete(localEntry)
+	return nil
+}
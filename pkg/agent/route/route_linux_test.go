@@ -0,0 +1,81 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"antrea.io/antrea/pkg/agent/config"
+)
+
+func TestRestoreIptablesDataLoopbackDrop(t *testing.T) {
+	_, podCIDR, _ := net.ParseCIDR("10.10.0.0/24")
+	_, podIPv6CIDR, _ := net.ParseCIDR("fd12:ab34:34:a001::/64")
+	dropRule := `-A ANTREA-INPUT -m comment --comment "Antrea: drop non-local packets to loopback addresses" -d 127.0.0.0/8 ! -s 127.0.0.0/8 -m conntrack ! --ctstate RELATED,ESTABLISHED,DNAT -j DROP`
+	tests := []struct {
+		name          string
+		proxyNodePort bool
+		podCIDR       *net.IPNet
+		isIPv6        bool
+		expectedChain bool
+		expectedDrop  bool
+	}{
+		{
+			name:          "NodePort IPv4",
+			proxyNodePort: true,
+			podCIDR:       podCIDR,
+			expectedChain: true,
+			expectedDrop:  true,
+		},
+		{
+			name:          "NodePort IPv6",
+			proxyNodePort: true,
+			podCIDR:       podIPv6CIDR,
+			isIPv6:        true,
+			expectedChain: true,
+		},
+		{
+			name:    "no NodePort",
+			podCIDR: podCIDR,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{
+				nodeConfig: &config.NodeConfig{
+					GatewayConfig: &config.GatewayConfig{Name: "antrea-gw0"},
+				},
+				networkConfig: &config.NetworkConfig{TrafficEncapMode: config.TrafficEncapModeNoEncap},
+				noSNAT:        true,
+				proxyNodePort: tt.proxyNodePort,
+			}
+			lines := strings.Split(c.restoreIptablesData(tt.podCIDR, getIPSetName(tt.podCIDR.IP), nil, tt.isIPv6).String(), "\n")
+			if tt.expectedChain {
+				assert.Contains(t, lines, ":ANTREA-INPUT - [0:0]")
+			} else {
+				assert.NotContains(t, lines, ":ANTREA-INPUT - [0:0]")
+			}
+			if tt.expectedDrop {
+				assert.Contains(t, lines, dropRule)
+			} else {
+				assert.NotContains(t, lines, dropRule)
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"antrea.io/antrea/pkg/agent/config"
 	"antrea.io/antrea/pkg/agent/util"
 	"antrea.io/antrea/pkg/agent/util/winfirewall"
+	binding "antrea.io/antrea/pkg/ovs/openflow"
 )
 
 const (
@@ -46,7 +47,7 @@ type Client struct {
 
 // NewClient returns a route client.
 // Todo: remove param serviceCIDR after kube-proxy is replaced by Antrea Proxy completely.
-func NewClient(serviceCIDR *net.IPNet, networkConfig *config.NetworkConfig, noSNAT bool) (*Client, error) {
+func NewClient(serviceCIDR *net.IPNet, networkConfig *config.NetworkConfig, noSNAT, proxyNodePort bool) (*Client, error) {
 	return &Client{
 		networkConfig: networkConfig,
 		serviceCIDR:   serviceCIDR,
@@ -165,6 +166,16 @@ func (c *Client) DeleteRoutes(podCIDR *net.IPNet) error {
 	return nil
 }
 
+// AddPodIPRoute is not supported on Windows.
+func (c *Client) AddPodIPRoute(podIP net.IP, nodeName string, nodeIP, peerGwIP net.IP) error {
+	return errors.New("AddPodIPRoute is unsupported on Windows")
+}
+
+// DeletePodIPRoute is not supported on Windows.
+func (c *Client) DeletePodIPRoute(podIP net.IP) error {
+	return errors.New("DeletePodIPRoute is unsupported on Windows")
+}
+
 // MigrateRoutesToGw is not supported on Windows.
 func (c *Client) MigrateRoutesToGw(linkName string) error {
 	return errors.New("MigrateRoutesToGw is unsupported on Windows")
@@ -237,3 +248,13 @@ func (c *Client) AddSNATRule(snatIP net.IP, mark uint32) error {
 func (c *Client) DeleteSNATRule(mark uint32) error {
 	return nil
 }
+
+// AddNodePort is not supported on Windows and returns immediately.
+func (c *Client) AddNodePort(nodePortAddresses []net.IP, port uint16, protocol binding.Protocol, isLocal bool) error {
+	return nil
+}
+
+// DeleteNodePort is not supported on Windows and returns immediately.
+func (c *Client) DeleteNodePort(nodePortAddresses []net.IP, port uint16, protocol binding.Protocol) error {
+	return nil
+}
@@ -53,6 +53,7 @@ const (
 	IPNetAddr
 	OFPortAddr
 	L4PortAddr
+	ICMPAddr
 	UnSupported
 )
 
@@ -82,6 +83,13 @@ type PolicyRule struct {
 	TableID       binding.TableIDType
 	PolicyRef     *v1beta2.NetworkPolicyReference
 	EnableLogging bool
+	// EnforcementMode is the enforcement mode of the rule. The action of the rule is not applied in Audit mode.
+	EnforcementMode secv1alpha1.RuleEnforcementMode
+	// TierPriority is the priority of the Tier of an Antrea-native policy rule. It is nil for K8s NetworkPolicy rules.
+	TierPriority *int32
+	// L7RuleVlanID is the VLAN ID used to tag the packets redirected to the L7 engine. It is only set for the
+	// Antrea-native policy rules with L7 protocols.
+	L7RuleVlanID *uint32
 }
 
 // IsAntreaNetworkPolicyRule returns if a PolicyRule is created for Antrea NetworkPolicy types.
@@ -89,6 +97,11 @@ func (r *PolicyRule) IsAntreaNetworkPolicyRule() bool {
 	return r.PolicyRef.Type != v1beta2.K8sNetworkPolicy
 }
 
+// IsAuditRule returns if a PolicyRule is in Audit mode, in which case matching traffic is only logged and counted.
+func (r *PolicyRule) IsAuditRule() bool {
+	return r.EnforcementMode == secv1alpha1.RuleEnforcementModeAudit
+}
+
 // Priority is a struct that is composed of Antrea NetworkPolicy priority, rule priority and Tier priority.
 // It is used as the basic unit for priority sorting.
 type Priority struct {
@@ -149,6 +162,13 @@ type BitRange struct {
 	Mask  *uint16
 }
 
+// An ICMPMatch is a representation of the type and code of the ICMP or ICMPv6
+// messages to match. A nil field matches all values.
+type ICMPMatch struct {
+	ICMPType *int32
+	ICMPCode *int32
+}
+
 // EntityReference represents a reference to either a Pod or an ExternalEntity.
 type EntityReference struct {
 	// Pod maintains the reference to the Pod.
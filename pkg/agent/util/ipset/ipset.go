@@ -28,6 +28,8 @@ const (
 	// The lookup time grows linearly with the number of the different prefix values added to the set.
 	HashNet SetType = "hash:net"
 	HashIP  SetType = "hash:ip"
+	// The hash:ip,port set type uses a hash to store IP address and protocol-port pairs.
+	HashIPPort SetType = "hash:ip,port"
 )
 
 // memberPattern is used to match the members part of ipset list result.
@@ -65,6 +67,15 @@ func DelEntry(name string, entry string) error {
 	return nil
 }
 
+// FlushIPSet deletes all the entries of the set.
+func FlushIPSet(name string) error {
+	cmd := exec.Command("ipset", "flush", name)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error flushing ipset %s: %v", name, err)
+	}
+	return nil
+}
+
 // ListEntries lists all the entries of the set.
 func ListEntries(name string) ([]string, error) {
 	cmd := exec.Command("ipset", "list", name)
@@ -82,3 +93,27 @@ func ListEntries(name string) ([]string, error) {
 	}
 	return entries, nil
 }
+
+// DestroyIPSet destroys the set, it will ignore error when the set doesn't exist.
+func DestroyIPSet(name string) error {
+	output, err := exec.Command("ipset", "destroy", name).CombinedOutput()
+	if err != nil && !strings.Contains(string(output), "does not exist") {
+		return fmt.Errorf("error destroying ipset %s: %v", name, err)
+	}
+	return nil
+}
+
+// ListIPSets lists the names of all the sets.
+func ListIPSets() ([]string, error) {
+	output, err := exec.Command("ipset", "list", "-n").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error listing ipsets: %v", err)
+	}
+	var names []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) > 0 {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
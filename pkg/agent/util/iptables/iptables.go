@@ -35,13 +35,19 @@ const (
 	RawTable    = "raw"
 
 	AcceptTarget     = "ACCEPT"
+	DropTarget       = "DROP"
+	RejectTarget     = "REJECT"
+	ReturnTarget     = "RETURN"
+	LogTarget        = "LOG"
 	MasqueradeTarget = "MASQUERADE"
 	MarkTarget       = "MARK"
 	ConnTrackTarget  = "CT"
 	NoTrackTarget    = "NOTRACK"
 	SNATTarget       = "SNAT"
+	DNATTarget       = "DNAT"
 
 	PreRoutingChain  = "PREROUTING"
+	InputChain       = "INPUT"
 	ForwardChain     = "FORWARD"
 	PostRoutingChain = "POSTROUTING"
 	OutputChain      = "OUTPUT"
@@ -149,3 +149,60 @@ func GetIPWithFamily(ips []net.IP, addrFamily uint8) (net.IP, error) {
 		return nil, errors.New("no IP found with IPv4 AddressFamily")
 	}
 }
+
+// GetAllNodeAddresses gets all the IPv4 and IPv6 addresses configured on the
+// Node's interfaces, excluding loopback and link-local addresses and the
+// addresses of the interfaces listed in excludeDevices.
+func GetAllNodeAddresses(excludeDevices []string) ([]net.IP, []net.IP, error) {
+	linkList, err := net.Interfaces()
+	if err != nil {
+		return nil, nil, err
+	}
+	var nodeAddressesIPv4, nodeAddressesIPv6 []net.IP
+	for _, link := range linkList {
+		excluded := false
+		for _, device := range excludeDevices {
+			if link.Name == device {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		addrList, err := link.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrList {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			if ipNet.IP.To4() != nil {
+				nodeAddressesIPv4 = append(nodeAddressesIPv4, ipNet.IP)
+			} else {
+				nodeAddressesIPv6 = append(nodeAddressesIPv6, ipNet.IP)
+			}
+		}
+	}
+	return nodeAddressesIPv4, nodeAddressesIPv6, nil
+}
+
+// FilterIPsByCIDRs returns the IPs which are included in any of the provided
+// CIDRs. If no CIDR is provided, all the IPs are returned.
+func FilterIPsByCIDRs(ips []net.IP, cidrs []*net.IPNet) []net.IP {
+	if len(cidrs) == 0 {
+		return ips
+	}
+	var filtered []net.IP
+	for _, ip := range ips {
+		for _, cidr := range cidrs {
+			if cidr.Contains(ip) {
+				filtered = append(filtered, ip)
+				break
+			}
+		}
+	}
+	return filtered
+}
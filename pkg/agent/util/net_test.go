@@ -61,3 +61,32 @@ func TestGetDefaultLocalNodeAddr(t *testing.T) {
 	}
 	t.Logf("IP obtained %s, %v", ip, dev)
 }
+
+func TestFilterIPsByCIDRs(t *testing.T) {
+	ips := []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("192.168.1.10"), net.ParseIP("2001:db8::1")}
+	_, cidr1, _ := net.ParseCIDR("192.168.1.0/24")
+	_, cidr2, _ := net.ParseCIDR("2001:db8::/64")
+
+	tests := []struct {
+		name     string
+		cidrs    []*net.IPNet
+		expected []net.IP
+	}{
+		{"no CIDR", nil, ips},
+		{"IPv4 CIDR", []*net.IPNet{cidr1}, []net.IP{ips[1]}},
+		{"IPv4 and IPv6 CIDRs", []*net.IPNet{cidr1, cidr2}, []net.IP{ips[1], ips[2]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterIPsByCIDRs(ips, tt.cidrs)
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, filtered)
+			}
+			for i := range filtered {
+				if !filtered[i].Equal(tt.expected[i]) {
+					t.Errorf("Expected %v, got %v", tt.expected, filtered)
+				}
+			}
+		})
+	}
+}
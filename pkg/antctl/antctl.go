@@ -38,6 +38,7 @@ import (
 	controllerinforest "antrea.io/antrea/pkg/apiserver/registry/system/controllerinfo"
 	"antrea.io/antrea/pkg/client/clientset/versioned/scheme"
 	controllernetworkpolicy "antrea.io/antrea/pkg/controller/networkpolicy"
+	"antrea.io/antrea/pkg/flowaggregator/apiserver/handlers/flows"
 )
 
 // CommandList defines all commands that could be used in the antctl for both agents
@@ -347,6 +348,64 @@ var CommandList = &commandList{
 			commandGroup:        get,
 			transformedResponse: reflect.TypeOf(ovsflows.Response{}),
 		},
+		{
+			use:     "flows",
+			aliases: []string{"flow"},
+			short:   "Print recent aggregated flow records",
+			long:    "Print the aggregated flow records which were exported recently by the Flow Aggregator, the most recent first.",
+			example: `  Get all the recent flow records
+  $ antctl get flows
+  Get the flow records of the Pods in a Namespace in the last 5 minutes
+  $ antctl get flows -n ns1 --since 5m
+  Get the flow records of a Pod
+  $ antctl get flows -n ns1 -p pod1
+  Get the flow records to a Service
+  $ antctl get flows -S ns1/svc1
+  Get the 10 most recent flow records dropped by a NetworkPolicy
+  $ antctl get flows --policy np1 --action Drop --limit 10`,
+			flowAggregatorEndpoint: &endpoint{
+				nonResourceEndpoint: &nonResourceEndpoint{
+					path: "/flows",
+					params: []flagInfo{
+						{
+							name:      "namespace",
+							usage:     "Namespace of the source or destination Pod",
+							shorthand: "n",
+						},
+						{
+							name:      "pod",
+							usage:     "Name of the source or destination Pod",
+							shorthand: "p",
+						},
+						{
+							name:      "service",
+							usage:     "Destination Service, with format <namespace>/<name>",
+							shorthand: "S",
+						},
+						{
+							name:  "policy",
+							usage: "Name of the ingress or egress NetworkPolicy",
+						},
+						{
+							name:            "action",
+							supportedValues: []string{"Allow", "Drop", "Reject"},
+							usage:           "Action of the ingress or egress NetworkPolicy rule, Allow, Drop or Reject",
+						},
+						{
+							name:  "since",
+							usage: "Only get the flow records exported in this duration, e.g. 5m",
+						},
+						{
+							name:  "limit",
+							usage: "Maximum number of flow records",
+						},
+					},
+					outputType: multiple,
+				},
+			},
+			commandGroup:        get,
+			transformedResponse: reflect.TypeOf(flows.Response{}),
+		},
 		{
 			use:   "trace-packet",
 			short: "OVS packet tracing",
@@ -431,6 +490,78 @@ var CommandList = &commandList{
 			},
 			transformedResponse: reflect.TypeOf(controllernetworkpolicy.EndpointQueryResponse{}),
 		},
+		{
+			use:   "reachability",
+			short: "Evaluate the network policies enforced on traffic between two endpoints.",
+			long:  "Evaluate all Tiers, priorities and K8s NetworkPolicies enforced on traffic between two endpoints and print the effective verdict along with the deciding rule. The evaluation is performed by the controller from its in-memory state, no packet is injected. An endpoint can be a Pod, specified as POD or NAMESPACE/POD, or an IP.",
+			example: `  Evaluate TCP traffic from Pod ns1/pod1 to port 80 of Pod ns2/pod2
+  $ antctl query reachability -S ns1/pod1 -D ns2/pod2 --port 80
+  Evaluate ICMP traffic from IP 10.10.0.1 to Pod pod1 of the default Namespace
+  $ antctl query reachability -S 10.10.0.1 -D pod1 -P ICMP
+`,
+			commandGroup: query,
+			controllerEndpoint: &endpoint{
+				nonResourceEndpoint: &nonResourceEndpoint{
+					path: "/reachability",
+					params: []flagInfo{
+						{
+							name:      "source",
+							usage:     "Source of the traffic, as POD, NAMESPACE/POD or IP",
+							shorthand: "S",
+						},
+						{
+							name:      "destination",
+							usage:     "Destination of the traffic, as POD, NAMESPACE/POD or IP",
+							shorthand: "D",
+						},
+						{
+							name:            "protocol",
+							usage:           "Protocol of the traffic",
+							shorthand:       "P",
+							defaultValue:    "TCP",
+							supportedValues: []string{"TCP", "UDP", "SCTP", "ICMP", "ICMPv6"},
+						},
+						{
+							name:  "port",
+							usage: "Destination port of the traffic, ignored for ICMP and ICMPv6",
+						},
+					},
+					outputType: single,
+				},
+			},
+			transformedResponse: reflect.TypeOf(controllernetworkpolicy.ReachabilityQueryResponse{}),
+		},
+		{
+			use:   "ruleconflicts",
+			short: "Report shadowed, redundant and conflicting rules of Antrea-native policies.",
+			long:  "Report the rules of Antrea ClusterNetworkPolicies and Antrea NetworkPolicies which are fully covered by a rule with a higher precedence (shadowed if the actions differ, redundant otherwise), or which overlap with a rule of another policy with the same Tier and priority but a different action (conflicting). The analysis is performed by the controller with the current members of the groups.",
+			example: `  Report the rule conflicts of all Antrea-native policies
+  $ antctl query ruleconflicts
+  Report the rule conflicts of the Antrea NetworkPolicies in Namespace ns1
+  $ antctl query ruleconflicts -n ns1
+  Report the rule conflicts of Antrea ClusterNetworkPolicy acnp1
+  $ antctl query ruleconflicts --name acnp1
+`,
+			commandGroup: query,
+			controllerEndpoint: &endpoint{
+				nonResourceEndpoint: &nonResourceEndpoint{
+					path: "/ruleconflicts",
+					params: []flagInfo{
+						{
+							name:      "namespace",
+							usage:     "Only report the rules of the Antrea NetworkPolicies in this Namespace",
+							shorthand: "n",
+						},
+						{
+							name:  "name",
+							usage: "Only report the rules of the policies with this name",
+						},
+					},
+					outputType: single,
+				},
+			},
+			transformedResponse: reflect.TypeOf(controllernetworkpolicy.RuleConflictQueryResponse{}),
+		},
 	},
 	rawCommands: []rawCommand{
 		{
@@ -17,13 +17,16 @@ package antctl
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 
+	"antrea.io/antrea/pkg/antctl/runtime"
 	antreaversion "antrea.io/antrea/pkg/version"
 )
 
@@ -82,3 +85,36 @@ func TestExtraArgs(t *testing.T) {
 	cmd.Execute()
 	assert.Contains(t, bufOut.String(), fmt.Sprintf("unknown command %q for", extraArg))
 }
+
+// TestFlowAggregatorCommands verifies that only the commands supported by the
+// Flow Aggregator are available in flow aggregator mode, and that the flows
+// command passes its filters to the server.
+func TestFlowAggregatorCommands(t *testing.T) {
+	mode := runtime.Mode
+	runtime.Mode = runtime.ModeFlowAggregator
+	defer func() {
+		runtime.Mode = mode
+	}()
+	assert.Equal(t, [][]string{{"get", "flows"}}, CommandList.GetDebugCommands(runtime.ModeFlowAggregator))
+
+	rootCmd := &cobra.Command{
+		Use: "antctl",
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := NewMockAntctlClient(ctrl)
+	var bufOut bytes.Buffer
+	CommandList.applyToRootCommand(rootCmd, client, &bufOut)
+
+	client.EXPECT().request(gomock.Any()).DoAndReturn(func(opt *requestOption) (io.Reader, error) {
+		assert.Equal(t, map[string]string{"namespace": "ns1", "action": "Drop"}, opt.args)
+		return strings.NewReader(`[{"sourceIP":"10.0.0.1","destinationIP":"10.0.0.2","sourceTransportPort":1234,"destinationTransportPort":80,"protocolIdentifier":6,"sourcePodName":"pod1","sourcePodNamespace":"ns1","egressNetworkPolicyName":"np1","egressNetworkPolicyRuleAction":2}]`), nil
+	})
+
+	rootCmd.SetOut(&bufOut)
+	rootCmd.SetErr(&bufOut)
+	rootCmd.SetArgs([]string{"get", "flows", "-n", "ns1", "--action", "Drop"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.Contains(t, bufOut.String(), "ns1/pod1(10.0.0.1:1234)")
+	assert.Contains(t, bufOut.String(), "np1(Drop)")
+}
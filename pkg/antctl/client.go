@@ -32,6 +32,7 @@ import (
 	"antrea.io/antrea/pkg/antctl/runtime"
 	"antrea.io/antrea/pkg/apis"
 	controllerapiserver "antrea.io/antrea/pkg/apiserver"
+	flowaggregatorapiserver "antrea.io/antrea/pkg/flowaggregator/apiserver"
 )
 
 // requestOption describes options to issue requests.
@@ -46,8 +47,9 @@ type requestOption struct {
 	// response body.
 	timeout time.Duration
 	// server is the address and port of the APIServer specified by user explicitly.
-	// If not set, antctl will connect to 127.0.0.1:10350 in agent mode, and will
-	// connect to the server set in kubeconfig in controller mode.
+	// If not set, antctl will connect to 127.0.0.1:10350 in agent mode, to
+	// 127.0.0.1:10348 in flow aggregator mode, and will connect to the server set
+	// in kubeconfig in controller mode.
 	// It set, it takes precedence over the above default endpoints.
 	server string
 }
@@ -85,6 +87,9 @@ func (c *client) resolveKubeconfig(opt *requestOption) (*rest.Config, error) {
 		} else if runtime.Mode == runtime.ModeController {
 			kubeconfig.Host = net.JoinHostPort("127.0.0.1", fmt.Sprint(apis.AntreaControllerAPIPort))
 			kubeconfig.BearerTokenFile = controllerapiserver.TokenPath
+		} else if runtime.Mode == runtime.ModeFlowAggregator {
+			kubeconfig.Host = net.JoinHostPort("127.0.0.1", fmt.Sprint(apis.FlowAggregatorAPIPort))
+			kubeconfig.BearerTokenFile = flowaggregatorapiserver.TokenPath
 		}
 	}
 	return kubeconfig, nil
@@ -94,6 +99,8 @@ func (c *client) request(opt *requestOption) (io.Reader, error) {
 	var e *endpoint
 	if runtime.Mode == runtime.ModeAgent {
 		e = opt.commandDefinition.agentEndpoint
+	} else if runtime.Mode == runtime.ModeFlowAggregator {
+		e = opt.commandDefinition.flowAggregatorEndpoint
 	} else {
 		e = opt.commandDefinition.controllerEndpoint
 	}
@@ -196,9 +196,10 @@ type commandDefinition struct {
 	long    string
 	example string // It will be filled with generated examples if it is not provided.
 	// commandGroup represents the group of the command.
-	commandGroup       commandGroup
-	controllerEndpoint *endpoint
-	agentEndpoint      *endpoint
+	commandGroup           commandGroup
+	controllerEndpoint     *endpoint
+	agentEndpoint          *endpoint
+	flowAggregatorEndpoint *endpoint
 	// transformedResponse is the final response struct of the command. If the
 	// AddonTransform is set, TransformedResponse is not needed to be used as the
 	// response struct of the handler, but it is still needed to guide the formatter.
@@ -211,6 +212,8 @@ func (cd *commandDefinition) namespaced() bool {
 		return cd.agentEndpoint != nil && cd.agentEndpoint.resourceEndpoint != nil && cd.agentEndpoint.resourceEndpoint.namespaced
 	} else if runtime.Mode == runtime.ModeController {
 		return cd.controllerEndpoint != nil && cd.controllerEndpoint.resourceEndpoint != nil && cd.controllerEndpoint.resourceEndpoint.namespaced
+	} else if runtime.Mode == runtime.ModeFlowAggregator {
+		return cd.flowAggregatorEndpoint != nil && cd.flowAggregatorEndpoint.resourceEndpoint != nil && cd.flowAggregatorEndpoint.resourceEndpoint.namespaced
 	}
 	return false
 }
@@ -220,6 +223,8 @@ func (cd *commandDefinition) getAddonTransform() func(reader io.Reader, single b
 		return cd.agentEndpoint.addonTransform
 	} else if runtime.Mode == runtime.ModeController && cd.controllerEndpoint != nil {
 		return cd.controllerEndpoint.addonTransform
+	} else if runtime.Mode == runtime.ModeFlowAggregator && cd.flowAggregatorEndpoint != nil {
+		return cd.flowAggregatorEndpoint.addonTransform
 	}
 	return nil
 }
@@ -239,6 +244,13 @@ func (cd *commandDefinition) getEndpoint() endpointResponder {
 			}
 			return cd.controllerEndpoint.nonResourceEndpoint
 		}
+	} else if runtime.Mode == runtime.ModeFlowAggregator {
+		if cd.flowAggregatorEndpoint != nil {
+			if cd.flowAggregatorEndpoint.resourceEndpoint != nil {
+				return cd.flowAggregatorEndpoint.resourceEndpoint
+			}
+			return cd.flowAggregatorEndpoint.nonResourceEndpoint
+		}
 	}
 	return nil
 }
@@ -252,6 +264,10 @@ func (cd *commandDefinition) getRequestErrorFallback() func() (io.Reader, error)
 		if cd.controllerEndpoint != nil {
 			return cd.controllerEndpoint.requestErrorFallback
 		}
+	} else if runtime.Mode == runtime.ModeFlowAggregator {
+		if cd.flowAggregatorEndpoint != nil {
+			return cd.flowAggregatorEndpoint.requestErrorFallback
+		}
 	}
 	return nil
 }
@@ -298,7 +314,7 @@ func (cd *commandDefinition) validate() []error {
 	if cd.transformedResponse == nil {
 		errs = append(errs, fmt.Errorf("%s: command does not define output struct", cd.use))
 	}
-	if cd.agentEndpoint == nil && cd.controllerEndpoint == nil {
+	if cd.agentEndpoint == nil && cd.controllerEndpoint == nil && cd.flowAggregatorEndpoint == nil {
 		errs = append(errs, fmt.Errorf("%s: command does not define any supported component", cd.use))
 	}
 	if cd.agentEndpoint != nil && cd.agentEndpoint.nonResourceEndpoint != nil && cd.agentEndpoint.resourceEndpoint != nil {
@@ -313,6 +329,12 @@ func (cd *commandDefinition) validate() []error {
 	if cd.controllerEndpoint != nil && cd.controllerEndpoint.nonResourceEndpoint == nil && cd.controllerEndpoint.resourceEndpoint == nil {
 		errs = append(errs, fmt.Errorf("%s: command for controller must define one endpoint", cd.use))
 	}
+	if cd.flowAggregatorEndpoint != nil && cd.flowAggregatorEndpoint.nonResourceEndpoint != nil && cd.flowAggregatorEndpoint.resourceEndpoint != nil {
+		errs = append(errs, fmt.Errorf("%s: command for flow aggregator can only define one endpoint", cd.use))
+	}
+	if cd.flowAggregatorEndpoint != nil && cd.flowAggregatorEndpoint.nonResourceEndpoint == nil && cd.flowAggregatorEndpoint.resourceEndpoint == nil {
+		errs = append(errs, fmt.Errorf("%s: command for flow aggregator must define one endpoint", cd.use))
+	}
 	empty := struct{}{}
 	existingFlags := map[string]struct{}{"output": empty, "help": empty, "kubeconfig": empty, "timeout": empty, "verbose": empty}
 	if endpoint := cd.getEndpoint(); endpoint != nil {
@@ -629,6 +651,70 @@ func (cd *commandDefinition) tableOutputForQueryEndpoint(obj interface{}, writer
 	return nil
 }
 
+// tableOutputForQueryReachability prints the verdict of a reachability query, followed by a table of the
+// evaluation of the egress and ingress rules.
+func (cd *commandDefinition) tableOutputForQueryReachability(obj interface{}, writer io.Writer) error {
+	reachabilityQueryResponse := obj.(*networkpolicy.ReachabilityQueryResponse)
+	if _, err := fmt.Fprintf(writer, "Verdict: %s\n\n", reachabilityQueryResponse.Verdict); err != nil {
+		return err
+	}
+	rows := [][]string{{"Direction", "Verdict", "Policy", "Rule", "Isolated", "Passed By"}}
+	for _, v := range []struct {
+		direction string
+		verdict   *networkpolicy.ReachabilityVerdict
+	}{
+		{"Egress", reachabilityQueryResponse.Egress},
+		{"Ingress", reachabilityQueryResponse.Ingress},
+	} {
+		if v.verdict == nil {
+			continue
+		}
+		policy, rule := policyRuleToString(v.verdict.Rule)
+		passedBy := "<NONE>"
+		if v.verdict.PassedBy != nil {
+			passedByPolicy, passedByRule := policyRuleToString(v.verdict.PassedBy)
+			passedBy = passedByPolicy + " " + passedByRule
+		}
+		rows = append(rows, []string{v.direction, string(v.verdict.Verdict), policy, rule, strconv.FormatBool(v.verdict.Isolated), passedBy})
+	}
+	numRows, numCols := len(rows), len(rows[0])
+	return constructTable(numRows, numCols, getColumnWidths(numRows, numCols, rows), rows, writer)
+}
+
+// tableOutputForQueryRuleConflicts prints a table of the affected rules and the rules affecting them.
+func (cd *commandDefinition) tableOutputForQueryRuleConflicts(obj interface{}, writer io.Writer) error {
+	ruleConflictQueryResponse := obj.(*networkpolicy.RuleConflictQueryResponse)
+	if len(ruleConflictQueryResponse.Conflicts) == 0 {
+		_, err := fmt.Fprintln(writer, "No rule conflicts found")
+		return err
+	}
+	rows := [][]string{{"Type", "Policy", "Direction", "Rule", "Action", "By Policy", "By Rule", "By Action"}}
+	for i := range ruleConflictQueryResponse.Conflicts {
+		conflict := &ruleConflictQueryResponse.Conflicts[i]
+		policy, rule := policyRuleToString(&conflict.Rule)
+		byPolicy, byRule := policyRuleToString(&conflict.By)
+		rows = append(rows, []string{string(conflict.Type), policy, string(conflict.Rule.Direction), rule, string(conflict.Rule.Action), byPolicy, byRule, string(conflict.By.Action)})
+	}
+	numRows, numCols := len(rows), len(rows[0])
+	return constructTable(numRows, numCols, getColumnWidths(numRows, numCols, rows), rows, writer)
+}
+
+// policyRuleToString returns the string representations of the policy and of the rule of a PolicyRuleRef.
+func policyRuleToString(rule *networkpolicy.PolicyRuleRef) (string, string) {
+	if rule == nil {
+		return "<NONE>", "<NONE>"
+	}
+	policy := string(rule.PolicyType) + ":" + rule.Name
+	if rule.Namespace != "" {
+		policy = string(rule.PolicyType) + ":" + rule.Namespace + "/" + rule.Name
+	}
+	ruleStr := strconv.Itoa(rule.RuleIndex)
+	if rule.RuleName != "" {
+		ruleStr += " (" + rule.RuleName + ")"
+	}
+	return policy, ruleStr
+}
+
 func (cd *commandDefinition) tableOutput(obj interface{}, writer io.Writer) error {
 	target, err := respTransformer(obj)
 	if err != nil {
@@ -749,6 +835,10 @@ func (cd *commandDefinition) output(resp io.Reader, writer io.Writer, ft formatt
 		} else if cd.commandGroup == query {
 			if cd.controllerEndpoint.nonResourceEndpoint.path == "/endpoint" {
 				return cd.tableOutputForQueryEndpoint(obj, writer)
+			} else if cd.controllerEndpoint.nonResourceEndpoint.path == "/reachability" {
+				return cd.tableOutputForQueryReachability(obj, writer)
+			} else if cd.controllerEndpoint.nonResourceEndpoint.path == "/ruleconflicts" {
+				return cd.tableOutputForQueryRuleConflicts(obj, writer)
 			}
 		} else {
 			return cd.tableOutput(obj, writer)
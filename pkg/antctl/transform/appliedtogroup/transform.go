@@ -65,9 +65,13 @@ func (r Response) GetTableHeader() []string {
 }
 
 func (r Response) GetPodNames(maxColumnLength int) string {
-	list := make([]string, len(r.Pods))
-	for i, pod := range r.Pods {
-		list[i] = pod.Pod.Namespace + "/" + pod.Pod.Name
+	list := make([]string, 0, len(r.Pods))
+	for _, pod := range r.Pods {
+		if pod.Node != nil {
+			list = append(list, "Node:"+pod.Node.Name)
+		} else if pod.Pod != nil {
+			list = append(list, pod.Pod.Namespace+"/"+pod.Pod.Name)
+		}
 	}
 	return common.GenerateTableElementWithSummary(list, maxColumnLength)
 }
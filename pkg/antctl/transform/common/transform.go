@@ -29,6 +29,8 @@ type GroupMember struct {
 	IP string `json:"ip,omitempty"`
 	// Ports maintain the named port mapping of this Pod.
 	Ports []cpv1beta.NamedPort `json:"ports,omitempty"`
+	// Node maintains the reference to the Node if the member is a Node.
+	Node *cpv1beta.NodeReference `json:"node,omitempty"`
 }
 
 func GroupMemberPodTransform(member cpv1beta.GroupMember) GroupMember {
@@ -39,7 +41,7 @@ func GroupMemberPodTransform(member cpv1beta.GroupMember) GroupMember {
 		}
 		ipStr += net.IP(ip).String()
 	}
-	return GroupMember{Pod: member.Pod, IP: ipStr, Ports: member.Ports}
+	return GroupMember{Pod: member.Pod, IP: ipStr, Ports: member.Ports, Node: member.Node}
 }
 
 type TableOutput interface {
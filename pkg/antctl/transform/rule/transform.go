@@ -15,6 +15,8 @@
 package rule
 
 import (
+	"strconv"
+
 	cpv1beta "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
 	"antrea.io/antrea/pkg/util/ip"
 )
@@ -23,6 +25,8 @@ type service struct {
 	Protocol string `json:"protocol,omitempty"`
 	Port     string `json:"port,omitempty"`
 	EndPort  string `json:"endPort,omitempty"`
+	ICMPType string `json:"icmpType,omitempty"`
+	ICMPCode string `json:"icmpCode,omitempty"`
 }
 
 type ipBlock struct {
@@ -53,10 +57,19 @@ func serviceTransform(services ...cpv1beta.Service) []service {
 		if s.EndPort != nil {
 			endPort = string(*s.EndPort)
 		}
+		var icmpType, icmpCode string
+		if s.ICMPType != nil {
+			icmpType = strconv.Itoa(int(*s.ICMPType))
+		}
+		if s.ICMPCode != nil {
+			icmpCode = strconv.Itoa(int(*s.ICMPCode))
+		}
 		ret = append(ret, service{
 			Protocol: string(*s.Protocol),
 			Port:     port,
 			EndPort:  endPort,
+			ICMPType: icmpType,
+			ICMPCode: icmpCode,
 		})
 	}
 	return ret
@@ -67,6 +67,12 @@ type ExternalEntityReference struct {
 	Namespace string
 }
 
+// NodeReference represents a Node Reference.
+type NodeReference struct {
+	// The name of this Node.
+	Name string
+}
+
 // GroupMember represents an resource member to be populated in Groups.
 type GroupMember struct {
 	// Pod maintains the reference to the Pod.
@@ -77,6 +83,8 @@ type GroupMember struct {
 	IPs []IPAddress
 	// Ports is the list NamedPort of the GroupMember.
 	Ports []NamedPort
+	// Node maintains the reference to the Node.
+	Node *NodeReference
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -222,6 +230,14 @@ type NetworkPolicyRule struct {
 	// Cannot be set in conjunction with NetworkPolicy.AppliedToGroups of the NetworkPolicy
 	// that this Rule is referred to.
 	AppliedToGroups []string
+	// L7Protocols is a list of application-layer protocols which should be matched
+	// by the traffic allowed by this rule. If it's empty, the rule is enforced at
+	// L3/L4 only.
+	L7Protocols []L7Protocol
+	// EnforcementMode specifies how the rule is enforced. An empty mode defaults
+	// to Enforce. In Audit mode, the traffic matching the rule is only logged and
+	// counted.
+	EnforcementMode crdv1alpha1.RuleEnforcementMode
 }
 
 // Protocol defines network protocols supported for things like container ports.
@@ -234,12 +250,16 @@ const (
 	ProtocolUDP Protocol = "UDP"
 	// ProtocolSCTP is the SCTP protocol.
 	ProtocolSCTP Protocol = "SCTP"
+	// ProtocolICMP is the ICMP protocol.
+	ProtocolICMP Protocol = "ICMP"
+	// ProtocolICMPv6 is the ICMPv6 protocol.
+	ProtocolICMPv6 Protocol = "ICMPv6"
 )
 
 // Service describes a port to allow traffic on.
 type Service struct {
-	// The protocol (TCP, UDP, or SCTP) which traffic must match. If not specified, this
-	// field defaults to TCP.
+	// The protocol (TCP, UDP, SCTP, ICMP, or ICMPv6) which traffic must match. If not
+	// specified, this field defaults to TCP.
 	// +optional
 	Protocol *Protocol
 	// The port name or number on the given protocol. If not specified, this matches all port numbers.
@@ -249,15 +269,41 @@ type Service struct {
 	// It can only be specified when a numerical `port` is specified.
 	// +optional
 	EndPort *int32
+	// ICMPType and ICMPCode can only be specified when the Protocol is ICMP or
+	// ICMPv6. If they are not specified and the Protocol is ICMP or ICMPv6, this
+	// matches all ICMP or ICMPv6 traffic.
+	// +optional
+	ICMPType *int32
+	// +optional
+	ICMPCode *int32
+}
+
+// L7Protocol describes an application-layer protocol to match.
+type L7Protocol struct {
+	HTTP *HTTPProtocol
+}
+
+// HTTPProtocol describes the HTTP requests to match. Empty fields match all
+// values.
+type HTTPProtocol struct {
+	// Host is the hostname of the requests.
+	Host string
+	// Method is the method of the requests.
+	Method string
+	// Path is the prefix of the path of the requests.
+	Path string
 }
 
 // NetworkPolicyPeer describes a peer of NetworkPolicyRules.
-// It could be a list of names of AddressGroups and/or a list of IPBlock.
+// It could be a list of names of AddressGroups and/or a list of IPBlock,
+// or a list of FQDN selectors.
 type NetworkPolicyPeer struct {
 	// A list of names of AddressGroups.
 	AddressGroups []string
 	// A list of IPBlock.
 	IPBlocks []IPBlock
+	// A list of FQDN selectors, which can be exact names or wildcard patterns.
+	FQDNs []string
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24"). The except entry describes CIDRs that should
@@ -404,6 +404,10 @@ func Convert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(in *con
 	return autoConvert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(in, out, s)
 }
 
+func Convert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(in *controlplane.NetworkPolicyPeer, out *NetworkPolicyPeer, s conversion.Scope) error {
+	return autoConvert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(in, out, s)
+}
+
 func Convert_v1beta1_Service_To_controlplane_Service(in *Service, out *controlplane.Service, s conversion.Scope) error {
 	if in.Protocol != nil {
 		outProtocol := controlplane.Protocol(*in.Protocol)
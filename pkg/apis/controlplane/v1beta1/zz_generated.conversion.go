@@ -117,11 +117,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*controlplane.NetworkPolicyPeer)(nil), (*NetworkPolicyPeer)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(a.(*controlplane.NetworkPolicyPeer), b.(*NetworkPolicyPeer), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NetworkPolicyReference)(nil), (*controlplane.NetworkPolicyReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_NetworkPolicyReference_To_controlplane_NetworkPolicyReference(a.(*NetworkPolicyReference), b.(*controlplane.NetworkPolicyReference), scope)
 	}); err != nil {
@@ -192,6 +187,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*controlplane.NetworkPolicyPeer)(nil), (*NetworkPolicyPeer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(a.(*controlplane.NetworkPolicyPeer), b.(*NetworkPolicyPeer), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*controlplane.NetworkPolicyRule)(nil), (*NetworkPolicyRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(a.(*controlplane.NetworkPolicyRule), b.(*NetworkPolicyRule), scope)
 	}); err != nil {
@@ -541,6 +541,7 @@ func autoConvert_controlplane_GroupMember_To_v1beta1_GroupMember(in *controlplan
 	out.ExternalEntity = (*ExternalEntityReference)(unsafe.Pointer(in.ExternalEntity))
 	// WARNING: in.IPs requires manual conversion: does not exist in peer-type
 	// WARNING: in.Ports requires manual conversion: does not exist in peer-type
+	// WARNING: in.Node requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -717,14 +718,10 @@ func Convert_v1beta1_NetworkPolicyPeer_To_controlplane_NetworkPolicyPeer(in *Net
 func autoConvert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(in *controlplane.NetworkPolicyPeer, out *NetworkPolicyPeer, s conversion.Scope) error {
 	out.AddressGroups = *(*[]string)(unsafe.Pointer(&in.AddressGroups))
 	out.IPBlocks = *(*[]IPBlock)(unsafe.Pointer(&in.IPBlocks))
+	// WARNING: in.FQDNs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer is an autogenerated conversion function.
-func Convert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(in *controlplane.NetworkPolicyPeer, out *NetworkPolicyPeer, s conversion.Scope) error {
-	return autoConvert_controlplane_NetworkPolicyPeer_To_v1beta1_NetworkPolicyPeer(in, out, s)
-}
-
 func autoConvert_v1beta1_NetworkPolicyReference_To_controlplane_NetworkPolicyReference(in *NetworkPolicyReference, out *controlplane.NetworkPolicyReference, s conversion.Scope) error {
 	out.Type = controlplane.NetworkPolicyType(in.Type)
 	out.Namespace = in.Namespace
@@ -805,6 +802,8 @@ func autoConvert_controlplane_NetworkPolicyRule_To_v1beta1_NetworkPolicyRule(in
 	out.Action = (*v1alpha1.RuleAction)(unsafe.Pointer(in.Action))
 	out.EnableLogging = in.EnableLogging
 	// WARNING: in.AppliedToGroups requires manual conversion: does not exist in peer-type
+	// WARNING: in.L7Protocols requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnforcementMode requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -894,5 +893,7 @@ func autoConvert_controlplane_Service_To_v1beta1_Service(in *controlplane.Servic
 	out.Protocol = (*Protocol)(unsafe.Pointer(in.Protocol))
 	out.Port = (*intstr.IntOrString)(unsafe.Pointer(in.Port))
 	// WARNING: in.EndPort requires manual conversion: does not exist in peer-type
+	// WARNING: in.ICMPType requires manual conversion: does not exist in peer-type
+	// WARNING: in.ICMPCode requires manual conversion: does not exist in peer-type
 	return nil
 }
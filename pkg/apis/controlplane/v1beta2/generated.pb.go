@@ -439,10 +439,38 @@ func (m *GroupReference) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GroupReference proto.InternalMessageInfo
 
+func (m *HTTPProtocol) Reset()      { *m = HTTPProtocol{} }
+func (*HTTPProtocol) ProtoMessage() {}
+func (*HTTPProtocol) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbaa7d016762fa1d, []int{14}
+}
+func (m *HTTPProtocol) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HTTPProtocol) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *HTTPProtocol) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HTTPProtocol.Merge(m, src)
+}
+func (m *HTTPProtocol) XXX_Size() int {
+	return m.Size()
+}
+func (m *HTTPProtocol) XXX_DiscardUnknown() {
+	xxx_messageInfo_HTTPProtocol.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HTTPProtocol proto.InternalMessageInfo
+
 func (m *IPBlock) Reset()      { *m = IPBlock{} }
 func (*IPBlock) ProtoMessage() {}
 func (*IPBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{14}
+	return fileDescriptor_fbaa7d016762fa1d, []int{15}
 }
 func (m *IPBlock) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -470,7 +498,7 @@ var xxx_messageInfo_IPBlock proto.InternalMessageInfo
 func (m *IPNet) Reset()      { *m = IPNet{} }
 func (*IPNet) ProtoMessage() {}
 func (*IPNet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{15}
+	return fileDescriptor_fbaa7d016762fa1d, []int{16}
 }
 func (m *IPNet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -495,10 +523,38 @@ func (m *IPNet) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_IPNet proto.InternalMessageInfo
 
+func (m *L7Protocol) Reset()      { *m = L7Protocol{} }
+func (*L7Protocol) ProtoMessage() {}
+func (*L7Protocol) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbaa7d016762fa1d, []int{17}
+}
+func (m *L7Protocol) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *L7Protocol) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *L7Protocol) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_L7Protocol.Merge(m, src)
+}
+func (m *L7Protocol) XXX_Size() int {
+	return m.Size()
+}
+func (m *L7Protocol) XXX_DiscardUnknown() {
+	xxx_messageInfo_L7Protocol.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_L7Protocol proto.InternalMessageInfo
+
 func (m *NamedPort) Reset()      { *m = NamedPort{} }
 func (*NamedPort) ProtoMessage() {}
 func (*NamedPort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{16}
+	return fileDescriptor_fbaa7d016762fa1d, []int{18}
 }
 func (m *NamedPort) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -526,7 +582,7 @@ var xxx_messageInfo_NamedPort proto.InternalMessageInfo
 func (m *NetworkPolicy) Reset()      { *m = NetworkPolicy{} }
 func (*NetworkPolicy) ProtoMessage() {}
 func (*NetworkPolicy) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{17}
+	return fileDescriptor_fbaa7d016762fa1d, []int{19}
 }
 func (m *NetworkPolicy) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -554,7 +610,7 @@ var xxx_messageInfo_NetworkPolicy proto.InternalMessageInfo
 func (m *NetworkPolicyList) Reset()      { *m = NetworkPolicyList{} }
 func (*NetworkPolicyList) ProtoMessage() {}
 func (*NetworkPolicyList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{18}
+	return fileDescriptor_fbaa7d016762fa1d, []int{20}
 }
 func (m *NetworkPolicyList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -582,7 +638,7 @@ var xxx_messageInfo_NetworkPolicyList proto.InternalMessageInfo
 func (m *NetworkPolicyNodeStatus) Reset()      { *m = NetworkPolicyNodeStatus{} }
 func (*NetworkPolicyNodeStatus) ProtoMessage() {}
 func (*NetworkPolicyNodeStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{19}
+	return fileDescriptor_fbaa7d016762fa1d, []int{21}
 }
 func (m *NetworkPolicyNodeStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -610,7 +666,7 @@ var xxx_messageInfo_NetworkPolicyNodeStatus proto.InternalMessageInfo
 func (m *NetworkPolicyPeer) Reset()      { *m = NetworkPolicyPeer{} }
 func (*NetworkPolicyPeer) ProtoMessage() {}
 func (*NetworkPolicyPeer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{20}
+	return fileDescriptor_fbaa7d016762fa1d, []int{22}
 }
 func (m *NetworkPolicyPeer) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -638,7 +694,7 @@ var xxx_messageInfo_NetworkPolicyPeer proto.InternalMessageInfo
 func (m *NetworkPolicyReference) Reset()      { *m = NetworkPolicyReference{} }
 func (*NetworkPolicyReference) ProtoMessage() {}
 func (*NetworkPolicyReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{21}
+	return fileDescriptor_fbaa7d016762fa1d, []int{23}
 }
 func (m *NetworkPolicyReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -666,7 +722,7 @@ var xxx_messageInfo_NetworkPolicyReference proto.InternalMessageInfo
 func (m *NetworkPolicyRule) Reset()      { *m = NetworkPolicyRule{} }
 func (*NetworkPolicyRule) ProtoMessage() {}
 func (*NetworkPolicyRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{22}
+	return fileDescriptor_fbaa7d016762fa1d, []int{24}
 }
 func (m *NetworkPolicyRule) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -694,7 +750,7 @@ var xxx_messageInfo_NetworkPolicyRule proto.InternalMessageInfo
 func (m *NetworkPolicyStats) Reset()      { *m = NetworkPolicyStats{} }
 func (*NetworkPolicyStats) ProtoMessage() {}
 func (*NetworkPolicyStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{23}
+	return fileDescriptor_fbaa7d016762fa1d, []int{25}
 }
 func (m *NetworkPolicyStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -722,7 +778,7 @@ var xxx_messageInfo_NetworkPolicyStats proto.InternalMessageInfo
 func (m *NetworkPolicyStatus) Reset()      { *m = NetworkPolicyStatus{} }
 func (*NetworkPolicyStatus) ProtoMessage() {}
 func (*NetworkPolicyStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{24}
+	return fileDescriptor_fbaa7d016762fa1d, []int{26}
 }
 func (m *NetworkPolicyStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -747,10 +803,38 @@ func (m *NetworkPolicyStatus) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NetworkPolicyStatus proto.InternalMessageInfo
 
+func (m *NodeReference) Reset()      { *m = NodeReference{} }
+func (*NodeReference) ProtoMessage() {}
+func (*NodeReference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_fbaa7d016762fa1d, []int{27}
+}
+func (m *NodeReference) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NodeReference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NodeReference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeReference.Merge(m, src)
+}
+func (m *NodeReference) XXX_Size() int {
+	return m.Size()
+}
+func (m *NodeReference) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeReference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeReference proto.InternalMessageInfo
+
 func (m *NodeStatsSummary) Reset()      { *m = NodeStatsSummary{} }
 func (*NodeStatsSummary) ProtoMessage() {}
 func (*NodeStatsSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{25}
+	return fileDescriptor_fbaa7d016762fa1d, []int{28}
 }
 func (m *NodeStatsSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -778,7 +862,7 @@ var xxx_messageInfo_NodeStatsSummary proto.InternalMessageInfo
 func (m *PodReference) Reset()      { *m = PodReference{} }
 func (*PodReference) ProtoMessage() {}
 func (*PodReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{26}
+	return fileDescriptor_fbaa7d016762fa1d, []int{29}
 }
 func (m *PodReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -806,7 +890,7 @@ var xxx_messageInfo_PodReference proto.InternalMessageInfo
 func (m *Service) Reset()      { *m = Service{} }
 func (*Service) ProtoMessage() {}
 func (*Service) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{27}
+	return fileDescriptor_fbaa7d016762fa1d, []int{30}
 }
 func (m *Service) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -834,7 +918,7 @@ var xxx_messageInfo_Service proto.InternalMessageInfo
 func (m *ServiceReference) Reset()      { *m = ServiceReference{} }
 func (*ServiceReference) ProtoMessage() {}
 func (*ServiceReference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_fbaa7d016762fa1d, []int{28}
+	return fileDescriptor_fbaa7d016762fa1d, []int{31}
 }
 func (m *ServiceReference) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -874,8 +958,10 @@ func init() {
 	proto.RegisterType((*GroupAssociation)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.GroupAssociation")
 	proto.RegisterType((*GroupMember)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.GroupMember")
 	proto.RegisterType((*GroupReference)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.GroupReference")
+	proto.RegisterType((*HTTPProtocol)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.HTTPProtocol")
 	proto.RegisterType((*IPBlock)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.IPBlock")
 	proto.RegisterType((*IPNet)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.IPNet")
+	proto.RegisterType((*L7Protocol)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.L7Protocol")
 	proto.RegisterType((*NamedPort)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NamedPort")
 	proto.RegisterType((*NetworkPolicy)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NetworkPolicy")
 	proto.RegisterType((*NetworkPolicyList)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NetworkPolicyList")
@@ -885,6 +971,7 @@ func init() {
 	proto.RegisterType((*NetworkPolicyRule)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NetworkPolicyRule")
 	proto.RegisterType((*NetworkPolicyStats)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NetworkPolicyStats")
 	proto.RegisterType((*NetworkPolicyStatus)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NetworkPolicyStatus")
+	proto.RegisterType((*NodeReference)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NodeReference")
 	proto.RegisterType((*NodeStatsSummary)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.NodeStatsSummary")
 	proto.RegisterType((*PodReference)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.PodReference")
 	proto.RegisterType((*Service)(nil), "antrea_io.antrea.pkg.apis.controlplane.v1beta2.Service")
@@ -896,122 +983,132 @@ func init() {
 }
 
 var fileDescriptor_fbaa7d016762fa1d = []byte{
-	// 1826 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0x4d, 0x6c, 0x23, 0x49,
-	0x15, 0x4e, 0xfb, 0x27, 0x89, 0x5f, 0x9c, 0xc4, 0xa9, 0xec, 0x30, 0x66, 0x19, 0xec, 0x6c, 0xf3,
-	0xa3, 0x1c, 0xd8, 0xf6, 0x26, 0xcc, 0xee, 0x0e, 0xec, 0x0f, 0x8a, 0x77, 0xb2, 0x91, 0xa5, 0x59,
-	0xaf, 0x55, 0xc9, 0x6a, 0x24, 0xc4, 0xc2, 0x76, 0xba, 0xcb, 0x4e, 0x11, 0xbb, 0xab, 0xd5, 0x5d,
-	0x0e, 0x13, 0x71, 0x59, 0x04, 0x1c, 0x16, 0x90, 0xe0, 0xc6, 0x99, 0x13, 0x17, 0x6e, 0x48, 0xdc,
-	0x39, 0x20, 0xcd, 0x71, 0x11, 0x42, 0xec, 0xc9, 0x62, 0x8c, 0x00, 0x71, 0xe0, 0xc6, 0x29, 0x5c,
-	0x50, 0x55, 0x57, 0xff, 0x3a, 0x9e, 0x8c, 0x37, 0x99, 0x20, 0xc1, 0x9e, 0xec, 0x7e, 0xf5, 0xde,
-	0xfb, 0xde, 0xab, 0xf7, 0x57, 0xd5, 0x0d, 0xaf, 0x9b, 0x0e, 0xf7, 0x88, 0x69, 0x50, 0xd6, 0x08,
-	0xfe, 0x35, 0xdc, 0xe3, 0x5e, 0xc3, 0x74, 0xa9, 0xdf, 0xb0, 0x98, 0xc3, 0x3d, 0xd6, 0x77, 0xfb,
-	0xa6, 0x43, 0x1a, 0x27, 0x5b, 0x87, 0x84, 0x9b, 0xdb, 0x8d, 0x1e, 0x71, 0x88, 0x67, 0x72, 0x62,
-	0x1b, 0xae, 0xc7, 0x38, 0x43, 0x46, 0x20, 0xf5, 0x2d, 0xca, 0xd4, 0x3f, 0xc3, 0x3d, 0xee, 0x19,
-	0x42, 0xde, 0x48, 0xca, 0x1b, 0x4a, 0xfe, 0xd9, 0x3b, 0xd3, 0xf1, 0x7c, 0x6e, 0x72, 0xbf, 0x71,
-	0xb2, 0x65, 0xf6, 0xdd, 0x23, 0x73, 0x2b, 0x8b, 0xf4, 0xec, 0xf3, 0x3d, 0xca, 0x8f, 0x86, 0x87,
-	0x86, 0xc5, 0x06, 0x8d, 0x1e, 0xeb, 0xb1, 0x86, 0x24, 0x1f, 0x0e, 0xbb, 0xf2, 0x49, 0x3e, 0xc8,
-	0x7f, 0x8a, 0xfd, 0xf6, 0xf1, 0x1d, 0x5f, 0xa2, 0xb8, 0x74, 0x60, 0x5a, 0x47, 0xd4, 0x21, 0xde,
-	0x69, 0x8c, 0x35, 0x20, 0xdc, 0x6c, 0x9c, 0x4c, 0x82, 0x34, 0xa6, 0x49, 0x79, 0x43, 0x87, 0xd3,
-	0x01, 0x99, 0x10, 0x78, 0xe9, 0x22, 0x01, 0xdf, 0x3a, 0x22, 0x03, 0x73, 0x42, 0xee, 0xcb, 0xd3,
-	0xe4, 0x86, 0x9c, 0xf6, 0x1b, 0xd4, 0xe1, 0x3e, 0xf7, 0xb2, 0x42, 0xfa, 0xdf, 0x35, 0x28, 0xef,
-	0xd8, 0xb6, 0x47, 0x7c, 0x7f, 0xcf, 0x63, 0x43, 0x17, 0xbd, 0x07, 0x8b, 0xc2, 0x13, 0xdb, 0xe4,
-	0x66, 0x55, 0xdb, 0xd0, 0x36, 0x97, 0xb6, 0x5f, 0x30, 0x02, 0xc5, 0x46, 0x52, 0x71, 0x1c, 0x13,
-	0xc1, 0x6d, 0x9c, 0x6c, 0x19, 0x6f, 0x1f, 0x7e, 0x9b, 0x58, 0xfc, 0x2d, 0xc2, 0xcd, 0x26, 0x7a,
-	0x38, 0xaa, 0xcf, 0x8d, 0x47, 0x75, 0x88, 0x69, 0x38, 0xd2, 0x8a, 0x86, 0x50, 0xee, 0x09, 0xa8,
-	0xb7, 0xc8, 0xe0, 0x90, 0x78, 0x7e, 0x35, 0xb7, 0x91, 0xdf, 0x5c, 0xda, 0x7e, 0x65, 0xc6, 0xb0,
-	0x1b, 0x7b, 0xb1, 0x8e, 0xe6, 0x33, 0x0a, 0xb0, 0x9c, 0x20, 0xfa, 0x38, 0x05, 0xa3, 0xff, 0x41,
-	0x83, 0x4a, 0xd2, 0xd3, 0x7b, 0xd4, 0xe7, 0xe8, 0x1b, 0x13, 0xde, 0x1a, 0x4f, 0xe6, 0xad, 0x90,
-	0x96, 0xbe, 0x56, 0x14, 0xf4, 0x62, 0x48, 0x49, 0x78, 0x6a, 0x42, 0x91, 0x72, 0x32, 0x08, 0x5d,
-	0x7c, 0x75, 0x56, 0x17, 0x93, 0xe6, 0x36, 0x97, 0x15, 0x50, 0xb1, 0x25, 0x54, 0xe2, 0x40, 0xb3,
-	0xfe, 0x41, 0x1e, 0xd6, 0x92, 0x6c, 0x1d, 0x93, 0x5b, 0x47, 0xd7, 0x10, 0xc4, 0x1f, 0x68, 0xb0,
-	0x66, 0xda, 0x36, 0xb1, 0xf7, 0xae, 0x38, 0x94, 0x9f, 0x56, 0xb0, 0xc2, 0xab, 0xb4, 0x76, 0x3c,
-	0x09, 0x88, 0x7e, 0xa4, 0xc1, 0xba, 0x47, 0x06, 0xec, 0x24, 0x63, 0x48, 0xfe, 0xf2, 0x86, 0x7c,
-	0x46, 0x19, 0xb2, 0x8e, 0x27, 0xf5, 0xe3, 0xf3, 0x40, 0xf5, 0x7f, 0x68, 0xb0, 0xb2, 0xe3, 0xba,
-	0x7d, 0x4a, 0xec, 0x03, 0xf6, 0x3f, 0x5e, 0x4d, 0x7f, 0xd2, 0x00, 0xa5, 0x7d, 0xbd, 0x86, 0x7a,
-	0xb2, 0xd2, 0xf5, 0xf4, 0xfa, 0xcc, 0xf5, 0x94, 0x32, 0x78, 0x4a, 0x45, 0xfd, 0x38, 0x0f, 0xeb,
-	0x69, 0xc6, 0x4f, 0x6a, 0xea, 0xbf, 0x57, 0x53, 0xff, 0xd6, 0x60, 0xfd, 0x8d, 0xfe, 0xd0, 0xe7,
-	0xc4, 0x4b, 0x19, 0xf9, 0xf4, 0xa3, 0xf1, 0x3d, 0x0d, 0x2a, 0xa4, 0xdb, 0x25, 0x16, 0xa7, 0x27,
-	0xe4, 0x0a, 0x83, 0x51, 0x55, 0xa8, 0x95, 0xdd, 0x8c, 0x72, 0x3c, 0x01, 0xa7, 0xff, 0x4d, 0x83,
-	0xa5, 0xdd, 0xde, 0xff, 0xc1, 0x70, 0xfe, 0xbd, 0x06, 0xab, 0x09, 0x47, 0xaf, 0xa1, 0x97, 0xbc,
-	0x97, 0xee, 0x25, 0x33, 0x7b, 0x98, 0xb0, 0x76, 0x4a, 0x23, 0xf9, 0x49, 0x1e, 0x2a, 0x09, 0xae,
-	0xa0, 0x8b, 0xd8, 0x00, 0x2c, 0xda, 0xf7, 0x2b, 0x8d, 0x61, 0x42, 0xef, 0x27, 0x9d, 0xe4, 0x9c,
-	0x4e, 0xd2, 0x87, 0x9b, 0xbb, 0x0f, 0x38, 0xf1, 0x1c, 0xb3, 0xbf, 0xeb, 0x70, 0xca, 0x4f, 0x31,
-	0xe9, 0x12, 0x8f, 0x38, 0x16, 0x41, 0x1b, 0x50, 0x70, 0xcc, 0x01, 0x91, 0xe1, 0x28, 0x35, 0xcb,
-	0x4a, 0x75, 0xa1, 0x6d, 0x0e, 0x08, 0x96, 0x2b, 0xa8, 0x01, 0x25, 0xf1, 0xeb, 0xbb, 0xa6, 0x45,
-	0xaa, 0x39, 0xc9, 0xb6, 0xa6, 0xd8, 0x4a, 0xed, 0x70, 0x01, 0xc7, 0x3c, 0xa2, 0x6f, 0x55, 0x24,
-	0xfc, 0x8e, 0xef, 0x33, 0x8b, 0x9a, 0x9c, 0x32, 0xe7, 0x7a, 0x46, 0x48, 0xc5, 0x54, 0x88, 0xca,
-	0xff, 0x8f, 0x3d, 0x2d, 0xa5, 0x74, 0xb4, 0x49, 0x71, 0xdf, 0xda, 0xc9, 0xe8, 0xc7, 0x13, 0x88,
-	0xfa, 0xbf, 0x72, 0xb0, 0x94, 0xd8, 0x7c, 0x74, 0x1f, 0xf2, 0x2e, 0xb3, 0x95, 0xcf, 0x33, 0x1f,
-	0x83, 0x3b, 0xcc, 0x8e, 0xcd, 0x58, 0x18, 0x8f, 0xea, 0x79, 0x41, 0x11, 0x1a, 0xd1, 0xf7, 0x35,
-	0x58, 0x21, 0xa9, 0xa8, 0xca, 0xe8, 0x2c, 0x6d, 0xef, 0xcd, 0x5c, 0xcf, 0xe7, 0xe7, 0x46, 0x13,
-	0x8d, 0x47, 0xf5, 0x95, 0xcc, 0x62, 0x06, 0x12, 0x7d, 0x11, 0xf2, 0xd4, 0x0d, 0xd2, 0xba, 0xdc,
-	0x7c, 0x46, 0x18, 0xd8, 0xea, 0xf8, 0x67, 0xa3, 0x7a, 0xa9, 0xd5, 0x51, 0x67, 0x73, 0x2c, 0x18,
-	0xd0, 0x37, 0xa1, 0xe8, 0x32, 0x8f, 0xfb, 0xd5, 0x82, 0x8c, 0xc8, 0x57, 0x66, 0xb5, 0x51, 0x64,
-	0x9a, 0xdd, 0x61, 0x1e, 0x8f, 0x3b, 0x8e, 0x78, 0xf2, 0x71, 0xa0, 0x56, 0xff, 0xa5, 0x06, 0x2b,
-	0xe9, 0xa8, 0xa5, 0x13, 0x57, 0xbb, 0x38, 0x71, 0xa3, 0x5a, 0xc8, 0x4d, 0xad, 0x85, 0x26, 0xe4,
-	0x87, 0xd4, 0xae, 0xe6, 0x25, 0xc3, 0x0b, 0x8a, 0x21, 0xff, 0x4e, 0xeb, 0xee, 0xd9, 0xa8, 0xfe,
-	0xdc, 0xb4, 0x3b, 0x28, 0x3f, 0x75, 0x89, 0x6f, 0xbc, 0xd3, 0xba, 0x8b, 0x85, 0xb0, 0xfe, 0x5b,
-	0x0d, 0x16, 0x5a, 0x9d, 0x66, 0x9f, 0x59, 0xc7, 0xe8, 0x3e, 0x14, 0x2c, 0x6a, 0x7b, 0x2a, 0x3b,
-	0x5e, 0x9c, 0x75, 0x53, 0x5a, 0x9d, 0x36, 0xe1, 0xb1, 0xa1, 0x6f, 0xb4, 0xee, 0x62, 0x2c, 0x15,
-	0xa2, 0x77, 0x61, 0x9e, 0x3c, 0xb0, 0x88, 0xcb, 0x55, 0x05, 0x7c, 0x4c, 0xd5, 0x2b, 0x4a, 0xf5,
-	0xfc, 0xae, 0x54, 0x86, 0x95, 0x52, 0xbd, 0x0b, 0x45, 0xc9, 0x80, 0x3e, 0x07, 0x39, 0xea, 0x4a,
-	0xf3, 0xcb, 0xcd, 0xf5, 0xf1, 0xa8, 0x9e, 0x6b, 0x75, 0xd2, 0xc1, 0xcf, 0x51, 0x17, 0xdd, 0x81,
-	0xb2, 0xeb, 0x91, 0x2e, 0x7d, 0x70, 0x8f, 0x38, 0x3d, 0x7e, 0x24, 0xf7, 0xb7, 0x18, 0xcf, 0xc6,
-	0x4e, 0x62, 0x0d, 0xa7, 0x38, 0xf5, 0x0f, 0x34, 0x28, 0x45, 0x91, 0x17, 0xf1, 0x11, 0xc1, 0x96,
-	0x70, 0xc5, 0xd8, 0x6d, 0xb1, 0x86, 0xe5, 0xca, 0x13, 0x44, 0xf0, 0x0e, 0x2c, 0xca, 0xdb, 0xbf,
-	0xc5, 0xfa, 0x2a, 0x8c, 0xb7, 0xc2, 0x49, 0xd9, 0x51, 0xf4, 0xb3, 0xc4, 0x7f, 0x1c, 0x71, 0xeb,
-	0xff, 0xcc, 0xc3, 0x72, 0x9b, 0xf0, 0xef, 0x30, 0xef, 0xb8, 0xc3, 0xfa, 0xd4, 0x3a, 0xbd, 0x86,
-	0x9e, 0xd6, 0x85, 0xa2, 0x37, 0xec, 0x93, 0xb0, 0x8f, 0xed, 0xcc, 0x5c, 0x35, 0x49, 0x7b, 0xf1,
-	0xb0, 0x4f, 0xe2, 0xea, 0x11, 0x4f, 0x3e, 0x0e, 0xd4, 0xa3, 0xd7, 0x60, 0xd5, 0x4c, 0x9d, 0xfb,
-	0x83, 0x8a, 0x2e, 0xc9, 0x98, 0xae, 0xa6, 0xaf, 0x04, 0x3e, 0xce, 0xf2, 0xa2, 0x4d, 0xb1, 0xa9,
-	0x94, 0x79, 0xa2, 0x07, 0x15, 0x36, 0xb4, 0x4d, 0xad, 0x59, 0x0e, 0x36, 0x34, 0xa0, 0xe1, 0x68,
-	0x15, 0xdd, 0x86, 0x32, 0xa7, 0xc4, 0x0b, 0x57, 0xaa, 0x45, 0x19, 0xca, 0x8a, 0x48, 0x83, 0x83,
-	0x04, 0x1d, 0xa7, 0xb8, 0x90, 0x0f, 0x25, 0x9f, 0x0d, 0x3d, 0x8b, 0x60, 0xd2, 0xad, 0xce, 0xcb,
-	0x9d, 0x7e, 0xf3, 0x72, 0x5b, 0x11, 0xf5, 0xb8, 0x65, 0xd1, 0x0d, 0xf6, 0x43, 0xe5, 0x38, 0xc6,
-	0xd1, 0xff, 0xa8, 0xc1, 0x5a, 0x4a, 0xe8, 0x1a, 0x4e, 0x66, 0x87, 0xe9, 0x93, 0xd9, 0x6b, 0x97,
-	0x72, 0x72, 0xca, 0xd9, 0xec, 0xbb, 0x70, 0x33, 0xc5, 0xd6, 0x66, 0x36, 0xd9, 0xe7, 0x26, 0x1f,
-	0xfa, 0xe8, 0x4b, 0xb0, 0xe8, 0x30, 0x9b, 0xb4, 0xe3, 0x03, 0x41, 0x64, 0x6c, 0x5b, 0xd1, 0x71,
-	0xc4, 0x81, 0xb6, 0x01, 0xd4, 0x2b, 0x35, 0xca, 0x1c, 0x59, 0x72, 0xf9, 0x38, 0x9d, 0xf7, 0xa2,
-	0x15, 0x9c, 0xe0, 0xd2, 0x7f, 0x9d, 0xdd, 0xd4, 0x0e, 0x21, 0x1e, 0x7a, 0x19, 0x96, 0xcd, 0xc4,
-	0x8b, 0x1c, 0xbf, 0xaa, 0xc9, 0xe4, 0x5b, 0x1b, 0x8f, 0xea, 0xcb, 0xc9, 0x37, 0x3c, 0x3e, 0x4e,
-	0xf3, 0x21, 0x02, 0x8b, 0xd4, 0x95, 0xad, 0x34, 0xdc, 0xb2, 0x97, 0x67, 0x6f, 0x74, 0x52, 0x3e,
-	0xf6, 0x54, 0x11, 0x7c, 0x1c, 0xa9, 0x16, 0x77, 0x91, 0x4f, 0x9d, 0x9f, 0x3f, 0xe8, 0x45, 0x28,
-	0x88, 0xfe, 0xae, 0xb6, 0xeb, 0xb9, 0xb0, 0xe3, 0x1c, 0x9c, 0xba, 0xe4, 0x6c, 0x54, 0x4f, 0xfb,
-	0x2a, 0x88, 0x58, 0xb2, 0xcf, 0x7c, 0xa8, 0x8a, 0x3a, 0x5b, 0xfe, 0xa2, 0xd9, 0x54, 0xb8, 0xcc,
-	0x6c, 0xfa, 0x45, 0x31, 0x13, 0x1e, 0xd1, 0x25, 0xd0, 0xab, 0x50, 0xb2, 0xa9, 0x27, 0xae, 0x67,
-	0xcc, 0x51, 0x8e, 0xd6, 0x42, 0x63, 0xef, 0x86, 0x0b, 0x67, 0xc9, 0x07, 0x1c, 0x0b, 0x20, 0x0b,
-	0x0a, 0x5d, 0x8f, 0x0d, 0xd4, 0xe1, 0xe4, 0x72, 0x2d, 0x4c, 0x64, 0x4b, 0xec, 0xfc, 0x9b, 0x1e,
-	0x1b, 0x60, 0xa9, 0x1c, 0xbd, 0x0b, 0x39, 0xce, 0xe4, 0xe6, 0x5c, 0x09, 0x04, 0x28, 0x88, 0xdc,
-	0x01, 0xc3, 0x39, 0xce, 0x44, 0x9e, 0xf9, 0xc4, 0x3b, 0xa1, 0x16, 0x09, 0x0f, 0x30, 0x33, 0xe7,
-	0xd9, 0x7e, 0x20, 0x1f, 0xe7, 0x99, 0x22, 0xf8, 0x38, 0x52, 0x2d, 0xea, 0xcf, 0xcd, 0x74, 0xc6,
-	0x78, 0x38, 0x4d, 0xf4, 0xd2, 0xfb, 0x30, 0x6f, 0x06, 0x31, 0x99, 0x97, 0x31, 0xf9, 0x9a, 0x18,
-	0xd4, 0x3b, 0x61, 0x30, 0xb6, 0x1e, 0xf3, 0x29, 0xc2, 0xb3, 0xa3, 0x0f, 0x03, 0x86, 0x88, 0x70,
-	0x20, 0x84, 0x95, 0x3a, 0xf4, 0x0a, 0x2c, 0x13, 0xc7, 0x3c, 0xec, 0x93, 0x7b, 0xac, 0xd7, 0xa3,
-	0x4e, 0xaf, 0xba, 0xb0, 0xa1, 0x6d, 0x2e, 0x36, 0x6f, 0x28, 0x5b, 0x96, 0x77, 0x93, 0x8b, 0x38,
-	0xcd, 0x7b, 0xde, 0x28, 0x59, 0x9c, 0x61, 0x94, 0x84, 0x79, 0x5e, 0x9a, 0x96, 0xe7, 0xfa, 0x4f,
-	0xf3, 0x80, 0x52, 0x11, 0x13, 0xcd, 0xcb, 0x17, 0xc7, 0xe1, 0x65, 0x27, 0x49, 0x56, 0xed, 0xf9,
-	0xaa, 0x06, 0x45, 0xe4, 0x7d, 0x7a, 0x3d, 0x8d, 0x89, 0x5c, 0x28, 0x73, 0xcf, 0xec, 0x76, 0xa9,
-	0x25, 0xad, 0x52, 0x49, 0xff, 0xd2, 0x63, 0x6c, 0x90, 0xdf, 0x69, 0x8c, 0x28, 0x1c, 0x07, 0x09,
-	0xe9, 0xf8, 0x88, 0x94, 0xa4, 0xe2, 0x14, 0x02, 0x7a, 0x5f, 0x83, 0x8a, 0x18, 0xe2, 0x49, 0x16,
-	0x75, 0xcb, 0xfc, 0xea, 0x93, 0xc3, 0xe2, 0x8c, 0x86, 0xf8, 0xca, 0x93, 0x5d, 0xc1, 0x13, 0x68,
-	0xfa, 0x5f, 0x35, 0x58, 0x9f, 0x88, 0xc8, 0xf0, 0x3a, 0x5e, 0x54, 0xf5, 0xa1, 0x28, 0xc6, 0x51,
-	0xd8, 0xfc, 0xf7, 0x2e, 0x15, 0xeb, 0x78, 0x10, 0xc6, 0x93, 0x53, 0xd0, 0x7c, 0x1c, 0x80, 0xe8,
-	0xbf, 0x2b, 0x40, 0x25, 0x64, 0xf2, 0xf7, 0x87, 0x83, 0x81, 0xe9, 0x5d, 0xc7, 0x21, 0xf0, 0x87,
-	0x1a, 0xac, 0x26, 0xb3, 0x8c, 0x46, 0xfe, 0x36, 0x2f, 0xe5, 0x6f, 0x10, 0xe8, 0x9b, 0x0a, 0x7b,
-	0xb5, 0x9d, 0x86, 0xc0, 0x59, 0x4c, 0xf4, 0x2b, 0x0d, 0x6e, 0x05, 0x28, 0xea, 0xad, 0x64, 0x46,
-	0x42, 0x65, 0xdd, 0x55, 0x18, 0xf5, 0x79, 0x65, 0xd4, 0xad, 0x9d, 0xc7, 0xe0, 0xe1, 0xc7, 0x5a,
-	0x83, 0x7e, 0xae, 0xc1, 0x8d, 0x80, 0x21, 0x6b, 0x67, 0xe1, 0xca, 0xec, 0xfc, 0xac, 0xb2, 0xf3,
-	0xc6, 0xce, 0x79, 0x40, 0xf8, 0x7c, 0x7c, 0xdd, 0x84, 0x72, 0xf2, 0x5e, 0xff, 0x34, 0xde, 0xc1,
-	0xfc, 0x46, 0x83, 0x05, 0x35, 0x60, 0xd0, 0xed, 0xc4, 0x95, 0x27, 0x80, 0xa8, 0x5e, 0x7c, 0xdd,
-	0x41, 0x6d, 0x75, 0xd9, 0xca, 0x5d, 0x90, 0xd3, 0x43, 0x4e, 0xfb, 0x46, 0xf0, 0x85, 0xd5, 0x68,
-	0x39, 0xfc, 0x6d, 0x6f, 0x9f, 0x7b, 0xd4, 0xe9, 0x35, 0x17, 0x33, 0x57, 0xb3, 0x2f, 0xc0, 0x02,
-	0x71, 0xe4, 0x3d, 0x4e, 0x8e, 0xe9, 0x62, 0x73, 0x69, 0x3c, 0xaa, 0x2f, 0xec, 0x06, 0x24, 0x1c,
-	0xae, 0xe9, 0x04, 0x2a, 0xca, 0xee, 0xa7, 0xb9, 0x3f, 0xcd, 0xe7, 0x1f, 0x3e, 0xaa, 0xcd, 0x7d,
-	0xf8, 0xa8, 0x36, 0xf7, 0xd1, 0xa3, 0xda, 0xdc, 0xfb, 0xe3, 0x9a, 0xf6, 0x70, 0x5c, 0xd3, 0x3e,
-	0x1c, 0xd7, 0xb4, 0x8f, 0xc6, 0x35, 0xed, 0xcf, 0xe3, 0x9a, 0xf6, 0xb3, 0xbf, 0xd4, 0xe6, 0xbe,
-	0xbe, 0xa0, 0x42, 0xff, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x50, 0x14, 0x7b, 0x97, 0xd8, 0x1f,
-	0x00, 0x00,
+	// 1993 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x59, 0x5f, 0x6f, 0x23, 0x57,
+	0x15, 0xcf, 0x78, 0xec, 0xfc, 0x39, 0x71, 0x12, 0xe7, 0xa6, 0xdb, 0x35, 0xcb, 0x62, 0xa7, 0x53,
+	0x40, 0x79, 0xa0, 0xe3, 0x26, 0x6c, 0xbb, 0x0b, 0xed, 0x16, 0xc5, 0xbb, 0x69, 0xb0, 0xb4, 0xeb,
+	0x9a, 0x9b, 0x54, 0x2b, 0xa1, 0xb6, 0x74, 0x32, 0x73, 0x6d, 0x0f, 0xb1, 0xe7, 0x0e, 0x33, 0xd7,
+	0x61, 0x23, 0x5e, 0x8a, 0x80, 0x87, 0x02, 0x12, 0xbc, 0xf1, 0x11, 0x10, 0x12, 0x5f, 0x82, 0x07,
+	0xc4, 0xf2, 0x56, 0x84, 0x10, 0x7d, 0xb2, 0x58, 0x23, 0x40, 0x3c, 0xc0, 0x07, 0x08, 0x2f, 0xe8,
+	0xde, 0xb9, 0xf3, 0xd7, 0xf1, 0x06, 0x6f, 0xb2, 0x41, 0x82, 0x3e, 0x79, 0xe6, 0xde, 0x73, 0xce,
+	0xef, 0xfc, 0xbf, 0xe7, 0x8e, 0xe1, 0x0d, 0xc3, 0x61, 0x1e, 0x31, 0x74, 0x9b, 0xd6, 0x82, 0xa7,
+	0x9a, 0x7b, 0xd8, 0xa9, 0x19, 0xae, 0xed, 0xd7, 0x4c, 0xea, 0x30, 0x8f, 0xf6, 0xdc, 0x9e, 0xe1,
+	0x90, 0xda, 0xd1, 0xe6, 0x01, 0x61, 0xc6, 0x56, 0xad, 0x43, 0x1c, 0xe2, 0x19, 0x8c, 0x58, 0xba,
+	0xeb, 0x51, 0x46, 0x91, 0x1e, 0x70, 0x7d, 0xc3, 0xa6, 0xf2, 0x49, 0x77, 0x0f, 0x3b, 0x3a, 0xe7,
+	0xd7, 0x93, 0xfc, 0xba, 0xe4, 0xbf, 0x76, 0x6b, 0x32, 0x9e, 0xcf, 0x0c, 0xe6, 0xd7, 0x8e, 0x36,
+	0x8d, 0x9e, 0xdb, 0x35, 0x36, 0xb3, 0x48, 0xd7, 0x5e, 0xea, 0xd8, 0xac, 0x3b, 0x38, 0xd0, 0x4d,
+	0xda, 0xaf, 0x75, 0x68, 0x87, 0xd6, 0xc4, 0xf2, 0xc1, 0xa0, 0x2d, 0xde, 0xc4, 0x8b, 0x78, 0x92,
+	0xe4, 0x37, 0x0e, 0x6f, 0xf9, 0x02, 0xc5, 0xb5, 0xfb, 0x86, 0xd9, 0xb5, 0x1d, 0xe2, 0x1d, 0xc7,
+	0x58, 0x7d, 0xc2, 0x8c, 0xda, 0xd1, 0x38, 0x48, 0x6d, 0x12, 0x97, 0x37, 0x70, 0x98, 0xdd, 0x27,
+	0x63, 0x0c, 0xaf, 0x9e, 0xc5, 0xe0, 0x9b, 0x5d, 0xd2, 0x37, 0xc6, 0xf8, 0xbe, 0x38, 0x89, 0x6f,
+	0xc0, 0xec, 0x5e, 0xcd, 0x76, 0x98, 0xcf, 0xbc, 0x2c, 0x93, 0xf6, 0x37, 0x05, 0x8a, 0xdb, 0x96,
+	0xe5, 0x11, 0xdf, 0xdf, 0xf5, 0xe8, 0xc0, 0x45, 0xef, 0xc3, 0x3c, 0xb7, 0xc4, 0x32, 0x98, 0x51,
+	0x56, 0xd6, 0x95, 0x8d, 0xc5, 0xad, 0x97, 0xf5, 0x40, 0xb0, 0x9e, 0x14, 0x1c, 0xc7, 0x84, 0x53,
+	0xeb, 0x47, 0x9b, 0xfa, 0x5b, 0x07, 0xdf, 0x24, 0x26, 0xbb, 0x4f, 0x98, 0x51, 0x47, 0x8f, 0x86,
+	0xd5, 0x99, 0xd1, 0xb0, 0x0a, 0xf1, 0x1a, 0x8e, 0xa4, 0xa2, 0x01, 0x14, 0x3b, 0x1c, 0xea, 0x3e,
+	0xe9, 0x1f, 0x10, 0xcf, 0x2f, 0xe7, 0xd6, 0xd5, 0x8d, 0xc5, 0xad, 0xd7, 0xa6, 0x0c, 0xbb, 0xbe,
+	0x1b, 0xcb, 0xa8, 0x3f, 0x27, 0x01, 0x8b, 0x89, 0x45, 0x1f, 0xa7, 0x60, 0xb4, 0xdf, 0x2b, 0x50,
+	0x4a, 0x5a, 0x7a, 0xcf, 0xf6, 0x19, 0x7a, 0x67, 0xcc, 0x5a, 0xfd, 0x3f, 0xb3, 0x96, 0x73, 0x0b,
+	0x5b, 0x4b, 0x12, 0x7a, 0x3e, 0x5c, 0x49, 0x58, 0x6a, 0x40, 0xc1, 0x66, 0xa4, 0x1f, 0x9a, 0xf8,
+	0xfa, 0xb4, 0x26, 0x26, 0xd5, 0xad, 0x2f, 0x49, 0xa0, 0x42, 0x83, 0x8b, 0xc4, 0x81, 0x64, 0xed,
+	0x43, 0x15, 0x56, 0x93, 0x64, 0x2d, 0x83, 0x99, 0xdd, 0x4b, 0x08, 0xe2, 0xf7, 0x15, 0x58, 0x35,
+	0x2c, 0x8b, 0x58, 0xbb, 0x17, 0x1c, 0xca, 0x4f, 0x49, 0xd8, 0xd5, 0xed, 0xac, 0x74, 0x3c, 0x0e,
+	0x88, 0x7e, 0xa8, 0xc0, 0x9a, 0x47, 0xfa, 0xf4, 0x28, 0xa3, 0x88, 0x7a, 0x7e, 0x45, 0x3e, 0x2d,
+	0x15, 0x59, 0xc3, 0xe3, 0xf2, 0xf1, 0x69, 0xa0, 0xda, 0xdf, 0x15, 0x58, 0xde, 0x76, 0xdd, 0x9e,
+	0x4d, 0xac, 0x7d, 0xfa, 0x3f, 0x5e, 0x4d, 0x7f, 0x54, 0x00, 0xa5, 0x6d, 0xbd, 0x84, 0x7a, 0x32,
+	0xd3, 0xf5, 0xf4, 0xc6, 0xd4, 0xf5, 0x94, 0x52, 0x78, 0x42, 0x45, 0xfd, 0x48, 0x85, 0xb5, 0x34,
+	0xe1, 0x27, 0x35, 0xf5, 0xdf, 0xab, 0xa9, 0x7f, 0x29, 0xb0, 0x76, 0xa7, 0x37, 0xf0, 0x19, 0xf1,
+	0x52, 0x4a, 0x3e, 0xfb, 0x68, 0x7c, 0x57, 0x81, 0x12, 0x69, 0xb7, 0x89, 0xc9, 0xec, 0x23, 0x72,
+	0x81, 0xc1, 0x28, 0x4b, 0xd4, 0xd2, 0x4e, 0x46, 0x38, 0x1e, 0x83, 0xd3, 0xfe, 0xaa, 0xc0, 0xe2,
+	0x4e, 0xe7, 0xff, 0xe0, 0x70, 0xfe, 0x9d, 0x02, 0x2b, 0x09, 0x43, 0x2f, 0xa1, 0x97, 0xbc, 0x9f,
+	0xee, 0x25, 0x53, 0x5b, 0x98, 0xd0, 0x76, 0x42, 0x23, 0xf9, 0xb1, 0x0a, 0xa5, 0x04, 0x55, 0xd0,
+	0x45, 0x2c, 0x00, 0x1a, 0xf9, 0xfd, 0x42, 0x63, 0x98, 0x90, 0xfb, 0x49, 0x27, 0x39, 0xa5, 0x93,
+	0xf4, 0xe0, 0xea, 0xce, 0x43, 0x46, 0x3c, 0xc7, 0xe8, 0xed, 0x38, 0xcc, 0x66, 0xc7, 0x98, 0xb4,
+	0x89, 0x47, 0x1c, 0x93, 0xa0, 0x75, 0xc8, 0x3b, 0x46, 0x9f, 0x88, 0x70, 0x2c, 0xd4, 0x8b, 0x52,
+	0x74, 0xbe, 0x69, 0xf4, 0x09, 0x16, 0x3b, 0xa8, 0x06, 0x0b, 0xfc, 0xd7, 0x77, 0x0d, 0x93, 0x94,
+	0x73, 0x82, 0x6c, 0x55, 0x92, 0x2d, 0x34, 0xc3, 0x0d, 0x1c, 0xd3, 0xf0, 0xbe, 0x55, 0x12, 0xf0,
+	0xdb, 0xbe, 0x4f, 0x4d, 0xdb, 0x60, 0x36, 0x75, 0x2e, 0xe7, 0x08, 0x29, 0x19, 0x12, 0x51, 0xda,
+	0xff, 0xd4, 0xa7, 0xa5, 0xe0, 0x8e, 0x9c, 0x14, 0xf7, 0xad, 0xed, 0x8c, 0x7c, 0x3c, 0x86, 0xa8,
+	0xfd, 0x46, 0x85, 0xc5, 0x84, 0xf3, 0xd1, 0x03, 0x50, 0x5d, 0x6a, 0x49, 0x9b, 0xa7, 0x1e, 0x83,
+	0x5b, 0xd4, 0x8a, 0xd5, 0x98, 0x1b, 0x0d, 0xab, 0x2a, 0x5f, 0xe1, 0x12, 0xd1, 0xf7, 0x14, 0x58,
+	0x26, 0xa9, 0xa8, 0x8a, 0xe8, 0x2c, 0x6e, 0xed, 0x4e, 0x5d, 0xcf, 0xa7, 0xe7, 0x46, 0x1d, 0x8d,
+	0x86, 0xd5, 0xe5, 0xcc, 0x66, 0x06, 0x12, 0x7d, 0x1e, 0x54, 0xdb, 0x0d, 0xd2, 0xba, 0x58, 0x7f,
+	0x8e, 0x2b, 0xd8, 0x68, 0xf9, 0x27, 0xc3, 0xea, 0x42, 0xa3, 0x25, 0x67, 0x73, 0xcc, 0x09, 0xd0,
+	0x7b, 0x50, 0x70, 0xa9, 0xc7, 0xfc, 0x72, 0x5e, 0x44, 0xe4, 0x4b, 0xd3, 0xea, 0xc8, 0x33, 0xcd,
+	0x6a, 0x51, 0x8f, 0xc5, 0x1d, 0x87, 0xbf, 0xf9, 0x38, 0x10, 0x8b, 0xbe, 0x06, 0x79, 0x87, 0x5a,
+	0xa4, 0x5c, 0x10, 0x2e, 0xb8, 0x3d, 0xb5, 0x78, 0x6a, 0x91, 0xc8, 0x70, 0x2c, 0x44, 0x69, 0x3f,
+	0x57, 0x60, 0x39, 0x9d, 0x08, 0xe9, 0x5a, 0x50, 0xce, 0xae, 0x85, 0xa8, 0xbc, 0x72, 0x13, 0xcb,
+	0xab, 0x0e, 0xea, 0xc0, 0xb6, 0xca, 0xaa, 0x20, 0x78, 0x59, 0x12, 0xa8, 0x6f, 0x37, 0xee, 0x9e,
+	0x0c, 0xab, 0x2f, 0x4c, 0xba, 0xd6, 0xb2, 0x63, 0x97, 0xf8, 0xfa, 0xdb, 0x8d, 0xbb, 0x98, 0x33,
+	0x6b, 0x4d, 0x28, 0x7e, 0x75, 0x7f, 0xbf, 0xd5, 0xf2, 0x28, 0xa3, 0x26, 0xed, 0x21, 0x04, 0xf9,
+	0x2e, 0xf5, 0x59, 0xa0, 0x21, 0x16, 0xcf, 0xe8, 0x79, 0x98, 0xed, 0x13, 0xd6, 0xa5, 0x56, 0xa0,
+	0x0b, 0x96, 0x6f, 0x9c, 0xd6, 0x35, 0x58, 0x37, 0x50, 0x00, 0x8b, 0x67, 0xed, 0x57, 0x0a, 0xcc,
+	0x35, 0x5a, 0xf5, 0x1e, 0x35, 0x0f, 0xd1, 0x03, 0xc8, 0x9b, 0xb6, 0xe5, 0xc9, 0x04, 0x7e, 0x65,
+	0x5a, 0xc7, 0x36, 0x5a, 0x4d, 0xc2, 0x62, 0xc3, 0xef, 0x34, 0xee, 0x62, 0x2c, 0x04, 0xa2, 0x77,
+	0x61, 0x96, 0x3c, 0x34, 0x89, 0xcb, 0x64, 0x91, 0x3e, 0xa5, 0xe8, 0x65, 0x29, 0x7a, 0x76, 0x47,
+	0x08, 0xc3, 0x52, 0xa8, 0xd6, 0x86, 0x82, 0x20, 0x40, 0x2f, 0x42, 0xce, 0x76, 0x85, 0xfa, 0xc5,
+	0xfa, 0xda, 0x68, 0x58, 0xcd, 0x35, 0x5a, 0xe9, 0xfc, 0xcc, 0xd9, 0x2e, 0xba, 0x05, 0x45, 0xd7,
+	0x23, 0x6d, 0xfb, 0xe1, 0x3d, 0xe2, 0x74, 0x58, 0x57, 0xf8, 0xa8, 0x10, 0x1f, 0xdf, 0xad, 0xc4,
+	0x1e, 0x4e, 0x51, 0x6a, 0xef, 0x01, 0xdc, 0xbb, 0x19, 0x79, 0xbe, 0x05, 0xf9, 0x2e, 0x63, 0xee,
+	0xd3, 0x96, 0x7b, 0x32, 0x8a, 0x58, 0x48, 0xd2, 0x3e, 0x54, 0x60, 0x21, 0x4a, 0x7e, 0x9e, 0x4f,
+	0x3c, 0xdf, 0x85, 0xfc, 0x42, 0xec, 0x56, 0xbe, 0x87, 0xf3, 0xae, 0xa4, 0x38, 0x23, 0xe3, 0x6e,
+	0xc1, 0xbc, 0x2b, 0x31, 0x64, 0xda, 0x5d, 0x0f, 0x87, 0x85, 0x10, 0xfb, 0x24, 0xf1, 0x8c, 0x23,
+	0x6a, 0xed, 0x1f, 0x2a, 0x2c, 0x35, 0x09, 0xfb, 0x36, 0xf5, 0x0e, 0x5b, 0xb4, 0x67, 0x9b, 0xc7,
+	0x97, 0xd0, 0xd6, 0xdb, 0x50, 0xf0, 0x06, 0x3d, 0x12, 0xb6, 0xf2, 0xed, 0xa9, 0x2b, 0x3b, 0xa9,
+	0x2f, 0x1e, 0xf4, 0x48, 0xdc, 0x40, 0xf8, 0x9b, 0x8f, 0x03, 0xf1, 0xe8, 0x36, 0xac, 0x18, 0xa9,
+	0xab, 0x4f, 0xd0, 0xd4, 0x16, 0x44, 0xce, 0xac, 0xa4, 0x6f, 0x45, 0x3e, 0xce, 0xd2, 0xa2, 0x0d,
+	0xee, 0x54, 0x9b, 0x7a, 0xbc, 0x0d, 0xe7, 0xd7, 0x95, 0x0d, 0xa5, 0x5e, 0x0c, 0x1c, 0x1a, 0xac,
+	0xe1, 0x68, 0x17, 0xdd, 0x80, 0x22, 0xb3, 0x89, 0x17, 0xee, 0x88, 0x8e, 0x55, 0xa8, 0x97, 0x78,
+	0x9a, 0xed, 0x27, 0xd6, 0x71, 0x8a, 0x0a, 0xf9, 0xb0, 0xe0, 0xd3, 0x81, 0x67, 0xf2, 0x2e, 0x55,
+	0x9e, 0x15, 0x9e, 0x7e, 0xf3, 0x7c, 0xae, 0x88, 0xda, 0xfc, 0x12, 0xef, 0x5e, 0x7b, 0xa1, 0x70,
+	0x1c, 0xe3, 0x68, 0x7f, 0x50, 0x60, 0x35, 0xc5, 0x74, 0x09, 0xc3, 0xe9, 0x41, 0x7a, 0x38, 0xbd,
+	0x7d, 0x2e, 0x23, 0x27, 0x8c, 0xa7, 0xdf, 0x81, 0xab, 0x29, 0x32, 0xde, 0xfd, 0xf7, 0x98, 0xc1,
+	0x06, 0x3e, 0xfa, 0x02, 0xcc, 0xf3, 0xe6, 0xdf, 0x8c, 0x67, 0xa2, 0x48, 0xd9, 0xa6, 0x5c, 0xc7,
+	0x11, 0x05, 0xda, 0x02, 0x90, 0x5f, 0x15, 0x6d, 0xea, 0x88, 0x92, 0x53, 0xe3, 0x74, 0xde, 0x8d,
+	0x76, 0x70, 0x82, 0x4a, 0xfb, 0x6d, 0xd6, 0xa9, 0x2d, 0x42, 0x3c, 0x74, 0x13, 0x96, 0x8c, 0xc4,
+	0xb7, 0x2c, 0xbf, 0xac, 0x88, 0xe4, 0x5b, 0x1d, 0x0d, 0xab, 0x4b, 0xc9, 0x8f, 0x5c, 0x3e, 0x4e,
+	0xd3, 0x21, 0x02, 0xf3, 0xb6, 0x2b, 0x5a, 0x75, 0xe8, 0xb2, 0x9b, 0xd3, 0x37, 0x52, 0xc1, 0x1f,
+	0x5b, 0x2a, 0x17, 0x7c, 0x1c, 0x89, 0x46, 0xcf, 0x41, 0xa1, 0xfd, 0x2d, 0xcb, 0x91, 0x45, 0x81,
+	0x83, 0x17, 0x7e, 0x49, 0x7b, 0xfe, 0xf4, 0xac, 0x42, 0xaf, 0x40, 0x9e, 0x9f, 0x52, 0xd2, 0x89,
+	0x2f, 0x84, 0x7d, 0x68, 0xff, 0xd8, 0x25, 0x27, 0xc3, 0x6a, 0xda, 0x03, 0x7c, 0x11, 0x0b, 0xf2,
+	0xa9, 0xa7, 0xcd, 0xa8, 0xdf, 0xa9, 0x67, 0x9d, 0xb0, 0xf9, 0xf3, 0x9c, 0xb0, 0xbf, 0x98, 0xcd,
+	0x04, 0x8d, 0xf7, 0x0e, 0xf4, 0x3a, 0x2c, 0x58, 0xb6, 0xc7, 0xef, 0xad, 0xd4, 0x91, 0x86, 0x56,
+	0x42, 0x65, 0xef, 0x86, 0x1b, 0x27, 0xc9, 0x17, 0x1c, 0x33, 0x20, 0x13, 0xf2, 0x6d, 0x8f, 0xf6,
+	0xe5, 0xd4, 0x76, 0xbe, 0xc6, 0xc6, 0x73, 0x28, 0x36, 0xfe, 0x4d, 0x8f, 0xf6, 0xb1, 0x10, 0x8e,
+	0xde, 0x85, 0x1c, 0xa3, 0x65, 0xf5, 0xa2, 0x20, 0x40, 0x42, 0xe4, 0xf6, 0x29, 0xce, 0x31, 0xca,
+	0xb3, 0xcf, 0x27, 0xde, 0x91, 0x6d, 0x92, 0x70, 0xb2, 0x9b, 0x3a, 0xfb, 0xf6, 0x02, 0xfe, 0x38,
+	0xfb, 0xe4, 0x82, 0x8f, 0x23, 0xd1, 0xbc, 0x2a, 0xdd, 0x4c, 0xbf, 0x8c, 0x8f, 0xac, 0xb1, 0x0e,
+	0xfb, 0x00, 0x66, 0x8d, 0x20, 0x26, 0xb3, 0x22, 0x26, 0x5f, 0xe1, 0xe3, 0xc1, 0x76, 0x18, 0x8c,
+	0xcd, 0x27, 0xfc, 0x47, 0xe3, 0x59, 0xd1, 0x3f, 0x26, 0x3a, 0x8f, 0x70, 0xc0, 0x84, 0xa5, 0x38,
+	0xf4, 0x1a, 0x2c, 0x11, 0xc7, 0x38, 0xe8, 0x91, 0x7b, 0xb4, 0xd3, 0xb1, 0x9d, 0x4e, 0x79, 0x6e,
+	0x5d, 0xd9, 0x98, 0xaf, 0x5f, 0x91, 0xba, 0x2c, 0xed, 0x24, 0x37, 0x71, 0x9a, 0xf6, 0xb4, 0x03,
+	0x66, 0x7e, 0x8a, 0x03, 0x26, 0xcc, 0xf3, 0x85, 0x89, 0x79, 0xfe, 0x0e, 0x2c, 0xf6, 0xa2, 0x49,
+	0xc4, 0x2f, 0x83, 0x08, 0xc7, 0x97, 0xa7, 0x0d, 0x47, 0x3c, 0xcc, 0xe0, 0xa4, 0x38, 0xb4, 0x01,
+	0x2b, 0xc4, 0x69, 0x53, 0xcf, 0x24, 0x7d, 0xe2, 0xb0, 0xfb, 0x7c, 0xd6, 0x5e, 0x14, 0x23, 0x63,
+	0x76, 0x59, 0xfb, 0x89, 0x0a, 0x28, 0x95, 0x39, 0xbc, 0xb5, 0xfa, 0xfc, 0xbe, 0xb2, 0xe4, 0x24,
+	0x97, 0xcb, 0xca, 0x85, 0x1e, 0x63, 0x51, 0x14, 0xd2, 0xfb, 0x69, 0x4c, 0xe4, 0x42, 0x91, 0x79,
+	0x46, 0xbb, 0x6d, 0x9b, 0x42, 0x2b, 0x59, 0x7c, 0xaf, 0x3e, 0x41, 0x07, 0xf1, 0x47, 0x9a, 0x1e,
+	0xa5, 0xc5, 0x7e, 0x82, 0x3b, 0x1e, 0x10, 0x93, 0xab, 0x38, 0x85, 0x80, 0x3e, 0x50, 0xa0, 0xc4,
+	0x47, 0x8c, 0x24, 0x49, 0x59, 0x3d, 0x33, 0x38, 0x19, 0x58, 0x9c, 0x91, 0x10, 0xdf, 0x49, 0xb3,
+	0x3b, 0x78, 0x0c, 0x4d, 0xfb, 0x8b, 0x02, 0x6b, 0x63, 0x11, 0x19, 0x5c, 0xc6, 0x97, 0xc4, 0x1e,
+	0x14, 0xf8, 0x61, 0x19, 0x1e, 0x4d, 0xbb, 0xe7, 0x8a, 0x75, 0x7c, 0x4c, 0xc7, 0xe7, 0x3a, 0x5f,
+	0xf3, 0x71, 0x00, 0xa2, 0xbd, 0x08, 0x4b, 0xa9, 0x8b, 0x1c, 0xbf, 0xdc, 0xc4, 0x5f, 0x37, 0x82,
+	0x32, 0xd1, 0x7e, 0x9d, 0x87, 0x52, 0x28, 0xc9, 0xdf, 0x1b, 0xf4, 0xfb, 0x86, 0x77, 0x19, 0x73,
+	0xec, 0x0f, 0x14, 0x58, 0x49, 0xa6, 0xa2, 0x1d, 0x39, 0xa5, 0x7e, 0x2e, 0xa7, 0x04, 0xd9, 0x70,
+	0x55, 0x62, 0xaf, 0x34, 0xd3, 0x10, 0x38, 0x8b, 0x89, 0x7e, 0xa9, 0xc0, 0xf5, 0x00, 0x45, 0x7e,
+	0x5b, 0xce, 0x70, 0x94, 0xd5, 0x0b, 0x53, 0xea, 0xb3, 0x52, 0xa9, 0xeb, 0xdb, 0x4f, 0xc0, 0xc3,
+	0x4f, 0xd4, 0x06, 0xfd, 0x4c, 0x81, 0x2b, 0x01, 0x41, 0x56, 0xcf, 0xfc, 0x85, 0xe9, 0xf9, 0x19,
+	0xa9, 0xe7, 0x95, 0xed, 0xd3, 0x80, 0xf0, 0xe9, 0xf8, 0x9a, 0x01, 0xc5, 0xe4, 0xd7, 0x99, 0x67,
+	0xf1, 0x25, 0xed, 0x9f, 0x0a, 0xcc, 0xc9, 0xd3, 0x10, 0xdd, 0x48, 0xdc, 0xda, 0x02, 0x88, 0xf2,
+	0xd9, 0x37, 0x36, 0xd4, 0x94, 0xf7, 0xc5, 0xdc, 0x19, 0x39, 0xcd, 0xff, 0x27, 0xd7, 0x83, 0xff,
+	0xc9, 0xf5, 0x86, 0xc3, 0xde, 0xf2, 0xf6, 0x98, 0x67, 0x3b, 0x9d, 0xfa, 0x7c, 0xe6, 0x76, 0xf9,
+	0x39, 0x98, 0x23, 0x8e, 0xb8, 0x8a, 0x8a, 0x99, 0xa2, 0x50, 0x5f, 0x1c, 0x0d, 0xab, 0x73, 0x3b,
+	0xc1, 0x12, 0x0e, 0xf7, 0xd0, 0x35, 0x98, 0xb7, 0xcd, 0xbe, 0xcb, 0xe7, 0x3a, 0x31, 0x77, 0x15,
+	0x70, 0xf4, 0x1e, 0xee, 0xdd, 0x09, 0xbf, 0xd6, 0x14, 0x70, 0xf4, 0xae, 0x11, 0x28, 0x49, 0x7b,
+	0x9f, 0xa5, 0x5f, 0xeb, 0x2f, 0x3d, 0x7a, 0x5c, 0x99, 0xf9, 0xe8, 0x71, 0x65, 0xe6, 0xe3, 0xc7,
+	0x95, 0x99, 0x0f, 0x46, 0x15, 0xe5, 0xd1, 0xa8, 0xa2, 0x7c, 0x34, 0xaa, 0x28, 0x1f, 0x8f, 0x2a,
+	0xca, 0x9f, 0x46, 0x15, 0xe5, 0xa7, 0x7f, 0xae, 0xcc, 0x7c, 0x7d, 0x4e, 0xa6, 0xcc, 0xbf, 0x07,
+	0x00, 0x05, 0xf0, 0x32, 0xf4, 0xd6, 0x21, 0x00, 0x00,
 }
 
 func (m *AddressGroup) Marshal() (dAtA []byte, err error) {
@@ -1626,6 +1723,18 @@ func (m *GroupMember) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Node != nil {
+		{
+			size, err := m.Node.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
 	if len(m.Ports) > 0 {
 		for iNdEx := len(m.Ports) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1714,6 +1823,44 @@ func (m *GroupReference) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *HTTPProtocol) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HTTPProtocol) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HTTPProtocol) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.Path)
+	copy(dAtA[i:], m.Path)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Path)))
+	i--
+	dAtA[i] = 0x1a
+	i -= len(m.Method)
+	copy(dAtA[i:], m.Method)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Method)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Host)
+	copy(dAtA[i:], m.Host)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Host)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *IPBlock) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1794,6 +1941,41 @@ func (m *IPNet) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *L7Protocol) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *L7Protocol) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *L7Protocol) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.HTTP != nil {
+		{
+			size, err := m.HTTP.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *NamedPort) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2007,6 +2189,15 @@ func (m *NetworkPolicyPeer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.FQDNs) > 0 {
+		for iNdEx := len(m.FQDNs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.FQDNs[iNdEx])
+			copy(dAtA[i:], m.FQDNs[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.FQDNs[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.IPBlocks) > 0 {
 		for iNdEx := len(m.IPBlocks) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -2096,6 +2287,25 @@ func (m *NetworkPolicyRule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	i -= len(m.EnforcementMode)
+	copy(dAtA[i:], m.EnforcementMode)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.EnforcementMode)))
+	i--
+	dAtA[i] = 0x5a
+	if len(m.L7Protocols) > 0 {
+		for iNdEx := len(m.L7Protocols) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.L7Protocols[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
 	i -= len(m.Name)
 	copy(dAtA[i:], m.Name)
 	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
@@ -2274,6 +2484,34 @@ func (m *NetworkPolicyStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NodeReference) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NodeReference) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NodeReference) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *NodeStatsSummary) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2402,6 +2640,16 @@ func (m *Service) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ICMPCode != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.ICMPCode))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.ICMPType != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.ICMPType))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.EndPort != nil {
 		i = encodeVarintGenerated(dAtA, i, uint64(*m.EndPort))
 		i--
@@ -2717,6 +2965,10 @@ func (m *GroupMember) Size() (n int) {
 			n += 1 + l + sovGenerated(uint64(l))
 		}
 	}
+	if m.Node != nil {
+		l = m.Node.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
 	return n
 }
 
@@ -2735,6 +2987,21 @@ func (m *GroupReference) Size() (n int) {
 	return n
 }
 
+func (m *HTTPProtocol) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Host)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Method)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Path)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
 func (m *IPBlock) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2766,6 +3033,19 @@ func (m *IPNet) Size() (n int) {
 	return n
 }
 
+func (m *L7Protocol) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.HTTP != nil {
+		l = m.HTTP.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
 func (m *NamedPort) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2860,6 +3140,12 @@ func (m *NetworkPolicyPeer) Size() (n int) {
 			n += 1 + l + sovGenerated(uint64(l))
 		}
 	}
+	if len(m.FQDNs) > 0 {
+		for _, s := range m.FQDNs {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2912,6 +3198,14 @@ func (m *NetworkPolicyRule) Size() (n int) {
 	}
 	l = len(m.Name)
 	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.L7Protocols) > 0 {
+		for _, e := range m.L7Protocols {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	l = len(m.EnforcementMode)
+	n += 1 + l + sovGenerated(uint64(l))
 	return n
 }
 
@@ -2951,6 +3245,17 @@ func (m *NetworkPolicyStatus) Size() (n int) {
 	return n
 }
 
+func (m *NodeReference) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
 func (m *NodeStatsSummary) Size() (n int) {
 	if m == nil {
 		return 0
@@ -3010,6 +3315,12 @@ func (m *Service) Size() (n int) {
 	if m.EndPort != nil {
 		n += 1 + sovGenerated(uint64(*m.EndPort))
 	}
+	if m.ICMPType != nil {
+		n += 1 + sovGenerated(uint64(*m.ICMPType))
+	}
+	if m.ICMPCode != nil {
+		n += 1 + sovGenerated(uint64(*m.ICMPCode))
+	}
 	return n
 }
 
@@ -3251,6 +3562,7 @@ func (this *GroupMember) String() string {
 		`ExternalEntity:` + strings.Replace(this.ExternalEntity.String(), "ExternalEntityReference", "ExternalEntityReference", 1) + `,`,
 		`IPs:` + fmt.Sprintf("%v", this.IPs) + `,`,
 		`Ports:` + repeatedStringForPorts + `,`,
+		`Node:` + strings.Replace(this.Node.String(), "NodeReference", "NodeReference", 1) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3267,6 +3579,18 @@ func (this *GroupReference) String() string {
 	}, "")
 	return s
 }
+func (this *HTTPProtocol) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&HTTPProtocol{`,
+		`Host:` + fmt.Sprintf("%v", this.Host) + `,`,
+		`Method:` + fmt.Sprintf("%v", this.Method) + `,`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *IPBlock) String() string {
 	if this == nil {
 		return "nil"
@@ -3294,6 +3618,16 @@ func (this *IPNet) String() string {
 	}, "")
 	return s
 }
+func (this *L7Protocol) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&L7Protocol{`,
+		`HTTP:` + strings.Replace(this.HTTP.String(), "HTTPProtocol", "HTTPProtocol", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *NamedPort) String() string {
 	if this == nil {
 		return "nil"
@@ -3365,6 +3699,7 @@ func (this *NetworkPolicyPeer) String() string {
 	s := strings.Join([]string{`&NetworkPolicyPeer{`,
 		`AddressGroups:` + fmt.Sprintf("%v", this.AddressGroups) + `,`,
 		`IPBlocks:` + repeatedStringForIPBlocks + `,`,
+		`FQDNs:` + fmt.Sprintf("%v", this.FQDNs) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3391,6 +3726,11 @@ func (this *NetworkPolicyRule) String() string {
 		repeatedStringForServices += strings.Replace(strings.Replace(f.String(), "Service", "Service", 1), `&`, ``, 1) + ","
 	}
 	repeatedStringForServices += "}"
+	repeatedStringForL7Protocols := "[]L7Protocol{"
+	for _, f := range this.L7Protocols {
+		repeatedStringForL7Protocols += strings.Replace(strings.Replace(f.String(), "L7Protocol", "L7Protocol", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForL7Protocols += "}"
 	s := strings.Join([]string{`&NetworkPolicyRule{`,
 		`Direction:` + fmt.Sprintf("%v", this.Direction) + `,`,
 		`From:` + strings.Replace(strings.Replace(this.From.String(), "NetworkPolicyPeer", "NetworkPolicyPeer", 1), `&`, ``, 1) + `,`,
@@ -3401,6 +3741,8 @@ func (this *NetworkPolicyRule) String() string {
 		`EnableLogging:` + fmt.Sprintf("%v", this.EnableLogging) + `,`,
 		`AppliedToGroups:` + fmt.Sprintf("%v", this.AppliedToGroups) + `,`,
 		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`L7Protocols:` + repeatedStringForL7Protocols + `,`,
+		`EnforcementMode:` + fmt.Sprintf("%v", this.EnforcementMode) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3438,6 +3780,16 @@ func (this *NetworkPolicyStatus) String() string {
 	}, "")
 	return s
 }
+func (this *NodeReference) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NodeReference{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *NodeStatsSummary) String() string {
 	if this == nil {
 		return "nil"
@@ -3485,6 +3837,8 @@ func (this *Service) String() string {
 		`Protocol:` + valueToStringGenerated(this.Protocol) + `,`,
 		`Port:` + strings.Replace(fmt.Sprintf("%v", this.Port), "IntOrString", "intstr.IntOrString", 1) + `,`,
 		`EndPort:` + valueToStringGenerated(this.EndPort) + `,`,
+		`ICMPType:` + valueToStringGenerated(this.ICMPType) + `,`,
+		`ICMPCode:` + valueToStringGenerated(this.ICMPCode) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -5178,7 +5532,43 @@ func (m *GroupMember) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Node", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Node == nil {
+				m.Node = &NodeReference{}
+			}
+			if err := m.Node.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
 			if err != nil {
@@ -5345,6 +5735,152 @@ func (m *GroupReference) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *HTTPProtocol) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HTTPProtocol: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HTTPProtocol: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Host", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Host = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Method", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Method = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *IPBlock) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -5565,6 +6101,92 @@ func (m *IPNet) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *L7Protocol) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: L7Protocol: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: L7Protocol: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HTTP", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HTTP == nil {
+				m.HTTP = &HTTPProtocol{}
+			}
+			if err := m.HTTP.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *NamedPort) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -6228,6 +6850,38 @@ func (m *NetworkPolicyPeer) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FQDNs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FQDNs = append(m.FQDNs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -6724,6 +7378,72 @@ func (m *NetworkPolicyRule) Unmarshal(dAtA []byte) error {
 			}
 			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field L7Protocols", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.L7Protocols = append(m.L7Protocols, L7Protocol{})
+			if err := m.L7Protocols[len(m.L7Protocols)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EnforcementMode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EnforcementMode = antrea_io_antrea_pkg_apis_crd_v1alpha1.RuleEnforcementMode(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -7012,6 +7732,88 @@ func (m *NetworkPolicyStatus) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *NodeReference) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NodeReference: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NodeReference: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *NodeStatsSummary) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -7429,6 +8231,46 @@ func (m *Service) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.EndPort = &v
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ICMPType", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ICMPType = &v
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ICMPCode", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ICMPCode = &v
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
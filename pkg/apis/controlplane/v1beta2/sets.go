@@ -45,6 +45,8 @@ func normalizeGroupMember(member *GroupMember) groupMemberKey {
 		b.WriteString(member.ExternalEntity.Namespace)
 		b.WriteString(delimiter)
 		b.WriteString(member.ExternalEntity.Name)
+	} else if member.Node != nil {
+		b.WriteString(member.Node.Name)
 	}
 	for _, ip := range member.IPs {
 		b.Write(ip)
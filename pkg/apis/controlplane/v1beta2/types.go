@@ -70,6 +70,12 @@ type ExternalEntityReference struct {
 	Namespace string `json:"namespace,omitempty" protobuf:"bytes,2,opt,name=namespace"`
 }
 
+// NodeReference represents a Node Reference.
+type NodeReference struct {
+	// The name of this Node.
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+}
+
 // GroupMember represents resource member to be populated in Groups.
 type GroupMember struct {
 	// Pod maintains the reference to the Pod.
@@ -80,6 +86,8 @@ type GroupMember struct {
 	IPs []IPAddress `json:"ips,omitempty" protobuf:"bytes,3,rep,name=ips"`
 	// Ports is the list NamedPort of the GroupMember.
 	Ports []NamedPort `json:"ports,omitempty" protobuf:"bytes,4,rep,name=ports"`
+	// Node maintains the reference to the Node.
+	Node *NodeReference `json:"node,omitempty" protobuf:"bytes,5,opt,name=node"`
 }
 
 // +genclient
@@ -232,6 +240,14 @@ type NetworkPolicyRule struct {
 	// Name describes the intention of this rule.
 	// Name should be unique within the policy.
 	Name string `json:"name,omitempty" protobuf:"bytes,9,opt,name=name"`
+	// L7Protocols is a list of application-layer protocols which should be matched
+	// by the traffic allowed by this rule. If it's empty, the rule is enforced at
+	// L3/L4 only.
+	L7Protocols []L7Protocol `json:"l7Protocols,omitempty" protobuf:"bytes,10,rep,name=l7Protocols"`
+	// EnforcementMode specifies how the rule is enforced. An empty mode defaults
+	// to Enforce. In Audit mode, the traffic matching the rule is only logged and
+	// counted.
+	EnforcementMode crdv1alpha1.RuleEnforcementMode `json:"enforcementMode,omitempty" protobuf:"bytes,11,opt,name=enforcementMode,casttype=antrea.io/antrea/pkg/apis/crd/v1alpha1.RuleEnforcementMode"`
 }
 
 // Protocol defines network protocols supported for things like container ports.
@@ -244,12 +260,16 @@ const (
 	ProtocolUDP Protocol = "UDP"
 	// ProtocolSCTP is the SCTP protocol.
 	ProtocolSCTP Protocol = "SCTP"
+	// ProtocolICMP is the ICMP protocol.
+	ProtocolICMP Protocol = "ICMP"
+	// ProtocolICMPv6 is the ICMPv6 protocol.
+	ProtocolICMPv6 Protocol = "ICMPv6"
 )
 
 // Service describes a port to allow traffic on.
 type Service struct {
-	// The protocol (TCP, UDP, or SCTP) which traffic must match. If not specified, this
-	// field defaults to TCP.
+	// The protocol (TCP, UDP, SCTP, ICMP, or ICMPv6) which traffic must match. If not
+	// specified, this field defaults to TCP.
 	// +optional
 	Protocol *Protocol `json:"protocol,omitempty" protobuf:"bytes,1,opt,name=protocol"`
 	// The port name or number on the given protocol. If not specified, this matches all port numbers.
@@ -259,15 +279,41 @@ type Service struct {
 	// It can only be specified when a numerical `port` is specified.
 	// +optional
 	EndPort *int32 `json:"endPort,omitempty" protobuf:"bytes,3,opt,name=endPort"`
+	// ICMPType and ICMPCode can only be specified when the Protocol is ICMP or
+	// ICMPv6. If they are not specified and the Protocol is ICMP or ICMPv6, this
+	// matches all ICMP or ICMPv6 traffic.
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty" protobuf:"varint,4,opt,name=icmpType"`
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty" protobuf:"varint,5,opt,name=icmpCode"`
+}
+
+// L7Protocol describes an application-layer protocol to match.
+type L7Protocol struct {
+	HTTP *HTTPProtocol `json:"http,omitempty" protobuf:"bytes,1,opt,name=http"`
+}
+
+// HTTPProtocol describes the HTTP requests to match. Empty fields match all
+// values.
+type HTTPProtocol struct {
+	// Host is the hostname of the requests.
+	Host string `json:"host,omitempty" protobuf:"bytes,1,opt,name=host"`
+	// Method is the method of the requests.
+	Method string `json:"method,omitempty" protobuf:"bytes,2,opt,name=method"`
+	// Path is the prefix of the path of the requests.
+	Path string `json:"path,omitempty" protobuf:"bytes,3,opt,name=path"`
 }
 
 // NetworkPolicyPeer describes a peer of NetworkPolicyRules.
-// It could be a list of names of AddressGroups and/or a list of IPBlock.
+// It could be a list of names of AddressGroups and/or a list of IPBlock,
+// or a list of FQDN selectors.
 type NetworkPolicyPeer struct {
 	// A list of names of AddressGroups.
 	AddressGroups []string `json:"addressGroups,omitempty" protobuf:"bytes,1,rep,name=addressGroups"`
 	// A list of IPBlock.
 	IPBlocks []IPBlock `json:"ipBlocks,omitempty" protobuf:"bytes,2,rep,name=ipBlocks"`
+	// A list of FQDN selectors, which can be exact names or wildcard patterns.
+	FQDNs []string `json:"fqdns,omitempty" protobuf:"bytes,3,rep,name=fqdns"`
 }
 
 // IPBlock describes a particular CIDR (Ex. "192.168.1.1/24"). The except entry describes CIDRs that should
@@ -312,6 +312,11 @@ type NetworkPolicySpec struct {
 	// field within a Rule.
 	// +optional
 	Egress []Rule `json:"egress"`
+	// Set of time windows during which the policy is enforced. If empty, the
+	// policy is always enforced. Otherwise it is only enforced while the current
+	// time is within at least one of the windows.
+	// +optional
+	ActivationWindows []ActivationWindow `json:"activationWindows,omitempty"`
 }
 
 // NetworkPolicyPhase defines the phase in which a NetworkPolicy is.
@@ -325,8 +330,63 @@ const (
 	NetworkPolicyRealizing NetworkPolicyPhase = "Realizing"
 	// NetworkPolicyRealized means the NetworkPolicy has been enforced to all Pods on all Nodes it applies to.
 	NetworkPolicyRealized NetworkPolicyPhase = "Realized"
+	// NetworkPolicyInactive means the NetworkPolicy is outside all of its activation windows and is not enforced.
+	NetworkPolicyInactive NetworkPolicyPhase = "Inactive"
+)
+
+// NetworkPolicyConditionType describes the type of a NetworkPolicyCondition.
+type NetworkPolicyConditionType string
+
+// These are the valid values for NetworkPolicyConditionType.
+const (
+	// NetworkPolicyRulesShadowed means some rules of the NetworkPolicy never match any traffic, because the
+	// traffic they select is fully covered by rules with a higher precedence and a different action.
+	NetworkPolicyRulesShadowed NetworkPolicyConditionType = "RulesShadowed"
+	// NetworkPolicyRulesRedundant means some rules of the NetworkPolicy never match any traffic, because the
+	// traffic they select is fully covered by rules with a higher precedence and the same action.
+	NetworkPolicyRulesRedundant NetworkPolicyConditionType = "RulesRedundant"
+	// NetworkPolicyRulesConflicting means some rules of the NetworkPolicy select traffic which is also selected
+	// by rules with a different action in another NetworkPolicy with the same Tier and priority, in which case
+	// the precedence between the rules is undefined.
+	NetworkPolicyRulesConflicting NetworkPolicyConditionType = "RulesConflicting"
 )
 
+type NetworkPolicyCondition struct {
+	Type               NetworkPolicyConditionType `json:"type"`
+	Status             v1.ConditionStatus         `json:"status"`
+	LastTransitionTime metav1.Time                `json:"lastTransitionTime,omitempty"`
+	// Message lists the rules affected by the condition.
+	Message string `json:"message,omitempty"`
+}
+
+// ActivationWindow describes a period of time during which a NetworkPolicy is
+// enforced. Either Start and/or End, or Schedule and Duration must be set.
+type ActivationWindow struct {
+	// Start is the time at which the window opens. If unset, the window is
+	// open until End.
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+	// End is the time at which the window closes. If unset, the window stays
+	// open after Start.
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+	// Schedule is a cron expression in the standard 5-field format
+	// ("minute hour day-of-month month day-of-week"), evaluated in TimeZone.
+	// The window opens at each time matching the expression and stays open
+	// for Duration.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// Duration is how long the window stays open after each scheduled time.
+	// It must be set together with Schedule.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// TimeZone is the name of the IANA time zone in which Schedule is
+	// evaluated, e.g. "America/Los_Angeles". It can only be set together with
+	// Schedule. If unset, Schedule is evaluated in UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
 // NetworkPolicyStatus represents information about the status of a NetworkPolicy.
 type NetworkPolicyStatus struct {
 	// The phase of a NetworkPolicy is a simple, high-level summary of the NetworkPolicy's status.
@@ -337,6 +397,8 @@ type NetworkPolicyStatus struct {
 	CurrentNodesRealized int32 `json:"currentNodesRealized"`
 	// The total number of nodes that should realize the NetworkPolicy.
 	DesiredNodesRealized int32 `json:"desiredNodesRealized"`
+	// Conditions report the issues detected in the rules of the NetworkPolicy.
+	Conditions []NetworkPolicyCondition `json:"conditions,omitempty"`
 }
 
 // Rule describes the traffic allowed to/from the workloads selected by
@@ -349,6 +411,19 @@ type Rule struct {
 	// or empty, this rule matches all ports.
 	// +optional
 	Ports []NetworkPolicyPort `json:"ports,omitempty"`
+	// Set of protocols not supported by Ports, e.g. ICMP, allowed/denied by
+	// the rule. If this field and Ports are both unset or empty, this rule
+	// matches all protocols.
+	// +optional
+	Protocols []NetworkPolicyProtocol `json:"protocols,omitempty"`
+	// Set of application-layer protocols, e.g. HTTP, allowed by the rule. It
+	// can only be set when the action is Allow. If this field is set, the
+	// traffic matching the rule is redirected to the L7 enforcement engine of
+	// the Antrea Agent, which rejects the requests not matching any of the
+	// protocols. If this field is unset or empty, the rule is enforced at
+	// L3/L4 only.
+	// +optional
+	L7Protocols []L7Protocol `json:"l7Protocols,omitempty"`
 	// Rule is matched if traffic originates from workloads selected by
 	// this field. If this field is empty, this rule matches all sources.
 	// +optional
@@ -365,6 +440,13 @@ type Rule struct {
 	// EnableLogging is used to indicate if agent should generate logs
 	// when rules are matched. Should be default to false.
 	EnableLogging bool `json:"enableLogging"`
+	// EnforcementMode specifies how the rule is enforced. In Audit mode,
+	// the traffic matching the rule is logged and counted in the
+	// NetworkPolicy stats, but the action of the rule is not applied and
+	// the traffic is evaluated against the subsequent rules as if the rule
+	// did not exist. Defaults to Enforce.
+	// +optional
+	EnforcementMode RuleEnforcementMode `json:"enforcementMode,omitempty"`
 	// Select workloads on which this rule will be applied to. Cannot be set in
 	// conjunction with NetworkPolicySpec/ClusterNetworkPolicySpec.AppliedTo.
 	// +optional
@@ -412,6 +494,22 @@ type NetworkPolicyPeer struct {
 	// a stand-alone selector. A Group cannot be set with any other
 	// selector.
 	Group string `json:"group,omitempty"`
+	// Restrict egress access to the Fully Qualified Domain Names prescribed
+	// by name or by wildcard match patterns. This field can only be set for
+	// NetworkPolicyPeer of egress rules. Cannot be set with any other field.
+	// Supported formats are:
+	//  Exact FQDNs, i.e. "google.com", "db-svc.default.svc.cluster.local".
+	//  Wildcard expressions, i.e. "*.github.com", which match any name ending
+	//  with ".github.com".
+	// +optional
+	FQDN string `json:"fqdn,omitempty"`
+	// Select Nodes as workloads in AppliedTo fields. Policies applied to
+	// Nodes are enforced on the traffic to/from the host network namespace
+	// of the selected Nodes. This field can only be set when
+	// NetworkPolicyPeer is created for ClusterNetworkPolicy AppliedTo.
+	// Cannot be set with any other selector.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
 }
 
 type PeerNamespaces struct {
@@ -450,6 +548,55 @@ type NetworkPolicyPort struct {
 	EndPort *int32 `json:"endPort,omitempty"`
 }
 
+// NetworkPolicyProtocol describes a protocol, which is not supported by
+// NetworkPolicyPort, to match in a rule. Exactly one field must be set.
+type NetworkPolicyProtocol struct {
+	// ICMP matches ICMP traffic.
+	// +optional
+	ICMP *NetworkPolicyICMP `json:"icmp,omitempty"`
+	// ICMPv6 matches ICMPv6 traffic.
+	// +optional
+	ICMPv6 *NetworkPolicyICMP `json:"icmpv6,omitempty"`
+}
+
+// NetworkPolicyICMP describes the type and code of the ICMP or ICMPv6 messages to
+// match in a rule.
+type NetworkPolicyICMP struct {
+	// The type of the messages. If this field is not provided, this matches
+	// all types.
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty"`
+	// The code of the messages. It can only be specified when ICMPType is
+	// specified. If this field is not provided, this matches all codes.
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty"`
+}
+
+// L7Protocol describes an application-layer protocol to match in a rule.
+// Exactly one field must be set.
+type L7Protocol struct {
+	// HTTP matches HTTP requests.
+	// +optional
+	HTTP *HTTPProtocol `json:"http,omitempty"`
+}
+
+// HTTPProtocol describes the HTTP requests to match in a rule. An empty
+// HTTPProtocol matches all HTTP requests.
+type HTTPProtocol struct {
+	// Host is the hostname of the requests, matched against their Host
+	// header. If this field is not provided, this matches all hosts.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Method is the method of the requests, e.g. GET. If this field is not
+	// provided, this matches all methods.
+	// +optional
+	Method string `json:"method,omitempty"`
+	// Path is the prefix of the path of the requests, which must start with
+	// "/". If this field is not provided, this matches all paths.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
 // RuleAction describes the action to be applied on traffic matching a rule.
 type RuleAction string
 
@@ -461,6 +608,22 @@ const (
 	// RuleActionReject indicates that the traffic matching the rule must be rejected and the
 	// client will receive a response.
 	RuleActionReject RuleAction = "Reject"
+	// RuleActionPass indicates that the traffic matching the rule must skip the evaluation of
+	// the remaining rules of the Tier, and be evaluated against the rules in the next Tiers,
+	// the K8s NetworkPolicy rules and the rules in the baseline Tier.
+	RuleActionPass RuleAction = "Pass"
+)
+
+// RuleEnforcementMode describes how a rule is enforced.
+type RuleEnforcementMode string
+
+const (
+	// RuleEnforcementModeEnforce describes that the action of the rule is applied to the
+	// traffic matching the rule.
+	RuleEnforcementModeEnforce RuleEnforcementMode = "Enforce"
+	// RuleEnforcementModeAudit describes that the traffic matching the rule is only logged
+	// and counted, and that the action of the rule is not applied.
+	RuleEnforcementModeAudit RuleEnforcementMode = "Audit"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -513,6 +676,11 @@ type ClusterNetworkPolicySpec struct {
 	// field within a Rule.
 	// +optional
 	Egress []Rule `json:"egress"`
+	// Set of time windows during which the policy is enforced. If empty, the
+	// policy is always enforced. Otherwise it is only enforced while the current
+	// time is within at least one of the windows.
+	// +optional
+	ActivationWindows []ActivationWindow `json:"activationWindows,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
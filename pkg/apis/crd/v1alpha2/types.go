@@ -210,6 +210,34 @@ type Egress struct {
 type EgressStatus struct {
 	// The name of the Node that holds the Egress IP.
 	EgressNode string `json:"egressNode"`
+	// The reason why the Egress IP was moved to the current Node, set when the Egress IP fails over.
+	Reason string `json:"reason,omitempty"`
+	// The most recent failovers of the Egress IP, from the oldest to the newest.
+	FailoverHistory []EgressFailover `json:"failoverHistory,omitempty"`
+	// The number of packets dropped on the Egress Node because they exceeded the bandwidth of the Egress.
+	DroppedPackets int64 `json:"droppedPackets,omitempty"`
+	// The Egress IPs of the Egress and the Nodes that hold them, set when the Egress has multiple Egress IPs.
+	EgressIPs []EgressIPStatus `json:"egressIPs,omitempty"`
+}
+
+// EgressIPStatus represents the current status of one of the Egress IPs of an Egress.
+type EgressIPStatus struct {
+	// The Egress IP.
+	IP string `json:"ip"`
+	// The name of the Node that holds the Egress IP.
+	Node string `json:"node"`
+}
+
+// EgressFailover records a move of the Egress IP from a Node to another one.
+type EgressFailover struct {
+	// The name of the Node that held the Egress IP before the failover.
+	FromNode string `json:"fromNode"`
+	// The name of the Node that holds the Egress IP after the failover.
+	ToNode string `json:"toNode"`
+	// The reason why the Egress IP was moved.
+	Reason string `json:"reason"`
+	// The time of the failover.
+	Time metav1.Time `json:"time"`
 }
 
 // EgressSpec defines the desired state for Egress.
@@ -226,6 +254,46 @@ type EgressSpec struct {
 	// If it is non-empty, the EgressIP will be assigned to a Node specified by the pool automatically and will failover
 	// to a different Node when the Node becomes unreachable.
 	ExternalIPPool string `json:"externalIPPool"`
+	// EgressIPs specifies multiple SNAT IP addresses for the selected workloads, which are hashed among them. It's
+	// mutually exclusive with EgressIP.
+	// If ExternalIPPool is empty, they must be assigned to Nodes manually.
+	// If ExternalIPPool is non-empty, the IPs must be in the pool and are assigned to the Nodes specified by the pool
+	// automatically, independently of each other. If there are fewer IPs than EgressIPCount, the missing ones will be
+	// allocated by Antrea automatically.
+	// +optional
+	EgressIPs []string `json:"egressIPs,omitempty"`
+	// EgressIPCount specifies the number of EgressIPs that should be allocated from ExternalIPPool.
+	// +optional
+	EgressIPCount int32 `json:"egressIPCount,omitempty"`
+	// Bandwidth specifies the bandwidth limit of the traffic SNAT'd with the EgressIP. It is enforced on the Node
+	// which holds the EgressIP. The Egresses sharing an EgressIP share the same limit, which is the lowest one among
+	// them.
+	// +optional
+	Bandwidth *Bandwidth `json:"bandwidth,omitempty"`
+	// To restricts the Egress to the traffic to the specified destinations. The traffic of the selected Pods to other
+	// destinations is not SNAT'd with the EgressIP. An Egress with destinations takes precedence over the Egresses
+	// applying to all destinations. If it is empty, the Egress applies to all traffic to the external network.
+	// +optional
+	To []EgressPeer `json:"to,omitempty"`
+}
+
+// EgressPeer describes the destinations of the traffic an Egress applies to. Exactly one of the fields must be set.
+type EgressPeer struct {
+	// IPBlock describes the destination IP addresses.
+	// +optional
+	IPBlock *v1alpha1.IPBlock `json:"ipBlock,omitempty"`
+	// Group is the name of a ClusterGroup whose IPBlocks describe the destination IP addresses, including the
+	// IPBlocks of its ChildGroups. The other selectors of the ClusterGroup are ignored.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// Bandwidth specifies a bandwidth limit.
+type Bandwidth struct {
+	// Rate is the maximum rate in bits per second, as a quantity, e.g. "100M".
+	Rate string `json:"rate"`
+	// Burst is the maximum burst size in bits, as a quantity, e.g. "200M". Defaults to the rate.
+	Burst string `json:"burst,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -280,3 +348,87 @@ type ExternalIPPoolList struct {
 
 	Items []ExternalIPPool `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IPPool defines one or multiple IP ranges that can be allocated to Pods by Antrea IPAM. The Pods in the Namespaces
+// annotated with the name of the IPPool get their IPs from it. The allocations are recorded in the status, so that
+// they don't depend on the Node which allocated them.
+type IPPool struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard metadata of the object.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the IPPool.
+	Spec IPPoolSpec `json:"spec"`
+
+	// Most recently observed status of the IPPool.
+	Status IPPoolStatus `json:"status"`
+}
+
+type IPPoolSpec struct {
+	// The IP ranges of this IP pool, e.g. 10.10.0.0/24, 10.10.10.2-10.10.10.20, 10.10.10.30-10.10.10.30.
+	IPRanges []SubnetIPRange `json:"ipRanges"`
+}
+
+// SubnetIPRange is a set of contiguous IP addresses in a subnet.
+type SubnetIPRange struct {
+	IPRange    `json:",inline"`
+	SubnetInfo `json:",inline"`
+}
+
+// SubnetInfo specifies the subnet of the IPs allocated to Pods.
+type SubnetInfo struct {
+	// Gateway IP of the subnet, which is configured as the default gateway of the Pods.
+	Gateway string `json:"gateway"`
+	// Prefix length of the subnet, e.g. 24 for a /24 IPv4 subnet.
+	PrefixLength int32 `json:"prefixLength"`
+}
+
+type IPPoolStatus struct {
+	// The IPs allocated from the IPPool.
+	IPAddresses []IPAddressState `json:"ipAddresses,omitempty"`
+}
+
+type IPAddressPhase string
+
+const (
+	IPAddressPhaseAllocated IPAddressPhase = "Allocated"
+)
+
+// IPAddressState is the state of an IP allocated from an IPPool.
+type IPAddressState struct {
+	// The allocated IP.
+	IPAddress string `json:"ipAddress"`
+	// The allocation phase of the IP.
+	Phase IPAddressPhase `json:"phase"`
+	// The owner of the IP.
+	Owner IPAddressOwner `json:"owner"`
+	// The Node the IP is allocated on, which the traffic to the IP is routed to.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+}
+
+// IPAddressOwner is the owner of an IP allocated from an IPPool.
+type IPAddressOwner struct {
+	Pod *PodOwner `json:"pod,omitempty"`
+}
+
+// PodOwner identifies the Pod, and the infra container of the Pod, an IP is allocated to.
+type PodOwner struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	ContainerID string `json:"containerID"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type IPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []IPPool `json:"items"`
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reachability
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"antrea.io/antrea/pkg/apis/controlplane"
+	"antrea.io/antrea/pkg/controller/networkpolicy"
+)
+
+// parseEndpoint parses an endpoint provided as an IP, a Pod name in the default
+// Namespace, or a Pod name prefixed with its Namespace and a slash.
+func parseEndpoint(value string) networkpolicy.ReachabilityEndpoint {
+	if net.ParseIP(value) != nil {
+		return networkpolicy.ReachabilityEndpoint{IP: value}
+	}
+	if i := strings.Index(value, "/"); i >= 0 {
+		return networkpolicy.ReachabilityEndpoint{Namespace: value[:i], Pod: value[i+1:]}
+	}
+	return networkpolicy.ReachabilityEndpoint{Namespace: "default", Pod: value}
+}
+
+// parseProtocol returns the protocol whose name matches the value
+// case-insensitively. Unknown protocols are returned in upper case.
+func parseProtocol(value string) controlplane.Protocol {
+	for _, protocol := range []controlplane.Protocol{controlplane.ProtocolTCP, controlplane.ProtocolUDP, controlplane.ProtocolSCTP, controlplane.ProtocolICMP, controlplane.ProtocolICMPv6} {
+		if strings.EqualFold(value, string(protocol)) {
+			return protocol
+		}
+	}
+	return controlplane.Protocol(strings.ToUpper(value))
+}
+
+func HandleFunc(rq networkpolicy.ReachabilityQuerier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		source := r.URL.Query().Get("source")
+		destination := r.URL.Query().Get("destination")
+		protocol := parseProtocol(r.URL.Query().Get("protocol"))
+		if protocol == "" {
+			protocol = controlplane.ProtocolTCP
+		}
+		// check for incomplete arguments
+		if source == "" || destination == "" {
+			http.Error(w, "source and destination must be provided", http.StatusBadRequest)
+			return
+		}
+		query := &networkpolicy.ReachabilityQuery{
+			Source:      parseEndpoint(source),
+			Destination: parseEndpoint(destination),
+			Protocol:    protocol,
+		}
+		switch protocol {
+		case controlplane.ProtocolTCP, controlplane.ProtocolUDP, controlplane.ProtocolSCTP:
+			port, err := strconv.ParseUint(r.URL.Query().Get("port"), 10, 16)
+			if err != nil || port == 0 {
+				http.Error(w, "a valid port must be provided for protocol "+string(protocol), http.StatusBadRequest)
+				return
+			}
+			query.Port = int32(port)
+		case controlplane.ProtocolICMP, controlplane.ProtocolICMPv6:
+		default:
+			http.Error(w, "unsupported protocol "+string(protocol), http.StatusBadRequest)
+			return
+		}
+		// query reachability and handle response errors
+		response, err := rq.QueryReachability(query)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			} else {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+		if err := json.NewEncoder(w).Encode(*response); err != nil {
+			http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reachability
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"antrea.io/antrea/pkg/apis/controlplane"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	"antrea.io/antrea/pkg/controller/networkpolicy"
+	queriermock "antrea.io/antrea/pkg/controller/networkpolicy/testing"
+)
+
+func TestReachabilityQuery(t *testing.T) {
+	dropResponse := &networkpolicy.ReachabilityQueryResponse{
+		Verdict: crdv1alpha1.RuleActionDrop,
+		Rule:    &networkpolicy.PolicyRuleRef{PolicyRef: networkpolicy.PolicyRef{Name: "policy1"}, Action: crdv1alpha1.RuleActionDrop},
+	}
+	tests := []struct {
+		name             string
+		handlerRequest   string
+		expectedQuery    *networkpolicy.ReachabilityQuery
+		queryResponse    *networkpolicy.ReachabilityQueryResponse
+		queryError       error
+		expectedStatus   int
+		expectedResponse *networkpolicy.ReachabilityQueryResponse
+	}{
+		{
+			name:           "missing-destination",
+			handlerRequest: "?source=ns1/pod1&port=80",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing-port",
+			handlerRequest: "?source=ns1/pod1&destination=pod2",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported-protocol",
+			handlerRequest: "?source=ns1/pod1&destination=pod2&protocol=GRE",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "pods",
+			handlerRequest: "?source=ns1/pod1&destination=pod2&port=80",
+			expectedQuery: &networkpolicy.ReachabilityQuery{
+				Source:      networkpolicy.ReachabilityEndpoint{Namespace: "ns1", Pod: "pod1"},
+				Destination: networkpolicy.ReachabilityEndpoint{Namespace: "default", Pod: "pod2"},
+				Protocol:    controlplane.ProtocolTCP,
+				Port:        80,
+			},
+			queryResponse:    dropResponse,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: dropResponse,
+		},
+		{
+			name:           "ip-icmp",
+			handlerRequest: "?source=10.0.0.1&destination=ns1/pod2&protocol=icmp",
+			expectedQuery: &networkpolicy.ReachabilityQuery{
+				Source:      networkpolicy.ReachabilityEndpoint{IP: "10.0.0.1"},
+				Destination: networkpolicy.ReachabilityEndpoint{Namespace: "ns1", Pod: "pod2"},
+				Protocol:    controlplane.ProtocolICMP,
+			},
+			queryResponse:    dropResponse,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: dropResponse,
+		},
+		{
+			name:           "ip-icmpv6",
+			handlerRequest: "?source=fd00::1&destination=ns1/pod2&protocol=icmpv6",
+			expectedQuery: &networkpolicy.ReachabilityQuery{
+				Source:      networkpolicy.ReachabilityEndpoint{IP: "fd00::1"},
+				Destination: networkpolicy.ReachabilityEndpoint{Namespace: "ns1", Pod: "pod2"},
+				Protocol:    controlplane.ProtocolICMPv6,
+			},
+			queryResponse:    dropResponse,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: dropResponse,
+		},
+		{
+			name:           "pod-not-found",
+			handlerRequest: "?source=ns1/pod1&destination=ns1/pod2&protocol=UDP&port=53",
+			expectedQuery: &networkpolicy.ReachabilityQuery{
+				Source:      networkpolicy.ReachabilityEndpoint{Namespace: "ns1", Pod: "pod1"},
+				Destination: networkpolicy.ReachabilityEndpoint{Namespace: "ns1", Pod: "pod2"},
+				Protocol:    controlplane.ProtocolUDP,
+				Port:        53,
+			},
+			queryError:     errors.NewNotFound(v1.Resource("pod"), "pod1"),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "query-error",
+			handlerRequest: "?source=10.0.0.1&destination=10.0.0.2&port=80",
+			expectedQuery: &networkpolicy.ReachabilityQuery{
+				Source:      networkpolicy.ReachabilityEndpoint{IP: "10.0.0.1"},
+				Destination: networkpolicy.ReachabilityEndpoint{IP: "10.0.0.2"},
+				Protocol:    controlplane.ProtocolTCP,
+				Port:        80,
+			},
+			queryError:     fmt.Errorf("at least one of the source and destination must be a Pod"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockQuerier := queriermock.NewMockReachabilityQuerier(mockCtrl)
+			if tt.expectedQuery != nil {
+				mockQuerier.EXPECT().QueryReachability(tt.expectedQuery).Return(tt.queryResponse, tt.queryError)
+			}
+			handler := HandleFunc(mockQuerier)
+			req, err := http.NewRequest(http.MethodGet, tt.handlerRequest, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var received networkpolicy.ReachabilityQueryResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+			assert.Equal(t, *tt.expectedResponse, received)
+		})
+	}
+}
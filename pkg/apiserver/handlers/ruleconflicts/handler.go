@@ -0,0 +1,46 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ruleconflicts
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"antrea.io/antrea/pkg/controller/networkpolicy"
+)
+
+// HandleFunc returns the function which can handle queries issued by the rule
+// conflicts command. The conflicts can be filtered by the namespace and name of
+// the policy of the affected rule.
+func HandleFunc(rq networkpolicy.RuleConflictQuerier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace := r.URL.Query().Get("namespace")
+		name := r.URL.Query().Get("name")
+		response := rq.QueryRuleConflicts()
+		conflicts := make([]networkpolicy.RuleConflict, 0, len(response.Conflicts))
+		for _, conflict := range response.Conflicts {
+			if namespace != "" && conflict.Rule.Namespace != namespace {
+				continue
+			}
+			if name != "" && conflict.Rule.Name != name {
+				continue
+			}
+			conflicts = append(conflicts, conflict)
+		}
+		if err := json.NewEncoder(w).Encode(networkpolicy.RuleConflictQueryResponse{Conflicts: conflicts}); err != nil {
+			http.Error(w, "failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
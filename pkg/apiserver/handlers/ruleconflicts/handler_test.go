@@ -0,0 +1,87 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ruleconflicts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	"antrea.io/antrea/pkg/controller/networkpolicy"
+	queriermock "antrea.io/antrea/pkg/controller/networkpolicy/testing"
+)
+
+func TestRuleConflictsQuery(t *testing.T) {
+	acnpConflict := networkpolicy.RuleConflict{
+		Type: networkpolicy.RuleConflictShadowed,
+		Rule: networkpolicy.PolicyRuleRef{PolicyRef: networkpolicy.PolicyRef{Name: "acnp1"}, RuleIndex: 1, Action: crdv1alpha1.RuleActionAllow},
+		By:   networkpolicy.PolicyRuleRef{PolicyRef: networkpolicy.PolicyRef{Name: "acnp2"}, Action: crdv1alpha1.RuleActionDrop},
+	}
+	anpConflict := networkpolicy.RuleConflict{
+		Type: networkpolicy.RuleConflictRedundant,
+		Rule: networkpolicy.PolicyRuleRef{PolicyRef: networkpolicy.PolicyRef{Namespace: "ns1", Name: "anp1"}, RuleIndex: 1, Action: crdv1alpha1.RuleActionDrop},
+		By:   networkpolicy.PolicyRuleRef{PolicyRef: networkpolicy.PolicyRef{Namespace: "ns1", Name: "anp1"}, Action: crdv1alpha1.RuleActionDrop},
+	}
+	tests := []struct {
+		name              string
+		handlerRequest    string
+		expectedConflicts []networkpolicy.RuleConflict
+	}{
+		{
+			name:              "all",
+			handlerRequest:    "",
+			expectedConflicts: []networkpolicy.RuleConflict{acnpConflict, anpConflict},
+		},
+		{
+			name:              "namespace",
+			handlerRequest:    "?namespace=ns1",
+			expectedConflicts: []networkpolicy.RuleConflict{anpConflict},
+		},
+		{
+			name:              "name",
+			handlerRequest:    "?name=acnp1",
+			expectedConflicts: []networkpolicy.RuleConflict{acnpConflict},
+		},
+		{
+			name:           "no-match",
+			handlerRequest: "?namespace=ns1&name=acnp1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockQuerier := queriermock.NewMockRuleConflictQuerier(mockCtrl)
+			mockQuerier.EXPECT().QueryRuleConflicts().Return(&networkpolicy.RuleConflictQueryResponse{
+				Conflicts: []networkpolicy.RuleConflict{acnpConflict, anpConflict},
+			})
+			handler := HandleFunc(mockQuerier)
+			req, err := http.NewRequest(http.MethodGet, tt.handlerRequest, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var received networkpolicy.RuleConflictQueryResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+			assert.Equal(t, tt.expectedConflicts, received.Conflicts)
+		})
+	}
+}
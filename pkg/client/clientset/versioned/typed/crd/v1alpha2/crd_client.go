@@ -28,6 +28,7 @@ type CrdV1alpha2Interface interface {
 	EgressesGetter
 	ExternalEntitiesGetter
 	ExternalIPPoolsGetter
+	IPPoolsGetter
 }
 
 // CrdV1alpha2Client is used to interact with features provided by the crd.antrea.io group.
@@ -51,6 +52,10 @@ func (c *CrdV1alpha2Client) ExternalIPPools() ExternalIPPoolInterface {
 	return newExternalIPPools(c)
 }
 
+func (c *CrdV1alpha2Client) IPPools() IPPoolInterface {
+	return newIPPools(c)
+}
+
 // NewForConfig creates a new CrdV1alpha2Client for the given config.
 func NewForConfig(c *rest.Config) (*CrdV1alpha2Client, error) {
 	config := *c
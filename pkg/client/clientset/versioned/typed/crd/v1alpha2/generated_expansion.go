@@ -23,3 +23,5 @@ type EgressExpansion interface{}
 type ExternalEntityExpansion interface{}
 
 type ExternalIPPoolExpansion interface{}
+
+type IPPoolExpansion interface{}
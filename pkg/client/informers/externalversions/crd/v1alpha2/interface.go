@@ -30,6 +30,8 @@ type Interface interface {
 	ExternalEntities() ExternalEntityInformer
 	// ExternalIPPools returns a ExternalIPPoolInformer.
 	ExternalIPPools() ExternalIPPoolInformer
+	// IPPools returns a IPPoolInformer.
+	IPPools() IPPoolInformer
 }
 
 type version struct {
@@ -62,3 +64,8 @@ func (v *version) ExternalEntities() ExternalEntityInformer {
 func (v *version) ExternalIPPools() ExternalIPPoolInformer {
 	return &externalIPPoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// IPPools returns a IPPoolInformer.
+func (v *version) IPPools() IPPoolInformer {
+	return &iPPoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -35,3 +35,7 @@ type ExternalEntityNamespaceListerExpansion interface{}
 // ExternalIPPoolListerExpansion allows custom methods to be added to
 // ExternalIPPoolLister.
 type ExternalIPPoolListerExpansion interface{}
+
+// IPPoolListerExpansion allows custom methods to be added to
+// IPPoolLister.
+type IPPoolListerExpansion interface{}
@@ -39,9 +39,9 @@ import (
 	clientset "antrea.io/antrea/pkg/client/clientset/versioned"
 	egressinformers "antrea.io/antrea/pkg/client/informers/externalversions/crd/v1alpha2"
 	egresslisters "antrea.io/antrea/pkg/client/listers/crd/v1alpha2"
-	"antrea.io/antrea/pkg/controller/egress/ipallocator"
 	"antrea.io/antrea/pkg/controller/grouping"
 	antreatypes "antrea.io/antrea/pkg/controller/types"
+	"antrea.io/antrea/pkg/ipam/ipallocator"
 )
 
 const (
@@ -69,6 +69,12 @@ type ipAllocation struct {
 	ipPool string
 }
 
+// ipsAllocation contains the IPs of an Egress with multiple Egress IPs and the IP Pool which allocates them.
+type ipsAllocation struct {
+	ips    []net.IP
+	ipPool string
+}
+
 // EgressController is responsible for synchronizing the EgressGroups selected by Egresses.
 type EgressController struct {
 	crdClient                  clientset.Interface
@@ -82,6 +88,9 @@ type EgressController struct {
 	// changed and to release the IP after the Egress is removed.
 	ipAllocationMap   map[string]*ipAllocation
 	ipAllocationMutex sync.RWMutex
+	// ipsAllocationMap is a map from Egress name to ipsAllocation, which is the counterpart of ipAllocationMap for the
+	// Egresses with multiple Egress IPs. It's protected by ipAllocationMutex too.
+	ipsAllocationMap map[string]*ipsAllocation
 
 	egressInformer egressinformers.EgressInformer
 	egressLister   egresslisters.EgressLister
@@ -118,6 +127,7 @@ func NewEgressController(crdClient clientset.Interface,
 		groupingInterfaceSynced:    groupingInterface.HasSynced,
 		ipAllocatorMap:             map[string]ipallocator.MultiIPAllocator{},
 		ipAllocationMap:            map[string]*ipAllocation{},
+		ipsAllocationMap:           map[string]*ipsAllocation{},
 	}
 	// Add handlers for Group events and Egress events.
 	c.groupingInterface.AddEventHandler(egressGroupType, c.enqueueEgressGroup)
@@ -182,6 +192,10 @@ func (c *EgressController) Run(stopCh <-chan struct{}) {
 // updateIPAllocation sets the EgressIP of an Egress as allocated in the specified ExternalIPPool and records the
 // allocation in ipAllocationMap.
 func (c *EgressController) updateIPAllocation(egress *egressv1alpha2.Egress) {
+	if len(egress.Spec.EgressIPs) > 0 {
+		c.updateIPsAllocation(egress)
+		return
+	}
 	// Ignore Egress that is not associated to ExternalIPPool or doesn't have EgressIP assigned.
 	if egress.Spec.ExternalIPPool == "" || egress.Spec.EgressIP == "" {
 		return
@@ -202,6 +216,32 @@ func (c *EgressController) updateIPAllocation(egress *egressv1alpha2.Egress) {
 	klog.InfoS("Allocated EgressIP", "egress", egress.Name, "ip", egress.Spec.EgressIP, "pool", egress.Spec.ExternalIPPool)
 }
 
+// updateIPsAllocation sets the EgressIPs of an Egress as allocated in the specified ExternalIPPool and records the
+// allocation in ipsAllocationMap.
+func (c *EgressController) updateIPsAllocation(egress *egressv1alpha2.Egress) {
+	// Ignore Egress that is not associated to ExternalIPPool.
+	if egress.Spec.ExternalIPPool == "" {
+		return
+	}
+	ipAllocator, exists := c.getIPAllocator(egress.Spec.ExternalIPPool)
+	if !exists {
+		klog.ErrorS(externalIPPoolNotFound, "Failed to allocate EgressIPs", "egress", egress.Name, "ips", egress.Spec.EgressIPs, "pool", egress.Spec.ExternalIPPool)
+		return
+	}
+	var ips []net.IP
+	for _, ipStr := range egress.Spec.EgressIPs {
+		ip := net.ParseIP(ipStr)
+		if err := ipAllocator.AllocateIP(ip); err != nil {
+			klog.ErrorS(err, "Failed to allocate EgressIP", "egress", egress.Name, "ip", ipStr, "pool", egress.Spec.ExternalIPPool)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	// Record the valid IP allocations.
+	c.setIPsAllocation(egress.Name, ips, egress.Spec.ExternalIPPool)
+	klog.InfoS("Allocated EgressIPs", "egress", egress.Name, "ips", ips, "pool", egress.Spec.ExternalIPPool)
+}
+
 // createOrUpdateIPAllocator creates or updates the IP allocator based on the provided ExternalIPPool.
 // Currently it's assumed that only new ranges will be added and existing ranges should not be deleted.
 // TODO: Use validation webhook to ensure it.
@@ -311,6 +351,31 @@ func (c *EgressController) setIPAllocation(egressName string, ip net.IP, poolNam
 	}
 }
 
+func (c *EgressController) getIPsAllocation(egressName string) ([]net.IP, string, bool) {
+	c.ipAllocationMutex.RLock()
+	defer c.ipAllocationMutex.RUnlock()
+	allocation, exists := c.ipsAllocationMap[egressName]
+	if !exists {
+		return nil, "", false
+	}
+	return allocation.ips, allocation.ipPool, true
+}
+
+func (c *EgressController) deleteIPsAllocation(egressName string) {
+	c.ipAllocationMutex.Lock()
+	defer c.ipAllocationMutex.Unlock()
+	delete(c.ipsAllocationMap, egressName)
+}
+
+func (c *EgressController) setIPsAllocation(egressName string, ips []net.IP, poolName string) {
+	c.ipAllocationMutex.Lock()
+	defer c.ipAllocationMutex.Unlock()
+	c.ipsAllocationMap[egressName] = &ipsAllocation{
+		ips:    ips,
+		ipPool: poolName,
+	}
+}
+
 // syncEgressIP is responsible for releasing stale EgressIP and allocating new EgressIP for an Egress if applicable.
 func (c *EgressController) syncEgressIP(egress *egressv1alpha2.Egress) (net.IP, error) {
 	prevIP, prevIPPool, exists := c.getIPAllocation(egress.Name)
@@ -365,6 +430,136 @@ func (c *EgressController) syncEgressIP(egress *egressv1alpha2.Egress) (net.IP,
 	return ip, nil
 }
 
+// syncEgressIPs is the counterpart of syncEgressIP for the Egresses with multiple Egress IPs. It releases the IPs
+// removed from the Egress, allocates the IPs added to it, and allocates new IPs from the ExternalIPPool until the
+// Egress has EgressIPCount IPs.
+func (c *EgressController) syncEgressIPs(egress *egressv1alpha2.Egress) ([]net.IP, error) {
+	prevIPs, prevIPPool, exists := c.getIPsAllocation(egress.Name)
+	if exists {
+		_, ipAllocatorExists := c.getIPAllocator(prevIPPool)
+		// The ExternalIPPool changes, release all the previous IPs first.
+		if prevIPPool != egress.Spec.ExternalIPPool || !ipAllocatorExists {
+			c.releaseEgressIPs(egress.Name, prevIPs, prevIPPool)
+			prevIPs = nil
+		}
+	}
+
+	// Skip allocating EgressIPs if ExternalIPPool is not specified and return whatever user specifies.
+	if egress.Spec.ExternalIPPool == "" {
+		var ips []net.IP
+		for _, ipStr := range egress.Spec.EgressIPs {
+			ips = append(ips, net.ParseIP(ipStr))
+		}
+		return ips, nil
+	}
+
+	ipAllocator, exists := c.getIPAllocator(egress.Spec.ExternalIPPool)
+	if !exists {
+		// The IP pool has been deleted, reclaim the IPs from the Egress API.
+		if len(egress.Spec.EgressIPs) > 0 {
+			if err := c.updateEgressIPs(egress, nil); err != nil {
+				return nil, err
+			}
+		}
+		return nil, externalIPPoolNotFound
+	}
+
+	prevIPSet := map[string]net.IP{}
+	for _, ip := range prevIPs {
+		prevIPSet[ip.String()] = ip
+	}
+	var ips []net.IP
+	var allocateErr error
+	// User or a previous sync specifies the Egress IPs, try to allocate the new ones. If it fails, the datapath may
+	// still work, we just don't track the IP allocation so deleting this Egress won't release the IP to the Pool.
+	for _, ipStr := range egress.Spec.EgressIPs {
+		ip := net.ParseIP(ipStr)
+		if _, allocated := prevIPSet[ip.String()]; allocated {
+			delete(prevIPSet, ip.String())
+			ips = append(ips, ip)
+			continue
+		}
+		if err := ipAllocator.AllocateIP(ip); err != nil {
+			allocateErr = fmt.Errorf("error when allocating IP %v for Egress %s from ExternalIPPool %s: %v", ip, egress.Name, egress.Spec.ExternalIPPool, err)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	// Release the IPs removed from the Egress.
+	for _, ip := range prevIPSet {
+		if err := ipAllocator.Release(ip); err != nil {
+			klog.ErrorS(err, "Failed to release EgressIP", "egress", egress.Name, "ip", ip, "pool", egress.Spec.ExternalIPPool)
+		}
+	}
+	c.setIPsAllocation(egress.Name, ips, egress.Spec.ExternalIPPool)
+	if allocateErr != nil {
+		return nil, allocateErr
+	}
+
+	missing := int(egress.Spec.EgressIPCount) - len(egress.Spec.EgressIPs)
+	if missing <= 0 {
+		return ips, nil
+	}
+	// User doesn't specify enough Egress IPs, allocate the missing ones.
+	var newIPs []net.IP
+	releaseNewIPs := func() {
+		for _, ip := range newIPs {
+			ipAllocator.Release(ip)
+		}
+	}
+	for i := 0; i < missing; i++ {
+		ip, err := ipAllocator.AllocateNext()
+		if err != nil {
+			releaseNewIPs()
+			return nil, err
+		}
+		newIPs = append(newIPs, ip)
+	}
+	egressIPs := append([]string{}, egress.Spec.EgressIPs...)
+	for _, ip := range newIPs {
+		egressIPs = append(egressIPs, ip.String())
+	}
+	if err := c.updateEgressIPs(egress, egressIPs); err != nil {
+		releaseNewIPs()
+		return nil, err
+	}
+	ips = append(ips, newIPs...)
+	c.setIPsAllocation(egress.Name, ips, egress.Spec.ExternalIPPool)
+	klog.InfoS("Allocated EgressIPs", "egress", egress.Name, "ips", newIPs, "pool", egress.Spec.ExternalIPPool)
+	return ips, nil
+}
+
+// updateEgressIPs updates the Egress's EgressIPs in Kubernetes API.
+func (c *EgressController) updateEgressIPs(egress *egressv1alpha2.Egress, ips []string) error {
+	patch := map[string]interface{}{
+		"spec": map[string][]string{
+			"egressIPs": ips,
+		},
+	}
+	patchBytes, _ := json.Marshal(patch)
+	if _, err := c.crdClient.CrdV1alpha2().Egresses().Patch(context.TODO(), egress.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("error when updating EgressIPs for Egress %s: %v", egress.Name, err)
+	}
+	return nil
+}
+
+// releaseEgressIPs removes the Egress's ipsAllocation in the cache and releases the IPs to the pool.
+func (c *EgressController) releaseEgressIPs(egressName string, egressIPs []net.IP, poolName string) {
+	c.deleteIPsAllocation(egressName)
+	allocator, exists := c.getIPAllocator(poolName)
+	if !exists {
+		klog.ErrorS(externalIPPoolNotFound, "Failed to release EgressIPs", "egress", egressName, "ips", egressIPs, "pool", poolName)
+		return
+	}
+	for _, ip := range egressIPs {
+		if err := allocator.Release(ip); err != nil {
+			klog.ErrorS(err, "Failed to release EgressIP", "egress", egressName, "ip", ip, "pool", poolName)
+			continue
+		}
+		klog.InfoS("Released EgressIP", "egress", egressName, "ip", ip, "pool", poolName)
+	}
+}
+
 // updateEgressIP updates the Egress's EgressIP in Kubernetes API.
 func (c *EgressController) updateEgressIP(egress *egressv1alpha2.Egress, ip string) error {
 	var egressIPPtr *string
@@ -407,15 +602,32 @@ func (c *EgressController) syncEgress(key string) error {
 
 	egress, err := c.egressLister.Get(key)
 	if err != nil {
-		// The Egress has been deleted, release its EgressIP if there was one.
+		// The Egress has been deleted, release its EgressIPs if there were.
 		if prevIP, prevIPPool, exists := c.getIPAllocation(key); exists {
 			c.releaseEgressIP(key, prevIP, prevIPPool)
 		}
+		if prevIPs, prevIPPool, exists := c.getIPsAllocation(key); exists {
+			c.releaseEgressIPs(key, prevIPs, prevIPPool)
+		}
 		return nil
 	}
 
-	if _, err := c.syncEgressIP(egress); err != nil {
-		return err
+	// An Egress uses either a single EgressIP or multiple EgressIPs. When it switches from one to the other, the
+	// allocation of the previous kind is released.
+	if len(egress.Spec.EgressIPs) > 0 || egress.Spec.EgressIPCount > 0 {
+		if prevIP, prevIPPool, exists := c.getIPAllocation(key); exists {
+			c.releaseEgressIP(key, prevIP, prevIPPool)
+		}
+		if _, err := c.syncEgressIPs(egress); err != nil {
+			return err
+		}
+	} else {
+		if prevIPs, prevIPPool, exists := c.getIPsAllocation(key); exists {
+			c.releaseEgressIPs(key, prevIPs, prevIPPool)
+		}
+		if _, err := c.syncEgressIP(egress); err != nil {
+			return err
+		}
 	}
 
 	egressGroupObj, found, _ := c.egressGroupStore.Get(key)
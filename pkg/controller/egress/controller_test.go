@@ -683,6 +683,67 @@ func TestSyncEgressIP(t *testing.T) {
 	}
 }
 
+func TestSyncEgressIPs(t *testing.T) {
+	egress := &v1alpha2.Egress{
+		ObjectMeta: metav1.ObjectMeta{Name: "egressA", UID: "uidA"},
+		Spec: v1alpha2.EgressSpec{
+			EgressIPs:      []string{"1.1.1.5"},
+			EgressIPCount:  3,
+			ExternalIPPool: "ipPoolA",
+		},
+	}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	controller := newController(nil, []runtime.Object{egress})
+	controller.crdInformerFactory.Start(stopCh)
+	controller.crdInformerFactory.WaitForCacheSync(stopCh)
+	controller.createOrUpdateIPAllocator(newExternalIPPool("ipPoolA", "1.1.1.0/24", "", ""))
+
+	getEgressIPs := func() []string {
+		egress, err := controller.crdClient.CrdV1alpha2().Egresses().Get(context.TODO(), egress.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return egress.Spec.EgressIPs
+	}
+
+	// The specified IP is allocated and the missing ones are allocated from the pool.
+	ips, err := controller.syncEgressIPs(egress)
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("1.1.1.5"), net.ParseIP("1.1.1.1"), net.ParseIP("1.1.1.2")}, ips)
+	assert.Equal(t, []string{"1.1.1.5", "1.1.1.1", "1.1.1.2"}, getEgressIPs())
+	checkExternalIPPoolUsed(t, controller, "ipPoolA", 3)
+
+	// Syncing the updated Egress doesn't allocate more IPs.
+	updatedEgress := egress.DeepCopy()
+	updatedEgress.Spec.EgressIPs = getEgressIPs()
+	ips, err = controller.syncEgressIPs(updatedEgress)
+	require.NoError(t, err)
+	assert.Len(t, ips, 3)
+	checkExternalIPPoolUsed(t, controller, "ipPoolA", 3)
+
+	// The IPs removed from the Egress are released.
+	updatedEgress.Spec.EgressIPs = []string{"1.1.1.1"}
+	updatedEgress.Spec.EgressIPCount = 1
+	ips, err = controller.syncEgressIPs(updatedEgress)
+	require.NoError(t, err)
+	assert.Equal(t, []net.IP{net.ParseIP("1.1.1.1")}, ips)
+	checkExternalIPPoolUsed(t, controller, "ipPoolA", 1)
+
+	// Switching to a single EgressIP releases the EgressIPs.
+	updatedEgress.Spec.EgressIPs = nil
+	updatedEgress.Spec.EgressIPCount = 0
+	updatedEgress.Spec.EgressIP = "1.1.1.10"
+	_, err = controller.crdClient.CrdV1alpha2().Egresses().Update(context.TODO(), updatedEgress, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.NoError(t, wait.Poll(time.Millisecond*100, time.Second, func() (bool, error) {
+		egress, _ := controller.egressLister.Get(egress.Name)
+		return egress.Spec.EgressIP == "1.1.1.10", nil
+	}))
+	require.NoError(t, controller.syncEgress(egress.Name))
+	_, _, exists := controller.getIPsAllocation(egress.Name)
+	assert.False(t, exists)
+	checkExternalIPPoolUsed(t, controller, "ipPoolA", 1)
+}
+
 func checkExternalIPPoolUsed(t *testing.T, controller *egressController, poolName string, used int) {
 	ipAllocator, exists := controller.getIPAllocator(poolName)
 	require.True(t, exists)
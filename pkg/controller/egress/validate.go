@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 
 	crdv1alpha2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+	"antrea.io/antrea/pkg/util/bandwidth"
 )
 
 func (c *EgressController) ValidateExternalIPPool(review *admv1.AdmissionReview) *admv1.AdmissionResponse {
@@ -100,6 +101,24 @@ func (c *EgressController) ValidateEgress(review *admv1.AdmissionReview) *admv1.
 	}
 
 	shouldAllow := func(oldEgress, newEgress *crdv1alpha2.Egress) (bool, string) {
+		if newEgress.Spec.Bandwidth != nil {
+			if _, _, err := bandwidth.Parse(newEgress.Spec.Bandwidth); err != nil {
+				return false, fmt.Sprintf("Bandwidth is not valid: %v", err)
+			}
+		}
+		for _, peer := range newEgress.Spec.To {
+			if (peer.IPBlock == nil) == (peer.Group == "") {
+				return false, "Exactly one of ipBlock and group must be set in a destination"
+			}
+			if peer.IPBlock != nil {
+				if _, _, err := net.ParseCIDR(peer.IPBlock.CIDR); err != nil {
+					return false, fmt.Sprintf("CIDR %s is not valid", peer.IPBlock.CIDR)
+				}
+			}
+		}
+		if len(newEgress.Spec.EgressIPs) > 0 || newEgress.Spec.EgressIPCount > 0 {
+			return c.validateEgressIPs(oldEgress, newEgress)
+		}
 		// Allow it if EgressIP and ExternalIPPool don't change.
 		if newEgress.Spec.EgressIP == oldEgress.Spec.EgressIP && newEgress.Spec.ExternalIPPool == oldEgress.Spec.ExternalIPPool {
 			return true, ""
@@ -147,6 +166,42 @@ func (c *EgressController) ValidateEgress(review *admv1.AdmissionReview) *admv1.
 	}
 }
 
+// validateEgressIPs validates the EgressIPs and the EgressIPCount of an Egress with multiple Egress IPs.
+func (c *EgressController) validateEgressIPs(oldEgress, newEgress *crdv1alpha2.Egress) (bool, string) {
+	if newEgress.Spec.EgressIP != "" {
+		return false, "EgressIP and EgressIPs cannot be set at the same time"
+	}
+	if newEgress.Spec.EgressIPCount > 0 && newEgress.Spec.ExternalIPPool == "" {
+		return false, "EgressIPCount requires ExternalIPPool to be set"
+	}
+	ipSet := sets.NewString()
+	for _, ipStr := range newEgress.Spec.EgressIPs {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return false, fmt.Sprintf("IP %s is not valid", ipStr)
+		}
+		if ipSet.Has(ip.String()) {
+			return false, fmt.Sprintf("IP %s is duplicated", ipStr)
+		}
+		ipSet.Insert(ip.String())
+	}
+	// Only validate whether the specified Egress IPs are in the Pool when they change.
+	if newEgress.Spec.ExternalIPPool == "" || (newEgress.Spec.ExternalIPPool == oldEgress.Spec.ExternalIPPool && ipSet.Equal(sets.NewString(oldEgress.Spec.EgressIPs...))) {
+		return true, ""
+	}
+	ipAllocator, exists := c.getIPAllocator(newEgress.Spec.ExternalIPPool)
+	// The ExternalIPPool doesn't exist, cannot determine whether the IPs are in the pool.
+	if !exists {
+		return false, fmt.Sprintf("ExternalIPPool %s does not exist", newEgress.Spec.ExternalIPPool)
+	}
+	for _, ipStr := range newEgress.Spec.EgressIPs {
+		if !ipAllocator.Has(net.ParseIP(ipStr)) {
+			return false, fmt.Sprintf("IP %s is not within the IP range", ipStr)
+		}
+	}
+	return true, ""
+}
+
 func getIPRangeSet(ipRanges []crdv1alpha2.IPRange) sets.String {
 	set := sets.NewString()
 	for _, ipRange := range ipRanges {
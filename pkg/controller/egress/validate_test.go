@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
 	crdv1alpha2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
 )
 
@@ -180,6 +181,159 @@ func TestEgressControllerValidateEgress(t *testing.T) {
 			},
 			expectedResponse: &admv1.AdmissionResponse{Allowed: true},
 		},
+		{
+			name: "Setting valid bandwidth should be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "UPDATE",
+				OldObject: runtime.RawExtension{Raw: marshal(newEgress("foo", "10.10.10.1", "", nil, nil))},
+				Object:    runtime.RawExtension{Raw: marshal(newEgressWithBandwidth("foo", "10.10.10.1", &crdv1alpha2.Bandwidth{Rate: "100M", Burst: "10M"}))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{Allowed: true},
+		},
+		{
+			name: "Setting invalid bandwidth rate should not be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "CREATE",
+				Object:    runtime.RawExtension{Raw: marshal(newEgressWithBandwidth("foo", "10.10.10.1", &crdv1alpha2.Bandwidth{Rate: "0"}))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: `Bandwidth is not valid: invalid rate "0": must be at least 1k`,
+				},
+			},
+		},
+		{
+			name: "Setting invalid bandwidth burst should not be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "UPDATE",
+				OldObject: runtime.RawExtension{Raw: marshal(newEgress("foo", "10.10.10.1", "", nil, nil))},
+				Object:    runtime.RawExtension{Raw: marshal(newEgressWithBandwidth("foo", "10.10.10.1", &crdv1alpha2.Bandwidth{Rate: "100M", Burst: "foo"}))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: `Bandwidth is not valid: invalid burst "foo": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`,
+				},
+			},
+		},
+		{
+			name: "Setting valid destinations should be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "CREATE",
+				Object: runtime.RawExtension{Raw: marshal(newEgressWithDestinations("foo", "10.10.10.1",
+					crdv1alpha2.EgressPeer{IPBlock: &crdv1alpha1.IPBlock{CIDR: "192.168.0.0/16"}},
+					crdv1alpha2.EgressPeer{Group: "bar"}))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{Allowed: true},
+		},
+		{
+			name: "Setting destination with both ipBlock and group should not be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "CREATE",
+				Object: runtime.RawExtension{Raw: marshal(newEgressWithDestinations("foo", "10.10.10.1",
+					crdv1alpha2.EgressPeer{IPBlock: &crdv1alpha1.IPBlock{CIDR: "192.168.0.0/16"}, Group: "bar"}))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: "Exactly one of ipBlock and group must be set in a destination",
+				},
+			},
+		},
+		{
+			name: "Setting destination with invalid CIDR should not be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "UPDATE",
+				OldObject: runtime.RawExtension{Raw: marshal(newEgress("foo", "10.10.10.1", "", nil, nil))},
+				Object: runtime.RawExtension{Raw: marshal(newEgressWithDestinations("foo", "10.10.10.1",
+					crdv1alpha2.EgressPeer{IPBlock: &crdv1alpha1.IPBlock{CIDR: "192.168.0.0"}}))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: "CIDR 192.168.0.0 is not valid",
+				},
+			},
+		},
+		{
+			name:                   "Requesting multiple normal IPs should be allowed",
+			existingExternalIPPool: newExternalIPPool("bar", "10.10.10.0/24", "", ""),
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "CREATE",
+				Object:    runtime.RawExtension{Raw: marshal(newEgressWithIPs("foo", "bar", 3, "10.10.10.1", "10.10.10.2"))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{Allowed: true},
+		},
+		{
+			name:                   "Requesting multiple IPs with one out of range should not be allowed",
+			existingExternalIPPool: newExternalIPPool("bar", "10.10.10.0/24", "", ""),
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "UPDATE",
+				OldObject: runtime.RawExtension{Raw: marshal(newEgressWithIPs("foo", "bar", 0, "10.10.10.1"))},
+				Object:    runtime.RawExtension{Raw: marshal(newEgressWithIPs("foo", "bar", 0, "10.10.10.1", "10.10.11.1"))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: "IP 10.10.11.1 is not within the IP range",
+				},
+			},
+		},
+		{
+			name: "Requesting duplicate IPs should not be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "CREATE",
+				Object:    runtime.RawExtension{Raw: marshal(newEgressWithIPs("foo", "", 0, "10.10.10.1", "10.10.10.1"))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: "IP 10.10.10.1 is duplicated",
+				},
+			},
+		},
+		{
+			name: "Requesting IP count without ExternalIPPool should not be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "CREATE",
+				Object:    runtime.RawExtension{Raw: marshal(newEgressWithIPs("foo", "", 2))},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: "EgressIPCount requires ExternalIPPool to be set",
+				},
+			},
+		},
+		{
+			name: "Setting both EgressIP and EgressIPs should not be allowed",
+			request: &admv1.AdmissionRequest{
+				Name:      "foo",
+				Operation: "CREATE",
+				Object: runtime.RawExtension{Raw: marshal(func() *crdv1alpha2.Egress {
+					egress := newEgressWithIPs("foo", "", 0, "10.10.10.1")
+					egress.Spec.EgressIP = "10.10.10.2"
+					return egress
+				}())},
+			},
+			expectedResponse: &admv1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Message: "EgressIP and EgressIPs cannot be set at the same time",
+				},
+			},
+		},
 		{
 			name: "DELETE operation should be allowed",
 			request: &admv1.AdmissionRequest{
@@ -204,3 +358,22 @@ func TestEgressControllerValidateEgress(t *testing.T) {
 		})
 	}
 }
+
+func newEgressWithBandwidth(name, egressIP string, bandwidth *crdv1alpha2.Bandwidth) *crdv1alpha2.Egress {
+	egress := newEgress(name, egressIP, "", nil, nil)
+	egress.Spec.Bandwidth = bandwidth
+	return egress
+}
+
+func newEgressWithDestinations(name, egressIP string, destinations ...crdv1alpha2.EgressPeer) *crdv1alpha2.Egress {
+	egress := newEgress(name, egressIP, "", nil, nil)
+	egress.Spec.To = destinations
+	return egress
+}
+
+func newEgressWithIPs(name, externalIPPool string, count int32, egressIPs ...string) *crdv1alpha2.Egress {
+	egress := newEgress(name, "", externalIPPool, nil, nil)
+	egress.Spec.EgressIPs = egressIPs
+	egress.Spec.EgressIPCount = count
+	return egress
+}
@@ -32,6 +32,11 @@ func (n *NetworkPolicyController) addANP(obj interface{}) {
 	klog.Infof("Processing Antrea NetworkPolicy %s/%s ADD event", np.Namespace, np.Name)
 	// Create an internal NetworkPolicy object corresponding to this
 	// NetworkPolicy and enqueue task to internal NetworkPolicy Workqueue.
+	n.enqueuePolicySchedule(*getANPReference(np), np.Spec.ActivationWindows)
+	if !isPolicyActive(np.Spec.ActivationWindows) {
+		klog.Infof("%s is inactive according to its activation windows", getANPReference(np).ToString())
+		return
+	}
 	internalNP := n.processAntreaNetworkPolicy(np)
 	klog.V(2).Infof("Creating new internal NetworkPolicy %s for %s", internalNP.Name, internalNP.SourceRef.ToString())
 	n.internalNetworkPolicyStore.Create(internalNP)
@@ -47,19 +52,33 @@ func (n *NetworkPolicyController) updateANP(old, cur interface{}) {
 	klog.Infof("Processing Antrea NetworkPolicy %s/%s UPDATE event", curNP.Namespace, curNP.Name)
 	// Update an internal NetworkPolicy, corresponding to this NetworkPolicy and
 	// enqueue task to internal NetworkPolicy Workqueue.
-	curInternalNP := n.processAntreaNetworkPolicy(curNP)
-	klog.V(2).Infof("Updating existing internal NetworkPolicy %s for %s", curInternalNP.Name, curInternalNP.SourceRef.ToString())
 	// Retrieve old crdv1alpha1.NetworkPolicy object.
 	oldNP := old.(*crdv1alpha1.NetworkPolicy)
 	// Old and current NetworkPolicy share the same key.
 	key := internalNetworkPolicyKeyFunc(oldNP)
+	n.enqueuePolicySchedule(*getANPReference(curNP), curNP.Spec.ActivationWindows)
+	if !isPolicyActive(curNP.Spec.ActivationWindows) {
+		// Remove the internal NetworkPolicy if the policy was active.
+		n.deleteInternalNetworkPolicy(key)
+		return
+	}
+	curInternalNP := n.processAntreaNetworkPolicy(curNP)
+	klog.V(2).Infof("Updating existing internal NetworkPolicy %s for %s", curInternalNP.Name, curInternalNP.SourceRef.ToString())
 	// Lock access to internal NetworkPolicy store such that concurrent access
 	// to an internal NetworkPolicy is not allowed. This will avoid the
 	// case in which an Update to an internal NetworkPolicy object may
 	// cause the SpanMeta member to be overridden with stale SpanMeta members
 	// from an older internal NetworkPolicy.
 	n.internalNetworkPolicyMutex.Lock()
-	oldInternalNPObj, _, _ := n.internalNetworkPolicyStore.Get(key)
+	oldInternalNPObj, exists, _ := n.internalNetworkPolicyStore.Get(key)
+	if !exists {
+		// The policy was inactive, create the internal NetworkPolicy.
+		klog.V(2).Infof("Creating new internal NetworkPolicy %s for %s", curInternalNP.Name, curInternalNP.SourceRef.ToString())
+		n.internalNetworkPolicyStore.Create(curInternalNP)
+		n.internalNetworkPolicyMutex.Unlock()
+		n.enqueueInternalNetworkPolicy(key)
+		return
+	}
 	oldInternalNP := oldInternalNPObj.(*antreatypes.NetworkPolicy)
 	// Must preserve old internal NetworkPolicy Span.
 	curInternalNP.SpanMeta = oldInternalNP.SpanMeta
@@ -103,18 +122,8 @@ func (n *NetworkPolicyController) deleteANP(old interface{}) {
 	defer n.heartbeat("deleteANP")
 	klog.Infof("Processing Antrea NetworkPolicy %s/%s DELETE event", np.Namespace, np.Name)
 	key := internalNetworkPolicyKeyFunc(np)
-	oldInternalNPObj, _, _ := n.internalNetworkPolicyStore.Get(key)
-	oldInternalNP := oldInternalNPObj.(*antreatypes.NetworkPolicy)
-	klog.V(2).Infof("Deleting internal NetworkPolicy %s for %s", oldInternalNP.Name, oldInternalNP.SourceRef.ToString())
-	err := n.internalNetworkPolicyStore.Delete(key)
-	if err != nil {
-		klog.Errorf("Error deleting internal NetworkPolicy during Antrea NetworkPolicy %s delete: %v", np.Name, err)
-		return
-	}
-	for _, atg := range oldInternalNP.AppliedToGroups {
-		n.deleteDereferencedAppliedToGroup(atg)
-	}
-	n.deleteDereferencedAddressGroups(oldInternalNP)
+	// The internal NetworkPolicy doesn't exist if the policy is inactive.
+	n.deleteInternalNetworkPolicy(key)
 }
 
 // processAntreaNetworkPolicy creates an internal NetworkPolicy instance
@@ -138,7 +147,7 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *crdv1alpha1.Net
 	// Compute NetworkPolicyRule for Ingress Rule.
 	for idx, ingressRule := range np.Spec.Ingress {
 		// Set default action to ALLOW to allow traffic.
-		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports)
+		services, namedPortExists := toAntreaServicesForCRD(ingressRule.Ports, ingressRule.Protocols)
 		var appliedToGroupNamesForRule []string
 		// Create AppliedToGroup for each AppliedTo present in the ingress rule.
 		for _, at := range ingressRule.AppliedTo {
@@ -155,12 +164,14 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *crdv1alpha1.Net
 			Priority:        int32(idx),
 			EnableLogging:   ingressRule.EnableLogging,
 			AppliedToGroups: appliedToGroupNamesForRule,
+			L7Protocols:     toAntreaL7ProtocolsForCRD(ingressRule.L7Protocols),
+			EnforcementMode: ingressRule.EnforcementMode,
 		})
 	}
 	// Compute NetworkPolicyRule for Egress Rule.
 	for idx, egressRule := range np.Spec.Egress {
 		// Set default action to ALLOW to allow traffic.
-		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports)
+		services, namedPortExists := toAntreaServicesForCRD(egressRule.Ports, egressRule.Protocols)
 		var appliedToGroupNamesForRule []string
 		// Create AppliedToGroup for each AppliedTo present in the ingress rule.
 		for _, at := range egressRule.AppliedTo {
@@ -177,16 +188,13 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *crdv1alpha1.Net
 			Priority:        int32(idx),
 			EnableLogging:   egressRule.EnableLogging,
 			AppliedToGroups: appliedToGroupNamesForRule,
+			L7Protocols:     toAntreaL7ProtocolsForCRD(egressRule.L7Protocols),
+			EnforcementMode: egressRule.EnforcementMode,
 		})
 	}
 	tierPriority := n.getTierPriority(np.Spec.Tier)
 	internalNetworkPolicy := &antreatypes.NetworkPolicy{
-		SourceRef: &controlplane.NetworkPolicyReference{
-			Type:      controlplane.AntreaNetworkPolicy,
-			Namespace: np.Namespace,
-			Name:      np.Name,
-			UID:       np.UID,
-		},
+		SourceRef:        getANPReference(np),
 		Name:             internalNetworkPolicyKeyFunc(np),
 		UID:              np.UID,
 		Generation:       np.Generation,
@@ -198,3 +206,13 @@ func (n *NetworkPolicyController) processAntreaNetworkPolicy(np *crdv1alpha1.Net
 	}
 	return internalNetworkPolicy
 }
+
+// getANPReference returns the NetworkPolicyReference of an Antrea NetworkPolicy.
+func getANPReference(np *crdv1alpha1.NetworkPolicy) *controlplane.NetworkPolicyReference {
+	return &controlplane.NetworkPolicyReference{
+		Type:      controlplane.AntreaNetworkPolicy,
+		Namespace: np.Namespace,
+		Name:      np.Name,
+		UID:       np.UID,
+	}
+}
@@ -266,10 +266,15 @@ func (n *NetworkPolicyController) triggerCNPUpdates(cg *crdv1alpha3.ClusterGroup
 	}
 	for _, obj := range cnps {
 		cnp := obj.(*crdv1alpha1.ClusterNetworkPolicy)
+		key := internalNetworkPolicyKeyFunc(cnp)
+		// Skip ClusterNetworkPolicies which are inactive according to their
+		// activation windows.
+		if _, exists, _ := n.internalNetworkPolicyStore.Get(key); !exists {
+			continue
+		}
 		// Re-process ClusterNetworkPolicies which may be affected due to updates to CG.
 		curInternalNP := n.processClusterNetworkPolicy(cnp)
 		klog.V(2).Infof("Updating existing internal NetworkPolicy %s for %s", curInternalNP.Name, curInternalNP.SourceRef.ToString())
-		key := internalNetworkPolicyKeyFunc(cnp)
 		// Lock access to internal NetworkPolicy store such that concurrent access
 		// to an internal NetworkPolicy is not allowed. This will avoid the
 		// case in which an Update to an internal NetworkPolicy object may
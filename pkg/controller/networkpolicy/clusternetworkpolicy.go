@@ -15,6 +15,8 @@
 package networkpolicy
 
 import (
+	"reflect"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -37,6 +39,11 @@ func (n *NetworkPolicyController) addCNP(obj interface{}) {
 	klog.Infof("Processing ClusterNetworkPolicy %s ADD event", cnp.Name)
 	// Create an internal NetworkPolicy object corresponding to this
 	// ClusterNetworkPolicy and enqueue task to internal NetworkPolicy Workqueue.
+	n.enqueuePolicySchedule(*getCNPReference(cnp), cnp.Spec.ActivationWindows)
+	if !isPolicyActive(cnp.Spec.ActivationWindows) {
+		klog.Infof("%s is inactive according to its activation windows", getCNPReference(cnp).ToString())
+		return
+	}
 	internalNP := n.processClusterNetworkPolicy(cnp)
 	klog.V(2).Infof("Creating new internal NetworkPolicy %s for %s", internalNP.Name, internalNP.SourceRef.ToString())
 	n.internalNetworkPolicyStore.Create(internalNP)
@@ -52,19 +59,33 @@ func (n *NetworkPolicyController) updateCNP(old, cur interface{}) {
 	klog.Infof("Processing ClusterNetworkPolicy %s UPDATE event", curCNP.Name)
 	// Update an internal NetworkPolicy, corresponding to this NetworkPolicy and
 	// enqueue task to internal NetworkPolicy Workqueue.
-	curInternalNP := n.processClusterNetworkPolicy(curCNP)
-	klog.V(2).Infof("Updating existing internal NetworkPolicy %s for %s", curInternalNP.Name, curInternalNP.SourceRef.ToString())
 	// Retrieve old crdv1alpha1.NetworkPolicy object.
 	oldCNP := old.(*crdv1alpha1.ClusterNetworkPolicy)
 	// Old and current NetworkPolicy share the same key.
 	key := internalNetworkPolicyKeyFunc(oldCNP)
+	n.enqueuePolicySchedule(*getCNPReference(curCNP), curCNP.Spec.ActivationWindows)
+	if !isPolicyActive(curCNP.Spec.ActivationWindows) {
+		// Remove the internal NetworkPolicy if the policy was active.
+		n.deleteInternalNetworkPolicy(key)
+		return
+	}
+	curInternalNP := n.processClusterNetworkPolicy(curCNP)
+	klog.V(2).Infof("Updating existing internal NetworkPolicy %s for %s", curInternalNP.Name, curInternalNP.SourceRef.ToString())
 	// Lock access to internal NetworkPolicy store such that concurrent access
 	// to an internal NetworkPolicy is not allowed. This will avoid the
 	// case in which an Update to an internal NetworkPolicy object may
 	// cause the SpanMeta member to be overridden with stale SpanMeta members
 	// from an older internal NetworkPolicy.
 	n.internalNetworkPolicyMutex.Lock()
-	oldInternalNPObj, _, _ := n.internalNetworkPolicyStore.Get(key)
+	oldInternalNPObj, exists, _ := n.internalNetworkPolicyStore.Get(key)
+	if !exists {
+		// The policy was inactive, create the internal NetworkPolicy.
+		klog.V(2).Infof("Creating new internal NetworkPolicy %s for %s", curInternalNP.Name, curInternalNP.SourceRef.ToString())
+		n.internalNetworkPolicyStore.Create(curInternalNP)
+		n.internalNetworkPolicyMutex.Unlock()
+		n.enqueueInternalNetworkPolicy(key)
+		return
+	}
 	oldInternalNP := oldInternalNPObj.(*antreatypes.NetworkPolicy)
 	// Must preserve old internal NetworkPolicy Span.
 	curInternalNP.SpanMeta = oldInternalNP.SpanMeta
@@ -108,22 +129,8 @@ func (n *NetworkPolicyController) deleteCNP(old interface{}) {
 	defer n.heartbeat("deleteCNP")
 	klog.Infof("Processing ClusterNetworkPolicy %s DELETE event", cnp.Name)
 	key := internalNetworkPolicyKeyFunc(cnp)
-	// Lock access to internal NetworkPolicy store so that concurrent reprocessCNP
-	// calls will not re-process and add a CNP that has already been deleted.
-	n.internalNetworkPolicyMutex.Lock()
-	oldInternalNPObj, _, _ := n.internalNetworkPolicyStore.Get(key)
-	oldInternalNP := oldInternalNPObj.(*antreatypes.NetworkPolicy)
-	klog.V(2).Infof("Deleting internal NetworkPolicy %s for %s", oldInternalNP.Name, oldInternalNP.SourceRef.ToString())
-	err := n.internalNetworkPolicyStore.Delete(key)
-	n.internalNetworkPolicyMutex.Unlock()
-	if err != nil {
-		klog.Errorf("Error deleting internal NetworkPolicy during NetworkPolicy %s delete: %v", cnp.Name, err)
-		return
-	}
-	for _, atg := range oldInternalNP.AppliedToGroups {
-		n.deleteDereferencedAppliedToGroup(atg)
-	}
-	n.deleteDereferencedAddressGroups(oldInternalNP)
+	// The internal NetworkPolicy doesn't exist if the policy is inactive.
+	n.deleteInternalNetworkPolicy(key)
 }
 
 // reprocessCNP is triggered by Namespace ADD/UPDATE/DELETE events when they impact the
@@ -247,6 +254,70 @@ func (n *NetworkPolicyController) deleteNamespace(old interface{}) {
 	}
 }
 
+// filterAppliedToGroupsForNodeLabels returns the keys of all AppliedToGroups whose
+// NodeSelector matches the given Node labels.
+func (n *NetworkPolicyController) filterAppliedToGroupsForNodeLabels(nodeLabels labels.Set) sets.String {
+	groupKeys := sets.String{}
+	for _, obj := range n.appliedToGroupStore.List() {
+		atg := obj.(*antreatypes.AppliedToGroup)
+		if atg.Selector.NodeSelector != nil && atg.Selector.NodeSelector.Matches(nodeLabels) {
+			groupKeys.Insert(atg.Name)
+		}
+	}
+	return groupKeys
+}
+
+// addNode receives Node ADD events and enqueues all AppliedToGroups that select
+// this Node.
+func (n *NetworkPolicyController) addNode(obj interface{}) {
+	defer n.heartbeat("addNode")
+	node := obj.(*v1.Node)
+	klog.V(2).Infof("Processing Node %s ADD event, labels: %v", node.Name, node.Labels)
+	for key := range n.filterAppliedToGroupsForNodeLabels(labels.Set(node.Labels)) {
+		n.enqueueAppliedToGroup(key)
+	}
+}
+
+// updateNode receives Node UPDATE events and enqueues all AppliedToGroups that
+// select either the original or the new Node, if the labels or the addresses
+// of the Node have changed.
+func (n *NetworkPolicyController) updateNode(oldObj, curObj interface{}) {
+	defer n.heartbeat("updateNode")
+	oldNode, curNode := oldObj.(*v1.Node), curObj.(*v1.Node)
+	if reflect.DeepEqual(oldNode.Labels, curNode.Labels) && reflect.DeepEqual(oldNode.Status.Addresses, curNode.Status.Addresses) {
+		return
+	}
+	klog.V(2).Infof("Processing Node %s UPDATE event, labels: %v", curNode.Name, curNode.Labels)
+	groupKeys := n.filterAppliedToGroupsForNodeLabels(labels.Set(oldNode.Labels))
+	groupKeys = groupKeys.Union(n.filterAppliedToGroupsForNodeLabels(labels.Set(curNode.Labels)))
+	for key := range groupKeys {
+		n.enqueueAppliedToGroup(key)
+	}
+}
+
+// deleteNode receives Node DELETE events and enqueues all AppliedToGroups that
+// select this Node.
+func (n *NetworkPolicyController) deleteNode(old interface{}) {
+	node, ok := old.(*v1.Node)
+	if !ok {
+		tombstone, ok := old.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Error decoding object when deleting Node, invalid type: %v", old)
+			return
+		}
+		node, ok = tombstone.Obj.(*v1.Node)
+		if !ok {
+			klog.Errorf("Error decoding object tombstone when deleting Node, invalid type: %v", tombstone.Obj)
+			return
+		}
+	}
+	defer n.heartbeat("deleteNode")
+	klog.V(2).Infof("Processing Node %s DELETE event, labels: %v", node.Name, node.Labels)
+	for key := range n.filterAppliedToGroupsForNodeLabels(labels.Set(node.Labels)) {
+		n.enqueueAppliedToGroup(key)
+	}
+}
+
 // processClusterNetworkPolicy creates an internal NetworkPolicy instance
 // corresponding to the crdv1alpha1.ClusterNetworkPolicy object. This method
 // does not commit the internal NetworkPolicy in store, instead returns an
@@ -288,7 +359,8 @@ func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *crdv1alpha1.C
 	var rules []controlplane.NetworkPolicyRule
 	processRules := func(cnpRules []crdv1alpha1.Rule, direction controlplane.Direction) {
 		for idx, cnpRule := range cnpRules {
-			services, namedPortExists := toAntreaServicesForCRD(cnpRule.Ports)
+			services, namedPortExists := toAntreaServicesForCRD(cnpRule.Ports, cnpRule.Protocols)
+			l7Protocols := toAntreaL7ProtocolsForCRD(cnpRule.L7Protocols)
 			clusterPeers, perNSPeers := splitPeersByScope(cnpRule, direction)
 			addRule := func(peer *controlplane.NetworkPolicyPeer, dir controlplane.Direction, ruleAppliedTos []string) {
 				rule := controlplane.NetworkPolicyRule{
@@ -299,6 +371,8 @@ func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *crdv1alpha1.C
 					Priority:        int32(idx),
 					EnableLogging:   cnpRule.EnableLogging,
 					AppliedToGroups: ruleAppliedTos,
+					L7Protocols:     l7Protocols,
+					EnforcementMode: cnpRule.EnforcementMode,
 				}
 				if dir == controlplane.DirectionIn {
 					rule.From = *peer
@@ -353,13 +427,9 @@ func (n *NetworkPolicyController) processClusterNetworkPolicy(cnp *crdv1alpha1.C
 	}
 	tierPriority := n.getTierPriority(cnp.Spec.Tier)
 	internalNetworkPolicy := &antreatypes.NetworkPolicy{
-		Name:       internalNetworkPolicyKeyFunc(cnp),
-		Generation: cnp.Generation,
-		SourceRef: &controlplane.NetworkPolicyReference{
-			Type: controlplane.AntreaClusterNetworkPolicy,
-			Name: cnp.Name,
-			UID:  cnp.UID,
-		},
+		Name:                  internalNetworkPolicyKeyFunc(cnp),
+		Generation:            cnp.Generation,
+		SourceRef:             getCNPReference(cnp),
 		UID:                   cnp.UID,
 		AppliedToGroups:       atgNamesSet.List(),
 		Rules:                 rules,
@@ -398,6 +468,8 @@ func (n *NetworkPolicyController) processClusterAppliedTo(appliedTo []crdv1alpha
 		var atg string
 		if at.Group != "" {
 			atg = n.processAppliedToGroupForCG(at.Group)
+		} else if at.NodeSelector != nil {
+			atg = n.createAppliedToGroupForNodes(at.NodeSelector)
 		} else {
 			atg = n.createAppliedToGroup("", at.PodSelector, at.NamespaceSelector, at.ExternalEntitySelector)
 		}
@@ -536,3 +608,12 @@ func (n *NetworkPolicyController) processAppliedToGroupForCG(g string) string {
 	}
 	return n.createAppliedToGroupForClusterGroupCRD(intGrp)
 }
+
+// getCNPReference returns the NetworkPolicyReference of an Antrea ClusterNetworkPolicy.
+func getCNPReference(cnp *crdv1alpha1.ClusterNetworkPolicy) *controlplane.NetworkPolicyReference {
+	return &controlplane.NetworkPolicyReference{
+		Type: controlplane.AntreaClusterNetworkPolicy,
+		Name: cnp.Name,
+		UID:  cnp.UID,
+	}
+}
@@ -36,9 +36,10 @@ var (
 )
 
 // toAntreaServicesForCRD converts a slice of v1alpha1.NetworkPolicyPort
-// objects to a slice of Antrea Service objects. A bool is returned along with
-// the Service objects to indicate whether any named port exists.
-func toAntreaServicesForCRD(npPorts []v1alpha1.NetworkPolicyPort) ([]controlplane.Service, bool) {
+// objects and a slice of v1alpha1.NetworkPolicyProtocol objects to a slice of
+// Antrea Service objects. A bool is returned along with the Service objects to
+// indicate whether any named port exists.
+func toAntreaServicesForCRD(npPorts []v1alpha1.NetworkPolicyPort, npProtocols []v1alpha1.NetworkPolicyProtocol) ([]controlplane.Service, bool) {
 	var antreaServices []controlplane.Service
 	var namedPortExists bool
 	for _, npPort := range npPorts {
@@ -51,9 +52,44 @@ func toAntreaServicesForCRD(npPorts []v1alpha1.NetworkPolicyPort) ([]controlplan
 			EndPort:  npPort.EndPort,
 		})
 	}
+	for _, npProtocol := range npProtocols {
+		var protocol controlplane.Protocol
+		var icmp *v1alpha1.NetworkPolicyICMP
+		if npProtocol.ICMP != nil {
+			protocol, icmp = controlplane.ProtocolICMP, npProtocol.ICMP
+		} else if npProtocol.ICMPv6 != nil {
+			protocol, icmp = controlplane.ProtocolICMPv6, npProtocol.ICMPv6
+		} else {
+			continue
+		}
+		antreaServices = append(antreaServices, controlplane.Service{
+			Protocol: &protocol,
+			ICMPType: icmp.ICMPType,
+			ICMPCode: icmp.ICMPCode,
+		})
+	}
 	return antreaServices, namedPortExists
 }
 
+// toAntreaL7ProtocolsForCRD converts a slice of v1alpha1.L7Protocol objects to
+// a slice of Antrea L7Protocol objects.
+func toAntreaL7ProtocolsForCRD(l7Protocols []v1alpha1.L7Protocol) []controlplane.L7Protocol {
+	var antreaL7Protocols []controlplane.L7Protocol
+	for _, l7Protocol := range l7Protocols {
+		if l7Protocol.HTTP == nil {
+			continue
+		}
+		antreaL7Protocols = append(antreaL7Protocols, controlplane.L7Protocol{
+			HTTP: &controlplane.HTTPProtocol{
+				Host:   l7Protocol.HTTP.Host,
+				Method: l7Protocol.HTTP.Method,
+				Path:   l7Protocol.HTTP.Path,
+			},
+		})
+	}
+	return antreaL7Protocols
+}
+
 // toAntreaIPBlockForCRD converts a v1alpha1.IPBlock to an Antrea IPBlock.
 func toAntreaIPBlockForCRD(ipBlock *v1alpha1.IPBlock) (*controlplane.IPBlock, error) {
 	// Convert the allowed IPBlock to networkpolicy.IPNet.
@@ -93,11 +129,14 @@ func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []v1alpha1.NetworkPol
 		return &podsPeer
 	}
 	var ipBlocks []controlplane.IPBlock
+	var fqdns []string
 	for _, peer := range peers {
 		// A v1alpha1.NetworkPolicyPeer will either have an IPBlock or a
 		// podSelector and/or namespaceSelector set or a reference to the
-		// ClusterGroup.
-		if peer.IPBlock != nil {
+		// ClusterGroup or a FQDN.
+		if peer.FQDN != "" {
+			fqdns = append(fqdns, strings.ToLower(peer.FQDN))
+		} else if peer.IPBlock != nil {
 			ipBlock, err := toAntreaIPBlockForCRD(peer.IPBlock)
 			if err != nil {
 				klog.Errorf("Failure processing Antrea NetworkPolicy %s/%s IPBlock %v: %v", np.GetNamespace(), np.GetName(), peer.IPBlock, err)
@@ -116,7 +155,7 @@ func (n *NetworkPolicyController) toAntreaPeerForCRD(peers []v1alpha1.NetworkPol
 			addressGroups = append(addressGroups, normalizedUID)
 		}
 	}
-	return &controlplane.NetworkPolicyPeer{AddressGroups: addressGroups, IPBlocks: ipBlocks}
+	return &controlplane.NetworkPolicyPeer{AddressGroups: addressGroups, IPBlocks: ipBlocks, FQDNs: fqdns}
 }
 
 // toNamespacedPeerForCRD creates an Antrea controlplane NetworkPolicyPeer for crdv1alpha1 NetworkPolicyPeer
@@ -29,8 +29,14 @@ import (
 )
 
 func TestToAntreaServicesForCRD(t *testing.T) {
+	icmpType8 := int32(8)
+	icmpCode0 := int32(0)
+	icmpType128 := int32(128)
+	protocolICMP := controlplane.ProtocolICMP
+	protocolICMPv6 := controlplane.ProtocolICMPv6
 	tables := []struct {
 		ports              []crdv1alpha1.NetworkPolicyPort
+		protocols          []crdv1alpha1.NetworkPolicyProtocol
 		expServices        []controlplane.Service
 		expNamedPortExists bool
 	}{
@@ -81,14 +87,82 @@ func TestToAntreaServicesForCRD(t *testing.T) {
 			},
 			expNamedPortExists: false,
 		},
+		{
+			ports: []crdv1alpha1.NetworkPolicyPort{
+				{
+					Protocol: &k8sProtocolTCP,
+					Port:     &int80,
+				},
+			},
+			protocols: []crdv1alpha1.NetworkPolicyProtocol{
+				{
+					ICMP: &crdv1alpha1.NetworkPolicyICMP{
+						ICMPType: &icmpType8,
+						ICMPCode: &icmpCode0,
+					},
+				},
+				{
+					ICMPv6: &crdv1alpha1.NetworkPolicyICMP{
+						ICMPType: &icmpType128,
+					},
+				},
+				{
+					ICMP: &crdv1alpha1.NetworkPolicyICMP{},
+				},
+			},
+			expServices: []controlplane.Service{
+				{
+					Protocol: toAntreaProtocol(&k8sProtocolTCP),
+					Port:     &int80,
+				},
+				{
+					Protocol: &protocolICMP,
+					ICMPType: &icmpType8,
+					ICMPCode: &icmpCode0,
+				},
+				{
+					Protocol: &protocolICMPv6,
+					ICMPType: &icmpType128,
+				},
+				{
+					Protocol: &protocolICMP,
+				},
+			},
+			expNamedPortExists: false,
+		},
 	}
 	for _, table := range tables {
-		services, namedPortExist := toAntreaServicesForCRD(table.ports)
+		services, namedPortExist := toAntreaServicesForCRD(table.ports, table.protocols)
 		assert.Equal(t, table.expServices, services)
 		assert.Equal(t, table.expNamedPortExists, namedPortExist)
 	}
 }
 
+func TestToAntreaL7ProtocolsForCRD(t *testing.T) {
+	tables := []struct {
+		l7Protocols    []crdv1alpha1.L7Protocol
+		expL7Protocols []controlplane.L7Protocol
+	}{
+		{
+			l7Protocols:    nil,
+			expL7Protocols: nil,
+		},
+		{
+			l7Protocols: []crdv1alpha1.L7Protocol{
+				{HTTP: &crdv1alpha1.HTTPProtocol{}},
+				{HTTP: &crdv1alpha1.HTTPProtocol{Host: "foo.bar.com", Method: "GET", Path: "/api"}},
+			},
+			expL7Protocols: []controlplane.L7Protocol{
+				{HTTP: &controlplane.HTTPProtocol{}},
+				{HTTP: &controlplane.HTTPProtocol{Host: "foo.bar.com", Method: "GET", Path: "/api"}},
+			},
+		},
+	}
+	for _, table := range tables {
+		assert.Equal(t, table.expL7Protocols, toAntreaL7ProtocolsForCRD(table.l7Protocols))
+	}
+}
+
 func TestToAntreaIPBlockForCRD(t *testing.T) {
 	expIPNet := controlplane.IPNet{
 		IP:           ipStrToIPAddress("10.0.0.0"),
@@ -279,6 +353,29 @@ func TestToAntreaPeerForCRD(t *testing.T) {
 			},
 			direction: controlplane.DirectionOut,
 		},
+		{
+			name: "fqdn-peer-egress",
+			inPeers: []crdv1alpha1.NetworkPolicyPeer{
+				{
+					FQDN: "www.Google.com",
+				},
+				{
+					FQDN: "*.github.com",
+				},
+				{
+					IPBlock: &selectorIP,
+				},
+			},
+			outPeer: controlplane.NetworkPolicyPeer{
+				IPBlocks: []controlplane.IPBlock{
+					{
+						CIDR: *cidrIPNet,
+					},
+				},
+				FQDNs: []string{"www.google.com", "*.github.com"},
+			},
+			direction: controlplane.DirectionOut,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -289,6 +386,9 @@ func TestToAntreaPeerForCRD(t *testing.T) {
 			if !reflect.DeepEqual(tt.outPeer.AddressGroups, actualPeer.AddressGroups) {
 				t.Errorf("Unexpected AddressGroups in Antrea Peer conversion. Expected %v, got %v", tt.outPeer.AddressGroups, actualPeer.AddressGroups)
 			}
+			if !reflect.DeepEqual(tt.outPeer.FQDNs, actualPeer.FQDNs) {
+				t.Errorf("Unexpected FQDNs in Antrea Peer conversion. Expected %v, got %v", tt.outPeer.FQDNs, actualPeer.FQDNs)
+			}
 			if len(tt.outPeer.IPBlocks) != len(actualPeer.IPBlocks) {
 				t.Errorf("Unexpected number of IPBlocks in Antrea Peer conversion. Expected %v, got %v", len(tt.outPeer.IPBlocks), len(actualPeer.IPBlocks))
 			}
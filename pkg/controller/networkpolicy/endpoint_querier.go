@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	cpv1beta "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
 	"antrea.io/antrea/pkg/controller/networkpolicy/store"
 	antreatypes "antrea.io/antrea/pkg/controller/types"
 )
@@ -67,6 +68,16 @@ type Rule struct {
 	RuleIndex int                `json:"ruleindex,omitempty"`
 }
 
+// PolicyRuleRef references a rule of a NetworkPolicy, along with its action.
+type PolicyRuleRef struct {
+	PolicyRef
+	PolicyType cpv1beta.NetworkPolicyType `json:"policytype,omitempty"`
+	Direction  cpv1beta.Direction         `json:"direction,omitempty"`
+	RuleIndex  int                        `json:"ruleindex"`
+	RuleName   string                     `json:"rulename,omitempty"`
+	Action     crdv1alpha1.RuleAction     `json:"action,omitempty"`
+}
+
 // NewEndpointQuerier returns a new *endpointQuerier.
 func NewEndpointQuerier(networkPolicyController *NetworkPolicyController) *endpointQuerier {
 	n := &endpointQuerier{
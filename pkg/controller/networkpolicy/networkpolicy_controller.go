@@ -133,6 +133,13 @@ type NetworkPolicyController struct {
 	// namespaceListerSynced is a function which returns true if the Namespace shared informer has been synced at least once.
 	namespaceListerSynced cache.InformerSynced
 
+	nodeInformer coreinformers.NodeInformer
+	// nodeLister is able to list/get Nodes and is populated by the shared informer passed to
+	// NewNetworkPolicyController.
+	nodeLister corelisters.NodeLister
+	// nodeListerSynced is a function which returns true if the Node shared informer has been synced at least once.
+	nodeListerSynced cache.InformerSynced
+
 	serviceInformer coreinformers.ServiceInformer
 	// serviceLister is able to list/get Services and is populated by the shared informer passed to
 	// NewNetworkPolicyController.
@@ -198,6 +205,13 @@ type NetworkPolicyController struct {
 	// internalGroupQueue maintains the networkpolicy.Group objects that needs to be
 	// synced.
 	internalGroupQueue workqueue.RateLimitingInterface
+	// policyScheduleQueue maintains the references of Antrea-native policies with
+	// ActivationWindows whose active state needs to be evaluated, at the time
+	// at which it may change.
+	policyScheduleQueue workqueue.RateLimitingInterface
+	// statusControl is used to report the status of Antrea-native policies
+	// which are inactive.
+	statusControl networkPolicyControlInterface
 
 	// internalNetworkPolicyMutex protects the internalNetworkPolicyStore from
 	// concurrent access during updates to the internal NetworkPolicy object.
@@ -221,6 +235,7 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 	crdClient versioned.Interface,
 	groupingInterface grouping.Interface,
 	namespaceInformer coreinformers.NamespaceInformer,
+	nodeInformer coreinformers.NodeInformer,
 	serviceInformer coreinformers.ServiceInformer,
 	networkPolicyInformer networkinginformers.NetworkPolicyInformer,
 	cnpInformer secinformers.ClusterNetworkPolicyInformer,
@@ -245,6 +260,7 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 		addressGroupQueue:          workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "addressGroup"),
 		internalNetworkPolicyQueue: workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "internalNetworkPolicy"),
 		internalGroupQueue:         workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "internalGroup"),
+		policyScheduleQueue:        workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "policySchedule"),
 		groupingInterface:          groupingInterface,
 		groupingInterfaceSynced:    groupingInterface.HasSynced,
 	}
@@ -265,6 +281,9 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 		n.namespaceInformer = namespaceInformer
 		n.namespaceLister = namespaceInformer.Lister()
 		n.namespaceListerSynced = namespaceInformer.Informer().HasSynced
+		n.nodeInformer = nodeInformer
+		n.nodeLister = nodeInformer.Lister()
+		n.nodeListerSynced = nodeInformer.Informer().HasSynced
 		n.serviceInformer = serviceInformer
 		n.serviceLister = serviceInformer.Lister()
 		n.serviceListerSynced = serviceInformer.Informer().HasSynced
@@ -280,6 +299,11 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 		n.cgInformer = cgInformer
 		n.cgLister = cgInformer.Lister()
 		n.cgListerSynced = cgInformer.Informer().HasSynced
+		n.statusControl = &networkPolicyControl{
+			antreaClient: crdClient,
+			anpLister:    n.anpLister,
+			cnpLister:    n.cnpLister,
+		}
 		// Add handlers for Namespace events.
 		n.namespaceInformer.Informer().AddEventHandlerWithResyncPeriod(
 			cache.ResourceEventHandlerFuncs{
@@ -289,6 +313,15 @@ func NewNetworkPolicyController(kubeClient clientset.Interface,
 			},
 			resyncPeriod,
 		)
+		// Add handlers for Node events.
+		n.nodeInformer.Informer().AddEventHandlerWithResyncPeriod(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    n.addNode,
+				UpdateFunc: n.updateNode,
+				DeleteFunc: n.deleteNode,
+			},
+			resyncPeriod,
+		)
 		n.serviceInformer.Informer().AddEventHandlerWithResyncPeriod(
 			cache.ResourceEventHandlerFuncs{
 				AddFunc:    n.addService,
@@ -453,6 +486,15 @@ func toGroupSelector(namespace string, podSelector, nsSelector, extEntitySelecto
 	return &groupSelector
 }
 
+// toNodeGroupSelector converts the nodeSelector to a networkpolicy.GroupSelector object.
+func toNodeGroupSelector(nodeSelector *metav1.LabelSelector) *antreatypes.GroupSelector {
+	nSelector, _ := metav1.LabelSelectorAsSelector(nodeSelector)
+	return &antreatypes.GroupSelector{
+		NodeSelector:   nSelector,
+		NormalizedName: fmt.Sprintf("nodeSelector=%s", nSelector.String()),
+	}
+}
+
 // getNormalizedUID generates a unique UUID based on a given string.
 // For example, it can be used to generate keys using normalized selectors
 // unique within the Namespace by adding the constant UID.
@@ -505,6 +547,28 @@ func (n *NetworkPolicyController) createAppliedToGroup(npNsName string, pSel, nS
 	return appliedToGroupUID
 }
 
+// createAppliedToGroupForNodes creates an AppliedToGroup object selecting
+// Nodes in store if it is not created already. Unlike the AppliedToGroups
+// selecting Pods or ExternalEntities, its members are not computed by the
+// grouping interface but from the Nodes matched by the nodeSelector.
+func (n *NetworkPolicyController) createAppliedToGroupForNodes(nodeSelector *metav1.LabelSelector) string {
+	groupSelector := toNodeGroupSelector(nodeSelector)
+	appliedToGroupUID := getNormalizedUID(groupSelector.NormalizedName)
+	_, found, _ := n.appliedToGroupStore.Get(appliedToGroupUID)
+	if found {
+		return appliedToGroupUID
+	}
+	newAppliedToGroup := &antreatypes.AppliedToGroup{
+		Name:     appliedToGroupUID,
+		UID:      types.UID(appliedToGroupUID),
+		Selector: *groupSelector,
+	}
+	klog.V(2).Infof("Creating new AppliedToGroup %s with selector (%s)", newAppliedToGroup.Name, newAppliedToGroup.Selector.NormalizedName)
+	n.appliedToGroupStore.Create(newAppliedToGroup)
+	n.enqueueAppliedToGroup(appliedToGroupUID)
+	return appliedToGroupUID
+}
+
 // createAddressGroup creates an AddressGroup object corresponding to a
 // NetworkPolicyPeer object in NetworkPolicyRule. This function simply
 // creates the object without actually populating the PodAddresses as the
@@ -929,6 +993,7 @@ func (n *NetworkPolicyController) Run(stopCh <-chan struct{}) {
 	defer n.addressGroupQueue.ShutDown()
 	defer n.internalNetworkPolicyQueue.ShutDown()
 	defer n.internalGroupQueue.ShutDown()
+	defer n.policyScheduleQueue.ShutDown()
 
 	klog.Infof("Starting %s", controllerName)
 	defer klog.Infof("Shutting down %s", controllerName)
@@ -936,7 +1001,7 @@ func (n *NetworkPolicyController) Run(stopCh <-chan struct{}) {
 	cacheSyncs := []cache.InformerSynced{n.networkPolicyListerSynced, n.groupingInterfaceSynced}
 	// Only wait for cnpListerSynced and anpListerSynced when AntreaPolicy feature gate is enabled.
 	if features.DefaultFeatureGate.Enabled(features.AntreaPolicy) {
-		cacheSyncs = append(cacheSyncs, n.cnpListerSynced, n.anpListerSynced, n.cgListerSynced)
+		cacheSyncs = append(cacheSyncs, n.cnpListerSynced, n.anpListerSynced, n.cgListerSynced, n.nodeListerSynced)
 	}
 	if !cache.WaitForNamedCacheSync(controllerName, stopCh, cacheSyncs...) {
 		return
@@ -948,6 +1013,7 @@ func (n *NetworkPolicyController) Run(stopCh <-chan struct{}) {
 		go wait.Until(n.internalNetworkPolicyWorker, time.Second, stopCh)
 		go wait.Until(n.internalGroupWorker, time.Second, stopCh)
 	}
+	go wait.Until(n.policyScheduleWorker, time.Second, stopCh)
 	<-stopCh
 }
 
@@ -1184,6 +1250,24 @@ func podToGroupMember(pod *v1.Pod, includeIP bool) *controlplane.GroupMember {
 	return memberPod
 }
 
+// nodeToGroupMember converts a Node to a GroupMember. The internal and
+// external IPs of the Node are included as the GroupMember's IPs.
+func nodeToGroupMember(node *v1.Node) *controlplane.GroupMember {
+	memberNode := &controlplane.GroupMember{
+		Node: &controlplane.NodeReference{Name: node.Name},
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type != v1.NodeInternalIP && address.Type != v1.NodeExternalIP {
+			continue
+		}
+		if net.ParseIP(address.Address) == nil {
+			continue
+		}
+		memberNode.IPs = append(memberNode.IPs, ipStrToIPAddress(address.Address))
+	}
+	return memberNode
+}
+
 func externalEntityToGroupMember(ee *v1alpha2.ExternalEntity) *controlplane.GroupMember {
 	memberEntity := &controlplane.GroupMember{}
 	namedPorts := make([]controlplane.NamedPort, len(ee.Spec.Ports))
@@ -1229,6 +1313,9 @@ func (n *NetworkPolicyController) syncAppliedToGroup(key string) error {
 	memberSetByNode := make(map[string]controlplane.GroupMemberSet)
 	scheduledPodNum, scheduledExtEntityNum := 0, 0
 	appliedToGroup := appliedToGroupObj.(*antreatypes.AppliedToGroup)
+	if appliedToGroup.Selector.NodeSelector != nil {
+		return n.syncNodeAppliedToGroup(appliedToGroup)
+	}
 	pods, externalEntities := n.getAppliedToWorkloads(appliedToGroup)
 	for _, pod := range pods {
 		if pod.Spec.NodeName == "" || pod.Spec.HostNetwork == true {
@@ -1288,6 +1375,40 @@ func (n *NetworkPolicyController) syncAppliedToGroup(key string) error {
 	return nil
 }
 
+// syncNodeAppliedToGroup updates the AppliedToGroup selecting Nodes to reflect
+// the latest set of Nodes matched by its NodeSelector. Each Node is the only
+// member of the AppliedToGroup disseminated to it.
+func (n *NetworkPolicyController) syncNodeAppliedToGroup(appliedToGroup *antreatypes.AppliedToGroup) error {
+	nodes, err := n.nodeLister.List(appliedToGroup.Selector.NodeSelector)
+	if err != nil {
+		return fmt.Errorf("unable to list Nodes for AppliedToGroup %s: %v", appliedToGroup.Name, err)
+	}
+	memberSetByNode := make(map[string]controlplane.GroupMemberSet)
+	appGroupNodeNames := sets.String{}
+	for _, node := range nodes {
+		memberSetByNode[node.Name] = controlplane.NewGroupMemberSet(nodeToGroupMember(node))
+		appGroupNodeNames.Insert(node.Name)
+	}
+	updatedAppliedToGroup := &antreatypes.AppliedToGroup{
+		UID:               appliedToGroup.UID,
+		Name:              appliedToGroup.Name,
+		Selector:          appliedToGroup.Selector,
+		GroupMemberByNode: memberSetByNode,
+		SpanMeta:          antreatypes.SpanMeta{NodeNames: appGroupNodeNames},
+	}
+	klog.V(2).Infof("Updating existing AppliedToGroup %s with %d Nodes", appliedToGroup.Name, appGroupNodeNames.Len())
+	n.appliedToGroupStore.Update(updatedAppliedToGroup)
+	nps, err := n.internalNetworkPolicyStore.GetByIndex(store.AppliedToGroupIndex, appliedToGroup.Name)
+	if err != nil {
+		return fmt.Errorf("unable to filter internal NetworkPolicies for AppliedToGroup %s: %v", appliedToGroup.Name, err)
+	}
+	for _, npObj := range nps {
+		npKey, _ := store.NetworkPolicyKeyFunc(npObj)
+		n.enqueueInternalNetworkPolicy(npKey)
+	}
+	return nil
+}
+
 // getAppliedToWorkloads returns a list of workloads (Pods and ExternalEntities) selected by an AppliedToGroup
 // for standalone selectors or corresponding to a ClusterGroup.
 func (n *NetworkPolicyController) getAppliedToWorkloads(g *antreatypes.AppliedToGroup) ([]*v1.Pod, []*v1alpha2.ExternalEntity) {
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
@@ -103,6 +104,7 @@ func newController(objects ...runtime.Object) (*fake.Clientset, *networkPolicyCo
 		crdClient,
 		groupEntityIndex,
 		informerFactory.Core().V1().Namespaces(),
+		informerFactory.Core().V1().Nodes(),
 		informerFactory.Core().V1().Services(),
 		informerFactory.Networking().V1().NetworkPolicies(),
 		crdInformerFactory.Crd().V1alpha1().ClusterNetworkPolicies(),
@@ -115,6 +117,8 @@ func newController(objects ...runtime.Object) (*fake.Clientset, *networkPolicyCo
 		internalGroupStore)
 	npController.namespaceLister = informerFactory.Core().V1().Namespaces().Lister()
 	npController.namespaceListerSynced = alwaysReady
+	npController.nodeLister = informerFactory.Core().V1().Nodes().Lister()
+	npController.nodeListerSynced = alwaysReady
 	npController.networkPolicyListerSynced = alwaysReady
 	npController.cnpListerSynced = alwaysReady
 	npController.tierLister = crdInformerFactory.Crd().V1alpha1().Tiers().Lister()
@@ -169,6 +173,7 @@ func newControllerWithoutEventHandler(objects ...runtime.Object) (*fake.Clientse
 		addressGroupQueue:          workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "addressGroup"),
 		internalNetworkPolicyQueue: workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "internalNetworkPolicy"),
 		internalGroupQueue:         workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "internalGroup"),
+		policyScheduleQueue:        workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "policySchedule"),
 		groupingInterface:          groupEntityIndex,
 	}
 	return client, &networkPolicyController{
@@ -1537,6 +1542,60 @@ func TestDeletePod(t *testing.T) {
 	assert.False(t, groupMembers.Has(memberPod2))
 }
 
+func TestSyncNodeAppliedToGroup(t *testing.T) {
+	nodeSelector := metav1.LabelSelector{MatchLabels: map[string]string{"role": "worker"}}
+	node1 := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"role": "worker"}},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "192.168.0.1"},
+				{Type: corev1.NodeHostName, Address: "node1"},
+			},
+		},
+	}
+	node2 := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"role": "master"}},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "192.168.0.2"},
+			},
+		},
+	}
+	_, npc := newController()
+	nodeStore := npc.informerFactory.Core().V1().Nodes().Informer().GetStore()
+	nodeStore.Add(node1)
+	nodeStore.Add(node2)
+	atgName := npc.createAppliedToGroupForNodes(&nodeSelector)
+	assert.NoError(t, npc.syncAppliedToGroup(atgName))
+	atgObj, _, _ := npc.appliedToGroupStore.Get(atgName)
+	atg := atgObj.(*antreatypes.AppliedToGroup)
+	expectedMember := &controlplane.GroupMember{
+		Node: &controlplane.NodeReference{Name: "node1"},
+		IPs:  []controlplane.IPAddress{ipStrToIPAddress("192.168.0.1")},
+	}
+	assert.Equal(t, map[string]controlplane.GroupMemberSet{"node1": controlplane.NewGroupMemberSet(expectedMember)}, atg.GroupMemberByNode)
+	assert.Equal(t, sets.NewString("node1"), atg.SpanMeta.NodeNames)
+
+	// Relabel node2 so that it's selected by the AppliedToGroup.
+	updatedNode2 := node2.DeepCopy()
+	updatedNode2.Labels = map[string]string{"role": "worker"}
+	nodeStore.Update(updatedNode2)
+	npc.updateNode(node2, updatedNode2)
+	assert.Equal(t, 1, npc.appliedToGroupQueue.Len())
+	assert.NoError(t, npc.syncAppliedToGroup(atgName))
+	atgObj, _, _ = npc.appliedToGroupStore.Get(atgName)
+	atg = atgObj.(*antreatypes.AppliedToGroup)
+	assert.Equal(t, sets.NewString("node1", "node2"), atg.SpanMeta.NodeNames)
+
+	// Delete node1.
+	nodeStore.Delete(node1)
+	npc.deleteNode(node1)
+	assert.NoError(t, npc.syncAppliedToGroup(atgName))
+	atgObj, _, _ = npc.appliedToGroupStore.Get(atgName)
+	atg = atgObj.(*antreatypes.AppliedToGroup)
+	assert.Equal(t, sets.NewString("node2"), atg.SpanMeta.NodeNames)
+}
+
 func TestAddNamespace(t *testing.T) {
 	selectorSpec := metav1.LabelSelector{}
 	selectorIn := metav1.LabelSelector{
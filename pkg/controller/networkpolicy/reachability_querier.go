@@ -0,0 +1,516 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	"antrea.io/antrea/pkg/apis/controlplane"
+	cpv1beta "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	antreatypes "antrea.io/antrea/pkg/controller/types"
+)
+
+const (
+	// ICMP echo requests are used to evaluate ICMP traffic.
+	icmpEchoRequestType   = int32(8)
+	icmpv6EchoRequestType = int32(128)
+)
+
+// ReachabilityQuerier handles requests for antctl reachability queries.
+type ReachabilityQuerier interface {
+	// QueryReachability evaluates the NetworkPolicies which apply to the traffic
+	// described by the provided query, and returns the effective verdict along with
+	// the rule which decided it.
+	QueryReachability(query *ReachabilityQuery) (*ReachabilityQueryResponse, error)
+}
+
+// reachabilityQuerier implements the ReachabilityQuerier interface.
+type reachabilityQuerier struct {
+	networkPolicyController *NetworkPolicyController
+	podLister               corelisters.PodLister
+}
+
+// ReachabilityEndpoint is either a Pod, identified by its Namespace and name, or
+// an IP address.
+type ReachabilityEndpoint struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	IP        string `json:"ip,omitempty"`
+}
+
+// ReachabilityQuery describes the traffic to evaluate.
+type ReachabilityQuery struct {
+	Source      ReachabilityEndpoint
+	Destination ReachabilityEndpoint
+	// Protocol defaults to TCP. For ICMP and ICMPv6, an echo request is
+	// evaluated, with the ICMP version matching the IP family of the destination.
+	Protocol controlplane.Protocol
+	// Port is the destination port. It's ignored for ICMP and ICMPv6.
+	Port int32
+}
+
+// ReachabilityQueryResponse is the reply struct for antctl reachability queries.
+type ReachabilityQueryResponse struct {
+	// Verdict is the effective action applied to the traffic.
+	Verdict crdv1alpha1.RuleAction `json:"verdict"`
+	// Rule is the rule which decided the verdict. It's unset if the traffic is
+	// not matched by any rule.
+	Rule *PolicyRuleRef `json:"rule,omitempty"`
+	// Egress is the evaluation of the rules applied to the source. It's unset if
+	// the source is not a Pod.
+	Egress *ReachabilityVerdict `json:"egress,omitempty"`
+	// Ingress is the evaluation of the rules applied to the destination. It's
+	// unset if the destination is not a Pod.
+	Ingress *ReachabilityVerdict `json:"ingress,omitempty"`
+}
+
+// ReachabilityVerdict is the result of the evaluation of the rules in one
+// direction.
+type ReachabilityVerdict struct {
+	Verdict crdv1alpha1.RuleAction `json:"verdict"`
+	// Rule is the rule which decided the verdict. It's unset if the traffic is
+	// not matched by any rule.
+	Rule *PolicyRuleRef `json:"rule,omitempty"`
+	// Isolated is true if the Pod is isolated by K8s NetworkPolicies in this
+	// direction.
+	Isolated bool `json:"isolated,omitempty"`
+	// PassedBy is the last rule with the Pass action which delegated the
+	// decision to the next Tiers, if any.
+	PassedBy *PolicyRuleRef `json:"passedBy,omitempty"`
+}
+
+// reachabilityPeer is a resolved ReachabilityEndpoint. pod is nil if the
+// endpoint is not a Pod selectable by NetworkPolicies.
+type reachabilityPeer struct {
+	pod *v1.Pod
+	ips []net.IP
+	ip  net.IP
+}
+
+// matchedRule is a rule of an internal NetworkPolicy which matches the traffic.
+type matchedRule struct {
+	policy *antreatypes.NetworkPolicy
+	rule   *controlplane.NetworkPolicyRule
+	// index is the index of the rule among the rules of the same direction.
+	index int
+}
+
+// NewReachabilityQuerier returns a new *reachabilityQuerier.
+func NewReachabilityQuerier(networkPolicyController *NetworkPolicyController, podInformer coreinformers.PodInformer) *reachabilityQuerier {
+	return &reachabilityQuerier{
+		networkPolicyController: networkPolicyController,
+		podLister:               podInformer.Lister(),
+	}
+}
+
+// QueryReachability evaluates the rules of all Tiers and K8s NetworkPolicies from
+// the internal NetworkPolicy store, in the same order as the datapath: the egress
+// rules applied to the source first, then the ingress rules applied to the
+// destination. No packet is injected in the datapath.
+func (rq *reachabilityQuerier) QueryReachability(query *ReachabilityQuery) (*ReachabilityQueryResponse, error) {
+	src, err := rq.resolveEndpoint(query.Source)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := rq.resolveEndpoint(query.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if src.pod == nil && dst.pod == nil {
+		return nil, fmt.Errorf("at least one of the source and destination must be a Pod")
+	}
+	if err := selectIPs(src, dst); err != nil {
+		return nil, err
+	}
+	protocol := query.Protocol
+	if protocol == "" {
+		protocol = controlplane.ProtocolTCP
+	}
+	if protocol == controlplane.ProtocolICMP && dst.ip.To4() == nil {
+		protocol = controlplane.ProtocolICMPv6
+	} else if protocol == controlplane.ProtocolICMPv6 && dst.ip.To4() != nil {
+		protocol = controlplane.ProtocolICMP
+	}
+
+	response := &ReachabilityQueryResponse{Verdict: crdv1alpha1.RuleActionAllow}
+	if src.pod != nil {
+		response.Egress = rq.evaluateDirection(controlplane.DirectionOut, src, dst, dst, protocol, query.Port)
+	}
+	if dst.pod != nil {
+		response.Ingress = rq.evaluateDirection(controlplane.DirectionIn, dst, src, dst, protocol, query.Port)
+	}
+	for _, v := range []*ReachabilityVerdict{response.Egress, response.Ingress} {
+		if v == nil {
+			continue
+		}
+		if v.Verdict != crdv1alpha1.RuleActionAllow {
+			response.Verdict, response.Rule = v.Verdict, v.Rule
+			break
+		}
+		// For allowed traffic, the ingress rule is reported if any, as it's the
+		// last one to be evaluated.
+		if v.Rule != nil {
+			response.Rule = v.Rule
+		}
+	}
+	return response, nil
+}
+
+// resolveEndpoint gets the Pod and the IPs of the provided endpoint. If only an IP
+// is provided, the Pod which owns it is looked up, as Pods are selected by
+// NetworkPolicies through labels rather than IPs.
+func (rq *reachabilityQuerier) resolveEndpoint(endpoint ReachabilityEndpoint) (*reachabilityPeer, error) {
+	if endpoint.Pod != "" {
+		namespace := endpoint.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		pod, err := rq.podLister.Pods(namespace).Get(endpoint.Pod)
+		if err != nil {
+			return nil, err
+		}
+		peer := &reachabilityPeer{ips: getPodIPs(pod)}
+		if len(peer.ips) == 0 {
+			return nil, fmt.Errorf("Pod %s/%s has no IP address", namespace, endpoint.Pod)
+		}
+		// hostNetwork Pods are not selected by NetworkPolicies.
+		if !pod.Spec.HostNetwork {
+			peer.pod = pod
+		}
+		return peer, nil
+	}
+	if endpoint.IP == "" {
+		return nil, fmt.Errorf("either a Pod or an IP must be provided")
+	}
+	ip := net.ParseIP(endpoint.IP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %s", endpoint.IP)
+	}
+	peer := &reachabilityPeer{ips: []net.IP{ip}}
+	// We iterate over all Pods. This is acceptable since this implementation only
+	// supports user queries.
+	pods, err := rq.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork {
+			continue
+		}
+		for _, podIP := range getPodIPs(pod) {
+			if podIP.Equal(ip) {
+				peer.pod = pod
+				return peer, nil
+			}
+		}
+	}
+	return peer, nil
+}
+
+func getPodIPs(pod *v1.Pod) []net.IP {
+	var ips []net.IP
+	for _, podIP := range pod.Status.PodIPs {
+		if ip := net.ParseIP(podIP.IP); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 {
+		if ip := net.ParseIP(pod.Status.PodIP); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// selectIPs selects the IPs of the source and destination from the same address
+// family, IPv4 being preferred for dual-stack endpoints.
+func selectIPs(src, dst *reachabilityPeer) error {
+	for _, isIPv4 := range []bool{true, false} {
+		srcIP, dstIP := getIPOfFamily(src.ips, isIPv4), getIPOfFamily(dst.ips, isIPv4)
+		if srcIP != nil && dstIP != nil {
+			src.ip, dst.ip = srcIP, dstIP
+			return nil
+		}
+	}
+	return fmt.Errorf("source and destination have no IP address of the same family")
+}
+
+func getIPOfFamily(ips []net.IP, isIPv4 bool) net.IP {
+	for _, ip := range ips {
+		if (ip.To4() != nil) == isIPv4 {
+			return ip
+		}
+	}
+	return nil
+}
+
+// evaluateDirection evaluates the rules of the provided direction which apply to
+// target, peer being the other end of the traffic. Antrea-native policy rules are
+// evaluated first, by order of Tier priority, policy priority and rule priority,
+// until a rule matches the traffic. A rule with the Pass action skips the rest
+// of the rules of its Tier. The K8s NetworkPolicy rules are evaluated next, and
+// finally the rules of the baseline Tier if the Pod is not isolated by K8s
+// NetworkPolicies.
+func (rq *reachabilityQuerier) evaluateDirection(direction controlplane.Direction, target, peer, dst *reachabilityPeer, protocol controlplane.Protocol, port int32) *ReachabilityVerdict {
+	var tierRules, k8sRules, baselineRules []*matchedRule
+	isolated := false
+	// We iterate over all internal NetworkPolicies, for the same reason as in
+	// QueryNetworkPolicies.
+	for _, obj := range rq.networkPolicyController.internalNetworkPolicyStore.List() {
+		policy := obj.(*antreatypes.NetworkPolicy)
+		isK8sNetworkPolicy := policy.SourceRef.Type == controlplane.K8sNetworkPolicy
+		index := 0
+		for i := range policy.Rules {
+			rule := &policy.Rules[i]
+			if rule.Direction != direction {
+				continue
+			}
+			m := &matchedRule{policy: policy, rule: rule, index: index}
+			index++
+			if !rq.appliesTo(policy, rule, target.pod) {
+				continue
+			}
+			if isK8sNetworkPolicy {
+				isolated = true
+			} else if rule.EnforcementMode == crdv1alpha1.RuleEnforcementModeAudit {
+				// Rules in Audit mode don't affect the traffic.
+				continue
+			}
+			rulePeer := rule.From
+			if direction == controlplane.DirectionOut {
+				rulePeer = rule.To
+			}
+			if !rq.peerMatches(rulePeer, peer) || !servicesMatch(rule.Services, protocol, port, dst) {
+				continue
+			}
+			switch {
+			case isK8sNetworkPolicy:
+				k8sRules = append(k8sRules, m)
+			case *policy.TierPriority == BaselineTierPriority:
+				baselineRules = append(baselineRules, m)
+			default:
+				tierRules = append(tierRules, m)
+			}
+		}
+	}
+	sortMatchedRules(tierRules)
+	sortMatchedRules(k8sRules)
+	sortMatchedRules(baselineRules)
+
+	verdict := &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionAllow, Isolated: isolated}
+	var passedTierPriority *int32
+	for _, m := range tierRules {
+		if passedTierPriority != nil && *m.policy.TierPriority == *passedTierPriority {
+			continue
+		}
+		if m.action() == crdv1alpha1.RuleActionPass {
+			verdict.PassedBy = m.toPolicyRuleRef()
+			passedTierPriority = m.policy.TierPriority
+			continue
+		}
+		verdict.Verdict, verdict.Rule = m.action(), m.toPolicyRuleRef()
+		return verdict
+	}
+	if len(k8sRules) > 0 {
+		verdict.Rule = k8sRules[0].toPolicyRuleRef()
+		return verdict
+	}
+	// Traffic which is not allowed by any K8s NetworkPolicy rule is dropped if the
+	// Pod is isolated, before the baseline Tier is evaluated.
+	if isolated {
+		verdict.Verdict = crdv1alpha1.RuleActionDrop
+		return verdict
+	}
+	if len(baselineRules) > 0 {
+		verdict.Verdict, verdict.Rule = baselineRules[0].action(), baselineRules[0].toPolicyRuleRef()
+	}
+	return verdict
+}
+
+// appliesTo returns whether the rule of the policy applies to the Pod.
+func (rq *reachabilityQuerier) appliesTo(policy *antreatypes.NetworkPolicy, rule *controlplane.NetworkPolicyRule, pod *v1.Pod) bool {
+	appliedToGroups := rule.AppliedToGroups
+	if len(appliedToGroups) == 0 {
+		appliedToGroups = policy.AppliedToGroups
+	}
+	member := podToGroupMember(pod, false)
+	for _, name := range appliedToGroups {
+		obj, found, _ := rq.networkPolicyController.appliedToGroupStore.Get(name)
+		if !found {
+			continue
+		}
+		for _, members := range obj.(*antreatypes.AppliedToGroup).GroupMemberByNode {
+			if members.Has(member) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// peerMatches returns whether the endpoint is selected by the peer of a rule.
+// FQDNs are resolved by the Antrea Agents and are never matched.
+func (rq *reachabilityQuerier) peerMatches(rulePeer controlplane.NetworkPolicyPeer, peer *reachabilityPeer) bool {
+	for _, name := range rulePeer.AddressGroups {
+		obj, found, _ := rq.networkPolicyController.addressGroupStore.Get(name)
+		if !found {
+			continue
+		}
+		members := obj.(*antreatypes.AddressGroup).GroupMembers
+		if peer.pod != nil {
+			if members.Has(podToGroupMember(peer.pod, true)) {
+				return true
+			}
+			continue
+		}
+		for _, member := range members {
+			for _, ip := range member.IPs {
+				if net.IP(ip).Equal(peer.ip) {
+					return true
+				}
+			}
+		}
+	}
+	for _, ipBlock := range rulePeer.IPBlocks {
+		if ipBlockContains(ipBlock, peer.ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func ipBlockContains(ipBlock controlplane.IPBlock, ip net.IP) bool {
+	if !ipNetContains(ipBlock.CIDR, ip) {
+		return false
+	}
+	for _, except := range ipBlock.Except {
+		if ipNetContains(except, ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func ipNetContains(ipNet controlplane.IPNet, ip net.IP) bool {
+	bits := 8 * net.IPv6len
+	if net.IP(ipNet.IP).To4() != nil {
+		bits = 8 * net.IPv4len
+	}
+	n := net.IPNet{IP: net.IP(ipNet.IP), Mask: net.CIDRMask(int(ipNet.PrefixLength), bits)}
+	return n.Contains(ip)
+}
+
+// servicesMatch returns whether the traffic is matched by any of the services of a
+// rule. Named ports are resolved against the destination Pod.
+func servicesMatch(services []controlplane.Service, protocol controlplane.Protocol, port int32, dst *reachabilityPeer) bool {
+	if len(services) == 0 {
+		return true
+	}
+	for _, service := range services {
+		serviceProtocol := controlplane.ProtocolTCP
+		if service.Protocol != nil {
+			serviceProtocol = *service.Protocol
+		}
+		if serviceProtocol != protocol {
+			continue
+		}
+		if protocol == controlplane.ProtocolICMP || protocol == controlplane.ProtocolICMPv6 {
+			echoRequestType := icmpEchoRequestType
+			if protocol == controlplane.ProtocolICMPv6 {
+				echoRequestType = icmpv6EchoRequestType
+			}
+			if (service.ICMPType == nil || *service.ICMPType == echoRequestType) && (service.ICMPCode == nil || *service.ICMPCode == 0) {
+				return true
+			}
+			continue
+		}
+		if service.Port == nil {
+			return true
+		}
+		if service.Port.Type == intstr.Int {
+			endPort := service.Port.IntVal
+			if service.EndPort != nil {
+				endPort = *service.EndPort
+			}
+			if port >= service.Port.IntVal && port <= endPort {
+				return true
+			}
+			continue
+		}
+		if dst.pod == nil {
+			continue
+		}
+		for _, namedPort := range podToGroupMember(dst.pod, false).Ports {
+			if namedPort.Name == service.Port.StrVal && namedPort.Protocol == protocol && namedPort.Port == port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortMatchedRules sorts the rules by order of evaluation.
+func sortMatchedRules(rules []*matchedRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		return evaluatedBefore(rules[i], rules[j])
+	})
+}
+
+// evaluatedBefore returns whether rule a is evaluated before rule b. The rules of
+// K8s NetworkPolicies have no priority and are sorted by policy name.
+func evaluatedBefore(a, b *matchedRule) bool {
+	pa, pb := a.policy, b.policy
+	if pa.TierPriority != nil && pb.TierPriority != nil && *pa.TierPriority != *pb.TierPriority {
+		return *pa.TierPriority < *pb.TierPriority
+	}
+	if pa.Priority != nil && pb.Priority != nil && *pa.Priority != *pb.Priority {
+		return *pa.Priority < *pb.Priority
+	}
+	if pa.Name != pb.Name {
+		return pa.Name < pb.Name
+	}
+	return a.rule.Priority < b.rule.Priority
+}
+
+func (m *matchedRule) action() crdv1alpha1.RuleAction {
+	if m.rule.Action == nil {
+		return crdv1alpha1.RuleActionAllow
+	}
+	return *m.rule.Action
+}
+
+func (m *matchedRule) toPolicyRuleRef() *PolicyRuleRef {
+	return &PolicyRuleRef{
+		PolicyRef: PolicyRef{
+			Namespace: m.policy.SourceRef.Namespace,
+			Name:      m.policy.SourceRef.Name,
+			UID:       m.policy.SourceRef.UID,
+		},
+		PolicyType: cpv1beta.NetworkPolicyType(m.policy.SourceRef.Type),
+		Direction:  cpv1beta.Direction(m.rule.Direction),
+		RuleIndex:  m.index,
+		RuleName:   m.rule.Name,
+		Action:     m.action(),
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"antrea.io/antrea/pkg/apis/controlplane"
+	cpv1beta "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	antreatypes "antrea.io/antrea/pkg/controller/types"
+)
+
+func newReachabilityTestPod(namespace, name, ip string, namedPort int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "container-1",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: namedPort, Protocol: corev1.ProtocolTCP}},
+			}},
+			NodeName: "node1",
+		},
+		Status: corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: ip}}},
+	}
+}
+
+func newReachabilityTestPolicy(name string, sourceType controlplane.NetworkPolicyType, tierPriority *int32, priority *float64, appliedToGroup string, rules ...controlplane.NetworkPolicyRule) *antreatypes.NetworkPolicy {
+	namespace := "ns1"
+	if sourceType == controlplane.AntreaClusterNetworkPolicy {
+		namespace = ""
+	}
+	return &antreatypes.NetworkPolicy{
+		Name: name,
+		UID:  types.UID(name),
+		SourceRef: &controlplane.NetworkPolicyReference{
+			Type:      sourceType,
+			Namespace: namespace,
+			Name:      name,
+			UID:       types.UID(name),
+		},
+		TierPriority:    tierPriority,
+		Priority:        priority,
+		AppliedToGroups: []string{appliedToGroup},
+		Rules:           rules,
+	}
+}
+
+func TestQueryReachability(t *testing.T) {
+	podA := newReachabilityTestPod("ns1", "podA", "10.0.0.1", 8080)
+	podB := newReachabilityTestPod("ns1", "podB", "10.0.0.2", 80)
+	appTierPriority, baselineTierPriority := DefaultTierPriority, BaselineTierPriority
+	securityOpsTierPriority := int32(100)
+	priority, priority2 := float64(1), float64(2)
+	tcp := controlplane.ProtocolTCP
+	port80, portHTTP := intstr.FromInt(80), intstr.FromString("http")
+	allowAction, dropAction, passAction := crdv1alpha1.RuleActionAllow, crdv1alpha1.RuleActionDrop, crdv1alpha1.RuleActionPass
+	peerA := controlplane.NetworkPolicyPeer{AddressGroups: []string{"agA"}}
+	ipBlockPeer := controlplane.NetworkPolicyPeer{IPBlocks: []controlplane.IPBlock{{
+		CIDR:   controlplane.IPNet{IP: ipStrToIPAddress("10.0.0.0"), PrefixLength: 24},
+		Except: []controlplane.IPNet{{IP: ipStrToIPAddress("10.0.0.128"), PrefixLength: 25}},
+	}}}
+
+	k8sAllowFromA := newReachabilityTestPolicy("k8s-allow-from-a", controlplane.K8sNetworkPolicy, nil, nil, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, From: peerA, Services: []controlplane.Service{{Protocol: &tcp, Port: &port80}}, Action: &allowAction})
+	k8sAllowNamedPort := newReachabilityTestPolicy("k8s-allow-named-port", controlplane.K8sNetworkPolicy, nil, nil, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, From: peerA, Services: []controlplane.Service{{Protocol: &tcp, Port: &portHTTP}}, Action: &allowAction})
+	acnpDropEgress := newReachabilityTestPolicy("acnp-drop-egress", controlplane.AntreaClusterNetworkPolicy, &appTierPriority, &priority, "atgA",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionOut, Name: "drop-to-block", To: ipBlockPeer, Action: &dropAction})
+	acnpPassIngress := newReachabilityTestPolicy("acnp-pass-ingress", controlplane.AntreaClusterNetworkPolicy, &securityOpsTierPriority, &priority, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, Name: "pass-from-a", From: peerA, Action: &passAction})
+	acnpDropIngress := newReachabilityTestPolicy("acnp-drop-ingress", controlplane.AntreaClusterNetworkPolicy, &appTierPriority, &priority, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, Name: "drop-from-block", From: ipBlockPeer, Action: &dropAction})
+	securityOpsDropIngress := newReachabilityTestPolicy("securityops-drop-ingress", controlplane.AntreaClusterNetworkPolicy, &securityOpsTierPriority, &priority2, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, Name: "drop-from-block", From: ipBlockPeer, Action: &dropAction})
+	acnpAuditIngress := newReachabilityTestPolicy("acnp-audit-ingress", controlplane.AntreaClusterNetworkPolicy, &securityOpsTierPriority, &priority, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, From: peerA, Action: &dropAction, EnforcementMode: crdv1alpha1.RuleEnforcementModeAudit})
+	baselineDropIngress := newReachabilityTestPolicy("baseline-drop-ingress", controlplane.AntreaClusterNetworkPolicy, &baselineTierPriority, &priority, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, Name: "baseline-drop", From: peerA, Action: &dropAction})
+
+	ruleRef := func(policy *antreatypes.NetworkPolicy, direction cpv1beta.Direction, action crdv1alpha1.RuleAction) *PolicyRuleRef {
+		return &PolicyRuleRef{
+			PolicyRef:  PolicyRef{Namespace: policy.SourceRef.Namespace, Name: policy.SourceRef.Name, UID: policy.SourceRef.UID},
+			PolicyType: cpv1beta.NetworkPolicyType(policy.SourceRef.Type),
+			Direction:  direction,
+			RuleName:   policy.Rules[0].Name,
+			Action:     action,
+		}
+	}
+	podAToPodB := &ReachabilityQuery{
+		Source:      ReachabilityEndpoint{Namespace: "ns1", Pod: "podA"},
+		Destination: ReachabilityEndpoint{Namespace: "ns1", Pod: "podB"},
+		Port:        80,
+	}
+	allowed := &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionAllow}
+
+	tests := []struct {
+		name             string
+		policies         []*antreatypes.NetworkPolicy
+		query            *ReachabilityQuery
+		expectedResponse *ReachabilityQueryResponse
+	}{
+		{
+			name:  "no-policy",
+			query: podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionAllow,
+				Egress:  allowed,
+				Ingress: allowed,
+			},
+		},
+		{
+			name:     "k8s-allowed",
+			policies: []*antreatypes.NetworkPolicy{k8sAllowFromA},
+			query:    podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionAllow,
+				Rule:    ruleRef(k8sAllowFromA, cpv1beta.DirectionIn, allowAction),
+				Egress:  allowed,
+				Ingress: &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionAllow, Rule: ruleRef(k8sAllowFromA, cpv1beta.DirectionIn, allowAction), Isolated: true},
+			},
+		},
+		{
+			name:     "k8s-isolated",
+			policies: []*antreatypes.NetworkPolicy{k8sAllowFromA},
+			query: &ReachabilityQuery{
+				Source:      podAToPodB.Source,
+				Destination: podAToPodB.Destination,
+				Port:        81,
+			},
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionDrop,
+				Egress:  allowed,
+				Ingress: &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionDrop, Isolated: true},
+			},
+		},
+		{
+			name:     "k8s-named-port",
+			policies: []*antreatypes.NetworkPolicy{k8sAllowNamedPort},
+			query:    podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionAllow,
+				Rule:    ruleRef(k8sAllowNamedPort, cpv1beta.DirectionIn, allowAction),
+				Egress:  allowed,
+				Ingress: &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionAllow, Rule: ruleRef(k8sAllowNamedPort, cpv1beta.DirectionIn, allowAction), Isolated: true},
+			},
+		},
+		{
+			name:     "acnp-dropped-at-egress",
+			policies: []*antreatypes.NetworkPolicy{acnpDropEgress, k8sAllowFromA},
+			query:    podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionDrop,
+				Rule:    ruleRef(acnpDropEgress, cpv1beta.DirectionOut, dropAction),
+				Egress:  &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionDrop, Rule: ruleRef(acnpDropEgress, cpv1beta.DirectionOut, dropAction)},
+				Ingress: &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionAllow, Rule: ruleRef(k8sAllowFromA, cpv1beta.DirectionIn, allowAction), Isolated: true},
+			},
+		},
+		{
+			name:     "acnp-pass-to-lower-tier",
+			policies: []*antreatypes.NetworkPolicy{acnpPassIngress, securityOpsDropIngress, acnpDropIngress, k8sAllowFromA},
+			query:    podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionDrop,
+				Rule:    ruleRef(acnpDropIngress, cpv1beta.DirectionIn, dropAction),
+				Egress:  allowed,
+				Ingress: &ReachabilityVerdict{
+					Verdict:  crdv1alpha1.RuleActionDrop,
+					Rule:     ruleRef(acnpDropIngress, cpv1beta.DirectionIn, dropAction),
+					Isolated: true,
+					PassedBy: ruleRef(acnpPassIngress, cpv1beta.DirectionIn, passAction),
+				},
+			},
+		},
+		{
+			name:     "acnp-pass-to-k8s",
+			policies: []*antreatypes.NetworkPolicy{acnpPassIngress, securityOpsDropIngress, k8sAllowFromA},
+			query:    podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionAllow,
+				Rule:    ruleRef(k8sAllowFromA, cpv1beta.DirectionIn, allowAction),
+				Egress:  allowed,
+				Ingress: &ReachabilityVerdict{
+					Verdict:  crdv1alpha1.RuleActionAllow,
+					Rule:     ruleRef(k8sAllowFromA, cpv1beta.DirectionIn, allowAction),
+					Isolated: true,
+					PassedBy: ruleRef(acnpPassIngress, cpv1beta.DirectionIn, passAction),
+				},
+			},
+		},
+		{
+			name:     "acnp-pass-to-baseline",
+			policies: []*antreatypes.NetworkPolicy{acnpPassIngress, securityOpsDropIngress, baselineDropIngress},
+			query:    podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionDrop,
+				Rule:    ruleRef(baselineDropIngress, cpv1beta.DirectionIn, dropAction),
+				Egress:  allowed,
+				Ingress: &ReachabilityVerdict{
+					Verdict:  crdv1alpha1.RuleActionDrop,
+					Rule:     ruleRef(baselineDropIngress, cpv1beta.DirectionIn, dropAction),
+					PassedBy: ruleRef(acnpPassIngress, cpv1beta.DirectionIn, passAction),
+				},
+			},
+		},
+		{
+			name:     "audit-rule-ignored",
+			policies: []*antreatypes.NetworkPolicy{acnpAuditIngress},
+			query:    podAToPodB,
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionAllow,
+				Egress:  allowed,
+				Ingress: allowed,
+			},
+		},
+		{
+			name:     "source-ip",
+			policies: []*antreatypes.NetworkPolicy{acnpDropIngress},
+			query: &ReachabilityQuery{
+				Source:      ReachabilityEndpoint{IP: "10.0.0.10"},
+				Destination: podAToPodB.Destination,
+				Port:        80,
+			},
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionDrop,
+				Rule:    ruleRef(acnpDropIngress, cpv1beta.DirectionIn, dropAction),
+				Ingress: &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionDrop, Rule: ruleRef(acnpDropIngress, cpv1beta.DirectionIn, dropAction)},
+			},
+		},
+		{
+			name:     "source-ip-in-except",
+			policies: []*antreatypes.NetworkPolicy{acnpDropIngress},
+			query: &ReachabilityQuery{
+				Source:      ReachabilityEndpoint{IP: "10.0.0.200"},
+				Destination: podAToPodB.Destination,
+				Port:        80,
+			},
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionAllow,
+				Ingress: allowed,
+			},
+		},
+		{
+			name:     "source-ip-of-pod",
+			policies: []*antreatypes.NetworkPolicy{k8sAllowFromA},
+			query: &ReachabilityQuery{
+				Source:      ReachabilityEndpoint{IP: "10.0.0.1"},
+				Destination: podAToPodB.Destination,
+				Port:        80,
+			},
+			expectedResponse: &ReachabilityQueryResponse{
+				Verdict: crdv1alpha1.RuleActionAllow,
+				Rule:    ruleRef(k8sAllowFromA, cpv1beta.DirectionIn, allowAction),
+				Egress:  allowed,
+				Ingress: &ReachabilityVerdict{Verdict: crdv1alpha1.RuleActionAllow, Rule: ruleRef(k8sAllowFromA, cpv1beta.DirectionIn, allowAction), Isolated: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newController()
+			c.informerFactory.Core().V1().Pods().Informer().GetStore().Add(podA)
+			c.informerFactory.Core().V1().Pods().Informer().GetStore().Add(podB)
+			c.appliedToGroupStore.Create(&antreatypes.AppliedToGroup{
+				Name:              "atgA",
+				GroupMemberByNode: map[string]controlplane.GroupMemberSet{"node1": controlplane.NewGroupMemberSet(podToGroupMember(podA, false))},
+			})
+			c.appliedToGroupStore.Create(&antreatypes.AppliedToGroup{
+				Name:              "atgB",
+				GroupMemberByNode: map[string]controlplane.GroupMemberSet{"node1": controlplane.NewGroupMemberSet(podToGroupMember(podB, false))},
+			})
+			c.addressGroupStore.Create(&antreatypes.AddressGroup{
+				Name:         "agA",
+				GroupMembers: controlplane.NewGroupMemberSet(podToGroupMember(podA, true)),
+			})
+			for _, policy := range tt.policies {
+				c.internalNetworkPolicyStore.Create(policy)
+			}
+			querier := NewReachabilityQuerier(c.NetworkPolicyController, c.informerFactory.Core().V1().Pods())
+			response, err := querier.QueryReachability(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResponse, response)
+		})
+	}
+}
+
+func TestQueryReachabilityErrors(t *testing.T) {
+	_, c := newController()
+	querier := NewReachabilityQuerier(c.NetworkPolicyController, c.informerFactory.Core().V1().Pods())
+
+	_, err := querier.QueryReachability(&ReachabilityQuery{
+		Source:      ReachabilityEndpoint{Namespace: "ns1", Pod: "podA"},
+		Destination: ReachabilityEndpoint{IP: "10.0.0.2"},
+	})
+	assert.True(t, errors.IsNotFound(err))
+
+	_, err = querier.QueryReachability(&ReachabilityQuery{
+		Source:      ReachabilityEndpoint{IP: "10.0.0.1"},
+		Destination: ReachabilityEndpoint{IP: "10.0.0.2"},
+	})
+	assert.EqualError(t, err, "at least one of the source and destination must be a Pod")
+
+	_, err = querier.QueryReachability(&ReachabilityQuery{
+		Source:      ReachabilityEndpoint{IP: "10.0.0.a"},
+		Destination: ReachabilityEndpoint{IP: "10.0.0.2"},
+	})
+	assert.EqualError(t, err, "invalid IP address 10.0.0.a")
+}
@@ -0,0 +1,479 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"net"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"antrea.io/antrea/pkg/apis/controlplane"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	"antrea.io/antrea/pkg/apiserver/storage"
+	antreatypes "antrea.io/antrea/pkg/controller/types"
+)
+
+// RuleConflictType describes how a rule is affected by another rule.
+type RuleConflictType string
+
+const (
+	// RuleConflictShadowed means that the rule is fully covered by a rule with a
+	// higher precedence and a different action, so it can never match.
+	RuleConflictShadowed RuleConflictType = "Shadowed"
+	// RuleConflictRedundant means that the rule is fully covered by a rule with a
+	// higher precedence and the same action, so it can be removed.
+	RuleConflictRedundant RuleConflictType = "Redundant"
+	// RuleConflictConflicting means that the rule overlaps with a rule of another
+	// policy with the same Tier and priority but a different action, so the action
+	// applied to the overlapping traffic is not defined by the priorities.
+	RuleConflictConflicting RuleConflictType = "Conflicting"
+)
+
+// RuleConflictQuerier handles requests for antctl rule conflict queries.
+type RuleConflictQuerier interface {
+	QueryRuleConflicts() *RuleConflictQueryResponse
+}
+
+// RuleConflict describes a rule affected by another rule.
+type RuleConflict struct {
+	Type RuleConflictType `json:"type"`
+	// Rule is the affected rule.
+	Rule PolicyRuleRef `json:"rule"`
+	// By is the rule which covers or overlaps Rule.
+	By PolicyRuleRef `json:"by"`
+}
+
+// RuleConflictQueryResponse is the reply struct for QueryRuleConflicts.
+type RuleConflictQueryResponse struct {
+	Conflicts []RuleConflict `json:"conflicts,omitempty"`
+}
+
+// ruleAnalyzer detects the Antrea-native policy rules which are shadowed by,
+// redundant with, or conflicting with other rules, based on the internal
+// NetworkPolicies and the current members of their groups.
+type ruleAnalyzer struct {
+	internalNetworkPolicyStore storage.Interface
+	appliedToGroupStore        storage.Interface
+	addressGroupStore          storage.Interface
+}
+
+// analyzedRule is a rule with its resolved appliedTo and peer members.
+type analyzedRule struct {
+	matchedRule
+	appliedTo   controlplane.GroupMemberSet
+	peer        controlplane.NetworkPolicyPeer
+	peerMembers controlplane.GroupMemberSet
+	// peerMatchesAll indicates that the peer matches all addresses, i.e. it is
+	// empty or it includes the IPv4 and IPv6 Any IPBlocks of matchAllPeer.
+	peerMatchesAll bool
+}
+
+func newRuleAnalyzer(internalNetworkPolicyStore, appliedToGroupStore, addressGroupStore storage.Interface) *ruleAnalyzer {
+	return &ruleAnalyzer{
+		internalNetworkPolicyStore: internalNetworkPolicyStore,
+		appliedToGroupStore:        appliedToGroupStore,
+		addressGroupStore:          addressGroupStore,
+	}
+}
+
+// analyze compares each rule with the rules evaluated before it in the same
+// direction, within and across Tiers. A rule is reported once as Shadowed or
+// Redundant with the first rule covering it. Conflicting rules are reported for
+// both rules of each pair. Rules in Audit mode and rules selecting no member are
+// ignored, and rules with L7 protocols are never considered to cover other rules.
+// A rule with a match-all peer covers the rules with any peer.
+func (a *ruleAnalyzer) analyze() []RuleConflict {
+	rules := a.collectRules()
+	var conflicts []RuleConflict
+	for j, r := range rules {
+		for i := 0; i < j; i++ {
+			h := rules[i]
+			if h.rule.Direction != r.rule.Direction {
+				continue
+			}
+			if h.policy.Name == r.policy.Name && h.rule.Priority == r.rule.Priority {
+				// The rules are generated from the same rule, e.g. a rule applied
+				// per Namespace.
+				continue
+			}
+			if samePrecedence(&h.matchedRule, &r.matchedRule) {
+				if h.action() != r.action() && h.overlaps(r) {
+					conflicts = append(conflicts,
+						RuleConflict{Type: RuleConflictConflicting, Rule: *h.toPolicyRuleRef(), By: *r.toPolicyRuleRef()},
+						RuleConflict{Type: RuleConflictConflicting, Rule: *r.toPolicyRuleRef(), By: *h.toPolicyRuleRef()})
+				}
+				continue
+			}
+			// Pass rules only skip the remaining rules of their Tier.
+			if h.action() == crdv1alpha1.RuleActionPass && *h.policy.TierPriority != *r.policy.TierPriority {
+				continue
+			}
+			if !h.covers(r) {
+				continue
+			}
+			conflictType := RuleConflictShadowed
+			if h.action() == r.action() {
+				conflictType = RuleConflictRedundant
+			}
+			conflicts = append(conflicts, RuleConflict{Type: conflictType, Rule: *r.toPolicyRuleRef(), By: *h.toPolicyRuleRef()})
+			break
+		}
+	}
+	return conflicts
+}
+
+// collectRules returns the rules to analyze, sorted by order of evaluation.
+func (a *ruleAnalyzer) collectRules() []*analyzedRule {
+	var rules []*analyzedRule
+	for _, obj := range a.internalNetworkPolicyStore.List() {
+		policy := obj.(*antreatypes.NetworkPolicy)
+		if policy.SourceRef.Type == controlplane.K8sNetworkPolicy || policy.TierPriority == nil || policy.Priority == nil {
+			continue
+		}
+		indexes := map[controlplane.Direction]int{}
+		for i := range policy.Rules {
+			rule := &policy.Rules[i]
+			index := indexes[rule.Direction]
+			indexes[rule.Direction]++
+			if rule.EnforcementMode == crdv1alpha1.RuleEnforcementModeAudit {
+				continue
+			}
+			r := &analyzedRule{
+				matchedRule: matchedRule{policy: policy, rule: rule, index: index},
+				appliedTo:   a.getAppliedToMembers(policy, rule),
+				peer:        rule.From,
+			}
+			if rule.Direction == controlplane.DirectionOut {
+				r.peer = rule.To
+			}
+			r.peerMembers = a.getAddressGroupMembers(r.peer.AddressGroups)
+			r.peerMatchesAll = isMatchAllPeer(&r.peer)
+			if len(r.appliedTo) == 0 {
+				continue
+			}
+			if !r.peerMatchesAll && len(r.peerMembers) == 0 && len(r.peer.IPBlocks) == 0 && len(r.peer.FQDNs) == 0 {
+				continue
+			}
+			rules = append(rules, r)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return evaluatedBefore(&rules[i].matchedRule, &rules[j].matchedRule)
+	})
+	return rules
+}
+
+func (a *ruleAnalyzer) getAppliedToMembers(policy *antreatypes.NetworkPolicy, rule *controlplane.NetworkPolicyRule) controlplane.GroupMemberSet {
+	appliedToGroups := rule.AppliedToGroups
+	if len(appliedToGroups) == 0 {
+		appliedToGroups = policy.AppliedToGroups
+	}
+	members := controlplane.GroupMemberSet{}
+	for _, name := range appliedToGroups {
+		obj, found, _ := a.appliedToGroupStore.Get(name)
+		if !found {
+			continue
+		}
+		for _, nodeMembers := range obj.(*antreatypes.AppliedToGroup).GroupMemberByNode {
+			members = members.Union(nodeMembers)
+		}
+	}
+	return members
+}
+
+func (a *ruleAnalyzer) getAddressGroupMembers(addressGroups []string) controlplane.GroupMemberSet {
+	members := controlplane.GroupMemberSet{}
+	for _, name := range addressGroups {
+		obj, found, _ := a.addressGroupStore.Get(name)
+		if !found {
+			continue
+		}
+		members = members.Union(obj.(*antreatypes.AddressGroup).GroupMembers)
+	}
+	return members
+}
+
+// isMatchAllPeer returns whether the peer matches all addresses.
+func isMatchAllPeer(peer *controlplane.NetworkPolicyPeer) bool {
+	if len(peer.AddressGroups) == 0 && len(peer.IPBlocks) == 0 && len(peer.FQDNs) == 0 {
+		return true
+	}
+	for _, anyBlock := range matchAllPeer.IPBlocks {
+		found := false
+		for _, ipBlock := range peer.IPBlocks {
+			if len(ipBlock.Except) == 0 && ipNetCovers(ipBlock.CIDR, anyBlock.CIDR) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// samePrecedence returns whether the rules of two different policies have no
+// defined order of evaluation.
+func samePrecedence(a, b *matchedRule) bool {
+	return a.policy.Name != b.policy.Name &&
+		*a.policy.TierPriority == *b.policy.TierPriority &&
+		*a.policy.Priority == *b.policy.Priority
+}
+
+// covers returns whether all the traffic matched by rule r is matched by rule h.
+func (h *analyzedRule) covers(r *analyzedRule) bool {
+	if len(h.rule.L7Protocols) > 0 {
+		return false
+	}
+	return h.appliedTo.IsSuperset(r.appliedTo) && h.peerCovers(r) && servicesCover(h.rule.Services, r.rule.Services)
+}
+
+func (h *analyzedRule) peerCovers(r *analyzedRule) bool {
+	if h.peerMatchesAll {
+		return true
+	}
+	if r.peerMatchesAll {
+		return false
+	}
+	for _, fqdn := range r.peer.FQDNs {
+		if !containsString(h.peer.FQDNs, fqdn) {
+			return false
+		}
+	}
+	for _, member := range r.peerMembers {
+		if h.peerMembers.Has(member) {
+			continue
+		}
+		if len(member.IPs) == 0 {
+			return false
+		}
+		for _, ip := range member.IPs {
+			if !ipBlocksContain(h.peer.IPBlocks, net.IP(ip)) {
+				return false
+			}
+		}
+	}
+	for _, rBlock := range r.peer.IPBlocks {
+		covered := false
+		for _, hBlock := range h.peer.IPBlocks {
+			if ipBlockCovers(hBlock, rBlock) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns whether some traffic may be matched by both rules.
+func (h *analyzedRule) overlaps(r *analyzedRule) bool {
+	appliedToOverlap := false
+	for _, member := range r.appliedTo {
+		if h.appliedTo.Has(member) {
+			appliedToOverlap = true
+			break
+		}
+	}
+	return appliedToOverlap && peersOverlap(h, r) && servicesOverlap(h.rule.Services, r.rule.Services)
+}
+
+func peersOverlap(h, r *analyzedRule) bool {
+	if h.peerMatchesAll || r.peerMatchesAll {
+		return true
+	}
+	for _, member := range r.peerMembers {
+		if h.peerMembers.Has(member) {
+			return true
+		}
+		for _, ip := range member.IPs {
+			if ipBlocksContain(h.peer.IPBlocks, net.IP(ip)) {
+				return true
+			}
+		}
+	}
+	for _, member := range h.peerMembers {
+		for _, ip := range member.IPs {
+			if ipBlocksContain(r.peer.IPBlocks, net.IP(ip)) {
+				return true
+			}
+		}
+	}
+	for _, hBlock := range h.peer.IPBlocks {
+		for _, rBlock := range r.peer.IPBlocks {
+			if ipNetsOverlap(hBlock.CIDR, rBlock.CIDR) {
+				return true
+			}
+		}
+	}
+	for _, fqdn := range r.peer.FQDNs {
+		if containsString(h.peer.FQDNs, fqdn) {
+			return true
+		}
+	}
+	return false
+}
+
+func ipBlocksContain(ipBlocks []controlplane.IPBlock, ip net.IP) bool {
+	for _, ipBlock := range ipBlocks {
+		if ipBlockContains(ipBlock, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipBlockCovers returns whether all the IPs of IPBlock b are in IPBlock a.
+func ipBlockCovers(a, b controlplane.IPBlock) bool {
+	if !ipNetCovers(a.CIDR, b.CIDR) {
+		return false
+	}
+	for _, aExcept := range a.Except {
+		if !ipNetsOverlap(aExcept, b.CIDR) {
+			continue
+		}
+		excluded := false
+		for _, bExcept := range b.Except {
+			if ipNetCovers(bExcept, aExcept) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			return false
+		}
+	}
+	return true
+}
+
+func ipNetCovers(a, b controlplane.IPNet) bool {
+	return a.PrefixLength <= b.PrefixLength && ipNetContains(a, net.IP(b.IP))
+}
+
+func ipNetsOverlap(a, b controlplane.IPNet) bool {
+	return ipNetContains(a, net.IP(b.IP)) || ipNetContains(b, net.IP(a.IP))
+}
+
+// servicesCover returns whether all the traffic matched by services rs is
+// matched by services hs. An empty list of services matches all traffic.
+func servicesCover(hs, rs []controlplane.Service) bool {
+	if len(hs) == 0 {
+		return true
+	}
+	if len(rs) == 0 {
+		return false
+	}
+	for i := range rs {
+		covered := false
+		for j := range hs {
+			if serviceCovers(&hs[j], &rs[i]) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func serviceCovers(h, r *controlplane.Service) bool {
+	if serviceProtocol(h) != serviceProtocol(r) {
+		return false
+	}
+	if h.ICMPType != nil && (r.ICMPType == nil || *r.ICMPType != *h.ICMPType) {
+		return false
+	}
+	if h.ICMPCode != nil && (r.ICMPCode == nil || *r.ICMPCode != *h.ICMPCode) {
+		return false
+	}
+	if h.Port == nil {
+		return true
+	}
+	if r.Port == nil {
+		return false
+	}
+	if h.Port.Type == intstr.String || r.Port.Type == intstr.String {
+		return h.Port.Type == r.Port.Type && h.Port.StrVal == r.Port.StrVal
+	}
+	hStart, hEnd := servicePortRange(h)
+	rStart, rEnd := servicePortRange(r)
+	return hStart <= rStart && rEnd <= hEnd
+}
+
+// servicesOverlap returns whether some traffic may be matched by both services
+// hs and rs. Named ports are only considered to overlap with the same name.
+func servicesOverlap(hs, rs []controlplane.Service) bool {
+	if len(hs) == 0 || len(rs) == 0 {
+		return true
+	}
+	for i := range hs {
+		for j := range rs {
+			if serviceOverlaps(&hs[i], &rs[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func serviceOverlaps(h, r *controlplane.Service) bool {
+	if serviceProtocol(h) != serviceProtocol(r) {
+		return false
+	}
+	if h.ICMPType != nil && r.ICMPType != nil && *h.ICMPType != *r.ICMPType {
+		return false
+	}
+	if h.ICMPCode != nil && r.ICMPCode != nil && *h.ICMPCode != *r.ICMPCode {
+		return false
+	}
+	if h.Port == nil || r.Port == nil {
+		return true
+	}
+	if h.Port.Type == intstr.String || r.Port.Type == intstr.String {
+		return h.Port.Type == r.Port.Type && h.Port.StrVal == r.Port.StrVal
+	}
+	hStart, hEnd := servicePortRange(h)
+	rStart, rEnd := servicePortRange(r)
+	return hStart <= rEnd && rStart <= hEnd
+}
+
+func serviceProtocol(service *controlplane.Service) controlplane.Protocol {
+	if service.Protocol == nil {
+		return controlplane.ProtocolTCP
+	}
+	return *service.Protocol
+}
+
+func servicePortRange(service *controlplane.Service) (int32, int32) {
+	if service.EndPort == nil {
+		return service.Port.IntVal, service.Port.IntVal
+	}
+	return service.Port.IntVal, *service.EndPort
+}
+
+func containsString(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"antrea.io/antrea/pkg/apis/controlplane"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	"antrea.io/antrea/pkg/controller/networkpolicy/store"
+	antreatypes "antrea.io/antrea/pkg/controller/types"
+)
+
+func newTestRuleAnalyzer() *ruleAnalyzer {
+	podA := newReachabilityTestPod("ns1", "podA", "10.0.0.1", 8080)
+	podB := newReachabilityTestPod("ns1", "podB", "10.0.0.2", 80)
+	appliedToGroupStore := store.NewAppliedToGroupStore()
+	appliedToGroupStore.Create(&antreatypes.AppliedToGroup{
+		Name:              "atgB",
+		GroupMemberByNode: map[string]controlplane.GroupMemberSet{"node1": controlplane.NewGroupMemberSet(podToGroupMember(podB, false))},
+	})
+	appliedToGroupStore.Create(&antreatypes.AppliedToGroup{
+		Name:              "atgAB",
+		GroupMemberByNode: map[string]controlplane.GroupMemberSet{"node1": controlplane.NewGroupMemberSet(podToGroupMember(podA, false), podToGroupMember(podB, false))},
+	})
+	addressGroupStore := store.NewAddressGroupStore()
+	addressGroupStore.Create(&antreatypes.AddressGroup{
+		Name:         "agA",
+		GroupMembers: controlplane.NewGroupMemberSet(podToGroupMember(podA, true)),
+	})
+	return newRuleAnalyzer(store.NewNetworkPolicyStore(), appliedToGroupStore, addressGroupStore)
+}
+
+func TestAnalyzeRules(t *testing.T) {
+	appTierPriority, baselineTierPriority := DefaultTierPriority, BaselineTierPriority
+	securityOpsTierPriority := int32(100)
+	priority1, priority2 := float64(1), float64(2)
+	tcp := controlplane.ProtocolTCP
+	port80, port81, port8080, portHTTP := intstr.FromInt(80), intstr.FromInt(81), intstr.FromInt(8080), intstr.FromString("http")
+	endPort90 := int32(90)
+	allowAction, dropAction, passAction := crdv1alpha1.RuleActionAllow, crdv1alpha1.RuleActionDrop, crdv1alpha1.RuleActionPass
+	peerA := controlplane.NetworkPolicyPeer{AddressGroups: []string{"agA"}}
+	ipBlockPeer := controlplane.NetworkPolicyPeer{IPBlocks: []controlplane.IPBlock{{
+		CIDR: controlplane.IPNet{IP: ipStrToIPAddress("10.0.0.0"), PrefixLength: 24},
+	}}}
+	ipBlockExceptPeer := controlplane.NetworkPolicyPeer{IPBlocks: []controlplane.IPBlock{{
+		CIDR:   controlplane.IPNet{IP: ipStrToIPAddress("10.0.0.0"), PrefixLength: 16},
+		Except: []controlplane.IPNet{{IP: ipStrToIPAddress("10.0.0.0"), PrefixLength: 25}},
+	}}}
+	services80 := []controlplane.Service{{Protocol: &tcp, Port: &port80}}
+
+	ingressRule := func(name string, peer controlplane.NetworkPolicyPeer, services []controlplane.Service, action *crdv1alpha1.RuleAction, rulePriority int32) controlplane.NetworkPolicyRule {
+		return controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, Name: name, From: peer, Services: services, Action: action, Priority: rulePriority}
+	}
+	newPolicy := func(name string, tierPriority *int32, priority *float64, appliedToGroup string, rules ...controlplane.NetworkPolicyRule) *antreatypes.NetworkPolicy {
+		return newReachabilityTestPolicy(name, controlplane.AntreaClusterNetworkPolicy, tierPriority, priority, appliedToGroup, rules...)
+	}
+	ruleRef := func(policy *antreatypes.NetworkPolicy, index int) PolicyRuleRef {
+		m := &matchedRule{policy: policy, rule: &policy.Rules[index], index: index}
+		return *m.toPolicyRuleRef()
+	}
+
+	dropFromBlock := newPolicy("drop-from-block", &securityOpsTierPriority, &priority1, "atgAB",
+		ingressRule("drop-from-block", ipBlockPeer, nil, &dropAction, 0))
+	dropFromA := newPolicy("drop-from-a", &appTierPriority, &priority1, "atgB",
+		ingressRule("drop-from-a", peerA, services80, &dropAction, 0))
+	allowFromA := newPolicy("allow-from-a", &appTierPriority, &priority1, "atgB",
+		ingressRule("allow-from-a", peerA, nil, &allowAction, 0))
+	allowThenDrop := newPolicy("allow-then-drop", &appTierPriority, &priority1, "atgB",
+		ingressRule("allow-port-range", peerA, []controlplane.Service{{Protocol: &tcp, Port: &port80, EndPort: &endPort90}}, &allowAction, 0),
+		ingressRule("drop-80", peerA, services80, &dropAction, 1),
+		ingressRule("drop-8080", peerA, []controlplane.Service{{Protocol: &tcp, Port: &port8080}}, &dropAction, 2))
+	dropFromExcept := newPolicy("drop-from-except", &securityOpsTierPriority, &priority1, "atgAB",
+		ingressRule("drop-from-except", ipBlockExceptPeer, nil, &dropAction, 0))
+	passFromA := newPolicy("pass-from-a", &securityOpsTierPriority, &priority1, "atgAB",
+		ingressRule("pass-from-a", peerA, nil, &passAction, 0))
+	baselineDropFromA := newPolicy("baseline-drop-from-a", &baselineTierPriority, &priority1, "atgB",
+		ingressRule("baseline-drop-from-a", peerA, nil, &dropAction, 0))
+	appDropFromA := newPolicy("app-drop-from-a", &appTierPriority, &priority2, "atgB",
+		ingressRule("app-drop-from-a", peerA, nil, &dropAction, 0))
+	securityOpsDropFromA := newPolicy("securityops-drop-from-a", &securityOpsTierPriority, &priority2, "atgB",
+		ingressRule("securityops-drop-from-a", peerA, nil, &dropAction, 0))
+	baselineDropFromAToAll := newPolicy("baseline-drop-from-a-to-all", &baselineTierPriority, &priority1, "atgAB",
+		ingressRule("baseline-drop-from-a-to-all", peerA, nil, &dropAction, 0))
+	auditDropFromBlock := newPolicy("audit-drop-from-block", &securityOpsTierPriority, &priority1, "atgAB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, From: ipBlockPeer, Action: &dropAction, EnforcementMode: crdv1alpha1.RuleEnforcementModeAudit})
+	l7AllowFromA := newPolicy("l7-allow-from-a", &securityOpsTierPriority, &priority1, "atgAB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, From: peerA, Action: &allowAction, L7Protocols: []controlplane.L7Protocol{{HTTP: &controlplane.HTTPProtocol{}}}})
+	allowNamedPort := newPolicy("allow-named-port", &appTierPriority, &priority1, "atgB",
+		ingressRule("allow-named-port", peerA, []controlplane.Service{{Protocol: &tcp, Port: &portHTTP}}, &allowAction, 0))
+	dropFromAll := newPolicy("drop-from-all", &securityOpsTierPriority, &priority1, "atgAB",
+		ingressRule("drop-from-all", controlplane.NetworkPolicyPeer{}, nil, &dropAction, 0))
+	allowFromAll := newPolicy("allow-from-all", &appTierPriority, &priority1, "atgB",
+		ingressRule("allow-from-all", matchAllPeer, services80, &allowAction, 0))
+	dropPort81 := newPolicy("drop-port-81", &appTierPriority, &priority1, "atgB",
+		ingressRule("drop-port-81", peerA, []controlplane.Service{{Protocol: &tcp, Port: &port81}}, &dropAction, 0))
+
+	tests := []struct {
+		name              string
+		policies          []*antreatypes.NetworkPolicy
+		expectedConflicts []RuleConflict
+	}{
+		{
+			name:     "redundant-across-tiers",
+			policies: []*antreatypes.NetworkPolicy{dropFromBlock, dropFromA},
+			expectedConflicts: []RuleConflict{
+				{Type: RuleConflictRedundant, Rule: ruleRef(dropFromA, 0), By: ruleRef(dropFromBlock, 0)},
+			},
+		},
+		{
+			name:     "shadowed-within-policy",
+			policies: []*antreatypes.NetworkPolicy{allowThenDrop},
+			expectedConflicts: []RuleConflict{
+				{Type: RuleConflictShadowed, Rule: ruleRef(allowThenDrop, 1), By: ruleRef(allowThenDrop, 0)},
+			},
+		},
+		{
+			name:     "ipblock-except",
+			policies: []*antreatypes.NetworkPolicy{dropFromExcept, dropFromA},
+		},
+		{
+			name:     "conflicting",
+			policies: []*antreatypes.NetworkPolicy{allowFromA, dropFromA},
+			expectedConflicts: []RuleConflict{
+				{Type: RuleConflictConflicting, Rule: ruleRef(allowFromA, 0), By: ruleRef(dropFromA, 0)},
+				{Type: RuleConflictConflicting, Rule: ruleRef(dropFromA, 0), By: ruleRef(allowFromA, 0)},
+			},
+		},
+		{
+			name:     "conflicting-named-port",
+			policies: []*antreatypes.NetworkPolicy{allowNamedPort, dropPort81},
+		},
+		{
+			name:     "pass-does-not-cover-baseline",
+			policies: []*antreatypes.NetworkPolicy{passFromA, baselineDropFromA},
+		},
+		{
+			name:     "shadowed-by-pass",
+			policies: []*antreatypes.NetworkPolicy{passFromA, securityOpsDropFromA},
+			expectedConflicts: []RuleConflict{
+				{Type: RuleConflictShadowed, Rule: ruleRef(securityOpsDropFromA, 0), By: ruleRef(passFromA, 0)},
+			},
+		},
+		{
+			name:     "lower-tier-not-shadowed-by-pass",
+			policies: []*antreatypes.NetworkPolicy{passFromA, appDropFromA},
+		},
+		{
+			name:     "audit-and-l7-ignored",
+			policies: []*antreatypes.NetworkPolicy{auditDropFromBlock, l7AllowFromA, dropFromA},
+		},
+		{
+			name:     "shadowed-by-match-all",
+			policies: []*antreatypes.NetworkPolicy{dropFromAll, allowFromA, allowFromAll},
+			expectedConflicts: []RuleConflict{
+				{Type: RuleConflictShadowed, Rule: ruleRef(allowFromA, 0), By: ruleRef(dropFromAll, 0)},
+				{Type: RuleConflictShadowed, Rule: ruleRef(allowFromAll, 0), By: ruleRef(dropFromAll, 0)},
+			},
+		},
+		{
+			name:     "match-all-not-covered",
+			policies: []*antreatypes.NetworkPolicy{dropFromBlock, allowFromAll},
+		},
+		{
+			name:     "conflicting-with-match-all",
+			policies: []*antreatypes.NetworkPolicy{allowFromAll, dropFromA},
+			expectedConflicts: []RuleConflict{
+				{Type: RuleConflictConflicting, Rule: ruleRef(allowFromAll, 0), By: ruleRef(dropFromA, 0)},
+				{Type: RuleConflictConflicting, Rule: ruleRef(dropFromA, 0), By: ruleRef(allowFromAll, 0)},
+			},
+		},
+		{
+			name:     "appliedto-not-covered",
+			policies: []*antreatypes.NetworkPolicy{appDropFromA, baselineDropFromAToAll},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestRuleAnalyzer()
+			for _, policy := range tt.policies {
+				a.internalNetworkPolicyStore.Create(policy)
+			}
+			assert.Equal(t, tt.expectedConflicts, a.analyze())
+		})
+	}
+}
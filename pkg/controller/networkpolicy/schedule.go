@@ -0,0 +1,386 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"antrea.io/antrea/pkg/apis/controlplane"
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+	antreatypes "antrea.io/antrea/pkg/controller/types"
+)
+
+const (
+	// maxScheduleSearch bounds the search for the next time matching a cron
+	// schedule, so that schedules which can never match (e.g. "0 0 31 2 *")
+	// don't loop forever.
+	maxScheduleSearch = 5 * 366 * 24 * time.Hour
+	// maxOverlappingWindows bounds the number of consecutive overlapping
+	// occurrences of a scheduled window that are merged when computing when
+	// the window closes.
+	maxOverlappingWindows = 1000
+)
+
+// cronSchedule is a parsed 5-field cron expression. Each field is a bitset of
+// the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record whether the day-of-month and day-of-week
+	// fields are unrestricted. As in cron, when both are restricted a day
+	// matches if either of them matches.
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day-of-month", 1, 31},
+	{"month", 1, 12},
+	{"day-of-week", 0, 7},
+}
+
+// parseCronSchedule parses a cron expression in the standard 5-field format
+// ("minute hour day-of-month month day-of-week"). Each field is a comma-separated
+// list of "*", "N" or "N-M", optionally followed by "/STEP". Both 0 and 7 mean
+// Sunday in the day-of-week field.
+func parseCronSchedule(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected %d fields in schedule %q, got %d", len(cronFields), spec, len(fields))
+	}
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+	// Sunday can be written as both 0 and 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", field.name, part)
+			}
+		}
+		start, end := field.min, field.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value in %s field %q", field.name, part)
+			}
+			end = start
+			if step > 1 {
+				// "N/STEP" means "N-MAX/STEP".
+				end = field.max
+			}
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value in %s field %q", field.name, part)
+				}
+			}
+		}
+		if start < field.min || end > field.max || start > end {
+			return 0, fmt.Errorf("%s field %q out of range [%d, %d]", field.name, part, field.min, field.max)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first time strictly after t matching the schedule in the
+// provided location, or the zero time if there is none within maxScheduleSearch.
+// The returned time is in UTC.
+func (s *cronSchedule) next(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxScheduleSearch)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			// Truncating to the hour is not used as some locations have offsets
+			// which are not a whole number of hours.
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t.UTC()
+	}
+	return time.Time{}
+}
+
+// loadScheduleLocation returns the location in which the schedule of an
+// ActivationWindow is evaluated, which is UTC if timeZone is empty.
+func loadScheduleLocation(timeZone string) (*time.Location, error) {
+	if timeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+	}
+	return loc, nil
+}
+
+// windowState returns whether the ActivationWindow is open at now, and the
+// next time after now at which that changes, which is the zero time if it
+// never changes.
+func windowState(window *crdv1alpha1.ActivationWindow, now time.Time) (bool, time.Time, error) {
+	if window.Schedule == "" {
+		if window.Start != nil && now.Before(window.Start.Time) {
+			return false, window.Start.Time, nil
+		}
+		if window.End != nil {
+			if now.Before(window.End.Time) {
+				return true, window.End.Time, nil
+			}
+			return false, time.Time{}, nil
+		}
+		return true, time.Time{}, nil
+	}
+	schedule, err := parseCronSchedule(window.Schedule)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	loc, err := loadScheduleLocation(window.TimeZone)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	var duration time.Duration
+	if window.Duration != nil {
+		duration = window.Duration.Duration
+	}
+	// The window is open if it was last opened less than duration ago, i.e.
+	// if there is a scheduled time in (now-duration, now].
+	start := schedule.next(now.Add(-duration), loc)
+	if start.IsZero() {
+		return false, time.Time{}, nil
+	}
+	if start.After(now) {
+		return false, start, nil
+	}
+	// Merge the following occurrences which open before the current one closes.
+	end := start.Add(duration)
+	for i := 0; i < maxOverlappingWindows; i++ {
+		nextStart := schedule.next(start, loc)
+		if nextStart.IsZero() || nextStart.After(end) {
+			break
+		}
+		start, end = nextStart, nextStart.Add(duration)
+	}
+	return true, end, nil
+}
+
+// activationState returns whether a policy with the provided ActivationWindows
+// should be enforced at now, and the next time after now at which that needs
+// to be evaluated again, which is the zero time if it never changes. A policy
+// without ActivationWindows is always active.
+func activationState(windows []crdv1alpha1.ActivationWindow, now time.Time) (bool, time.Time) {
+	if len(windows) == 0 {
+		return true, time.Time{}
+	}
+	active := false
+	var nextTransition time.Time
+	for i := range windows {
+		open, transition, err := windowState(&windows[i], now)
+		if err != nil {
+			klog.Errorf("Invalid activation window: %v", err)
+			continue
+		}
+		active = active || open
+		if !transition.IsZero() && (nextTransition.IsZero() || transition.Before(nextTransition)) {
+			nextTransition = transition
+		}
+	}
+	return active, nextTransition
+}
+
+// isPolicyActive returns whether a policy with the provided ActivationWindows
+// should currently be present in the internalNetworkPolicyStore.
+func isPolicyActive(windows []crdv1alpha1.ActivationWindow) bool {
+	active, _ := activationState(windows, time.Now())
+	return active
+}
+
+// enqueuePolicySchedule enqueues the reference of an Antrea-native policy so
+// that its active state is evaluated. Policies without ActivationWindows are
+// always active and are not tracked.
+func (n *NetworkPolicyController) enqueuePolicySchedule(ref controlplane.NetworkPolicyReference, windows []crdv1alpha1.ActivationWindow) {
+	if len(windows) == 0 {
+		return
+	}
+	n.policyScheduleQueue.Add(ref)
+}
+
+func (n *NetworkPolicyController) policyScheduleWorker() {
+	for n.processNextPolicyScheduleWorkItem() {
+	}
+}
+
+func (n *NetworkPolicyController) processNextPolicyScheduleWorkItem() bool {
+	key, quit := n.policyScheduleQueue.Get()
+	if quit {
+		return false
+	}
+	defer n.policyScheduleQueue.Done(key)
+
+	ref := key.(controlplane.NetworkPolicyReference)
+	err := n.syncPolicySchedule(ref)
+	if err != nil {
+		n.policyScheduleQueue.AddRateLimited(key)
+		klog.Errorf("Failed to sync schedule of %s: %v", ref.ToString(), err)
+		return true
+	}
+	n.policyScheduleQueue.Forget(key)
+	return true
+}
+
+// syncPolicySchedule evaluates the ActivationWindows of an Antrea-native policy.
+// It adds the internal NetworkPolicy to the internalNetworkPolicyStore when the
+// policy becomes active, removes it when the policy becomes inactive, reports
+// the inactive state in the policy status, and requeues the policy for the next
+// time its active state may change.
+func (n *NetworkPolicyController) syncPolicySchedule(ref controlplane.NetworkPolicyReference) error {
+	now := time.Now()
+	var obj metav1.Object
+	var windows []crdv1alpha1.ActivationWindow
+	switch ref.Type {
+	case controlplane.AntreaNetworkPolicy:
+		anp, err := n.anpLister.NetworkPolicies(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			// The policy has been deleted.
+			return nil
+		}
+		obj, windows = anp, anp.Spec.ActivationWindows
+	case controlplane.AntreaClusterNetworkPolicy:
+		cnp, err := n.cnpLister.Get(ref.Name)
+		if err != nil {
+			return nil
+		}
+		obj, windows = cnp, cnp.Spec.ActivationWindows
+	default:
+		return nil
+	}
+	// The policy has been recreated, the new one is tracked separately.
+	if obj.GetUID() != ref.UID {
+		return nil
+	}
+	active, nextTransition := activationState(windows, now)
+	_, exists, _ := n.internalNetworkPolicyStore.Get(internalNetworkPolicyKeyFunc(obj))
+	if active != exists {
+		klog.Infof("Active state of %s changed to %t according to its activation windows", ref.ToString(), active)
+		// The update handlers add or remove the internal NetworkPolicy according
+		// to the active state of the policy.
+		switch ref.Type {
+		case controlplane.AntreaNetworkPolicy:
+			n.updateANP(obj, obj)
+		case controlplane.AntreaClusterNetworkPolicy:
+			n.updateCNP(obj, obj)
+		}
+	}
+	if !nextTransition.IsZero() {
+		n.policyScheduleQueue.AddAfter(ref, nextTransition.Sub(now))
+	}
+	// The status of active policies is maintained by the StatusController.
+	if active {
+		return nil
+	}
+	inactiveStatus := &crdv1alpha1.NetworkPolicyStatus{
+		Phase:              crdv1alpha1.NetworkPolicyInactive,
+		ObservedGeneration: obj.GetGeneration(),
+	}
+	if ref.Type == controlplane.AntreaNetworkPolicy {
+		return n.statusControl.UpdateAntreaNetworkPolicyStatus(ref.Namespace, ref.Name, inactiveStatus)
+	}
+	return n.statusControl.UpdateAntreaClusterNetworkPolicyStatus(ref.Name, inactiveStatus)
+}
+
+// deleteInternalNetworkPolicy deletes the internal NetworkPolicy with the
+// provided key if it exists, as well as the AppliedToGroups and AddressGroups
+// which are no longer referenced.
+func (n *NetworkPolicyController) deleteInternalNetworkPolicy(key string) {
+	// Lock access to internal NetworkPolicy store so that concurrent reprocessCNP
+	// calls will not re-process and add a CNP that has already been deleted.
+	n.internalNetworkPolicyMutex.Lock()
+	oldInternalNPObj, exists, _ := n.internalNetworkPolicyStore.Get(key)
+	if !exists {
+		n.internalNetworkPolicyMutex.Unlock()
+		return
+	}
+	oldInternalNP := oldInternalNPObj.(*antreatypes.NetworkPolicy)
+	klog.V(2).Infof("Deleting internal NetworkPolicy %s for %s", oldInternalNP.Name, oldInternalNP.SourceRef.ToString())
+	err := n.internalNetworkPolicyStore.Delete(key)
+	n.internalNetworkPolicyMutex.Unlock()
+	if err != nil {
+		klog.Errorf("Error deleting internal NetworkPolicy %s for %s: %v", key, oldInternalNP.SourceRef.ToString(), err)
+		return
+	}
+	for _, atg := range oldInternalNP.AppliedToGroups {
+		n.deleteDereferencedAppliedToGroup(atg)
+	}
+	n.deleteDereferencedAddressGroups(oldInternalNP)
+}
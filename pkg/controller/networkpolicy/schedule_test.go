@@ -0,0 +1,305 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	ts, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return ts
+}
+
+func TestParseCronSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedule  string
+		expectErr bool
+	}{
+		{name: "every-minute", schedule: "* * * * *"},
+		{name: "lists-ranges-steps", schedule: "0,30 9-17/2 1 */3 1-5"},
+		{name: "sunday-as-7", schedule: "0 0 * * 7"},
+		{name: "too-few-fields", schedule: "0 0 * *", expectErr: true},
+		{name: "out-of-range", schedule: "60 0 * * *", expectErr: true},
+		{name: "inverted-range", schedule: "0 10-2 * * *", expectErr: true},
+		{name: "invalid-step", schedule: "*/0 * * * *", expectErr: true},
+		{name: "invalid-value", schedule: "0 0 L * *", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCronSchedule(tt.schedule)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		timeZone string
+		after    string
+		expected string
+	}{
+		{
+			name:     "same-day",
+			schedule: "30 22 * * *",
+			after:    "2021-06-01T10:15:00Z",
+			expected: "2021-06-01T22:30:00Z",
+		},
+		{
+			name:     "next-day",
+			schedule: "30 22 * * *",
+			after:    "2021-06-01T22:30:00Z",
+			expected: "2021-06-02T22:30:00Z",
+		},
+		{
+			name:     "weekday",
+			schedule: "0 2 * * 6",
+			after:    "2021-06-01T10:15:00Z",
+			expected: "2021-06-05T02:00:00Z",
+		},
+		{
+			name:     "sunday-as-7",
+			schedule: "0 2 * * 7",
+			after:    "2021-06-01T10:15:00Z",
+			expected: "2021-06-06T02:00:00Z",
+		},
+		{
+			name:     "day-of-month-or-day-of-week",
+			schedule: "0 0 15 * 1",
+			after:    "2021-06-08T00:00:00Z",
+			expected: "2021-06-14T00:00:00Z",
+		},
+		{
+			name:     "next-year",
+			schedule: "0 0 1 1 *",
+			after:    "2021-06-01T00:00:00Z",
+			expected: "2022-01-01T00:00:00Z",
+		},
+		{
+			name:     "step-from-value",
+			schedule: "10/20 * * * *",
+			after:    "2021-06-01T10:31:00Z",
+			expected: "2021-06-01T10:50:00Z",
+		},
+		{
+			name:     "never",
+			schedule: "0 0 31 2 *",
+			after:    "2021-06-01T00:00:00Z",
+		},
+		{
+			name:     "time-zone",
+			schedule: "0 2 * * 6",
+			timeZone: "America/New_York",
+			after:    "2021-06-01T10:15:00Z",
+			expected: "2021-06-05T06:00:00Z",
+		},
+		{
+			name:     "time-zone-with-half-hour-offset",
+			schedule: "0 9 * * *",
+			timeZone: "Asia/Kolkata",
+			after:    "2021-06-01T04:00:00Z",
+			expected: "2021-06-02T03:30:00Z",
+		},
+		{
+			name:     "time-zone-daylight-saving-time",
+			schedule: "0 2 * * 6",
+			timeZone: "America/New_York",
+			after:    "2021-11-07T12:00:00Z",
+			expected: "2021-11-13T07:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCronSchedule(tt.schedule)
+			require.NoError(t, err)
+			loc, err := loadScheduleLocation(tt.timeZone)
+			require.NoError(t, err)
+			next := schedule.next(mustParseTime(t, tt.after), loc)
+			if tt.expected == "" {
+				assert.True(t, next.IsZero())
+			} else {
+				assert.Equal(t, mustParseTime(t, tt.expected), next)
+			}
+		})
+	}
+}
+
+func TestActivationState(t *testing.T) {
+	start := metav1.NewTime(mustParseTime(t, "2021-06-01T00:00:00Z"))
+	end := metav1.NewTime(mustParseTime(t, "2021-06-02T00:00:00Z"))
+	twoHours := &metav1.Duration{Duration: 2 * time.Hour}
+	tests := []struct {
+		name               string
+		windows            []crdv1alpha1.ActivationWindow
+		now                string
+		expectedActive     bool
+		expectedTransition string
+	}{
+		{
+			name:           "no-window",
+			now:            "2021-06-01T12:00:00Z",
+			expectedActive: true,
+		},
+		{
+			name:               "before-start",
+			windows:            []crdv1alpha1.ActivationWindow{{Start: &start, End: &end}},
+			now:                "2021-05-31T12:00:00Z",
+			expectedActive:     false,
+			expectedTransition: "2021-06-01T00:00:00Z",
+		},
+		{
+			name:               "between-start-and-end",
+			windows:            []crdv1alpha1.ActivationWindow{{Start: &start, End: &end}},
+			now:                "2021-06-01T00:00:00Z",
+			expectedActive:     true,
+			expectedTransition: "2021-06-02T00:00:00Z",
+		},
+		{
+			name:           "after-end",
+			windows:        []crdv1alpha1.ActivationWindow{{Start: &start, End: &end}},
+			now:            "2021-06-02T00:00:00Z",
+			expectedActive: false,
+		},
+		{
+			name:           "start-only",
+			windows:        []crdv1alpha1.ActivationWindow{{Start: &start}},
+			now:            "2021-07-01T00:00:00Z",
+			expectedActive: true,
+		},
+		{
+			name:               "scheduled-open",
+			windows:            []crdv1alpha1.ActivationWindow{{Schedule: "0 22 * * *", Duration: twoHours}},
+			now:                "2021-06-01T23:59:00Z",
+			expectedActive:     true,
+			expectedTransition: "2021-06-02T00:00:00Z",
+		},
+		{
+			name:               "scheduled-closed",
+			windows:            []crdv1alpha1.ActivationWindow{{Schedule: "0 22 * * *", Duration: twoHours}},
+			now:                "2021-06-02T00:00:00Z",
+			expectedActive:     false,
+			expectedTransition: "2021-06-02T22:00:00Z",
+		},
+		{
+			name:           "scheduled-overlapping",
+			windows:        []crdv1alpha1.ActivationWindow{{Schedule: "0 * * * *", Duration: twoHours}},
+			now:            "2021-06-01T10:30:00Z",
+			expectedActive: true,
+			// Merging stops after maxOverlappingWindows occurrences.
+			expectedTransition: "2021-07-13T03:00:00Z",
+		},
+		{
+			name: "multiple-windows",
+			windows: []crdv1alpha1.ActivationWindow{
+				{Start: &start, End: &end},
+				{Schedule: "0 22 * * *", Duration: twoHours},
+			},
+			now:                "2021-05-31T12:00:00Z",
+			expectedActive:     false,
+			expectedTransition: "2021-05-31T22:00:00Z",
+		},
+		{
+			name:               "scheduled-in-time-zone",
+			windows:            []crdv1alpha1.ActivationWindow{{Schedule: "0 22 * * *", Duration: twoHours, TimeZone: "Europe/Paris"}},
+			now:                "2021-06-01T20:30:00Z",
+			expectedActive:     true,
+			expectedTransition: "2021-06-01T22:00:00Z",
+		},
+		{
+			name:           "invalid-time-zone",
+			windows:        []crdv1alpha1.ActivationWindow{{Schedule: "0 22 * * *", Duration: twoHours, TimeZone: "Mars/Olympus_Mons"}},
+			now:            "2021-06-01T20:30:00Z",
+			expectedActive: false,
+		},
+		{
+			name:           "invalid-schedule",
+			windows:        []crdv1alpha1.ActivationWindow{{Schedule: "0 22 * *", Duration: twoHours}},
+			now:            "2021-06-01T23:00:00Z",
+			expectedActive: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			active, transition := activationState(tt.windows, mustParseTime(t, tt.now))
+			assert.Equal(t, tt.expectedActive, active)
+			if tt.expectedTransition == "" {
+				assert.True(t, transition.IsZero())
+			} else {
+				assert.Equal(t, mustParseTime(t, tt.expectedTransition), transition)
+			}
+		})
+	}
+}
+
+func TestSyncPolicySchedule(t *testing.T) {
+	_, npc := newController()
+	statusControl := &fakeNetworkPolicyControl{}
+	npc.statusControl = statusControl
+	anpStore := npc.crdInformerFactory.Crd().V1alpha1().NetworkPolicies().Informer().GetStore()
+
+	anp := getANP()
+	anp.UID = "uidA"
+	anp.Generation = 1
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	anp.Spec.ActivationWindows = []crdv1alpha1.ActivationWindow{{Start: &future}}
+	anpStore.Add(anp)
+	key := internalNetworkPolicyKeyFunc(anp)
+	ref := *getANPReference(anp)
+
+	// The policy is not active yet.
+	npc.addANP(anp)
+	_, found, _ := npc.internalNetworkPolicyStore.Get(key)
+	assert.False(t, found, "expected inactive policy not to be in the store")
+	assert.Equal(t, 1, npc.policyScheduleQueue.Len())
+	require.NoError(t, npc.syncPolicySchedule(ref))
+	assert.Equal(t, &crdv1alpha1.NetworkPolicyStatus{Phase: crdv1alpha1.NetworkPolicyInactive, ObservedGeneration: 1}, statusControl.getAntreaNetworkPolicyStatus())
+
+	// The policy becomes active.
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	anp = anp.DeepCopy()
+	anp.Spec.ActivationWindows = []crdv1alpha1.ActivationWindow{{Start: &past, End: &future}}
+	anpStore.Update(anp)
+	require.NoError(t, npc.syncPolicySchedule(ref))
+	_, found, _ = npc.internalNetworkPolicyStore.Get(key)
+	assert.True(t, found, "expected active policy to be in the store")
+	assert.Len(t, npc.appliedToGroupStore.List(), 1)
+
+	// The policy becomes inactive again.
+	anp = anp.DeepCopy()
+	anp.Generation = 2
+	anp.Spec.ActivationWindows = []crdv1alpha1.ActivationWindow{{Start: &past, End: &past}}
+	anpStore.Update(anp)
+	require.NoError(t, npc.syncPolicySchedule(ref))
+	_, found, _ = npc.internalNetworkPolicyStore.Get(key)
+	assert.False(t, found, "expected inactive policy to be removed from the store")
+	assert.Len(t, npc.appliedToGroupStore.List(), 0)
+	assert.Len(t, npc.addressGroupStore.List(), 0)
+	assert.Equal(t, &crdv1alpha1.NetworkPolicyStatus{Phase: crdv1alpha1.NetworkPolicyInactive, ObservedGeneration: 2}, statusControl.getAntreaNetworkPolicyStatus())
+}
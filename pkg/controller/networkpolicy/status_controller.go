@@ -16,9 +16,15 @@ package networkpolicy
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -29,6 +35,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"antrea.io/antrea/pkg/apis/controlplane"
+	cpv1beta "antrea.io/antrea/pkg/apis/controlplane/v1beta2"
 	crdv1alpha1 "antrea.io/antrea/pkg/apis/crd/v1alpha1"
 	"antrea.io/antrea/pkg/apiserver/storage"
 	antreaclientset "antrea.io/antrea/pkg/client/clientset/versioned"
@@ -40,6 +47,8 @@ import (
 
 const (
 	statusControllerName = "NetworkPolicyStatusController"
+	// ruleConflictsSyncPeriod is the interval at which the rules of Antrea-native policies are analyzed.
+	ruleConflictsSyncPeriod = 60 * time.Second
 )
 
 // StatusController is responsible for synchronizing the status of Antrea ClusterNetworkPolicy and Antrea NetworkPolicy.
@@ -59,6 +68,13 @@ type StatusController struct {
 	statuses     map[string]map[string]*controlplane.NetworkPolicyNodeStatus
 	statusesLock sync.RWMutex
 
+	// ruleAnalyzer detects shadowed, redundant and conflicting rules.
+	ruleAnalyzer *ruleAnalyzer
+	// ruleConflicts keeps the result of the last rule analysis. The map's keys are the NetworkPolicy keys, the values
+	// are the conflicts of the rules of each NetworkPolicy.
+	ruleConflicts     map[string][]RuleConflict
+	ruleConflictsLock sync.RWMutex
+
 	// cnpLister is able to list/get ClusterNetworkPolicies and is populated by the shared informer passed to
 	// NewClusterNetworkPolicyController.
 	cnpLister crdlisters.ClusterNetworkPolicyLister
@@ -71,7 +87,7 @@ type StatusController struct {
 	anpListerSynced cache.InformerSynced
 }
 
-func NewStatusController(antreaClient antreaclientset.Interface, internalNetworkPolicyStore storage.Interface, cnpInformer crdinformers.ClusterNetworkPolicyInformer, anpInformer crdinformers.NetworkPolicyInformer) *StatusController {
+func NewStatusController(antreaClient antreaclientset.Interface, internalNetworkPolicyStore, appliedToGroupStore, addressGroupStore storage.Interface, cnpInformer crdinformers.ClusterNetworkPolicyInformer, anpInformer crdinformers.NetworkPolicyInformer) *StatusController {
 	c := &StatusController{
 		npControlInterface: &networkPolicyControl{
 			antreaClient: antreaClient,
@@ -81,6 +97,8 @@ func NewStatusController(antreaClient antreaclientset.Interface, internalNetwork
 		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicy"),
 		internalNetworkPolicyStore: internalNetworkPolicyStore,
 		statuses:                   map[string]map[string]*controlplane.NetworkPolicyNodeStatus{},
+		ruleAnalyzer:               newRuleAnalyzer(internalNetworkPolicyStore, appliedToGroupStore, addressGroupStore),
+		ruleConflicts:              map[string][]RuleConflict{},
 		cnpListerSynced:            cnpInformer.Informer().HasSynced,
 		anpListerSynced:            anpInformer.Informer().HasSynced,
 	}
@@ -109,7 +127,7 @@ func NewStatusController(antreaClient antreaclientset.Interface, internalNetwork
 func (c *StatusController) updateCNP(old, cur interface{}) {
 	curCNP := cur.(*crdv1alpha1.ClusterNetworkPolicy)
 	oldCNP := old.(*crdv1alpha1.ClusterNetworkPolicy)
-	if oldCNP.Status == curCNP.Status {
+	if apiequality.Semantic.DeepEqual(oldCNP.Status, curCNP.Status) {
 		return
 	}
 	key := internalNetworkPolicyKeyFunc(oldCNP)
@@ -119,7 +137,7 @@ func (c *StatusController) updateCNP(old, cur interface{}) {
 func (c *StatusController) updateANP(old, cur interface{}) {
 	curANP := cur.(*crdv1alpha1.NetworkPolicy)
 	oldANP := old.(*crdv1alpha1.NetworkPolicy)
-	if oldANP.Status == curANP.Status {
+	if apiequality.Semantic.DeepEqual(oldANP.Status, curANP.Status) {
 		return
 	}
 	key := internalNetworkPolicyKeyFunc(oldANP)
@@ -192,6 +210,8 @@ func (c *StatusController) Run(stopCh <-chan struct{}) {
 
 	go wait.NonSlidingUntil(c.watchInternalNetworkPolicy, 5*time.Second, stopCh)
 
+	go wait.NonSlidingUntil(c.syncRuleConflicts, ruleConflictsSyncPeriod, stopCh)
+
 	for i := 0; i < defaultWorkers; i++ {
 		go wait.Until(c.runWorker, time.Second, stopCh)
 	}
@@ -225,6 +245,93 @@ func (c *StatusController) watchInternalNetworkPolicy() {
 	}
 }
 
+// syncRuleConflicts analyzes the rules of all Antrea-native policies and enqueues the policies whose rule conflicts
+// have changed since the last analysis, so that their status conditions get updated.
+func (c *StatusController) syncRuleConflicts() {
+	conflictsByPolicy := map[string][]RuleConflict{}
+	for _, conflict := range c.ruleAnalyzer.analyze() {
+		key := string(conflict.Rule.UID)
+		conflictsByPolicy[key] = append(conflictsByPolicy[key], conflict)
+	}
+	var changedKeys []string
+	func() {
+		c.ruleConflictsLock.Lock()
+		defer c.ruleConflictsLock.Unlock()
+		for key, conflicts := range conflictsByPolicy {
+			if !reflect.DeepEqual(c.ruleConflicts[key], conflicts) {
+				changedKeys = append(changedKeys, key)
+			}
+		}
+		for key := range c.ruleConflicts {
+			if _, exists := conflictsByPolicy[key]; !exists {
+				changedKeys = append(changedKeys, key)
+			}
+		}
+		c.ruleConflicts = conflictsByPolicy
+	}()
+	for _, key := range changedKeys {
+		c.queue.Add(key)
+	}
+}
+
+// QueryRuleConflicts analyzes the rules of all Antrea-native policies with the current state of the stores.
+func (c *StatusController) QueryRuleConflicts() *RuleConflictQueryResponse {
+	return &RuleConflictQueryResponse{Conflicts: c.ruleAnalyzer.analyze()}
+}
+
+// getRuleConflictConditions returns one condition per type of conflict found for the rules of a NetworkPolicy in the
+// last rule analysis.
+func (c *StatusController) getRuleConflictConditions(key string) []crdv1alpha1.NetworkPolicyCondition {
+	c.ruleConflictsLock.RLock()
+	defer c.ruleConflictsLock.RUnlock()
+	messages := map[crdv1alpha1.NetworkPolicyConditionType][]string{}
+	for _, conflict := range c.ruleConflicts[key] {
+		var conditionType crdv1alpha1.NetworkPolicyConditionType
+		var verb string
+		switch conflict.Type {
+		case RuleConflictShadowed:
+			conditionType, verb = crdv1alpha1.NetworkPolicyRulesShadowed, "is shadowed by"
+		case RuleConflictRedundant:
+			conditionType, verb = crdv1alpha1.NetworkPolicyRulesRedundant, "is redundant with"
+		case RuleConflictConflicting:
+			conditionType, verb = crdv1alpha1.NetworkPolicyRulesConflicting, "conflicts with"
+		}
+		policyRef := &controlplane.NetworkPolicyReference{
+			Type:      controlplane.NetworkPolicyType(conflict.By.PolicyType),
+			Namespace: conflict.By.Namespace,
+			Name:      conflict.By.Name,
+		}
+		message := fmt.Sprintf("%s %s %s of %s", describeRule(&conflict.Rule), verb, describeRule(&conflict.By), policyRef.ToString())
+		messages[conditionType] = append(messages[conditionType], message)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	conditions := make([]crdv1alpha1.NetworkPolicyCondition, 0, len(messages))
+	for conditionType, typeMessages := range messages {
+		conditions = append(conditions, crdv1alpha1.NetworkPolicyCondition{
+			Type:    conditionType,
+			Status:  corev1.ConditionTrue,
+			Message: strings.Join(typeMessages, "; "),
+		})
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i].Type < conditions[j].Type
+	})
+	return conditions
+}
+
+func describeRule(rule *PolicyRuleRef) string {
+	direction := "ingress"
+	if rule.Direction == cpv1beta.DirectionOut {
+		direction = "egress"
+	}
+	if rule.RuleName == "" {
+		return fmt.Sprintf("%s rule %d", direction, rule.RuleIndex)
+	}
+	return fmt.Sprintf("%s rule %d (%s)", direction, rule.RuleIndex, rule.RuleName)
+}
+
 func (c *StatusController) runWorker() {
 	for c.processNextWorkItem() {
 	}
@@ -272,6 +379,7 @@ func (c *StatusController) syncHandler(key string) error {
 		status := &crdv1alpha1.NetworkPolicyStatus{
 			Phase:              crdv1alpha1.NetworkPolicyPending,
 			ObservedGeneration: internalNP.Generation,
+			Conditions:         c.getRuleConflictConditions(key),
 		}
 		if internalNP.SourceRef.Type == controlplane.AntreaNetworkPolicy {
 			return c.npControlInterface.UpdateAntreaNetworkPolicyStatus(internalNP.SourceRef.Namespace, internalNP.SourceRef.Name, status)
@@ -303,6 +411,7 @@ func (c *StatusController) syncHandler(key string) error {
 		ObservedGeneration:   internalNP.Generation,
 		CurrentNodesRealized: int32(currentNodes),
 		DesiredNodesRealized: int32(desiredNodes),
+		Conditions:           c.getRuleConflictConditions(key),
 	}
 	klog.V(2).Infof("Updating NetworkPolicy %s status: %v", internalNP.SourceRef.ToString(), status)
 	if internalNP.SourceRef.Type == controlplane.AntreaNetworkPolicy {
@@ -331,7 +440,8 @@ func (c *networkPolicyControl) UpdateAntreaNetworkPolicyStatus(namespace, name s
 		klog.Infof("Didn't find the original Antrea NetworkPolicy %s/%s, skip updating status", namespace, name)
 		return nil
 	}
-	if anp.Status == *status {
+	status.Conditions = mergeConditions(anp.Status.Conditions, status.Conditions)
+	if apiequality.Semantic.DeepEqual(anp.Status, *status) {
 		return nil
 	}
 	metrics.AntreaNetworkPolicyStatusUpdates.Inc()
@@ -347,8 +457,9 @@ func (c *networkPolicyControl) UpdateAntreaClusterNetworkPolicyStatus(name strin
 		klog.Infof("Didn't find the original Antrea ClusterNetworkPolicy %s, skip updating status", name)
 		return nil
 	}
+	status.Conditions = mergeConditions(cnp.Status.Conditions, status.Conditions)
 	// If the current status equals to the desired status, no need to update.
-	if cnp.Status == *status {
+	if apiequality.Semantic.DeepEqual(cnp.Status, *status) {
 		return nil
 	}
 	metrics.AntreaClusterNetworkPolicyStatusUpdates.Inc()
@@ -357,3 +468,19 @@ func (c *networkPolicyControl) UpdateAntreaClusterNetworkPolicyStatus(name strin
 	_, err = c.antreaClient.CrdV1alpha1().ClusterNetworkPolicies().UpdateStatus(context.TODO(), toUpdate, v1.UpdateOptions{})
 	return err
 }
+
+// mergeConditions sets the LastTransitionTime of the desired conditions. It's kept from the current condition of the
+// same type if the condition status hasn't changed.
+func mergeConditions(current, desired []crdv1alpha1.NetworkPolicyCondition) []crdv1alpha1.NetworkPolicyCondition {
+	now := v1.Now()
+	for i := range desired {
+		desired[i].LastTransitionTime = now
+		for _, condition := range current {
+			if condition.Type == desired[i].Type && condition.Status == desired[i].Status {
+				desired[i].LastTransitionTime = condition.LastTransitionTime
+				break
+			}
+		}
+	}
+	return desired
+}
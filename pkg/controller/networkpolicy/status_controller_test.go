@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -82,6 +83,8 @@ func newTestStatusController(initialObjects ...runtime.Object) (*StatusControlle
 		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(minRetryDelay, maxRetryDelay), "networkpolicy"),
 		internalNetworkPolicyStore: networkPolicyStore,
 		statuses:                   map[string]map[string]*controlplane.NetworkPolicyNodeStatus{},
+		ruleAnalyzer:               newRuleAnalyzer(networkPolicyStore, store.NewAppliedToGroupStore(), store.NewAddressGroupStore()),
+		ruleConflicts:              map[string][]RuleConflict{},
 		cnpListerSynced:            cnpInformer.Informer().HasSynced,
 		anpListerSynced:            anpInformer.Informer().HasSynced,
 	}
@@ -348,3 +351,62 @@ func BenchmarkSyncHandler(b *testing.B) {
 		statusController.syncHandler("anp1")
 	}
 }
+
+func TestRuleConflictConditions(t *testing.T) {
+	statusController, _, _, networkPolicyStore, networkPolicyControl := newTestStatusController()
+	analyzer := newTestRuleAnalyzer()
+	analyzer.internalNetworkPolicyStore = networkPolicyStore
+	statusController.ruleAnalyzer = analyzer
+
+	appTierPriority, priority := DefaultTierPriority, float64(1)
+	allowAction, dropAction := crdv1alpha1.RuleActionAllow, crdv1alpha1.RuleActionDrop
+	peerA := controlplane.NetworkPolicyPeer{AddressGroups: []string{"agA"}}
+	cnp1 := newReachabilityTestPolicy("cnp1", controlplane.AntreaClusterNetworkPolicy, &appTierPriority, &priority, "atgB",
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, Name: "drop-from-a", From: peerA, Action: &dropAction},
+		controlplane.NetworkPolicyRule{Direction: controlplane.DirectionIn, Name: "allow-from-a", From: peerA, Action: &allowAction, Priority: 1})
+	cnp1.Generation = 1
+	cnp1.SpanMeta = types.SpanMeta{NodeNames: sets.NewString("node1")}
+	networkPolicyStore.Create(cnp1)
+
+	statusController.syncRuleConflicts()
+	assert.Equal(t, 1, statusController.queue.Len())
+	assert.NoError(t, statusController.syncHandler("cnp1"))
+	expectedStatus := &crdv1alpha1.NetworkPolicyStatus{
+		Phase:                crdv1alpha1.NetworkPolicyRealizing,
+		ObservedGeneration:   1,
+		DesiredNodesRealized: 1,
+		Conditions: []crdv1alpha1.NetworkPolicyCondition{
+			{
+				Type:    crdv1alpha1.NetworkPolicyRulesShadowed,
+				Status:  corev1.ConditionTrue,
+				Message: "ingress rule 1 (allow-from-a) is shadowed by ingress rule 0 (drop-from-a) of AntreaClusterNetworkPolicy:cnp1",
+			},
+		},
+	}
+	assert.Equal(t, expectedStatus, networkPolicyControl.getAntreaClusterNetworkPolicyStatus())
+
+	// The conditions are removed once the conflict is resolved.
+	networkPolicyStore.Delete("cnp1")
+	cnp1.Rules = cnp1.Rules[:1]
+	networkPolicyStore.Create(cnp1)
+	statusController.syncRuleConflicts()
+	assert.NoError(t, statusController.syncHandler("cnp1"))
+	expectedStatus.Conditions = nil
+	assert.Equal(t, expectedStatus, networkPolicyControl.getAntreaClusterNetworkPolicyStatus())
+}
+
+func TestMergeConditions(t *testing.T) {
+	lastTransitionTime := v1.NewTime(time.Now().Add(-time.Hour))
+	current := []crdv1alpha1.NetworkPolicyCondition{
+		{Type: crdv1alpha1.NetworkPolicyRulesShadowed, Status: corev1.ConditionTrue, LastTransitionTime: lastTransitionTime, Message: "old"},
+		{Type: crdv1alpha1.NetworkPolicyRulesRedundant, Status: corev1.ConditionTrue, LastTransitionTime: lastTransitionTime},
+	}
+	desired := []crdv1alpha1.NetworkPolicyCondition{
+		{Type: crdv1alpha1.NetworkPolicyRulesShadowed, Status: corev1.ConditionTrue, Message: "new"},
+		{Type: crdv1alpha1.NetworkPolicyRulesConflicting, Status: corev1.ConditionTrue},
+	}
+	merged := mergeConditions(current, desired)
+	assert.Equal(t, lastTransitionTime, merged[0].LastTransitionTime)
+	assert.Equal(t, "new", merged[0].Message)
+	assert.True(t, lastTransitionTime.Before(&merged[1].LastTransitionTime))
+}
@@ -14,7 +14,7 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: antrea.io/antrea/pkg/controller/networkpolicy (interfaces: EndpointQuerier)
+// Source: antrea.io/antrea/pkg/controller/networkpolicy (interfaces: EndpointQuerier,ReachabilityQuerier,RuleConflictQuerier)
 
 // Package testing is a generated GoMock package.
 package testing
@@ -62,3 +62,78 @@ func (mr *MockEndpointQuerierMockRecorder) QueryNetworkPolicies(arg0, arg1 inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryNetworkPolicies", reflect.TypeOf((*MockEndpointQuerier)(nil).QueryNetworkPolicies), arg0, arg1)
 }
+
+// MockReachabilityQuerier is a mock of ReachabilityQuerier interface
+type MockReachabilityQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockReachabilityQuerierMockRecorder
+}
+
+// MockReachabilityQuerierMockRecorder is the mock recorder for MockReachabilityQuerier
+type MockReachabilityQuerierMockRecorder struct {
+	mock *MockReachabilityQuerier
+}
+
+// NewMockReachabilityQuerier creates a new mock instance
+func NewMockReachabilityQuerier(ctrl *gomock.Controller) *MockReachabilityQuerier {
+	mock := &MockReachabilityQuerier{ctrl: ctrl}
+	mock.recorder = &MockReachabilityQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReachabilityQuerier) EXPECT() *MockReachabilityQuerierMockRecorder {
+	return m.recorder
+}
+
+// QueryReachability mocks base method
+func (m *MockReachabilityQuerier) QueryReachability(arg0 *networkpolicy.ReachabilityQuery) (*networkpolicy.ReachabilityQueryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryReachability", arg0)
+	ret0, _ := ret[0].(*networkpolicy.ReachabilityQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryReachability indicates an expected call of QueryReachability
+func (mr *MockReachabilityQuerierMockRecorder) QueryReachability(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryReachability", reflect.TypeOf((*MockReachabilityQuerier)(nil).QueryReachability), arg0)
+}
+
+// MockRuleConflictQuerier is a mock of RuleConflictQuerier interface
+type MockRuleConflictQuerier struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleConflictQuerierMockRecorder
+}
+
+// MockRuleConflictQuerierMockRecorder is the mock recorder for MockRuleConflictQuerier
+type MockRuleConflictQuerierMockRecorder struct {
+	mock *MockRuleConflictQuerier
+}
+
+// NewMockRuleConflictQuerier creates a new mock instance
+func NewMockRuleConflictQuerier(ctrl *gomock.Controller) *MockRuleConflictQuerier {
+	mock := &MockRuleConflictQuerier{ctrl: ctrl}
+	mock.recorder = &MockRuleConflictQuerierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRuleConflictQuerier) EXPECT() *MockRuleConflictQuerierMockRecorder {
+	return m.recorder
+}
+
+// QueryRuleConflicts mocks base method
+func (m *MockRuleConflictQuerier) QueryRuleConflicts() *networkpolicy.RuleConflictQueryResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryRuleConflicts")
+	ret0, _ := ret[0].(*networkpolicy.RuleConflictQueryResponse)
+	return ret0
+}
+
+// QueryRuleConflicts indicates an expected call of QueryRuleConflicts
+func (mr *MockRuleConflictQuerierMockRecorder) QueryRuleConflicts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRuleConflicts", reflect.TypeOf((*MockRuleConflictQuerier)(nil).QueryRuleConflicts))
+}
@@ -17,10 +17,13 @@ package networkpolicy
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 
 	admv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -31,6 +34,7 @@ import (
 	crdv1alpha2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
 	"antrea.io/antrea/pkg/controller/networkpolicy/store"
 	"antrea.io/antrea/pkg/controller/types"
+	"antrea.io/antrea/pkg/features"
 	"antrea.io/antrea/pkg/util/env"
 )
 
@@ -74,6 +78,9 @@ var (
 	// reservedTierNames stores the set of Tier names which cannot be deleted
 	// since they are created by Antrea.
 	reservedTierNames = sets.NewString("baseline", "application", "platform", "networkops", "securityops", "emergency")
+	// fqdnPatternRegex matches the FQDNs which can be set in NetworkPolicyPeers, i.e.
+	// DNS names whose labels may contain "*" wildcards.
+	fqdnPatternRegex = regexp.MustCompile(`^(?i)[a-z0-9*]([-a-z0-9*]*[a-z0-9*])?(\.[a-z0-9*]([-a-z0-9*]*[a-z0-9*])?)*\.?$`)
 )
 
 // RegisterAntreaPolicyValidator registers an Antrea-native policy validator
@@ -254,10 +261,24 @@ func (v *NetworkPolicyValidator) validateAntreaPolicy(curObj, oldObj interface{}
 	return reason, allowed
 }
 
-// validatePort validates if ports is valid
+// validatePort validates if ports and protocols are valid
 func (a *antreaPolicyValidator) validatePort(ingress, egress []crdv1alpha1.Rule) error {
 	isValid := func(rules []crdv1alpha1.Rule) error {
 		for _, rule := range rules {
+			for _, protocol := range rule.Protocols {
+				icmp := protocol.ICMP
+				if icmp == nil {
+					icmp = protocol.ICMPv6
+				} else if protocol.ICMPv6 != nil {
+					return fmt.Errorf("`icmp` and `icmpv6` cannot be specified in the same protocol")
+				}
+				if icmp == nil {
+					return fmt.Errorf("one of `icmp` and `icmpv6` must be specified in a protocol")
+				}
+				if icmp.ICMPCode != nil && icmp.ICMPType == nil {
+					return fmt.Errorf("if `icmpCode` is specified `icmpType` must be specified")
+				}
+			}
 			for _, port := range rule.Ports {
 				if port.EndPort == nil {
 					continue
@@ -385,6 +406,8 @@ func (a *antreaPolicyValidator) createValidate(curObj interface{}, userInfo auth
 	var tier string
 	var ingress, egress []crdv1alpha1.Rule
 	var specAppliedTo []crdv1alpha1.NetworkPolicyPeer
+	var activationWindows []crdv1alpha1.ActivationWindow
+	var isClusterPolicy bool
 	switch curObj.(type) {
 	case *crdv1alpha1.ClusterNetworkPolicy:
 		curCNP := curObj.(*crdv1alpha1.ClusterNetworkPolicy)
@@ -392,12 +415,15 @@ func (a *antreaPolicyValidator) createValidate(curObj interface{}, userInfo auth
 		ingress = curCNP.Spec.Ingress
 		egress = curCNP.Spec.Egress
 		specAppliedTo = curCNP.Spec.AppliedTo
+		activationWindows = curCNP.Spec.ActivationWindows
+		isClusterPolicy = true
 	case *crdv1alpha1.NetworkPolicy:
 		curANP := curObj.(*crdv1alpha1.NetworkPolicy)
 		tier = curANP.Spec.Tier
 		ingress = curANP.Spec.Ingress
 		egress = curANP.Spec.Egress
 		specAppliedTo = curANP.Spec.AppliedTo
+		activationWindows = curANP.Spec.ActivationWindows
 	}
 	reason, allowed := a.validateTierForPolicy(tier)
 	if !allowed {
@@ -414,9 +440,25 @@ func (a *antreaPolicyValidator) createValidate(curObj interface{}, userInfo auth
 	if !allowed {
 		return reason, allowed
 	}
+	reason, allowed = a.validateNodeSelector(ingress, egress, specAppliedTo, isClusterPolicy)
+	if !allowed {
+		return reason, allowed
+	}
 	if err := a.validatePort(ingress, egress); err != nil {
 		return err.Error(), false
 	}
+	reason, allowed = a.validateL7Protocols(ingress, egress)
+	if !allowed {
+		return reason, allowed
+	}
+	reason, allowed = a.validateActivationWindows(activationWindows)
+	if !allowed {
+		return reason, allowed
+	}
+	reason, allowed = a.validatePassAction(tier, ingress, egress)
+	if !allowed {
+		return reason, allowed
+	}
 	return "", true
 }
 
@@ -490,13 +532,27 @@ func (a *antreaPolicyValidator) validateAppliedTo(ingress, egress []crdv1alpha1.
 }
 
 // validatePeers ensures that the NetworkPolicyPeer object set in rules are valid, i.e.
-// currently it ensures that a Group cannot be set with other stand-alone selectors or IPBlock.
+// currently it ensures that a Group cannot be set with other stand-alone selectors or IPBlock,
+// and that a FQDN is a valid name pattern set alone in egress rules.
 func (a *antreaPolicyValidator) validatePeers(ingress, egress []crdv1alpha1.Rule) (string, bool) {
-	checkPeers := func(peers []crdv1alpha1.NetworkPolicyPeer) (string, bool) {
+	checkPeers := func(peers []crdv1alpha1.NetworkPolicyPeer, isEgress bool) (string, bool) {
 		for _, peer := range peers {
 			if peer.NamespaceSelector != nil && peer.Namespaces != nil {
 				return "namespaces and namespaceSelector cannot be set at the same time for a single NetworkPolicyPeer", false
 			}
+			if peer.FQDN != "" {
+				if !isEgress {
+					return "fqdn can only be set in egress rules", false
+				}
+				if peer.PodSelector != nil || peer.IPBlock != nil || peer.NamespaceSelector != nil ||
+					peer.Namespaces != nil || peer.ExternalEntitySelector != nil || peer.Group != "" {
+					return "fqdn cannot be set with other peers in rules", false
+				}
+				if !fqdnPatternRegex.MatchString(peer.FQDN) {
+					return fmt.Sprintf("fqdn %s is not a valid domain name or wildcard pattern", peer.FQDN), false
+				}
+				continue
+			}
 			if peer.Group == "" {
 				continue
 			}
@@ -511,13 +567,13 @@ func (a *antreaPolicyValidator) validatePeers(ingress, egress []crdv1alpha1.Rule
 		return "", true
 	}
 	for _, rule := range ingress {
-		msg, isValid := checkPeers(rule.From)
+		msg, isValid := checkPeers(rule.From, false)
 		if !isValid {
 			return msg, false
 		}
 	}
 	for _, rule := range egress {
-		msg, isValid := checkPeers(rule.To)
+		msg, isValid := checkPeers(rule.To, true)
 		if !isValid {
 			return msg, false
 		}
@@ -525,6 +581,168 @@ func (a *antreaPolicyValidator) validatePeers(ingress, egress []crdv1alpha1.Rule
 	return "", true
 }
 
+// validateL7Protocols ensures that l7Protocols are only set in the rules with the
+// Allow action matching TCP traffic, and only when the L7NetworkPolicy feature is
+// enabled.
+func (a *antreaPolicyValidator) validateL7Protocols(ingress, egress []crdv1alpha1.Rule) (string, bool) {
+	for _, rule := range append(ingress, egress...) {
+		if len(rule.L7Protocols) == 0 {
+			continue
+		}
+		if !features.DefaultFeatureGate.Enabled(features.L7NetworkPolicy) {
+			return "l7Protocols can only be set when the L7NetworkPolicy feature is enabled", false
+		}
+		if rule.Action == nil || *rule.Action != crdv1alpha1.RuleActionAllow {
+			return "l7Protocols can only be set in rules with the Allow action", false
+		}
+		if rule.EnforcementMode == crdv1alpha1.RuleEnforcementModeAudit {
+			return "l7Protocols cannot be set in rules in Audit mode", false
+		}
+		if len(rule.Protocols) > 0 {
+			return "l7Protocols cannot be set with protocols in rules", false
+		}
+		for _, port := range rule.Ports {
+			if port.Protocol != nil && *port.Protocol != v1.ProtocolTCP {
+				return "l7Protocols can only be set in rules matching TCP ports", false
+			}
+		}
+		for _, l7Protocol := range rule.L7Protocols {
+			if l7Protocol.HTTP == nil {
+				return "http must be specified in l7Protocols", false
+			}
+			if l7Protocol.HTTP.Path != "" && !strings.HasPrefix(l7Protocol.HTTP.Path, "/") {
+				return fmt.Sprintf("path %s of http in l7Protocols must start with /", l7Protocol.HTTP.Path), false
+			}
+		}
+	}
+	return "", true
+}
+
+// validatePassAction ensures that the Pass action is not used in the baseline
+// Tier, as there are no rules to delegate the traffic to after it.
+func (a *antreaPolicyValidator) validatePassAction(tier string, ingress, egress []crdv1alpha1.Rule) (string, bool) {
+	if strings.ToLower(tier) != baselineTierName {
+		return "", true
+	}
+	for _, rules := range [][]crdv1alpha1.Rule{ingress, egress} {
+		for _, rule := range rules {
+			if rule.Action != nil && *rule.Action == crdv1alpha1.RuleActionPass {
+				return "Pass action cannot be set in rules of policies in the baseline Tier", false
+			}
+		}
+	}
+	return "", true
+}
+
+// validateActivationWindows ensures that each activation window is either
+// defined by a start and/or end time, or by a valid cron schedule and a
+// positive duration.
+func (a *antreaPolicyValidator) validateActivationWindows(windows []crdv1alpha1.ActivationWindow) (string, bool) {
+	for _, window := range windows {
+		if window.Schedule == "" {
+			if window.Duration != nil {
+				return "duration can only be set with schedule in activationWindows", false
+			}
+			if window.TimeZone != "" {
+				return "timeZone can only be set with schedule in activationWindows", false
+			}
+			if window.Start == nil && window.End == nil {
+				return "either start/end or schedule must be set in activationWindows", false
+			}
+			if window.Start != nil && window.End != nil && !window.End.After(window.Start.Time) {
+				return "end must be after start in activationWindows", false
+			}
+			continue
+		}
+		if window.Start != nil || window.End != nil {
+			return "start/end cannot be set with schedule in activationWindows", false
+		}
+		if _, err := parseCronSchedule(window.Schedule); err != nil {
+			return fmt.Sprintf("invalid schedule in activationWindows: %v", err), false
+		}
+		if window.Duration == nil || window.Duration.Duration <= 0 {
+			return "a positive duration must be set with schedule in activationWindows", false
+		}
+		if _, err := loadScheduleLocation(window.TimeZone); err != nil {
+			return fmt.Sprintf("invalid timeZone in activationWindows: %v", err), false
+		}
+	}
+	return "", true
+}
+
+// validateNodeSelector ensures that nodeSelector is only set alone in the appliedTo of
+// ClusterNetworkPolicies, and that a policy applied to Nodes is applied to Nodes only and
+// has no per-namespace rule, FQDN or L7 protocol, which the agents can't enforce on the
+// traffic of the Nodes.
+func (a *antreaPolicyValidator) validateNodeSelector(ingress, egress []crdv1alpha1.Rule, specAppliedTo []crdv1alpha1.NetworkPolicyPeer, isClusterPolicy bool) (string, bool) {
+	appliedToNodes, appliedToOthers := false, false
+	checkAppliedTo := func(appTos []crdv1alpha1.NetworkPolicyPeer) (string, bool) {
+		for _, appTo := range appTos {
+			if appTo.NodeSelector == nil {
+				appliedToOthers = true
+				continue
+			}
+			if !isClusterPolicy {
+				return "nodeSelector can only be set in appliedTo of ClusterNetworkPolicies", false
+			}
+			if appTo.PodSelector != nil || appTo.NamespaceSelector != nil || appTo.ExternalEntitySelector != nil || appTo.Group != "" {
+				return "nodeSelector cannot be set with other selectors in appliedTo", false
+			}
+			appliedToNodes = true
+		}
+		return "", true
+	}
+	checkRules := func(rules []crdv1alpha1.Rule, isEgress bool) (string, bool) {
+		for _, rule := range rules {
+			if reason, allowed := checkAppliedTo(rule.AppliedTo); !allowed {
+				return reason, false
+			}
+			peers := rule.From
+			if isEgress {
+				peers = rule.To
+			}
+			for _, peer := range peers {
+				if peer.NodeSelector != nil {
+					return "nodeSelector can only be set in appliedTo", false
+				}
+			}
+		}
+		return "", true
+	}
+	if reason, allowed := checkAppliedTo(specAppliedTo); !allowed {
+		return reason, false
+	}
+	if reason, allowed := checkRules(ingress, false); !allowed {
+		return reason, false
+	}
+	if reason, allowed := checkRules(egress, true); !allowed {
+		return reason, false
+	}
+	if !appliedToNodes {
+		return "", true
+	}
+	if appliedToOthers {
+		return "nodeSelector must be set in all appliedTo of policies applied to Nodes", false
+	}
+	cnp := &crdv1alpha1.ClusterNetworkPolicy{Spec: crdv1alpha1.ClusterNetworkPolicySpec{Ingress: ingress, Egress: egress}}
+	if hasPerNamespaceRule(cnp) {
+		return "namespaces cannot be set in rules of policies applied to Nodes", false
+	}
+	for _, rules := range [][]crdv1alpha1.Rule{ingress, egress} {
+		for _, rule := range rules {
+			if len(rule.L7Protocols) > 0 {
+				return "l7Protocols cannot be set in rules of policies applied to Nodes", false
+			}
+			for _, peer := range rule.To {
+				if peer.FQDN != "" {
+					return "fqdn cannot be set in rules of policies applied to Nodes", false
+				}
+			}
+		}
+	}
+	return "", true
+}
+
 // validateTierForPolicy validates whether a referenced Tier exists.
 func (v *antreaPolicyValidator) validateTierForPolicy(tier string) (string, bool) {
 	// "tier" must exist before referencing
@@ -544,6 +762,8 @@ func (a *antreaPolicyValidator) updateValidate(curObj, oldObj interface{}, userI
 	var tier string
 	var ingress, egress []crdv1alpha1.Rule
 	var specAppliedTo []crdv1alpha1.NetworkPolicyPeer
+	var activationWindows []crdv1alpha1.ActivationWindow
+	var isClusterPolicy bool
 	switch curObj.(type) {
 	case *crdv1alpha1.ClusterNetworkPolicy:
 		curCNP := curObj.(*crdv1alpha1.ClusterNetworkPolicy)
@@ -551,12 +771,15 @@ func (a *antreaPolicyValidator) updateValidate(curObj, oldObj interface{}, userI
 		ingress = curCNP.Spec.Ingress
 		egress = curCNP.Spec.Egress
 		specAppliedTo = curCNP.Spec.AppliedTo
+		activationWindows = curCNP.Spec.ActivationWindows
+		isClusterPolicy = true
 	case *crdv1alpha1.NetworkPolicy:
 		curANP := curObj.(*crdv1alpha1.NetworkPolicy)
 		tier = curANP.Spec.Tier
 		ingress = curANP.Spec.Ingress
 		egress = curANP.Spec.Egress
 		specAppliedTo = curANP.Spec.AppliedTo
+		activationWindows = curANP.Spec.ActivationWindows
 	}
 	reason, allowed := a.validateAppliedTo(ingress, egress, specAppliedTo)
 	if !allowed {
@@ -569,9 +792,25 @@ func (a *antreaPolicyValidator) updateValidate(curObj, oldObj interface{}, userI
 	if !allowed {
 		return reason, allowed
 	}
+	reason, allowed = a.validateNodeSelector(ingress, egress, specAppliedTo, isClusterPolicy)
+	if !allowed {
+		return reason, allowed
+	}
 	if err := a.validatePort(ingress, egress); err != nil {
 		return err.Error(), false
 	}
+	reason, allowed = a.validateL7Protocols(ingress, egress)
+	if !allowed {
+		return reason, allowed
+	}
+	reason, allowed = a.validateActivationWindows(activationWindows)
+	if !allowed {
+		return reason, allowed
+	}
+	reason, allowed = a.validatePassAction(tier, ingress, egress)
+	if !allowed {
+		return reason, allowed
+	}
 	return a.validateTierForPolicy(tier)
 }
 
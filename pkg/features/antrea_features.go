@@ -51,6 +51,11 @@ const (
 	// Allows to trace path from a generated packet.
 	Traceflow featuregate.Feature = "Traceflow"
 
+	// alpha: v1.2
+	// Enable topology aware Endpoint selection in AntreaProxy, based on the topology hints of EndpointSlices. If
+	// AntreaProxy or EndpointSlice is not enabled, this flag will not take effect.
+	TopologyAwareHints featuregate.Feature = "TopologyAwareHints"
+
 	// alpha: v0.9
 	// Flow exporter exports IPFIX flow records of Antrea flows seen in conntrack module.
 	FlowExporter featuregate.Feature = "FlowExporter"
@@ -67,6 +72,15 @@ const (
 	// alpha: v1.0
 	// Enable controlling SNAT IPs of Pod egress traffic.
 	Egress featuregate.Feature = "Egress"
+
+	// alpha: v1.2
+	// Enable allocating Pod IPs from the IPPools selected by the Namespaces of the Pods.
+	AntreaIPAM featuregate.Feature = "AntreaIPAM"
+
+	// alpha: v1.2
+	// Enable the L7 protocols of Antrea-native policy rules, whose traffic is
+	// redirected to an L7 enforcement engine by the Antrea Agent.
+	L7NetworkPolicy featuregate.Feature = "L7NetworkPolicy"
 )
 
 var (
@@ -81,12 +95,15 @@ var (
 	// To add a new feature, define a key for it above and add it here. The features will be
 	// available throughout Antrea binaries.
 	DefaultAntreaFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+		AntreaIPAM:         {Default: false, PreRelease: featuregate.Alpha},
 		AntreaPolicy:       {Default: true, PreRelease: featuregate.Beta},
 		AntreaProxy:        {Default: true, PreRelease: featuregate.Beta},
 		Egress:             {Default: false, PreRelease: featuregate.Alpha},
 		EndpointSlice:      {Default: false, PreRelease: featuregate.Alpha},
+		TopologyAwareHints: {Default: false, PreRelease: featuregate.Alpha},
 		Traceflow:          {Default: true, PreRelease: featuregate.Beta},
 		FlowExporter:       {Default: false, PreRelease: featuregate.Alpha},
+		L7NetworkPolicy:    {Default: false, PreRelease: featuregate.Alpha},
 		NetworkPolicyStats: {Default: true, PreRelease: featuregate.Beta},
 		NodePortLocal:      {Default: false, PreRelease: featuregate.Alpha},
 	}
@@ -102,8 +119,10 @@ var (
 	// can have different FeatureSpecs between Linux and Windows, we should
 	// still define a separate defaultAntreaFeatureGates map for Windows.
 	unsupportedFeaturesOnWindows = map[featuregate.Feature]struct{}{
-		NodePortLocal: {},
-		Egress:        {},
+		NodePortLocal:   {},
+		Egress:          {},
+		AntreaIPAM:      {},
+		L7NetworkPolicy: {},
 	}
 )
 
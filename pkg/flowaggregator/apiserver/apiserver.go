@@ -0,0 +1,115 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+
+	"antrea.io/antrea/pkg/flowaggregator"
+	"antrea.io/antrea/pkg/flowaggregator/apiserver/handlers/flows"
+	antreaversion "antrea.io/antrea/pkg/version"
+)
+
+const Name = "flow-aggregator-api"
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+	// #nosec G101: false positive triggered by variable name which includes "token"
+	TokenPath = "/var/run/antrea/flow-aggregator-apiserver/loopback-client-token"
+)
+
+type flowAggregatorAPIServer struct {
+	GenericAPIServer *genericapiserver.GenericAPIServer
+}
+
+func (s *flowAggregatorAPIServer) Run(stopCh <-chan struct{}) error {
+	return s.GenericAPIServer.PrepareRun().Run(stopCh)
+}
+
+func installHandlers(fq flowaggregator.FlowQuerier, s *genericapiserver.GenericAPIServer) {
+	// The flow query API is not served when the in-memory window of flow records is disabled.
+	if fq != nil {
+		s.Handler.NonGoRestfulMux.HandleFunc("/flows", flows.HandleFunc(fq))
+	}
+}
+
+// New creates an APIServer for running in flow aggregator.
+func New(fq flowaggregator.FlowQuerier, bindPort int, enableMetrics bool) (*flowAggregatorAPIServer, error) {
+	cfg, err := newConfig(bindPort, enableMetrics)
+	if err != nil {
+		return nil, err
+	}
+	s, err := cfg.New(Name, genericapiserver.NewEmptyDelegate())
+	if err != nil {
+		return nil, err
+	}
+	installHandlers(fq, s)
+	return &flowAggregatorAPIServer{GenericAPIServer: s}, nil
+}
+
+func newConfig(bindPort int, enableMetrics bool) (*genericapiserver.CompletedConfig, error) {
+	secureServing := genericoptions.NewSecureServingOptions().WithLoopback()
+	authentication := genericoptions.NewDelegatingAuthenticationOptions()
+	authorization := genericoptions.NewDelegatingAuthorizationOptions().WithAlwaysAllowPaths("/healthz", "/livez", "/readyz")
+
+	// Set the PairName but leave certificate directory blank to generate in-memory by default.
+	secureServing.ServerCert.CertDirectory = ""
+	secureServing.ServerCert.PairName = Name
+	secureServing.BindAddress = net.IPv4zero
+	secureServing.BindPort = bindPort
+
+	if err := secureServing.MaybeDefaultWithSelfSignedCerts("localhost", nil, []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback}); err != nil {
+		return nil, fmt.Errorf("error creating self-signed certificates: %v", err)
+	}
+	serverConfig := genericapiserver.NewConfig(codecs)
+	if err := secureServing.ApplyTo(&serverConfig.SecureServing, &serverConfig.LoopbackClientConfig); err != nil {
+		return nil, err
+	}
+	if err := authentication.ApplyTo(&serverConfig.Authentication, serverConfig.SecureServing, nil); err != nil {
+		return nil, err
+	}
+	if err := authorization.ApplyTo(&serverConfig.Authorization); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(path.Dir(TokenPath), os.ModeDir); err != nil {
+		return nil, fmt.Errorf("error when creating dirs of token file: %v", err)
+	}
+	if err := ioutil.WriteFile(TokenPath, []byte(serverConfig.LoopbackClientConfig.BearerToken), 0600); err != nil {
+		return nil, fmt.Errorf("error when writing loopback access token to file: %v", err)
+	}
+	v := antreaversion.GetVersion()
+	serverConfig.Version = &k8sversion.Info{
+		Major:        fmt.Sprint(v.Major),
+		Minor:        fmt.Sprint(v.Minor),
+		GitVersion:   v.String(),
+		GitTreeState: antreaversion.GitTreeState,
+		GitCommit:    antreaversion.GetGitSHA(),
+	}
+	serverConfig.EnableMetrics = enableMetrics
+
+	completedServerCfg := serverConfig.Complete(nil)
+	return &completedServerCfg, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flows
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+
+	"antrea.io/antrea/pkg/antctl/transform/common"
+	"antrea.io/antrea/pkg/flowaggregator"
+)
+
+// Response describes the response struct of flows command.
+type Response struct {
+	flowaggregator.FlowRecord
+}
+
+// HandleFunc returns the function which can handle queries issued by the flows command.
+func HandleFunc(fq flowaggregator.FlowQuerier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := &flowaggregator.FlowFilter{
+			Namespace:  query.Get("namespace"),
+			PodName:    query.Get("pod"),
+			Service:    query.Get("service"),
+			PolicyName: query.Get("policy"),
+			Action:     query.Get("action"),
+		}
+		if filter.Action != "" {
+			if _, err := flowaggregator.ParseRuleAction(filter.Action); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if since := query.Get("since"); since != "" {
+			duration, err := time.ParseDuration(since)
+			if err != nil || duration <= 0 {
+				http.Error(w, "invalid since duration "+since, http.StatusBadRequest)
+				return
+			}
+			filter.Since = time.Now().Add(-duration)
+		}
+		if limit := query.Get("limit"); limit != "" {
+			var err error
+			if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+				http.Error(w, "invalid limit "+limit, http.StatusBadRequest)
+				return
+			}
+		}
+
+		records := fq.QueryFlows(filter)
+		flows := make([]Response, 0, len(records))
+		for _, record := range records {
+			flows = append(flows, Response{FlowRecord: record})
+		}
+		if err := json.NewEncoder(w).Encode(flows); err != nil {
+			http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+var _ common.TableOutput = new(Response)
+
+func (r Response) GetTableHeader() []string {
+	return []string{"SOURCE", "DESTINATION", "SERVICE", "PROTOCOL", "INGRESS-POLICY", "EGRESS-POLICY", "PACKETS", "BYTES", "FLOW-END"}
+}
+
+func endpointString(ip string, port uint16, namespace, podName string) string {
+	address := net.JoinHostPort(ip, strconv.Itoa(int(port)))
+	if podName == "" {
+		return address
+	}
+	return fmt.Sprintf("%s/%s(%s)", namespace, podName, address)
+}
+
+func protocolString(protocol uint8) string {
+	switch protocol {
+	case 1:
+		return "ICMP"
+	case 6:
+		return "TCP"
+	case 17:
+		return "UDP"
+	case 58:
+		return "ICMPv6"
+	case 132:
+		return "SCTP"
+	}
+	return strconv.Itoa(int(protocol))
+}
+
+func policyString(namespace, name string, action uint8) string {
+	if name == "" {
+		return flowaggregator.FormatRuleAction(action)
+	}
+	policy := name
+	if namespace != "" {
+		policy = namespace + "/" + name
+	}
+	if action == ipfixregistry.NetworkPolicyRuleActionNoAction {
+		return policy
+	}
+	return fmt.Sprintf("%s(%s)", policy, flowaggregator.FormatRuleAction(action))
+}
+
+func (r Response) GetTableRow(_ int) []string {
+	flowEnd := ""
+	if !r.FlowEndSeconds.IsZero() {
+		flowEnd = r.FlowEndSeconds.Format(time.RFC3339)
+	}
+	return []string{
+		endpointString(r.SourceIP, r.SourceTransportPort, r.SourcePodNamespace, r.SourcePodName),
+		endpointString(r.DestinationIP, r.DestinationTransportPort, r.DestinationPodNamespace, r.DestinationPodName),
+		r.DestinationServicePortName,
+		protocolString(r.ProtocolIdentifier),
+		policyString(r.IngressNetworkPolicyNamespace, r.IngressNetworkPolicyName, r.IngressNetworkPolicyRuleAction),
+		policyString(r.EgressNetworkPolicyNamespace, r.EgressNetworkPolicyName, r.EgressNetworkPolicyRuleAction),
+		strconv.FormatUint(r.PacketTotalCount+r.ReversePacketTotalCount, 10),
+		strconv.FormatUint(r.OctetTotalCount+r.ReverseOctetTotalCount, 10),
+		flowEnd,
+	}
+}
+
+// SortRows returns false as the flows are already sorted from the most recently exported.
+func (r Response) SortRows() bool {
+	return false
+}
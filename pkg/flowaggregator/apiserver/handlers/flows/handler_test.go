@@ -0,0 +1,148 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flows
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+
+	"antrea.io/antrea/pkg/flowaggregator"
+)
+
+type fakeFlowQuerier struct {
+	filter  *flowaggregator.FlowFilter
+	records []flowaggregator.FlowRecord
+}
+
+func (q *fakeFlowQuerier) QueryFlows(filter *flowaggregator.FlowFilter) []flowaggregator.FlowRecord {
+	q.filter = filter
+	return q.records
+}
+
+var testRecord = flowaggregator.FlowRecord{
+	FlowEndSeconds:                 time.Unix(1630000060, 0).UTC(),
+	SourceIP:                       "10.0.0.1",
+	DestinationIP:                  "10.0.0.2",
+	SourceTransportPort:            1234,
+	DestinationTransportPort:       80,
+	ProtocolIdentifier:             6,
+	PacketTotalCount:               10,
+	OctetTotalCount:                1000,
+	ReversePacketTotalCount:        5,
+	ReverseOctetTotalCount:         500,
+	SourcePodName:                  "pod1",
+	SourcePodNamespace:             "ns1",
+	DestinationServicePortName:     "ns2/svc:http",
+	IngressNetworkPolicyName:       "np1",
+	IngressNetworkPolicyNamespace:  "ns2",
+	IngressNetworkPolicyRuleAction: ipfixregistry.NetworkPolicyRuleActionAllow,
+	EgressNetworkPolicyName:        "acnp1",
+}
+
+func TestFlowsQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedFilter *flowaggregator.FlowFilter
+	}{
+		{
+			name:           "no filter",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedFilter: &flowaggregator.FlowFilter{},
+		},
+		{
+			name:           "filters",
+			query:          "?namespace=ns1&pod=pod1&service=ns2/svc&policy=np1&action=Allow&limit=10",
+			expectedStatus: http.StatusOK,
+			expectedFilter: &flowaggregator.FlowFilter{
+				Namespace:  "ns1",
+				PodName:    "pod1",
+				Service:    "ns2/svc",
+				PolicyName: "np1",
+				Action:     "Allow",
+				Limit:      10,
+			},
+		},
+		{
+			name:           "invalid action",
+			query:          "?action=Pass",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid since",
+			query:          "?since=-5m",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=all",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fq := &fakeFlowQuerier{records: []flowaggregator.FlowRecord{testRecord}}
+			handler := HandleFunc(fq)
+			req, err := http.NewRequest(http.MethodGet, "/flows"+tt.query, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.expectedFilter, fq.filter)
+			var received []Response
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &received))
+			assert.Equal(t, []Response{{FlowRecord: testRecord}}, received)
+		})
+	}
+
+	t.Run("since", func(t *testing.T) {
+		fq := &fakeFlowQuerier{}
+		req, err := http.NewRequest(http.MethodGet, "/flows?since=5m", nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		HandleFunc(fq).ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.WithinDuration(t, time.Now().Add(-5*time.Minute), fq.filter.Since, 10*time.Second)
+		assert.Equal(t, "[]\n", recorder.Body.String())
+	})
+}
+
+func TestFlowsTableRow(t *testing.T) {
+	r := Response{FlowRecord: testRecord}
+	assert.Equal(t, len(r.GetTableHeader()), len(r.GetTableRow(0)))
+	assert.Equal(t, []string{
+		"ns1/pod1(10.0.0.1:1234)",
+		"10.0.0.2:80",
+		"ns2/svc:http",
+		"TCP",
+		"ns2/np1(Allow)",
+		"acnp1",
+		"15",
+		"1500",
+		"2021-08-26T17:47:40Z",
+	}, r.GetTableRow(0))
+}
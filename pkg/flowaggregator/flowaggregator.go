@@ -46,6 +46,7 @@ var (
 		"octetTotalCount",
 		"packetDeltaCount",
 		"octetDeltaCount",
+		"samplingInterval",
 	}
 	ianaInfoElementsIPv4    = append(ianaInfoElementsCommon, []string{"sourceIPv4Address", "destinationIPv4Address"}...)
 	ianaInfoElementsIPv6    = append(ianaInfoElementsCommon, []string{"sourceIPv6Address", "destinationIPv6Address"}...)
@@ -185,6 +186,7 @@ type flowAggregator struct {
 	k8sClient                   kubernetes.Interface
 	observationDomainID         uint32
 	podInformer                 coreinformers.PodInformer
+	sinks                       []*asyncFlowSink
 }
 
 func NewFlowAggregator(
@@ -197,6 +199,7 @@ func NewFlowAggregator(
 	k8sClient kubernetes.Interface,
 	observationDomainID uint32,
 	podInformer coreinformers.PodInformer,
+	sinks []FlowSink,
 ) *flowAggregator {
 	registry := ipfix.NewIPFIXRegistry()
 	registry.LoadRegistry()
@@ -213,6 +216,9 @@ func NewFlowAggregator(
 		observationDomainID:         observationDomainID,
 		podInformer:                 podInformer,
 	}
+	for _, sink := range sinks {
+		fa.sinks = append(fa.sinks, newAsyncFlowSink(sink, flowSinkQueueSize))
+	}
 	podInformer.Informer().AddIndexers(cache.Indexers{podInfoIndex: podInfoIndexFunc})
 	return fa
 }
@@ -360,6 +366,9 @@ func (fa *flowAggregator) Run(stopCh <-chan struct{}) {
 	defer fa.collectingProcess.Stop()
 	go fa.aggregationProcess.Start()
 	defer fa.aggregationProcess.Stop()
+	for _, sink := range fa.sinks {
+		go sink.run()
+	}
 	go fa.flowRecordExpiryCheck(stopCh)
 
 	<-stopCh
@@ -374,10 +383,15 @@ func (fa *flowAggregator) flowRecordExpiryCheck(stopCh <-chan struct{}) {
 			if fa.exportingProcess != nil {
 				fa.exportingProcess.CloseConnToCollector()
 			}
+			for _, sink := range fa.sinks {
+				sink.close()
+			}
 			expireTimer.Stop()
 			return
 		case <-expireTimer.C:
-			if fa.exportingProcess == nil {
+			// The IPFIX collector is optional when flow records are exported to other sinks. When it is
+			// configured, the flow records are kept in the Aggregation Process until it can be reached.
+			if fa.externalFlowCollectorAddr != "" && fa.exportingProcess == nil {
 				err := fa.initExportingProcess()
 				if err != nil {
 					klog.Errorf("Error when initializing exporting process: %v, will retry in %s", err, fa.activeFlowRecordTimeout)
@@ -392,11 +406,15 @@ func (fa *flowAggregator) flowRecordExpiryCheck(stopCh <-chan struct{}) {
 				klog.Errorf("Error when sending expired flow records: %v", err)
 				// If there is an error when sending flow records because of intermittent connectivity, we reset the connection
 				// to IPFIX collector and retry in the next export cycle to reinitialize the connection and send flow records.
-				fa.exportingProcess.CloseConnToCollector()
-				fa.exportingProcess = nil
+				if fa.exportingProcess != nil {
+					fa.exportingProcess.CloseConnToCollector()
+					fa.exportingProcess = nil
+				}
+				fa.flushSinks()
 				expireTimer.Reset(fa.activeFlowRecordTimeout)
 				continue
 			}
+			fa.flushSinks()
 			// Get the new expiry and reset the timer.
 			expireTimer.Reset(fa.aggregationProcess.GetExpiryFromExpirePriorityQueue())
 		}
@@ -404,6 +422,31 @@ func (fa *flowAggregator) flowRecordExpiryCheck(stopCh <-chan struct{}) {
 }
 
 func (fa *flowAggregator) sendFlowKeyRecord(key ipfixintermediate.FlowKey, record *ipfixintermediate.AggregationFlowRecord) error {
+	if !fa.aggregationProcess.AreCorrelatedFieldsFilled(*record) {
+		fa.fillK8sMetadata(key, record.Record)
+		fa.aggregationProcess.SetCorrelatedFieldsFilled(record)
+	}
+	if !fa.aggregationProcess.AreExternalFieldsFilled(*record) {
+		fa.fillPodLabels(key, record.Record)
+		fa.aggregationProcess.SetExternalFieldsFilled(record)
+	}
+	if fa.exportingProcess != nil {
+		if err := fa.sendDataSet(record); err != nil {
+			return err
+		}
+	}
+	// The record is only exported to the other sinks once it has been sent to the IPFIX collector, as it will
+	// be sent again if there is an error.
+	if len(fa.sinks) > 0 {
+		flowRecord := newFlowRecord(key, record.Record)
+		for _, sink := range fa.sinks {
+			sink.addFlow(flowRecord)
+		}
+	}
+	return fa.aggregationProcess.ResetStatElementsInRecord(record.Record)
+}
+
+func (fa *flowAggregator) sendDataSet(record *ipfixintermediate.AggregationFlowRecord) error {
 	isRecordIPv4 := fa.aggregationProcess.IsAggregatedRecordIPv4(*record)
 	templateID := fa.templateIDv4
 	if !isRecordIPv4 {
@@ -414,14 +457,6 @@ func (fa *flowAggregator) sendFlowKeyRecord(key ipfixintermediate.FlowKey, recor
 	if err := fa.set.PrepareSet(ipfixentities.Data, templateID); err != nil {
 		return err
 	}
-	if !fa.aggregationProcess.AreCorrelatedFieldsFilled(*record) {
-		fa.fillK8sMetadata(key, record.Record)
-		fa.aggregationProcess.SetCorrelatedFieldsFilled(record)
-	}
-	if !fa.aggregationProcess.AreExternalFieldsFilled(*record) {
-		fa.fillPodLabels(key, record.Record)
-		fa.aggregationProcess.SetExternalFieldsFilled(record)
-	}
 	err := fa.set.AddRecord(record.Record.GetOrderedElementList(), templateID)
 	if err != nil {
 		return err
@@ -430,14 +465,17 @@ func (fa *flowAggregator) sendFlowKeyRecord(key ipfixintermediate.FlowKey, recor
 	if err != nil {
 		return err
 	}
-	if err = fa.aggregationProcess.ResetStatElementsInRecord(record.Record); err != nil {
-		return err
-	}
-
 	klog.V(4).Infof("Data set sent successfully: %d Bytes sent", sentBytes)
 	return nil
 }
 
+// flushSinks requests the sinks to export their buffered flow records at the end of an export cycle.
+func (fa *flowAggregator) flushSinks() {
+	for _, sink := range fa.sinks {
+		sink.flush()
+	}
+}
+
 func (fa *flowAggregator) sendTemplateSet(isIPv6 bool) (int, error) {
 	elements := make([]*ipfixentities.InfoElementWithValue, 0)
 	ianaInfoElements := ianaInfoElementsIPv4
@@ -0,0 +1,206 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+)
+
+// FlowFilter selects the flow records returned by a FlowQuerier. Empty fields match all flow records.
+type FlowFilter struct {
+	// Namespace matches the flow records whose source or destination Pod is in this Namespace.
+	Namespace string
+	// PodName matches the flow records whose source or destination Pod has this name. When Namespace is also
+	// set, the Pod must be in this Namespace.
+	PodName string
+	// Service matches the flow records whose destination is this Service, as "<namespace>/<name>".
+	Service string
+	// PolicyName matches the flow records whose ingress or egress NetworkPolicy has this name.
+	PolicyName string
+	// Action matches the flow records whose ingress or egress rule action is "Allow", "Drop" or "Reject".
+	Action string
+	// Since matches the flow records exported after this time.
+	Since time.Time
+	// Limit is the maximum number of flow records returned, the most recently exported first. 0 means no limit.
+	Limit int
+}
+
+// FlowQuerier queries the aggregated flow records which were exported recently.
+type FlowQuerier interface {
+	QueryFlows(filter *FlowFilter) []FlowRecord
+}
+
+// ParseRuleAction returns the value of the ingressNetworkPolicyRuleAction and egressNetworkPolicyRuleAction
+// Information Elements corresponding to a rule action.
+func ParseRuleAction(action string) (uint8, error) {
+	switch strings.ToLower(action) {
+	case "allow":
+		return ipfixregistry.NetworkPolicyRuleActionAllow, nil
+	case "drop":
+		return ipfixregistry.NetworkPolicyRuleActionDrop, nil
+	case "reject":
+		return ipfixregistry.NetworkPolicyRuleActionReject, nil
+	}
+	return 0, fmt.Errorf("unsupported rule action %s, it must be Allow, Drop or Reject", action)
+}
+
+// FormatRuleAction returns the name of a rule action from the value of the ingressNetworkPolicyRuleAction or
+// egressNetworkPolicyRuleAction Information Element. It is the reverse of ParseRuleAction.
+func FormatRuleAction(action uint8) string {
+	switch action {
+	case ipfixregistry.NetworkPolicyRuleActionAllow:
+		return "Allow"
+	case ipfixregistry.NetworkPolicyRuleActionDrop:
+		return "Drop"
+	case ipfixregistry.NetworkPolicyRuleActionReject:
+		return "Reject"
+	}
+	return ""
+}
+
+// flowStoreEntry is the last flow record exported for a connection.
+type flowStoreEntry struct {
+	key        string
+	record     *FlowRecord
+	exportTime time.Time
+}
+
+// FlowStore is a FlowSink keeping in memory the last flow record exported for each connection during a time
+// window, so that the recent flows can be queried. It implements FlowQuerier.
+type FlowStore struct {
+	mutex    sync.RWMutex
+	window   time.Duration
+	maxFlows int
+	// entries is ordered by export time, the oldest first.
+	entries *list.List
+	// elements indexes the elements of entries by connection.
+	elements map[string]*list.Element
+	// now is used to get the current time, it can be overridden in tests.
+	now func() time.Time
+}
+
+var _ FlowSink = new(FlowStore)
+var _ FlowQuerier = new(FlowStore)
+
+// NewFlowStore returns a FlowStore keeping the flow records exported in the last window, for at most maxFlows
+// connections. The oldest flow records are evicted first.
+func NewFlowStore(window time.Duration, maxFlows int) *FlowStore {
+	return &FlowStore{
+		window:   window,
+		maxFlows: maxFlows,
+		entries:  list.New(),
+		elements: map[string]*list.Element{},
+		now:      time.Now,
+	}
+}
+
+func (s *FlowStore) Name() string {
+	return "store"
+}
+
+func (s *FlowStore) AddFlow(record *FlowRecord) error {
+	key := fmt.Sprintf("%s/%d/%s/%d/%d", record.SourceIP, record.SourceTransportPort, record.DestinationIP, record.DestinationTransportPort, record.ProtocolIdentifier)
+	now := s.now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if element, exists := s.elements[key]; exists {
+		entry := element.Value.(*flowStoreEntry)
+		entry.record = record
+		entry.exportTime = now
+		s.entries.MoveToBack(element)
+	} else {
+		s.elements[key] = s.entries.PushBack(&flowStoreEntry{key: key, record: record, exportTime: now})
+	}
+	s.evict(now)
+	return nil
+}
+
+// evict removes the flow records exported before the window and the oldest flow records beyond maxFlows.
+func (s *FlowStore) evict(now time.Time) {
+	for element := s.entries.Front(); element != nil; element = s.entries.Front() {
+		entry := element.Value.(*flowStoreEntry)
+		if now.Sub(entry.exportTime) <= s.window && s.entries.Len() <= s.maxFlows {
+			return
+		}
+		s.entries.Remove(element)
+		delete(s.elements, entry.key)
+	}
+}
+
+// Flush evicts the flow records which are out of the window, as the FlowStore doesn't buffer flow records.
+func (s *FlowStore) Flush() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.evict(s.now())
+	return nil
+}
+
+func (s *FlowStore) Close() {}
+
+func (s *FlowStore) QueryFlows(filter *FlowFilter) []FlowRecord {
+	var action uint8
+	if filter.Action != "" {
+		var err error
+		// An invalid action doesn't match any flow record.
+		if action, err = ParseRuleAction(filter.Action); err != nil {
+			return nil
+		}
+	}
+	windowStart := s.now().Add(-s.window)
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var records []FlowRecord
+	for element := s.entries.Back(); element != nil; element = element.Prev() {
+		entry := element.Value.(*flowStoreEntry)
+		if entry.exportTime.Before(windowStart) || entry.exportTime.Before(filter.Since) {
+			break
+		}
+		if filter.Action != "" && entry.record.IngressNetworkPolicyRuleAction != action && entry.record.EgressNetworkPolicyRuleAction != action {
+			continue
+		}
+		if !filter.matches(entry.record) {
+			continue
+		}
+		records = append(records, *entry.record)
+		if filter.Limit > 0 && len(records) == filter.Limit {
+			break
+		}
+	}
+	return records
+}
+
+func (f *FlowFilter) matches(r *FlowRecord) bool {
+	matchesPod := func(namespace, name string) bool {
+		return (f.Namespace == "" || f.Namespace == namespace) && (f.PodName == "" || f.PodName == name)
+	}
+	if (f.Namespace != "" || f.PodName != "") &&
+		!matchesPod(r.SourcePodNamespace, r.SourcePodName) && !matchesPod(r.DestinationPodNamespace, r.DestinationPodName) {
+		return false
+	}
+	// The Service port name has format "<namespace>/<name>:<port name>", the port name being optional.
+	if f.Service != "" && r.DestinationServicePortName != f.Service && !strings.HasPrefix(r.DestinationServicePortName, f.Service+":") {
+		return false
+	}
+	if f.PolicyName != "" && r.IngressNetworkPolicyName != f.PolicyName && r.EgressNetworkPolicyName != f.PolicyName {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+)
+
+func TestFlowStoreEviction(t *testing.T) {
+	now := testFlowEndTime
+	store := NewFlowStore(time.Minute, 2)
+	store.now = func() time.Time { return now }
+	addFlow := func(sourcePort uint16) {
+		record := newTestFlowRecord()
+		record.SourceTransportPort = sourcePort
+		require.NoError(t, store.AddFlow(record))
+	}
+	sourcePorts := func() []uint16 {
+		var ports []uint16
+		for _, record := range store.QueryFlows(&FlowFilter{}) {
+			ports = append(ports, record.SourceTransportPort)
+		}
+		return ports
+	}
+
+	addFlow(1)
+	now = now.Add(10 * time.Second)
+	addFlow(2)
+	assert.Equal(t, []uint16{2, 1}, sourcePorts())
+
+	// Updating a flow makes it the most recent one.
+	now = now.Add(10 * time.Second)
+	addFlow(1)
+	assert.Equal(t, []uint16{1, 2}, sourcePorts())
+
+	// The oldest flow is evicted beyond maxFlows.
+	now = now.Add(10 * time.Second)
+	addFlow(3)
+	assert.Equal(t, []uint16{3, 1}, sourcePorts())
+
+	// The flows are evicted out of the window.
+	now = now.Add(55 * time.Second)
+	assert.Equal(t, []uint16{3}, sourcePorts())
+	require.NoError(t, store.Flush())
+	assert.Equal(t, 1, store.entries.Len())
+	assert.Len(t, store.elements, 1)
+}
+
+func TestFlowStoreQueryFlows(t *testing.T) {
+	now := testFlowEndTime
+	store := NewFlowStore(time.Minute, 10)
+	store.now = func() time.Time { return now }
+
+	record1 := newTestFlowRecord()
+	record1.DestinationPodName = "pod2"
+	record1.DestinationPodNamespace = "ns2"
+	record1.DestinationServicePortName = "ns2/svc:http"
+	record1.IngressNetworkPolicyName = "np1"
+	record1.IngressNetworkPolicyRuleAction = ipfixregistry.NetworkPolicyRuleActionAllow
+	require.NoError(t, store.AddFlow(record1))
+
+	now = now.Add(10 * time.Second)
+	record2 := newTestFlowRecord()
+	record2.SourceTransportPort = 5678
+	record2.SourcePodName = "pod3"
+	record2.DestinationPodName = "pod1"
+	record2.DestinationPodNamespace = "ns2"
+	record2.EgressNetworkPolicyName = "np2"
+	record2.EgressNetworkPolicyRuleAction = ipfixregistry.NetworkPolicyRuleActionDrop
+	require.NoError(t, store.AddFlow(record2))
+
+	tests := []struct {
+		name     string
+		filter   FlowFilter
+		expected []FlowRecord
+	}{
+		{"all", FlowFilter{}, []FlowRecord{*record2, *record1}},
+		{"limit", FlowFilter{Limit: 1}, []FlowRecord{*record2}},
+		{"since", FlowFilter{Since: testFlowEndTime.Add(5 * time.Second)}, []FlowRecord{*record2}},
+		{"namespace", FlowFilter{Namespace: "ns2"}, []FlowRecord{*record2, *record1}},
+		{"pod", FlowFilter{PodName: "pod2"}, []FlowRecord{*record1}},
+		{"pod in namespace", FlowFilter{Namespace: "ns2", PodName: "pod1"}, []FlowRecord{*record2}},
+		{"pod not in namespace", FlowFilter{Namespace: "ns2", PodName: "pod3"}, nil},
+		{"service", FlowFilter{Service: "ns2/svc"}, []FlowRecord{*record1}},
+		{"service prefix", FlowFilter{Service: "ns2/sv"}, nil},
+		{"ingress policy", FlowFilter{PolicyName: "np1"}, []FlowRecord{*record1}},
+		{"egress policy", FlowFilter{PolicyName: "np2"}, []FlowRecord{*record2}},
+		{"allow", FlowFilter{Action: "Allow"}, []FlowRecord{*record1}},
+		{"drop", FlowFilter{Action: "drop"}, []FlowRecord{*record2}},
+		{"reject", FlowFilter{Action: "Reject"}, nil},
+		{"invalid action", FlowFilter{Action: "Pass"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, store.QueryFlows(&tt.filter))
+		})
+	}
+}
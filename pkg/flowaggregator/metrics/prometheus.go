@@ -0,0 +1,189 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	metricNamespaceAntrea         = "antrea"
+	metricSubsystemFlowAggregator = "flow_aggregator"
+)
+
+var (
+	NamespaceBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "namespace_bytes_total",
+			Help:           "Number of bytes of the aggregated flows, partitioned by Namespace of the source (egress) or destination (ingress) Pod.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"namespace", "direction"},
+	)
+
+	NamespacePackets = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "namespace_packets_total",
+			Help:           "Number of packets of the aggregated flows, partitioned by Namespace of the source (egress) or destination (ingress) Pod.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"namespace", "direction"},
+	)
+
+	NamespaceConnections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "namespace_connections_total",
+			Help:           "Number of connections of the aggregated flows, partitioned by Namespace of the source (egress) or destination (ingress) Pod.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"namespace", "direction"},
+	)
+
+	ServiceBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "service_bytes_total",
+			Help:           "Number of bytes of the aggregated flows, partitioned by destination Service port.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"service"},
+	)
+
+	ServicePackets = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "service_packets_total",
+			Help:           "Number of packets of the aggregated flows, partitioned by destination Service port.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"service"},
+	)
+
+	ServiceConnections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "service_connections_total",
+			Help:           "Number of connections of the aggregated flows, partitioned by destination Service port.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"service"},
+	)
+
+	NetworkPolicyRuleBytes = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "networkpolicy_rule_bytes_total",
+			Help:           "Number of bytes of the aggregated flows, partitioned by ingress or egress NetworkPolicy rule.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"direction", "policy_namespace", "policy_name", "rule_name", "action"},
+	)
+
+	NetworkPolicyRulePackets = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "networkpolicy_rule_packets_total",
+			Help:           "Number of packets of the aggregated flows, partitioned by ingress or egress NetworkPolicy rule.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"direction", "policy_namespace", "policy_name", "rule_name", "action"},
+	)
+
+	NetworkPolicyRuleConnections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "networkpolicy_rule_connections_total",
+			Help:           "Number of connections of the aggregated flows, partitioned by ingress or egress NetworkPolicy rule.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"direction", "policy_namespace", "policy_name", "rule_name", "action"},
+	)
+
+	TopTalkerBytes = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Namespace:      metricNamespaceAntrea,
+			Subsystem:      metricSubsystemFlowAggregator,
+			Name:           "top_talker_bytes",
+			Help:           "Number of bytes sent and received by the Pods with the most traffic during the last top talker interval.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"namespace", "pod"},
+	)
+)
+
+func InitializePrometheusMetrics() {
+	klog.Info("Initializing prometheus metrics")
+
+	InitializeNamespaceMetrics()
+	InitializeServiceMetrics()
+	InitializeNetworkPolicyRuleMetrics()
+	InitializeTopTalkerMetrics()
+}
+
+func InitializeNamespaceMetrics() {
+	if err := legacyregistry.Register(NamespaceBytes); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_namespace_bytes_total with error: %v", err)
+	}
+	if err := legacyregistry.Register(NamespacePackets); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_namespace_packets_total with error: %v", err)
+	}
+	if err := legacyregistry.Register(NamespaceConnections); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_namespace_connections_total with error: %v", err)
+	}
+}
+
+func InitializeServiceMetrics() {
+	if err := legacyregistry.Register(ServiceBytes); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_service_bytes_total with error: %v", err)
+	}
+	if err := legacyregistry.Register(ServicePackets); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_service_packets_total with error: %v", err)
+	}
+	if err := legacyregistry.Register(ServiceConnections); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_service_connections_total with error: %v", err)
+	}
+}
+
+func InitializeNetworkPolicyRuleMetrics() {
+	if err := legacyregistry.Register(NetworkPolicyRuleBytes); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_networkpolicy_rule_bytes_total with error: %v", err)
+	}
+	if err := legacyregistry.Register(NetworkPolicyRulePackets); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_networkpolicy_rule_packets_total with error: %v", err)
+	}
+	if err := legacyregistry.Register(NetworkPolicyRuleConnections); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_networkpolicy_rule_connections_total with error: %v", err)
+	}
+}
+
+func InitializeTopTalkerMetrics() {
+	if err := legacyregistry.Register(TopTalkerBytes); err != nil {
+		klog.Errorf("Failed to register antrea_flow_aggregator_top_talker_bytes with error: %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
+	ipfixintermediate "github.com/vmware/go-ipfix/pkg/intermediate"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// FlowRecordFormatJSON encodes each flow record as a JSON object.
+	FlowRecordFormatJSON = "json"
+	// FlowRecordFormatCSV encodes each flow record as a line of comma-separated values, in the order of the
+	// fields of FlowRecord.
+	FlowRecordFormatCSV = "csv"
+	// FlowRecordFormatProtobuf encodes each flow record with the FlowType2 protobuf schema of go-ipfix.
+	FlowRecordFormatProtobuf = "protobuf"
+
+	// flowSinkQueueSize is the number of flow records queued for each FlowSink. The flow records are dropped
+	// when the queue of a sink is full, i.e. when the sink cannot keep up with the flow records.
+	flowSinkQueueSize = 10000
+	// flowSinkCloseTimeout is the maximum time to wait for a FlowSink to export its queued flow records when
+	// the Flow Aggregator stops.
+	flowSinkCloseTimeout = 10 * time.Second
+)
+
+// FlowSink is a destination of the aggregated flow records, other than the IPFIX collector. Each FlowSink is
+// run in its own goroutine, so its methods are never called concurrently and may block.
+type FlowSink interface {
+	// Name returns the name of the sink, used in logs.
+	Name() string
+	// AddFlow exports an aggregated flow record. The sink may buffer the record until Flush is called. The
+	// record is shared by all the sinks and must not be modified.
+	AddFlow(record *FlowRecord) error
+	// Flush exports the buffered flow records. It is called at the end of each export cycle.
+	Flush() error
+	// Close flushes the buffered flow records and releases the resources of the sink.
+	Close()
+}
+
+// asyncFlowSink runs a FlowSink in a dedicated goroutine, so that a slow or unavailable destination doesn't
+// delay the export of the flow records to the IPFIX collector and to the other sinks. The flow records are
+// passed to the goroutine through a bounded queue, and dropped when the queue is full.
+type asyncFlowSink struct {
+	sink FlowSink
+	// queue holds the flow records to export, a nil record being a request to flush the sink once the
+	// records queued before it have been exported.
+	queue  chan *FlowRecord
+	doneCh chan struct{}
+	// droppedRecords is the number of flow records dropped since the last flush. It's accessed atomically.
+	droppedRecords uint64
+	// failedRecords and lastErr are the number of flow records which failed to be exported since the last
+	// flush, and the last error. They are only accessed by the goroutine of the sink.
+	failedRecords int
+	lastErr       error
+}
+
+func newAsyncFlowSink(sink FlowSink, queueSize int) *asyncFlowSink {
+	return &asyncFlowSink{
+		sink:   sink,
+		queue:  make(chan *FlowRecord, queueSize),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// run exports the queued flow records until close is called. It must be run in its own goroutine.
+func (s *asyncFlowSink) run() {
+	defer close(s.doneCh)
+	for record := range s.queue {
+		if record == nil {
+			s.flushSink()
+			continue
+		}
+		if err := s.sink.AddFlow(record); err != nil {
+			s.failedRecords++
+			s.lastErr = err
+		}
+	}
+	s.sink.Close()
+}
+
+// flushSink flushes the sink, and reports the flow records which failed to be exported or were dropped
+// since the last flush, so that a failing sink logs once per export cycle instead of once per record.
+func (s *asyncFlowSink) flushSink() {
+	if err := s.sink.Flush(); err != nil {
+		klog.ErrorS(err, "Error when flushing flow records", "sink", s.sink.Name())
+	}
+	if s.failedRecords > 0 {
+		klog.ErrorS(s.lastErr, "Error when exporting flow records", "sink", s.sink.Name(), "count", s.failedRecords)
+		s.failedRecords = 0
+		s.lastErr = nil
+	}
+	if dropped := atomic.SwapUint64(&s.droppedRecords, 0); dropped > 0 {
+		klog.InfoS("Dropped flow records as the queue of the sink was full", "sink", s.sink.Name(), "count", dropped)
+	}
+}
+
+// addFlow queues the flow record without blocking. The record is dropped if the queue is full.
+func (s *asyncFlowSink) addFlow(record *FlowRecord) {
+	select {
+	case s.queue <- record:
+	default:
+		atomic.AddUint64(&s.droppedRecords, 1)
+	}
+}
+
+// flush requests the sink to export its buffered flow records, after the flow records queued so far. The
+// request is dropped if the queue is full, the sink being flushed at the end of the next export cycle.
+func (s *asyncFlowSink) flush() {
+	select {
+	case s.queue <- nil:
+	default:
+	}
+}
+
+// close exports the queued flow records and closes the sink, waiting up to flowSinkCloseTimeout. It must be
+// called by the goroutine calling addFlow and flush, after its last call to them.
+func (s *asyncFlowSink) close() {
+	close(s.queue)
+	select {
+	case <-s.doneCh:
+	case <-time.After(flowSinkCloseTimeout):
+		klog.InfoS("Timed out when closing the sink", "sink", s.sink.Name())
+	}
+}
+
+// FlowRecord is an aggregated flow record as exported to the FlowSinks. The field names match the names of the
+// corresponding IPFIX Information Elements.
+type FlowRecord struct {
+	FlowStartSeconds               time.Time `json:"flowStartSeconds"`
+	FlowEndSeconds                 time.Time `json:"flowEndSeconds"`
+	FlowEndReason                  uint8     `json:"flowEndReason"`
+	SourceIP                       string    `json:"sourceIP"`
+	DestinationIP                  string    `json:"destinationIP"`
+	SourceTransportPort            uint16    `json:"sourceTransportPort"`
+	DestinationTransportPort       uint16    `json:"destinationTransportPort"`
+	ProtocolIdentifier             uint8     `json:"protocolIdentifier"`
+	PacketTotalCount               uint64    `json:"packetTotalCount"`
+	OctetTotalCount                uint64    `json:"octetTotalCount"`
+	PacketDeltaCount               uint64    `json:"packetDeltaCount"`
+	OctetDeltaCount                uint64    `json:"octetDeltaCount"`
+	SamplingInterval               uint32    `json:"samplingInterval"`
+	ReversePacketTotalCount        uint64    `json:"reversePacketTotalCount"`
+	ReverseOctetTotalCount         uint64    `json:"reverseOctetTotalCount"`
+	ReversePacketDeltaCount        uint64    `json:"reversePacketDeltaCount"`
+	ReverseOctetDeltaCount         uint64    `json:"reverseOctetDeltaCount"`
+	SourcePodName                  string    `json:"sourcePodName"`
+	SourcePodNamespace             string    `json:"sourcePodNamespace"`
+	SourceNodeName                 string    `json:"sourceNodeName"`
+	DestinationPodName             string    `json:"destinationPodName"`
+	DestinationPodNamespace        string    `json:"destinationPodNamespace"`
+	DestinationNodeName            string    `json:"destinationNodeName"`
+	DestinationClusterIP           string    `json:"destinationClusterIP"`
+	DestinationServicePort         uint16    `json:"destinationServicePort"`
+	DestinationServicePortName     string    `json:"destinationServicePortName"`
+	IngressNetworkPolicyName       string    `json:"ingressNetworkPolicyName"`
+	IngressNetworkPolicyNamespace  string    `json:"ingressNetworkPolicyNamespace"`
+	IngressNetworkPolicyType       uint8     `json:"ingressNetworkPolicyType"`
+	IngressNetworkPolicyRuleName   string    `json:"ingressNetworkPolicyRuleName"`
+	IngressNetworkPolicyRuleAction uint8     `json:"ingressNetworkPolicyRuleAction"`
+	EgressNetworkPolicyName        string    `json:"egressNetworkPolicyName"`
+	EgressNetworkPolicyNamespace   string    `json:"egressNetworkPolicyNamespace"`
+	EgressNetworkPolicyType        uint8     `json:"egressNetworkPolicyType"`
+	EgressNetworkPolicyRuleName    string    `json:"egressNetworkPolicyRuleName"`
+	EgressNetworkPolicyRuleAction  uint8     `json:"egressNetworkPolicyRuleAction"`
+	TCPState                       string    `json:"tcpState"`
+	FlowType                       uint8     `json:"flowType"`
+	SourcePodLabels                string    `json:"sourcePodLabels"`
+	DestinationPodLabels           string    `json:"destinationPodLabels"`
+}
+
+// newFlowRecord converts an aggregated IPFIX record to a FlowRecord.
+func newFlowRecord(key ipfixintermediate.FlowKey, record ipfixentities.Record) *FlowRecord {
+	r := &FlowRecord{
+		SourceIP:                 key.SourceAddress,
+		DestinationIP:            key.DestinationAddress,
+		SourceTransportPort:      key.SourcePort,
+		DestinationTransportPort: key.DestinationPort,
+		ProtocolIdentifier:       key.Protocol,
+	}
+	getValue := func(name string) interface{} {
+		if ie, exist := record.GetInfoElementWithValue(name); exist {
+			return ie.Value
+		}
+		return nil
+	}
+	getString := func(name string) string {
+		switch v := getValue(name).(type) {
+		case string:
+			return v
+		case []byte:
+			return string(v)
+		case net.IP:
+			if v.IsUnspecified() {
+				return ""
+			}
+			return v.String()
+		}
+		return ""
+	}
+	getUint8 := func(name string) uint8 {
+		v, _ := getValue(name).(uint8)
+		return v
+	}
+	getUint16 := func(name string) uint16 {
+		v, _ := getValue(name).(uint16)
+		return v
+	}
+	getUint32 := func(name string) uint32 {
+		v, _ := getValue(name).(uint32)
+		return v
+	}
+	getUint64 := func(name string) uint64 {
+		v, _ := getValue(name).(uint64)
+		return v
+	}
+	getTime := func(name string) time.Time {
+		if v, ok := getValue(name).(uint32); ok && v != 0 {
+			return time.Unix(int64(v), 0).UTC()
+		}
+		return time.Time{}
+	}
+
+	r.FlowStartSeconds = getTime("flowStartSeconds")
+	r.FlowEndSeconds = getTime("flowEndSeconds")
+	r.FlowEndReason = getUint8("flowEndReason")
+	r.PacketTotalCount = getUint64("packetTotalCount")
+	r.OctetTotalCount = getUint64("octetTotalCount")
+	r.PacketDeltaCount = getUint64("packetDeltaCount")
+	r.OctetDeltaCount = getUint64("octetDeltaCount")
+	r.SamplingInterval = getUint32("samplingInterval")
+	r.ReversePacketTotalCount = getUint64("reversePacketTotalCount")
+	r.ReverseOctetTotalCount = getUint64("reverseOctetTotalCount")
+	r.ReversePacketDeltaCount = getUint64("reversePacketDeltaCount")
+	r.ReverseOctetDeltaCount = getUint64("reverseOctetDeltaCount")
+	r.SourcePodName = getString("sourcePodName")
+	r.SourcePodNamespace = getString("sourcePodNamespace")
+	r.SourceNodeName = getString("sourceNodeName")
+	r.DestinationPodName = getString("destinationPodName")
+	r.DestinationPodNamespace = getString("destinationPodNamespace")
+	r.DestinationNodeName = getString("destinationNodeName")
+	r.DestinationClusterIP = getString("destinationClusterIPv4")
+	if r.DestinationClusterIP == "" {
+		r.DestinationClusterIP = getString("destinationClusterIPv6")
+	}
+	r.DestinationServicePort = getUint16("destinationServicePort")
+	r.DestinationServicePortName = getString("destinationServicePortName")
+	r.IngressNetworkPolicyName = getString("ingressNetworkPolicyName")
+	r.IngressNetworkPolicyNamespace = getString("ingressNetworkPolicyNamespace")
+	r.IngressNetworkPolicyType = getUint8("ingressNetworkPolicyType")
+	r.IngressNetworkPolicyRuleName = getString("ingressNetworkPolicyRuleName")
+	r.IngressNetworkPolicyRuleAction = getUint8("ingressNetworkPolicyRuleAction")
+	r.EgressNetworkPolicyName = getString("egressNetworkPolicyName")
+	r.EgressNetworkPolicyNamespace = getString("egressNetworkPolicyNamespace")
+	r.EgressNetworkPolicyType = getUint8("egressNetworkPolicyType")
+	r.EgressNetworkPolicyRuleName = getString("egressNetworkPolicyRuleName")
+	r.EgressNetworkPolicyRuleAction = getUint8("egressNetworkPolicyRuleAction")
+	r.TCPState = getString("tcpState")
+	r.FlowType = getUint8("flowType")
+	r.SourcePodLabels = getString("sourcePodLabels")
+	r.DestinationPodLabels = getString("destinationPodLabels")
+	return r
+}
+
+// flowRecordCSVHeader returns the names of the columns of the CSV encoding of a FlowRecord.
+func flowRecordCSVHeader() []string {
+	t := reflect.TypeOf(FlowRecord{})
+	header := make([]string, t.NumField())
+	for i := range header {
+		header[i] = strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+	}
+	return header
+}
+
+// csvRow returns the values of the CSV encoding of the FlowRecord.
+func (r *FlowRecord) csvRow() []string {
+	v := reflect.ValueOf(r).Elem()
+	row := make([]string, v.NumField())
+	for i := range row {
+		switch field := v.Field(i).Interface().(type) {
+		case time.Time:
+			if !field.IsZero() {
+				row[i] = field.Format(time.RFC3339)
+			}
+		default:
+			row[i] = fmt.Sprint(field)
+		}
+	}
+	return row
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	clickHouseRequestTimeout = 30 * time.Second
+	// clickHouseRetryInterval is the minimum interval before a full batch is inserted again after a failed
+	// insertion. The buffered records are still inserted at the end of each export cycle.
+	clickHouseRetryInterval = 10 * time.Second
+	// clickHouseMaxBufferedBatches is the number of batches which are kept in memory while the ClickHouse
+	// server is unavailable. The oldest records are dropped beyond this limit.
+	clickHouseMaxBufferedBatches = 10
+)
+
+// ClickHouseSinkConfig is the configuration of a FlowSink inserting the flow records into a ClickHouse table.
+type ClickHouseSinkConfig struct {
+	// Address is the URL of the HTTP interface of the ClickHouse server, e.g. "http://clickhouse:8123".
+	Address string
+	// Database is the database of the table.
+	Database string
+	// Table is the table into which the flow records are inserted. Its columns must be named after the
+	// JSON fields of FlowRecord.
+	Table string
+	// Username and Password are the credentials used to authenticate to the ClickHouse server.
+	Username string
+	Password string
+	// BatchSize is the maximum number of flow records inserted with a single query. The buffered records are
+	// also inserted at the end of each export cycle.
+	BatchSize int
+}
+
+type clickHouseSink struct {
+	config  ClickHouseSinkConfig
+	client  *http.Client
+	url     string
+	records []*FlowRecord
+	// nextInsert is the earliest time at which a full batch is inserted after a failed insertion.
+	nextInsert time.Time
+}
+
+// NewClickHouseSink returns a FlowSink inserting the flow records into a ClickHouse table by batches, using
+// the HTTP interface of the ClickHouse server.
+func NewClickHouseSink(config ClickHouseSinkConfig) (FlowSink, error) {
+	u, err := url.Parse(config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ClickHouse address %s: %v", config.Address, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid ClickHouse address %s: scheme must be http or https", config.Address)
+	}
+	if config.Table == "" {
+		return nil, fmt.Errorf("ClickHouse table must be provided")
+	}
+	if config.BatchSize <= 0 {
+		return nil, fmt.Errorf("invalid ClickHouse batch size %d", config.BatchSize)
+	}
+	table := config.Table
+	if config.Database != "" {
+		table = config.Database + "." + table
+	}
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", table))
+	// Parse the RFC 3339 timestamps of the JSON encoding.
+	query.Set("date_time_input_format", "best_effort")
+	u.RawQuery = query.Encode()
+	return &clickHouseSink{
+		config: config,
+		client: &http.Client{Timeout: clickHouseRequestTimeout},
+		url:    u.String(),
+	}, nil
+}
+
+func (s *clickHouseSink) Name() string {
+	return "clickhouse"
+}
+
+func (s *clickHouseSink) AddFlow(record *FlowRecord) error {
+	if maxRecords := s.config.BatchSize * clickHouseMaxBufferedBatches; len(s.records) >= maxRecords {
+		klog.Warningf("Dropping %d flow records buffered for ClickHouse", s.config.BatchSize)
+		s.records = s.records[s.config.BatchSize:]
+	}
+	s.records = append(s.records, record)
+	if len(s.records)%s.config.BatchSize == 0 && !time.Now().Before(s.nextInsert) {
+		return s.Flush()
+	}
+	return nil
+}
+
+func (s *clickHouseSink) Flush() error {
+	for len(s.records) > 0 {
+		n := s.config.BatchSize
+		if n > len(s.records) {
+			n = len(s.records)
+		}
+		if err := s.insert(s.records[:n]); err != nil {
+			// The records are kept to be inserted again with the next batch.
+			s.nextInsert = time.Now().Add(clickHouseRetryInterval)
+			return err
+		}
+		s.records = s.records[n:]
+	}
+	// Release the memory of the underlying array.
+	s.records = nil
+	return nil
+}
+
+func (s *clickHouseSink) insert(records []*FlowRecord) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("error when encoding flow record: %v", err)
+		}
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, &body)
+	if err != nil {
+		return err
+	}
+	if s.config.Username != "" {
+		req.SetBasicAuth(s.config.Username, s.config.Password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error when inserting flow records into ClickHouse: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("error when inserting flow records into ClickHouse: %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	klog.V(4).InfoS("Inserted flow records into ClickHouse", "count", len(records))
+	return nil
+}
+
+func (s *clickHouseSink) Close() {
+	if err := s.Flush(); err != nil {
+		klog.ErrorS(err, "Error when flushing flow records", "sink", s.Name())
+	}
+}
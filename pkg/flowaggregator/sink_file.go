@@ -0,0 +1,102 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+	"k8s.io/klog/v2"
+)
+
+// FileSinkConfig is the configuration of a FlowSink writing the flow records to a rotated local file.
+type FileSinkConfig struct {
+	// Path is the path of the file.
+	Path string
+	// Format is FlowRecordFormatJSON (one JSON object per line) or FlowRecordFormatCSV.
+	Format string
+	// MaxSize is the maximum size in megabytes of the file before it gets rotated.
+	MaxSize int
+	// MaxBackups is the maximum number of rotated files to retain.
+	MaxBackups int
+	// Compress determines whether the rotated files are compressed with gzip.
+	Compress bool
+}
+
+type fileSink struct {
+	output    io.WriteCloser
+	writer    *bufio.Writer
+	encoder   *json.Encoder
+	csvWriter *csv.Writer
+}
+
+// NewFileSink returns a FlowSink writing the flow records to a rotated local file.
+func NewFileSink(config FileSinkConfig) (FlowSink, error) {
+	output := &lumberjack.Logger{
+		Filename:   config.Path,
+		MaxSize:    config.MaxSize,
+		MaxBackups: config.MaxBackups,
+		Compress:   config.Compress,
+	}
+	return newFileSink(output, config.Format)
+}
+
+func newFileSink(output io.WriteCloser, format string) (*fileSink, error) {
+	s := &fileSink{
+		output: output,
+		writer: bufio.NewWriter(output),
+	}
+	switch format {
+	case FlowRecordFormatJSON:
+		s.encoder = json.NewEncoder(s.writer)
+	case FlowRecordFormatCSV:
+		s.csvWriter = csv.NewWriter(s.writer)
+	default:
+		return nil, fmt.Errorf("unsupported format %s for file sink", format)
+	}
+	return s, nil
+}
+
+func (s *fileSink) Name() string {
+	return "file"
+}
+
+func (s *fileSink) AddFlow(record *FlowRecord) error {
+	if s.encoder != nil {
+		return s.encoder.Encode(record)
+	}
+	return s.csvWriter.Write(record.csvRow())
+}
+
+func (s *fileSink) Flush() error {
+	if s.csvWriter != nil {
+		s.csvWriter.Flush()
+		if err := s.csvWriter.Error(); err != nil {
+			return err
+		}
+	}
+	return s.writer.Flush()
+}
+
+func (s *fileSink) Close() {
+	if err := s.Flush(); err != nil {
+		klog.ErrorS(err, "Error when flushing flow records", "sink", s.Name())
+	}
+	s.output.Close()
+}
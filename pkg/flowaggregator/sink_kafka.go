@@ -0,0 +1,186 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/vmware/go-ipfix/pkg/producer/protobuf"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+)
+
+// kafkaProducerRetryInterval is the minimum interval between two attempts to create the Kafka producer. The
+// flow records exported in the meantime are dropped.
+const kafkaProducerRetryInterval = 10 * time.Second
+
+// KafkaSinkConfig is the configuration of a FlowSink publishing the flow records to a Kafka topic.
+type KafkaSinkConfig struct {
+	// Brokers are the addresses of the Kafka brokers, as "host:port".
+	Brokers []string
+	// Topic is the Kafka topic to which the flow records are published.
+	Topic string
+	// Format is FlowRecordFormatJSON or FlowRecordFormatProtobuf.
+	Format string
+	// Version is the version of the Kafka brokers. It defaults to the oldest version supported by the client.
+	Version string
+}
+
+type kafkaSink struct {
+	topic       string
+	format      string
+	newProducer func() (sarama.AsyncProducer, error)
+	producer    sarama.AsyncProducer
+	// nextProducerRetry is the earliest time at which the producer can be created again after a failure.
+	nextProducerRetry time.Time
+}
+
+// NewKafkaSink returns a FlowSink publishing each flow record as a message to a Kafka topic. The connection
+// to the brokers is established when the first flow record is exported, and retried every
+// kafkaProducerRetryInterval until it succeeds.
+func NewKafkaSink(config KafkaSinkConfig) (FlowSink, error) {
+	kafkaConfig := sarama.NewConfig()
+	kafkaConfig.ClientID = "flow-aggregator"
+	if config.Version != "" {
+		version, err := sarama.ParseKafkaVersion(config.Version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Kafka version %s: %v", config.Version, err)
+		}
+		kafkaConfig.Version = version
+	}
+	if err := kafkaConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Kafka configuration: %v", err)
+	}
+	newProducer := func() (sarama.AsyncProducer, error) {
+		return sarama.NewAsyncProducer(config.Brokers, kafkaConfig)
+	}
+	return newKafkaSink(config.Topic, config.Format, newProducer)
+}
+
+func newKafkaSink(topic, format string, newProducer func() (sarama.AsyncProducer, error)) (*kafkaSink, error) {
+	if format != FlowRecordFormatJSON && format != FlowRecordFormatProtobuf {
+		return nil, fmt.Errorf("unsupported format %s for Kafka sink", format)
+	}
+	return &kafkaSink{
+		topic:       topic,
+		format:      format,
+		newProducer: newProducer,
+	}, nil
+}
+
+func (s *kafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *kafkaSink) AddFlow(record *FlowRecord) error {
+	if s.producer == nil {
+		if time.Now().Before(s.nextProducerRetry) {
+			return fmt.Errorf("no Kafka producer available, retrying at %s", s.nextProducerRetry.Format(time.RFC3339))
+		}
+		producer, err := s.newProducer()
+		if err != nil {
+			s.nextProducerRetry = time.Now().Add(kafkaProducerRetryInterval)
+			return fmt.Errorf("error when creating Kafka producer: %v", err)
+		}
+		// The errors must be consumed from the producer, otherwise it will deadlock.
+		go func() {
+			for err := range producer.Errors() {
+				klog.ErrorS(err, "Error when publishing flow record to Kafka")
+			}
+		}()
+		s.producer = producer
+	}
+	var value []byte
+	var err error
+	if s.format == FlowRecordFormatJSON {
+		value, err = json.Marshal(record)
+	} else {
+		value, err = proto.Marshal(record.toProtobuf())
+	}
+	if err != nil {
+		return fmt.Errorf("error when encoding flow record: %v", err)
+	}
+	// The message is dropped instead of blocking when the buffer of the producer is full, e.g. when the
+	// brokers cannot be reached.
+	select {
+	case s.producer.Input() <- &sarama.ProducerMessage{
+		Topic: s.topic,
+		Value: sarama.ByteEncoder(value),
+	}:
+		return nil
+	default:
+		return fmt.Errorf("buffer of Kafka producer is full")
+	}
+}
+
+// Flush is a no-op as the producer publishes the messages asynchronously.
+func (s *kafkaSink) Flush() error {
+	return nil
+}
+
+func (s *kafkaSink) Close() {
+	if s.producer == nil {
+		return
+	}
+	// Close publishes the buffered messages before closing the producer.
+	if err := s.producer.Close(); err != nil {
+		klog.ErrorS(err, "Error when closing Kafka producer")
+	}
+	s.producer = nil
+}
+
+// toProtobuf converts the FlowRecord to a message with the FlowType2 protobuf schema of go-ipfix.
+func (r *FlowRecord) toProtobuf() *protobuf.FlowType2 {
+	flowMsg := &protobuf.FlowType2{
+		FlowEndReason:          uint32(r.FlowEndReason),
+		TcpState:               r.TCPState,
+		SrcIP:                  r.SourceIP,
+		DstIP:                  r.DestinationIP,
+		SrcPort:                uint32(r.SourceTransportPort),
+		DstPort:                uint32(r.DestinationTransportPort),
+		Proto:                  uint32(r.ProtocolIdentifier),
+		PacketsTotal:           r.PacketTotalCount,
+		BytesTotal:             r.OctetTotalCount,
+		PacketsDelta:           r.PacketDeltaCount,
+		BytesDelta:             r.OctetDeltaCount,
+		ReversePacketsTotal:    r.ReversePacketTotalCount,
+		ReverseBytesTotal:      r.ReverseOctetTotalCount,
+		ReversePacketsDelta:    r.ReversePacketDeltaCount,
+		ReverseBytesDelta:      r.ReverseOctetDeltaCount,
+		SrcPodName:             r.SourcePodName,
+		SrcPodNamespace:        r.SourcePodNamespace,
+		SrcNodeName:            r.SourceNodeName,
+		DstPodName:             r.DestinationPodName,
+		DstPodNamespace:        r.DestinationPodNamespace,
+		DstNodeName:            r.DestinationNodeName,
+		DstClusterIP:           r.DestinationClusterIP,
+		DstServicePort:         uint32(r.DestinationServicePort),
+		DstServicePortName:     r.DestinationServicePortName,
+		IngressPolicyName:      r.IngressNetworkPolicyName,
+		IngressPolicyNamespace: r.IngressNetworkPolicyNamespace,
+		EgressPolicyName:       r.EgressNetworkPolicyName,
+		EgressPolicyNamespace:  r.EgressNetworkPolicyNamespace,
+	}
+	if !r.FlowStartSeconds.IsZero() {
+		flowMsg.TimeFlowStartInSecs = uint32(r.FlowStartSeconds.Unix())
+	}
+	if !r.FlowEndSeconds.IsZero() {
+		flowMsg.TimeFlowEndInSecs = uint32(r.FlowEndSeconds.Unix())
+	}
+	return flowMsg
+}
@@ -0,0 +1,259 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"antrea.io/antrea/pkg/flowaggregator/metrics"
+)
+
+const (
+	// overflowLabelValue replaces the values of the labels of a metric once its cardinality limit is reached.
+	overflowLabelValue = "_other"
+
+	directionIngress = "ingress"
+	directionEgress  = "egress"
+)
+
+// MetricsSinkConfig is the configuration of a FlowSink computing Prometheus metrics from the flow records.
+type MetricsSinkConfig struct {
+	// MaxNamespaces is the maximum number of Namespaces for which the Namespace metrics are reported.
+	MaxNamespaces int
+	// MaxServices is the maximum number of Service ports for which the Service metrics are reported.
+	MaxServices int
+	// MaxNetworkPolicyRules is the maximum number of NetworkPolicy rules for which the NetworkPolicy rule
+	// metrics are reported.
+	MaxNetworkPolicyRules int
+	// TopTalkers is the number of Pods reported by the top talker metric.
+	TopTalkers int
+	// TopTalkerInterval is the interval over which the traffic of the top talkers is computed.
+	TopTalkerInterval time.Duration
+}
+
+// labelLimiter limits the number of distinct label sets of a metric. The label sets beyond the limit are
+// reported with overflowLabelValue.
+type labelLimiter struct {
+	max    int
+	values map[string]*labelSet
+}
+
+type labelSet struct {
+	values []string
+	// used is whether the label set has been used since the last call to expire.
+	used bool
+}
+
+func newLabelLimiter(max int) *labelLimiter {
+	return &labelLimiter{max: max, values: map[string]*labelSet{}}
+}
+
+// allow returns whether the label values can be used, or must be replaced with overflowLabelValue.
+func (l *labelLimiter) allow(labelValues ...string) bool {
+	key := strings.Join(labelValues, "/")
+	if set, exists := l.values[key]; exists {
+		set.used = true
+		return true
+	}
+	if len(l.values) >= l.max {
+		return false
+	}
+	l.values[key] = &labelSet{values: labelValues, used: true}
+	return true
+}
+
+// expire removes the label sets which haven't been used since the previous call, so that new label sets can take
+// their place, and returns their values.
+func (l *labelLimiter) expire() [][]string {
+	var expired [][]string
+	for key, set := range l.values {
+		if !set.used {
+			expired = append(expired, set.values)
+			delete(l.values, key)
+		} else {
+			set.used = false
+		}
+	}
+	return expired
+}
+
+type podKey struct {
+	namespace string
+	name      string
+}
+
+type metricsSink struct {
+	config            MetricsSinkConfig
+	namespaces        *labelLimiter
+	services          *labelLimiter
+	rules             *labelLimiter
+	podBytes          map[podKey]uint64
+	lastTopTalkerTime time.Time
+	// now is used to get the current time, it can be overridden in tests.
+	now func() time.Time
+}
+
+// NewMetricsSink returns a FlowSink updating the per-Namespace, per-Service and per-NetworkPolicy-rule
+// Prometheus metrics and the top talker metric with the flow records. The metrics must have been registered
+// with metrics.InitializePrometheusMetrics.
+func NewMetricsSink(config MetricsSinkConfig) FlowSink {
+	return newMetricsSink(config, time.Now)
+}
+
+func newMetricsSink(config MetricsSinkConfig, now func() time.Time) *metricsSink {
+	return &metricsSink{
+		config:            config,
+		namespaces:        newLabelLimiter(config.MaxNamespaces),
+		services:          newLabelLimiter(config.MaxServices),
+		rules:             newLabelLimiter(config.MaxNetworkPolicyRules),
+		podBytes:          map[podKey]uint64{},
+		lastTopTalkerTime: now(),
+		now:               now,
+	}
+}
+
+func (s *metricsSink) Name() string {
+	return "metrics"
+}
+
+func (s *metricsSink) AddFlow(record *FlowRecord) error {
+	// The delta counts are the traffic since the previous export of the flow record.
+	bytes := float64(record.OctetDeltaCount + record.ReverseOctetDeltaCount)
+	packets := float64(record.PacketDeltaCount + record.ReversePacketDeltaCount)
+	// The delta counts are reset after each export, so they are equal to the total counts only the first time
+	// a connection is exported.
+	connections := float64(0)
+	if record.PacketTotalCount+record.ReversePacketTotalCount > 0 &&
+		record.PacketDeltaCount == record.PacketTotalCount && record.ReversePacketDeltaCount == record.ReversePacketTotalCount {
+		connections = 1
+	}
+
+	addNamespace := func(namespace, direction string) {
+		if namespace == "" {
+			return
+		}
+		if !s.namespaces.allow(namespace) {
+			namespace = overflowLabelValue
+		}
+		metrics.NamespaceBytes.WithLabelValues(namespace, direction).Add(bytes)
+		metrics.NamespacePackets.WithLabelValues(namespace, direction).Add(packets)
+		metrics.NamespaceConnections.WithLabelValues(namespace, direction).Add(connections)
+	}
+	addNamespace(record.SourcePodNamespace, directionEgress)
+	addNamespace(record.DestinationPodNamespace, directionIngress)
+
+	if service := record.DestinationServicePortName; service != "" {
+		if !s.services.allow(service) {
+			service = overflowLabelValue
+		}
+		metrics.ServiceBytes.WithLabelValues(service).Add(bytes)
+		metrics.ServicePackets.WithLabelValues(service).Add(packets)
+		metrics.ServiceConnections.WithLabelValues(service).Add(connections)
+	}
+
+	addRule := func(direction, namespace, name, ruleName string, action uint8) {
+		if name == "" {
+			return
+		}
+		labelValues := []string{direction, namespace, name, ruleName, FormatRuleAction(action)}
+		if !s.rules.allow(labelValues...) {
+			labelValues = []string{direction, overflowLabelValue, overflowLabelValue, overflowLabelValue, overflowLabelValue}
+		}
+		metrics.NetworkPolicyRuleBytes.WithLabelValues(labelValues...).Add(bytes)
+		metrics.NetworkPolicyRulePackets.WithLabelValues(labelValues...).Add(packets)
+		metrics.NetworkPolicyRuleConnections.WithLabelValues(labelValues...).Add(connections)
+	}
+	addRule(directionIngress, record.IngressNetworkPolicyNamespace, record.IngressNetworkPolicyName,
+		record.IngressNetworkPolicyRuleName, record.IngressNetworkPolicyRuleAction)
+	addRule(directionEgress, record.EgressNetworkPolicyNamespace, record.EgressNetworkPolicyName,
+		record.EgressNetworkPolicyRuleName, record.EgressNetworkPolicyRuleAction)
+
+	if record.SourcePodName != "" {
+		s.podBytes[podKey{record.SourcePodNamespace, record.SourcePodName}] += uint64(bytes)
+	}
+	if record.DestinationPodName != "" {
+		s.podBytes[podKey{record.DestinationPodNamespace, record.DestinationPodName}] += uint64(bytes)
+	}
+	return nil
+}
+
+// Flush updates the top talker metric and removes the metrics of the label sets without traffic once per
+// TopTalkerInterval.
+func (s *metricsSink) Flush() error {
+	now := s.now()
+	if now.Sub(s.lastTopTalkerTime) < s.config.TopTalkerInterval {
+		return nil
+	}
+	s.lastTopTalkerTime = now
+	s.deleteExpiredMetrics()
+
+	pods := make([]podKey, 0, len(s.podBytes))
+	for pod := range s.podBytes {
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if s.podBytes[pods[i]] != s.podBytes[pods[j]] {
+			return s.podBytes[pods[i]] > s.podBytes[pods[j]]
+		}
+		if pods[i].namespace != pods[j].namespace {
+			return pods[i].namespace < pods[j].namespace
+		}
+		return pods[i].name < pods[j].name
+	})
+	if len(pods) > s.config.TopTalkers {
+		pods = pods[:s.config.TopTalkers]
+	}
+	metrics.TopTalkerBytes.Reset()
+	for _, pod := range pods {
+		metrics.TopTalkerBytes.WithLabelValues(pod.namespace, pod.name).Set(float64(s.podBytes[pod]))
+	}
+	s.podBytes = map[podKey]uint64{}
+	return nil
+}
+
+// deleteExpiredMetrics deletes the metrics of the Namespaces, Service ports and NetworkPolicy rules which had no
+// traffic during the last TopTalkerInterval, so that they no longer count against the cardinality limits.
+func (s *metricsSink) deleteExpiredMetrics() {
+	for _, labelValues := range s.namespaces.expire() {
+		for _, direction := range []string{directionIngress, directionEgress} {
+			labels := map[string]string{"namespace": labelValues[0], "direction": direction}
+			metrics.NamespaceBytes.Delete(labels)
+			metrics.NamespacePackets.Delete(labels)
+			metrics.NamespaceConnections.Delete(labels)
+		}
+	}
+	for _, labelValues := range s.services.expire() {
+		labels := map[string]string{"service": labelValues[0]}
+		metrics.ServiceBytes.Delete(labels)
+		metrics.ServicePackets.Delete(labels)
+		metrics.ServiceConnections.Delete(labels)
+	}
+	for _, labelValues := range s.rules.expire() {
+		labels := map[string]string{
+			"direction":        labelValues[0],
+			"policy_namespace": labelValues[1],
+			"policy_name":      labelValues[2],
+			"rule_name":        labelValues[3],
+			"action":           labelValues[4],
+		}
+		metrics.NetworkPolicyRuleBytes.Delete(labels)
+		metrics.NetworkPolicyRulePackets.Delete(labels)
+		metrics.NetworkPolicyRuleConnections.Delete(labels)
+	}
+}
+
+func (s *metricsSink) Close() {}
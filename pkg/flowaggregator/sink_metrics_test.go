@@ -0,0 +1,162 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"antrea.io/antrea/pkg/flowaggregator/metrics"
+)
+
+func TestMetricsSink(t *testing.T) {
+	metrics.InitializePrometheusMetrics()
+	now := testFlowEndTime
+	sink := newMetricsSink(MetricsSinkConfig{
+		MaxNamespaces:         2,
+		MaxServices:           1,
+		MaxNetworkPolicyRules: 1,
+		TopTalkers:            2,
+		TopTalkerInterval:     time.Minute,
+	}, func() time.Time { return now })
+
+	// First export of a connection from ns1/pod1 to ns2/pod2 through Service ns2/svc:http.
+	record1 := newTestFlowRecord()
+	record1.PacketDeltaCount = 10
+	record1.OctetDeltaCount = 1000
+	record1.ReversePacketTotalCount = 5
+	record1.ReverseOctetTotalCount = 500
+	record1.ReversePacketDeltaCount = 5
+	record1.ReverseOctetDeltaCount = 500
+	record1.DestinationPodName = "pod2"
+	record1.DestinationPodNamespace = "ns2"
+	record1.DestinationServicePortName = "ns2/svc:http"
+	record1.IngressNetworkPolicyNamespace = "ns2"
+	record1.IngressNetworkPolicyName = "np1"
+	record1.IngressNetworkPolicyRuleName = "rule1"
+	record1.IngressNetworkPolicyRuleAction = ipfixregistry.NetworkPolicyRuleActionAllow
+	require.NoError(t, sink.AddFlow(record1))
+
+	// Second export of the same connection, which is not counted as a new connection.
+	record2 := *record1
+	record2.PacketTotalCount = 12
+	record2.OctetTotalCount = 1200
+	record2.PacketDeltaCount = 2
+	record2.OctetDeltaCount = 200
+	record2.ReversePacketDeltaCount = 0
+	record2.ReverseOctetDeltaCount = 0
+	require.NoError(t, sink.AddFlow(&record2))
+
+	// A connection from ns3/pod3 to an external IP, dropped by an egress rule. The limits of Namespaces and
+	// NetworkPolicy rules are reached.
+	record3 := newTestFlowRecord()
+	record3.SourcePodName = "pod3"
+	record3.SourcePodNamespace = "ns3"
+	record3.PacketDeltaCount = 10
+	record3.OctetDeltaCount = 100
+	record3.OctetTotalCount = 100
+	record3.DestinationServicePortName = ""
+	record3.EgressNetworkPolicyName = "acnp1"
+	record3.EgressNetworkPolicyRuleName = "rule2"
+	record3.EgressNetworkPolicyRuleAction = ipfixregistry.NetworkPolicyRuleActionDrop
+	require.NoError(t, sink.AddFlow(record3))
+
+	expected := `
+# HELP antrea_flow_aggregator_namespace_bytes_total [ALPHA] Number of bytes of the aggregated flows, partitioned by Namespace of the source (egress) or destination (ingress) Pod.
+# TYPE antrea_flow_aggregator_namespace_bytes_total counter
+antrea_flow_aggregator_namespace_bytes_total{direction="egress",namespace="_other"} 100
+antrea_flow_aggregator_namespace_bytes_total{direction="egress",namespace="ns1"} 1700
+antrea_flow_aggregator_namespace_bytes_total{direction="ingress",namespace="ns2"} 1700
+# HELP antrea_flow_aggregator_namespace_connections_total [ALPHA] Number of connections of the aggregated flows, partitioned by Namespace of the source (egress) or destination (ingress) Pod.
+# TYPE antrea_flow_aggregator_namespace_connections_total counter
+antrea_flow_aggregator_namespace_connections_total{direction="egress",namespace="_other"} 1
+antrea_flow_aggregator_namespace_connections_total{direction="egress",namespace="ns1"} 1
+antrea_flow_aggregator_namespace_connections_total{direction="ingress",namespace="ns2"} 1
+# HELP antrea_flow_aggregator_service_packets_total [ALPHA] Number of packets of the aggregated flows, partitioned by destination Service port.
+# TYPE antrea_flow_aggregator_service_packets_total counter
+antrea_flow_aggregator_service_packets_total{service="ns2/svc:http"} 17
+# HELP antrea_flow_aggregator_networkpolicy_rule_connections_total [ALPHA] Number of connections of the aggregated flows, partitioned by ingress or egress NetworkPolicy rule.
+# TYPE antrea_flow_aggregator_networkpolicy_rule_connections_total counter
+antrea_flow_aggregator_networkpolicy_rule_connections_total{action="Allow",direction="ingress",policy_name="np1",policy_namespace="ns2",rule_name="rule1"} 1
+antrea_flow_aggregator_networkpolicy_rule_connections_total{action="_other",direction="egress",policy_name="_other",policy_namespace="_other",rule_name="_other"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"antrea_flow_aggregator_namespace_bytes_total",
+		"antrea_flow_aggregator_namespace_connections_total",
+		"antrea_flow_aggregator_service_packets_total",
+		"antrea_flow_aggregator_networkpolicy_rule_connections_total"))
+
+	// The top talkers are only updated once per interval.
+	require.NoError(t, sink.Flush())
+	assert.NoError(t, testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(""), "antrea_flow_aggregator_top_talker_bytes"))
+	now = now.Add(time.Minute)
+	require.NoError(t, sink.Flush())
+	expected = `
+# HELP antrea_flow_aggregator_top_talker_bytes [ALPHA] Number of bytes sent and received by the Pods with the most traffic during the last top talker interval.
+# TYPE antrea_flow_aggregator_top_talker_bytes gauge
+antrea_flow_aggregator_top_talker_bytes{namespace="ns1",pod="pod1"} 1700
+antrea_flow_aggregator_top_talker_bytes{namespace="ns2",pod="pod2"} 1700
+`
+	assert.NoError(t, testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "antrea_flow_aggregator_top_talker_bytes"))
+
+	// The top talkers are reset when there is no traffic during the interval. The metrics of the Namespaces,
+	// Service ports and NetworkPolicy rules without traffic during the interval are removed.
+	now = now.Add(time.Minute)
+	require.NoError(t, sink.Flush())
+	assert.NoError(t, testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(""), "antrea_flow_aggregator_top_talker_bytes"))
+	expected = `
+# HELP antrea_flow_aggregator_namespace_bytes_total [ALPHA] Number of bytes of the aggregated flows, partitioned by Namespace of the source (egress) or destination (ingress) Pod.
+# TYPE antrea_flow_aggregator_namespace_bytes_total counter
+antrea_flow_aggregator_namespace_bytes_total{direction="egress",namespace="_other"} 100
+# HELP antrea_flow_aggregator_networkpolicy_rule_connections_total [ALPHA] Number of connections of the aggregated flows, partitioned by ingress or egress NetworkPolicy rule.
+# TYPE antrea_flow_aggregator_networkpolicy_rule_connections_total counter
+antrea_flow_aggregator_networkpolicy_rule_connections_total{action="_other",direction="egress",policy_name="_other",policy_namespace="_other",rule_name="_other"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected),
+		"antrea_flow_aggregator_namespace_bytes_total",
+		"antrea_flow_aggregator_service_packets_total",
+		"antrea_flow_aggregator_networkpolicy_rule_connections_total"))
+
+	// The removed Namespaces no longer count against the limit.
+	require.NoError(t, sink.AddFlow(record3))
+	expected = `
+# HELP antrea_flow_aggregator_namespace_bytes_total [ALPHA] Number of bytes of the aggregated flows, partitioned by Namespace of the source (egress) or destination (ingress) Pod.
+# TYPE antrea_flow_aggregator_namespace_bytes_total counter
+antrea_flow_aggregator_namespace_bytes_total{direction="egress",namespace="_other"} 100
+antrea_flow_aggregator_namespace_bytes_total{direction="egress",namespace="ns3"} 100
+`
+	assert.NoError(t, testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "antrea_flow_aggregator_namespace_bytes_total"))
+}
+
+func TestLabelLimiter(t *testing.T) {
+	limiter := newLabelLimiter(2)
+	assert.True(t, limiter.allow("ns1"))
+	assert.True(t, limiter.allow("ns2"))
+	assert.False(t, limiter.allow("ns3"))
+	assert.Empty(t, limiter.expire())
+
+	// ns1 is used again during the interval, ns2 is not and expires.
+	assert.True(t, limiter.allow("ns1"))
+	assert.Equal(t, [][]string{{"ns2"}}, limiter.expire())
+	assert.True(t, limiter.allow("ns3"))
+	assert.False(t, limiter.allow("ns2"))
+}
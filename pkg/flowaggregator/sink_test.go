@@ -0,0 +1,364 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowaggregator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	saramamocks "github.com/Shopify/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ipfixentities "github.com/vmware/go-ipfix/pkg/entities"
+	ipfixintermediate "github.com/vmware/go-ipfix/pkg/intermediate"
+	"github.com/vmware/go-ipfix/pkg/producer/protobuf"
+	ipfixregistry "github.com/vmware/go-ipfix/pkg/registry"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	testFlowStartTime = time.Unix(1630000000, 0).UTC()
+	testFlowEndTime   = time.Unix(1630000060, 0).UTC()
+)
+
+func newTestFlowRecord() *FlowRecord {
+	return &FlowRecord{
+		FlowStartSeconds:         testFlowStartTime,
+		FlowEndSeconds:           testFlowEndTime,
+		FlowEndReason:            1,
+		SourceIP:                 "10.0.0.1",
+		DestinationIP:            "10.0.0.2",
+		SourceTransportPort:      1234,
+		DestinationTransportPort: 80,
+		ProtocolIdentifier:       6,
+		PacketTotalCount:         10,
+		OctetTotalCount:          1000,
+		SamplingInterval:         1,
+		SourcePodName:            "pod1",
+		SourcePodNamespace:       "ns1",
+		DestinationClusterIP:     "10.96.0.1",
+		DestinationServicePort:   80,
+		TCPState:                 "ESTABLISHED",
+	}
+}
+
+// nopCloser is a bytes.Buffer implementing io.WriteCloser.
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (c *nopCloser) Close() error {
+	return nil
+}
+
+func TestNewFlowRecord(t *testing.T) {
+	ipfixregistry.LoadRegistry()
+	values := []struct {
+		name       string
+		enterprise uint32
+		value      interface{}
+	}{
+		{"flowStartSeconds", ipfixregistry.IANAEnterpriseID, uint32(testFlowStartTime.Unix())},
+		{"flowEndSeconds", ipfixregistry.IANAEnterpriseID, uint32(testFlowEndTime.Unix())},
+		{"flowEndReason", ipfixregistry.IANAEnterpriseID, uint8(1)},
+		{"packetTotalCount", ipfixregistry.IANAEnterpriseID, uint64(10)},
+		{"octetTotalCount", ipfixregistry.IANAEnterpriseID, uint64(1000)},
+		{"samplingInterval", ipfixregistry.IANAEnterpriseID, uint32(1)},
+		{"sourcePodName", ipfixregistry.AntreaEnterpriseID, "pod1"},
+		{"sourcePodNamespace", ipfixregistry.AntreaEnterpriseID, "ns1"},
+		{"destinationClusterIPv4", ipfixregistry.AntreaEnterpriseID, net.ParseIP("10.96.0.1").To4()},
+		{"destinationServicePort", ipfixregistry.AntreaEnterpriseID, uint16(80)},
+		{"tcpState", ipfixregistry.AntreaEnterpriseID, "ESTABLISHED"},
+	}
+	record := ipfixentities.NewDataRecord(testTemplateIDv4, len(values), false)
+	for _, v := range values {
+		element, err := ipfixregistry.GetInfoElement(v.name, v.enterprise)
+		require.NoError(t, err)
+		require.NoError(t, record.AddInfoElement(ipfixentities.NewInfoElementWithValue(element, v.value)))
+	}
+	key := ipfixintermediate.FlowKey{
+		SourceAddress:      "10.0.0.1",
+		DestinationAddress: "10.0.0.2",
+		Protocol:           6,
+		SourcePort:         1234,
+		DestinationPort:    80,
+	}
+	assert.Equal(t, newTestFlowRecord(), newFlowRecord(key, record))
+}
+
+// fakeFlowSink reports the calls to its methods on events.
+type fakeFlowSink struct {
+	events chan string
+}
+
+func (s *fakeFlowSink) Name() string {
+	return "fake"
+}
+
+func (s *fakeFlowSink) AddFlow(record *FlowRecord) error {
+	s.events <- "add " + record.SourcePodName
+	return nil
+}
+
+func (s *fakeFlowSink) Flush() error {
+	s.events <- "flush"
+	return nil
+}
+
+func (s *fakeFlowSink) Close() {
+	s.events <- "close"
+}
+
+func TestAsyncFlowSink(t *testing.T) {
+	fakeSink := &fakeFlowSink{events: make(chan string, 10)}
+	sink := newAsyncFlowSink(fakeSink, 2)
+	newRecord := func(podName string) *FlowRecord {
+		return &FlowRecord{SourcePodName: podName}
+	}
+	expectEvents := func(events ...string) {
+		for _, event := range events {
+			select {
+			case e := <-fakeSink.events:
+				assert.Equal(t, event, e)
+			case <-time.After(time.Second):
+				t.Fatalf("Timed out when waiting for event %s", event)
+			}
+		}
+	}
+
+	// The flow records and flush requests are dropped without blocking when the queue is full.
+	sink.addFlow(newRecord("pod1"))
+	sink.addFlow(newRecord("pod2"))
+	sink.addFlow(newRecord("pod3"))
+	sink.flush()
+	assert.Equal(t, uint64(1), atomic.LoadUint64(&sink.droppedRecords))
+
+	go sink.run()
+	expectEvents("add pod1", "add pod2")
+	// The sink is flushed after the flow records queued before the flush request are exported.
+	sink.addFlow(newRecord("pod4"))
+	sink.flush()
+	expectEvents("add pod4", "flush")
+	sink.close()
+	expectEvents("close")
+	assert.Equal(t, uint64(0), atomic.LoadUint64(&sink.droppedRecords))
+}
+
+func TestFileSink(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		output := &nopCloser{}
+		sink, err := newFileSink(output, FlowRecordFormatJSON)
+		require.NoError(t, err)
+		require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+		require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+		require.NoError(t, sink.Flush())
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		require.Len(t, lines, 2)
+		var record FlowRecord
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+		assert.Equal(t, *newTestFlowRecord(), record)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		output := &nopCloser{}
+		sink, err := newFileSink(output, FlowRecordFormatCSV)
+		require.NoError(t, err)
+		require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+		sink.Close()
+
+		row := strings.Split(strings.TrimSpace(output.String()), ",")
+		header := flowRecordCSVHeader()
+		require.Len(t, row, len(header))
+		values := map[string]string{}
+		for i := range header {
+			values[header[i]] = row[i]
+		}
+		assert.Equal(t, "2021-08-26T17:46:40Z", values["flowStartSeconds"])
+		assert.Equal(t, "10.0.0.1", values["sourceIP"])
+		assert.Equal(t, "1000", values["octetTotalCount"])
+		assert.Equal(t, "pod1", values["sourcePodName"])
+		assert.Equal(t, "", values["destinationPodName"])
+	})
+
+	_, err := newFileSink(&nopCloser{}, FlowRecordFormatProtobuf)
+	assert.Error(t, err)
+}
+
+func TestKafkaSink(t *testing.T) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	producer := saramamocks.NewAsyncProducer(t, config)
+	newProducer := func() (sarama.AsyncProducer, error) {
+		return producer, nil
+	}
+
+	t.Run("json", func(t *testing.T) {
+		sink, err := newKafkaSink("flows", FlowRecordFormatJSON, newProducer)
+		require.NoError(t, err)
+		producer.ExpectInputWithCheckerFunctionAndSucceed(func(value []byte) error {
+			var record FlowRecord
+			require.NoError(t, json.Unmarshal(value, &record))
+			assert.Equal(t, *newTestFlowRecord(), record)
+			return nil
+		})
+		require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+		msg := <-producer.Successes()
+		assert.Equal(t, "flows", msg.Topic)
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		sink, err := newKafkaSink("flows", FlowRecordFormatProtobuf, newProducer)
+		require.NoError(t, err)
+		producer.ExpectInputWithCheckerFunctionAndSucceed(func(value []byte) error {
+			flowMsg := &protobuf.FlowType2{}
+			require.NoError(t, proto.Unmarshal(value, flowMsg))
+			assert.Equal(t, "10.0.0.1", flowMsg.SrcIP)
+			assert.Equal(t, uint32(80), flowMsg.DstPort)
+			assert.Equal(t, uint64(1000), flowMsg.BytesTotal)
+			assert.Equal(t, uint32(testFlowStartTime.Unix()), flowMsg.TimeFlowStartInSecs)
+			assert.Equal(t, "ESTABLISHED", flowMsg.TcpState)
+			return nil
+		})
+		require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+		<-producer.Successes()
+		sink.Close()
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		producer := &fakeAsyncProducer{
+			input:  make(chan *sarama.ProducerMessage, 1),
+			errors: make(chan *sarama.ProducerError),
+		}
+		attempts := 0
+		sink, err := newKafkaSink("flows", FlowRecordFormatJSON, func() (sarama.AsyncProducer, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, fmt.Errorf("brokers not reachable")
+			}
+			return producer, nil
+		})
+		require.NoError(t, err)
+		// The producer is not created again before kafkaProducerRetryInterval.
+		assert.Error(t, sink.AddFlow(newTestFlowRecord()))
+		assert.Error(t, sink.AddFlow(newTestFlowRecord()))
+		assert.Equal(t, 1, attempts)
+		sink.nextProducerRetry = time.Time{}
+		// The flow records are dropped instead of blocking when the buffer of the producer is full.
+		require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+		assert.Error(t, sink.AddFlow(newTestFlowRecord()))
+		assert.Equal(t, 2, attempts)
+		sink.Close()
+	})
+
+	_, err := newKafkaSink("flows", FlowRecordFormatCSV, newProducer)
+	assert.Error(t, err)
+}
+
+// fakeAsyncProducer is a sarama.AsyncProducer which never publishes the messages of its input.
+type fakeAsyncProducer struct {
+	input  chan *sarama.ProducerMessage
+	errors chan *sarama.ProducerError
+}
+
+func (p *fakeAsyncProducer) AsyncClose() {
+	close(p.errors)
+}
+
+func (p *fakeAsyncProducer) Close() error {
+	close(p.errors)
+	return nil
+}
+
+func (p *fakeAsyncProducer) Input() chan<- *sarama.ProducerMessage {
+	return p.input
+}
+
+func (p *fakeAsyncProducer) Successes() <-chan *sarama.ProducerMessage {
+	return nil
+}
+
+func (p *fakeAsyncProducer) Errors() <-chan *sarama.ProducerError {
+	return p.errors
+}
+
+func TestClickHouseSink(t *testing.T) {
+	var queries []string
+	var batches [][]FlowRecord
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "user", username)
+		assert.Equal(t, "password", password)
+		queries = append(queries, r.URL.Query().Get("query"))
+		body, _ := ioutil.ReadAll(r.Body)
+		var batch []FlowRecord
+		for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+			var record FlowRecord
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			batch = append(batch, record)
+		}
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	sink, err := NewClickHouseSink(ClickHouseSinkConfig{
+		Address:   server.URL,
+		Database:  "default",
+		Table:     "flows",
+		Username:  "user",
+		Password:  "password",
+		BatchSize: 2,
+	})
+	require.NoError(t, err)
+
+	// A full batch is inserted immediately.
+	require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+	assert.Len(t, batches, 0)
+	require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0], 2)
+	assert.Equal(t, "INSERT INTO default.flows FORMAT JSONEachRow", queries[0])
+
+	// The records are kept when the server is unavailable.
+	fail = true
+	require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+	assert.Error(t, sink.Flush())
+	fail = false
+	// A full batch is not inserted immediately after a failed insertion, but at the end of the export cycle.
+	require.NoError(t, sink.AddFlow(newTestFlowRecord()))
+	assert.Len(t, batches, 1)
+	require.NoError(t, sink.Flush())
+	require.Len(t, batches, 2)
+	assert.Len(t, batches[1], 2)
+	assert.Equal(t, *newTestFlowRecord(), batches[1][0])
+
+	_, err = NewClickHouseSink(ClickHouseSinkConfig{Address: "clickhouse:8123", Table: "flows", BatchSize: 1})
+	assert.Error(t, err)
+}
@@ -214,6 +214,41 @@ func (a *ofFlowAction) SetTunnelDst(addr net.IP) FlowBuilder {
 	return a.builder
 }
 
+// pushVLANAction is used to push a new VLAN header onto the packet. ofctrl only supports
+// pushing a VLAN header together with setting the VLAN ID in Flow.SetVlan, which cannot be
+// combined with the other actions in the desired order.
+type pushVLANAction struct {
+	etherType uint16
+}
+
+func (a *pushVLANAction) GetActionMessage() openflow13.Action {
+	return openflow13.NewActionPushVlan(a.etherType)
+}
+
+func (a *pushVLANAction) GetActionType() string {
+	return "pushVlan"
+}
+
+// PushVLAN is an action to push a new VLAN header with the specified ethertype onto the packet.
+func (a *ofFlowAction) PushVLAN(etherType uint16) FlowBuilder {
+	a.builder.ApplyAction(&pushVLANAction{etherType: etherType})
+	return a.builder
+}
+
+// SetVLAN is an action to modify the VLAN ID of the packet's outermost VLAN header.
+func (a *ofFlowAction) SetVLAN(vlanID uint16) FlowBuilder {
+	setVLANAct := &ofctrl.SetVLANAction{VlanID: vlanID}
+	a.builder.ApplyAction(setVLANAct)
+	return a.builder
+}
+
+// PopVLAN is an action to remove the packet's outermost VLAN header.
+func (a *ofFlowAction) PopVLAN() FlowBuilder {
+	popVLANAct := &ofctrl.PopVLANAction{}
+	a.builder.ApplyAction(popVLANAct)
+	return a.builder
+}
+
 // LoadARPOperation is an action to Load data to NXM_OF_ARP_OP field.
 func (a *ofFlowAction) LoadARPOperation(value uint16) FlowBuilder {
 	loadAct, _ := ofctrl.NewNXLoadAction(NxmFieldARPOp, uint64(value), openflow13.NewNXRange(0, 15))
@@ -149,7 +149,15 @@ type OFBridge struct {
 }
 
 func (b *OFBridge) CreateGroup(id GroupIDType) Group {
-	ofctrlGroup, err := b.ofSwitch.NewGroup(uint32(id), ofctrl.GroupSelect)
+	return b.createGroupWithType(id, ofctrl.GroupSelect)
+}
+
+func (b *OFBridge) CreateGroupTypeAll(id GroupIDType) Group {
+	return b.createGroupWithType(id, ofctrl.GroupAll)
+}
+
+func (b *OFBridge) createGroupWithType(id GroupIDType, groupType ofctrl.GroupType) Group {
+	ofctrlGroup, err := b.ofSwitch.NewGroup(uint32(id), groupType)
 	if err != nil { // group already exists
 		ofctrlGroup = b.ofSwitch.GetGroup(uint32(id))
 	}
@@ -388,11 +396,7 @@ func (b *OFBridge) AddFlowsInBundle(addflows []Flow, modFlows []Flow, delFlows [
 			// the BundleAdd message. An absence of error does not mean that all Openflow entries are added into the
 			// bundle by the switch. The number of entries successfully added to the bundle by the switch will be
 			// returned by function "Complete".
-			flowMod, err := ofFlow.Flow.GetBundleMessage(operation)
-			if err != nil {
-				return err
-			}
-			if err := tx.AddMessage(flowMod); err != nil {
+			if err := ofFlow.addToBundle(tx, operation); err != nil {
 				// Close the bundle and cancel it if there is error when adding the FlowMod message.
 				_, err := tx.Complete()
 				if err == nil {
@@ -493,15 +497,20 @@ func (b *OFBridge) AddOFEntriesInBundle(addEntries []OFEntry, modEntries []OFEnt
 			return nil
 		}
 		for _, e := range entrySet {
-			msg, err := e.entry.GetBundleMessage(e.operation)
-			if err != nil {
-				return err
-			}
 			// "AddMessage" operation is async, the function only returns error which occur when constructing and sending
 			// the BundleAdd message. An absence of error does not mean that all OpenFlow entries are added into the
 			// bundle by the switch. The number of entries successfully added to the bundle by the switch will be
 			// returned by function "Complete".
-			if err := tx.AddMessage(msg); err != nil {
+			var err error
+			if flow, ok := e.entry.(*ofFlow); ok {
+				err = flow.addToBundle(tx, getFlowModOperation(e.operation))
+			} else {
+				var msg ofctrl.OpenFlowModMessage
+				if msg, err = e.entry.GetBundleMessage(e.operation); err == nil {
+					err = tx.AddMessage(msg)
+				}
+			}
+			if err != nil {
 				// Close the bundle and cancel it if there is error when adding the FlowMod message.
 				_, err := tx.Complete()
 				if err == nil {
@@ -327,6 +327,20 @@ func (b *ofFlowBuilder) MatchDstIPNet(ipnet net.IPNet) FlowBuilder {
 	return b
 }
 
+// MatchICMPType adds match condition for matching the type of ICMPv4 packets.
+func (b *ofFlowBuilder) MatchICMPType(icmpType byte) FlowBuilder {
+	b.matchers = append(b.matchers, fmt.Sprintf("icmp_type=%d", icmpType))
+	b.icmpType = &icmpType
+	return b
+}
+
+// MatchICMPCode adds match condition for matching the code of ICMPv4 packets.
+func (b *ofFlowBuilder) MatchICMPCode(icmpCode byte) FlowBuilder {
+	b.matchers = append(b.matchers, fmt.Sprintf("icmp_code=%d", icmpCode))
+	b.icmpCode = &icmpCode
+	return b
+}
+
 func (b *ofFlowBuilder) MatchICMPv6Type(icmp6Type byte) FlowBuilder {
 	b.matchers = append(b.matchers, fmt.Sprintf("icmp_type=%d", icmp6Type))
 	b.Match.Icmp6Type = &icmp6Type
@@ -33,6 +33,15 @@ type ofFlow struct {
 	ctStates *openflow13.CTStates
 	// isDropFlow is true if this flow actions contain "drop"
 	isDropFlow bool
+	// icmpType and icmpCode are the ICMPv4 type and code to match. They are not supported by ofctrl.FlowMatch, so the
+	// FlowMod messages of a Flow matching them are generated by getFlowMod instead of the ofctrl library.
+	icmpType *uint8
+	icmpCode *uint8
+	// appliedActions, gotoTable and meter keep the instructions of the Flow, which are not exposed by ofctrl.Flow, for
+	// getFlowMod.
+	appliedActions []ofctrl.OFAction
+	gotoTable      *uint8
+	meter          *uint32
 }
 
 // Reset updates the ofFlow.Flow.Table field with ofFlow.table.Table.
@@ -44,7 +53,7 @@ func (f *ofFlow) Reset() {
 }
 
 func (f *ofFlow) Add() error {
-	err := f.Flow.Send(openflow13.FC_ADD)
+	err := f.send(openflow13.FC_ADD)
 	if err != nil {
 		return err
 	}
@@ -53,7 +62,7 @@ func (f *ofFlow) Add() error {
 }
 
 func (f *ofFlow) Modify() error {
-	err := f.Flow.Send(openflow13.FC_MODIFY_STRICT)
+	err := f.send(openflow13.FC_MODIFY_STRICT)
 	if err != nil {
 		return err
 	}
@@ -63,7 +72,7 @@ func (f *ofFlow) Modify() error {
 
 func (f *ofFlow) Delete() error {
 	f.Flow.UpdateInstallStatus(true)
-	err := f.Flow.Send(openflow13.FC_DELETE_STRICT)
+	err := f.send(openflow13.FC_DELETE_STRICT)
 	if err != nil {
 		return err
 	}
@@ -100,6 +109,17 @@ func (f *ofFlow) FlowProtocol() Protocol {
 }
 
 func (f *ofFlow) GetBundleMessage(entryOper OFOperation) (ofctrl.OpenFlowModMessage, error) {
+	if f.matchICMP() {
+		return nil, fmt.Errorf("the bundle message of Flow %s is not generated by the ofctrl library", f.MatchString())
+	}
+	message, err := f.Flow.GetBundleMessage(getFlowModOperation(entryOper))
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func getFlowModOperation(entryOper OFOperation) int {
 	var operation int
 	switch entryOper {
 	case AddMessage:
@@ -109,11 +129,115 @@ func (f *ofFlow) GetBundleMessage(entryOper OFOperation) (ofctrl.OpenFlowModMess
 	case DeleteMessage:
 		operation = openflow13.FC_DELETE_STRICT
 	}
-	message, err := f.Flow.GetBundleMessage(operation)
+	return operation
+}
+
+func (f *ofFlow) matchICMP() bool {
+	return f.icmpType != nil || f.icmpCode != nil
+}
+
+// send generates a FlowMod message according to the operation, and sends it to the OFSwitch.
+func (f *ofFlow) send(operation int) error {
+	if !f.matchICMP() {
+		return f.Flow.Send(operation)
+	}
+	flowMod, err := f.getFlowMod(operation)
+	if err != nil {
+		return err
+	}
+	return f.Flow.Table.Switch.Send(flowMod)
+}
+
+// addToBundle generates a FlowMod message according to the operation, and adds it in the bundle of the transaction.
+func (f *ofFlow) addToBundle(tx *ofctrl.Transaction, operation int) error {
+	if !f.matchICMP() {
+		message, err := f.Flow.GetBundleMessage(operation)
+		if err != nil {
+			return err
+		}
+		return tx.AddMessage(message)
+	}
+	flowMod, err := f.getFlowMod(operation)
+	if err != nil {
+		return err
+	}
+	return tx.AddFlow(flowMod)
+}
+
+// getFlowMod generates the FlowMod message of a Flow matching the ICMPv4 type or code. The ofctrl library translates
+// the match of the Flow, and the ICMPv4 match fields and the instructions kept by the ofFlow are added to it.
+func (f *ofFlow) getFlowMod(operation int) (*openflow13.FlowMod, error) {
+	// GenerateFlowModMessage requires a next element, and the empty one doesn't add any instruction.
+	flow := &ofctrl.Flow{
+		Table:       f.Flow.Table,
+		Match:       f.Flow.Match,
+		CookieID:    f.Flow.CookieID,
+		CookieMask:  f.Flow.CookieMask,
+		HardTimeout: f.Flow.HardTimeout,
+		IdleTimeout: f.Flow.IdleTimeout,
+		NextElem:    ofctrl.NewEmptyElem(),
+	}
+	flowMod, err := flow.GenerateFlowModMessage(operation)
 	if err != nil {
 		return nil, err
 	}
-	return message, nil
+	// Keep the cookie allocated by the ofctrl library if the Flow has none.
+	f.Flow.CookieID = flow.CookieID
+	if f.icmpType != nil {
+		field, _ := openflow13.FindFieldHeaderByName("NXM_OF_ICMP_TYPE", false)
+		field.Value = &openflow13.IcmpTypeField{Type: *f.icmpType}
+		flowMod.Match.AddField(*field)
+	}
+	if f.icmpCode != nil {
+		field, _ := openflow13.FindFieldHeaderByName("NXM_OF_ICMP_CODE", false)
+		field.Value = &openflow13.IcmpCodeField{Code: *f.icmpCode}
+		flowMod.Match.AddField(*field)
+	}
+	if operation == openflow13.FC_DELETE || operation == openflow13.FC_DELETE_STRICT {
+		return flowMod, nil
+	}
+	if len(f.appliedActions) > 0 {
+		instruction := openflow13.NewInstrApplyActions()
+		for _, action := range f.appliedActions {
+			if err := instruction.AddAction(action.GetActionMessage(), false); err != nil {
+				return nil, err
+			}
+		}
+		flowMod.AddInstruction(instruction)
+	}
+	if f.gotoTable != nil {
+		flowMod.AddInstruction(openflow13.NewInstrGotoTable(*f.gotoTable))
+	}
+	if f.meter != nil {
+		flowMod.AddInstruction(openflow13.NewInstrMeter(*f.meter))
+	}
+	return flowMod, nil
+}
+
+// ApplyAction adds an action to the apply-actions instruction of the Flow.
+func (f *ofFlow) ApplyAction(action ofctrl.OFAction) {
+	f.appliedActions = append(f.appliedActions, action)
+	f.Flow.ApplyAction(action)
+}
+
+// Goto sets the goto-table instruction of the Flow.
+func (f *ofFlow) Goto(tableID uint8) {
+	f.gotoTable = &tableID
+	f.Flow.Goto(tableID)
+}
+
+// Meter sets the meter instruction of the Flow.
+func (f *ofFlow) Meter(meterID uint32) {
+	f.meter = &meterID
+	f.Flow.Meter(meterID)
+}
+
+// Drop removes all the instructions of the Flow.
+func (f *ofFlow) Drop() {
+	f.appliedActions = nil
+	f.gotoTable = nil
+	f.meter = nil
+	f.Flow.Drop()
 }
 
 // CopyToBuilder returns a new FlowBuilder that copies the table, protocols,
@@ -138,10 +262,15 @@ func (f *ofFlow) CopyToBuilder(priority uint16, copyActions bool) FlowBuilder {
 		table:    f.table,
 		Flow:     flow,
 		matchers: f.matchers,
+		icmpType: f.icmpType,
+		icmpCode: f.icmpCode,
 		protocol: f.protocol,
 	}
 	if copyActions {
 		newFlow.isDropFlow = f.isDropFlow
+		newFlow.appliedActions = f.appliedActions
+		newFlow.gotoTable = f.gotoTable
+		newFlow.meter = f.meter
 	}
 	return &ofFlowBuilder{newFlow}
 }
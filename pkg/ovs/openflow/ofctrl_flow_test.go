@@ -3,7 +3,10 @@ package openflow
 import (
 	"testing"
 
+	"github.com/contiv/libOpenflow/openflow13"
+	"github.com/contiv/ofnet/ofctrl"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCopyToBuilder(t *testing.T) {
@@ -50,3 +53,50 @@ func TestCopyToBuilder_Drop(t *testing.T) {
 	assert.Equal(t, newPriority, newFlow2.Done().(*ofFlow).Match.Priority)
 	assert.Equal(t, true, newFlow2.Done().IsDropFlow())
 }
+
+func TestICMPMatch(t *testing.T) {
+	table := &ofTable{
+		id:    0,
+		next:  1,
+		Table: &ofctrl.Table{TableId: 0},
+	}
+	flow := table.BuildFlow(uint16(100)).MatchProtocol(ProtocolICMP).
+		MatchICMPType(8).MatchICMPCode(0).
+		Action().GotoTable(1).
+		Done()
+	assert.Equal(t, "table=0,icmp,icmp_type=8,icmp_code=0", flow.MatchString())
+	assert.Equal(t, flow.MatchString(), flow.CopyToBuilder(0, false).Done().MatchString())
+
+	flowMod, err := flow.(*ofFlow).getFlowMod(openflow13.FC_ADD)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(100), flowMod.Priority)
+	require.Len(t, flowMod.Instructions, 1)
+	assert.Equal(t, uint8(1), flowMod.Instructions[0].(*openflow13.InstrGotoTable).TableId)
+	var icmpType, icmpCode *openflow13.MatchField
+	for i, field := range flowMod.Match.Fields {
+		if field.Class != openflow13.OXM_CLASS_NXM_0 {
+			continue
+		}
+		switch field.Field {
+		case openflow13.NXM_OF_ICMP_TYPE:
+			icmpType = &flowMod.Match.Fields[i]
+		case openflow13.NXM_OF_ICMP_CODE:
+			icmpCode = &flowMod.Match.Fields[i]
+		}
+	}
+	require.NotNil(t, icmpType)
+	require.NotNil(t, icmpCode)
+	assert.Equal(t, uint8(8), icmpType.Value.(*openflow13.IcmpTypeField).Type)
+	assert.Equal(t, uint8(0), icmpCode.Value.(*openflow13.IcmpCodeField).Code)
+
+	copiedFlowMod, err := flow.CopyToBuilder(0, true).Done().(*ofFlow).getFlowMod(openflow13.FC_ADD)
+	require.NoError(t, err)
+	assert.Equal(t, flowMod.Cookie, copiedFlowMod.Cookie)
+	assert.Equal(t, flowMod.Match, copiedFlowMod.Match)
+	assert.Equal(t, flowMod.Instructions, copiedFlowMod.Instructions)
+
+	flowMod, err = flow.(*ofFlow).getFlowMod(openflow13.FC_DELETE_STRICT)
+	require.NoError(t, err)
+	assert.Equal(t, copiedFlowMod.Match, flowMod.Match)
+	assert.Empty(t, flowMod.Instructions)
+}
@@ -27,8 +27,17 @@ type ofMeter struct {
 	bridge *OFBridge
 }
 
+// Reset creates a new ofctrl.Meter object for the updated ofSwitch, so that
+// the meter is added instead of modified when it is replayed to OVS. If the
+// meter already exists in the ofSwitch, the existing object is kept.
 func (m *ofMeter) Reset() {
-	m.ofctrl.Switch = m.bridge.ofSwitch
+	newMeter, err := m.bridge.ofSwitch.NewMeter(m.ofctrl.ID, m.ofctrl.Flags)
+	if err != nil {
+		m.ofctrl.Switch = m.bridge.ofSwitch
+		return
+	}
+	newMeter.MeterBands = m.ofctrl.MeterBands
+	m.ofctrl = newMeter
 }
 
 func (m *ofMeter) Add() error {
@@ -210,6 +210,15 @@ func (b *ofPacketOutBuilder) SetUDPDstPort(port uint16) PacketOutBuilder {
 	return b
 }
 
+// SetUDPData sets the data in the packet's UDP header.
+func (b *ofPacketOutBuilder) SetUDPData(data []byte) PacketOutBuilder {
+	if b.pktOut.UDPHeader == nil {
+		b.pktOut.UDPHeader = new(protocol.UDP)
+	}
+	b.pktOut.UDPHeader.Data = data
+	return b
+}
+
 // SetICMPType sets the type in the packet's ICMP header.
 func (b *ofPacketOutBuilder) SetICMPType(icmpType uint8) PacketOutBuilder {
 	if b.pktOut.ICMPHeader == nil {
@@ -14,7 +14,7 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: antrea.io/antrea/pkg/ovs/openflow (interfaces: Bridge,Table,Flow,Action,CTAction,FlowBuilder)
+// Source: antrea.io/antrea/pkg/ovs/openflow (interfaces: Bridge,Table,Flow,Action,CTAction,FlowBuilder,Meter,MeterBandBuilder)
 
 // Package testing is a generated GoMock package.
 package testing
@@ -134,6 +134,20 @@ func (mr *MockBridgeMockRecorder) CreateGroup(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockBridge)(nil).CreateGroup), arg0)
 }
 
+// CreateGroupTypeAll mocks base method
+func (m *MockBridge) CreateGroupTypeAll(arg0 openflow.GroupIDType) openflow.Group {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupTypeAll", arg0)
+	ret0, _ := ret[0].(openflow.Group)
+	return ret0
+}
+
+// CreateGroupTypeAll indicates an expected call of CreateGroupTypeAll
+func (mr *MockBridgeMockRecorder) CreateGroupTypeAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupTypeAll", reflect.TypeOf((*MockBridge)(nil).CreateGroupTypeAll), arg0)
+}
+
 // CreateMeter mocks base method
 func (m *MockBridge) CreateMeter(arg0 openflow.MeterIDType, arg1 ofctrl.MeterFlag) openflow.Meter {
 	m.ctrl.T.Helper()
@@ -917,6 +931,34 @@ func (mr *MockActionMockRecorder) OutputRegRange(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutputRegRange", reflect.TypeOf((*MockAction)(nil).OutputRegRange), arg0, arg1)
 }
 
+// PopVLAN mocks base method
+func (m *MockAction) PopVLAN() openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PopVLAN")
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// PopVLAN indicates an expected call of PopVLAN
+func (mr *MockActionMockRecorder) PopVLAN() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PopVLAN", reflect.TypeOf((*MockAction)(nil).PopVLAN))
+}
+
+// PushVLAN mocks base method
+func (m *MockAction) PushVLAN(arg0 uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushVLAN", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// PushVLAN indicates an expected call of PushVLAN
+func (mr *MockActionMockRecorder) PushVLAN(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushVLAN", reflect.TypeOf((*MockAction)(nil).PushVLAN), arg0)
+}
+
 // Resubmit mocks base method
 func (m *MockAction) Resubmit(arg0 uint16, arg1 openflow.TableIDType) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1085,6 +1127,20 @@ func (mr *MockActionMockRecorder) SetTunnelDst(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTunnelDst", reflect.TypeOf((*MockAction)(nil).SetTunnelDst), arg0)
 }
 
+// SetVLAN mocks base method
+func (m *MockAction) SetVLAN(arg0 uint16) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVLAN", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// SetVLAN indicates an expected call of SetVLAN
+func (mr *MockActionMockRecorder) SetVLAN(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVLAN", reflect.TypeOf((*MockAction)(nil).SetVLAN), arg0)
+}
+
 // MockCTAction is a mock of CTAction interface
 type MockCTAction struct {
 	ctrl     *gomock.Controller
@@ -1649,6 +1705,34 @@ func (mr *MockFlowBuilderMockRecorder) MatchDstPort(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchDstPort", reflect.TypeOf((*MockFlowBuilder)(nil).MatchDstPort), arg0, arg1)
 }
 
+// MatchICMPCode mocks base method
+func (m *MockFlowBuilder) MatchICMPCode(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchICMPCode", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchICMPCode indicates an expected call of MatchICMPCode
+func (mr *MockFlowBuilderMockRecorder) MatchICMPCode(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchICMPCode", reflect.TypeOf((*MockFlowBuilder)(nil).MatchICMPCode), arg0)
+}
+
+// MatchICMPType mocks base method
+func (m *MockFlowBuilder) MatchICMPType(arg0 byte) openflow.FlowBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchICMPType", arg0)
+	ret0, _ := ret[0].(openflow.FlowBuilder)
+	return ret0
+}
+
+// MatchICMPType indicates an expected call of MatchICMPType
+func (mr *MockFlowBuilderMockRecorder) MatchICMPType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchICMPType", reflect.TypeOf((*MockFlowBuilder)(nil).MatchICMPType), arg0)
+}
+
 // MatchICMPv6Code mocks base method
 func (m *MockFlowBuilder) MatchICMPv6Code(arg0 byte) openflow.FlowBuilder {
 	m.ctrl.T.Helper()
@@ -1914,3 +1998,258 @@ func (mr *MockFlowBuilderMockRecorder) SetIdleTimeout(arg0 interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIdleTimeout", reflect.TypeOf((*MockFlowBuilder)(nil).SetIdleTimeout), arg0)
 }
+
+// MockMeter is a mock of Meter interface
+type MockMeter struct {
+	ctrl     *gomock.Controller
+	recorder *MockMeterMockRecorder
+}
+
+// MockMeterMockRecorder is the mock recorder for MockMeter
+type MockMeterMockRecorder struct {
+	mock *MockMeter
+}
+
+// NewMockMeter creates a new mock instance
+func NewMockMeter(ctrl *gomock.Controller) *MockMeter {
+	mock := &MockMeter{ctrl: ctrl}
+	mock.recorder = &MockMeterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMeter) EXPECT() *MockMeterMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method
+func (m *MockMeter) Add() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add
+func (mr *MockMeterMockRecorder) Add() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockMeter)(nil).Add))
+}
+
+// Delete mocks base method
+func (m *MockMeter) Delete() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockMeterMockRecorder) Delete() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMeter)(nil).Delete))
+}
+
+// GetBundleMessage mocks base method
+func (m *MockMeter) GetBundleMessage(arg0 openflow.OFOperation) (ofctrl.OpenFlowModMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundleMessage", arg0)
+	ret0, _ := ret[0].(ofctrl.OpenFlowModMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundleMessage indicates an expected call of GetBundleMessage
+func (mr *MockMeterMockRecorder) GetBundleMessage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundleMessage", reflect.TypeOf((*MockMeter)(nil).GetBundleMessage), arg0)
+}
+
+// KeyString mocks base method
+func (m *MockMeter) KeyString() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyString")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// KeyString indicates an expected call of KeyString
+func (mr *MockMeterMockRecorder) KeyString() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyString", reflect.TypeOf((*MockMeter)(nil).KeyString))
+}
+
+// MeterBand mocks base method
+func (m *MockMeter) MeterBand() openflow.MeterBandBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MeterBand")
+	ret0, _ := ret[0].(openflow.MeterBandBuilder)
+	return ret0
+}
+
+// MeterBand indicates an expected call of MeterBand
+func (mr *MockMeterMockRecorder) MeterBand() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MeterBand", reflect.TypeOf((*MockMeter)(nil).MeterBand))
+}
+
+// Modify mocks base method
+func (m *MockMeter) Modify() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Modify")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Modify indicates an expected call of Modify
+func (mr *MockMeterMockRecorder) Modify() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Modify", reflect.TypeOf((*MockMeter)(nil).Modify))
+}
+
+// Reset mocks base method
+func (m *MockMeter) Reset() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reset")
+}
+
+// Reset indicates an expected call of Reset
+func (mr *MockMeterMockRecorder) Reset() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockMeter)(nil).Reset))
+}
+
+// ResetMeterBands mocks base method
+func (m *MockMeter) ResetMeterBands() openflow.Meter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMeterBands")
+	ret0, _ := ret[0].(openflow.Meter)
+	return ret0
+}
+
+// ResetMeterBands indicates an expected call of ResetMeterBands
+func (mr *MockMeterMockRecorder) ResetMeterBands() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMeterBands", reflect.TypeOf((*MockMeter)(nil).ResetMeterBands))
+}
+
+// Type mocks base method
+func (m *MockMeter) Type() openflow.EntryType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Type")
+	ret0, _ := ret[0].(openflow.EntryType)
+	return ret0
+}
+
+// Type indicates an expected call of Type
+func (mr *MockMeterMockRecorder) Type() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Type", reflect.TypeOf((*MockMeter)(nil).Type))
+}
+
+// MockMeterBandBuilder is a mock of MeterBandBuilder interface
+type MockMeterBandBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockMeterBandBuilderMockRecorder
+}
+
+// MockMeterBandBuilderMockRecorder is the mock recorder for MockMeterBandBuilder
+type MockMeterBandBuilderMockRecorder struct {
+	mock *MockMeterBandBuilder
+}
+
+// NewMockMeterBandBuilder creates a new mock instance
+func NewMockMeterBandBuilder(ctrl *gomock.Controller) *MockMeterBandBuilder {
+	mock := &MockMeterBandBuilder{ctrl: ctrl}
+	mock.recorder = &MockMeterBandBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMeterBandBuilder) EXPECT() *MockMeterBandBuilderMockRecorder {
+	return m.recorder
+}
+
+// Burst mocks base method
+func (m *MockMeterBandBuilder) Burst(arg0 uint32) openflow.MeterBandBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Burst", arg0)
+	ret0, _ := ret[0].(openflow.MeterBandBuilder)
+	return ret0
+}
+
+// Burst indicates an expected call of Burst
+func (mr *MockMeterBandBuilderMockRecorder) Burst(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Burst", reflect.TypeOf((*MockMeterBandBuilder)(nil).Burst), arg0)
+}
+
+// Done mocks base method
+func (m *MockMeterBandBuilder) Done() openflow.Meter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Done")
+	ret0, _ := ret[0].(openflow.Meter)
+	return ret0
+}
+
+// Done indicates an expected call of Done
+func (mr *MockMeterBandBuilderMockRecorder) Done() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Done", reflect.TypeOf((*MockMeterBandBuilder)(nil).Done))
+}
+
+// Experimenter mocks base method
+func (m *MockMeterBandBuilder) Experimenter(arg0 uint32) openflow.MeterBandBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Experimenter", arg0)
+	ret0, _ := ret[0].(openflow.MeterBandBuilder)
+	return ret0
+}
+
+// Experimenter indicates an expected call of Experimenter
+func (mr *MockMeterBandBuilderMockRecorder) Experimenter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Experimenter", reflect.TypeOf((*MockMeterBandBuilder)(nil).Experimenter), arg0)
+}
+
+// MeterType mocks base method
+func (m *MockMeterBandBuilder) MeterType(arg0 ofctrl.MeterType) openflow.MeterBandBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MeterType", arg0)
+	ret0, _ := ret[0].(openflow.MeterBandBuilder)
+	return ret0
+}
+
+// MeterType indicates an expected call of MeterType
+func (mr *MockMeterBandBuilderMockRecorder) MeterType(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MeterType", reflect.TypeOf((*MockMeterBandBuilder)(nil).MeterType), arg0)
+}
+
+// PrecLevel mocks base method
+func (m *MockMeterBandBuilder) PrecLevel(arg0 byte) openflow.MeterBandBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrecLevel", arg0)
+	ret0, _ := ret[0].(openflow.MeterBandBuilder)
+	return ret0
+}
+
+// PrecLevel indicates an expected call of PrecLevel
+func (mr *MockMeterBandBuilderMockRecorder) PrecLevel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrecLevel", reflect.TypeOf((*MockMeterBandBuilder)(nil).PrecLevel), arg0)
+}
+
+// Rate mocks base method
+func (m *MockMeterBandBuilder) Rate(arg0 uint32) openflow.MeterBandBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", arg0)
+	ret0, _ := ret[0].(openflow.MeterBandBuilder)
+	return ret0
+}
+
+// Rate indicates an expected call of Rate
+func (mr *MockMeterBandBuilderMockRecorder) Rate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*MockMeterBandBuilder)(nil).Rate), arg0)
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bandwidth
+
+import (
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	crdv1alpha2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+)
+
+// Parse returns the rate in kbps and the burst size in kbits of the provided bandwidth, which are the units used by
+// OpenFlow meters. The burst size defaults to the rate.
+func Parse(bandwidth *crdv1alpha2.Bandwidth) (uint32, uint32, error) {
+	rate, err := parseKilobits(bandwidth.Rate)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rate %q: %v", bandwidth.Rate, err)
+	}
+	burst := rate
+	if bandwidth.Burst != "" {
+		burst, err = parseKilobits(bandwidth.Burst)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid burst %q: %v", bandwidth.Burst, err)
+		}
+	}
+	return rate, burst, nil
+}
+
+func parseKilobits(value string) (uint32, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, err
+	}
+	kilobits := quantity.ScaledValue(resource.Kilo)
+	if kilobits < 1 {
+		return 0, fmt.Errorf("must be at least 1k")
+	}
+	if kilobits > math.MaxUint32 {
+		return 0, fmt.Errorf("must be at most %dk", uint32(math.MaxUint32))
+	}
+	return uint32(kilobits), nil
+}
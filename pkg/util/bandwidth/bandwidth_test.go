@@ -0,0 +1,84 @@
+// Copyright 2021 Antrea Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bandwidth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	crdv1alpha2 "antrea.io/antrea/pkg/apis/crd/v1alpha2"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		bandwidth     crdv1alpha2.Bandwidth
+		expectedRate  uint32
+		expectedBurst uint32
+		expectedErr   bool
+	}{
+		{
+			name:          "rate only",
+			bandwidth:     crdv1alpha2.Bandwidth{Rate: "100M"},
+			expectedRate:  100000,
+			expectedBurst: 100000,
+		},
+		{
+			name:          "rate and burst",
+			bandwidth:     crdv1alpha2.Bandwidth{Rate: "10M", Burst: "1G"},
+			expectedRate:  10000,
+			expectedBurst: 1000000,
+		},
+		{
+			name:          "rate rounded up to kilobits",
+			bandwidth:     crdv1alpha2.Bandwidth{Rate: "1500"},
+			expectedRate:  2,
+			expectedBurst: 2,
+		},
+		{
+			name:        "invalid rate",
+			bandwidth:   crdv1alpha2.Bandwidth{Rate: "10Mbps"},
+			expectedErr: true,
+		},
+		{
+			name:        "zero rate",
+			bandwidth:   crdv1alpha2.Bandwidth{Rate: "0"},
+			expectedErr: true,
+		},
+		{
+			name:        "negative burst",
+			bandwidth:   crdv1alpha2.Bandwidth{Rate: "10M", Burst: "-1M"},
+			expectedErr: true,
+		},
+		{
+			name:        "rate too large",
+			bandwidth:   crdv1alpha2.Bandwidth{Rate: "5T"},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, burst, err := Parse(&tt.bandwidth)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRate, rate)
+			assert.Equal(t, tt.expectedBurst, burst)
+		})
+	}
+}
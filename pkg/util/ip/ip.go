@@ -143,6 +143,15 @@ func IPNetToNetIPNet(ipNet *v1beta2.IPNet) *net.IPNet {
 	return &net.IPNet{IP: maskedIP, Mask: mask}
 }
 
+// NewHostIPNet returns the IPNet which only contains the IP, i.e. a /32 CIDR for an IPv4 address and a /128 CIDR for
+// an IPv6 address.
+func NewHostIPNet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(V4BitLen, V4BitLen)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(V6BitLen, V6BitLen)}
+}
+
 const (
 	ICMPProtocol   = 1
 	TCPProtocol    = 6
@@ -157,6 +157,11 @@ func TestIPNetToNetIPNet(t *testing.T) {
 	}
 }
 
+func TestNewHostIPNet(t *testing.T) {
+	assert.Equal(t, newCIDR("10.10.0.1/32"), NewHostIPNet(net.ParseIP("10.10.0.1")))
+	assert.Equal(t, newCIDR("2001:ab03:cd04:55ef::1/128"), NewHostIPNet(net.ParseIP("2001:ab03:cd04:55ef::1")))
+}
+
 func TestIPProtocolNumberToString(t *testing.T) {
 	const defaultValue = "UnknownProtocol"
 	assert.Equal(t, "IPv6-ICMP", IPProtocolNumberToString(ICMPv6Protocol, defaultValue))
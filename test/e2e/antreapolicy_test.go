@@ -17,6 +17,7 @@ package e2e
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 	"sync"
@@ -2818,7 +2819,7 @@ func TestAntreaPolicyStatus(t *testing.T) {
 		if err != nil {
 			return false, err
 		}
-		return anp.Status == expectedStatus, nil
+		return reflect.DeepEqual(anp.Status, expectedStatus), nil
 	})
 	assert.NoError(t, err, "Antrea NetworkPolicy failed to reach expected status")
 	err = wait.Poll(100*time.Millisecond, policyRealizedTimeout, func() (bool, error) {
@@ -2826,7 +2827,7 @@ func TestAntreaPolicyStatus(t *testing.T) {
 		if err != nil {
 			return false, err
 		}
-		return anp.Status == expectedStatus, nil
+		return reflect.DeepEqual(anp.Status, expectedStatus), nil
 	})
 	assert.NoError(t, err, "Antrea ClusterNetworkPolicy failed to reach expected status")
 }
@@ -17,6 +17,7 @@ package e2e
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -2287,7 +2288,7 @@ func TestLegacyAntreaPolicyStatus(t *testing.T) {
 		if err != nil {
 			return false, err
 		}
-		return anp.Status == expectedStatus, nil
+		return reflect.DeepEqual(anp.Status, expectedStatus), nil
 	})
 	assert.NoError(t, err, "Antrea NetworkPolicy failed to reach expected status")
 	err = wait.Poll(100*time.Millisecond, 3*time.Second, func() (bool, error) {
@@ -2295,7 +2296,7 @@ func TestLegacyAntreaPolicyStatus(t *testing.T) {
 		if err != nil {
 			return false, err
 		}
-		return anp.Status == expectedStatus, nil
+		return reflect.DeepEqual(anp.Status, expectedStatus), nil
 	})
 	assert.NoError(t, err, "Antrea ClusterNetworkPolicy failed to reach expected status")
 }
@@ -1388,12 +1388,12 @@ func TestSNATFlows(t *testing.T) {
 	expectedFlows := append(prepareSNATFlows(snatIP, snatMark, podOFPort, podOFPortRemote, vMAC, gwMAC),
 		prepareSNATFlows(snatIPV6, snatMarkV6, podOFPortV6, podOFPortRemoteV6, vMAC, gwMAC)...)
 
-	c.InstallSNATMarkFlows(snatIP, snatMark)
-	c.InstallSNATMarkFlows(snatIPV6, snatMarkV6)
-	c.InstallPodSNATFlows(podOFPort, snatIP, snatMark)
-	c.InstallPodSNATFlows(podOFPortRemote, snatIP, 0)
-	c.InstallPodSNATFlows(podOFPortV6, snatIPV6, snatMarkV6)
-	c.InstallPodSNATFlows(podOFPortRemoteV6, snatIPV6, 0)
+	c.InstallSNATMarkFlows(snatIP, snatMark, false)
+	c.InstallSNATMarkFlows(snatIPV6, snatMarkV6, false)
+	c.InstallPodSNATFlows(podOFPort, snatIP, snatMark, false)
+	c.InstallPodSNATFlows(podOFPortRemote, snatIP, 0, false)
+	c.InstallPodSNATFlows(podOFPortV6, snatIPV6, snatMarkV6, false)
+	c.InstallPodSNATFlows(podOFPortRemoteV6, snatIPV6, 0, false)
 	for _, tableFlow := range expectedFlows {
 		ofTestUtils.CheckFlowExists(t, ovsCtlClient, tableFlow.tableID, true, tableFlow.flows)
 	}
@@ -36,6 +36,7 @@ Modifies:
 - Remove functions: "newBaseEndpointInfo", "makeEndpointFunc",
   "NewEndpointChangeTracker", "detectStaleConnections"
 - Remove structs: "EndpointChangeTracker", "EndpointsMap"
+- Add ZoneHints to BaseEndpointInfo and compare it in Equal
 */
 package proxy
 
@@ -43,6 +44,8 @@ import (
 	"net"
 	"strconv"
 
+	"k8s.io/apimachinery/pkg/util/sets"
+
 	utilproxy "antrea.io/antrea/third_party/proxy/util"
 )
 
@@ -55,6 +58,9 @@ type BaseEndpointInfo struct {
 	// IsLocal indicates whether the endpoint is running in same host as kube-proxy.
 	IsLocal  bool
 	Topology map[string]string
+	// ZoneHints represent the zone hints for the endpoint. This is based on
+	// endpoint.hints.forZones[*].name in the EndpointSlice API.
+	ZoneHints sets.String
 }
 
 var _ Endpoint = &BaseEndpointInfo{}
@@ -74,6 +80,11 @@ func (info *BaseEndpointInfo) GetTopology() map[string]string {
 	return info.Topology
 }
 
+// GetZoneHints returns the zone hints for the endpoint.
+func (info *BaseEndpointInfo) GetZoneHints() sets.String {
+	return info.ZoneHints
+}
+
 // IP returns just the IP part of the endpoint, it's a part of proxy.Endpoint interface.
 func (info *BaseEndpointInfo) IP() string {
 	return utilproxy.IPPart(info.Endpoint)
@@ -86,13 +97,16 @@ func (info *BaseEndpointInfo) Port() (int, error) {
 
 // Equal is part of proxy.Endpoint interface.
 func (info *BaseEndpointInfo) Equal(other Endpoint) bool {
-	return info.String() == other.String() && info.GetIsLocal() == other.GetIsLocal()
+	return info.String() == other.String() &&
+		info.GetIsLocal() == other.GetIsLocal() &&
+		info.GetZoneHints().Equal(other.GetZoneHints())
 }
 
-func NewBaseEndpointInfo(IP string, port int, isLocal bool, topology map[string]string) *BaseEndpointInfo {
+func NewBaseEndpointInfo(IP string, port int, isLocal bool, topology map[string]string, zoneHints sets.String) *BaseEndpointInfo {
 	return &BaseEndpointInfo{
-		Endpoint: net.JoinHostPort(IP, strconv.Itoa(port)),
-		IsLocal:  isLocal,
-		Topology: topology,
+		Endpoint:  net.JoinHostPort(IP, strconv.Itoa(port)),
+		IsLocal:   isLocal,
+		Topology:  topology,
+		ZoneHints: zoneHints,
 	}
 }
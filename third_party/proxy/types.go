@@ -33,6 +33,7 @@ Modifies:
 - Remove config.EndpointSliceHandler, config.NodeHandler from Provider interface type
 - Remove NodeHandler, EndpointSliceHandler, Sync() from Provider interface
 - Add Run() to Provider interface
+- Add GetZoneHints() to Endpoint interface
 */
 
 package proxy
@@ -43,6 +44,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"antrea.io/antrea/third_party/proxy/config"
 )
@@ -117,6 +119,8 @@ type Endpoint interface {
 	GetIsLocal() bool
 	// GetTopology returns the topology information of the endpoint.
 	GetTopology() map[string]string
+	// GetZoneHints returns the zone hints for the endpoint.
+	GetZoneHints() sets.String
 	// IP returns IP part of the endpoint.
 	IP() string
 	// Port returns the Port part of the endpoint.